@@ -62,6 +62,8 @@ var (
 		router.SignUp:            {},
 		router.SiteInit:          {},
 		router.SignIn:            {},
+		router.SignInMFA:         {},
+		router.SignInMFAEnroll:   {},
 		router.SignOut:           {},
 		router.ResetPasswordInit: {},
 		router.ResetPasswordCode: {},
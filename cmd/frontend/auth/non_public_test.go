@@ -36,6 +36,7 @@ func TestAllowAnonymousRequest(t *testing.T) {
 		{req: req("GET", "/"), want: false},
 		{req: req("POST", "/"), want: false},
 		{req: req("POST", "/-/sign-in"), want: true},
+		{req: req("POST", "/-/sign-in/mfa"), want: true},
 		{req: req("GET", "/sign-in"), want: true},
 		{req: req("GET", "/doesntexist"), want: false},
 		{req: req("POST", "/doesntexist"), want: false},
@@ -2,14 +2,22 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/errcode"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
 )
 
+var (
+	errUserSuspended       = errors.New("user account is suspended")
+	errUserPendingApproval = errors.New("user account is pending approval by a site admin")
+)
+
 var MockGetAndSaveUser func(ctx context.Context, op GetAndSaveUserOp) (userID int32, safeErrMsg string, err error)
 
 type GetAndSaveUserOp struct {
@@ -36,7 +44,11 @@ type GetAndSaveUserOp struct {
 //    creating the external account if it does not already exist or updating it if it
 //    already does.
 // 3. Update any user props that have changed.
-// 4. Return the user ID.
+// 4. Check that the user is active (i.e., not suspended or pending approval).
+// 5. Return the user ID.
+//
+// If the auth.userApprovalRequired critical config property is true, users created in step 1d are
+// pending approval by a site admin.
 //
 // 🚨 SECURITY: It is the caller's responsibility to ensure the veracity of the information that
 // op contains (e.g., by receiving it from the appropriate authentication mechanism). It must
@@ -94,7 +106,11 @@ func GetAndSaveUser(ctx context.Context, op GetAndSaveUserOp) (userID int32, saf
 		}
 
 		// If CreateIfNotExist is true, create the new user, regardless of whether the email was verified or not.
-		userID, err := db.ExternalAccounts.CreateUserAndSave(ctx, op.UserProps, op.ExternalAccount, op.ExternalAccountData)
+		newUser := op.UserProps
+		if conf.Get().Critical.AuthUserApprovalRequired {
+			newUser.State = types.UserStatePendingApproval
+		}
+		userID, err := db.ExternalAccounts.CreateUserAndSave(ctx, newUser, op.ExternalAccount, op.ExternalAccountData)
 		switch {
 		case db.IsUsernameExists(err):
 			return 0, false, false, fmt.Sprintf("Username %q already exists, but no verified email matched %q", op.UserProps.Username, op.UserProps.Email), err
@@ -109,6 +125,11 @@ func GetAndSaveUser(ctx context.Context, op GetAndSaveUserOp) (userID int32, saf
 		return 0, safeErrMsg, err
 	}
 
+	var state types.UserState
+	if userSaved && conf.Get().Critical.AuthUserApprovalRequired {
+		state = types.UserStatePendingApproval
+	}
+
 	// Update user properties, if they've changed
 	if !userSaved {
 		// Update user in our DB if their profile info changed on the issuer. (Except username and
@@ -117,6 +138,7 @@ func GetAndSaveUser(ctx context.Context, op GetAndSaveUserOp) (userID int32, saf
 		if err != nil {
 			return 0, "Unexpected error getting the Sourcegraph user account. Ask a site admin for help.", err
 		}
+		state = user.State
 		var userUpdate db.UserUpdate
 		if user.DisplayName != op.UserProps.DisplayName {
 			userUpdate.DisplayName = &op.UserProps.DisplayName
@@ -139,5 +161,15 @@ func GetAndSaveUser(ctx context.Context, op GetAndSaveUserOp) (userID int32, saf
 		}
 	}
 
+	// 🚨 SECURITY: Suspended users and users pending approval must not be able to sign in. This
+	// check happens after the external account is saved so that the account is linked to the user
+	// when a site admin approves or unsuspends them.
+	switch state {
+	case types.UserStateSuspended:
+		return 0, "Your Sourcegraph user account is suspended. Ask a site admin for help.", errUserSuspended
+	case types.UserStatePendingApproval:
+		return 0, "Your Sourcegraph user account is pending approval by a site admin. Try signing in again after a site admin approves your account.", errUserPendingApproval
+	}
+
 	return userID, "", nil
 }
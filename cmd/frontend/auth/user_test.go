@@ -12,8 +12,10 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/errcode"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func init() {
@@ -348,7 +350,45 @@ func TestGetAndSaveUser(t *testing.T) {
 		},
 	}
 
-	allCases := append(append([]outerCase{}, mainCase), errorCases...)
+	suspendedCase := outerCase{
+		description: "suspended user",
+		mock: mockParams{
+			userInfos: []userInfo{{
+				user: types.User{ID: 1, Username: "u1", State: types.UserStateSuspended},
+				extAccts: []extsvc.ExternalAccountSpec{
+					ext("st1", "s1", "c1", "s1/u1"),
+				},
+				emails: []string{"u1@example.com"},
+			}},
+		},
+		innerCases: []innerCase{
+			{
+				description:                "ext acct exists",
+				op:                         getOneUserOp,
+				createIfNotExistIrrelevant: true,
+				expSafeErr:                 "Your Sourcegraph user account is suspended. Ask a site admin for help.",
+				expErr:                     errUserSuspended,
+				expSavedExtAccts: map[int32][]extsvc.ExternalAccountSpec{
+					1: {ext("st1", "s1", "c1", "s1/u1")},
+				},
+			},
+			{
+				description: "ext acct doesn't exist, user with email exists",
+				op: GetAndSaveUserOp{
+					ExternalAccount: ext("st1", "s-new", "c1", "s-new/u1"),
+					UserProps:       userProps("u1", "u1@example.com", true),
+				},
+				createIfNotExistIrrelevant: true,
+				expSafeErr:                 "Your Sourcegraph user account is suspended. Ask a site admin for help.",
+				expErr:                     errUserSuspended,
+				expSavedExtAccts: map[int32][]extsvc.ExternalAccountSpec{
+					1: {ext("st1", "s-new", "c1", "s-new/u1")},
+				},
+			},
+		},
+	}
+
+	allCases := append(append([]outerCase{}, mainCase, suspendedCase), errorCases...)
 	for _, oc := range allCases {
 		t.Run(oc.description, func(t *testing.T) {
 			for _, c := range oc.innerCases {
@@ -409,6 +449,35 @@ func TestGetAndSaveUser(t *testing.T) {
 	}
 }
 
+// 🚨 SECURITY: This tests that users who are automatically created on sign-in are pending approval
+// (and can't sign in) when auth.userApprovalRequired is set.
+func TestGetAndSaveUser_userApprovalRequired(t *testing.T) {
+	conf.Mock(&conf.Unified{Critical: schema.CriticalConfiguration{AuthUserApprovalRequired: true}})
+	defer conf.Mock(nil)
+
+	m := newMocks(t, mockParams{})
+	m.apply()
+	defer m.reset()
+
+	userID, safeErr, err := GetAndSaveUser(context.Background(), GetAndSaveUserOp{
+		ExternalAccount:  ext("st1", "s1", "c1", "s1/u-new"),
+		UserProps:        userProps("u-new", "u-new@example.com", true),
+		CreateIfNotExist: true,
+	})
+	if err != errUserPendingApproval {
+		t.Errorf("got error %v, want %v", err, errUserPendingApproval)
+	}
+	if userID != 0 || safeErr == "" {
+		t.Errorf("got userID %d and safeErr %q, want no user and a safe error message", userID, safeErr)
+	}
+	if got, want := m.createdUsers[10001].State, types.UserStatePendingApproval; got != want {
+		t.Errorf("got created user state %q, want %q", got, want)
+	}
+	if got, want := m.savedExtAccts[10001], []extsvc.ExternalAccountSpec{ext("st1", "s1", "c1", "s1/u-new")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got saved external accounts %+v, want %+v", got, want)
+	}
+}
+
 type userInfo struct {
 	user     types.User
 	extAccts []extsvc.ExternalAccountSpec
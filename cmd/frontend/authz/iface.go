@@ -66,3 +66,16 @@ type Provider interface {
 	// problems.
 	Validate() (problems []string)
 }
+
+// UserProvider is an authz provider whose source of truth identifies users by their Sourcegraph
+// user rather than by an external account (e.g., because the code host has no user accounts of
+// its own). Callers use UserRepoPerms instead of FetchAccount and RepoPerms for such providers, so
+// that no external account is created for the user.
+type UserProvider interface {
+	Provider
+
+	// UserRepoPerms is like RepoPerms, except that it identifies the user by their Sourcegraph
+	// user. The user argument may be nil, in which case the set of permissions for an
+	// unauthenticated user is returned.
+	UserRepoPerms(ctx context.Context, user *types.User, repos []*types.Repo) ([]RepoPerms, error)
+}
@@ -1,13 +1,93 @@
 package authz
 
+import "strings"
+
 const (
 	// Access token scopes.
 	ScopeUserAll       = "user:all"        // Full control of all resources accessible to the user account.
 	ScopeSiteAdminSudo = "site-admin:sudo" // Ability to perform any action as any other user.
+	ScopeSiteAdminSCIM = "site-admin:scim" // Ability to provision users and organizations with the SCIM API.
+
+	// Fine-grained access token scopes. A token with only these scopes may only perform the
+	// operations that they permit (see ScopeGrants).
+	ScopeSearchRead    = "search:read"    // Ability to search.
+	ScopeRepoRead      = "repo:read"      // Read access to repositories and their contents.
+	ScopeRepoWrite     = "repo:write"     // Ability to update repositories and discussions.
+	ScopeSettingsRead  = "settings:read"  // Read access to settings and saved searches.
+	ScopeSettingsWrite = "settings:write" // Ability to change settings and saved searches.
+	ScopeUserRead      = "user:read"      // Read access to the user account, its emails and organizations.
+	ScopeUserWrite     = "user:write"     // Ability to change the user account, its emails and organizations.
 )
 
 // AllScopes is a list of all known access token scopes.
 var AllScopes = []string{
 	ScopeUserAll,
 	ScopeSiteAdminSudo,
+	ScopeSiteAdminSCIM,
+	ScopeSearchRead,
+	ScopeRepoRead,
+	ScopeRepoWrite,
+	ScopeSettingsRead,
+	ScopeSettingsWrite,
+	ScopeUserRead,
+	ScopeUserWrite,
+}
+
+// APIScopes is a list of the access token scopes that permit using the API. Each API operation
+// checks that the scopes of the access token that was used (if any) grant the scope that it
+// requires.
+var APIScopes = []string{
+	ScopeUserAll,
+	ScopeSearchRead,
+	ScopeRepoRead,
+	ScopeRepoWrite,
+	ScopeSettingsRead,
+	ScopeSettingsWrite,
+	ScopeUserRead,
+	ScopeUserWrite,
+}
+
+// IsFineGrainedScope reports whether scope is one of the fine-grained "area:read" or
+// "area:write" scopes.
+func IsFineGrainedScope(scope string) bool {
+	switch scope {
+	case ScopeSearchRead, ScopeRepoRead, ScopeRepoWrite, ScopeSettingsRead, ScopeSettingsWrite, ScopeUserRead, ScopeUserWrite:
+		return true
+	}
+	return false
+}
+
+// ScopeGrants reports whether an access token with the granted scopes may perform an operation
+// that requires the required scope (i.e., whether any of the granted scopes is one of
+// ScopesGranting(required)).
+func ScopeGrants(granted []string, required string) bool {
+	for _, scope := range ScopesGranting(required) {
+		for _, g := range granted {
+			if g == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ScopesGranting returns the scopes that grant the required scope. A scope grants itself. In
+// addition:
+//
+// - "user:all" grants all fine-grained scopes.
+// - An "area:write" scope grants the corresponding "area:read" scope.
+func ScopesGranting(required string) []string {
+	if required == "" {
+		return nil
+	}
+	scopes := []string{required}
+	if IsFineGrainedScope(required) {
+		scopes = append(scopes, ScopeUserAll)
+		if strings.HasSuffix(required, ":read") {
+			if write := strings.TrimSuffix(required, ":read") + ":write"; IsFineGrainedScope(write) {
+				scopes = append(scopes, write)
+			}
+		}
+	}
+	return scopes
 }
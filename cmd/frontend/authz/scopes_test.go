@@ -0,0 +1,30 @@
+package authz
+
+import "testing"
+
+func TestScopeGrants(t *testing.T) {
+	tests := []struct {
+		granted  []string
+		required string
+		want     bool
+	}{
+		{granted: []string{ScopeUserAll}, required: ScopeUserAll, want: true},
+		{granted: []string{ScopeUserAll}, required: ScopeSearchRead, want: true},
+		{granted: []string{ScopeUserAll}, required: ScopeSettingsWrite, want: true},
+		{granted: []string{ScopeUserAll}, required: ScopeSiteAdminSudo, want: false},
+		{granted: []string{ScopeSearchRead}, required: ScopeSearchRead, want: true},
+		{granted: []string{ScopeSearchRead}, required: ScopeRepoRead, want: false},
+		{granted: []string{ScopeSearchRead}, required: ScopeUserAll, want: false},
+		{granted: []string{ScopeSettingsWrite}, required: ScopeSettingsRead, want: true},
+		{granted: []string{ScopeSettingsRead}, required: ScopeSettingsWrite, want: false},
+		{granted: []string{ScopeRepoWrite}, required: ScopeUserRead, want: false},
+		{granted: []string{ScopeSearchRead, ScopeUserRead}, required: ScopeUserRead, want: true},
+		{granted: []string{ScopeUserAll}, required: "", want: false},
+		{granted: nil, required: ScopeSearchRead, want: false},
+	}
+	for _, test := range tests {
+		if got := ScopeGrants(test.granted, test.required); got != test.want {
+			t.Errorf("ScopeGrants(%q, %q) = %v, want %v", test.granted, test.required, got, test.want)
+		}
+	}
+}
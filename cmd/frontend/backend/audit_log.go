@@ -0,0 +1,160 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"log/syslog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/requestclient"
+	"github.com/sourcegraph/sourcegraph/schema"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// AuditEvent describes a security-relevant action to record in the audit log.
+type AuditEvent struct {
+	Action     string // the name of the action (e.g., "setUserIsSiteAdmin")
+	TargetKind string // the kind of the target (e.g., "User")
+	TargetID   string // the ID of the target (e.g., its GraphQL ID)
+	TargetName string // a human-readable name of the target (e.g., the username)
+	Changes    []db.AuditLogChange
+}
+
+// LogAuditEvent records a security-relevant action performed by the current actor in the audit
+// log. It should be called after the action succeeds.
+//
+// The entry is stored in the database and, if the "auditLog" site configuration property is set,
+// also written to the configured file and/or syslog server. Failures to record the entry are
+// logged but are not returned, so that they don't cause the (already performed) action to appear
+// to have failed.
+func LogAuditEvent(ctx context.Context, event AuditEvent) {
+	e := &db.AuditLogEntry{
+		ActorUserID: actor.FromContext(ctx).UID,
+		Action:      event.Action,
+		TargetKind:  event.TargetKind,
+		TargetID:    event.TargetID,
+		TargetName:  event.TargetName,
+		Changes:     event.Changes,
+	}
+	if client := requestclient.FromContext(ctx); client != nil {
+		e.RemoteAddr = client.IP
+		e.ForwardedFor = client.ForwardedFor
+	}
+	if err := db.AuditLog.Create(ctx, e); err != nil {
+		log15.Error("Failed to record audit log entry in database.", "action", e.Action, "actor", e.ActorUserID, "targetKind", e.TargetKind, "targetID", e.TargetID, "err", err)
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+
+	if cfg := conf.Get().AuditLog; cfg != nil {
+		go writeAuditLogEntry(cfg, e)
+	}
+}
+
+// auditLogRecord is the JSON representation of an audit log entry that is written to the
+// external audit log sinks.
+type auditLogRecord struct {
+	ID            int64               `json:"id,omitempty"`
+	Timestamp     time.Time           `json:"timestamp"`
+	ActorUserID   int32               `json:"actorUserID,omitempty"`
+	ActorUsername string              `json:"actorUsername,omitempty"`
+	RemoteAddr    string              `json:"remoteAddr,omitempty"`
+	ForwardedFor  string              `json:"forwardedFor,omitempty"`
+	Action        string              `json:"action"`
+	TargetKind    string              `json:"targetKind"`
+	TargetID      string              `json:"targetID"`
+	TargetName    string              `json:"targetName,omitempty"`
+	Changes       []db.AuditLogChange `json:"changes,omitempty"`
+}
+
+// auditLogFileMu serializes writes to the audit log file so that records are not interleaved.
+var auditLogFileMu sync.Mutex
+
+func writeAuditLogEntry(cfg *schema.AuditLog, e *db.AuditLogEntry) {
+	data, err := json.Marshal(auditLogRecord{
+		ID:            e.ID,
+		Timestamp:     e.CreatedAt.UTC(),
+		ActorUserID:   e.ActorUserID,
+		ActorUsername: e.ActorUsername,
+		RemoteAddr:    e.RemoteAddr,
+		ForwardedFor:  e.ForwardedFor,
+		Action:        e.Action,
+		TargetKind:    e.TargetKind,
+		TargetID:      e.TargetID,
+		TargetName:    e.TargetName,
+		Changes:       e.Changes,
+	})
+	if err != nil {
+		log15.Error("Failed to marshal audit log entry.", "action", e.Action, "err", err)
+		return
+	}
+
+	if cfg.File != "" {
+		if err := appendAuditLogFile(cfg.File, data); err != nil {
+			log15.Error("Failed to write audit log entry to file.", "file", cfg.File, "action", e.Action, "err", err)
+		}
+	}
+	if cfg.Syslog != nil {
+		if err := sendAuditLogSyslog(cfg.Syslog, data); err != nil {
+			log15.Error("Failed to send audit log entry to syslog.", "address", cfg.Syslog.Address, "action", e.Action, "err", err)
+		}
+	}
+}
+
+func appendAuditLogFile(path string, data []byte) error {
+	auditLogFileMu.Lock()
+	defer auditLogFileMu.Unlock()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// auditLogSyslog is the connection to the syslog server that audit log entries are sent to. It is
+// reused for all entries and reopened when the configuration changes or sending fails.
+var auditLogSyslog struct {
+	mu  sync.Mutex
+	cfg schema.AuditLogSyslog // the configuration that w was opened with
+	w   *syslog.Writer
+}
+
+func sendAuditLogSyslog(cfg *schema.AuditLogSyslog, data []byte) error {
+	auditLogSyslog.mu.Lock()
+	defer auditLogSyslog.mu.Unlock()
+
+	if auditLogSyslog.w != nil && auditLogSyslog.cfg != *cfg {
+		auditLogSyslog.w.Close()
+		auditLogSyslog.w = nil
+	}
+	if auditLogSyslog.w == nil {
+		tag := cfg.Tag
+		if tag == "" {
+			tag = "sourcegraph-audit"
+		}
+		w, err := syslog.Dial(cfg.Network, cfg.Address, syslog.LOG_INFO|syslog.LOG_AUTH, tag)
+		if err != nil {
+			return err
+		}
+		auditLogSyslog.cfg, auditLogSyslog.w = *cfg, w
+	}
+
+	// The writer reconnects once by itself if sending fails, so if it still fails, drop the
+	// connection and dial again for the next entry.
+	if err := auditLogSyslog.w.Info(string(data)); err != nil {
+		auditLogSyslog.w.Close()
+		auditLogSyslog.w = nil
+		return err
+	}
+	return nil
+}
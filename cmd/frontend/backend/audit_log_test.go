@@ -0,0 +1,48 @@
+package backend
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestSendAuditLogSyslog_reusesConnection(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	defer func() {
+		if auditLogSyslog.w != nil {
+			auditLogSyslog.w.Close()
+			auditLogSyslog.w = nil
+		}
+	}()
+
+	cfg := &schema.AuditLogSyslog{Network: "udp", Address: conn.LocalAddr().String()}
+	if err := sendAuditLogSyslog(cfg, []byte(`{"action":"a"}`)); err != nil {
+		t.Fatal(err)
+	}
+	w := auditLogSyslog.w
+	if err := sendAuditLogSyslog(cfg, []byte(`{"action":"b"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if auditLogSyslog.w != w {
+		t.Error("got a new syslog connection for the second entry, want the first one reused")
+	}
+
+	buf := make([]byte, 1024)
+	for _, want := range []string{`{"action":"a"}`, `{"action":"b"}`} {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg := string(buf[:n]); !strings.Contains(msg, want) || !strings.Contains(msg, "sourcegraph-audit") {
+			t.Errorf("got syslog message %q, want it to contain %q", msg, want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+)
+
+// RepoWriteDeniedError occurs when the current user may not create commits or branches in a
+// repository.
+type RepoWriteDeniedError struct {
+	Repo     api.RepoName
+	ReadOnly bool // whether the repository is protected by the site configuration
+}
+
+func (e *RepoWriteDeniedError) Error() string {
+	if e.ReadOnly {
+		return fmt.Sprintf("repository %s is read-only (protected by the site configuration)", e.Repo)
+	}
+	return fmt.Sprintf("write access to repository %s is required", e.Repo)
+}
+
+func (e *RepoWriteDeniedError) HTTPStatusCode() int { return http.StatusForbidden }
+
+// CheckRepoWrite returns an error if the current user may NOT create commits or branches in the
+// repository. That is the case if the repository is read-only (see conf.IsRepoReadOnly) or if the
+// authz providers do not report write access for the user (site admins have write access to all
+// repositories that are not read-only).
+//
+// 🚨 SECURITY: All APIs that create commits or branches must call this.
+func CheckRepoWrite(ctx context.Context, repo *types.Repo) error {
+	if conf.IsRepoReadOnly(repo.Name) {
+		return &RepoWriteDeniedError{Repo: repo.Name, ReadOnly: true}
+	}
+	if hasAuthzBypass(ctx) {
+		return nil
+	}
+	if err := CheckActorScope(ctx, authz.ScopeRepoWrite); err != nil {
+		return err
+	}
+	if !actor.FromContext(ctx).IsAuthenticated() {
+		return ErrNotAuthenticated
+	}
+	ok, err := db.Repos.HasPerms(ctx, repo, authz.Write)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &RepoWriteDeniedError{Repo: repo.Name}
+	}
+	return nil
+}
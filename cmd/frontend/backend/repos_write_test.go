@@ -0,0 +1,89 @@
+package backend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// 🚨 SECURITY: This tests that users can't create commits or branches in repositories they can't
+// write to, or in read-only repositories.
+func TestCheckRepoWrite(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		RepositoriesReadOnly: []string{`^github\.com/protected/`},
+	}})
+	defer conf.Mock(nil)
+
+	tests := map[string]struct {
+		ctx       context.Context
+		repo      api.RepoName
+		hasPerms  bool
+		wantErr   bool
+		wantPerms bool // whether HasPerms should be called
+	}{
+		"writable": {
+			ctx:       actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+			repo:      "github.com/u/r",
+			hasPerms:  true,
+			wantPerms: true,
+		},
+		"no write perms": {
+			ctx:       actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+			repo:      "github.com/u/r",
+			hasPerms:  false,
+			wantErr:   true,
+			wantPerms: true,
+		},
+		"read-only": {
+			ctx:      actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+			repo:     "github.com/protected/r",
+			hasPerms: true,
+			wantErr:  true,
+		},
+		"read-only with authz bypass": {
+			ctx:     WithAuthzBypass(context.Background()),
+			repo:    "github.com/protected/r",
+			wantErr: true,
+		},
+		"unauthenticated": {
+			ctx:      context.Background(),
+			repo:     "github.com/u/r",
+			hasPerms: true,
+			wantErr:  true,
+		},
+		"access token without repo:write scope": {
+			ctx:      actor.WithActor(context.Background(), &actor.Actor{UID: 1, Scopes: []string{authz.ScopeRepoRead}}),
+			repo:     "github.com/u/r",
+			hasPerms: true,
+			wantErr:  true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			testContext()
+			calledHasPerms := false
+			db.Mocks.Repos.HasPerms = func(ctx context.Context, repo *types.Repo, p authz.Perms) (bool, error) {
+				calledHasPerms = true
+				if p != authz.Write {
+					t.Errorf("got perms %s, want %s", p, authz.Write)
+				}
+				return test.hasPerms, nil
+			}
+
+			err := CheckRepoWrite(test.ctx, &types.Repo{ID: 1, Name: test.repo})
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Errorf("got error %v, want error %v", err, test.wantErr)
+			}
+			if calledHasPerms != test.wantPerms {
+				t.Errorf("got calledHasPerms %v, want %v", calledHasPerms, test.wantPerms)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+)
+
+// InsufficientScopeError occurs when the actor was authenticated with an access token that does
+// not grant the scope that an operation requires.
+type InsufficientScopeError struct {
+	Scope string // the required scope
+}
+
+func (e *InsufficientScopeError) Error() string {
+	return fmt.Sprintf("the access token does not grant the scope %q required for this operation", e.Scope)
+}
+
+func (e *InsufficientScopeError) HTTPStatusCode() int { return http.StatusForbidden }
+
+// CheckActorScope returns an error if the actor was authenticated with an access token that does
+// not grant the scope (see authz.ScopeGrants). Actors that were not authenticated with an access
+// token with restricted scopes may perform any operation that their user may perform.
+func CheckActorScope(ctx context.Context, scope string) error {
+	if hasAuthzBypass(ctx) {
+		return nil
+	}
+	if a := actor.FromContext(ctx); a.Scopes != nil && !authz.ScopeGrants(a.Scopes, scope) {
+		return &InsufficientScopeError{Scope: scope}
+	}
+	return nil
+}
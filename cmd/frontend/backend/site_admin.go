@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/actor"
@@ -18,6 +19,10 @@ func CheckCurrentUserIsSiteAdmin(ctx context.Context) error {
 	if hasAuthzBypass(ctx) {
 		return nil
 	}
+	// 🚨 SECURITY: Access tokens with fine-grained scopes do not grant site admin privileges.
+	if CheckActorScope(ctx, authz.ScopeUserAll) != nil {
+		return ErrMustBeSiteAdmin
+	}
 	user, err := currentUser(ctx)
 	if err != nil {
 		return err
@@ -3,7 +3,10 @@ package db
 import (
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
 )
 
@@ -32,7 +35,7 @@ func TestAccessTokens_Create(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	tid0, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a", "b"}, "n0", creator.ID)
+	tid0, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a", "b"}, "n0", creator.ID, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -51,7 +54,7 @@ func TestAccessTokens_Create(t *testing.T) {
 		t.Errorf("got %q, want %q", got.Note, want)
 	}
 
-	gotSubjectUserID, err := AccessTokens.Lookup(ctx, tv0, "a")
+	gotSubjectUserID, _, err := AccessTokens.Lookup(ctx, tv0, "a")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -105,11 +108,11 @@ func TestAccessTokens_List(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	_, _, err = AccessTokens.Create(ctx, subject1.ID, []string{"a", "b"}, "n0", subject1.ID)
+	_, _, err = AccessTokens.Create(ctx, subject1.ID, []string{"a", "b"}, "n0", subject1.ID, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, _, err = AccessTokens.Create(ctx, subject1.ID, []string{"a", "b"}, "n1", subject1.ID)
+	_, _, err = AccessTokens.Create(ctx, subject1.ID, []string{"a", "b"}, "n1", subject1.ID, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -183,13 +186,13 @@ func TestAccessTokens_Lookup(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	tid0, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a", "b"}, "n0", creator.ID)
+	tid0, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a", "b"}, "n0", creator.ID, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	for _, scope := range []string{"a", "b"} {
-		gotSubjectUserID, err := AccessTokens.Lookup(ctx, tv0, scope)
+		gotSubjectUserID, _, err := AccessTokens.Lookup(ctx, tv0, scope)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -199,12 +202,12 @@ func TestAccessTokens_Lookup(t *testing.T) {
 	}
 
 	// Lookup with a nonexistent scope and ensure it fails.
-	if _, err := AccessTokens.Lookup(ctx, tv0, "x"); err == nil {
+	if _, _, err := AccessTokens.Lookup(ctx, tv0, "x"); err == nil {
 		t.Fatal(err)
 	}
 
 	// Lookup with an empty scope and ensure it fails.
-	if _, err := AccessTokens.Lookup(ctx, tv0, ""); err == nil {
+	if _, _, err := AccessTokens.Lookup(ctx, tv0, ""); err == nil {
 		t.Fatal(err)
 	}
 
@@ -212,12 +215,86 @@ func TestAccessTokens_Lookup(t *testing.T) {
 	if err := AccessTokens.DeleteByID(ctx, tid0, subject.ID); err != nil {
 		t.Fatal(err)
 	}
-	if _, err := AccessTokens.Lookup(ctx, tv0, "a"); err == nil {
+	if _, _, err := AccessTokens.Lookup(ctx, tv0, "a"); err == nil {
 		t.Fatal(err)
 	}
 
 	// Try to Lookup a token that was never created.
-	if _, err := AccessTokens.Lookup(ctx, "abcdefg" /* this token value was never created */, "a"); err == nil {
+	if _, _, err := AccessTokens.Lookup(ctx, "abcdefg" /* this token value was never created */, "a"); err == nil {
+		t.Fatal(err)
+	}
+}
+
+// 🚨 SECURITY: This tests that Lookup enforces the access token's scopes and expiry.
+func TestAccessTokens_Lookup_scopesAndExpiry(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	subject, err := Users.Create(ctx, NewUser{Username: "u1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{authz.ScopeSettingsWrite, authz.ScopeSearchRead}, "n0", subject.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		requiredScopes []string
+		wantOK         bool
+	}{
+		{requiredScopes: []string{authz.ScopeSearchRead}, wantOK: true},
+		{requiredScopes: []string{authz.ScopeSettingsRead}, wantOK: true}, // granted by settings:write
+		{requiredScopes: []string{authz.ScopeRepoRead}, wantOK: false},
+		{requiredScopes: []string{authz.ScopeUserAll}, wantOK: false},
+		{requiredScopes: []string{authz.ScopeUserAll, authz.ScopeSearchRead}, wantOK: true},
+	} {
+		gotSubjectUserID, gotScopes, err := AccessTokens.Lookup(ctx, tv0, test.requiredScopes...)
+		if ok := err == nil; ok != test.wantOK {
+			t.Errorf("%v: got ok %v, want %v (error: %v)", test.requiredScopes, ok, test.wantOK, err)
+			continue
+		}
+		if !test.wantOK {
+			continue
+		}
+		if gotSubjectUserID != subject.ID {
+			t.Errorf("%v: got subject %d, want %d", test.requiredScopes, gotSubjectUserID, subject.ID)
+		}
+		if want := []string{authz.ScopeSettingsWrite, authz.ScopeSearchRead}; !reflect.DeepEqual(gotScopes, want) {
+			t.Errorf("%v: got scopes %v, want %v", test.requiredScopes, gotScopes, want)
+		}
+	}
+
+	// user:all grants the fine-grained scopes.
+	_, tv1, err := AccessTokens.Create(ctx, subject.ID, []string{authz.ScopeUserAll}, "n1", subject.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := AccessTokens.Lookup(ctx, tv1, authz.ScopeRepoRead); err != nil {
+		t.Fatal(err)
+	}
+
+	// Expired tokens can't be used.
+	past, future := time.Now().Add(-time.Minute), time.Now().Add(time.Hour)
+	tid2, tv2, err := AccessTokens.Create(ctx, subject.ID, []string{authz.ScopeUserAll}, "n2", subject.ID, &past)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := AccessTokens.Lookup(ctx, tv2, authz.ScopeUserAll); err != ErrAccessTokenNotFound {
+		t.Errorf("got error %v for expired token, want %v", err, ErrAccessTokenNotFound)
+	}
+	if token, err := AccessTokens.GetByID(ctx, tid2); err != nil {
+		t.Fatal(err)
+	} else if token.ExpiresAt == nil || !token.ExpiresAt.Equal(past.Truncate(time.Microsecond)) {
+		t.Errorf("got expiry %v, want %v", token.ExpiresAt, past)
+	}
+	_, tv3, err := AccessTokens.Create(ctx, subject.ID, []string{authz.ScopeUserAll}, "n3", subject.ID, &future)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := AccessTokens.Lookup(ctx, tv3, authz.ScopeUserAll); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -250,18 +327,18 @@ func TestAccessTokens_Lookup_deletedUser(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		_, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a"}, "n0", creator.ID)
+		_, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a"}, "n0", creator.ID, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
 		if err := Users.Delete(ctx, subject.ID); err != nil {
 			t.Fatal(err)
 		}
-		if _, err := AccessTokens.Lookup(ctx, tv0, "a"); err == nil {
+		if _, _, err := AccessTokens.Lookup(ctx, tv0, "a"); err == nil {
 			t.Fatal("Lookup: want error looking up token for deleted subject user")
 		}
 
-		if _, _, err := AccessTokens.Create(ctx, subject.ID, nil, "n0", creator.ID); err == nil {
+		if _, _, err := AccessTokens.Create(ctx, subject.ID, nil, "n0", creator.ID, nil); err == nil {
 			t.Fatal("Create: want error creating token for deleted subject user")
 		}
 	})
@@ -286,19 +363,54 @@ func TestAccessTokens_Lookup_deletedUser(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		_, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a"}, "n0", creator.ID)
+		_, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a"}, "n0", creator.ID, nil)
 		if err != nil {
 			t.Fatal(err)
 		}
 		if err := Users.Delete(ctx, creator.ID); err != nil {
 			t.Fatal(err)
 		}
-		if _, err := AccessTokens.Lookup(ctx, tv0, "a"); err == nil {
+		if _, _, err := AccessTokens.Lookup(ctx, tv0, "a"); err == nil {
 			t.Fatal("Lookup: want error looking up token for deleted creator user")
 		}
 
-		if _, _, err := AccessTokens.Create(ctx, subject.ID, nil, "n0", creator.ID); err == nil {
+		if _, _, err := AccessTokens.Create(ctx, subject.ID, nil, "n0", creator.ID, nil); err == nil {
 			t.Fatal("Create: want error creating token for deleted creator user")
 		}
 	})
 }
+
+func TestAccessTokens_Lookup_suspendedUser(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	subject, err := Users.Create(ctx, NewUser{
+		Email:                 "u1@example.com",
+		Username:              "u1",
+		Password:              "p1",
+		EmailVerificationCode: "c1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, tv0, err := AccessTokens.Create(ctx, subject.ID, []string{"a"}, "n0", subject.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Users.SetState(ctx, subject.ID, types.UserStateSuspended); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := AccessTokens.Lookup(ctx, tv0, "a"); err == nil {
+		t.Fatal("Lookup: want error looking up token for suspended subject user")
+	}
+
+	if err := Users.SetState(ctx, subject.ID, types.UserStateActive); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := AccessTokens.Lookup(ctx, tv0, "a"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,157 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// AuditLogEntry describes a security-relevant action that was performed on Sourcegraph.
+type AuditLogEntry struct {
+	ID            int64
+	ActorUserID   int32  // the user who performed the action (0 if it was not performed by a user)
+	ActorUsername string // the username of the actor at the time of the action (set by Create)
+	RemoteAddr    string // the IP address of the HTTP client (or proxy) that requested the action
+	ForwardedFor  string // the X-Forwarded-For header of the request (untrusted)
+	Action        string // the name of the action (e.g., "updateSiteConfiguration")
+	TargetKind    string // the kind of the target (e.g., "User" or "Site")
+	TargetID      string // the ID of the target
+	TargetName    string // a human-readable name of the target (e.g., the username)
+	Changes       []AuditLogChange
+	CreatedAt     time.Time
+}
+
+// AuditLogChange describes a change to a field of the target of an audited action.
+//
+// Before and After are omitted for fields whose values may be secret (such as most site
+// configuration fields).
+type AuditLogChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+type auditLog struct{}
+
+// Create appends an entry to the audit log and sets its ID, ActorUsername and CreatedAt fields.
+//
+// Entries can't be modified or deleted after they are created.
+func (*auditLog) Create(ctx context.Context, e *AuditLogEntry) error {
+	if Mocks.AuditLog.Create != nil {
+		return Mocks.AuditLog.Create(e)
+	}
+
+	changes := e.Changes
+	if changes == nil {
+		changes = []AuditLogChange{}
+	}
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	var actorUserID *int32
+	if e.ActorUserID != 0 {
+		actorUserID = &e.ActorUserID
+	}
+	return dbconn.Global.QueryRowContext(ctx, `
+INSERT INTO audit_log(actor_user_id, actor_username, remote_addr, forwarded_for, action, target_kind, target_id, target_name, changes)
+VALUES($1, COALESCE((SELECT username FROM users WHERE id=$1), ''), $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, actor_username, created_at`,
+		actorUserID, e.RemoteAddr, e.ForwardedFor, e.Action, e.TargetKind, e.TargetID, e.TargetName, string(changesJSON),
+	).Scan(&e.ID, &e.ActorUsername, &e.CreatedAt)
+}
+
+// AuditLogListOptions contains options for listing audit log entries.
+type AuditLogListOptions struct {
+	ActorUserID int32  // only list entries for actions performed by this user
+	Action      string // only list entries for this action
+	TargetKind  string // only list entries whose target is of this kind
+	TargetID    string // only list entries whose target has this ID (used with TargetKind)
+	Since       *time.Time
+	Until       *time.Time
+	*LimitOffset
+}
+
+func (o AuditLogListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if o.ActorUserID != 0 {
+		conds = append(conds, sqlf.Sprintf("actor_user_id=%d", o.ActorUserID))
+	}
+	if o.Action != "" {
+		conds = append(conds, sqlf.Sprintf("action=%s", o.Action))
+	}
+	if o.TargetKind != "" {
+		conds = append(conds, sqlf.Sprintf("target_kind=%s", o.TargetKind))
+	}
+	if o.TargetID != "" {
+		conds = append(conds, sqlf.Sprintf("target_id=%s", o.TargetID))
+	}
+	if o.Since != nil {
+		conds = append(conds, sqlf.Sprintf("created_at>=%s", *o.Since))
+	}
+	if o.Until != nil {
+		conds = append(conds, sqlf.Sprintf("created_at<%s", *o.Until))
+	}
+	return conds
+}
+
+// List lists the audit log entries that satisfy the options, most recent first.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*auditLog) List(ctx context.Context, opt AuditLogListOptions) ([]*AuditLogEntry, error) {
+	if Mocks.AuditLog.List != nil {
+		return Mocks.AuditLog.List(opt)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT id, actor_user_id, actor_username, remote_addr, forwarded_for, action, target_kind, target_id, target_name, changes, created_at
+FROM audit_log
+WHERE (%s)
+ORDER BY id DESC
+%s`,
+		sqlf.Join(opt.sqlConditions(), ") AND ("),
+		opt.LimitOffset.SQL(),
+	)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*AuditLogEntry
+	for rows.Next() {
+		var (
+			e           AuditLogEntry
+			actorUserID sql.NullInt64
+			changesJSON []byte
+		)
+		if err := rows.Scan(&e.ID, &actorUserID, &e.ActorUsername, &e.RemoteAddr, &e.ForwardedFor, &e.Action, &e.TargetKind, &e.TargetID, &e.TargetName, &changesJSON, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.ActorUserID = int32(actorUserID.Int64)
+		if err := json.Unmarshal(changesJSON, &e.Changes); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
+// Count counts the audit log entries that satisfy the options (ignoring limit and offset).
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*auditLog) Count(ctx context.Context, opt AuditLogListOptions) (int, error) {
+	if Mocks.AuditLog.Count != nil {
+		return Mocks.AuditLog.Count(opt)
+	}
+
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM audit_log WHERE (%s)", sqlf.Join(opt.sqlConditions(), ") AND ("))
+	var count int
+	err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,7 @@
+package db
+
+type MockAuditLog struct {
+	Create func(e *AuditLogEntry) error
+	List   func(opt AuditLogListOptions) ([]*AuditLogEntry, error)
+	Count  func(opt AuditLogListOptions) (int, error)
+}
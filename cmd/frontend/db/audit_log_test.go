@@ -0,0 +1,108 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+)
+
+func TestAuditLog_CreateList(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	user, err := Users.Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e1 := &AuditLogEntry{
+		ActorUserID: user.ID,
+		RemoteAddr:  "127.0.0.1",
+		Action:      "setUserIsSiteAdmin",
+		TargetKind:  "User",
+		TargetID:    "VXNlcjoy",
+		Changes:     []AuditLogChange{{Field: "siteAdmin", Before: false, After: true}},
+	}
+	if err := AuditLog.Create(ctx, e1); err != nil {
+		t.Fatal(err)
+	}
+	if e1.ID == 0 || e1.CreatedAt.IsZero() {
+		t.Errorf("got ID %d and CreatedAt %v, want them to be set", e1.ID, e1.CreatedAt)
+	}
+	if e1.ActorUsername != "u" {
+		t.Errorf("got actor username %q, want %q", e1.ActorUsername, "u")
+	}
+
+	e2 := &AuditLogEntry{Action: "updateSiteConfiguration", TargetKind: "Site", TargetID: "U2l0ZTpzaXRl"}
+	if err := AuditLog.Create(ctx, e2); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		opt  AuditLogListOptions
+		want []int64
+	}{
+		"all":         {opt: AuditLogListOptions{}, want: []int64{e2.ID, e1.ID}},
+		"actor":       {opt: AuditLogListOptions{ActorUserID: user.ID}, want: []int64{e1.ID}},
+		"action":      {opt: AuditLogListOptions{Action: "updateSiteConfiguration"}, want: []int64{e2.ID}},
+		"target":      {opt: AuditLogListOptions{TargetKind: "User", TargetID: "VXNlcjoy"}, want: []int64{e1.ID}},
+		"limit":       {opt: AuditLogListOptions{LimitOffset: &LimitOffset{Limit: 1}}, want: []int64{e2.ID}},
+		"since":       {opt: AuditLogListOptions{Since: &e1.CreatedAt}, want: []int64{e2.ID, e1.ID}},
+		"until":       {opt: AuditLogListOptions{Until: &e1.CreatedAt}, want: nil},
+		"no matches":  {opt: AuditLogListOptions{Action: "x"}, want: nil},
+		"target kind": {opt: AuditLogListOptions{TargetKind: "Site"}, want: []int64{e2.ID}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			entries, err := AuditLog.List(ctx, test.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []int64
+			for _, e := range entries {
+				ids = append(ids, e.ID)
+			}
+			if !reflect.DeepEqual(ids, test.want) {
+				t.Errorf("got entries %v, want %v", ids, test.want)
+			}
+		})
+	}
+
+	entries, err := AuditLog.List(ctx, AuditLogListOptions{ActorUserID: user.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := e1.Changes; len(entries) != 1 || !reflect.DeepEqual(entries[0].Changes, want) {
+		t.Errorf("got entries %+v, want changes %+v", entries, want)
+	}
+
+	if count, err := AuditLog.Count(ctx, AuditLogListOptions{}); err != nil {
+		t.Fatal(err)
+	} else if count != 2 {
+		t.Errorf("got count %d, want 2", count)
+	}
+}
+
+// 🚨 SECURITY: This tests that audit log entries can't be modified or deleted.
+func TestAuditLog_appendOnly(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	e := &AuditLogEntry{Action: "a", TargetKind: "k", TargetID: "i"}
+	if err := AuditLog.Create(ctx, e); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := dbconn.Global.ExecContext(ctx, "UPDATE audit_log SET action='b' WHERE id=$1", e.ID); err == nil {
+		t.Error("got nil error from UPDATE, want error")
+	}
+	if _, err := dbconn.Global.ExecContext(ctx, "DELETE FROM audit_log WHERE id=$1", e.ID); err == nil {
+		t.Error("got nil error from DELETE, want error")
+	}
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// DiscussionCommentReactionEmojis is the list of emoji that users may react to
+// discussion comments with, in the order that they should be displayed.
+var DiscussionCommentReactionEmojis = []string{"👍", "👎", "😄", "🎉", "😕", "❤️", "🚀", "👀"}
+
+// ErrInvalidReactionEmoji is the error returned by DiscussionCommentReactions
+// methods to indicate that the emoji is not one of
+// DiscussionCommentReactionEmojis.
+type ErrInvalidReactionEmoji struct {
+	Emoji string
+}
+
+func (e *ErrInvalidReactionEmoji) Error() string {
+	return fmt.Sprintf("invalid reaction emoji %q", e.Emoji)
+}
+
+func validateReactionEmoji(emoji string) error {
+	for _, e := range DiscussionCommentReactionEmojis {
+		if emoji == e {
+			return nil
+		}
+	}
+	return &ErrInvalidReactionEmoji{Emoji: emoji}
+}
+
+// discussionCommentReactions provides access to the `discussion_comment_reactions` table.
+//
+// For a detailed overview of the schema, see schema.md.
+type discussionCommentReactions struct{}
+
+// Add adds the user's reaction with the emoji to the comment. If the user has
+// already reacted to the comment with the emoji, it does nothing.
+func (*discussionCommentReactions) Add(ctx context.Context, commentID int64, userID int32, emoji string) error {
+	if Mocks.DiscussionCommentReactions.Add != nil {
+		return Mocks.DiscussionCommentReactions.Add(ctx, commentID, userID, emoji)
+	}
+	if err := validateReactionEmoji(emoji); err != nil {
+		return err
+	}
+	_, err := dbconn.Global.ExecContext(ctx, `
+INSERT INTO discussion_comment_reactions(comment_id, user_id, emoji)
+SELECT $1, $2, $3 WHERE EXISTS (SELECT 1 FROM discussion_comments WHERE id=$1 AND deleted_at IS NULL)
+ON CONFLICT DO NOTHING`,
+		commentID, userID, emoji,
+	)
+	return err
+}
+
+// Remove removes the user's reaction with the emoji from the comment. If the
+// user has not reacted to the comment with the emoji, it does nothing.
+func (*discussionCommentReactions) Remove(ctx context.Context, commentID int64, userID int32, emoji string) error {
+	if Mocks.DiscussionCommentReactions.Remove != nil {
+		return Mocks.DiscussionCommentReactions.Remove(ctx, commentID, userID, emoji)
+	}
+	if err := validateReactionEmoji(emoji); err != nil {
+		return err
+	}
+	_, err := dbconn.Global.ExecContext(ctx, "DELETE FROM discussion_comment_reactions WHERE comment_id=$1 AND user_id=$2 AND emoji=$3", commentID, userID, emoji)
+	return err
+}
+
+// ListByThread lists the reactions to all comments in the thread, ordered by
+// comment and then oldest first.
+func (*discussionCommentReactions) ListByThread(ctx context.Context, threadID int64) ([]*types.DiscussionCommentReaction, error) {
+	if Mocks.DiscussionCommentReactions.ListByThread != nil {
+		return Mocks.DiscussionCommentReactions.ListByThread(ctx, threadID)
+	}
+
+	rows, err := dbconn.Global.QueryContext(ctx, `
+SELECT r.comment_id, r.user_id, r.emoji, r.created_at FROM discussion_comment_reactions r
+JOIN discussion_comments c ON c.id=r.comment_id
+WHERE c.thread_id=$1
+ORDER BY r.comment_id ASC, r.created_at ASC, r.user_id ASC`,
+		threadID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reactions []*types.DiscussionCommentReaction
+	for rows.Next() {
+		var r types.DiscussionCommentReaction
+		if err := rows.Scan(&r.CommentID, &r.UserID, &r.Emoji, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		reactions = append(reactions, &r)
+	}
+	return reactions, rows.Err()
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+)
+
+func TestDiscussionCommentReactions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	user, err := Users.Create(ctx, NewUser{
+		Email:                 "a@a.com",
+		Username:              "u",
+		Password:              "p",
+		EmailVerificationCode: "c",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a repository to comply with the postgres repo constraint.
+	if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: "myrepo", Description: "", Fork: false, Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := Repos.GetByName(ctx, "myrepo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	thread, err := DiscussionThreads.Create(ctx, &types.DiscussionThread{
+		AuthorUserID: user.ID,
+		Title:        "Hello world!",
+		TargetRepo:   &types.DiscussionThreadTargetRepo{RepoID: repo.ID, Path: strPtr("mux.go")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	comment, err := DiscussionComments.Create(ctx, &types.DiscussionComment{
+		ThreadID:     thread.ID,
+		AuthorUserID: user.ID,
+		Contents:     "LGTM",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherComment, err := DiscussionComments.Create(ctx, &types.DiscussionComment{
+		ThreadID:     thread.ID,
+		AuthorUserID: user.ID,
+		Contents:     "Ship it",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := DiscussionCommentReactions.Add(ctx, otherComment.ID, user.ID, "🚀"); err != nil {
+		t.Fatal(err)
+	}
+
+	// listEmojis lists the thread's reactions, which must include the other
+	// comment's reaction last.
+	listEmojis := func() (emojis []string) {
+		t.Helper()
+		reactions, err := DiscussionCommentReactions.ListByThread(ctx, thread.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(reactions) == 0 {
+			t.Fatal("got no reactions")
+		}
+		last := reactions[len(reactions)-1]
+		if last.CommentID != otherComment.ID || last.Emoji != "🚀" {
+			t.Errorf("got last reaction %+v, want 🚀 reaction to comment %d", last, otherComment.ID)
+		}
+		for _, r := range reactions[:len(reactions)-1] {
+			if r.CommentID != comment.ID || r.UserID != user.ID {
+				t.Errorf("got reaction %+v, want reaction by user %d to comment %d", r, user.ID, comment.ID)
+			}
+			emojis = append(emojis, r.Emoji)
+		}
+		return emojis
+	}
+
+	// Adding the same reaction twice is a no-op.
+	for _, emoji := range []string{"👍", "🎉", "👍"} {
+		if err := DiscussionCommentReactions.Add(ctx, comment.ID, user.ID, emoji); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := listEmojis(), []string{"👍", "🎉"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got reactions %v, want %v", got, want)
+	}
+
+	if err := DiscussionCommentReactions.Add(ctx, comment.ID, user.ID, "x"); err == nil {
+		t.Error("got nil error, want invalid emoji error")
+	}
+
+	if err := DiscussionCommentReactions.Remove(ctx, comment.ID, user.ID, "👍"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := listEmojis(), []string{"🎉"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got reactions %v, want %v", got, want)
+	}
+}
@@ -189,6 +189,10 @@ type DiscussionCommentsListOptions struct {
 	// should be returned.
 	ThreadID *int64
 
+	// ThreadIDs, when len() > 0, specifies that only comments in one of these
+	// threads should be returned.
+	ThreadIDs []int64
+
 	// CommentID, when non-nil, specifies that only comments with this ID should
 	// be returned.
 	CommentID *int64
@@ -196,10 +200,20 @@ type DiscussionCommentsListOptions struct {
 	// Reported, when true, returns only threads that have at least one report.
 	Reported bool
 
+	// FullTextQuery, when non-nil, specifies that only comments whose contents
+	// match this full-text search query should be returned (see
+	// discussionsFullTextMatch).
+	FullTextQuery *string
+
 	// CreatedBefore, when non-nil, specifies that only comments that were
 	// created before this time should be returned.
 	CreatedBefore *time.Time
 	CreatedAfter  *time.Time
+
+	// LimitPerThread, when > 0, specifies that only the first (oldest) this
+	// many comments of each thread that match the other options should be
+	// returned.
+	LimitPerThread int
 }
 
 func (c *discussionComments) List(ctx context.Context, opts *DiscussionCommentsListOptions) ([]*types.DiscussionComment, error) {
@@ -252,18 +266,36 @@ func (*discussionComments) getListSQL(opts *DiscussionCommentsListOptions) (cond
 	if opts.ThreadID != nil {
 		conds = append(conds, sqlf.Sprintf("thread_id=%v", *opts.ThreadID))
 	}
+	if len(opts.ThreadIDs) > 0 {
+		conds = append(conds, sqlf.Sprintf("thread_id = ANY(%v)", pq.Array(opts.ThreadIDs)))
+	}
 	if opts.CommentID != nil {
 		conds = append(conds, sqlf.Sprintf("id=%v", *opts.CommentID))
 	}
 	if opts.Reported {
 		conds = append(conds, sqlf.Sprintf("array_length(reports,1) > 0"))
 	}
+	if opts.FullTextQuery != nil {
+		conds = append(conds, discussionsFullTextMatch("contents", *opts.FullTextQuery))
+	}
 	if opts.CreatedBefore != nil {
 		conds = append(conds, sqlf.Sprintf("created_at < %v", *opts.CreatedBefore))
 	}
 	if opts.CreatedAfter != nil {
 		conds = append(conds, sqlf.Sprintf("created_at > %v", *opts.CreatedAfter))
 	}
+	if opts.LimitPerThread > 0 {
+		// Number the matching comments of each thread (in the order in which
+		// they are listed) and keep only the first ones.
+		conds = []*sqlf.Query{sqlf.Sprintf(`id IN (
+	SELECT id FROM (
+		SELECT id, ROW_NUMBER() OVER (PARTITION BY thread_id ORDER BY id ASC) AS thread_rank
+		FROM discussion_comments
+		WHERE %s
+	) ranked
+	WHERE thread_rank <= %v
+)`, sqlf.Join(conds, "AND"), opts.LimitPerThread)}
+	}
 	return conds
 }
 
@@ -70,12 +70,31 @@ func (t *discussionThreads) Create(ctx context.Context, newThread *types.Discuss
 	if newThread.DeletedAt != nil {
 		return nil, errors.New("newThread.DeletedAt must not be specified")
 	}
+	if newThread.ResolvedAt != nil || newThread.ResolvedByUserID != nil {
+		return nil, errors.New("newThread.ResolvedAt and newThread.ResolvedByUserID must not be specified")
+	}
+	if newThread.AssigneeUserID != nil {
+		return nil, errors.New("newThread.AssigneeUserID must not be specified")
+	}
 	if newThread.TargetRepo != nil {
 		if rev := newThread.TargetRepo.Revision; rev != nil {
 			if !git.IsAbsoluteRevision(*rev) {
 				return nil, errors.New("newThread.TargetRepo.Revision must be an absolute Git revision (40 character SHA-1 hash)")
 			}
 		}
+		if rev := newThread.TargetRepo.DiffHeadRevision; rev != nil {
+			if !git.IsAbsoluteRevision(*rev) {
+				return nil, errors.New("newThread.TargetRepo.DiffHeadRevision must be an absolute Git revision (40 character SHA-1 hash)")
+			}
+		}
+		if rev := newThread.TargetRepo.DiffBaseRevision; rev != nil {
+			if newThread.TargetRepo.DiffHeadRevision == nil {
+				return nil, errors.New("newThread.TargetRepo.DiffBaseRevision requires DiffHeadRevision")
+			}
+			if !git.IsAbsoluteRevision(*rev) {
+				return nil, errors.New("newThread.TargetRepo.DiffBaseRevision must be an absolute Git revision (40 character SHA-1 hash)")
+			}
+		}
 	} else {
 		return nil, errors.New("newThread must have a target")
 	}
@@ -150,6 +169,17 @@ type DiscussionThreadsUpdateOptions struct {
 	// Archive, when non-nil, specifies whether the thread is archived or not.
 	Archive *bool
 
+	// Resolve, when non-nil, specifies whether the thread is resolved or not.
+	// When resolving the thread, ResolvedByUserID must be the user who
+	// resolved it. Resolving an already-resolved thread (or unresolving an
+	// unresolved one) does nothing, so the original resolver is kept.
+	Resolve          *bool
+	ResolvedByUserID int32
+
+	// AssigneeUserID, when non-nil, updates the user the thread is assigned
+	// to. A value of zero unassigns the thread.
+	AssigneeUserID *int32
+
 	// Delete, when true, specifies that the thread should be deleted. This
 	// operation cannot be undone.
 	Delete bool
@@ -183,6 +213,35 @@ func (t *discussionThreads) Update(ctx context.Context, threadID int64, opts *Di
 			return nil, err
 		}
 	}
+	if opts.Resolve != nil {
+		anyUpdate = true
+		var (
+			resolvedAt       *time.Time
+			resolvedByUserID *int32
+			wasResolved      = "resolved_at IS NOT NULL"
+		)
+		if *opts.Resolve {
+			if opts.ResolvedByUserID == 0 {
+				return nil, errors.New("ResolvedByUserID must be specified when resolving a thread")
+			}
+			resolvedAt = &now
+			resolvedByUserID = &opts.ResolvedByUserID
+			wasResolved = "resolved_at IS NULL"
+		}
+		if _, err := dbconn.Global.ExecContext(ctx, "UPDATE discussion_threads SET resolved_at=$1, resolved_by_user_id=$2 WHERE id=$3 AND deleted_at IS NULL AND "+wasResolved, resolvedAt, resolvedByUserID, threadID); err != nil {
+			return nil, err
+		}
+	}
+	if opts.AssigneeUserID != nil {
+		anyUpdate = true
+		var assigneeUserID *int32
+		if *opts.AssigneeUserID != 0 {
+			assigneeUserID = opts.AssigneeUserID
+		}
+		if _, err := dbconn.Global.ExecContext(ctx, "UPDATE discussion_threads SET assignee_user_id=$1 WHERE id=$2 AND deleted_at IS NULL", assigneeUserID, threadID); err != nil {
+			return nil, err
+		}
+	}
 	if opts.Delete {
 		anyUpdate = true
 		if _, err := dbconn.Global.ExecContext(ctx, "UPDATE discussion_threads SET deleted_at=$1 WHERE id=$2 AND deleted_at IS NULL", now, threadID); err != nil {
@@ -238,11 +297,24 @@ type DiscussionThreadsListOptions struct {
 	TargetRepoID    *api.RepoID
 	NotTargetRepoID *api.RepoID
 
+	// TargetRepoIDs, when len() > 0, specifies that only threads that have a
+	// repo target in one of these repos should be returned.
+	TargetRepoIDs []api.RepoID
+
 	// TargetRepoPath, when non-nil, specifies that only threads that have a repo target
 	// and this path should be returned.
 	TargetRepoPath    *string
 	NotTargetRepoPath *string
 
+	// TargetRepoDiffs, when len() > 0, specifies that only threads that have a
+	// repo target on one of these diffs should be returned.
+	TargetRepoDiffs []DiscussionThreadsTargetRepoDiff
+
+	// FullTextQuery, when non-nil, specifies that only threads whose title or
+	// comments match this full-text search query should be returned (see
+	// discussionsFullTextMatch).
+	FullTextQuery *string
+
 	// CreatedBefore, when non-nil, specifies that only threads that were
 	// created before this time should be returned.
 	CreatedBefore *time.Time
@@ -255,6 +327,26 @@ type DiscussionThreadsListOptions struct {
 	// Reported, when true, specifies that only threads with at least one
 	// reported comment should be returned.
 	Reported bool
+
+	// Resolved, when non-nil, specifies that only threads that are resolved
+	// (true) or unresolved (false) should be returned.
+	Resolved *bool
+
+	// AssigneeUserIDs, when len() > 0, specifies that only threads assigned to
+	// one of these users should be returned.
+	AssigneeUserIDs []int32
+}
+
+// DiscussionThreadsTargetRepoDiff identifies the diff of a commit or of a
+// comparison between two revisions, for DiscussionThreadsListOptions.
+type DiscussionThreadsTargetRepoDiff struct {
+	// BaseRevision is the absolute base revision of the comparison, or nil
+	// for the diff of the commit HeadRevision.
+	BaseRevision *string
+
+	// HeadRevision is the absolute head revision of the comparison, or the
+	// commit.
+	HeadRevision string
 }
 
 // SetFromQuery sets the options based on the search query string.
@@ -390,6 +482,23 @@ func (opts *DiscussionThreadsListOptions) SetFromQuery(ctx context.Context, quer
 		"reported": func(value string) {
 			reported, _ = strconv.ParseBool(value)
 		},
+
+		// syntax: "resolved:true" or "resolved:false"
+		"resolved": func(value string) {
+			resolved, err := strconv.ParseBool(value)
+			if err != nil {
+				return
+			}
+			opts.Resolved = &resolved
+		},
+
+		// syntax: "assignee:slimsag" or "assignee:@slimsag" or `assignee:"slimsag @jack"`
+		"assignee": func(value string) {
+			opts.AssigneeUserIDs = userIDsList(value)
+			if len(opts.AssigneeUserIDs) == 0 {
+				opts.AssigneeUserIDs = []int32{-1}
+			}
+		},
 	}
 	remaining, operations := searchquery.Parse(query)
 	for _, operation := range operations {
@@ -549,6 +658,16 @@ func (*discussionThreads) getListSQL(opts *DiscussionThreadsListOptions) (conds
 	if opts.CreatedAfter != nil {
 		conds = append(conds, sqlf.Sprintf("created_at > %v", *opts.CreatedAfter))
 	}
+	if opts.Resolved != nil {
+		if *opts.Resolved {
+			conds = append(conds, sqlf.Sprintf("resolved_at IS NOT NULL"))
+		} else {
+			conds = append(conds, sqlf.Sprintf("resolved_at IS NULL"))
+		}
+	}
+	if len(opts.AssigneeUserIDs) > 0 {
+		conds = append(conds, sqlf.Sprintf("assignee_user_id = ANY(%v)", pq.Array(opts.AssigneeUserIDs)))
+	}
 
 	if opts.TargetRepoID != nil || opts.TargetRepoPath != nil || opts.NotTargetRepoID != nil || opts.NotTargetRepoPath != nil {
 		targetRepoConds := []*sqlf.Query{}
@@ -576,6 +695,26 @@ func (*discussionThreads) getListSQL(opts *DiscussionThreadsListOptions) (conds
 		}
 		conds = append(conds, sqlf.Sprintf("id IN (SELECT thread_id FROM discussion_threads_target_repo WHERE %v)", sqlf.Join(targetRepoConds, "AND")))
 	}
+	if len(opts.TargetRepoIDs) > 0 {
+		conds = append(conds, sqlf.Sprintf("id IN (SELECT thread_id FROM discussion_threads_target_repo WHERE repo_id = ANY(%v))", pq.Array(opts.TargetRepoIDs)))
+	}
+	if opts.FullTextQuery != nil {
+		conds = append(conds, sqlf.Sprintf("(%s OR id IN (SELECT thread_id FROM discussion_comments WHERE deleted_at IS NULL AND %s))",
+			discussionsFullTextMatch("title", *opts.FullTextQuery),
+			discussionsFullTextMatch("contents", *opts.FullTextQuery),
+		))
+	}
+	if len(opts.TargetRepoDiffs) > 0 {
+		diffConds := make([]*sqlf.Query, 0, len(opts.TargetRepoDiffs))
+		for _, d := range opts.TargetRepoDiffs {
+			if d.BaseRevision == nil {
+				diffConds = append(diffConds, sqlf.Sprintf("(diff_head_revision=%v AND diff_base_revision IS NULL)", d.HeadRevision))
+			} else {
+				diffConds = append(diffConds, sqlf.Sprintf("(diff_head_revision=%v AND diff_base_revision=%v)", d.HeadRevision, *d.BaseRevision))
+			}
+		}
+		conds = append(conds, sqlf.Sprintf("id IN (SELECT thread_id FROM discussion_threads_target_repo WHERE %v)", sqlf.Join(diffConds, "OR")))
+	}
 	return conds
 }
 
@@ -617,6 +756,23 @@ func (t *discussionThreads) createTargetRepo(ctx context.Context, tr *types.Disc
 		field("lines", strings.Join(*tr.Lines, "\n"))
 		field("lines_after", strings.Join(*tr.LinesAfter, "\n"))
 	}
+	if tr.DiffHeadRevision != nil {
+		field("diff_head_revision", *tr.DiffHeadRevision)
+	}
+	if tr.DiffBaseRevision != nil {
+		field("diff_base_revision", *tr.DiffBaseRevision)
+	}
+	if tr.OldPath != nil {
+		field("old_path", *tr.OldPath)
+	}
+	if tr.OldStartLine != nil && tr.OldEndLine != nil {
+		field("old_start_line", *tr.OldStartLine)
+		field("old_end_line", *tr.OldEndLine)
+	}
+	if tr.NewStartLine != nil && tr.NewEndLine != nil {
+		field("new_start_line", *tr.NewStartLine)
+		field("new_end_line", *tr.NewEndLine)
+	}
 	q := sqlf.Sprintf("INSERT INTO discussion_threads_target_repo(%v) VALUES (%v) RETURNING id", sqlf.Join(fields, ",\n"), sqlf.Join(values, ","))
 
 	// To debug query building, uncomment these lines:
@@ -640,7 +796,10 @@ func (t *discussionThreads) getBySQL(ctx context.Context, query string, args ...
 			t.target_repo_id,
 			t.created_at,
 			t.archived_at,
-			t.updated_at
+			t.updated_at,
+			t.resolved_at,
+			t.resolved_by_user_id,
+			t.assignee_user_id
 		FROM discussion_threads t `+query, args...)
 	if err != nil {
 		return nil, err
@@ -661,6 +820,9 @@ func (t *discussionThreads) getBySQL(ctx context.Context, query string, args ...
 			&thread.CreatedAt,
 			&thread.ArchivedAt,
 			&thread.UpdatedAt,
+			&thread.ResolvedAt,
+			&thread.ResolvedByUserID,
+			&thread.AssigneeUserID,
 		)
 		if err != nil {
 			return nil, err
@@ -696,7 +858,14 @@ func (t *discussionThreads) getTargetRepo(ctx context.Context, targetRepoID int6
 			t.end_character,
 			t.lines_before,
 			t.lines,
-			t.lines_after
+			t.lines_after,
+			t.diff_base_revision,
+			t.diff_head_revision,
+			t.old_path,
+			t.old_start_line,
+			t.old_end_line,
+			t.new_start_line,
+			t.new_end_line
 		FROM discussion_threads_target_repo t WHERE id=$1
 	`, targetRepoID).Scan(
 		&tr.ID,
@@ -712,6 +881,13 @@ func (t *discussionThreads) getTargetRepo(ctx context.Context, targetRepoID int6
 		&linesBefore,
 		&lines,
 		&linesAfter,
+		&tr.DiffBaseRevision,
+		&tr.DiffHeadRevision,
+		&tr.OldPath,
+		&tr.OldStartLine,
+		&tr.OldEndLine,
+		&tr.NewStartLine,
+		&tr.NewEndLine,
 	)
 	if err != nil {
 		return nil, err
@@ -749,3 +925,15 @@ func extraFuzzy(s string) string {
 	}
 	return string(result)
 }
+
+// discussionsFullTextMatch returns a SQL condition that is true when the text
+// column (a thread's title or a comment's contents) matches the full-text
+// search query, which is interpreted as plain text (all of its words must
+// match, after stemming).
+//
+// The expression must stay identical to the expressions of the full-text
+// indexes on discussion_threads.title and discussion_comments.contents, or the
+// indexes are not used.
+func discussionsFullTextMatch(column, query string) *sqlf.Query {
+	return sqlf.Sprintf("to_tsvector('english', "+column+") @@ plainto_tsquery('english', %v)", query)
+}
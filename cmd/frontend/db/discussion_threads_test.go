@@ -257,6 +257,248 @@ func TestDiscussionThreads_List(t *testing.T) {
 	}
 }
 
+func TestDiscussionThreads_ListTargetRepoDiffs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	user, err := Users.Create(ctx, NewUser{
+		Email:                 "a@a.com",
+		Username:              "u",
+		Password:              "p",
+		EmailVerificationCode: "c",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a repository to comply with the postgres repo constraint.
+	if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: "myrepo", Description: "", Fork: false, Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := Repos.GetByName(ctx, "myrepo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		base = "0c1a96370c1a96370c1a96370c1a96370c1a9637"
+		head = "1d2b07481d2b07481d2b07481d2b07481d2b0748"
+	)
+	createThread := func(target *types.DiscussionThreadTargetRepo) *types.DiscussionThread {
+		t.Helper()
+		target.RepoID = repo.ID
+		thread, err := DiscussionThreads.Create(ctx, &types.DiscussionThread{
+			AuthorUserID: user.ID,
+			Title:        "Hello world!",
+			TargetRepo:   target,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return thread
+	}
+	fileThread := createThread(&types.DiscussionThreadTargetRepo{
+		Path:     strPtr("foo/bar/mux.go"),
+		Revision: strPtr(head),
+	})
+	commitThread := createThread(&types.DiscussionThreadTargetRepo{
+		Path:             strPtr("foo/bar/mux.go"),
+		Revision:         strPtr(head),
+		DiffHeadRevision: strPtr(head),
+		OldPath:          strPtr("foo/mux.go"),
+		OldStartLine:     int32Ptr(3),
+		OldEndLine:       int32Ptr(5),
+		NewStartLine:     int32Ptr(3),
+		NewEndLine:       int32Ptr(4),
+	})
+	comparisonThread := createThread(&types.DiscussionThreadTargetRepo{
+		Revision:         strPtr(head),
+		DiffBaseRevision: strPtr(base),
+		DiffHeadRevision: strPtr(head),
+	})
+
+	gotThread, err := DiscussionThreads.Get(ctx, commitThread.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitThread.TargetRepo.ThreadID = gotThread.TargetRepo.ThreadID
+	if !reflect.DeepEqual(gotThread.TargetRepo, commitThread.TargetRepo) {
+		t.Logf("got thread TargetRepo:  %v", spew.Sdump(gotThread.TargetRepo))
+		t.Fatalf("want thread TargetRepo: %v", spew.Sdump(commitThread.TargetRepo))
+	}
+
+	tests := []struct {
+		name  string
+		diffs []DiscussionThreadsTargetRepoDiff
+		want  []int64
+	}{
+		{
+			name: "no diffs",
+			want: []int64{comparisonThread.ID, commitThread.ID, fileThread.ID},
+		},
+		{
+			name:  "commit",
+			diffs: []DiscussionThreadsTargetRepoDiff{{HeadRevision: head}},
+			want:  []int64{commitThread.ID},
+		},
+		{
+			name:  "comparison",
+			diffs: []DiscussionThreadsTargetRepoDiff{{BaseRevision: strPtr(base), HeadRevision: head}},
+			want:  []int64{comparisonThread.ID},
+		},
+		{
+			name:  "commit or comparison",
+			diffs: []DiscussionThreadsTargetRepoDiff{{HeadRevision: head}, {BaseRevision: strPtr(base), HeadRevision: head}},
+			want:  []int64{comparisonThread.ID, commitThread.ID},
+		},
+		{
+			name:  "other commit",
+			diffs: []DiscussionThreadsTargetRepoDiff{{HeadRevision: base}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			threads, err := DiscussionThreads.List(ctx, &DiscussionThreadsListOptions{TargetRepoDiffs: test.diffs})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int64
+			for _, thread := range threads {
+				got = append(got, thread.ID)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got threads %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDiscussionThreads_ListFullTextQuery(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	user, err := Users.Create(ctx, NewUser{
+		Email:                 "a@a.com",
+		Username:              "u",
+		Password:              "p",
+		EmailVerificationCode: "c",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create repositories to comply with the postgres repo constraint.
+	var repos []*types.Repo
+	for _, name := range []api.RepoName{"myrepo", "otherrepo"} {
+		if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: name, Description: "", Fork: false, Enabled: true}); err != nil {
+			t.Fatal(err)
+		}
+		repo, err := Repos.GetByName(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		repos = append(repos, repo)
+	}
+
+	createThread := func(repo *types.Repo, title string, comments ...string) *types.DiscussionThread {
+		t.Helper()
+		thread, err := DiscussionThreads.Create(ctx, &types.DiscussionThread{
+			AuthorUserID: user.ID,
+			Title:        title,
+			TargetRepo:   &types.DiscussionThreadTargetRepo{RepoID: repo.ID, Path: strPtr("mux.go")},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, contents := range comments {
+			if _, err := DiscussionComments.Create(ctx, &types.DiscussionComment{ThreadID: thread.ID, AuthorUserID: user.ID, Contents: contents}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return thread
+	}
+	titleThread := createThread(repos[0], "Routers should handle panics", "What do you think?")
+	commentThread := createThread(repos[0], "Question", "Does the router recover from a panic?", "No")
+	otherRepoThread := createThread(repos[1], "Panics in handlers")
+
+	tests := []struct {
+		name          string
+		query         string
+		targetRepoIDs []api.RepoID
+		want          []int64
+	}{
+		{
+			name:  "title or comment (stemmed)",
+			query: "panic",
+			want:  []int64{otherRepoThread.ID, commentThread.ID, titleThread.ID},
+		},
+		{
+			name:  "all words must match",
+			query: "router panic",
+			want:  []int64{commentThread.ID, titleThread.ID},
+		},
+		{
+			name:          "in repos",
+			query:         "panic",
+			targetRepoIDs: []api.RepoID{repos[1].ID},
+			want:          []int64{otherRepoThread.ID},
+		},
+		{
+			name:  "no match",
+			query: "mutex",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			threads, err := DiscussionThreads.List(ctx, &DiscussionThreadsListOptions{FullTextQuery: &test.query, TargetRepoIDs: test.targetRepoIDs})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int64
+			for _, thread := range threads {
+				got = append(got, thread.ID)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got threads %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	comments, err := DiscussionComments.List(ctx, &DiscussionCommentsListOptions{ThreadID: &commentThread.ID, FullTextQuery: strPtr("panics")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 1 || comments[0].Contents != "Does the router recover from a panic?" {
+		t.Errorf("got comments %+v, want the comment about panics", comments)
+	}
+
+	comments, err = DiscussionComments.List(ctx, &DiscussionCommentsListOptions{ThreadIDs: []int64{titleThread.ID, commentThread.ID}, FullTextQuery: strPtr("think router")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 0 {
+		t.Errorf("got comments %+v, want none (no comment matches all words)", comments)
+	}
+	comments, err = DiscussionComments.List(ctx, &DiscussionCommentsListOptions{ThreadIDs: []int64{titleThread.ID, commentThread.ID, otherRepoThread.ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 3 {
+		t.Errorf("got %d comments, want all 3 comments of the threads", len(comments))
+	}
+	comments, err = DiscussionComments.List(ctx, &DiscussionCommentsListOptions{ThreadIDs: []int64{titleThread.ID, commentThread.ID, otherRepoThread.ID}, LimitPerThread: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 2 || comments[0].Contents != "What do you think?" || comments[1].Contents != "Does the router recover from a panic?" {
+		t.Errorf("got comments %+v, want the first comment of each thread", comments)
+	}
+}
+
 func TestDiscussionThreads_Delete(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -324,6 +566,129 @@ func TestDiscussionThreads_Delete(t *testing.T) {
 	}
 }
 
+func TestDiscussionThreads_ResolveAssign(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	var users []*types.User
+	for _, username := range []string{"u1", "u2"} {
+		user, err := Users.Create(ctx, NewUser{
+			Email:                 username + "@example.com",
+			Username:              username,
+			Password:              "p",
+			EmailVerificationCode: "c",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		users = append(users, user)
+	}
+
+	// Create a repository to comply with the postgres repo constraint.
+	if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: "myrepo", Description: "", Fork: false, Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := Repos.GetByName(ctx, "myrepo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var threads []*types.DiscussionThread
+	for _, title := range []string{"Resolved", "Assigned"} {
+		thread, err := DiscussionThreads.Create(ctx, &types.DiscussionThread{
+			AuthorUserID: users[0].ID,
+			Title:        title,
+			TargetRepo:   &types.DiscussionThreadTargetRepo{RepoID: repo.ID, Path: strPtr("mux.go")},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		threads = append(threads, thread)
+	}
+	resolvedThread, assignedThread := threads[0], threads[1]
+
+	// Resolve the first thread and assign the second thread.
+	gotThread, err := DiscussionThreads.Update(ctx, resolvedThread.ID, &DiscussionThreadsUpdateOptions{
+		Resolve:          boolPtr(true),
+		ResolvedByUserID: users[1].ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotThread.ResolvedAt == nil || gotThread.ResolvedByUserID == nil || *gotThread.ResolvedByUserID != users[1].ID {
+		t.Errorf("got ResolvedAt %v ResolvedByUserID %v, want thread resolved by user %d", gotThread.ResolvedAt, gotThread.ResolvedByUserID, users[1].ID)
+	}
+	resolvedAt := *gotThread.ResolvedAt
+
+	// Resolving an already-resolved thread keeps the original resolution.
+	gotThread, err = DiscussionThreads.Update(ctx, resolvedThread.ID, &DiscussionThreadsUpdateOptions{
+		Resolve:          boolPtr(true),
+		ResolvedByUserID: users[0].ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotThread.ResolvedAt == nil || !gotThread.ResolvedAt.Equal(resolvedAt) || gotThread.ResolvedByUserID == nil || *gotThread.ResolvedByUserID != users[1].ID {
+		t.Errorf("got ResolvedAt %v ResolvedByUserID %v, want thread still resolved at %v by user %d", gotThread.ResolvedAt, gotThread.ResolvedByUserID, resolvedAt, users[1].ID)
+	}
+
+	gotThread, err = DiscussionThreads.Update(ctx, assignedThread.ID, &DiscussionThreadsUpdateOptions{
+		AssigneeUserID: &users[1].ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotThread.AssigneeUserID == nil || *gotThread.AssigneeUserID != users[1].ID {
+		t.Errorf("got AssigneeUserID %v, want %d", gotThread.AssigneeUserID, users[1].ID)
+	}
+
+	listIDs := func(opts *DiscussionThreadsListOptions) (ids []int64) {
+		t.Helper()
+		threads, err := DiscussionThreads.List(ctx, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, thread := range threads {
+			ids = append(ids, thread.ID)
+		}
+		return ids
+	}
+	tests := map[string]struct {
+		opts *DiscussionThreadsListOptions
+		want []int64
+	}{
+		"resolved":    {opts: &DiscussionThreadsListOptions{Resolved: boolPtr(true)}, want: []int64{resolvedThread.ID}},
+		"unresolved":  {opts: &DiscussionThreadsListOptions{Resolved: boolPtr(false)}, want: []int64{assignedThread.ID}},
+		"assigned":    {opts: &DiscussionThreadsListOptions{AssigneeUserIDs: []int32{users[1].ID}}, want: []int64{assignedThread.ID}},
+		"no assigned": {opts: &DiscussionThreadsListOptions{AssigneeUserIDs: []int32{users[0].ID}}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := listIDs(test.opts); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got threads %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	// Unresolve and unassign the threads.
+	gotThread, err = DiscussionThreads.Update(ctx, resolvedThread.ID, &DiscussionThreadsUpdateOptions{Resolve: boolPtr(false)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotThread.ResolvedAt != nil || gotThread.ResolvedByUserID != nil {
+		t.Errorf("got ResolvedAt %v ResolvedByUserID %v, want thread unresolved", gotThread.ResolvedAt, gotThread.ResolvedByUserID)
+	}
+	gotThread, err = DiscussionThreads.Update(ctx, assignedThread.ID, &DiscussionThreadsUpdateOptions{AssigneeUserID: int32Ptr(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotThread.AssigneeUserID != nil {
+		t.Errorf("got AssigneeUserID %v, want thread unassigned", *gotThread.AssigneeUserID)
+	}
+}
+
 func boolPtr(b bool) *bool {
 	return &b
 }
@@ -331,3 +696,7 @@ func boolPtr(b bool) *bool {
 func strPtr(s string) *string {
 	return &s
 }
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
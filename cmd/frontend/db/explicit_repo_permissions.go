@@ -0,0 +1,220 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbutil"
+)
+
+// ExplicitRepoPatternPermissions is the set of users that are allowed to read
+// the repositories whose names match a pattern.
+type ExplicitRepoPatternPermissions struct {
+	Pattern string // a regular expression matched against repository names
+	UserIDs []int32
+}
+
+// ExplicitUserRepoPermissions is the set of repositories a user is explicitly
+// allowed to read.
+type ExplicitUserRepoPermissions struct {
+	RepoIDs  []api.RepoID
+	Patterns []string // regular expressions matched against repository names
+}
+
+// explicitRepoPermissions provides access to the `explicit_repo_permissions`
+// table, which holds the read permissions granted by site admins to specific
+// users on repositories whose code host has no permissions of its own (such as
+// Gitolite and "other" repositories).
+//
+// A permission applies either to a single repository or to all repositories
+// whose names match a regular expression.
+//
+// For a detailed overview of the schema, see schema.md.
+type explicitRepoPermissions struct{}
+
+// SetRepoUsers replaces the set of users that are explicitly allowed to read
+// the given repository. An empty set of users removes all explicit permissions
+// on the repository.
+func (*explicitRepoPermissions) SetRepoUsers(ctx context.Context, repoID api.RepoID, userIDs []int32) error {
+	if Mocks.ExplicitRepoPermissions.SetRepoUsers != nil {
+		return Mocks.ExplicitRepoPermissions.SetRepoUsers(ctx, repoID, userIDs)
+	}
+
+	return dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		q := sqlf.Sprintf("DELETE FROM explicit_repo_permissions WHERE repo_id=%s AND permission=%s", repoID, authz.Read.String())
+		if _, err := tx.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...); err != nil {
+			return err
+		}
+		for _, userID := range dedupeUserIDs(userIDs) {
+			q := sqlf.Sprintf("INSERT INTO explicit_repo_permissions(user_id, repo_id, permission) VALUES(%s, %s, %s)", userID, repoID, authz.Read.String())
+			if _, err := tx.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetPatternUsers replaces the set of users that are explicitly allowed to read
+// the repositories whose names match the given regular expression. An empty set
+// of users removes the pattern.
+func (*explicitRepoPermissions) SetPatternUsers(ctx context.Context, pattern string, userIDs []int32) error {
+	if Mocks.ExplicitRepoPermissions.SetPatternUsers != nil {
+		return Mocks.ExplicitRepoPermissions.SetPatternUsers(ctx, pattern, userIDs)
+	}
+
+	if pattern == "" {
+		return errors.New("repository pattern must not be empty")
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return errors.Wrap(err, "invalid repository pattern")
+	}
+
+	return dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		q := sqlf.Sprintf("DELETE FROM explicit_repo_permissions WHERE repo_pattern=%s AND permission=%s", pattern, authz.Read.String())
+		if _, err := tx.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...); err != nil {
+			return err
+		}
+		for _, userID := range dedupeUserIDs(userIDs) {
+			q := sqlf.Sprintf("INSERT INTO explicit_repo_permissions(user_id, repo_pattern, permission) VALUES(%s, %s, %s)", userID, pattern, authz.Read.String())
+			if _, err := tx.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListRepoUsers returns the IDs of the users that are explicitly allowed to
+// read the given repository (not including users that are allowed to read it
+// through a pattern).
+func (*explicitRepoPermissions) ListRepoUsers(ctx context.Context, repoID api.RepoID) ([]int32, error) {
+	if Mocks.ExplicitRepoPermissions.ListRepoUsers != nil {
+		return Mocks.ExplicitRepoPermissions.ListRepoUsers(ctx, repoID)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT user_id FROM explicit_repo_permissions
+WHERE repo_id=%s AND permission=%s
+ORDER BY user_id
+`, repoID, authz.Read.String())
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, id)
+	}
+	return userIDs, rows.Err()
+}
+
+// ListPatterns returns all repository patterns and the IDs of the users that
+// are allowed to read the repositories matching each of them.
+func (*explicitRepoPermissions) ListPatterns(ctx context.Context) ([]*ExplicitRepoPatternPermissions, error) {
+	if Mocks.ExplicitRepoPermissions.ListPatterns != nil {
+		return Mocks.ExplicitRepoPermissions.ListPatterns(ctx)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT repo_pattern, array_agg(user_id ORDER BY user_id) FROM explicit_repo_permissions
+WHERE repo_pattern IS NOT NULL AND permission=%s
+GROUP BY repo_pattern
+ORDER BY repo_pattern
+`, authz.Read.String())
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ps []*ExplicitRepoPatternPermissions
+	for rows.Next() {
+		var (
+			p   ExplicitRepoPatternPermissions
+			ids pq.Int64Array
+		)
+		if err := rows.Scan(&p.Pattern, &ids); err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			p.UserIDs = append(p.UserIDs, int32(id))
+		}
+		ps = append(ps, &p)
+	}
+	return ps, rows.Err()
+}
+
+// GetForUser returns the repositories and repository patterns the given user
+// is explicitly allowed to read.
+func (*explicitRepoPermissions) GetForUser(ctx context.Context, userID int32) (*ExplicitUserRepoPermissions, error) {
+	if Mocks.ExplicitRepoPermissions.GetForUser != nil {
+		return Mocks.ExplicitRepoPermissions.GetForUser(ctx, userID)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT repo_id, repo_pattern FROM explicit_repo_permissions
+WHERE user_id=%s AND permission=%s
+ORDER BY id
+`, userID, authz.Read.String())
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var p ExplicitUserRepoPermissions
+	for rows.Next() {
+		var (
+			repoID  sql.NullInt64
+			pattern sql.NullString
+		)
+		if err := rows.Scan(&repoID, &pattern); err != nil {
+			return nil, err
+		}
+		if repoID.Valid {
+			p.RepoIDs = append(p.RepoIDs, api.RepoID(repoID.Int64))
+		}
+		if pattern.Valid {
+			p.Patterns = append(p.Patterns, pattern.String)
+		}
+	}
+	return &p, rows.Err()
+}
+
+func dedupeUserIDs(userIDs []int32) []int32 {
+	seen := make(map[int32]bool, len(userIDs))
+	deduped := make([]int32, 0, len(userIDs))
+	for _, id := range userIDs {
+		if !seen[id] {
+			seen[id] = true
+			deduped = append(deduped, id)
+		}
+	}
+	return deduped
+}
+
+// MockExplicitRepoPermissions mocks the explicit repository permissions store.
+type MockExplicitRepoPermissions struct {
+	SetRepoUsers    func(ctx context.Context, repoID api.RepoID, userIDs []int32) error
+	SetPatternUsers func(ctx context.Context, pattern string, userIDs []int32) error
+	ListRepoUsers   func(ctx context.Context, repoID api.RepoID) ([]int32, error)
+	ListPatterns    func(ctx context.Context) ([]*ExplicitRepoPatternPermissions, error)
+	GetForUser      func(ctx context.Context, userID int32) (*ExplicitUserRepoPermissions, error)
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+)
+
+func TestExplicitRepoPermissions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	var userIDs []int32
+	for _, username := range []string{"u1", "u2", "u3"} {
+		user, err := Users.Create(ctx, NewUser{Username: username})
+		if err != nil {
+			t.Fatal(err)
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+
+	if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: "gitolite.example.com/a", Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := Repos.GetByName(actor.WithActor(ctx, &actor.Actor{Internal: true}), "gitolite.example.com/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Setting the users of a repository replaces the previous users.
+	if err := ExplicitRepoPermissions.SetRepoUsers(ctx, repo.ID, []int32{userIDs[0], userIDs[1], userIDs[1]}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExplicitRepoPermissions.SetRepoUsers(ctx, repo.ID, []int32{userIDs[1], userIDs[2]}); err != nil {
+		t.Fatal(err)
+	}
+	repoUsers, err := ExplicitRepoPermissions.ListRepoUsers(ctx, repo.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int32{userIDs[1], userIDs[2]}; !reflect.DeepEqual(repoUsers, want) {
+		t.Errorf("got repository users %v, want %v", repoUsers, want)
+	}
+
+	if err := ExplicitRepoPermissions.SetPatternUsers(ctx, "(", userIDs); err == nil {
+		t.Error("got nil error for invalid pattern, want error")
+	}
+	if err := ExplicitRepoPermissions.SetPatternUsers(ctx, "^gitolite\\.example\\.com/", []int32{userIDs[0]}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExplicitRepoPermissions.SetPatternUsers(ctx, "^other/", []int32{userIDs[0]}); err != nil {
+		t.Fatal(err)
+	}
+	// An empty set of users removes the pattern.
+	if err := ExplicitRepoPermissions.SetPatternUsers(ctx, "^other/", nil); err != nil {
+		t.Fatal(err)
+	}
+	patterns, err := ExplicitRepoPermissions.ListPatterns(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPatterns := []*ExplicitRepoPatternPermissions{{Pattern: "^gitolite\\.example\\.com/", UserIDs: []int32{userIDs[0]}}}
+	if !reflect.DeepEqual(patterns, wantPatterns) {
+		t.Errorf("got patterns %+v, want %+v", patterns, wantPatterns)
+	}
+
+	for i, want := range []*ExplicitUserRepoPermissions{
+		{Patterns: []string{"^gitolite\\.example\\.com/"}},
+		{RepoIDs: []api.RepoID{repo.ID}},
+		{RepoIDs: []api.RepoID{repo.ID}},
+	} {
+		got, err := ExplicitRepoPermissions.GetForUser(ctx, userIDs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("user %d: got %+v, want %+v", userIDs[i], got, want)
+		}
+	}
+}
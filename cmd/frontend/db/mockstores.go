@@ -22,7 +22,31 @@ type MockStores struct {
 
 	ExternalAccounts MockExternalAccounts
 
+	UserRepoPermissions MockUserRepoPermissions
+
+	ExplicitRepoPermissions MockExplicitRepoPermissions
+
 	OrgInvitations MockOrgInvitations
 
 	ExternalServices MockExternalServices
+
+	RepoUpdateAttempts MockRepoUpdateAttempts
+
+	AuditLog MockAuditLog
+
+	UserMFA MockUserMFA
+
+	UserSessions MockUserSessions
+
+	SavedSearchWebhooks MockSavedSearchWebhooks
+
+	SavedSearchSubscriptions MockSavedSearchSubscriptions
+
+	SavedSearchRuns MockSavedSearchRuns
+
+	Notifications MockNotifications
+
+	UserNotificationSettings MockUserNotificationSettings
+
+	DiscussionCommentReactions MockDiscussionCommentReactions
 }
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// Notification kinds.
+const (
+	NotificationDiscussionThread  = "discussion_thread"  // a thread was created that the user is subscribed to
+	NotificationDiscussionComment = "discussion_comment" // a comment was added to a thread that the user is subscribed to
+	NotificationDiscussionMention = "discussion_mention" // the user was @mentioned in a thread title or comment
+)
+
+// Notification describes an in-app notification of an event for a user.
+//
+// The title, body, and URL are rendered when the notification is created, so that any subsystem
+// (not just discussions) may create notifications without the notification list needing to know
+// how to render them.
+type Notification struct {
+	ID          int64
+	UserID      int32  // the recipient
+	Kind        string // the kind of event (e.g., NotificationDiscussionComment)
+	ActorUserID *int32 // the user who caused the event, if any
+	ThreadID    *int64 // the discussion thread the event occurred in, if any
+	CommentID   *int64 // the discussion comment the event refers to, if any
+	Title       string // a short plain-text summary of the event
+	Body        string // an optional plain-text excerpt (e.g., of a comment's contents)
+	URL         string // the absolute URL to the subject of the event
+	CreatedAt   time.Time
+	ReadAt      *time.Time
+}
+
+// NotificationsListOptions contains options for listing notifications.
+type NotificationsListOptions struct {
+	UserID     int32 // list the notifications of this user (required)
+	UnreadOnly bool  // only list notifications that have not been read
+	*LimitOffset
+}
+
+func (o NotificationsListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("user_id=%d", o.UserID)}
+	if o.UnreadOnly {
+		conds = append(conds, sqlf.Sprintf("read_at IS NULL"))
+	}
+	return conds
+}
+
+// notifications provides access to the `notifications` table.
+//
+// For a detailed overview of the schema, see schema.md.
+type notifications struct{}
+
+// Create creates a notification. The notification's ID and CreatedAt fields are set to the values
+// assigned by the database.
+func (*notifications) Create(ctx context.Context, n *Notification) error {
+	if Mocks.Notifications.Create != nil {
+		return Mocks.Notifications.Create(ctx, n)
+	}
+
+	return dbconn.Global.QueryRowContext(ctx, `
+INSERT INTO notifications(user_id, kind, actor_user_id, thread_id, comment_id, title, body, url)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at`,
+		n.UserID, n.Kind, n.ActorUserID, n.ThreadID, n.CommentID, n.Title, n.Body, n.URL,
+	).Scan(&n.ID, &n.CreatedAt)
+}
+
+// List lists the user's notifications, newest first.
+func (*notifications) List(ctx context.Context, opt NotificationsListOptions) ([]*Notification, error) {
+	if Mocks.Notifications.List != nil {
+		return Mocks.Notifications.List(ctx, opt)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT id, user_id, kind, actor_user_id, thread_id, comment_id, title, body, url, created_at, read_at
+FROM notifications WHERE %s ORDER BY id DESC %s`,
+		sqlf.Join(opt.sqlConditions(), "AND"), opt.LimitOffset.SQL(),
+	)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []*Notification
+	for rows.Next() {
+		var n Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Kind, &n.ActorUserID, &n.ThreadID, &n.CommentID, &n.Title, &n.Body, &n.URL, &n.CreatedAt, &n.ReadAt); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, &n)
+	}
+	return notifications, rows.Err()
+}
+
+// Count counts the user's notifications (ignoring opt.LimitOffset).
+func (*notifications) Count(ctx context.Context, opt NotificationsListOptions) (int, error) {
+	if Mocks.Notifications.Count != nil {
+		return Mocks.Notifications.Count(ctx, opt)
+	}
+
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM notifications WHERE %s", sqlf.Join(opt.sqlConditions(), "AND"))
+	var count int
+	err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count)
+	return count, err
+}
+
+// MarkRead marks the user's notifications with the given IDs as read. If ids is nil, all of the
+// user's notifications are marked as read. IDs of notifications that do not belong to the user are
+// ignored. It returns the number of notifications that were marked as read (excluding those that
+// were already read).
+func (*notifications) MarkRead(ctx context.Context, userID int32, ids []int64) (int, error) {
+	if Mocks.Notifications.MarkRead != nil {
+		return Mocks.Notifications.MarkRead(ctx, userID, ids)
+	}
+
+	conds := []*sqlf.Query{sqlf.Sprintf("user_id=%d", userID), sqlf.Sprintf("read_at IS NULL")}
+	if ids != nil {
+		if len(ids) == 0 {
+			return 0, nil
+		}
+		items := make([]*sqlf.Query, len(ids))
+		for i, id := range ids {
+			items[i] = sqlf.Sprintf("%d", id)
+		}
+		conds = append(conds, sqlf.Sprintf("id IN (%s)", sqlf.Join(items, ",")))
+	}
+	q := sqlf.Sprintf("UPDATE notifications SET read_at=now() WHERE %s", sqlf.Join(conds, "AND"))
+	res, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return 0, err
+	}
+	nrows, err := res.RowsAffected()
+	return int(nrows), err
+}
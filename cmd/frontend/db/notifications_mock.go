@@ -0,0 +1,10 @@
+package db
+
+import "context"
+
+type MockNotifications struct {
+	Create   func(ctx context.Context, n *Notification) error
+	List     func(ctx context.Context, opt NotificationsListOptions) ([]*Notification, error)
+	Count    func(ctx context.Context, opt NotificationsListOptions) (int, error)
+	MarkRead func(ctx context.Context, userID int32, ids []int64) (int, error)
+}
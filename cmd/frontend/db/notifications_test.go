@@ -0,0 +1,75 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+)
+
+func TestNotifications(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+	user1, err := Users.Create(ctx, NewUser{Username: "u1", Email: "u1@example.com", EmailVerificationCode: "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user2, err := Users.Create(ctx, NewUser{Username: "u2", Email: "u2@example.com", EmailVerificationCode: "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []int64
+	for _, title := range []string{"a", "b", "c"} {
+		n := &Notification{UserID: user1.ID, Kind: NotificationDiscussionMention, ActorUserID: &user2.ID, Title: title, URL: "https://example.com/" + title}
+		if err := Notifications.Create(ctx, n); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, n.ID)
+	}
+	other := &Notification{UserID: user2.ID, Kind: NotificationDiscussionComment, Title: "d", URL: "https://example.com/d"}
+	if err := Notifications.Create(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+
+	notifications, err := Notifications.List(ctx, NotificationsListOptions{UserID: user1.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notifications) != 3 || notifications[0].Title != "c" || notifications[2].Title != "a" {
+		t.Fatalf("got notifications %+v, want c, b, a", notifications)
+	}
+	if n := notifications[0]; n.ActorUserID == nil || *n.ActorUserID != user2.ID || n.ReadAt != nil {
+		t.Errorf("got notification %+v, want unread notification from user %d", n, user2.ID)
+	}
+
+	// Notifications of other users are not marked as read.
+	if marked, err := Notifications.MarkRead(ctx, user1.ID, []int64{ids[0], other.ID}); err != nil {
+		t.Fatal(err)
+	} else if marked != 1 {
+		t.Errorf("got %d marked as read, want 1", marked)
+	}
+	unread := NotificationsListOptions{UserID: user1.ID, UnreadOnly: true}
+	if count, err := Notifications.Count(ctx, unread); err != nil {
+		t.Fatal(err)
+	} else if count != 2 {
+		t.Errorf("got %d unread, want 2", count)
+	}
+
+	if marked, err := Notifications.MarkRead(ctx, user1.ID, nil); err != nil {
+		t.Fatal(err)
+	} else if marked != 2 {
+		t.Errorf("got %d marked as read, want 2", marked)
+	}
+	if notifications, err := Notifications.List(ctx, unread); err != nil {
+		t.Fatal(err)
+	} else if len(notifications) != 0 {
+		t.Errorf("got unread notifications %+v, want none", notifications)
+	}
+	if count, err := Notifications.Count(ctx, NotificationsListOptions{UserID: user2.ID, UnreadOnly: true}); err != nil {
+		t.Fatal(err)
+	} else if count != 1 {
+		t.Errorf("got %d unread for other user, want 1", count)
+	}
+}
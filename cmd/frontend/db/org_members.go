@@ -17,6 +17,9 @@ import (
 type orgMembers struct{}
 
 func (*orgMembers) Create(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+	if Mocks.OrgMembers.Create != nil {
+		return Mocks.OrgMembers.Create(ctx, orgID, userID)
+	}
 	m := types.OrgMembership{
 		OrgID:  orgID,
 		UserID: userID,
@@ -48,6 +51,9 @@ func (m *orgMembers) GetByOrgIDAndUserID(ctx context.Context, orgID, userID int3
 }
 
 func (*orgMembers) Remove(ctx context.Context, orgID, userID int32) error {
+	if Mocks.OrgMembers.Remove != nil {
+		return Mocks.OrgMembers.Remove(ctx, orgID, userID)
+	}
 	_, err := dbconn.Global.ExecContext(ctx, "DELETE FROM org_members WHERE (org_id=$1 AND user_id=$2)", orgID, userID)
 	return err
 }
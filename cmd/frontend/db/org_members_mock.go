@@ -8,7 +8,9 @@ import (
 )
 
 type MockOrgMembers struct {
+	Create              func(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error)
 	GetByOrgIDAndUserID func(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error)
+	Remove              func(ctx context.Context, orgID, userID int32) error
 }
 
 func (s *MockOrgMembers) MockGetByOrgIDAndUserID_Return(t *testing.T, returns *types.OrgMembership, returnsErr error) (called *bool) {
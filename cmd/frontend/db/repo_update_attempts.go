@@ -0,0 +1,165 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// MaxRepoUpdateAttempts is the number of clone and fetch attempts kept per
+// repository. The most recent successful attempt is kept in addition, so that
+// it is known when a repository last updated successfully even after a long
+// streak of failures.
+const MaxRepoUpdateAttempts = 20
+
+// repoUpdateAttempts provides access to the `repo_update_attempts` table.
+//
+// For a detailed overview of the schema, see schema.md.
+type repoUpdateAttempts struct{}
+
+// Record adds the attempt to the update history of the repository, removing
+// the oldest attempts so that at most MaxRepoUpdateAttempts are kept.
+func (*repoUpdateAttempts) Record(ctx context.Context, repoID api.RepoID, a *api.RepoUpdateAttempt) (err error) {
+	if Mocks.RepoUpdateAttempts.Record != nil {
+		return Mocks.RepoUpdateAttempts.Record(ctx, repoID, a)
+	}
+
+	tx, err := dbconn.Global.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			rollErr := tx.Rollback()
+			if rollErr != nil {
+				err = multierror.Append(err, rollErr)
+			}
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	var attemptErr *string
+	if a.Failed() {
+		attemptErr = &a.Error
+	}
+	if _, err := tx.ExecContext(ctx, `
+INSERT INTO repo_update_attempts(repo_id, kind, started_at, duration_ms, error, output, bytes_received)
+VALUES($1, $2, $3, $4, $5, $6, $7)`,
+		repoID, a.Kind, a.Started, int64(a.Duration/time.Millisecond), attemptErr, a.Output, a.BytesReceived,
+	); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+DELETE FROM repo_update_attempts WHERE repo_id=$1 AND id NOT IN (
+	(SELECT id FROM repo_update_attempts WHERE repo_id=$1 ORDER BY id DESC LIMIT $2)
+	UNION
+	(SELECT id FROM repo_update_attempts WHERE repo_id=$1 AND error IS NULL ORDER BY id DESC LIMIT 1)
+)`,
+		repoID, MaxRepoUpdateAttempts,
+	)
+	return err
+}
+
+// List returns the most recent clone and fetch attempts of the repository,
+// most recent first. At most limit attempts are returned.
+func (*repoUpdateAttempts) List(ctx context.Context, repoID api.RepoID, limit int) ([]*api.RepoUpdateAttempt, error) {
+	if Mocks.RepoUpdateAttempts.List != nil {
+		return Mocks.RepoUpdateAttempts.List(ctx, repoID, limit)
+	}
+
+	rows, err := dbconn.Global.QueryContext(ctx, `
+SELECT kind, started_at, duration_ms, error, output, bytes_received FROM repo_update_attempts
+WHERE repo_id=$1
+ORDER BY id DESC
+LIMIT $2`,
+		repoID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []*api.RepoUpdateAttempt
+	for rows.Next() {
+		var (
+			a          api.RepoUpdateAttempt
+			durationMS int64
+			attemptErr sql.NullString
+		)
+		if err := rows.Scan(&a.Kind, &a.Started, &durationMS, &attemptErr, &a.Output, &a.BytesReceived); err != nil {
+			return nil, err
+		}
+		a.Duration = time.Duration(durationMS) * time.Millisecond
+		a.Error = attemptErr.String
+		attempts = append(attempts, &a)
+	}
+	return attempts, rows.Err()
+}
+
+// ListFailing returns the repositories whose last minConsecutiveFailures (or
+// more) attempts all failed, ordered by the number of consecutive failures
+// (descending). At most limit repositories are returned.
+func (*repoUpdateAttempts) ListFailing(ctx context.Context, minConsecutiveFailures, limit int) ([]*types.FailingRepo, error) {
+	if Mocks.RepoUpdateAttempts.ListFailing != nil {
+		return Mocks.RepoUpdateAttempts.ListFailing(ctx, minConsecutiveFailures, limit)
+	}
+	if minConsecutiveFailures < 1 {
+		minConsecutiveFailures = 1
+	}
+
+	// Every attempt after the most recent successful attempt of a repository failed.
+	rows, err := dbconn.Global.QueryContext(ctx, `
+WITH last_succeeded AS (
+	SELECT repo_id, max(id) AS id FROM repo_update_attempts WHERE error IS NULL GROUP BY repo_id
+), failing AS (
+	SELECT a.repo_id, count(*) AS failures, max(a.id) AS last_id
+	FROM repo_update_attempts a
+	LEFT JOIN last_succeeded s ON s.repo_id=a.repo_id
+	WHERE a.id > COALESCE(s.id, 0)
+	GROUP BY a.repo_id
+	HAVING count(*) >= $1
+)
+SELECT f.repo_id, r.name, f.failures,
+	a.kind, a.started_at, a.duration_ms, a.error, a.output, a.bytes_received,
+	succeeded.started_at + succeeded.duration_ms * interval '1 millisecond'
+FROM failing f
+JOIN repo r ON r.id=f.repo_id
+JOIN repo_update_attempts a ON a.id=f.last_id
+LEFT JOIN last_succeeded s ON s.repo_id=f.repo_id
+LEFT JOIN repo_update_attempts succeeded ON succeeded.id=s.id
+ORDER BY f.failures DESC, r.name ASC
+LIMIT $2`,
+		minConsecutiveFailures, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var repos []*types.FailingRepo
+	for rows.Next() {
+		var (
+			r          types.FailingRepo
+			durationMS int64
+			attemptErr sql.NullString
+		)
+		if err := rows.Scan(
+			&r.RepoID, &r.RepoName, &r.ConsecutiveFailures,
+			&r.LastAttempt.Kind, &r.LastAttempt.Started, &durationMS, &attemptErr, &r.LastAttempt.Output, &r.LastAttempt.BytesReceived,
+			&r.LastSucceeded,
+		); err != nil {
+			return nil, err
+		}
+		r.LastAttempt.Duration = time.Duration(durationMS) * time.Millisecond
+		r.LastAttempt.Error = attemptErr.String
+		repos = append(repos, &r)
+	}
+	return repos, rows.Err()
+}
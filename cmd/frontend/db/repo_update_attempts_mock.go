@@ -0,0 +1,14 @@
+package db
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+type MockRepoUpdateAttempts struct {
+	Record      func(ctx context.Context, repoID api.RepoID, a *api.RepoUpdateAttempt) error
+	List        func(ctx context.Context, repoID api.RepoID, limit int) ([]*api.RepoUpdateAttempt, error)
+	ListFailing func(ctx context.Context, minConsecutiveFailures, limit int) ([]*types.FailingRepo, error)
+}
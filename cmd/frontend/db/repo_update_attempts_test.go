@@ -0,0 +1,102 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+)
+
+func TestRepoUpdateAttempts(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	repoIDs := map[api.RepoName]api.RepoID{}
+	for _, name := range []api.RepoName{"github.com/foo/ok", "github.com/foo/flaky", "github.com/foo/broken"} {
+		if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: name, Enabled: true}); err != nil {
+			t.Fatal(err)
+		}
+		repo, err := Repos.GetByName(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		repoIDs[name] = repo.ID
+	}
+
+	start := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	attempt := func(i int, failed bool) *api.RepoUpdateAttempt {
+		a := &api.RepoUpdateAttempt{Kind: "fetch", Started: start.Add(time.Duration(i) * time.Hour), Duration: time.Minute, Output: "done\n"}
+		if failed {
+			a.Error = "exit status 128"
+		}
+		return a
+	}
+	record := func(repo api.RepoName, a *api.RepoUpdateAttempt) {
+		t.Helper()
+		if err := RepoUpdateAttempts.Record(ctx, repoIDs[repo], a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	record("github.com/foo/ok", attempt(0, true))
+	record("github.com/foo/ok", attempt(1, false))
+	record("github.com/foo/flaky", attempt(0, false))
+	record("github.com/foo/flaky", attempt(1, true))
+	record("github.com/foo/broken", attempt(0, false))
+	for i := 1; i <= MaxRepoUpdateAttempts+5; i++ {
+		record("github.com/foo/broken", attempt(i, true))
+	}
+
+	attempts, err := RepoUpdateAttempts.List(ctx, repoIDs["github.com/foo/ok"], 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range attempts {
+		a.Started = a.Started.UTC()
+	}
+	if want := []*api.RepoUpdateAttempt{attempt(1, false), attempt(0, true)}; !reflect.DeepEqual(attempts, want) {
+		t.Errorf("got history %+v, want %+v", attempts, want)
+	}
+
+	attempts, err = RepoUpdateAttempts.List(ctx, repoIDs["github.com/foo/broken"], 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The most recent successful attempt is kept in addition to the most recent attempts.
+	if len(attempts) != MaxRepoUpdateAttempts+1 || !attempts[0].Started.Equal(attempt(MaxRepoUpdateAttempts+5, true).Started) || attempts[len(attempts)-1].Failed() {
+		t.Errorf("expected the %d most recent attempts and the last successful attempt, got %+v", MaxRepoUpdateAttempts, attempts)
+	}
+
+	failing, err := RepoUpdateAttempts.ListFailing(ctx, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []api.RepoName
+	for _, r := range failing {
+		names = append(names, r.RepoName)
+	}
+	if want := []api.RepoName{"github.com/foo/broken", "github.com/foo/flaky"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("got failing repos %v, want %v", names, want)
+	}
+	if got := failing[0]; got.RepoID != repoIDs["github.com/foo/broken"] || got.ConsecutiveFailures != MaxRepoUpdateAttempts || !got.LastAttempt.Failed() {
+		t.Errorf("unexpected failing repo %+v", got)
+	}
+	if got, want := failing[1].LastSucceeded, start.Add(time.Minute); got == nil || !got.Equal(want) {
+		t.Errorf("got last succeeded %v, want %v", got, want)
+	}
+
+	if got, err := RepoUpdateAttempts.ListFailing(ctx, 3, 10); err != nil {
+		t.Fatal(err)
+	} else if len(got) != 1 || got[0].RepoName != "github.com/foo/broken" {
+		t.Errorf("got %+v, want only github.com/foo/broken", got)
+	}
+	if got, err := RepoUpdateAttempts.ListFailing(ctx, 1, 1); err != nil {
+		t.Fatal(err)
+	} else if len(got) != 1 {
+		t.Errorf("got %d failing repos, want 1 (limit)", len(got))
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db/query"
@@ -122,6 +123,7 @@ SELECT %s
 FROM repo
 WHERE deleted_at IS NULL
 AND enabled = true
+AND %%s
 AND %%s`
 
 var getBySQLColumns = []string{
@@ -145,8 +147,18 @@ func (s *repos) getReposBySQL(ctx context.Context, minimal bool, querySuffix *sq
 		columns = columns[:5]
 	}
 
+	// 🚨 SECURITY: This enforces repository permissions in SQL if possible, see below otherwise.
+	authzCond, authzInSQL, err := authzQueryConds(ctx, authz.Read)
+	if err != nil {
+		return nil, err
+	}
+	if !authzInSQL {
+		authzCond = sqlf.Sprintf("TRUE")
+	}
+
 	q := sqlf.Sprintf(
 		fmt.Sprintf(getRepoByQueryFmtstr, strings.Join(columns, ",")),
+		authzCond,
 		querySuffix,
 	)
 
@@ -173,6 +185,10 @@ func (s *repos) getReposBySQL(ctx context.Context, minimal bool, querySuffix *sq
 		return nil, err
 	}
 
+	if authzInSQL {
+		return repos, nil
+	}
+
 	// 🚨 SECURITY: This enforces repository permissions
 	return authzFilter(ctx, repos, authz.Read)
 }
@@ -238,6 +254,30 @@ type ReposListOptions struct {
 	// OnlyArchived excludes non-archived repositories from the list.
 	OnlyArchived bool
 
+	// NoPrivate excludes repositories that are private on their code host from the list.
+	NoPrivate bool
+
+	// OnlyPrivate excludes repositories that are public on their code host from the list.
+	OnlyPrivate bool
+
+	// Topics excludes repositories that are not labeled with all of the given topics on their
+	// code host from the list.
+	Topics []string
+
+	// ExcludeTopics excludes repositories that are labeled with any of the given topics on their
+	// code host from the list.
+	ExcludeTopics []string
+
+	// MinStars, if non-nil, excludes repositories with fewer stars than it from the list.
+	MinStars *int
+
+	// MaxStars, if non-nil, excludes repositories with more stars than it from the list.
+	MaxStars *int
+
+	// ServiceID, if set, excludes repositories that are not from the code host with the given
+	// external service ID (such as "https://github.com/") from the list.
+	ServiceID string
+
 	// OnlyRepoIDs fetches only the RepoIDs fields in each Repo.
 	OnlyRepoIDs bool
 
@@ -434,6 +474,28 @@ func (*repos) listSQL(opt ReposListOptions) (conds []*sqlf.Query, err error) {
 	if opt.OnlyArchived {
 		conds = append(conds, sqlf.Sprintf("archived"))
 	}
+	if opt.NoPrivate {
+		conds = append(conds, sqlf.Sprintf("NOT private"))
+	}
+	if opt.OnlyPrivate {
+		conds = append(conds, sqlf.Sprintf("private"))
+	}
+	if len(opt.Topics) > 0 {
+		conds = append(conds, sqlf.Sprintf("topics @> %s", pq.Array(opt.Topics)))
+	}
+	if len(opt.ExcludeTopics) > 0 {
+		conds = append(conds, sqlf.Sprintf("NOT (topics && %s)", pq.Array(opt.ExcludeTopics)))
+	}
+	if opt.MinStars != nil {
+		conds = append(conds, sqlf.Sprintf("stars >= %d", *opt.MinStars))
+	}
+	if opt.MaxStars != nil {
+		conds = append(conds, sqlf.Sprintf("stars <= %d", *opt.MaxStars))
+	}
+
+	if opt.ServiceID != "" {
+		conds = append(conds, sqlf.Sprintf("external_service_id = %s", opt.ServiceID))
+	}
 
 	if opt.Index != nil {
 		// We don't currently have an index column, but when we want the
@@ -7,11 +7,13 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/lib/pq"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db/query"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/actor"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
 )
 
@@ -158,6 +160,61 @@ func TestRepos_List_fork(t *testing.T) {
 	}
 }
 
+func TestRepos_List_metadata(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	mockAuthzFilter = func(ctx context.Context, repos []*types.Repo, p authz.Perms) ([]*types.Repo, error) {
+		return repos, nil
+	}
+	defer func() { mockAuthzFilter = nil }()
+	ctx := dbtesting.TestContext(t)
+	ctx = actor.WithActor(ctx, &actor.Actor{})
+
+	popular := mustCreate(ctx, t, &types.Repo{Name: "a/popular"})
+	internal := mustCreate(ctx, t, &types.Repo{Name: "b/internal"})
+	for _, r := range []struct {
+		repo    *types.Repo
+		private bool
+		stars   int
+		topics  []string
+	}{
+		{popular[0], false, 1000, []string{"payments", "api"}},
+		{internal[0], true, 3, []string{"payments"}},
+	} {
+		if _, err := dbconn.Global.ExecContext(ctx, "UPDATE repo SET private=$1, stars=$2, topics=$3 WHERE id=$4", r.private, r.stars, pq.Array(r.topics), r.repo.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	intPtr := func(i int) *int { return &i }
+	tests := []struct {
+		name string
+		opt  ReposListOptions
+		want []*types.Repo
+	}{
+		{"OnlyPrivate", ReposListOptions{OnlyPrivate: true}, internal},
+		{"NoPrivate", ReposListOptions{NoPrivate: true}, popular},
+		{"Topics", ReposListOptions{Topics: []string{"payments"}}, append(append([]*types.Repo(nil), popular...), internal...)},
+		{"Topics all", ReposListOptions{Topics: []string{"payments", "api"}}, popular},
+		{"ExcludeTopics", ReposListOptions{ExcludeTopics: []string{"api"}}, internal},
+		{"MinStars", ReposListOptions{MinStars: intPtr(101)}, popular},
+		{"MaxStars", ReposListOptions{MaxStars: intPtr(100)}, internal},
+		{"MinStars and MaxStars", ReposListOptions{MinStars: intPtr(4), MaxStars: intPtr(999)}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.opt.Enabled = true
+			repos, err := Repos.List(ctx, test.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertJSONEqual(t, test.want, repos)
+		})
+	}
+}
+
 func TestRepos_List_pagination(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -5,6 +5,7 @@ import (
 
 	"context"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 )
@@ -16,6 +17,7 @@ type MockRepos struct {
 	Delete    func(ctx context.Context, repo api.RepoID) error
 	Count     func(ctx context.Context, opt ReposListOptions) (int, error)
 	Upsert    func(api.InsertRepoOp) error
+	HasPerms  func(ctx context.Context, repo *types.Repo, p authz.Perms) (bool, error)
 }
 
 func (s *MockRepos) MockGet(t *testing.T, wantRepo api.RepoID) (called *bool) {
@@ -5,11 +5,14 @@ import (
 	"sync"
 
 	"github.com/RoaringBitmap/roaring"
+	"github.com/keegancsmith/sqlf"
 	otlog "github.com/opentracing/opentracing-go/log"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+	"github.com/sourcegraph/sourcegraph/pkg/ratelimit"
 	"github.com/sourcegraph/sourcegraph/pkg/trace"
 	log15 "gopkg.in/inconshreveable/log15.v2"
 )
@@ -91,6 +94,9 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perms) (filte
 		return repos, nil
 	}
 
+	// Requests made by authz providers to code hosts count against the permissions budget.
+	ctx = ratelimit.WithConsumer(ctx, ratelimit.ConsumerPermissions)
+
 	var accts []*extsvc.ExternalAccount
 	if len(authzProviders) > 0 && currentUser != nil {
 		accts, err = ExternalAccounts.List(ctx, ExternalAccountsListOptions{UserID: currentUser.ID})
@@ -117,6 +123,9 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perms) (filte
 	// repo, we use its permissions for that repo.
 	verified := roaring.NewBitmap()
 	for _, authzProvider := range authzProviders {
+		// Providers that identify users by their Sourcegraph user don't need an external account.
+		userProvider, isUserProvider := authzProvider.(authz.UserProvider)
+
 		// determine external account to use
 		var providerAcct *extsvc.ExternalAccount
 		for _, acct := range accts {
@@ -126,7 +135,7 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perms) (filte
 			}
 		}
 
-		if providerAcct == nil && currentUser != nil { // no existing external account for authz provider
+		if providerAcct == nil && currentUser != nil && !isUserProvider { // no existing external account for authz provider
 			if pr, err := authzProvider.FetchAccount(ctx, currentUser, accts); err == nil {
 				providerAcct = pr
 				if providerAcct != nil {
@@ -147,7 +156,12 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perms) (filte
 		}
 
 		// check the perms on our repos
-		perms, err := authzProvider.RepoPerms(ctx, providerAcct, *ours)
+		var perms []authz.RepoPerms
+		if isUserProvider {
+			perms, err = userProvider.UserRepoPerms(ctx, currentUser, *ours)
+		} else {
+			perms, err = authzProvider.RepoPerms(ctx, providerAcct, *ours)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -187,6 +201,95 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perms) (filte
 	return filtered, nil
 }
 
+// HasPerms reports whether the currently authenticated user has the given permissions on the
+// repository. It implements the same enforcement policy as authzFilter.
+func (s *repos) HasPerms(ctx context.Context, repo *types.Repo, p authz.Perms) (bool, error) {
+	if Mocks.Repos.HasPerms != nil {
+		return Mocks.Repos.HasPerms(ctx, repo, p)
+	}
+
+	// 🚨 SECURITY: This enforces repository permissions
+	filtered, err := authzFilter(ctx, []*types.Repo{repo}, p)
+	if err != nil {
+		return false, err
+	}
+	return len(filtered) == 1, nil
+}
+
+// authzQueryConds returns a SQL condition on the repo table that enforces repository permissions
+// with the permissions synced by the background permissions syncer. It implements the same
+// enforcement policy as authzFilter, but lets the database filter the repositories (so that
+// LIMIT and OFFSET apply to the permitted repositories only).
+//
+// It returns ok == false if the permissions can't be enforced in SQL, in which case the caller
+// must call authzFilter on the repositories instead. This is the case when background syncing is
+// disabled, for anonymous users, and for users whose permissions haven't been synced yet for all
+// authz providers.
+func authzQueryConds(ctx context.Context, p authz.Perms) (cond *sqlf.Query, ok bool, err error) {
+	if mockAuthzFilter != nil || !conf.PermissionsBackgroundSyncEnabled() {
+		return nil, false, nil
+	}
+
+	if isInternalActor(ctx) {
+		return sqlf.Sprintf("TRUE"), true, nil
+	}
+
+	if !actor.FromContext(ctx).IsAuthenticated() {
+		return nil, false, nil
+	}
+	currentUser, err := Users.GetByCurrentAuthUser(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if currentUser.SiteAdmin {
+		return sqlf.Sprintf("TRUE"), true, nil
+	}
+
+	authzAllowByDefault, authzProviders := authz.GetProviders()
+	if authzAllowByDefault && len(authzProviders) == 0 {
+		return sqlf.Sprintf("TRUE"), true, nil
+	}
+
+	synced, err := UserRepoPermissions.syncedServiceIDs(ctx, currentUser.ID, p)
+	if err != nil {
+		return nil, false, err
+	}
+
+	serviceIDs := make([]*sqlf.Query, 0, len(authzProviders))
+	for _, authzProvider := range authzProviders {
+		if !synced[authzProvider.ServiceID()] {
+			return nil, false, nil
+		}
+		serviceIDs = append(serviceIDs, sqlf.Sprintf("%s", authzProvider.ServiceID()))
+	}
+
+	var conds []*sqlf.Query
+	if len(serviceIDs) > 0 {
+		// Repos owned by an authz provider are accessible if that provider granted access.
+		conds = append(conds, sqlf.Sprintf(`(
+repo.external_service_id IN (%s)
+AND repo.id IN (
+	SELECT unnest(repo_ids) FROM user_repo_permissions
+	WHERE user_id = %s AND permission = %s AND service_id IN (%s)
+))`,
+			sqlf.Join(serviceIDs, ","), currentUser.ID, p.String(), sqlf.Join(serviceIDs, ","),
+		))
+	}
+	if authzAllowByDefault {
+		// 🚨 SECURITY: Defensively bar access to repos with no external repo spec (we don't know
+		// where they came from, so can't reliably enforce permissions).
+		if len(serviceIDs) > 0 {
+			conds = append(conds, sqlf.Sprintf("(repo.external_service_id <> '' AND repo.external_service_id NOT IN (%s))", sqlf.Join(serviceIDs, ",")))
+		} else {
+			conds = append(conds, sqlf.Sprintf("repo.external_service_id <> ''"))
+		}
+	}
+	if len(conds) == 0 {
+		return sqlf.Sprintf("FALSE"), true, nil
+	}
+	return sqlf.Sprintf("(%s)", sqlf.Join(conds, "OR")), true, nil
+}
+
 // isInternalActor returns true if the actor represents an internal agent (i.e., non-user-bound
 // request that originates from within Sourcegraph itself).
 //
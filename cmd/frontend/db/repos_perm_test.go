@@ -8,6 +8,7 @@ import (
 	"github.com/keegancsmith/sqlf"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
@@ -231,3 +232,87 @@ func Test_getBySQL_permissionsCheck(t *testing.T) {
 		}
 	}
 }
+
+func TestRepos_HasPerms(t *testing.T) {
+	defer func() { mockAuthzFilter = nil }()
+
+	repo := &types.Repo{ID: 1, Name: "r"}
+	for _, allowed := range []bool{true, false} {
+		mockAuthzFilter = func(ctx context.Context, repos []*types.Repo, p authz.Perms) ([]*types.Repo, error) {
+			if p != authz.Write {
+				t.Errorf("got perms %s, want %s", p, authz.Write)
+			}
+			if !allowed {
+				return nil, nil
+			}
+			return repos, nil
+		}
+
+		got, err := Repos.HasPerms(context.Background(), repo, authz.Write)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != allowed {
+			t.Errorf("got %v, want %v", got, allowed)
+		}
+	}
+}
+
+type fakeUserProvider struct {
+	fakeProvider
+	granted map[int32][]api.RepoID // user ID -> repositories
+}
+
+func (f fakeUserProvider) UserRepoPerms(ctx context.Context, user *types.User, repos []*types.Repo) ([]authz.RepoPerms, error) {
+	if user == nil {
+		return nil, nil
+	}
+	var perms []authz.RepoPerms
+	for _, repo := range repos {
+		for _, id := range f.granted[user.ID] {
+			if repo.ID == id {
+				perms = append(perms, authz.RepoPerms{Repo: repo, Perms: authz.Read})
+			}
+		}
+	}
+	return perms, nil
+}
+
+// 🚨 SECURITY: test necessary to ensure security
+func Test_authzFilter_userProvider(t *testing.T) {
+	defer func() { Mocks = MockStores{} }()
+
+	Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: 42}, nil
+	}
+	Mocks.ExternalAccounts.List = func(ExternalAccountsListOptions) ([]*extsvc.ExternalAccount, error) {
+		return nil, nil
+	}
+	Mocks.ExternalAccounts.AssociateUserAndSave = func(userID int32, spec extsvc.ExternalAccountSpec, data extsvc.ExternalAccountData) error {
+		t.Errorf("unexpected external account %+v saved for user %d", spec, userID)
+		return nil
+	}
+
+	provider := fakeUserProvider{
+		fakeProvider: fakeProvider{codeHost: &extsvc.CodeHost{ServiceType: "gitolite", ServiceID: "git@gitolite.example.com"}},
+		granted:      map[int32][]api.RepoID{42: {1}},
+	}
+	{
+		authzAllowByDefault, providers := authz.GetProviders()
+		defer authz.SetProviders(authzAllowByDefault, providers)
+	}
+	authz.SetProviders(false, []authz.Provider{provider})
+
+	repos := []*types.Repo{
+		{ID: 1, Name: "gitolite.example.com/a", ExternalRepo: api.ExternalRepoSpec{ServiceID: provider.ServiceID()}},
+		{ID: 2, Name: "gitolite.example.com/b", ExternalRepo: api.ExternalRepoSpec{ServiceID: provider.ServiceID()}},
+	}
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 42})
+	filtered, err := authzFilter(ctx, repos, authz.Read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered) != 1 || filtered[0].ID != 1 {
+		t.Errorf("got repos %+v, want only repo 1", filtered)
+	}
+}
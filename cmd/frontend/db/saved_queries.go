@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
 )
@@ -85,3 +86,43 @@ func (s *queryRunnerState) Delete(ctx context.Context, query string) error {
 	)
 	return err
 }
+
+// GetResultFingerprints gets the fingerprints of the search results that the query runner last
+// recorded for the saved search. nil is returned if no result fingerprints have been recorded
+// (e.g., because the saved search has never run).
+func (s *queryRunnerState) GetResultFingerprints(ctx context.Context, savedSearchID int32) ([]string, error) {
+	var fingerprints []string
+	err := dbconn.Global.QueryRowContext(
+		ctx,
+		"SELECT fingerprints FROM saved_search_result_fingerprints WHERE saved_search_id=$1",
+		savedSearchID,
+	).Scan(pq.Array(&fingerprints))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "QueryRow")
+	}
+	if fingerprints == nil {
+		fingerprints = []string{} // distinguish "recorded but empty" from "never recorded"
+	}
+	return fingerprints, nil
+}
+
+// SetResultFingerprints replaces the recorded fingerprints of the saved search's search results.
+func (s *queryRunnerState) SetResultFingerprints(ctx context.Context, savedSearchID int32, fingerprints []string) error {
+	if fingerprints == nil {
+		fingerprints = []string{}
+	}
+	_, err := dbconn.Global.ExecContext(
+		ctx,
+		`INSERT INTO saved_search_result_fingerprints(saved_search_id, fingerprints) VALUES($1, $2)
+ON CONFLICT (saved_search_id) DO UPDATE SET fingerprints=excluded.fingerprints, updated_at=now()`,
+		savedSearchID,
+		pq.Array(fingerprints),
+	)
+	if err != nil {
+		return errors.Wrap(err, "INSERT")
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+)
+
+func TestQueryRunnerState_ResultFingerprints(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	user, err := Users.Create(ctx, NewUser{Username: "u", Email: "u@example.com", EmailVerificationCode: "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss, err := SavedSearches.Create(ctx, &types.SavedSearch{Query: "q", Description: "d", Notify: true, UserID: &user.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fingerprints, err := QueryRunnerState.GetResultFingerprints(ctx, ss.ID); err != nil {
+		t.Fatal(err)
+	} else if fingerprints != nil {
+		t.Errorf("got fingerprints %v, want nil (never recorded)", fingerprints)
+	}
+
+	for _, want := range [][]string{{"a", "b"}, {"b", "c"}, {}} {
+		if err := QueryRunnerState.SetResultFingerprints(ctx, ss.ID, want); err != nil {
+			t.Fatal(err)
+		}
+		fingerprints, err := QueryRunnerState.GetResultFingerprints(ctx, ss.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(fingerprints, want) {
+			t.Errorf("got fingerprints %v, want %v", fingerprints, want)
+		}
+	}
+
+	// Deleting the saved search deletes its result fingerprints.
+	if err := SavedSearches.Delete(ctx, ss.ID); err != nil {
+		t.Fatal(err)
+	}
+	if fingerprints, err := QueryRunnerState.GetResultFingerprints(ctx, ss.ID); err != nil {
+		t.Fatal(err)
+	} else if fingerprints != nil {
+		t.Errorf("got fingerprints %v after deletion, want nil", fingerprints)
+	}
+}
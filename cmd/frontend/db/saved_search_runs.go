@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// SavedSearchRun describes an execution of a saved search by the query runner.
+type SavedSearchRun struct {
+	ID             int32
+	SavedSearchID  int32
+	StartedAt      time.Time
+	Duration       time.Duration
+	ResultCount    int32 // the number of matches in the search results
+	NewResultCount int32 // the number of matches that were not in the previous run's results
+	Error          *string
+}
+
+// maxSavedSearchRuns is the number of most recent runs of each saved search that are kept in the
+// run history.
+const maxSavedSearchRuns = 100
+
+// savedSearchRuns provides access to the `saved_search_runs` table.
+//
+// For a detailed overview of the schema, see schema.md.
+type savedSearchRuns struct{}
+
+// Create records a run of a saved search. Only the saved search's maxSavedSearchRuns most recent
+// runs are kept.
+func (*savedSearchRuns) Create(ctx context.Context, run *SavedSearchRun) error {
+	if Mocks.SavedSearchRuns.Create != nil {
+		return Mocks.SavedSearchRuns.Create(ctx, run)
+	}
+
+	if err := dbconn.Global.QueryRowContext(ctx, `
+INSERT INTO saved_search_runs(saved_search_id, started_at, duration_ms, result_count, new_result_count, error)
+VALUES($1, $2, $3, $4, $5, $6) RETURNING id`,
+		run.SavedSearchID, run.StartedAt, int64(run.Duration/time.Millisecond), run.ResultCount, run.NewResultCount, run.Error,
+	).Scan(&run.ID); err != nil {
+		return err
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx, `
+DELETE FROM saved_search_runs WHERE saved_search_id=$1 AND id NOT IN (
+	SELECT id FROM saved_search_runs WHERE saved_search_id=$1 ORDER BY started_at DESC, id DESC LIMIT $2
+)`,
+		run.SavedSearchID, maxSavedSearchRuns,
+	)
+	return err
+}
+
+// ListBySavedSearch lists the saved search's most recent runs (newest first) and returns the total
+// number of runs in its run history.
+func (*savedSearchRuns) ListBySavedSearch(ctx context.Context, savedSearchID int32, limit int) (runs []*SavedSearchRun, totalCount int, err error) {
+	if Mocks.SavedSearchRuns.ListBySavedSearch != nil {
+		return Mocks.SavedSearchRuns.ListBySavedSearch(ctx, savedSearchID, limit)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT id, saved_search_id, started_at, duration_ms, result_count, new_result_count, error
+FROM saved_search_runs WHERE saved_search_id=%d ORDER BY started_at DESC, id DESC LIMIT %d`,
+		savedSearchID, limit,
+	)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r SavedSearchRun
+		var durationMS int64
+		if err := rows.Scan(&r.ID, &r.SavedSearchID, &r.StartedAt, &durationMS, &r.ResultCount, &r.NewResultCount, &r.Error); err != nil {
+			return nil, 0, err
+		}
+		r.Duration = time.Duration(durationMS) * time.Millisecond
+		runs = append(runs, &r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := dbconn.Global.QueryRowContext(ctx, "SELECT COUNT(*) FROM saved_search_runs WHERE saved_search_id=$1", savedSearchID).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+	return runs, totalCount, nil
+}
@@ -0,0 +1,8 @@
+package db
+
+import "context"
+
+type MockSavedSearchRuns struct {
+	Create            func(ctx context.Context, run *SavedSearchRun) error
+	ListBySavedSearch func(ctx context.Context, savedSearchID int32, limit int) ([]*SavedSearchRun, int, error)
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+)
+
+func TestSavedSearchRuns(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+	ss := createTestSavedSearch(ctx, t)
+
+	start := time.Now().Add(-time.Hour)
+	errMsg := "search: timed out"
+	for i := 0; i < maxSavedSearchRuns+2; i++ {
+		run := &SavedSearchRun{
+			SavedSearchID:  ss.ID,
+			StartedAt:      start.Add(time.Duration(i) * time.Second),
+			Duration:       1500 * time.Millisecond,
+			ResultCount:    int32(i),
+			NewResultCount: 1,
+		}
+		if i == maxSavedSearchRuns+1 {
+			run.Error = &errMsg
+		}
+		if err := SavedSearchRuns.Create(ctx, run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runs, totalCount, err := SavedSearchRuns.ListBySavedSearch(ctx, ss.ID, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totalCount != maxSavedSearchRuns {
+		t.Errorf("got total count %d, want %d (older runs should be removed)", totalCount, maxSavedSearchRuns)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("got %d runs, want 2", len(runs))
+	}
+	if r := runs[0]; r.ResultCount != maxSavedSearchRuns+1 || r.Error == nil || *r.Error != errMsg || r.Duration != 1500*time.Millisecond {
+		t.Errorf("got newest run %+v", r)
+	}
+	if r := runs[1]; r.ResultCount != maxSavedSearchRuns || r.Error != nil {
+		t.Errorf("got second newest run %+v", r)
+	}
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// Saved search subscription channels.
+const (
+	SavedSearchSubscriptionEmail = "email" // notify the user by email
+	SavedSearchSubscriptionSlack = "slack" // post to the subscription's Slack webhook URL
+)
+
+// SavedSearchSubscription describes a user's subscription to notifications about a saved search's
+// new search results on a channel. Users subscribe in addition to the saved search's owner (who is
+// notified according to the saved search's notify_owner and notify_slack settings).
+type SavedSearchSubscription struct {
+	ID              int32
+	SavedSearchID   int32
+	UserID          int32
+	Channel         string  // the notification channel (e.g., SavedSearchSubscriptionEmail)
+	SlackWebhookURL *string // the Slack webhook URL (only for SavedSearchSubscriptionSlack)
+	CreatedAt       time.Time
+}
+
+// savedSearchSubscriptionNotFoundError occurs when a saved search subscription does not exist.
+type savedSearchSubscriptionNotFoundError struct {
+	savedSearchID, userID int32
+	channel               string
+}
+
+func (err savedSearchSubscriptionNotFoundError) Error() string {
+	return fmt.Sprintf("saved search subscription not found: saved search %d, user %d, channel %q", err.savedSearchID, err.userID, err.channel)
+}
+
+func (err savedSearchSubscriptionNotFoundError) NotFound() bool { return true }
+
+// savedSearchSubscriptions provides access to the `saved_search_subscriptions` table.
+//
+// For a detailed overview of the schema, see schema.md.
+type savedSearchSubscriptions struct{}
+
+// Subscribe subscribes the user to notifications about the saved search on the channel. If the
+// user is already subscribed on the channel, the subscription's Slack webhook URL is updated.
+//
+// 🚨 SECURITY: This method does NOT verify that the user may access the saved search. It is the
+// caller's responsibility to ensure the user has the proper permissions.
+func (*savedSearchSubscriptions) Subscribe(ctx context.Context, savedSearchID, userID int32, channel string, slackWebhookURL *string) (*SavedSearchSubscription, error) {
+	if Mocks.SavedSearchSubscriptions.Subscribe != nil {
+		return Mocks.SavedSearchSubscriptions.Subscribe(ctx, savedSearchID, userID, channel, slackWebhookURL)
+	}
+
+	s := &SavedSearchSubscription{SavedSearchID: savedSearchID, UserID: userID, Channel: channel, SlackWebhookURL: slackWebhookURL}
+	if err := dbconn.Global.QueryRowContext(ctx, `
+INSERT INTO saved_search_subscriptions(saved_search_id, user_id, channel, slack_webhook_url) VALUES($1, $2, $3, $4)
+ON CONFLICT (saved_search_id, user_id, channel) DO UPDATE SET slack_webhook_url=EXCLUDED.slack_webhook_url
+RETURNING id, created_at`,
+		savedSearchID, userID, channel, slackWebhookURL,
+	).Scan(&s.ID, &s.CreatedAt); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Unsubscribe removes the user's subscription to the saved search on the channel. If the
+// subscription does not exist, it returns an error for which errcode.IsNotFound returns true.
+func (*savedSearchSubscriptions) Unsubscribe(ctx context.Context, savedSearchID, userID int32, channel string) error {
+	if Mocks.SavedSearchSubscriptions.Unsubscribe != nil {
+		return Mocks.SavedSearchSubscriptions.Unsubscribe(ctx, savedSearchID, userID, channel)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx,
+		"DELETE FROM saved_search_subscriptions WHERE saved_search_id=$1 AND user_id=$2 AND channel=$3",
+		savedSearchID, userID, channel,
+	)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return savedSearchSubscriptionNotFoundError{savedSearchID: savedSearchID, userID: userID, channel: channel}
+	}
+	return nil
+}
+
+// ListBySavedSearch lists the saved search's subscriptions, oldest first.
+func (*savedSearchSubscriptions) ListBySavedSearch(ctx context.Context, savedSearchID int32) ([]*SavedSearchSubscription, error) {
+	if Mocks.SavedSearchSubscriptions.ListBySavedSearch != nil {
+		return Mocks.SavedSearchSubscriptions.ListBySavedSearch(ctx, savedSearchID)
+	}
+
+	q := sqlf.Sprintf("SELECT id, saved_search_id, user_id, channel, slack_webhook_url, created_at FROM saved_search_subscriptions WHERE saved_search_id=%d ORDER BY id ASC", savedSearchID)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*SavedSearchSubscription
+	for rows.Next() {
+		var s SavedSearchSubscription
+		if err := rows.Scan(&s.ID, &s.SavedSearchID, &s.UserID, &s.Channel, &s.SlackWebhookURL, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, &s)
+	}
+	return subscriptions, rows.Err()
+}
@@ -0,0 +1,9 @@
+package db
+
+import "context"
+
+type MockSavedSearchSubscriptions struct {
+	Subscribe         func(ctx context.Context, savedSearchID, userID int32, channel string, slackWebhookURL *string) (*SavedSearchSubscription, error)
+	Unsubscribe       func(ctx context.Context, savedSearchID, userID int32, channel string) error
+	ListBySavedSearch func(ctx context.Context, savedSearchID int32) ([]*SavedSearchSubscription, error)
+}
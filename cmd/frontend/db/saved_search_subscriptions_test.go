@@ -0,0 +1,62 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+func TestSavedSearchSubscriptions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+	ss := createTestSavedSearch(ctx, t)
+	user, err := Users.Create(ctx, NewUser{Username: "u2", Email: "u2@example.com", EmailVerificationCode: "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := SavedSearchSubscriptions.Subscribe(ctx, ss.ID, user.ID, SavedSearchSubscriptionEmail, nil); err != nil {
+		t.Fatal(err)
+	}
+	slackWebhookURL := "https://hooks.slack.com/services/a"
+	if _, err := SavedSearchSubscriptions.Subscribe(ctx, ss.ID, user.ID, SavedSearchSubscriptionSlack, &slackWebhookURL); err != nil {
+		t.Fatal(err)
+	}
+	// Subscribing again on the same channel updates the Slack webhook URL.
+	slackWebhookURL2 := "https://hooks.slack.com/services/b"
+	if _, err := SavedSearchSubscriptions.Subscribe(ctx, ss.ID, user.ID, SavedSearchSubscriptionSlack, &slackWebhookURL2); err != nil {
+		t.Fatal(err)
+	}
+
+	subscriptions, err := SavedSearchSubscriptions.ListBySavedSearch(ctx, ss.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subscriptions) != 2 {
+		t.Fatalf("got %d subscriptions, want 2", len(subscriptions))
+	}
+	if s := subscriptions[1]; s.Channel != SavedSearchSubscriptionSlack || s.SlackWebhookURL == nil || *s.SlackWebhookURL != slackWebhookURL2 {
+		t.Errorf("got subscription %+v, want Slack subscription with URL %q", s, slackWebhookURL2)
+	}
+
+	if savedSearch, err := SavedSearches.GetByID(ctx, ss.ID); err != nil {
+		t.Fatal(err)
+	} else if !savedSearch.Config.HasSubscriptions {
+		t.Error("got HasSubscriptions false, want true")
+	}
+
+	if err := SavedSearchSubscriptions.Unsubscribe(ctx, ss.ID, user.ID, SavedSearchSubscriptionEmail); err != nil {
+		t.Fatal(err)
+	}
+	if err := SavedSearchSubscriptions.Unsubscribe(ctx, ss.ID, user.ID, SavedSearchSubscriptionEmail); !errcode.IsNotFound(err) {
+		t.Errorf("got error %v, want not found", err)
+	}
+	if subscriptions, err := SavedSearchSubscriptions.ListBySavedSearch(ctx, ss.ID); err != nil {
+		t.Fatal(err)
+	} else if len(subscriptions) != 1 || subscriptions[0].Channel != SavedSearchSubscriptionSlack {
+		t.Errorf("got subscriptions %+v, want only the Slack subscription", subscriptions)
+	}
+}
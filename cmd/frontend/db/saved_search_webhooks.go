@@ -0,0 +1,286 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// SavedSearchWebhook describes a webhook that receives notifications about a saved search's new
+// search results.
+type SavedSearchWebhook struct {
+	ID            int32
+	SavedSearchID int32
+	URL           string
+	Secret        string // the secret used to sign the webhook's deliveries (HMAC-SHA256)
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Saved search webhook delivery states.
+const (
+	SavedSearchWebhookDeliveryPending   = "pending"   // not yet delivered (and will be retried)
+	SavedSearchWebhookDeliverySucceeded = "succeeded" // delivered successfully
+	SavedSearchWebhookDeliveryFailed    = "failed"    // failed to deliver (and will not be retried)
+)
+
+// SavedSearchWebhookDelivery describes a notification that is (or was) delivered to a saved search
+// webhook.
+type SavedSearchWebhookDelivery struct {
+	ID                 int32
+	WebhookID          int32
+	Event              string // the type of the notification (e.g., "saved_search.new_results")
+	Payload            string // the JSON request body
+	State              string // the delivery state (e.g., SavedSearchWebhookDeliveryPending)
+	Attempts           int32  // the number of delivery attempts so far
+	ResponseStatusCode *int32 // the HTTP status code of the last attempt's response (if any)
+	Error              *string
+	CreatedAt          time.Time
+	LastAttemptAt      *time.Time
+	NextAttemptAt      *time.Time // when the next attempt is due (only for pending deliveries)
+}
+
+// SavedSearchWebhookDeliveryAttempt describes the outcome of an attempt to deliver a saved search
+// webhook notification.
+type SavedSearchWebhookDeliveryAttempt struct {
+	Success            bool
+	ResponseStatusCode *int32
+	Error              *string
+	NextAttemptAt      *time.Time // when to retry (nil if the delivery succeeded or should not be retried)
+}
+
+// savedSearchWebhookDeliveryRetention is how long completed (succeeded or failed) deliveries are
+// kept for the delivery log.
+const savedSearchWebhookDeliveryRetention = 30 * 24 * time.Hour
+
+// savedSearchWebhookNotFoundError occurs when a saved search webhook does not exist.
+type savedSearchWebhookNotFoundError struct {
+	id int32
+}
+
+func (err savedSearchWebhookNotFoundError) Error() string {
+	return fmt.Sprintf("saved search webhook not found: %d", err.id)
+}
+
+func (err savedSearchWebhookNotFoundError) NotFound() bool { return true }
+
+// savedSearchWebhooks provides access to the `saved_search_webhooks` and
+// `saved_search_webhook_deliveries` tables.
+//
+// For a detailed overview of the schema, see schema.md.
+type savedSearchWebhooks struct{}
+
+// Create creates a webhook for the saved search.
+func (*savedSearchWebhooks) Create(ctx context.Context, savedSearchID int32, url, secret string) (*SavedSearchWebhook, error) {
+	if Mocks.SavedSearchWebhooks.Create != nil {
+		return Mocks.SavedSearchWebhooks.Create(ctx, savedSearchID, url, secret)
+	}
+
+	w := &SavedSearchWebhook{SavedSearchID: savedSearchID, URL: url, Secret: secret}
+	if err := dbconn.Global.QueryRowContext(ctx,
+		"INSERT INTO saved_search_webhooks(saved_search_id, url, secret) VALUES($1, $2, $3) RETURNING id, created_at, updated_at",
+		savedSearchID, url, secret,
+	).Scan(&w.ID, &w.CreatedAt, &w.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// GetByID returns the webhook with the given ID. If it does not exist, it returns an error for
+// which errcode.IsNotFound returns true.
+func (*savedSearchWebhooks) GetByID(ctx context.Context, id int32) (*SavedSearchWebhook, error) {
+	if Mocks.SavedSearchWebhooks.GetByID != nil {
+		return Mocks.SavedSearchWebhooks.GetByID(ctx, id)
+	}
+
+	webhooks, err := getSavedSearchWebhooks(ctx, sqlf.Sprintf("id=%d", id))
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooks) == 0 {
+		return nil, savedSearchWebhookNotFoundError{id: id}
+	}
+	return webhooks[0], nil
+}
+
+// ListBySavedSearch lists the saved search's webhooks, oldest first.
+func (*savedSearchWebhooks) ListBySavedSearch(ctx context.Context, savedSearchID int32) ([]*SavedSearchWebhook, error) {
+	if Mocks.SavedSearchWebhooks.ListBySavedSearch != nil {
+		return Mocks.SavedSearchWebhooks.ListBySavedSearch(ctx, savedSearchID)
+	}
+	return getSavedSearchWebhooks(ctx, sqlf.Sprintf("saved_search_id=%d", savedSearchID))
+}
+
+func getSavedSearchWebhooks(ctx context.Context, cond *sqlf.Query) ([]*SavedSearchWebhook, error) {
+	q := sqlf.Sprintf("SELECT id, saved_search_id, url, secret, created_at, updated_at FROM saved_search_webhooks WHERE %s ORDER BY id ASC", cond)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []*SavedSearchWebhook
+	for rows.Next() {
+		var w SavedSearchWebhook
+		if err := rows.Scan(&w.ID, &w.SavedSearchID, &w.URL, &w.Secret, &w.CreatedAt, &w.UpdatedAt); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, &w)
+	}
+	return webhooks, rows.Err()
+}
+
+// Update updates the webhook's URL and (if secret is non-nil) secret.
+func (*savedSearchWebhooks) Update(ctx context.Context, id int32, url string, secret *string) (*SavedSearchWebhook, error) {
+	if Mocks.SavedSearchWebhooks.Update != nil {
+		return Mocks.SavedSearchWebhooks.Update(ctx, id, url, secret)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx,
+		"UPDATE saved_search_webhooks SET url=$2, secret=COALESCE($3, secret), updated_at=now() WHERE id=$1",
+		id, url, secret,
+	)
+	if err != nil {
+		return nil, err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if nrows == 0 {
+		return nil, savedSearchWebhookNotFoundError{id: id}
+	}
+	return SavedSearchWebhooks.GetByID(ctx, id)
+}
+
+// Delete deletes the webhook (and its delivery log).
+func (*savedSearchWebhooks) Delete(ctx context.Context, id int32) error {
+	if Mocks.SavedSearchWebhooks.Delete != nil {
+		return Mocks.SavedSearchWebhooks.Delete(ctx, id)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx, "DELETE FROM saved_search_webhooks WHERE id=$1", id)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return savedSearchWebhookNotFoundError{id: id}
+	}
+	return nil
+}
+
+// CreateDeliveries creates a pending delivery of the notification to each of the saved search's
+// webhooks and returns the number of deliveries created. The deliveries are due immediately.
+//
+// It also removes completed deliveries that are older than the delivery log's retention period.
+func (*savedSearchWebhooks) CreateDeliveries(ctx context.Context, savedSearchID int32, event, payload string) (int, error) {
+	if Mocks.SavedSearchWebhooks.CreateDeliveries != nil {
+		return Mocks.SavedSearchWebhooks.CreateDeliveries(ctx, savedSearchID, event, payload)
+	}
+
+	if _, err := dbconn.Global.ExecContext(ctx,
+		"DELETE FROM saved_search_webhook_deliveries WHERE state<>$1 AND created_at < $2",
+		SavedSearchWebhookDeliveryPending, time.Now().Add(-savedSearchWebhookDeliveryRetention),
+	); err != nil {
+		return 0, err
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx, `
+INSERT INTO saved_search_webhook_deliveries(webhook_id, event, payload, next_attempt_at)
+SELECT id, $2, $3, now() FROM saved_search_webhooks WHERE saved_search_id=$1`,
+		savedSearchID, event, payload,
+	)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// ListDueDeliveries lists the pending deliveries whose next attempt is due. The deliveries with
+// the earliest due time are listed first.
+func (*savedSearchWebhooks) ListDueDeliveries(ctx context.Context, limit int) ([]*SavedSearchWebhookDelivery, error) {
+	if Mocks.SavedSearchWebhooks.ListDueDeliveries != nil {
+		return Mocks.SavedSearchWebhooks.ListDueDeliveries(ctx, limit)
+	}
+	return getSavedSearchWebhookDeliveries(ctx, sqlf.Sprintf("state=%s AND next_attempt_at <= now() ORDER BY next_attempt_at ASC LIMIT %d", SavedSearchWebhookDeliveryPending, limit))
+}
+
+// ListDeliveries lists the webhook's most recent deliveries (newest first) and returns the total
+// number of deliveries in its delivery log.
+func (*savedSearchWebhooks) ListDeliveries(ctx context.Context, webhookID int32, limit int) (deliveries []*SavedSearchWebhookDelivery, totalCount int, err error) {
+	if Mocks.SavedSearchWebhooks.ListDeliveries != nil {
+		return Mocks.SavedSearchWebhooks.ListDeliveries(ctx, webhookID, limit)
+	}
+
+	deliveries, err = getSavedSearchWebhookDeliveries(ctx, sqlf.Sprintf("webhook_id=%d ORDER BY created_at DESC, id DESC LIMIT %d", webhookID, limit))
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := dbconn.Global.QueryRowContext(ctx, "SELECT COUNT(*) FROM saved_search_webhook_deliveries WHERE webhook_id=$1", webhookID).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+	return deliveries, totalCount, nil
+}
+
+func getSavedSearchWebhookDeliveries(ctx context.Context, condAndOrder *sqlf.Query) ([]*SavedSearchWebhookDelivery, error) {
+	q := sqlf.Sprintf(`
+SELECT id, webhook_id, event, payload, state, attempts, response_status_code, error, created_at, last_attempt_at, next_attempt_at
+FROM saved_search_webhook_deliveries WHERE %s`, condAndOrder)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*SavedSearchWebhookDelivery
+	for rows.Next() {
+		var d SavedSearchWebhookDelivery
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.Event, &d.Payload, &d.State, &d.Attempts, &d.ResponseStatusCode, &d.Error, &d.CreatedAt, &d.LastAttemptAt, &d.NextAttemptAt); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &d)
+	}
+	return deliveries, rows.Err()
+}
+
+// RecordDeliveryAttempt records the outcome of an attempt to deliver a pending delivery. The
+// delivery is marked as succeeded if the attempt succeeded, as pending (to be retried at
+// attempt.NextAttemptAt) if it failed and should be retried, and as failed otherwise.
+func (*savedSearchWebhooks) RecordDeliveryAttempt(ctx context.Context, deliveryID int32, attempt SavedSearchWebhookDeliveryAttempt) error {
+	if Mocks.SavedSearchWebhooks.RecordDeliveryAttempt != nil {
+		return Mocks.SavedSearchWebhooks.RecordDeliveryAttempt(ctx, deliveryID, attempt)
+	}
+
+	var state string
+	var nextAttemptAt *time.Time
+	switch {
+	case attempt.Success:
+		state = SavedSearchWebhookDeliverySucceeded
+	case attempt.NextAttemptAt != nil:
+		state = SavedSearchWebhookDeliveryPending
+		nextAttemptAt = attempt.NextAttemptAt
+	default:
+		state = SavedSearchWebhookDeliveryFailed
+	}
+
+	err := dbconn.Global.QueryRowContext(ctx, `
+UPDATE saved_search_webhook_deliveries
+SET state=$2, attempts=attempts+1, response_status_code=$3, error=$4, last_attempt_at=now(), next_attempt_at=$5
+WHERE id=$1 AND state=$6
+RETURNING id`,
+		deliveryID, state, attempt.ResponseStatusCode, attempt.Error, nextAttemptAt, SavedSearchWebhookDeliveryPending,
+	).Scan(&deliveryID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("pending saved search webhook delivery not found: %d", deliveryID)
+	}
+	return err
+}
@@ -0,0 +1,15 @@
+package db
+
+import "context"
+
+type MockSavedSearchWebhooks struct {
+	Create                func(ctx context.Context, savedSearchID int32, url, secret string) (*SavedSearchWebhook, error)
+	GetByID               func(ctx context.Context, id int32) (*SavedSearchWebhook, error)
+	ListBySavedSearch     func(ctx context.Context, savedSearchID int32) ([]*SavedSearchWebhook, error)
+	Update                func(ctx context.Context, id int32, url string, secret *string) (*SavedSearchWebhook, error)
+	Delete                func(ctx context.Context, id int32) error
+	CreateDeliveries      func(ctx context.Context, savedSearchID int32, event, payload string) (int, error)
+	ListDueDeliveries     func(ctx context.Context, limit int) ([]*SavedSearchWebhookDelivery, error)
+	ListDeliveries        func(ctx context.Context, webhookID int32, limit int) ([]*SavedSearchWebhookDelivery, int, error)
+	RecordDeliveryAttempt func(ctx context.Context, deliveryID int32, attempt SavedSearchWebhookDeliveryAttempt) error
+}
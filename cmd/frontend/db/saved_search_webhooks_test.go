@@ -0,0 +1,160 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+func createTestSavedSearch(ctx context.Context, t *testing.T) *types.SavedSearch {
+	user, err := Users.Create(ctx, NewUser{Username: "u", Email: "u@example.com", EmailVerificationCode: "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss, err := SavedSearches.Create(ctx, &types.SavedSearch{Query: "q", Description: "d", UserID: &user.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ss
+}
+
+func TestSavedSearchWebhooks(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+	ss := createTestSavedSearch(ctx, t)
+
+	w, err := SavedSearchWebhooks.Create(ctx, ss.ID, "https://example.com/hook", "s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	webhooks, err := SavedSearchWebhooks.ListBySavedSearch(ctx, ss.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(webhooks) != 1 || webhooks[0].ID != w.ID || webhooks[0].Secret != "s1" {
+		t.Fatalf("got webhooks %+v, want [%+v]", webhooks, w)
+	}
+
+	// Update only the URL.
+	w, err = SavedSearchWebhooks.Update(ctx, w.ID, "https://example.com/hook2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.URL != "https://example.com/hook2" || w.Secret != "s1" {
+		t.Errorf("got webhook %+v after URL update", w)
+	}
+	secret := "s2"
+	w, err = SavedSearchWebhooks.Update(ctx, w.ID, w.URL, &secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Secret != "s2" {
+		t.Errorf("got secret %q, want %q", w.Secret, "s2")
+	}
+
+	if err := SavedSearchWebhooks.Delete(ctx, w.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SavedSearchWebhooks.GetByID(ctx, w.ID); !errcode.IsNotFound(err) {
+		t.Errorf("got error %v, want not found", err)
+	}
+	if err := SavedSearchWebhooks.Delete(ctx, w.ID); !errcode.IsNotFound(err) {
+		t.Errorf("got error %v, want not found", err)
+	}
+}
+
+func TestSavedSearchWebhooks_deliveries(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+	ss := createTestSavedSearch(ctx, t)
+
+	w1, err := SavedSearchWebhooks.Create(ctx, ss.ID, "https://example.com/1", "s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w2, err := SavedSearchWebhooks.Create(ctx, ss.ID, "https://example.com/2", "s")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := SavedSearchWebhooks.CreateDeliveries(ctx, ss.ID, "saved_search.new_results", `{"a":1}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("got %d deliveries created, want 2", n)
+	}
+
+	due, err := SavedSearchWebhooks.ListDueDeliveries(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 2 {
+		t.Fatalf("got %d due deliveries, want 2", len(due))
+	}
+	for _, d := range due {
+		if d.State != SavedSearchWebhookDeliveryPending || d.Payload != `{"a":1}` || d.Attempts != 0 {
+			t.Errorf("got delivery %+v", d)
+		}
+	}
+
+	// Fail the first delivery (with a retry in the future) and deliver the second.
+	statusCode := int32(500)
+	errMsg := "unexpected HTTP response status 500"
+	nextAttemptAt := time.Now().Add(time.Hour)
+	deliveryByWebhook := map[int32]*SavedSearchWebhookDelivery{}
+	for _, d := range due {
+		deliveryByWebhook[d.WebhookID] = d
+	}
+	if err := SavedSearchWebhooks.RecordDeliveryAttempt(ctx, deliveryByWebhook[w1.ID].ID, SavedSearchWebhookDeliveryAttempt{
+		ResponseStatusCode: &statusCode,
+		Error:              &errMsg,
+		NextAttemptAt:      &nextAttemptAt,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SavedSearchWebhooks.RecordDeliveryAttempt(ctx, deliveryByWebhook[w2.ID].ID, SavedSearchWebhookDeliveryAttempt{Success: true}); err != nil {
+		t.Fatal(err)
+	}
+	if due, err := SavedSearchWebhooks.ListDueDeliveries(ctx, 10); err != nil {
+		t.Fatal(err)
+	} else if len(due) != 0 {
+		t.Errorf("got %d due deliveries, want 0", len(due))
+	}
+
+	deliveries, totalCount, err := SavedSearchWebhooks.ListDeliveries(ctx, w1.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totalCount != 1 || len(deliveries) != 1 {
+		t.Fatalf("got %d deliveries (total count %d), want 1", len(deliveries), totalCount)
+	}
+	if d := deliveries[0]; d.State != SavedSearchWebhookDeliveryPending || d.Attempts != 1 || d.ResponseStatusCode == nil || *d.ResponseStatusCode != 500 || d.Error == nil || d.LastAttemptAt == nil || d.NextAttemptAt == nil {
+		t.Errorf("got delivery %+v after failed attempt", d)
+	}
+
+	// A final failed attempt marks the delivery as failed.
+	if err := SavedSearchWebhooks.RecordDeliveryAttempt(ctx, deliveries[0].ID, SavedSearchWebhookDeliveryAttempt{Error: &errMsg}); err != nil {
+		t.Fatal(err)
+	}
+	deliveries, _, err = SavedSearchWebhooks.ListDeliveries(ctx, w1.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := deliveries[0]; d.State != SavedSearchWebhookDeliveryFailed || d.Attempts != 2 || d.NextAttemptAt != nil {
+		t.Errorf("got delivery %+v after final attempt", d)
+	}
+
+	// Completed deliveries can't be attempted again.
+	if err := SavedSearchWebhooks.RecordDeliveryAttempt(ctx, deliveries[0].ID, SavedSearchWebhookDeliveryAttempt{Success: true}); err == nil {
+		t.Error("got nil error for attempt of failed delivery")
+	}
+}
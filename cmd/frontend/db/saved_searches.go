@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	otlog "github.com/opentracing/opentracing-go/log"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
@@ -56,7 +57,11 @@ func (s *savedSearches) ListAll(ctx context.Context) (savedSearches []api.SavedQ
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url FROM saved_searches
+		slack_webhook_url,
+		EXISTS(SELECT 1 FROM saved_search_webhooks w WHERE w.saved_search_id=saved_searches.id),
+		EXISTS(SELECT 1 FROM saved_search_subscriptions s WHERE s.saved_search_id=saved_searches.id),
+		schedule,
+		timeout_seconds FROM saved_searches
 	`)
 	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar))
 	if err != nil {
@@ -73,7 +78,11 @@ func (s *savedSearches) ListAll(ctx context.Context) (savedSearches []api.SavedQ
 			&sq.Config.NotifySlack,
 			&sq.Config.UserID,
 			&sq.Config.OrgID,
-			&sq.Config.SlackWebhookURL); err != nil {
+			&sq.Config.SlackWebhookURL,
+			&sq.Config.HasWebhooks,
+			&sq.Config.HasSubscriptions,
+			&sq.Config.Schedule,
+			&sq.Config.TimeoutSeconds); err != nil {
 			return nil, errors.Wrap(err, "Scan")
 		}
 		sq.Spec.Key = sq.Config.Key
@@ -106,7 +115,11 @@ func (s *savedSearches) GetByID(ctx context.Context, id int32) (*api.SavedQueryS
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url
+		slack_webhook_url,
+		EXISTS(SELECT 1 FROM saved_search_webhooks w WHERE w.saved_search_id=saved_searches.id),
+		EXISTS(SELECT 1 FROM saved_search_subscriptions s WHERE s.saved_search_id=saved_searches.id),
+		schedule,
+		timeout_seconds
 		FROM saved_searches WHERE id=$1`, id).Scan(
 		&sq.Config.Key,
 		&sq.Config.Description,
@@ -115,7 +128,11 @@ func (s *savedSearches) GetByID(ctx context.Context, id int32) (*api.SavedQueryS
 		&sq.Config.NotifySlack,
 		&sq.Config.UserID,
 		&sq.Config.OrgID,
-		&sq.Config.SlackWebhookURL)
+		&sq.Config.SlackWebhookURL,
+		&sq.Config.HasWebhooks,
+		&sq.Config.HasSubscriptions,
+		&sq.Config.Schedule,
+		&sq.Config.TimeoutSeconds)
 	if err != nil {
 		return nil, err
 	}
@@ -166,7 +183,9 @@ func (s *savedSearches) ListSavedSearchesByUserID(ctx context.Context, userID in
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url
+		slack_webhook_url,
+		schedule,
+		timeout_seconds
 		FROM saved_searches %v`, conds)
 
 	rows, err := dbconn.Global.QueryContext(ctx, query.Query(sqlf.PostgresBindVar), query.Args()...)
@@ -175,7 +194,7 @@ func (s *savedSearches) ListSavedSearchesByUserID(ctx context.Context, userID in
 	}
 	for rows.Next() {
 		var ss types.SavedSearch
-		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL); err != nil {
+		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL, &ss.Schedule, &ss.TimeoutSeconds); err != nil {
 			return nil, errors.Wrap(err, "Scan(2)")
 		}
 		savedSearches = append(savedSearches, &ss)
@@ -201,7 +220,9 @@ func (s *savedSearches) ListSavedSearchesByOrgID(ctx context.Context, orgID int3
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url
+		slack_webhook_url,
+		schedule,
+		timeout_seconds
 		FROM saved_searches %v`, conds)
 
 	rows, err := dbconn.Global.QueryContext(ctx, query.Query(sqlf.PostgresBindVar), query.Args()...)
@@ -210,7 +231,7 @@ func (s *savedSearches) ListSavedSearchesByOrgID(ctx context.Context, orgID int3
 	}
 	for rows.Next() {
 		var ss types.SavedSearch
-		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL); err != nil {
+		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL, &ss.Schedule, &ss.TimeoutSeconds); err != nil {
 			return nil, errors.Wrap(err, "Scan")
 		}
 		savedSearches = append(savedSearches, &ss)
@@ -240,12 +261,14 @@ func (s *savedSearches) Create(ctx context.Context, newSavedSearch *types.SavedS
 	}()
 
 	savedQuery = &types.SavedSearch{
-		Description: newSavedSearch.Description,
-		Query:       newSavedSearch.Query,
-		Notify:      newSavedSearch.Notify,
-		NotifySlack: newSavedSearch.NotifySlack,
-		UserID:      newSavedSearch.UserID,
-		OrgID:       newSavedSearch.OrgID,
+		Description:    newSavedSearch.Description,
+		Query:          newSavedSearch.Query,
+		Notify:         newSavedSearch.Notify,
+		NotifySlack:    newSavedSearch.NotifySlack,
+		UserID:         newSavedSearch.UserID,
+		OrgID:          newSavedSearch.OrgID,
+		Schedule:       newSavedSearch.Schedule,
+		TimeoutSeconds: newSavedSearch.TimeoutSeconds,
 	}
 
 	err = dbconn.Global.QueryRowContext(ctx, `INSERT INTO saved_searches(
@@ -254,14 +277,18 @@ func (s *savedSearches) Create(ctx context.Context, newSavedSearch *types.SavedS
 			notify_owner,
 			notify_slack,
 			user_id,
-			org_id
-		) VALUES($1, $2, $3, $4, $5, $6) RETURNING id`,
+			org_id,
+			schedule,
+			timeout_seconds
+		) VALUES($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
 		newSavedSearch.Description,
 		newSavedSearch.Query,
 		newSavedSearch.Notify,
 		newSavedSearch.NotifySlack,
 		newSavedSearch.UserID,
 		newSavedSearch.OrgID,
+		newSavedSearch.Schedule,
+		newSavedSearch.TimeoutSeconds,
 	).Scan(&savedQuery.ID)
 	if err != nil {
 		return nil, err
@@ -293,6 +320,8 @@ func (s *savedSearches) Update(ctx context.Context, savedSearch *types.SavedSear
 		UserID:          savedSearch.UserID,
 		OrgID:           savedSearch.OrgID,
 		SlackWebhookURL: savedSearch.SlackWebhookURL,
+		Schedule:        savedSearch.Schedule,
+		TimeoutSeconds:  savedSearch.TimeoutSeconds,
 	}
 
 	fieldUpdates := []*sqlf.Query{
@@ -304,6 +333,12 @@ func (s *savedSearches) Update(ctx context.Context, savedSearch *types.SavedSear
 		sqlf.Sprintf("user_id=%v", savedSearch.UserID),
 		sqlf.Sprintf("org_id=%v", savedSearch.OrgID),
 		sqlf.Sprintf("slack_webhook_url=%v", savedSearch.SlackWebhookURL),
+		sqlf.Sprintf("schedule=%v", savedSearch.Schedule),
+		sqlf.Sprintf("timeout_seconds=%v", savedSearch.TimeoutSeconds),
+		// Run the updated saved search as soon as possible (and without backing off from the
+		// errors of the previous query), so that the new schedule takes effect.
+		sqlf.Sprintf("next_run_at=NULL"),
+		sqlf.Sprintf("consecutive_failures=0"),
 	}
 
 	updateQuery := sqlf.Sprintf(`UPDATE saved_searches SET %s WHERE ID=%v RETURNING id`, sqlf.Join(fieldUpdates, ", "), savedSearch.ID)
@@ -334,3 +369,101 @@ func (s *savedSearches) Delete(ctx context.Context, id int32) (err error) {
 	}
 	return nil
 }
+
+// ListRunStates lists the query runner's scheduling state of all saved searches.
+func (s *savedSearches) ListRunStates(ctx context.Context) (states []*api.SavedQueryRunState, err error) {
+	if Mocks.SavedSearches.ListRunStates != nil {
+		return Mocks.SavedSearches.ListRunStates(ctx)
+	}
+
+	tr, ctx := trace.New(ctx, "db.SavedSearches.ListRunStates", "")
+	defer func() {
+		tr.SetError(err)
+		tr.LogFields(otlog.Int("count", len(states)))
+		tr.Finish()
+	}()
+
+	rows, err := dbconn.Global.QueryContext(ctx, `SELECT
+		id,
+		next_run_at,
+		last_run_at,
+		last_run_duration_ms,
+		last_run_error,
+		consecutive_failures
+		FROM saved_searches ORDER BY id`)
+	if err != nil {
+		return nil, errors.Wrap(err, "QueryContext")
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var state api.SavedQueryRunState
+		var lastRunDurationMS sql.NullInt64
+		if err := rows.Scan(&state.SavedSearchID, &state.NextRunAt, &state.LastRunAt, &lastRunDurationMS, &state.LastRunError, &state.ConsecutiveFailures); err != nil {
+			return nil, errors.Wrap(err, "Scan")
+		}
+		state.LastRunDuration = time.Duration(lastRunDurationMS.Int64) * time.Millisecond
+		states = append(states, &state)
+	}
+	return states, rows.Err()
+}
+
+// GetRunState returns the query runner's scheduling state of the saved search.
+//
+// 🚨 SECURITY: This method does NOT verify the user's identity or that the
+// user is an admin. It is the callers responsibility to ensure this response
+// only makes it to users with proper permissions to access the saved search.
+func (s *savedSearches) GetRunState(ctx context.Context, id int32) (*api.SavedQueryRunState, error) {
+	if Mocks.SavedSearches.GetRunState != nil {
+		return Mocks.SavedSearches.GetRunState(ctx, id)
+	}
+
+	state := api.SavedQueryRunState{SavedSearchID: id}
+	var lastRunDurationMS sql.NullInt64
+	if err := dbconn.Global.QueryRowContext(ctx, `SELECT
+		next_run_at,
+		last_run_at,
+		last_run_duration_ms,
+		last_run_error,
+		consecutive_failures
+		FROM saved_searches WHERE id=$1`, id).Scan(
+		&state.NextRunAt,
+		&state.LastRunAt,
+		&lastRunDurationMS,
+		&state.LastRunError,
+		&state.ConsecutiveFailures,
+	); err != nil {
+		return nil, err
+	}
+	state.LastRunDuration = time.Duration(lastRunDurationMS.Int64) * time.Millisecond
+	return &state, nil
+}
+
+// UpdateRunState records the stats of a run of the saved search and when it should run next. The
+// saved search's count of consecutive failures is reset if the run succeeded and incremented
+// otherwise.
+func (s *savedSearches) UpdateRunState(ctx context.Context, run *api.SavedQueryRun) (err error) {
+	if Mocks.SavedSearches.UpdateRunState != nil {
+		return Mocks.SavedSearches.UpdateRunState(ctx, run)
+	}
+
+	tr, ctx := trace.New(ctx, "db.SavedSearches.UpdateRunState", "")
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	_, err = dbconn.Global.ExecContext(ctx, `UPDATE saved_searches SET
+		next_run_at=$2,
+		last_run_at=$3,
+		last_run_duration_ms=$4,
+		last_run_error=$5,
+		consecutive_failures=CASE WHEN $5::text IS NULL THEN 0 ELSE consecutive_failures+1 END
+		WHERE id=$1`,
+		run.SavedSearchID,
+		run.NextRunAt,
+		run.StartedAt,
+		int64(run.Duration/time.Millisecond),
+		run.Error,
+	)
+	return err
+}
@@ -14,4 +14,7 @@ type MockSavedSearches struct {
 	Update                    func(ctx context.Context, savedSearch *types.SavedSearch) (*types.SavedSearch, error)
 	Delete                    func(ctx context.Context, id int32) error
 	GetByID                   func(ctx context.Context, id int32) (*api.SavedQuerySpecAndConfig, error)
+	ListRunStates             func(ctx context.Context) ([]*api.SavedQueryRunState, error)
+	GetRunState               func(ctx context.Context, id int32) (*api.SavedQueryRunState, error)
+	UpdateRunState            func(ctx context.Context, run *api.SavedQueryRun) error
 }
@@ -3,6 +3,7 @@ package db
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
@@ -380,3 +381,98 @@ func TestListSavedSearchesByUserID(t *testing.T) {
 		t.Errorf("got %v, want %v", savedSearches, want)
 	}
 }
+
+func TestSavedSearchesRunState(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := dbtesting.TestContext(t)
+	_, err := Users.Create(ctx, NewUser{DisplayName: "test", Email: "test@test.com", Username: "test", Password: "test", EmailVerificationCode: "c2"})
+	if err != nil {
+		t.Fatal("can't create user", err)
+	}
+	userID := int32(1)
+	schedule := "@every 1h"
+	timeoutSeconds := int32(30)
+	ss, err := SavedSearches.Create(ctx, &types.SavedSearch{
+		Query:          "test",
+		Description:    "test",
+		Notify:         true,
+		UserID:         &userID,
+		Schedule:       &schedule,
+		TimeoutSeconds: &timeoutSeconds,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sq, err := SavedSearches.GetByID(ctx, ss.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sq.Config.Schedule == nil || *sq.Config.Schedule != schedule || sq.Config.TimeoutSeconds == nil || *sq.Config.TimeoutSeconds != timeoutSeconds {
+		t.Errorf("got schedule %v and timeout %v, want %q and %d", sq.Config.Schedule, sq.Config.TimeoutSeconds, schedule, timeoutSeconds)
+	}
+
+	state, err := SavedSearches.GetRunState(ctx, ss.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&api.SavedQueryRunState{SavedSearchID: ss.ID}); !reflect.DeepEqual(state, want) {
+		t.Errorf("got %+v, want %+v", state, want)
+	}
+
+	startedAt := time.Date(2019, 3, 6, 10, 0, 0, 0, time.UTC)
+	nextRunAt := startedAt.Add(time.Hour)
+	errStr := "timed out"
+	for i := 0; i < 2; i++ {
+		if err := SavedSearches.UpdateRunState(ctx, &api.SavedQueryRun{
+			SavedSearchID: ss.ID,
+			StartedAt:     startedAt,
+			Duration:      2 * time.Second,
+			Error:         &errStr,
+			NextRunAt:     nextRunAt,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	states, err := SavedSearches.ListRunStates(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 1 {
+		t.Fatalf("got %d run states, want 1", len(states))
+	}
+	state = states[0]
+	if state.NextRunAt == nil || !state.NextRunAt.Equal(nextRunAt) || state.LastRunAt == nil || !state.LastRunAt.Equal(startedAt) {
+		t.Errorf("got next run at %v and last run at %v, want %s and %s", state.NextRunAt, state.LastRunAt, nextRunAt, startedAt)
+	}
+	if state.LastRunDuration != 2*time.Second || state.LastRunError == nil || *state.LastRunError != errStr || state.ConsecutiveFailures != 2 {
+		t.Errorf("got %+v, want a failed 2s run with 2 consecutive failures", state)
+	}
+
+	// A successful run resets the count of consecutive failures.
+	if err := SavedSearches.UpdateRunState(ctx, &api.SavedQueryRun{SavedSearchID: ss.ID, StartedAt: startedAt, NextRunAt: nextRunAt}); err != nil {
+		t.Fatal(err)
+	}
+	state, err = SavedSearches.GetRunState(ctx, ss.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.LastRunError != nil || state.ConsecutiveFailures != 0 {
+		t.Errorf("got %+v, want no error and 0 consecutive failures", state)
+	}
+
+	// Updating the saved search makes it run as soon as possible.
+	if _, err := SavedSearches.Update(ctx, &types.SavedSearch{ID: ss.ID, Query: "test2", Description: "test", UserID: &userID}); err != nil {
+		t.Fatal(err)
+	}
+	state, err = SavedSearches.GetRunState(ctx, ss.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.NextRunAt != nil {
+		t.Errorf("got next run at %s, want nil", state.NextRunAt)
+	}
+}
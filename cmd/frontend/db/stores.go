@@ -20,5 +20,29 @@ var (
 
 	ExternalAccounts = &userExternalAccounts{}
 
+	UserRepoPermissions = &userRepoPermissions{}
+
+	ExplicitRepoPermissions = &explicitRepoPermissions{}
+
 	OrgInvitations = &orgInvitations{}
+
+	RepoUpdateAttempts = &repoUpdateAttempts{}
+
+	AuditLog = &auditLog{}
+
+	UserMFA = &userMFA{}
+
+	UserSessions = &userSessions{}
+
+	SavedSearchWebhooks = &savedSearchWebhooks{}
+
+	SavedSearchSubscriptions = &savedSearchSubscriptions{}
+
+	SavedSearchRuns = &savedSearchRuns{}
+
+	Notifications = &notifications{}
+
+	UserNotificationSettings = &userNotificationSettings{}
+
+	DiscussionCommentReactions = &discussionCommentReactions{}
 )
@@ -0,0 +1,276 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbutil"
+)
+
+// UserTOTP describes a user's time-based one-time password (TOTP) second factor.
+type UserTOTP struct {
+	UserID          int32
+	Secret          string
+	EnabledAt       *time.Time // nil if the user has started but not completed enrollment
+	LastUsedCounter int64      // the time step counter of the last code that was used
+}
+
+// userTOTPNotFoundError occurs when a user has no TOTP second factor (enabled or pending).
+type userTOTPNotFoundError struct {
+	userID int32
+}
+
+func (err userTOTPNotFoundError) Error() string {
+	return fmt.Sprintf("TOTP not found for user %d", err.userID)
+}
+
+func (err userTOTPNotFoundError) NotFound() bool { return true }
+
+// ErrTOTPAlreadyEnabled occurs when a user who has already enabled TOTP attempts to enroll again.
+var ErrTOTPAlreadyEnabled = errors.New("TOTP is already enabled for the user")
+
+// userMFA provides access to the `user_totp` and `user_mfa_recovery_codes` tables, which hold the
+// multi-factor authentication (MFA) settings of builtin auth users.
+//
+// Recovery codes are stored as SHA-256 hashes. (We don't use bcrypt because recovery codes are
+// randomly generated, not chosen by users, so brute-forcing them is implausible.)
+//
+// For a detailed overview of the schema, see schema.md.
+type userMFA struct{}
+
+// GetTOTP returns the user's TOTP second factor, which may be pending enrollment (if EnabledAt is
+// nil). If the user has none, it returns an error for which errcode.IsNotFound returns true.
+//
+// 🚨 SECURITY: The returned value contains the user's TOTP secret. It must not be shown to anyone.
+func (*userMFA) GetTOTP(ctx context.Context, userID int32) (*UserTOTP, error) {
+	if Mocks.UserMFA.GetTOTP != nil {
+		return Mocks.UserMFA.GetTOTP(ctx, userID)
+	}
+
+	t := UserTOTP{UserID: userID}
+	err := dbconn.Global.QueryRowContext(ctx, "SELECT secret, enabled_at, last_used_counter FROM user_totp WHERE user_id=$1", userID).Scan(&t.Secret, &t.EnabledAt, &t.LastUsedCounter)
+	if err == sql.ErrNoRows {
+		return nil, userTOTPNotFoundError{userID: userID}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// SetPendingTOTP begins (or restarts) the user's TOTP enrollment with the given secret. The secret
+// is not used to verify sign-ins until EnableTOTP is called. If the user has already enabled TOTP,
+// it returns ErrTOTPAlreadyEnabled.
+func (*userMFA) SetPendingTOTP(ctx context.Context, userID int32, secret string) error {
+	if Mocks.UserMFA.SetPendingTOTP != nil {
+		return Mocks.UserMFA.SetPendingTOTP(ctx, userID, secret)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx, `
+INSERT INTO user_totp(user_id, secret) VALUES($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET secret=excluded.secret, last_used_counter=0, created_at=now()
+WHERE user_totp.enabled_at IS NULL`, userID, secret)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return ErrTOTPAlreadyEnabled
+	}
+	return nil
+}
+
+// EnableTOTP completes the user's pending TOTP enrollment and replaces the user's recovery codes.
+// The counter is that of the code that the user supplied to confirm enrollment (so that the same
+// code can't be used to sign in).
+//
+// 🚨 SECURITY: The caller must verify that the user possesses the secret (by checking a code) before
+// calling this method.
+func (*userMFA) EnableTOTP(ctx context.Context, userID int32, counter int64, recoveryCodes []string) error {
+	if Mocks.UserMFA.EnableTOTP != nil {
+		return Mocks.UserMFA.EnableTOTP(ctx, userID, counter, recoveryCodes)
+	}
+
+	return dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, "UPDATE user_totp SET enabled_at=now(), last_used_counter=$2 WHERE user_id=$1 AND enabled_at IS NULL", userID, counter)
+		if err != nil {
+			return err
+		}
+		nrows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if nrows == 0 {
+			return errors.New("no pending TOTP enrollment for user")
+		}
+		return setRecoveryCodes(ctx, tx, userID, recoveryCodes)
+	})
+}
+
+// UseTOTPCounter records that the user used the code for the given time step counter. It returns
+// false (and does not record anything) if a code for the same or a later time step was already
+// used, or if the user has not enabled TOTP.
+//
+// 🚨 SECURITY: This prevents a code from being used more than once (RFC 6238 section 5.2). The
+// check and update are performed atomically.
+func (*userMFA) UseTOTPCounter(ctx context.Context, userID int32, counter int64) (bool, error) {
+	if Mocks.UserMFA.UseTOTPCounter != nil {
+		return Mocks.UserMFA.UseTOTPCounter(ctx, userID, counter)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx, "UPDATE user_totp SET last_used_counter=$2 WHERE user_id=$1 AND enabled_at IS NOT NULL AND last_used_counter < $2", userID, counter)
+	if err != nil {
+		return false, err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return nrows == 1, nil
+}
+
+// SetRecoveryCodes replaces the user's recovery codes.
+func (*userMFA) SetRecoveryCodes(ctx context.Context, userID int32, recoveryCodes []string) error {
+	if Mocks.UserMFA.SetRecoveryCodes != nil {
+		return Mocks.UserMFA.SetRecoveryCodes(ctx, userID, recoveryCodes)
+	}
+
+	return dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		return setRecoveryCodes(ctx, tx, userID, recoveryCodes)
+	})
+}
+
+func setRecoveryCodes(ctx context.Context, tx *sql.Tx, userID int32, recoveryCodes []string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_mfa_recovery_codes WHERE user_id=$1", userID); err != nil {
+		return err
+	}
+	for _, code := range recoveryCodes {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO user_mfa_recovery_codes(user_id, code_hash) VALUES($1, $2)", userID, hashRecoveryCode(code)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UseRecoveryCode marks the recovery code as used and returns true if it is one of the user's
+// unused recovery codes. Otherwise it returns false.
+func (*userMFA) UseRecoveryCode(ctx context.Context, userID int32, code string) (bool, error) {
+	if Mocks.UserMFA.UseRecoveryCode != nil {
+		return Mocks.UserMFA.UseRecoveryCode(ctx, userID, code)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx, "UPDATE user_mfa_recovery_codes SET used_at=now() WHERE user_id=$1 AND code_hash=$2 AND used_at IS NULL", userID, hashRecoveryCode(code))
+	if err != nil {
+		return false, err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return nrows > 0, nil
+}
+
+// CountUnusedRecoveryCodes returns the number of the user's recovery codes that have not been used.
+func (*userMFA) CountUnusedRecoveryCodes(ctx context.Context, userID int32) (int, error) {
+	if Mocks.UserMFA.CountUnusedRecoveryCodes != nil {
+		return Mocks.UserMFA.CountUnusedRecoveryCodes(ctx, userID)
+	}
+
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM user_mfa_recovery_codes WHERE user_id=%d AND used_at IS NULL", userID)
+	var count int
+	err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count)
+	return count, err
+}
+
+// RecordFailedAttempt records that the user supplied an incorrect code and returns the number of
+// consecutive incorrect codes that the user supplied (in any session) since their last successful
+// sign-in. Incorrect codes that were supplied more than a day before the previous one are
+// forgotten.
+func (*userMFA) RecordFailedAttempt(ctx context.Context, userID int32) (count int, err error) {
+	if Mocks.UserMFA.RecordFailedAttempt != nil {
+		return Mocks.UserMFA.RecordFailedAttempt(ctx, userID)
+	}
+
+	err = dbconn.Global.QueryRowContext(ctx, `
+INSERT INTO user_mfa_failed_attempts(user_id, count) VALUES($1, 1)
+ON CONFLICT (user_id) DO UPDATE SET
+  count=(CASE WHEN user_mfa_failed_attempts.updated_at < now() - interval '1 day' THEN 1 ELSE user_mfa_failed_attempts.count + 1 END),
+  updated_at=now()
+RETURNING count`, userID).Scan(&count)
+	return count, err
+}
+
+// LockUntil prevents the user from supplying codes before the given time. It must be called after
+// RecordFailedAttempt.
+func (*userMFA) LockUntil(ctx context.Context, userID int32, t time.Time) error {
+	if Mocks.UserMFA.LockUntil != nil {
+		return Mocks.UserMFA.LockUntil(ctx, userID, t)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx, "UPDATE user_mfa_failed_attempts SET locked_until=$2 WHERE user_id=$1", userID, t)
+	return err
+}
+
+// LockedUntil returns the time before which the user may not supply codes, or the zero time if the
+// user is not locked out.
+func (*userMFA) LockedUntil(ctx context.Context, userID int32) (time.Time, error) {
+	if Mocks.UserMFA.LockedUntil != nil {
+		return Mocks.UserMFA.LockedUntil(ctx, userID)
+	}
+
+	var lockedUntil *time.Time
+	err := dbconn.Global.QueryRowContext(ctx, "SELECT locked_until FROM user_mfa_failed_attempts WHERE user_id=$1", userID).Scan(&lockedUntil)
+	if err == sql.ErrNoRows || lockedUntil == nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return *lockedUntil, nil
+}
+
+// ResetFailedAttempts forgets the incorrect codes that the user supplied (and lifts any lockout).
+// It is called when the user signs in successfully.
+func (*userMFA) ResetFailedAttempts(ctx context.Context, userID int32) error {
+	if Mocks.UserMFA.ResetFailedAttempts != nil {
+		return Mocks.UserMFA.ResetFailedAttempts(ctx, userID)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx, "DELETE FROM user_mfa_failed_attempts WHERE user_id=$1", userID)
+	return err
+}
+
+// Delete removes the user's TOTP second factor (enabled or pending), recovery codes, and failed
+// attempts. It is used when a site admin resets a user's MFA (for example, if the user lost their
+// device and recovery codes).
+func (*userMFA) Delete(ctx context.Context, userID int32) error {
+	if Mocks.UserMFA.Delete != nil {
+		return Mocks.UserMFA.Delete(ctx, userID)
+	}
+
+	return dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM user_mfa_recovery_codes WHERE user_id=$1", userID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM user_mfa_failed_attempts WHERE user_id=$1", userID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "DELETE FROM user_totp WHERE user_id=$1", userID)
+		return err
+	})
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
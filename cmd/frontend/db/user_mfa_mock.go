@@ -0,0 +1,21 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+type MockUserMFA struct {
+	GetTOTP                  func(ctx context.Context, userID int32) (*UserTOTP, error)
+	SetPendingTOTP           func(ctx context.Context, userID int32, secret string) error
+	EnableTOTP               func(ctx context.Context, userID int32, counter int64, recoveryCodes []string) error
+	UseTOTPCounter           func(ctx context.Context, userID int32, counter int64) (bool, error)
+	SetRecoveryCodes         func(ctx context.Context, userID int32, recoveryCodes []string) error
+	UseRecoveryCode          func(ctx context.Context, userID int32, code string) (bool, error)
+	CountUnusedRecoveryCodes func(ctx context.Context, userID int32) (int, error)
+	RecordFailedAttempt      func(ctx context.Context, userID int32) (int, error)
+	LockUntil                func(ctx context.Context, userID int32, t time.Time) error
+	LockedUntil              func(ctx context.Context, userID int32) (time.Time, error)
+	ResetFailedAttempts      func(ctx context.Context, userID int32) error
+	Delete                   func(ctx context.Context, userID int32) error
+}
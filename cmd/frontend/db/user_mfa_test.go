@@ -0,0 +1,176 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+func TestUserMFA_TOTP(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	user, err := Users.Create(ctx, NewUser{Username: "u", Password: "p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := UserMFA.GetTOTP(ctx, user.ID); !errcode.IsNotFound(err) {
+		t.Fatalf("got error %v, want not found", err)
+	}
+	if ok, err := UserMFA.UseTOTPCounter(ctx, user.ID, 1); err != nil || ok {
+		t.Fatalf("got ok %v error %v, want false with no TOTP", ok, err)
+	}
+
+	// Begin enrollment twice (restarting enrollment is allowed until it is completed).
+	if err := UserMFA.SetPendingTOTP(ctx, user.ID, "SECRET1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := UserMFA.SetPendingTOTP(ctx, user.ID, "SECRET2"); err != nil {
+		t.Fatal(err)
+	}
+	totp, err := UserMFA.GetTOTP(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totp.Secret != "SECRET2" || totp.EnabledAt != nil {
+		t.Errorf("got %+v, want pending enrollment with SECRET2", totp)
+	}
+	if ok, err := UserMFA.UseTOTPCounter(ctx, user.ID, 1); err != nil || ok {
+		t.Fatalf("got ok %v error %v, want false with pending TOTP", ok, err)
+	}
+
+	if err := UserMFA.EnableTOTP(ctx, user.ID, 100, []string{"a", "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := UserMFA.EnableTOTP(ctx, user.ID, 100, []string{"a", "b"}); err == nil {
+		t.Error("got nil error enabling TOTP twice, want non-nil")
+	}
+	if err := UserMFA.SetPendingTOTP(ctx, user.ID, "SECRET3"); err != ErrTOTPAlreadyEnabled {
+		t.Errorf("got error %v, want %v", err, ErrTOTPAlreadyEnabled)
+	}
+	totp, err = UserMFA.GetTOTP(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totp.Secret != "SECRET2" || totp.EnabledAt == nil || totp.LastUsedCounter != 100 {
+		t.Errorf("got %+v, want enabled TOTP with SECRET2 and counter 100", totp)
+	}
+
+	// Codes may only be used once, and only in increasing order.
+	for _, test := range []struct {
+		counter int64
+		want    bool
+	}{{100, false}, {99, false}, {101, true}, {101, false}, {103, true}} {
+		ok, err := UserMFA.UseTOTPCounter(ctx, user.ID, test.counter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != test.want {
+			t.Errorf("counter %d: got %v, want %v", test.counter, ok, test.want)
+		}
+	}
+
+	if err := UserMFA.Delete(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UserMFA.GetTOTP(ctx, user.ID); !errcode.IsNotFound(err) {
+		t.Fatalf("got error %v, want not found", err)
+	}
+	if n, err := UserMFA.CountUnusedRecoveryCodes(ctx, user.ID); err != nil || n != 0 {
+		t.Errorf("got %d recovery codes (error %v), want 0", n, err)
+	}
+}
+
+func TestUserMFA_RecoveryCodes(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	user1, err := Users.Create(ctx, NewUser{Username: "u1", Password: "p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user2, err := Users.Create(ctx, NewUser{Username: "u2", Password: "p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := UserMFA.SetRecoveryCodes(ctx, user1.ID, []string{"a", "b", "c"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := UserMFA.SetRecoveryCodes(ctx, user2.ID, []string{"d"}); err != nil {
+		t.Fatal(err)
+	}
+
+	useCode := func(userID int32, code string, want bool) {
+		t.Helper()
+		ok, err := UserMFA.UseRecoveryCode(ctx, userID, code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != want {
+			t.Errorf("user %d code %q: got %v, want %v", userID, code, ok, want)
+		}
+	}
+	useCode(user1.ID, "a", true)
+	useCode(user1.ID, "a", false) // already used
+	useCode(user1.ID, "d", false) // another user's code
+	useCode(user1.ID, "x", false)
+
+	if n, err := UserMFA.CountUnusedRecoveryCodes(ctx, user1.ID); err != nil || n != 2 {
+		t.Errorf("got %d unused recovery codes (error %v), want 2", n, err)
+	}
+
+	// Replacing the codes invalidates the old codes.
+	if err := UserMFA.SetRecoveryCodes(ctx, user1.ID, []string{"e"}); err != nil {
+		t.Fatal(err)
+	}
+	useCode(user1.ID, "b", false)
+	useCode(user1.ID, "e", true)
+	useCode(user2.ID, "d", true)
+}
+
+func TestUserMFA_FailedAttempts(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	user, err := Users.Create(ctx, NewUser{Username: "u", Password: "p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lockedUntil, err := UserMFA.LockedUntil(ctx, user.ID); err != nil || !lockedUntil.IsZero() {
+		t.Fatalf("got locked until %v (error %v), want not locked", lockedUntil, err)
+	}
+	for want := 1; want <= 3; want++ {
+		if count, err := UserMFA.RecordFailedAttempt(ctx, user.ID); err != nil || count != want {
+			t.Fatalf("got count %d (error %v), want %d", count, err, want)
+		}
+	}
+
+	lockUntil := time.Now().Add(time.Hour).Truncate(time.Second)
+	if err := UserMFA.LockUntil(ctx, user.ID, lockUntil); err != nil {
+		t.Fatal(err)
+	}
+	if lockedUntil, err := UserMFA.LockedUntil(ctx, user.ID); err != nil || !lockedUntil.Equal(lockUntil) {
+		t.Fatalf("got locked until %v (error %v), want %v", lockedUntil, err, lockUntil)
+	}
+
+	if err := UserMFA.ResetFailedAttempts(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if lockedUntil, err := UserMFA.LockedUntil(ctx, user.ID); err != nil || !lockedUntil.IsZero() {
+		t.Fatalf("got locked until %v (error %v), want not locked after reset", lockedUntil, err)
+	}
+	if count, err := UserMFA.RecordFailedAttempt(ctx, user.ID); err != nil || count != 1 {
+		t.Fatalf("got count %d (error %v), want 1 after reset", count, err)
+	}
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// UserNotificationSettings describes the channels on which a user receives notifications.
+type UserNotificationSettings struct {
+	UserID          int32
+	InApp           bool    // create in-app notifications
+	Email           bool    // send email notifications to the user's primary verified email address
+	SlackWebhookURL *string // post notifications to this Slack webhook URL (e.g., of a direct message channel)
+	UpdatedAt       time.Time
+}
+
+// DefaultUserNotificationSettings returns the notification settings of a user who has never
+// changed them: in-app and email notifications only.
+func DefaultUserNotificationSettings(userID int32) *UserNotificationSettings {
+	return &UserNotificationSettings{UserID: userID, InApp: true, Email: true}
+}
+
+// userNotificationSettings provides access to the `user_notification_settings` table.
+//
+// For a detailed overview of the schema, see schema.md.
+type userNotificationSettings struct{}
+
+// GetByUserID returns the user's notification settings, or the default settings if the user has
+// never changed them.
+func (*userNotificationSettings) GetByUserID(ctx context.Context, userID int32) (*UserNotificationSettings, error) {
+	if Mocks.UserNotificationSettings.GetByUserID != nil {
+		return Mocks.UserNotificationSettings.GetByUserID(ctx, userID)
+	}
+
+	s := UserNotificationSettings{UserID: userID}
+	err := dbconn.Global.QueryRowContext(ctx,
+		"SELECT in_app, email, slack_webhook_url, updated_at FROM user_notification_settings WHERE user_id=$1",
+		userID,
+	).Scan(&s.InApp, &s.Email, &s.SlackWebhookURL, &s.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return DefaultUserNotificationSettings(userID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Update sets the user's notification settings.
+func (*userNotificationSettings) Update(ctx context.Context, s *UserNotificationSettings) error {
+	if Mocks.UserNotificationSettings.Update != nil {
+		return Mocks.UserNotificationSettings.Update(ctx, s)
+	}
+
+	return dbconn.Global.QueryRowContext(ctx, `
+INSERT INTO user_notification_settings(user_id, in_app, email, slack_webhook_url) VALUES($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE SET in_app=EXCLUDED.in_app, email=EXCLUDED.email, slack_webhook_url=EXCLUDED.slack_webhook_url, updated_at=now()
+RETURNING updated_at`,
+		s.UserID, s.InApp, s.Email, s.SlackWebhookURL,
+	).Scan(&s.UpdatedAt)
+}
@@ -0,0 +1,8 @@
+package db
+
+import "context"
+
+type MockUserNotificationSettings struct {
+	GetByUserID func(ctx context.Context, userID int32) (*UserNotificationSettings, error)
+	Update      func(ctx context.Context, s *UserNotificationSettings) error
+}
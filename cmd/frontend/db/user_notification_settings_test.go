@@ -0,0 +1,44 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+)
+
+func TestUserNotificationSettings(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+	user, err := Users.Create(ctx, NewUser{Username: "u", Email: "u@example.com", EmailVerificationCode: "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := UserNotificationSettings.GetByUserID(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := DefaultUserNotificationSettings(user.ID); !reflect.DeepEqual(settings, want) {
+		t.Errorf("got %+v, want default settings %+v", settings, want)
+	}
+
+	slackWebhookURL := "https://hooks.slack.com/services/a"
+	for _, update := range []*UserNotificationSettings{
+		{UserID: user.ID, InApp: true, Email: true},
+		{UserID: user.ID, InApp: true, Email: false, SlackWebhookURL: &slackWebhookURL},
+	} {
+		if err := UserNotificationSettings.Update(ctx, update); err != nil {
+			t.Fatal(err)
+		}
+	}
+	settings, err = UserNotificationSettings.GetByUserID(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !settings.InApp || settings.Email || settings.SlackWebhookURL == nil || *settings.SlackWebhookURL != slackWebhookURL {
+		t.Errorf("got %+v, want in-app and Slack notifications only", settings)
+	}
+}
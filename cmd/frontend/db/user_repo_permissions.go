@@ -0,0 +1,186 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	otlog "github.com/opentracing/opentracing-go/log"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/pkg/trace"
+)
+
+// UserRepoPermissions is the set of repositories of a code host on which a
+// user has a permission, as determined by the code host's authz provider.
+type UserRepoPermissions struct {
+	UserID      int32
+	Perm        authz.Perms
+	ServiceType string // the authz provider's service type
+	ServiceID   string // the authz provider's service ID
+	RepoIDs     *roaring.Bitmap
+	UpdatedAt   time.Time
+}
+
+// userRepoPermissions provides access to the `user_repo_permissions` table,
+// which holds the repository permissions synced by the background permissions
+// syncer for all authz providers.
+//
+// The repository IDs are stored as arrays (instead of serialized bitmaps) so
+// that repository queries can join against them.
+//
+// For a detailed overview of the schema, see schema.md.
+type userRepoPermissions struct{}
+
+// Upsert stores the given permissions, replacing any previously stored
+// permissions of the same user, permission and authz provider.
+func (*userRepoPermissions) Upsert(ctx context.Context, p *UserRepoPermissions) (err error) {
+	if Mocks.UserRepoPermissions.Upsert != nil {
+		return Mocks.UserRepoPermissions.Upsert(ctx, p)
+	}
+
+	if p.UpdatedAt.IsZero() {
+		return errors.New("UpdatedAt timestamp must be set")
+	}
+
+	tr, ctx := trace.New(ctx, "db.UserRepoPermissions.Upsert", "")
+	defer func() {
+		tr.SetError(err)
+		tr.LogFields(
+			otlog.Int32("user.id", p.UserID),
+			otlog.String("service.id", p.ServiceID),
+			otlog.Uint64("repos.count", p.RepoIDs.GetCardinality()),
+		)
+		tr.Finish()
+	}()
+
+	ids := make([]int64, 0, p.RepoIDs.GetCardinality())
+	for it := p.RepoIDs.Iterator(); it.HasNext(); {
+		ids = append(ids, int64(it.Next()))
+	}
+
+	q := sqlf.Sprintf(`
+INSERT INTO user_repo_permissions
+  (user_id, permission, service_type, service_id, repo_ids, updated_at)
+VALUES
+  (%s, %s, %s, %s, %s, %s)
+ON CONFLICT ON CONSTRAINT
+  user_repo_permissions_unique
+DO UPDATE SET
+  repo_ids = excluded.repo_ids,
+  updated_at = excluded.updated_at
+`, p.UserID, p.Perm.String(), p.ServiceType, p.ServiceID, pq.Array(ids), p.UpdatedAt.UTC())
+
+	_, err = dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	return err
+}
+
+// List returns the stored permissions of the given user, for all authz
+// providers.
+func (*userRepoPermissions) List(ctx context.Context, userID int32, perm authz.Perms) ([]*UserRepoPermissions, error) {
+	if Mocks.UserRepoPermissions.List != nil {
+		return Mocks.UserRepoPermissions.List(ctx, userID, perm)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT service_type, service_id, repo_ids, updated_at
+FROM user_repo_permissions
+WHERE user_id = %s AND permission = %s
+ORDER BY service_type, service_id
+`, userID, perm.String())
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ps []*UserRepoPermissions
+	for rows.Next() {
+		p := UserRepoPermissions{UserID: userID, Perm: perm, RepoIDs: roaring.NewBitmap()}
+		var ids pq.Int64Array
+		if err := rows.Scan(&p.ServiceType, &p.ServiceID, &ids, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			p.RepoIDs.Add(uint32(id))
+		}
+		ps = append(ps, &p)
+	}
+	return ps, rows.Err()
+}
+
+// ListUsersToSync returns the IDs of up to limit users whose repository
+// permissions have not been synced since the given time, least recently
+// synced first. Users whose permissions have never been synced come first.
+//
+// Site admins are never returned, because they have access to all
+// repositories.
+func (*userRepoPermissions) ListUsersToSync(ctx context.Context, syncedBefore time.Time, limit int) ([]int32, error) {
+	if Mocks.UserRepoPermissions.ListUsersToSync != nil {
+		return Mocks.UserRepoPermissions.ListUsersToSync(ctx, syncedBefore, limit)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT users.id
+FROM users
+LEFT JOIN user_repo_permissions p ON p.user_id = users.id
+WHERE users.deleted_at IS NULL AND NOT users.site_admin
+GROUP BY users.id
+HAVING MIN(p.updated_at) IS NULL OR MIN(p.updated_at) < %s
+ORDER BY MIN(p.updated_at) NULLS FIRST, users.id
+LIMIT %s
+`, syncedBefore.UTC(), limit)
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, id)
+	}
+	return userIDs, rows.Err()
+}
+
+// syncedServiceIDs returns the service IDs of the authz providers for which
+// the repository permissions of the given user have been synced.
+func (*userRepoPermissions) syncedServiceIDs(ctx context.Context, userID int32, perm authz.Perms) (map[string]bool, error) {
+	q := sqlf.Sprintf(`
+SELECT service_id
+FROM user_repo_permissions
+WHERE user_id = %s AND permission = %s
+`, userID, perm.String())
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	synced := map[string]bool{}
+	for rows.Next() {
+		var serviceID string
+		if err := rows.Scan(&serviceID); err != nil {
+			return nil, err
+		}
+		synced[serviceID] = true
+	}
+	return synced, rows.Err()
+}
+
+// MockUserRepoPermissions mocks the user repository permissions store.
+type MockUserRepoPermissions struct {
+	Upsert          func(ctx context.Context, p *UserRepoPermissions) error
+	List            func(ctx context.Context, userID int32, perm authz.Perms) ([]*UserRepoPermissions, error)
+	ListUsersToSync func(ctx context.Context, syncedBefore time.Time, limit int) ([]int32, error)
+}
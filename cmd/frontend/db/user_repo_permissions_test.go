@@ -0,0 +1,201 @@
+package db
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/RoaringBitmap/roaring"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestUserRepoPermissions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	// The first user is a site admin, and site admins are never synced.
+	if _, err := Users.Create(ctx, NewUser{Username: "admin"}); err != nil {
+		t.Fatal(err)
+	}
+	var users []*types.User
+	for _, username := range []string{"u1", "u2", "u3"} {
+		user, err := Users.Create(ctx, NewUser{Username: username})
+		if err != nil {
+			t.Fatal(err)
+		}
+		users = append(users, user)
+	}
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	upsert := func(userID int32, serviceID string, updatedAt time.Time, ids ...uint32) {
+		t.Helper()
+		err := UserRepoPermissions.Upsert(ctx, &UserRepoPermissions{
+			UserID:      userID,
+			Perm:        authz.Read,
+			ServiceType: "github",
+			ServiceID:   serviceID,
+			RepoIDs:     roaring.BitmapOf(ids...),
+			UpdatedAt:   updatedAt,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	upsert(users[0].ID, "https://github.com/", now.Add(-2*time.Hour), 1, 2)
+	upsert(users[0].ID, "https://github.com/", now, 2, 3) // replaces the previous permissions
+	upsert(users[0].ID, "https://ghe.example.com/", now, 4)
+	upsert(users[1].ID, "https://github.com/", now.Add(-3*time.Hour))
+
+	ps, err := UserRepoPermissions.List(ctx, users[0].ID, authz.Read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string][]uint32{}
+	for _, p := range ps {
+		if !p.UpdatedAt.Equal(now) {
+			t.Errorf("got UpdatedAt %s, want %s", p.UpdatedAt, now)
+		}
+		got[p.ServiceID] = p.RepoIDs.ToArray()
+	}
+	want := map[string][]uint32{
+		"https://github.com/":      {2, 3},
+		"https://ghe.example.com/": {4},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got permissions %v, want %v", got, want)
+	}
+
+	// u3 was never synced, u2 was synced 3 hours ago and u1 just now.
+	userIDs, err := UserRepoPermissions.ListUsersToSync(ctx, now.Add(-time.Hour), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int32{users[2].ID, users[1].ID}; !reflect.DeepEqual(userIDs, want) {
+		t.Errorf("got users to sync %v, want %v", userIDs, want)
+	}
+
+	userIDs, err = UserRepoPermissions.ListUsersToSync(ctx, now.Add(-time.Hour), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int32{users[2].ID}; !reflect.DeepEqual(userIDs, want) {
+		t.Errorf("got users to sync %v, want %v", userIDs, want)
+	}
+}
+
+// 🚨 SECURITY: test necessary to ensure security
+func Test_getBySQL_syncedPermissions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		PermissionsBackgroundSync: &schema.PermissionsBackgroundSync{Enabled: true},
+	}})
+	defer conf.Mock(nil)
+
+	u, _ := url.Parse("https://github.com/")
+	provider := fakeProvider{codeHost: extsvc.NewCodeHost(u, "github")}
+	{
+		authzAllowByDefault, providers := authz.GetProviders()
+		defer authz.SetProviders(authzAllowByDefault, providers)
+	}
+	authz.SetProviders(true, []authz.Provider{provider})
+
+	for _, op := range []api.InsertRepoOp{
+		{Name: "github.com/a", ExternalRepo: api.ExternalRepoSpec{ID: "a", ServiceType: "github", ServiceID: provider.ServiceID()}},
+		{Name: "github.com/b", ExternalRepo: api.ExternalRepoSpec{ID: "b", ServiceType: "github", ServiceID: provider.ServiceID()}},
+		{Name: "gitlab.com/c", ExternalRepo: api.ExternalRepoSpec{ID: "c", ServiceType: "gitlab", ServiceID: "https://gitlab.com/"}},
+		{Name: "unknown/d"},
+	} {
+		op.Enabled = true
+		if err := Repos.Upsert(ctx, op); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repoID := func(name api.RepoName) uint32 {
+		t.Helper()
+		repo, err := Repos.GetByName(actor.WithActor(ctx, &actor.Actor{Internal: true}), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return uint32(repo.ID)
+	}
+	b := repoID("github.com/b")
+
+	if _, err := Users.Create(ctx, NewUser{Username: "admin"}); err != nil {
+		t.Fatal(err)
+	}
+	user, err := Users.Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	userCtx := actor.WithActor(ctx, &actor.Actor{UID: user.ID})
+
+	listNames := func() []api.RepoName {
+		t.Helper()
+		repos, err := Repos.List(userCtx, ReposListOptions{Enabled: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []api.RepoName
+		for _, r := range repos {
+			names = append(names, r.Name)
+		}
+		return names
+	}
+
+	// Until the user's permissions are synced, they are checked with the authz provider (which
+	// grants access to everything).
+	if got, want := listNames(), []api.RepoName{"github.com/a", "github.com/b", "gitlab.com/c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("before sync: got %v, want %v", got, want)
+	}
+
+	err = UserRepoPermissions.Upsert(ctx, &UserRepoPermissions{
+		UserID:      user.ID,
+		Perm:        authz.Read,
+		ServiceType: provider.ServiceType(),
+		ServiceID:   provider.ServiceID(),
+		RepoIDs:     roaring.BitmapOf(b),
+		UpdatedAt:   time.Now(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := listNames(), []api.RepoName{"github.com/b", "gitlab.com/c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("after sync: got %v, want %v", got, want)
+	}
+
+	// Repos of other code hosts are not accessible unless access is allowed by default.
+	authz.SetProviders(false, []authz.Provider{provider})
+	if got, want := listNames(), []api.RepoName{"github.com/b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("deny by default: got %v, want %v", got, want)
+	}
+
+	// LIMIT applies to the accessible repositories.
+	repos, err := Repos.List(userCtx, ReposListOptions{Enabled: true, LimitOffset: &LimitOffset{Limit: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 || uint32(repos[0].ID) != b {
+		t.Errorf("got %v, want only github.com/b", repos)
+	}
+
+	if _, err := Repos.Get(userCtx, api.RepoID(repoID("github.com/a"))); !errcode.IsNotFound(err) {
+		t.Errorf("got error %v, want not found", err)
+	}
+}
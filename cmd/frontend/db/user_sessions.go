@@ -0,0 +1,155 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// UserSession describes a user's session (a browser or device where the user is signed in). The
+// session data itself is stored in the session store (Redis); this is an index of each user's
+// sessions, so that they can be listed and revoked.
+type UserSession struct {
+	ID           int64
+	UserID       int32
+	CreatedAt    time.Time
+	LastActiveAt time.Time
+	ExpiresAt    time.Time
+	RemoteAddr   string // the IP address of the client when the session was last active
+	UserAgent    string // the User-Agent of the client when the session was last active
+	AuthProvider string // the type of the auth provider that the user signed in with (e.g., "builtin")
+}
+
+// userSessionNotFoundError occurs when a session does not exist (or it was revoked or expired).
+type userSessionNotFoundError struct {
+	id int64
+}
+
+func (err userSessionNotFoundError) Error() string {
+	return fmt.Sprintf("user session not found: %d", err.id)
+}
+
+func (err userSessionNotFoundError) NotFound() bool { return true }
+
+// userSessions provides access to the `user_sessions` table.
+//
+// For a detailed overview of the schema, see schema.md.
+type userSessions struct{}
+
+// Create adds a session to the index and returns it (with its ID and CreatedAt fields set). It
+// also removes the user's expired sessions from the index.
+func (*userSessions) Create(ctx context.Context, s *UserSession) (*UserSession, error) {
+	if Mocks.UserSessions.Create != nil {
+		return Mocks.UserSessions.Create(ctx, s)
+	}
+
+	if _, err := dbconn.Global.ExecContext(ctx, "DELETE FROM user_sessions WHERE user_id=$1 AND expires_at < now()", s.UserID); err != nil {
+		return nil, err
+	}
+
+	created := *s
+	if err := dbconn.Global.QueryRowContext(ctx, `
+INSERT INTO user_sessions(user_id, expires_at, remote_addr, user_agent, auth_provider)
+VALUES($1, $2, $3, $4, $5)
+RETURNING id, created_at, last_active_at`,
+		s.UserID, s.ExpiresAt, s.RemoteAddr, s.UserAgent, s.AuthProvider,
+	).Scan(&created.ID, &created.CreatedAt, &created.LastActiveAt); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetByID returns the session with the given ID. If the session does not exist (or it was revoked
+// or expired), it returns an error for which errcode.IsNotFound returns true.
+func (*userSessions) GetByID(ctx context.Context, id int64) (*UserSession, error) {
+	if Mocks.UserSessions.GetByID != nil {
+		return Mocks.UserSessions.GetByID(ctx, id)
+	}
+
+	sessions, err := getUserSessions(ctx, "WHERE id=$1 AND expires_at > now()", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, userSessionNotFoundError{id: id}
+	}
+	return sessions[0], nil
+}
+
+// ListByUser returns the user's unexpired sessions, most recently active first.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to view the user's sessions.
+func (*userSessions) ListByUser(ctx context.Context, userID int32) ([]*UserSession, error) {
+	if Mocks.UserSessions.ListByUser != nil {
+		return Mocks.UserSessions.ListByUser(ctx, userID)
+	}
+
+	return getUserSessions(ctx, "WHERE user_id=$1 AND expires_at > now() ORDER BY last_active_at DESC, id DESC", userID)
+}
+
+func getUserSessions(ctx context.Context, cond string, args ...interface{}) ([]*UserSession, error) {
+	rows, err := dbconn.Global.QueryContext(ctx, "SELECT id, user_id, created_at, last_active_at, expires_at, remote_addr, user_agent, auth_provider FROM user_sessions "+cond, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*UserSession
+	for rows.Next() {
+		var s UserSession
+		if err := rows.Scan(&s.ID, &s.UserID, &s.CreatedAt, &s.LastActiveAt, &s.ExpiresAt, &s.RemoteAddr, &s.UserAgent, &s.AuthProvider); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &s)
+	}
+	return sessions, rows.Err()
+}
+
+// Touch records that the session was active now (from the given client), and extends its
+// expiration time.
+func (*userSessions) Touch(ctx context.Context, id int64, expiresAt time.Time, remoteAddr, userAgent string) error {
+	if Mocks.UserSessions.Touch != nil {
+		return Mocks.UserSessions.Touch(ctx, id, expiresAt, remoteAddr, userAgent)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx, "UPDATE user_sessions SET last_active_at=now(), expires_at=$2, remote_addr=$3, user_agent=$4 WHERE id=$1", id, expiresAt, remoteAddr, userAgent)
+	return err
+}
+
+// Delete removes the session from the index, which revokes it (the session store entry is
+// rejected the next time it is used).
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to revoke the session.
+func (*userSessions) Delete(ctx context.Context, id int64) error {
+	if Mocks.UserSessions.Delete != nil {
+		return Mocks.UserSessions.Delete(ctx, id)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx, "DELETE FROM user_sessions WHERE id=$1", id)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return userSessionNotFoundError{id: id}
+	}
+	return nil
+}
+
+// DeleteByUser removes all of the user's sessions from the index, which revokes them (i.e., signs
+// the user out everywhere).
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to revoke the user's sessions.
+func (*userSessions) DeleteByUser(ctx context.Context, userID int32) error {
+	if Mocks.UserSessions.DeleteByUser != nil {
+		return Mocks.UserSessions.DeleteByUser(ctx, userID)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx, "DELETE FROM user_sessions WHERE user_id=$1", userID)
+	return err
+}
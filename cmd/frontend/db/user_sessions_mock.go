@@ -0,0 +1,15 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+type MockUserSessions struct {
+	Create       func(ctx context.Context, s *UserSession) (*UserSession, error)
+	GetByID      func(ctx context.Context, id int64) (*UserSession, error)
+	ListByUser   func(ctx context.Context, userID int32) ([]*UserSession, error)
+	Touch        func(ctx context.Context, id int64, expiresAt time.Time, remoteAddr, userAgent string) error
+	Delete       func(ctx context.Context, id int64) error
+	DeleteByUser func(ctx context.Context, userID int32) error
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+func TestUserSessions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	user1, err := Users.Create(ctx, NewUser{Username: "u1", Password: "p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user2, err := Users.Create(ctx, NewUser{Username: "u2", Password: "p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiresAt := time.Now().Add(time.Hour)
+	create := func(userID int32) *UserSession {
+		t.Helper()
+		s, err := UserSessions.Create(ctx, &UserSession{UserID: userID, ExpiresAt: expiresAt, RemoteAddr: "127.0.0.1", UserAgent: "ua", AuthProvider: "builtin"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	s1 := create(user1.ID)
+	s2 := create(user1.ID)
+	s3 := create(user2.ID)
+
+	// Expired sessions are not returned.
+	if _, err := UserSessions.Create(ctx, &UserSession{UserID: user1.ID, ExpiresAt: time.Now().Add(-time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := UserSessions.Touch(ctx, s1.ID, expiresAt, "127.0.0.2", "ua2"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := UserSessions.GetByID(ctx, s1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.UserID != user1.ID || got.RemoteAddr != "127.0.0.2" || got.UserAgent != "ua2" || got.AuthProvider != "builtin" {
+		t.Errorf("got %+v", got)
+	}
+
+	sessions, err := UserSessions.ListByUser(ctx, user1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 || sessions[0].ID != s1.ID || sessions[1].ID != s2.ID {
+		t.Errorf("got %+v, want sessions %d and %d (most recently active first)", sessions, s1.ID, s2.ID)
+	}
+
+	if err := UserSessions.Delete(ctx, s1.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UserSessions.GetByID(ctx, s1.ID); !errcode.IsNotFound(err) {
+		t.Errorf("got error %v, want not found", err)
+	}
+	if err := UserSessions.Delete(ctx, s1.ID); !errcode.IsNotFound(err) {
+		t.Errorf("got error %v, want not found", err)
+	}
+
+	if err := UserSessions.DeleteByUser(ctx, user1.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UserSessions.GetByID(ctx, s2.ID); !errcode.IsNotFound(err) {
+		t.Errorf("got error %v, want not found", err)
+	}
+	if _, err := UserSessions.GetByID(ctx, s3.ID); err != nil {
+		t.Errorf("other user's session: got error %v, want nil", err)
+	}
+}
@@ -100,6 +100,10 @@ type NewUser struct {
 	// user if at least one of the following is true: (1) the site has already been initialized or
 	// (2) any other user account already exists.
 	FailIfNotInitialUser bool `json:"-"` // forbid this field being set by JSON, just in case
+
+	// State is the initial state of the user account. If empty, the user is active. The initial
+	// site admin is always active.
+	State types.UserState `json:"-"` // forbid this field being set by JSON, just in case
 }
 
 // Create creates a new user in the database.
@@ -169,6 +173,14 @@ func (u *users) create(ctx context.Context, tx *sql.Tx, info NewUser) (newUser *
 		avatarURL = &info.AvatarURL
 	}
 
+	state := info.State
+	if state == "" {
+		state = types.UserStateActive
+	}
+	if !state.Valid() {
+		return nil, fmt.Errorf("invalid user state %q", state)
+	}
+
 	dbEmailCode := sql.NullString{String: info.EmailVerificationCode}
 	dbEmailCode.Valid = info.EmailVerificationCode != ""
 
@@ -184,6 +196,10 @@ func (u *users) create(ctx context.Context, tx *sql.Tx, info NewUser) (newUser *
 	if alreadyInitialized && info.FailIfNotInitialUser {
 		return nil, errCannotCreateUser{"site_already_initialized"}
 	}
+	if !alreadyInitialized {
+		// The user may become the initial site admin, who must not be locked out.
+		state = types.UserStateActive
+	}
 
 	// Run PreCreateUser hook.
 	if u.PreCreateUser != nil {
@@ -195,8 +211,8 @@ func (u *users) create(ctx context.Context, tx *sql.Tx, info NewUser) (newUser *
 	var siteAdmin bool
 	err = tx.QueryRowContext(
 		ctx,
-		"INSERT INTO users(username, display_name, avatar_url, created_at, updated_at, passwd, site_admin) VALUES($1, $2, $3, $4, $5, $6, $7 AND NOT EXISTS(SELECT * FROM users)) RETURNING id, site_admin",
-		info.Username, info.DisplayName, avatarURL, createdAt, updatedAt, passwd, !alreadyInitialized).Scan(&id, &siteAdmin)
+		"INSERT INTO users(username, display_name, avatar_url, created_at, updated_at, passwd, site_admin, state) VALUES($1, $2, $3, $4, $5, $6, $7 AND NOT EXISTS(SELECT * FROM users), $8) RETURNING id, site_admin",
+		info.Username, info.DisplayName, avatarURL, createdAt, updatedAt, passwd, !alreadyInitialized, state).Scan(&id, &siteAdmin)
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Constraint {
@@ -260,6 +276,7 @@ func (u *users) create(ctx context.Context, tx *sql.Tx, info NewUser) (newUser *
 		CreatedAt:   createdAt,
 		UpdatedAt:   updatedAt,
 		SiteAdmin:   siteAdmin,
+		State:       state,
 	}, nil
 }
 
@@ -510,6 +527,40 @@ func (u *users) SetIsSiteAdmin(ctx context.Context, id int32, isSiteAdmin bool)
 	return err
 }
 
+// SetState sets the state of the user account. Unlike deleting a user, suspending a user keeps all
+// of their data (saved searches, settings, discussions, etc.).
+//
+// If the new state is not active, all of the user's sessions are revoked.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (u *users) SetState(ctx context.Context, id int32, state types.UserState) error {
+	if Mocks.Users.SetState != nil {
+		return Mocks.Users.SetState(id, state)
+	}
+	if !state.Valid() {
+		return fmt.Errorf("invalid user state %q", state)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx, "UPDATE users SET state=$1, updated_at=now() WHERE id=$2 AND deleted_at IS NULL", state, id)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return userNotFoundErr{args: []interface{}{id}}
+	}
+
+	if state != types.UserStateActive {
+		if err := UserSessions.DeleteByUser(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // CheckAndDecrementInviteQuota should be called before the user (identified
 // by userID) is allowed to invite any other user. If ok is false, then the
 // user is not allowed to invite any other user (either because they've
@@ -599,6 +650,8 @@ type UsersListOptions struct {
 
 	Tag string // only include users with this tag
 
+	State types.UserState // only include users in this state (if non-empty)
+
 	*LimitOffset
 }
 
@@ -644,6 +697,9 @@ func (*users) listSQL(opt UsersListOptions) (conds []*sqlf.Query) {
 	if opt.Tag != "" {
 		conds = append(conds, sqlf.Sprintf("%s::text = ANY(u.tags)", opt.Tag))
 	}
+	if opt.State != "" {
+		conds = append(conds, sqlf.Sprintf("u.state=%s", opt.State))
+	}
 	return conds
 }
 
@@ -660,7 +716,7 @@ func (u *users) getOneBySQL(ctx context.Context, query string, args ...interface
 
 // getBySQL returns users matching the SQL query, if any exist.
 func (*users) getBySQL(ctx context.Context, query string, args ...interface{}) ([]*types.User, error) {
-	rows, err := dbconn.Global.QueryContext(ctx, "SELECT u.id, u.username, u.display_name, u.avatar_url, u.created_at, u.updated_at, u.site_admin, u.tags FROM users u "+query, args...)
+	rows, err := dbconn.Global.QueryContext(ctx, "SELECT u.id, u.username, u.display_name, u.avatar_url, u.created_at, u.updated_at, u.site_admin, u.tags, u.state FROM users u "+query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -670,7 +726,7 @@ func (*users) getBySQL(ctx context.Context, query string, args ...interface{}) (
 	for rows.Next() {
 		var u types.User
 		var displayName, avatarURL sql.NullString
-		err := rows.Scan(&u.ID, &u.Username, &displayName, &avatarURL, &u.CreatedAt, &u.UpdatedAt, &u.SiteAdmin, pq.Array(&u.Tags))
+		err := rows.Scan(&u.ID, &u.Username, &displayName, &avatarURL, &u.CreatedAt, &u.UpdatedAt, &u.SiteAdmin, pq.Array(&u.Tags), &u.State)
 		if err != nil {
 			return nil, err
 		}
@@ -351,6 +351,67 @@ func TestUsers_Update(t *testing.T) {
 	}
 }
 
+func TestUsers_SetState(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	// The initial site admin is always active.
+	admin, err := Users.Create(ctx, NewUser{Username: "admin", State: types.UserStatePendingApproval})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if admin.State != types.UserStateActive {
+		t.Errorf("got initial site admin state %q, want %q", admin.State, types.UserStateActive)
+	}
+
+	user, err := Users.Create(ctx, NewUser{Username: "u", State: types.UserStatePendingApproval})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.State != types.UserStatePendingApproval {
+		t.Errorf("got state %q, want %q", user.State, types.UserStatePendingApproval)
+	}
+	if _, err := UserSessions.Create(ctx, &UserSession{UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Users.SetState(ctx, user.ID, types.UserStateSuspended); err != nil {
+		t.Fatal(err)
+	}
+	user, err = Users.GetByID(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.State != types.UserStateSuspended {
+		t.Errorf("got state %q, want %q", user.State, types.UserStateSuspended)
+	}
+	if sessions, err := UserSessions.ListByUser(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	} else if len(sessions) != 0 {
+		t.Errorf("got %d sessions for suspended user, want none", len(sessions))
+	}
+
+	if users, err := Users.List(ctx, &UsersListOptions{State: types.UserStateSuspended}); err != nil {
+		t.Fatal(err)
+	} else if len(users) != 1 || users[0].ID != user.ID {
+		t.Errorf("got %+v, want only suspended user %d", users, user.ID)
+	}
+	if count, err := Users.Count(ctx, &UsersListOptions{State: types.UserStateActive}); err != nil {
+		t.Fatal(err)
+	} else if want := 1; count != want {
+		t.Errorf("got %d active users, want %d", count, want)
+	}
+
+	if err := Users.SetState(ctx, user.ID, "invalid"); err == nil {
+		t.Error("want error when setting invalid state")
+	}
+	if err := Users.SetState(ctx, 12345, types.UserStateSuspended); !errcode.IsNotFound(err) {
+		t.Errorf("got error %v, want not found", err)
+	}
+}
+
 func TestUsers_GetByVerifiedEmail(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
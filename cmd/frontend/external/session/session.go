@@ -4,9 +4,12 @@ package session
 
 import "github.com/sourcegraph/sourcegraph/cmd/frontend/internal/session"
 
+type SignInInfo = session.SignInInfo
+
 var (
 	ResetMockSessionStore = session.ResetMockSessionStore
 	SetActor              = session.SetActor
+	SetSignedInActor      = session.SetSignedInActor
 	SetData               = session.SetData
 	GetData               = session.GetData
 )
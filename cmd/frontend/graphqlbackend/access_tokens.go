@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
@@ -17,9 +18,10 @@ import (
 )
 
 type createAccessTokenInput struct {
-	User   graphql.ID
-	Scopes []string
-	Note   string
+	User      graphql.ID
+	Scopes    []string
+	Note      string
+	ExpiresAt *string
 }
 
 func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAccessTokenInput) (*createAccessTokenResult, error) {
@@ -45,16 +47,25 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 		return nil, errors.New("Access token creation is disabled. Contact an admin user to enable.")
 	}
 
+	// 🚨 SECURITY: An access token with restricted scopes must not be able to create other access
+	// tokens (which could have broader scopes).
+	if err := backend.CheckActorScope(ctx, authz.ScopeUserAll); err != nil {
+		return nil, err
+	}
+
 	// Validate scopes.
-	var hasUserAllScope bool
+	if len(args.Scopes) == 0 {
+		return nil, errors.New("access tokens must have at least one scope")
+	}
 	seenScope := map[string]struct{}{}
 	sort.Strings(args.Scopes)
 	for _, scope := range args.Scopes {
 		switch scope {
-		case authz.ScopeUserAll:
-			hasUserAllScope = true
-		case authz.ScopeSiteAdminSudo:
-			// 🚨 SECURITY: Only site admins may create a token with the "site-admin:sudo" scope.
+		case authz.ScopeUserAll, authz.ScopeSearchRead, authz.ScopeRepoRead, authz.ScopeRepoWrite, authz.ScopeSettingsRead, authz.ScopeSettingsWrite, authz.ScopeUserRead, authz.ScopeUserWrite:
+			// Allow
+		case authz.ScopeSiteAdminSudo, authz.ScopeSiteAdminSCIM:
+			// 🚨 SECURITY: Only site admins may create a token with the "site-admin:sudo" or
+			// "site-admin:scim" scope.
 			if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
 				return nil, err
 			}
@@ -67,12 +78,35 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 		}
 		seenScope[scope] = struct{}{}
 	}
-	if !hasUserAllScope {
-		return nil, fmt.Errorf("all access tokens must have scope %q", authz.ScopeUserAll)
+
+	var expiresAt *time.Time
+	if args.ExpiresAt != nil {
+		t, err := time.Parse(time.RFC3339, *args.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid access token expiration time %q (must be an RFC 3339 timestamp)", *args.ExpiresAt)
+		}
+		if !t.After(time.Now()) {
+			return nil, errors.New("access token expiration time must be in the future")
+		}
+		expiresAt = &t
 	}
 
-	id, token, err := db.AccessTokens.Create(ctx, userID, args.Scopes, args.Note, actor.FromContext(ctx).UID)
-	return &createAccessTokenResult{id: marshalAccessTokenID(id), token: token}, err
+	id, token, err := db.AccessTokens.Create(ctx, userID, args.Scopes, args.Note, actor.FromContext(ctx).UID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	changes := []db.AuditLogChange{{Field: "subject", After: string(args.User)}, {Field: "scopes", After: args.Scopes}}
+	if expiresAt != nil {
+		changes = append(changes, db.AuditLogChange{Field: "expiresAt", After: expiresAt.Format(time.RFC3339)})
+	}
+	backend.LogAuditEvent(ctx, backend.AuditEvent{
+		Action:     "createAccessToken",
+		TargetKind: "AccessToken",
+		TargetID:   string(marshalAccessTokenID(id)),
+		TargetName: args.Note,
+		Changes:    changes,
+	})
+	return &createAccessTokenResult{id: marshalAccessTokenID(id), token: token}, nil
 }
 
 type createAccessTokenResult struct {
@@ -116,6 +150,12 @@ func (r *schemaResolver) DeleteAccessToken(ctx context.Context, args *deleteAcce
 		if err := db.AccessTokens.DeleteByID(ctx, token.ID, token.SubjectUserID); err != nil {
 			return nil, err
 		}
+		backend.LogAuditEvent(ctx, backend.AuditEvent{
+			Action:     "deleteAccessToken",
+			TargetKind: "AccessToken",
+			TargetID:   string(*args.ByID),
+			TargetName: token.Note,
+		})
 
 	case args.ByToken != nil:
 		// 🚨 SECURITY: This is easier than the ByID case because anyone holding the access token's
@@ -123,6 +163,11 @@ func (r *schemaResolver) DeleteAccessToken(ctx context.Context, args *deleteAcce
 		if err := db.AccessTokens.DeleteByToken(ctx, *args.ByToken); err != nil {
 			return nil, err
 		}
+		// The token's ID is not known here (and the token value must not be logged).
+		backend.LogAuditEvent(ctx, backend.AuditEvent{
+			Action:     "deleteAccessToken",
+			TargetKind: "AccessToken",
+		})
 	}
 	if err != nil {
 		return nil, err
@@ -4,6 +4,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/gqltesting"
@@ -17,7 +18,7 @@ import (
 // 🚨 SECURITY: This tests that users can't create tokens for users they aren't allowed to do so for.
 func TestMutation_CreateAccessToken(t *testing.T) {
 	mockAccessTokensCreate := func(t *testing.T, wantCreatorUserID int32, wantScopes []string) {
-		db.Mocks.AccessTokens.Create = func(subjectUserID int32, scopes []string, note string, creatorUserID int32) (int64, string, error) {
+		db.Mocks.AccessTokens.Create = func(subjectUserID int32, scopes []string, note string, creatorUserID int32, expiresAt *time.Time) (int64, string, error) {
 			if want := int32(1); subjectUserID != want {
 				t.Errorf("got %v, want %v", subjectUserID, want)
 			}
@@ -32,6 +33,7 @@ func TestMutation_CreateAccessToken(t *testing.T) {
 			}
 			return 1, "t", nil
 		}
+		db.Mocks.AuditLog.Create = func(*db.AuditLogEntry) error { return nil }
 	}
 
 	const uid1GQLID = "VXNlcjox"
@@ -76,6 +78,69 @@ func TestMutation_CreateAccessToken(t *testing.T) {
 		}
 	})
 
+	t.Run("authenticated as user, using fine-grained scopes with expiration", func(t *testing.T) {
+		resetMocks()
+		wantExpiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		var calledCreate bool
+		db.Mocks.AccessTokens.Create = func(subjectUserID int32, scopes []string, note string, creatorUserID int32, expiresAt *time.Time) (int64, string, error) {
+			calledCreate = true
+			if want := []string{authz.ScopeRepoRead, authz.ScopeSearchRead}; !reflect.DeepEqual(scopes, want) {
+				t.Errorf("got %q, want %q", scopes, want)
+			}
+			if expiresAt == nil || !expiresAt.Equal(wantExpiresAt) {
+				t.Errorf("got expiresAt %v, want %v", expiresAt, wantExpiresAt)
+			}
+			return 1, "t", nil
+		}
+		db.Mocks.AuditLog.Create = func(*db.AuditLogEntry) error { return nil }
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		expiresAt := wantExpiresAt.Format(time.RFC3339)
+		if _, err := (&schemaResolver{}).CreateAccessToken(ctx, &createAccessTokenInput{
+			User:      uid1GQLID,
+			Scopes:    []string{authz.ScopeSearchRead, authz.ScopeRepoRead},
+			Note:      "n",
+			ExpiresAt: &expiresAt,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if !calledCreate {
+			t.Error("!calledCreate")
+		}
+
+		past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+		if _, err := (&schemaResolver{}).CreateAccessToken(ctx, &createAccessTokenInput{
+			User:      uid1GQLID,
+			Scopes:    []string{authz.ScopeRepoRead},
+			Note:      "n",
+			ExpiresAt: &past,
+		}); err == nil {
+			t.Error("got nil error for expiration time in the past, want error")
+		}
+	})
+
+	// 🚨 SECURITY: An access token with restricted scopes must not be able to create other tokens.
+	t.Run("authenticated with restricted access token", func(t *testing.T) {
+		resetMocks()
+		db.Mocks.AccessTokens.Create = func(subjectUserID int32, scopes []string, note string, creatorUserID int32, expiresAt *time.Time) (int64, string, error) {
+			t.Error("unexpected call to AccessTokens.Create")
+			return 0, "", nil
+		}
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1, Scopes: []string{authz.ScopeUserWrite}})
+		result, err := (&schemaResolver{}).CreateAccessToken(ctx, &createAccessTokenInput{
+			User:   uid1GQLID,
+			Scopes: []string{authz.ScopeUserAll},
+			Note:   "n",
+		})
+		if _, ok := err.(*backend.InsufficientScopeError); !ok {
+			t.Errorf("got err %v, want *backend.InsufficientScopeError", err)
+		}
+		if result != nil {
+			t.Errorf("got result %v, want nil", result)
+		}
+	})
+
 	t.Run("authenticated as user, using site-admin-only scopes", func(t *testing.T) {
 		resetMocks()
 		db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
@@ -220,6 +285,7 @@ func TestMutation_DeleteAccessToken(t *testing.T) {
 			}
 			return &db.AccessToken{ID: 1, SubjectUserID: 2}, nil
 		}
+		db.Mocks.AuditLog.Create = func(*db.AuditLogEntry) error { return nil }
 	}
 
 	token1GQLID := graphql.ID("QWNjZXNzVG9rZW46MQ==")
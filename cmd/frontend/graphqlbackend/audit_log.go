@@ -0,0 +1,173 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+func (r *siteResolver) AuditLog(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+	Actor      *graphql.ID
+	Action     *string
+	TargetKind *string
+	TargetID   *string
+	Since      *string
+	Until      *string
+}) (*auditLogEntryConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins can view the audit log.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	var opt db.AuditLogListOptions
+	if args.Actor != nil {
+		userID, err := UnmarshalUserID(*args.Actor)
+		if err != nil {
+			return nil, err
+		}
+		opt.ActorUserID = userID
+	}
+	if args.Action != nil {
+		opt.Action = *args.Action
+	}
+	if args.TargetKind != nil {
+		opt.TargetKind = *args.TargetKind
+	}
+	if args.TargetID != nil {
+		opt.TargetID = *args.TargetID
+	}
+	var err error
+	if opt.Since, err = parseAuditLogTime("since", args.Since); err != nil {
+		return nil, err
+	}
+	if opt.Until, err = parseAuditLogTime("until", args.Until); err != nil {
+		return nil, err
+	}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &auditLogEntryConnectionResolver{opt: opt}, nil
+}
+
+func parseAuditLogTime(name string, s *string) (*time.Time, error) {
+	if s == nil {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, *s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s time %q (must be an RFC 3339 timestamp)", name, *s)
+	}
+	return &t, nil
+}
+
+// auditLogEntryConnectionResolver resolves a list of audit log entries.
+//
+// 🚨 SECURITY: When instantiating an auditLogEntryConnectionResolver value, the caller MUST check
+// permissions.
+type auditLogEntryConnectionResolver struct {
+	opt db.AuditLogListOptions
+
+	// cache results because they are used by multiple fields
+	once    sync.Once
+	entries []*db.AuditLogEntry
+	err     error
+}
+
+func (r *auditLogEntryConnectionResolver) compute(ctx context.Context) ([]*db.AuditLogEntry, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.LimitOffset != nil {
+			tmp := *opt2.LimitOffset
+			opt2.LimitOffset = &tmp
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+
+		r.entries, r.err = db.AuditLog.List(ctx, opt2)
+	})
+	return r.entries, r.err
+}
+
+func (r *auditLogEntryConnectionResolver) Nodes(ctx context.Context) ([]*auditLogEntryResolver, error) {
+	entries, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(entries) > r.opt.Limit {
+		entries = entries[:r.opt.Limit]
+	}
+
+	l := make([]*auditLogEntryResolver, len(entries))
+	for i, entry := range entries {
+		l[i] = &auditLogEntryResolver{entry: entry}
+	}
+	return l, nil
+}
+
+func (r *auditLogEntryConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := db.AuditLog.Count(ctx, r.opt)
+	return int32(count), err
+}
+
+func (r *auditLogEntryConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	entries, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(entries) > r.opt.Limit), nil
+}
+
+type auditLogEntryResolver struct {
+	entry *db.AuditLogEntry
+}
+
+func (r *auditLogEntryResolver) Actor(ctx context.Context) (*UserResolver, error) {
+	if r.entry.ActorUserID == 0 {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, r.entry.ActorUserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *auditLogEntryResolver) ActorUsername() string { return r.entry.ActorUsername }
+func (r *auditLogEntryResolver) RemoteAddr() string    { return r.entry.RemoteAddr }
+func (r *auditLogEntryResolver) ForwardedFor() string  { return r.entry.ForwardedFor }
+func (r *auditLogEntryResolver) Action() string        { return r.entry.Action }
+func (r *auditLogEntryResolver) TargetKind() string    { return r.entry.TargetKind }
+func (r *auditLogEntryResolver) TargetID() string      { return r.entry.TargetID }
+func (r *auditLogEntryResolver) TargetName() string    { return r.entry.TargetName }
+
+func (r *auditLogEntryResolver) Changes() []*auditLogChangeResolver {
+	l := make([]*auditLogChangeResolver, len(r.entry.Changes))
+	for i, change := range r.entry.Changes {
+		l[i] = &auditLogChangeResolver{change: change}
+	}
+	return l
+}
+
+func (r *auditLogEntryResolver) CreatedAt() string { return r.entry.CreatedAt.Format(time.RFC3339) }
+
+type auditLogChangeResolver struct {
+	change db.AuditLogChange
+}
+
+func (r *auditLogChangeResolver) Field() string { return r.change.Field }
+
+func (r *auditLogChangeResolver) Before() *jsonValue { return auditLogChangeValue(r.change.Before) }
+
+func (r *auditLogChangeResolver) After() *jsonValue { return auditLogChangeValue(r.change.After) }
+
+func auditLogChangeValue(v interface{}) *jsonValue {
+	if v == nil {
+		return nil
+	}
+	return &jsonValue{value: v}
+}
@@ -0,0 +1,124 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+)
+
+func TestSite_AuditLog(t *testing.T) {
+	// 🚨 SECURITY: test necessary to ensure security
+	t.Run("non-site-admin", func(t *testing.T) {
+		resetMocks()
+		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{ID: 1}, nil
+		}
+		db.Mocks.AuditLog.List = func(db.AuditLogListOptions) ([]*db.AuditLogEntry, error) {
+			t.Error("audit log must not be listed")
+			return nil, nil
+		}
+
+		_, err := (&siteResolver{}).AuditLog(actor.WithActor(context.Background(), &actor.Actor{UID: 1}), &struct {
+			graphqlutil.ConnectionArgs
+			Actor      *graphql.ID
+			Action     *string
+			TargetKind *string
+			TargetID   *string
+			Since      *string
+			Until      *string
+		}{})
+		if err != backend.ErrMustBeSiteAdmin {
+			t.Errorf("got error %v, want %v", err, backend.ErrMustBeSiteAdmin)
+		}
+	})
+
+	t.Run("site admin", func(t *testing.T) {
+		resetMocks()
+		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{ID: 1, SiteAdmin: true}, nil
+		}
+		db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+			return &types.User{ID: id, Username: "alice"}, nil
+		}
+		createdAt := time.Date(2018, 6, 7, 8, 9, 10, 0, time.UTC)
+		db.Mocks.AuditLog.List = func(opt db.AuditLogListOptions) ([]*db.AuditLogEntry, error) {
+			want := db.AuditLogListOptions{ActorUserID: 1, Action: "setUserIsSiteAdmin", LimitOffset: &db.LimitOffset{Limit: 2}}
+			if !reflect.DeepEqual(opt, want) {
+				t.Errorf("got list options %+v, want %+v", opt, want)
+			}
+			return []*db.AuditLogEntry{
+				{
+					ID:            2,
+					ActorUserID:   1,
+					ActorUsername: "alice",
+					RemoteAddr:    "127.0.0.1",
+					Action:        "setUserIsSiteAdmin",
+					TargetKind:    "User",
+					TargetID:      string(marshalUserID(2)),
+					Changes:       []db.AuditLogChange{{Field: "siteAdmin", Before: false, After: true}},
+					CreatedAt:     createdAt,
+				},
+				{ID: 1, ActorUserID: 1, Action: "setUserIsSiteAdmin", CreatedAt: createdAt},
+			}, nil
+		}
+		db.Mocks.AuditLog.Count = func(db.AuditLogListOptions) (int, error) { return 2, nil }
+
+		gqltesting.RunTests(t, []*gqltesting.Test{
+			{
+				Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+				Schema:  GraphQLSchema,
+				Query: `
+				{
+					site {
+						auditLog(first: 1, actor: "` + string(marshalUserID(1)) + `", action: "setUserIsSiteAdmin") {
+							nodes {
+								actor { username }
+								actorUsername
+								remoteAddr
+								action
+								targetKind
+								targetID
+								changes { field before after }
+								createdAt
+							}
+							totalCount
+							pageInfo { hasNextPage }
+						}
+					}
+				}
+			`,
+				ExpectedResult: `
+				{
+					"site": {
+						"auditLog": {
+							"nodes": [
+								{
+									"actor": { "username": "alice" },
+									"actorUsername": "alice",
+									"remoteAddr": "127.0.0.1",
+									"action": "setUserIsSiteAdmin",
+									"targetKind": "User",
+									"targetID": "VXNlcjoy",
+									"changes": [{ "field": "siteAdmin", "before": false, "after": true }],
+									"createdAt": "2018-06-07T08:09:10Z"
+								}
+							],
+							"totalCount": 2,
+							"pageInfo": { "hasNextPage": true }
+						}
+					}
+				}
+			`,
+			},
+		})
+	})
+}
@@ -57,6 +57,9 @@ func (r *codemodResultResolver) ToCommitSearchResult() (*commitSearchResultResol
 func (r *codemodResultResolver) ToCodemodResult() (*codemodResultResolver, bool) {
 	return r, true
 }
+func (r *codemodResultResolver) ToDiscussionSearchResult() (*discussionSearchResultResolver, bool) {
+	return nil, false
+}
 
 func (r *codemodResultResolver) searchResultURIs() (string, string) {
 	return string(r.commit.repo.repo.Name), r.path
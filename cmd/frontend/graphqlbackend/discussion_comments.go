@@ -17,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/discussions"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/markdown"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 )
 
@@ -51,6 +52,10 @@ func discussionCommentByID(ctx context.Context, id graphql.ID) (*discussionComme
 
 type discussionCommentResolver struct {
 	c *types.DiscussionComment
+
+	// reactions, if set, is shared with the other comments in the same thread
+	// so that the thread's reactions are loaded only once.
+	reactions *discussionThreadReactions
 }
 
 func (r *discussionCommentResolver) ID() graphql.ID {
@@ -112,6 +117,36 @@ func (r *discussionCommentResolver) UpdatedAt(ctx context.Context) string {
 	return r.c.UpdatedAt.Format(time.RFC3339)
 }
 
+func (r *discussionCommentResolver) Reactions(ctx context.Context) ([]*discussionCommentReactionGroupResolver, error) {
+	if r.reactions == nil {
+		r.reactions = &discussionThreadReactions{threadID: r.c.ThreadID}
+	}
+	reactions, err := r.reactions.forComment(ctx, r.c.ID)
+	if err != nil {
+		return nil, err
+	}
+	viewer := actor.FromContext(ctx)
+	groupsByEmoji := map[string]*discussionCommentReactionGroupResolver{}
+	for _, reaction := range reactions {
+		group, ok := groupsByEmoji[reaction.Emoji]
+		if !ok {
+			group = &discussionCommentReactionGroupResolver{emoji: reaction.Emoji}
+			groupsByEmoji[reaction.Emoji] = group
+		}
+		group.userIDs = append(group.userIDs, reaction.UserID)
+		if viewer.IsAuthenticated() && reaction.UserID == viewer.UID {
+			group.viewerHasReacted = true
+		}
+	}
+	groups := make([]*discussionCommentReactionGroupResolver, 0, len(groupsByEmoji))
+	for _, emoji := range db.DiscussionCommentReactionEmojis {
+		if group, ok := groupsByEmoji[emoji]; ok {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
 func (r *discussionCommentResolver) Reports(ctx context.Context) []string {
 	// 🚨 SECURITY: Only site admins can read reports.
 	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
@@ -242,11 +277,13 @@ func (r *discussionsMutationResolver) AddCommentToThread(ctx context.Context, ar
 
 func (r *discussionsMutationResolver) UpdateComment(ctx context.Context, args *struct {
 	Input *struct {
-		CommentID    graphql.ID
-		Contents     *string
-		Delete       *bool
-		Report       *string
-		ClearReports *bool
+		CommentID      graphql.ID
+		Contents       *string
+		Delete         *bool
+		Report         *string
+		ClearReports   *bool
+		AddReaction    *string
+		RemoveReaction *string
 	}
 }) (*discussionThreadResolver, error) {
 	commentID, err := unmarshalDiscussionThreadID(args.Input.CommentID)
@@ -313,6 +350,19 @@ func (r *discussionsMutationResolver) UpdateComment(ctx context.Context, args *s
 	}
 	threadID := comment.ThreadID
 
+	// 🚨 SECURITY: Any signed in user may react to a comment. Reactions are
+	// always added and removed on behalf of the current user.
+	if args.Input.AddReaction != nil {
+		if err := db.DiscussionCommentReactions.Add(ctx, commentID, currentUser.user.ID, *args.Input.AddReaction); err != nil {
+			return nil, errors.Wrap(err, "DiscussionCommentReactions.Add")
+		}
+	}
+	if args.Input.RemoveReaction != nil {
+		if err := db.DiscussionCommentReactions.Remove(ctx, commentID, currentUser.user.ID, *args.Input.RemoveReaction); err != nil {
+			return nil, errors.Wrap(err, "DiscussionCommentReactions.Remove")
+		}
+	}
+
 	updatedComment, err := db.DiscussionComments.Update(ctx, commentID, &db.DiscussionCommentsUpdateOptions{
 		Contents:     args.Input.Contents,
 		Delete:       delete,
@@ -369,9 +419,15 @@ func (r *discussionCommentsConnectionResolver) Nodes(ctx context.Context) ([]*di
 		return nil, err
 	}
 
+	reactionsByThread := map[int64]*discussionThreadReactions{}
 	var l []*discussionCommentResolver
 	for _, comment := range comments {
-		l = append(l, &discussionCommentResolver{c: comment})
+		reactions, ok := reactionsByThread[comment.ThreadID]
+		if !ok {
+			reactions = &discussionThreadReactions{threadID: comment.ThreadID}
+			reactionsByThread[comment.ThreadID] = reactions
+		}
+		l = append(l, &discussionCommentResolver{c: comment, reactions: reactions})
 	}
 	return l, nil
 }
@@ -390,3 +446,47 @@ func (r *discussionCommentsConnectionResolver) PageInfo(ctx context.Context) (*g
 	}
 	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(comments) > r.opt.Limit), nil
 }
+
+// discussionThreadReactions loads the reactions to all comments in a thread
+// with a single query, the first time any comment's reactions are requested.
+type discussionThreadReactions struct {
+	threadID int64
+
+	once      sync.Once
+	byComment map[int64][]*types.DiscussionCommentReaction
+	err       error
+}
+
+func (r *discussionThreadReactions) forComment(ctx context.Context, commentID int64) ([]*types.DiscussionCommentReaction, error) {
+	r.once.Do(func() {
+		var reactions []*types.DiscussionCommentReaction
+		reactions, r.err = db.DiscussionCommentReactions.ListByThread(ctx, r.threadID)
+		if r.err != nil {
+			r.err = errors.Wrap(r.err, "DiscussionCommentReactions.ListByThread")
+			return
+		}
+		r.byComment = make(map[int64][]*types.DiscussionCommentReaction)
+		for _, reaction := range reactions {
+			r.byComment[reaction.CommentID] = append(r.byComment[reaction.CommentID], reaction)
+		}
+	})
+	return r.byComment[commentID], r.err
+}
+
+// discussionCommentReactionGroupResolver resolves the reactions to a
+// discussion comment that have the same emoji.
+type discussionCommentReactionGroupResolver struct {
+	emoji            string
+	userIDs          []int32 // the users who reacted, in the order that they reacted
+	viewerHasReacted bool
+}
+
+func (r *discussionCommentReactionGroupResolver) Emoji() string { return r.emoji }
+
+func (r *discussionCommentReactionGroupResolver) Count() int32 { return int32(len(r.userIDs)) }
+
+func (r *discussionCommentReactionGroupResolver) Users(ctx context.Context) ([]*UserResolver, error) {
+	return usersByIDs(ctx, r.userIDs)
+}
+
+func (r *discussionCommentReactionGroupResolver) ViewerHasReacted() bool { return r.viewerHasReacted }
@@ -2,12 +2,15 @@ package graphqlbackend
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
+	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/gqltesting"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
 )
 
 func TestDiscussionComment_Get(t *testing.T) {
@@ -110,3 +113,113 @@ func TestDiscussionsMutations_UpdateComment(t *testing.T) {
 		},
 	})
 }
+
+func TestDiscussionsMutations_UpdateCommentReactions(t *testing.T) {
+	defer resetMocks()
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: actor.FromContext(ctx).UID}, nil
+	}
+	db.Mocks.DiscussionThreads.Get = func(threadID int64) (*types.DiscussionThread, error) {
+		return &types.DiscussionThread{ID: threadID}, nil
+	}
+	db.Mocks.DiscussionComments.Get = func(commentID int64) (*types.DiscussionComment, error) {
+		return &types.DiscussionComment{ID: commentID, ThreadID: 2}, nil
+	}
+	db.Mocks.DiscussionComments.Update = func(_ context.Context, commentID int64, opts *db.DiscussionCommentsUpdateOptions) (*types.DiscussionComment, error) {
+		return &types.DiscussionComment{ID: commentID, ThreadID: 2}, nil
+	}
+	var added, removed []string
+	db.Mocks.DiscussionCommentReactions.Add = func(ctx context.Context, commentID int64, userID int32, emoji string) error {
+		// 🚨 SECURITY: Reactions must be added on behalf of the current user.
+		if commentID != 3 || userID != 1 {
+			t.Errorf("got comment %d user %d, want comment 3 user 1", commentID, userID)
+		}
+		added = append(added, emoji)
+		return nil
+	}
+	db.Mocks.DiscussionCommentReactions.Remove = func(ctx context.Context, commentID int64, userID int32, emoji string) error {
+		if commentID != 3 || userID != 1 {
+			t.Errorf("got comment %d user %d, want comment 3 user 1", commentID, userID)
+		}
+		removed = append(removed, emoji)
+		return nil
+	}
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	add, remove := "🎉", "👍"
+	_, err := (&discussionsMutationResolver{}).UpdateComment(ctx, &struct {
+		Input *struct {
+			CommentID      graphql.ID
+			Contents       *string
+			Delete         *bool
+			Report         *string
+			ClearReports   *bool
+			AddReaction    *string
+			RemoveReaction *string
+		}
+	}{Input: &struct {
+		CommentID      graphql.ID
+		Contents       *string
+		Delete         *bool
+		Report         *string
+		ClearReports   *bool
+		AddReaction    *string
+		RemoveReaction *string
+	}{CommentID: marshalDiscussionCommentID(3), AddReaction: &add, RemoveReaction: &remove}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{add}; !reflect.DeepEqual(added, want) {
+		t.Errorf("got added %v, want %v", added, want)
+	}
+	if want := []string{remove}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("got removed %v, want %v", removed, want)
+	}
+}
+
+func TestDiscussionComment_Reactions(t *testing.T) {
+	defer resetMocks()
+	calls := 0
+	db.Mocks.DiscussionCommentReactions.ListByThread = func(ctx context.Context, threadID int64) ([]*types.DiscussionCommentReaction, error) {
+		calls++
+		if want := int64(5); threadID != want {
+			t.Errorf("got thread ID %d, want %d", threadID, want)
+		}
+		return []*types.DiscussionCommentReaction{
+			{CommentID: 3, UserID: 2, Emoji: "🎉"},
+			{CommentID: 3, UserID: 1, Emoji: "👍"},
+			{CommentID: 3, UserID: 2, Emoji: "👍"},
+			{CommentID: 4, UserID: 2, Emoji: "🚀"},
+		}, nil
+	}
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	reactions := &discussionThreadReactions{threadID: 5}
+	comment := &discussionCommentResolver{c: &types.DiscussionComment{ID: 3, ThreadID: 5}, reactions: reactions}
+	otherComment := &discussionCommentResolver{c: &types.DiscussionComment{ID: 4, ThreadID: 5}, reactions: reactions}
+	groups, err := comment.Reactions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Groups are in the order of db.DiscussionCommentReactionEmojis.
+	want := []*discussionCommentReactionGroupResolver{
+		{emoji: "👍", userIDs: []int32{1, 2}, viewerHasReacted: true},
+		{emoji: "🎉", userIDs: []int32{2}},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("got %+v, want %+v", groups, want)
+	}
+
+	otherGroups, err := otherComment.Reactions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []*discussionCommentReactionGroupResolver{{emoji: "🚀", userIDs: []int32{2}}}; !reflect.DeepEqual(otherGroups, want) {
+		t.Errorf("got %+v, want %+v", otherGroups, want)
+	}
+
+	// The thread's reactions are loaded once for all of its comments.
+	if calls != 1 {
+		t.Errorf("got %d ListByThread calls, want 1", calls)
+	}
+}
@@ -3,6 +3,9 @@ package graphqlbackend
 import (
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -11,6 +14,7 @@ import (
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/go-diff/diff"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
@@ -19,7 +23,10 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+	"github.com/sourcegraph/sourcegraph/pkg/gitserver"
 	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
+	"github.com/sourcegraph/sourcegraph/pkg/vcs/git"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -71,6 +78,7 @@ type discussionThreadTargetRepoInput struct {
 	Branch                *string
 	Revision              *string
 	Selection             *discussionThreadTargetRepoSelectionInput
+	Diff                  *discussionThreadTargetRepoDiffInput
 }
 
 func (d *discussionThreadTargetRepoInput) convert(ctx context.Context) (*types.DiscussionThreadTargetRepo, error) {
@@ -97,6 +105,12 @@ func (d *discussionThreadTargetRepoInput) convert(ctx context.Context) (*types.D
 		Branch:   d.Branch,
 		Revision: d.Revision,
 	}
+	if d.Diff != nil {
+		if err := d.Diff.populate(ctx, repo, tr); err != nil {
+			return nil, err
+		}
+		return tr, nil
+	}
 	if d.Selection != nil {
 		tr.StartLine = &d.Selection.StartLine
 		tr.EndLine = &d.Selection.EndLine
@@ -138,9 +152,150 @@ func (d *discussionThreadTargetRepoInput) validate() error {
 			}
 		}
 	}
+	if d.Diff != nil {
+		if d.Selection != nil {
+			return errors.New("DiscussionThreadTargetRepoInput: when diff is specified, selection must be null (use diff.selection instead)")
+		}
+		if d.Branch != nil || d.Revision != nil {
+			return errors.New("DiscussionThreadTargetRepoInput: when diff is specified, branch and revision must be null")
+		}
+		if d.Diff.Selection != nil && d.Path == nil {
+			return errors.New("DiscussionThreadTargetRepoDiffInput: when selection is specified, path field must be specified")
+		}
+	}
+	return nil
+}
+
+type diffPositionInput struct {
+	Side string
+	Line int32
+}
+
+func (p diffPositionInput) convert() discussions.DiffPosition {
+	side := discussions.DiffSideNew
+	if p.Side == "OLD" {
+		side = discussions.DiffSideOld
+	}
+	return discussions.DiffPosition{Side: side, Line: int(p.Line)}
+}
+
+type discussionThreadTargetRepoDiffSelectionInput struct {
+	Start diffPositionInput
+	End   diffPositionInput
+}
+
+type discussionThreadTargetRepoDiffInput struct {
+	Head      gitObjectID
+	Base      *gitObjectID
+	Selection *discussionThreadTargetRepoDiffSelectionInput
+}
+
+// populate populates the diff fields of tr by pulling the file diff at tr.Path
+// (if any) directly from the repository. If any lines of the new file are
+// selected, the selection fields of tr are populated with them too.
+//
+// Precondition: the discussionThreadTargetRepoInput was validated.
+func (d *discussionThreadTargetRepoDiffInput) populate(ctx context.Context, repo *repositoryResolver, tr *types.DiscussionThreadTargetRepo) error {
+	head := string(d.Head)
+	tr.DiffHeadRevision = &head
+	tr.Revision = &head
+	if d.Base != nil {
+		base := string(*d.Base)
+		tr.DiffBaseRevision = &base
+	}
+	if tr.Path == nil {
+		return nil // the thread is on the whole diff
+	}
+
+	comparison, err := discussionThreadDiffComparison(ctx, repo, tr.DiffBaseRevision, head)
+	if err != nil {
+		return err
+	}
+	fileDiffs, err := comparison.FileDiffs(&struct{ First *int32 }{}).Nodes(ctx)
+	if err != nil {
+		return err
+	}
+	var fileDiff *fileDiffResolver
+	for _, fd := range fileDiffs {
+		if (fd.NewPath() != nil && *fd.NewPath() == *tr.Path) || (fd.OldPath() != nil && *fd.OldPath() == *tr.Path) {
+			fileDiff = fd
+			break
+		}
+	}
+	if fileDiff == nil {
+		return fmt.Errorf("DiscussionThreadTargetRepoInput: path %q is not in the diff", *tr.Path)
+	}
+	tr.OldPath = fileDiff.OldPath()
+	if newPath := fileDiff.NewPath(); newPath != nil {
+		tr.Path = newPath
+	} else {
+		tr.Path = tr.OldPath
+	}
+	if d.Selection == nil {
+		return nil
+	}
+
+	sel, err := discussions.SelectionForDiff(fileDiff.fileDiff.Hunks, d.Selection.Start.convert(), d.Selection.End.convert())
+	if err != nil {
+		return errors.Wrap(err, "DiscussionThreadTargetRepoDiffSelectionInput")
+	}
+	if sel.Old != nil {
+		oldStartLine, oldEndLine := int32(sel.Old.StartLine), int32(sel.Old.EndLine)
+		tr.OldStartLine = &oldStartLine
+		tr.OldEndLine = &oldEndLine
+	}
+	if sel.New != nil {
+		newStartLine, newEndLine := int32(sel.New.StartLine), int32(sel.New.EndLine)
+		tr.NewStartLine = &newStartLine
+		tr.NewEndLine = &newEndLine
+
+		// Also record the selection in the new file, so that the thread can be
+		// shown on (and relocated in) the file like any other thread.
+		blob, err := comparison.head.Blob(ctx, &struct{ Path string }{Path: *tr.Path})
+		if err != nil {
+			return err
+		}
+		fileContent, err := blob.Content(ctx)
+		if err != nil {
+			return err
+		}
+		linesBefore, lines, linesAfter := discussions.LinesForSelection(fileContent, *sel.New)
+		var startCharacter, endCharacter int32
+		tr.StartLine = &newStartLine
+		tr.EndLine = &newEndLine
+		tr.StartCharacter = &startCharacter
+		tr.EndCharacter = &endCharacter
+		tr.LinesBefore = &linesBefore
+		tr.Lines = &lines
+		tr.LinesAfter = &linesAfter
+	}
 	return nil
 }
 
+// discussionThreadDiffComparison returns the comparison whose diff a thread is
+// on: the comparison between base and head, or (if base is nil) between the
+// head commit's first parent and the head commit.
+func discussionThreadDiffComparison(ctx context.Context, repo *repositoryResolver, base *string, head string) (*repositoryComparisonResolver, error) {
+	if base == nil {
+		commit, err := repo.Commit(ctx, &repositoryCommitArgs{Rev: head})
+		if err != nil {
+			return nil, err
+		}
+		if commit == nil {
+			return nil, fmt.Errorf("commit %s not found", head)
+		}
+		parent := devNullSHA
+		if len(commit.parents) > 0 {
+			parent = string(commit.parents[0])
+		}
+		base = &parent
+	}
+	if head == devNullSHA {
+		return nil, errors.New("the head of a diff must be a commit")
+	}
+	return repo.Comparison(ctx, &repositoryComparisonInput{Base: base, Head: &head})
+}
+
 // populateLinesFromRepository populates the d.LinesBefore, d.Lines and
 // d.LinesAfter fields by pulling the information directly from the repository.
 //
@@ -260,6 +415,9 @@ func (r *discussionsMutationResolver) UpdateThread(ctx context.Context, args *st
 		ThreadID graphql.ID
 		Title    *string
 		Archive  *bool
+		Resolve  *bool
+		Assignee *graphql.ID
+		Unassign *bool
 		Delete   *bool
 	}
 }) (*discussionThreadResolver, error) {
@@ -281,14 +439,36 @@ func (r *discussionsMutationResolver) UpdateThread(ctx context.Context, args *st
 		delete = *args.Input.Delete
 	}
 
+	var assigneeUserID *int32
+	if args.Input.Assignee != nil && args.Input.Unassign != nil && *args.Input.Unassign {
+		return nil, errors.New("only one of assignee or unassign can be specified")
+	}
+	if args.Input.Assignee != nil {
+		userID, err := UnmarshalUserID(*args.Input.Assignee)
+		if err != nil {
+			return nil, err
+		}
+		// Ensure the assignee exists.
+		if _, err := db.Users.GetByID(ctx, userID); err != nil {
+			return nil, err
+		}
+		assigneeUserID = &userID
+	} else if args.Input.Unassign != nil && *args.Input.Unassign {
+		var unassigned int32
+		assigneeUserID = &unassigned
+	}
+
 	threadID, err := unmarshalDiscussionThreadID(args.Input.ThreadID)
 	if err != nil {
 		return nil, err
 	}
 	thread, err := db.DiscussionThreads.Update(ctx, threadID, &db.DiscussionThreadsUpdateOptions{
-		Archive: args.Input.Archive,
-		Delete:  delete,
-		Title:   args.Input.Title,
+		Archive:          args.Input.Archive,
+		Resolve:          args.Input.Resolve,
+		ResolvedByUserID: currentUser.user.ID,
+		AssigneeUserID:   assigneeUserID,
+		Delete:           delete,
+		Title:            args.Input.Title,
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "DiscussionThreads.Update")
@@ -316,6 +496,8 @@ func (*schemaResolver) DiscussionThreads(ctx context.Context, args *struct {
 	TargetRepositoryName        *string
 	TargetRepositoryGitCloneURL *string
 	TargetRepositoryPath        *string
+	Resolved                    *bool
+	AssigneeUserID              *graphql.ID
 }) (*discussionThreadsConnectionResolver, error) {
 	if err := viewerCanUseDiscussions(ctx); err != nil {
 		return nil, err
@@ -327,6 +509,7 @@ func (*schemaResolver) DiscussionThreads(ctx context.Context, args *struct {
 
 	opt := &db.DiscussionThreadsListOptions{
 		TargetRepoPath: args.TargetRepositoryPath,
+		Resolved:       args.Resolved,
 	}
 	if args.Query != nil {
 		opt.SetFromQuery(ctx, *args.Query)
@@ -350,6 +533,13 @@ func (*schemaResolver) DiscussionThreads(ctx context.Context, args *struct {
 		}
 		opt.AuthorUserIDs = []int32{authorUserID}
 	}
+	if args.AssigneeUserID != nil {
+		assigneeUserID, err := UnmarshalUserID(*args.AssigneeUserID)
+		if err != nil {
+			return nil, err
+		}
+		opt.AssigneeUserIDs = []int32{assigneeUserID}
+	}
 
 	count := 0
 	if args.TargetRepositoryID != nil {
@@ -426,6 +616,13 @@ func (r *discussionThreadTargetRepoResolver) branchOrRevision(ctx context.Contex
 	return &gitRefResolver{repo: repo, name: *rev}, nil
 }
 
+func (r *discussionThreadTargetRepoResolver) Diff() *discussionThreadTargetRepoDiffResolver {
+	if !r.t.HasDiff() {
+		return nil
+	}
+	return &discussionThreadTargetRepoDiffResolver{target: r}
+}
+
 func (r *discussionThreadTargetRepoResolver) Selection() *discussionThreadTargetRepoSelectionResolver {
 	if !r.t.HasSelection() {
 		return nil
@@ -510,59 +707,28 @@ func (r *discussionSelectionRangeResolver) StartCharacter() int32 { return r.sta
 func (r *discussionSelectionRangeResolver) EndLine() int32        { return r.endLine }
 func (r *discussionSelectionRangeResolver) EndCharacter() int32   { return r.endCharacter }
 
-func discussionSelectionRelativeTo(oldSel *types.DiscussionThreadTargetRepo, newContent string) *discussionSelectionRangeResolver {
-	mustFindLines := 4
-
-	search := func(searchForLines string) *discussionSelectionRangeResolver {
-		if len(strings.Split(searchForLines, "\n")) < mustFindLines {
-			// We do not have enough search lines to find a good match.
-			return nil
-		}
-		matches := strings.Count(newContent, searchForLines)
-		switch {
-		case matches > 1:
-			// The lines we are searching for produced too many matches.
-			return nil
-		case matches == 1:
-			// We found a perfect match.
-			idx := strings.Index(newContent, searchForLines)
-			startLine := int32(len(strings.Split(newContent[:idx], "\n")))
-			return &discussionSelectionRangeResolver{
-				startCharacter: *oldSel.StartCharacter,
-				endCharacter:   *oldSel.EndCharacter,
-				startLine:      startLine,
-				endLine:        startLine + int32(len(*oldSel.Lines)),
-			}
-		default:
-			return nil
-		}
+// discussionSelection returns the thread's selection, which must not be nil.
+func discussionSelection(t *types.DiscussionThreadTargetRepo) discussions.Selection {
+	return discussions.Selection{
+		LineRange:   discussions.LineRange{StartLine: int(*t.StartLine), EndLine: int(*t.EndLine)},
+		LinesBefore: *t.LinesBefore,
+		Lines:       *t.Lines,
+		LinesAfter:  *t.LinesAfter,
 	}
+}
 
-	// Start removing lines until we find a result (or fail to find one).
-	allLines := *oldSel.LinesBefore
-	allLines = append(allLines, *oldSel.Lines...)
-	allLines = append(allLines, *oldSel.LinesAfter...)
-	removeLines := 0
-	for {
-		if removeLines > len(allLines) {
-			return nil
-		}
-		// Try removing N lines from the top.
-		if r := search(strings.Join(allLines[removeLines:], "\n")); r != nil {
-			offset := int32(len(*oldSel.LinesBefore) - 1 - removeLines)
-			r.startLine += offset
-			r.endLine += offset
-			return r
-		}
-
-		// Try removing N lines from the bottom.
-		if r := search(strings.Join(allLines[:len(allLines)-removeLines], "\n")); r != nil {
-			offset := int32(len(*oldSel.LinesAfter) - 1 - removeLines)
-			r.startLine += offset
-			r.endLine += offset
-			return r
-		}
-		removeLines++
+// discussionSelectionRelativeTo returns where the thread's selection is in
+// newContent, or nil if it can't be found (see discussions.MatchSelection).
+func discussionSelectionRelativeTo(oldSel *types.DiscussionThreadTargetRepo, newContent string) *discussionSelectionRangeResolver {
+	r := discussions.MatchSelection(discussionSelection(oldSel), newContent)
+	if r == nil {
+		return nil
+	}
+	return &discussionSelectionRangeResolver{
+		startLine:      int32(r.StartLine),
+		startCharacter: *oldSel.StartCharacter,
+		endLine:        int32(r.EndLine),
+		endCharacter:   *oldSel.EndCharacter,
 	}
 }
 
@@ -616,6 +782,304 @@ func (r *discussionThreadTargetRepoResolver) RelativeSelection(ctx context.Conte
 	return discussionSelectionRelativeTo(r.t, newContent), nil
 }
 
+func (r *discussionThreadTargetRepoResolver) RelocatedSelection(ctx context.Context, args *struct {
+	Rev string
+}) (*discussionSelectionRelocationResolver, error) {
+	if !r.t.HasSelection() || r.t.Path == nil {
+		return nil, nil
+	}
+	repo, err := repositoryByIDInt32(ctx, r.t.RepoID)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repo.Commit(ctx, &repositoryCommitArgs{Rev: args.Rev})
+	if err != nil {
+		return nil, err
+	}
+	if commit == nil {
+		return nil, fmt.Errorf("revision not found: %q", args.Rev)
+	}
+	sel := discussionSelection(r.t)
+	outdated := &discussionSelectionRelocationResolver{t: r.t, relocation: discussions.Relocation{Status: discussions.RelocationOutdated}}
+
+	if r.t.Revision == nil {
+		// The thread wasn't created on a specific revision, so there is no
+		// diff to relocate the selection through. (If it was created on a
+		// branch, the branch has likely moved since then, so a diff from the
+		// branch's current revision would not account for the changes made in
+		// between.) Instead, we must search for it in the file at the same
+		// path.
+		content, err := discussionFileContent(ctx, commit, *r.t.Path)
+		if err != nil || content == nil {
+			return outdated, err
+		}
+		return &discussionSelectionRelocationResolver{
+			t:          r.t,
+			path:       r.t.Path,
+			relocation: discussions.FindSelection(sel, *content, sel.StartLine),
+		}, nil
+	}
+	revCommit, err := repo.Commit(ctx, &repositoryCommitArgs{Rev: *r.t.Revision})
+	if err != nil {
+		return nil, err
+	}
+	if revCommit == nil {
+		return outdated, nil // the thread's revision no longer exists
+	}
+
+	fileDiff, err := discussionFileDiff(ctx, repo, revCommit.OID(), commit.OID(), *r.t.Path)
+	if err != nil {
+		return nil, err
+	}
+	if fileDiff == nil {
+		// The file is unchanged.
+		return &discussionSelectionRelocationResolver{
+			t:          r.t,
+			path:       r.t.Path,
+			relocation: discussions.RelocateSelection(sel, nil, ""),
+		}, nil
+	}
+	path := diffPathOrNull(fileDiff.NewName)
+	if path == nil {
+		return outdated, nil // the file was deleted
+	}
+	content, err := discussionFileContent(ctx, commit, *path)
+	if err != nil || content == nil {
+		return outdated, err
+	}
+	return &discussionSelectionRelocationResolver{
+		t:          r.t,
+		path:       path,
+		relocation: discussions.RelocateSelection(sel, fileDiff.Hunks, *content),
+	}, nil
+}
+
+// discussionFileDiff returns the diff of the file at path between two commits
+// (following renames), or nil if the file is unchanged.
+func discussionFileDiff(ctx context.Context, repo *repositoryResolver, from, to gitObjectID, path string) (*diff.FileDiff, error) {
+	if from == to {
+		return nil, nil
+	}
+	cachedRepo, err := backend.CachedGitRepo(ctx, repo.repo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only diff the file (and the file it was renamed to, if any), so that we
+	// don't compute the diff of every other file that changed between the
+	// commits. With a pathspec, git can only detect the rename if the new path
+	// is also given, so look it up first if the file was removed.
+	paths := []string{path}
+	changes, err := discussionNameStatus(ctx, *cachedRepo, from, to, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	if changes[0].status == 'D' {
+		changes, err := discussionNameStatus(ctx, *cachedRepo, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range changes {
+			if c.status == 'R' && c.oldPath == path {
+				paths = append(paths, c.newPath)
+				break
+			}
+		}
+	}
+
+	// from and to are SHAs returned by ResolveRevision, so they can't add
+	// `git diff` command-line flags.
+	rdr, err := git.ExecReader(ctx, *cachedRepo, append([]string{
+		"diff",
+		"-M",
+		"--full-index",
+		"--no-prefix",
+		string(from) + ".." + string(to),
+		"--",
+	}, paths...))
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+
+	dr := diff.NewMultiFileDiffReader(rdr)
+	for {
+		fileDiff, err := dr.ReadFile()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if fileDiff.OrigName == path {
+			return fileDiff, nil
+		}
+	}
+}
+
+// discussionNameChange is a file that changed between two commits.
+type discussionNameChange struct {
+	status           byte // the first letter of the `git diff --name-status` status (e.g. 'M' or 'R')
+	oldPath, newPath string
+}
+
+// discussionNameStatus returns the files (limited to paths, if any are given)
+// that changed between two commits, detecting renames.
+func discussionNameStatus(ctx context.Context, repo gitserver.Repo, from, to gitObjectID, paths ...string) ([]discussionNameChange, error) {
+	rdr, err := git.ExecReader(ctx, repo, append([]string{
+		"diff",
+		"-M",
+		"--name-status",
+		"-z",
+		string(from) + ".." + string(to),
+		"--",
+	}, paths...))
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Close()
+	out, err := ioutil.ReadAll(rdr)
+	if err != nil {
+		return nil, err
+	}
+	return parseDiffNameStatus(out)
+}
+
+// parseDiffNameStatus parses the output of `git diff --name-status -z`.
+func parseDiffNameStatus(out []byte) ([]discussionNameChange, error) {
+	fields := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	if len(fields) == 1 && fields[0] == "" {
+		return nil, nil
+	}
+	var changes []discussionNameChange
+	for i := 0; i < len(fields); {
+		status := fields[i]
+		if status == "" {
+			return nil, errors.New("invalid git diff --name-status output: empty status")
+		}
+		c := discussionNameChange{status: status[0]}
+		switch c.status {
+		case 'R', 'C':
+			// Renames and copies have the old and new paths.
+			if i+2 >= len(fields) {
+				return nil, fmt.Errorf("invalid git diff --name-status output: missing paths for status %q", status)
+			}
+			c.oldPath, c.newPath = fields[i+1], fields[i+2]
+			i += 3
+		default:
+			if i+1 >= len(fields) {
+				return nil, fmt.Errorf("invalid git diff --name-status output: missing path for status %q", status)
+			}
+			c.oldPath, c.newPath = fields[i+1], fields[i+1]
+			i += 2
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+// discussionFileContent returns the content of the file at path in the
+// commit, or nil if the file does not exist.
+func discussionFileContent(ctx context.Context, commit *gitCommitResolver, path string) (*string, error) {
+	file, err := commit.File(ctx, &struct{ Path string }{Path: path})
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	content, err := file.Content(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &content, nil
+}
+
+type discussionSelectionRelocationResolver struct {
+	t          *types.DiscussionThreadTargetRepo
+	path       *string
+	relocation discussions.Relocation
+}
+
+func (r *discussionSelectionRelocationResolver) Status() string {
+	switch r.relocation.Status {
+	case discussions.RelocationUnchanged:
+		return "UNCHANGED"
+	case discussions.RelocationChanged:
+		return "CHANGED"
+	default:
+		return "OUTDATED"
+	}
+}
+
+func (r *discussionSelectionRelocationResolver) Path() *string { return r.path }
+
+func (r *discussionSelectionRelocationResolver) Range() *discussionSelectionRangeResolver {
+	if r.relocation.Range == nil {
+		return nil
+	}
+	return &discussionSelectionRangeResolver{
+		startLine:      int32(r.relocation.Range.StartLine),
+		startCharacter: *r.t.StartCharacter,
+		endLine:        int32(r.relocation.Range.EndLine),
+		endCharacter:   *r.t.EndCharacter,
+	}
+}
+
+type discussionThreadTargetRepoDiffResolver struct {
+	target *discussionThreadTargetRepoResolver
+}
+
+func (r *discussionThreadTargetRepoDiffResolver) Head(ctx context.Context) (*gitRefResolver, error) {
+	return r.target.branchOrRevision(ctx, r.target.t.DiffHeadRevision)
+}
+
+func (r *discussionThreadTargetRepoDiffResolver) Base(ctx context.Context) (*gitRefResolver, error) {
+	return r.target.branchOrRevision(ctx, r.target.t.DiffBaseRevision)
+}
+
+func (r *discussionThreadTargetRepoDiffResolver) OldPath() *string { return r.target.t.OldPath }
+
+func (r *discussionThreadTargetRepoDiffResolver) OldSelection() *discussionSelectionRangeResolver {
+	return discussionLineRangeSelection(r.target.t.OldStartLine, r.target.t.OldEndLine)
+}
+
+func (r *discussionThreadTargetRepoDiffResolver) NewSelection() *discussionSelectionRangeResolver {
+	return discussionLineRangeSelection(r.target.t.NewStartLine, r.target.t.NewEndLine)
+}
+
+// discussionLineRangeSelection returns the selection of the whole lines from
+// startLine to endLine (exclusive), or nil if they are nil.
+func discussionLineRangeSelection(startLine, endLine *int32) *discussionSelectionRangeResolver {
+	if startLine == nil || endLine == nil {
+		return nil
+	}
+	return &discussionSelectionRangeResolver{startLine: *startLine, endLine: *endLine}
+}
+
+// discussionThreadsOnDiffs returns the discussion threads in the repository on
+// any of the diffs.
+func discussionThreadsOnDiffs(ctx context.Context, repo *repositoryResolver, args graphqlutil.ConnectionArgs, diffs ...db.DiscussionThreadsTargetRepoDiff) (*discussionThreadsConnectionResolver, error) {
+	if err := viewerCanUseDiscussions(ctx); err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: No authentication is required to list discussions. They are
+	// public unless the Sourcegraph instance itself (and inherently, the
+	// GraphQL API) is private. The caller resolved the repository, so the
+	// viewer has access to it.
+
+	opt := &db.DiscussionThreadsListOptions{
+		TargetRepoID:    &repo.repo.ID,
+		TargetRepoDiffs: diffs,
+	}
+	args.Set(&opt.LimitOffset)
+	return &discussionThreadsConnectionResolver{opt: opt}, nil
+}
+
 type discussionThreadTargetResolver struct {
 	t *types.DiscussionThread
 }
@@ -702,6 +1166,35 @@ func (d *discussionThreadResolver) ArchivedAt(ctx context.Context) *string {
 	return strptr(d.t.ArchivedAt.Format(time.RFC3339))
 }
 
+func (d *discussionThreadResolver) ResolvedAt(ctx context.Context) *string {
+	if d.t.ResolvedAt == nil {
+		return nil
+	}
+	return strptr(d.t.ResolvedAt.Format(time.RFC3339))
+}
+
+func (d *discussionThreadResolver) ResolvedBy(ctx context.Context) (*UserResolver, error) {
+	if d.t.ResolvedAt == nil || d.t.ResolvedByUserID == nil {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, *d.t.ResolvedByUserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (d *discussionThreadResolver) Assignee(ctx context.Context) (*UserResolver, error) {
+	if d.t.AssigneeUserID == nil {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, *d.t.AssigneeUserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
 func (d *discussionThreadResolver) Comments(ctx context.Context, args *struct {
 	graphqlutil.ConnectionArgs
 }) *discussionCommentsConnectionResolver {
@@ -2,8 +2,10 @@ package graphqlbackend
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/graph-gophers/graphql-go/gqltesting"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
@@ -49,6 +51,71 @@ func TestDiscussionThread_Get(t *testing.T) {
 	})
 }
 
+func TestDiscussionThread_TargetRepoDiff(t *testing.T) {
+	resetMocks()
+	mockViewerCanUseDiscussions = func() error { return nil }
+	defer func() { mockViewerCanUseDiscussions = nil }()
+	const wantThreadGraphQLID = "RGlzY3Vzc2lvblRocmVhZDoiM2Yi"
+	i32 := func(i int32) *int32 {
+		return &i
+	}
+	db.Mocks.DiscussionThreads.Get = func(threadID int64) (*types.DiscussionThread, error) {
+		head := "1d2b07481d2b07481d2b07481d2b07481d2b0748"
+		return &types.DiscussionThread{
+			ID: threadID,
+			TargetRepo: &types.DiscussionThreadTargetRepo{
+				Path:             strptr("b.go"),
+				Revision:         &head,
+				DiffHeadRevision: &head,
+				OldPath:          strptr("a.go"),
+				OldStartLine:     i32(3),
+				OldEndLine:       i32(5),
+			},
+		}, nil
+	}
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: GraphQLSchema,
+			Query: `
+				query ($id: ID!) {
+					node(id: $id) {
+						... on DiscussionThread {
+							target {
+								... on DiscussionThreadTargetRepo {
+									path
+									selection { startLine }
+									diff {
+										oldPath
+										oldSelection { startLine endLine }
+										newSelection { startLine endLine }
+									}
+								}
+							}
+						}
+					}
+				}
+			`,
+			Variables: map[string]interface{}{"id": wantThreadGraphQLID},
+			ExpectedResult: `
+				{
+					"node": {
+						"target": {
+							"path": "b.go",
+							"selection": null,
+							"diff": {
+								"oldPath": "a.go",
+								"oldSelection": {"startLine": 3, "endLine": 5},
+								"newSelection": null
+							}
+						}
+					}
+				}
+			`,
+		},
+	})
+}
+
 func TestDiscussionSelectionRelativeTo(t *testing.T) {
 	i32 := func(i int32) *int32 {
 		return &i
@@ -112,7 +179,7 @@ func TestDiscussionSelectionRelativeTo(t *testing.T) {
 				LinesAfter:  &[]string{"4", "5", "6"},
 			},
 			newContent: "0\n1\n2\n3\n",
-			want:       &discussionSelectionRangeResolver{startLine: 0, startCharacter: 0, endLine: 1, endCharacter: 1},
+			want:       &discussionSelectionRangeResolver{startLine: 3, startCharacter: 0, endLine: 4, endCharacter: 1},
 		},
 		{
 			name: "no_match",
@@ -137,6 +204,28 @@ func TestDiscussionSelectionRelativeTo(t *testing.T) {
 	}
 }
 
+func TestParseDiffNameStatus(t *testing.T) {
+	got, err := parseDiffNameStatus([]byte("M\x00a.go\x00R097\x00b.go\x00c.go\x00D\x00d.go\x00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []discussionNameChange{
+		{status: 'M', oldPath: "a.go", newPath: "a.go"},
+		{status: 'R', oldPath: "b.go", newPath: "c.go"},
+		{status: 'D', oldPath: "d.go", newPath: "d.go"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if got, err := parseDiffNameStatus(nil); err != nil || got != nil {
+		t.Errorf("empty output: got %+v, %v, want no changes", got, err)
+	}
+	if _, err := parseDiffNameStatus([]byte("R100\x00b.go\x00")); err == nil {
+		t.Error("truncated output: got nil error, want error")
+	}
+}
+
 func TestDiscussionsMutations_UpdateThread(t *testing.T) {
 	resetMocks()
 	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) { return &types.User{}, nil }
@@ -192,3 +281,62 @@ func TestDiscussionsMutations_UpdateThread(t *testing.T) {
 		},
 	})
 }
+
+func TestDiscussionsMutations_UpdateThreadResolveAssign(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) { return &types.User{ID: 1}, nil }
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, Username: fmt.Sprintf("u%d", id)}, nil
+	}
+	mockViewerCanUseDiscussions = func() error { return nil }
+	defer func() { mockViewerCanUseDiscussions = nil }()
+	resolvedAt := time.Date(2018, 1, 2, 3, 4, 5, 0, time.UTC)
+	db.Mocks.DiscussionThreads.Update = func(_ context.Context, threadID int64, opts *db.DiscussionThreadsUpdateOptions) (*types.DiscussionThread, error) {
+		if opts.Resolve == nil || !*opts.Resolve {
+			t.Errorf("got Resolve %v, want true", opts.Resolve)
+		}
+		// The viewer is recorded as the user who resolved the thread.
+		if opts.ResolvedByUserID != 1 {
+			t.Errorf("got ResolvedByUserID %d, want 1", opts.ResolvedByUserID)
+		}
+		if opts.AssigneeUserID == nil || *opts.AssigneeUserID != 2 {
+			t.Errorf("got AssigneeUserID %v, want 2", opts.AssigneeUserID)
+		}
+		return &types.DiscussionThread{
+			ID:               threadID,
+			ResolvedAt:       &resolvedAt,
+			ResolvedByUserID: &opts.ResolvedByUserID,
+			AssigneeUserID:   opts.AssigneeUserID,
+		}, nil
+	}
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Context: backend.WithAuthzBypass(context.Background()),
+			Schema:  GraphQLSchema,
+			Query: `
+                                mutation($assignee: ID!) {
+                                        discussions {
+                                                updateThread(input: {threadID: "RGlzY3Vzc2lvblRocmVhZDoiM2Yi", resolve: true, assignee: $assignee}) {
+                                                        resolvedAt
+                                                        resolvedBy { username }
+                                                        assignee { username }
+                                                }
+                                        }
+                                }
+                        `,
+			Variables: map[string]interface{}{"assignee": string(marshalUserID(2))},
+			ExpectedResult: `
+                                {
+                                        "discussions": {
+                                                "updateThread": {
+                                                        "resolvedAt": "2018-01-02T03:04:05Z",
+                                                        "resolvedBy": { "username": "u1" },
+                                                        "assignee": { "username": "u2" }
+                                                }
+                                        }
+                                }
+                        `,
+		},
+	})
+}
@@ -0,0 +1,235 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+type userIdentifierInput struct {
+	Username        *string
+	Email           *string
+	ExternalAccount *struct {
+		ServiceType string
+		ServiceID   string
+		ClientID    *string
+		AccountID   string
+	}
+}
+
+// resolveUserIdentifiers returns the IDs of the users identified by the given inputs. It returns an
+// error if any of the users does not exist.
+func resolveUserIdentifiers(ctx context.Context, inputs []*userIdentifierInput) ([]int32, error) {
+	userIDs := make([]int32, 0, len(inputs))
+	for _, in := range inputs {
+		id, err := resolveUserIdentifier(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, id)
+	}
+	return userIDs, nil
+}
+
+func resolveUserIdentifier(ctx context.Context, in *userIdentifierInput) (int32, error) {
+	n := 0
+	for _, set := range []bool{in.Username != nil, in.Email != nil, in.ExternalAccount != nil} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return 0, errors.New("exactly one of username, email and externalAccount must be set to identify a user")
+	}
+
+	switch {
+	case in.Username != nil:
+		user, err := db.Users.GetByUsername(ctx, *in.Username)
+		if err != nil {
+			return 0, errors.Wrapf(err, "user with username %q", *in.Username)
+		}
+		return user.ID, nil
+
+	case in.Email != nil:
+		user, err := db.Users.GetByVerifiedEmail(ctx, *in.Email)
+		if err != nil {
+			return 0, errors.Wrapf(err, "user with verified email %q", *in.Email)
+		}
+		return user.ID, nil
+
+	default:
+		a := in.ExternalAccount
+		opt := db.ExternalAccountsListOptions{
+			ServiceType: a.ServiceType,
+			ServiceID:   a.ServiceID,
+			AccountID:   a.AccountID,
+		}
+		if a.ClientID != nil {
+			opt.ClientID = *a.ClientID
+		}
+		accts, err := db.ExternalAccounts.List(ctx, opt)
+		if err != nil {
+			return 0, err
+		}
+		if len(accts) == 0 {
+			return 0, errors.Errorf("user with external account %s %s %q not found", a.ServiceType, a.ServiceID, a.AccountID)
+		}
+		return accts[0].UserID, nil
+	}
+}
+
+func (r *schemaResolver) SetRepositoryPermissionsForUsers(ctx context.Context, args *struct {
+	Repository graphql.ID
+	Users      []*userIdentifierInput
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can change repository permissions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	repoID, err := unmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+	// Ensure the repository exists.
+	repo, err := db.Repos.Get(ctx, repoID)
+	if err != nil {
+		return nil, err
+	}
+	// Explicit permissions are only consulted for repositories of external services with explicit
+	// permissions enabled, so refuse to store permissions that would have no effect.
+	if !hasExplicitPermissionsProvider(repo) {
+		return nil, errors.Errorf("repository %q does not use explicit permissions (set \"authorization\": {} in the configuration of its external service to enable them)", repo.Name)
+	}
+
+	userIDs, err := resolveUserIdentifiers(ctx, args.Users)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.ExplicitRepoPermissions.SetRepoUsers(ctx, repoID, userIDs); err != nil {
+		return nil, err
+	}
+	backend.LogAuditEvent(ctx, backend.AuditEvent{
+		Action:     "setRepositoryPermissionsForUsers",
+		TargetKind: "Repository",
+		TargetID:   string(args.Repository),
+		TargetName: string(repo.Name),
+		Changes:    []db.AuditLogChange{{Field: "users", After: marshalUserIDs(userIDs)}},
+	})
+	return &EmptyResponse{}, nil
+}
+
+// hasExplicitPermissionsProvider reports whether an authz provider that enforces explicit
+// permissions is responsible for the repository.
+func hasExplicitPermissionsProvider(repo *types.Repo) bool {
+	_, providers := authz.GetProviders()
+	for _, p := range providers {
+		if _, ok := p.(authz.UserProvider); ok && p.ServiceType() == repo.ExternalRepo.ServiceType && p.ServiceID() == repo.ExternalRepo.ServiceID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *schemaResolver) SetRepositoryPatternPermissionsForUsers(ctx context.Context, args *struct {
+	Pattern string
+	Users   []*userIdentifierInput
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can change repository permissions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	userIDs, err := resolveUserIdentifiers(ctx, args.Users)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.ExplicitRepoPermissions.SetPatternUsers(ctx, args.Pattern, userIDs); err != nil {
+		return nil, err
+	}
+	backend.LogAuditEvent(ctx, backend.AuditEvent{
+		Action:     "setRepositoryPatternPermissionsForUsers",
+		TargetKind: "ExplicitRepositoryPermissionPattern",
+		TargetID:   args.Pattern,
+		TargetName: args.Pattern,
+		Changes:    []db.AuditLogChange{{Field: "users", After: marshalUserIDs(userIDs)}},
+	})
+	return &EmptyResponse{}, nil
+}
+
+// marshalUserIDs returns the GraphQL IDs of the users, for recording in the audit log.
+func marshalUserIDs(userIDs []int32) []graphql.ID {
+	ids := make([]graphql.ID, len(userIDs))
+	for i, userID := range userIDs {
+		ids[i] = marshalUserID(userID)
+	}
+	return ids
+}
+
+func (r *repositoryResolver) ExplicitlyPermittedUsers(ctx context.Context) ([]*UserResolver, error) {
+	// 🚨 SECURITY: Only site admins can view repository permissions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	userIDs, err := db.ExplicitRepoPermissions.ListRepoUsers(ctx, r.repo.ID)
+	if err != nil {
+		return nil, err
+	}
+	return usersByIDs(ctx, userIDs)
+}
+
+func (r *siteResolver) ExplicitRepositoryPermissionPatterns(ctx context.Context) ([]*explicitRepositoryPermissionPatternResolver, error) {
+	// 🚨 SECURITY: Only site admins can view repository permissions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	ps, err := db.ExplicitRepoPermissions.ListPatterns(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*explicitRepositoryPermissionPatternResolver, len(ps))
+	for i, p := range ps {
+		resolvers[i] = &explicitRepositoryPermissionPatternResolver{p: p}
+	}
+	return resolvers, nil
+}
+
+type explicitRepositoryPermissionPatternResolver struct {
+	p *db.ExplicitRepoPatternPermissions
+}
+
+func (r *explicitRepositoryPermissionPatternResolver) Pattern() string { return r.p.Pattern }
+
+func (r *explicitRepositoryPermissionPatternResolver) Users(ctx context.Context) ([]*UserResolver, error) {
+	return usersByIDs(ctx, r.p.UserIDs)
+}
+
+// usersByIDs returns resolvers for the users with the given IDs, in the same order. Deleted users
+// are omitted.
+func usersByIDs(ctx context.Context, userIDs []int32) ([]*UserResolver, error) {
+	if len(userIDs) == 0 {
+		return []*UserResolver{}, nil
+	}
+	users, err := db.Users.List(ctx, &db.UsersListOptions{UserIDs: userIDs})
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int32]*types.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+	resolvers := make([]*UserResolver, 0, len(userIDs))
+	for _, id := range userIDs {
+		if u, ok := byID[id]; ok {
+			resolvers = append(resolvers, &UserResolver{user: u})
+		}
+	}
+	return resolvers, nil
+}
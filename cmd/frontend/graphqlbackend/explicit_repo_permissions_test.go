@@ -0,0 +1,193 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+)
+
+// fakeExplicitProvider is an authz.UserProvider for the repositories of the given service, like
+// the explicit permissions provider.
+type fakeExplicitProvider struct {
+	serviceType, serviceID string
+}
+
+func (p fakeExplicitProvider) FetchAccount(context.Context, *types.User, []*extsvc.ExternalAccount) (*extsvc.ExternalAccount, error) {
+	return nil, nil
+}
+func (p fakeExplicitProvider) RepoPerms(context.Context, *extsvc.ExternalAccount, []*types.Repo) ([]authz.RepoPerms, error) {
+	return nil, nil
+}
+func (p fakeExplicitProvider) UserRepoPerms(context.Context, *types.User, []*types.Repo) ([]authz.RepoPerms, error) {
+	return nil, nil
+}
+func (p fakeExplicitProvider) ServiceType() string { return p.serviceType }
+func (p fakeExplicitProvider) ServiceID() string   { return p.serviceID }
+func (p fakeExplicitProvider) Validate() []string  { return nil }
+
+// setExplicitPermissionsProvider sets an explicit permissions provider for the Gitolite host
+// git@gitolite.example.com and returns a func that restores the previous providers.
+func setExplicitPermissionsProvider() (restore func()) {
+	authzAllowByDefault, providers := authz.GetProviders()
+	authz.SetProviders(false, []authz.Provider{fakeExplicitProvider{serviceType: "gitolite", serviceID: "git@gitolite.example.com"}})
+	return func() { authz.SetProviders(authzAllowByDefault, providers) }
+}
+
+func TestSetRepositoryPermissionsForUsers(t *testing.T) {
+	resetMocks()
+	defer setExplicitPermissionsProvider()()
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1, SiteAdmin: true}, nil
+	}
+	db.Mocks.Repos.Get = func(ctx context.Context, id api.RepoID) (*types.Repo, error) {
+		return &types.Repo{
+			ID:           id,
+			Name:         "gitolite.example.com/a",
+			ExternalRepo: api.ExternalRepoSpec{ID: "a", ServiceType: "gitolite", ServiceID: "git@gitolite.example.com"},
+		}, nil
+	}
+	db.Mocks.Users.GetByUsername = func(ctx context.Context, username string) (*types.User, error) {
+		return &types.User{ID: 2, Username: username}, nil
+	}
+	db.Mocks.Users.GetByVerifiedEmail = func(ctx context.Context, email string) (*types.User, error) {
+		return &types.User{ID: 3}, nil
+	}
+	db.Mocks.ExternalAccounts.List = func(opt db.ExternalAccountsListOptions) ([]*extsvc.ExternalAccount, error) {
+		want := db.ExternalAccountsListOptions{ServiceType: "saml", ServiceID: "https://idp.example.com", AccountID: "alice"}
+		if !reflect.DeepEqual(opt, want) {
+			t.Errorf("got external accounts list options %+v, want %+v", opt, want)
+		}
+		return []*extsvc.ExternalAccount{{UserID: 4}}, nil
+	}
+	var setRepoID api.RepoID
+	var setUserIDs []int32
+	db.Mocks.ExplicitRepoPermissions.SetRepoUsers = func(ctx context.Context, repoID api.RepoID, userIDs []int32) error {
+		setRepoID, setUserIDs = repoID, userIDs
+		return nil
+	}
+	var auditLogEntry *db.AuditLogEntry
+	db.Mocks.AuditLog.Create = func(e *db.AuditLogEntry) error {
+		auditLogEntry = e
+		return nil
+	}
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+			Schema:  GraphQLSchema,
+			Query: `
+				mutation {
+					setRepositoryPermissionsForUsers(repository: "` + string(marshalRepositoryID(7)) + `", users: [
+						{username: "bob"},
+						{email: "carol@example.com"},
+						{externalAccount: {serviceType: "saml", serviceID: "https://idp.example.com", accountID: "alice"}}
+					]) {
+						alwaysNil
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"setRepositoryPermissionsForUsers": {
+						"alwaysNil": null
+					}
+				}
+			`,
+		},
+	})
+
+	if setRepoID != 7 {
+		t.Errorf("got repository %d, want 7", setRepoID)
+	}
+	if want := []int32{2, 3, 4}; !reflect.DeepEqual(setUserIDs, want) {
+		t.Errorf("got users %v, want %v", setUserIDs, want)
+	}
+	if auditLogEntry == nil {
+		t.Fatal("got no audit log entry")
+	}
+	if auditLogEntry.Action != "setRepositoryPermissionsForUsers" || auditLogEntry.ActorUserID != 1 || auditLogEntry.TargetName != "gitolite.example.com/a" {
+		t.Errorf("got audit log entry %+v", auditLogEntry)
+	}
+}
+
+func TestSetRepositoryPermissionsForUsers_noExplicitPermissions(t *testing.T) {
+	resetMocks()
+	defer setExplicitPermissionsProvider()()
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1, SiteAdmin: true}, nil
+	}
+	db.Mocks.Repos.Get = func(ctx context.Context, id api.RepoID) (*types.Repo, error) {
+		return &types.Repo{
+			ID:           id,
+			Name:         "github.com/a/b",
+			ExternalRepo: api.ExternalRepoSpec{ID: "b", ServiceType: "github", ServiceID: "https://github.com/"},
+		}, nil
+	}
+	db.Mocks.ExplicitRepoPermissions.SetRepoUsers = func(ctx context.Context, repoID api.RepoID, userIDs []int32) error {
+		t.Error("permissions must not be set")
+		return nil
+	}
+
+	_, err := (&schemaResolver{}).SetRepositoryPermissionsForUsers(actor.WithActor(context.Background(), &actor.Actor{UID: 1}), &struct {
+		Repository graphql.ID
+		Users      []*userIdentifierInput
+	}{Repository: marshalRepositoryID(7)})
+	if err == nil {
+		t.Error("got nil error, want error")
+	}
+}
+
+func TestSetRepositoryPatternPermissionsForUsers(t *testing.T) {
+	t.Run("ambiguous user", func(t *testing.T) {
+		resetMocks()
+		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{ID: 1, SiteAdmin: true}, nil
+		}
+		db.Mocks.ExplicitRepoPermissions.SetPatternUsers = func(ctx context.Context, pattern string, userIDs []int32) error {
+			t.Error("permissions must not be set")
+			return nil
+		}
+
+		username, email := "bob", "bob@example.com"
+		_, err := (&schemaResolver{}).SetRepositoryPatternPermissionsForUsers(context.Background(), &struct {
+			Pattern string
+			Users   []*userIdentifierInput
+		}{
+			Pattern: "^gitolite\\.example\\.com/",
+			Users:   []*userIdentifierInput{{Username: &username, Email: &email}},
+		})
+		if err == nil {
+			t.Error("got nil error, want error")
+		}
+	})
+
+	// 🚨 SECURITY: test necessary to ensure security
+	t.Run("non-site-admin", func(t *testing.T) {
+		resetMocks()
+		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{ID: 1}, nil
+		}
+		db.Mocks.ExplicitRepoPermissions.SetPatternUsers = func(ctx context.Context, pattern string, userIDs []int32) error {
+			t.Error("permissions must not be set")
+			return nil
+		}
+
+		_, err := (&schemaResolver{}).SetRepositoryPatternPermissionsForUsers(actor.WithActor(context.Background(), &actor.Actor{UID: 1}), &struct {
+			Pattern string
+			Users   []*userIdentifierInput
+		}{Pattern: ".*"})
+		if err != backend.ErrMustBeSiteAdmin {
+			t.Errorf("got error %v, want %v", err, backend.ErrMustBeSiteAdmin)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/repoupdater"
+	"github.com/sourcegraph/sourcegraph/pkg/repoupdater/protocol"
+)
+
+func (*schemaResolver) ExternalServiceDryRun(ctx context.Context, args *struct {
+	ID     *graphql.ID
+	Kind   string
+	Config string
+}) (*externalServiceDryRunResolver, error) {
+	// 🚨 SECURITY: Only site admins may preview external service configurations, because doing so
+	// uses the given credentials to list repositories on the code host.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	svc := api.ExternalService{Kind: args.Kind, Config: args.Config}
+	if args.ID != nil {
+		id, err := unmarshalExternalServiceID(*args.ID)
+		if err != nil {
+			return nil, err
+		}
+		existing, err := db.ExternalServices.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if existing.Kind != args.Kind {
+			return nil, fmt.Errorf("external service kind %s does not match kind %s of the external service being previewed", args.Kind, existing.Kind)
+		}
+		svc.ID = existing.ID
+		svc.DisplayName = existing.DisplayName
+	}
+
+	if err := db.ExternalServices.ValidateConfig(args.Kind, args.Config, conf.Get().Critical.AuthProviders); err != nil {
+		return nil, err
+	}
+
+	res, err := repoupdater.DefaultClient.ExternalServiceDryRun(ctx, svc)
+	if err != nil {
+		return nil, err
+	}
+	return &externalServiceDryRunResolver{res: res}, nil
+}
+
+type externalServiceDryRunResolver struct {
+	res *protocol.ExternalServiceDryRunResult
+}
+
+func (r *externalServiceDryRunResolver) Added() []string    { return repoNameStrings(r.res.Added) }
+func (r *externalServiceDryRunResolver) Removed() []string  { return repoNameStrings(r.res.Removed) }
+func (r *externalServiceDryRunResolver) Modified() []string { return repoNameStrings(r.res.Modified) }
+func (r *externalServiceDryRunResolver) UnmodifiedCount() int32 {
+	return int32(r.res.Unmodified)
+}
+
+func repoNameStrings(names []api.RepoName) []string {
+	ss := make([]string, len(names))
+	for i, name := range names {
+		ss[i] = string(name)
+	}
+	return ss
+}
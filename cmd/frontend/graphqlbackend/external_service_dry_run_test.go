@@ -0,0 +1,71 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/repoupdater"
+	"github.com/sourcegraph/sourcegraph/pkg/repoupdater/protocol"
+)
+
+func TestExternalServiceDryRun(t *testing.T) {
+	resetMocks()
+	conf.Mock(&conf.Unified{})
+	defer conf.Mock(nil)
+
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1, SiteAdmin: true}, nil
+	}
+	db.Mocks.ExternalServices.GetByID = func(id int64) (*types.ExternalService, error) {
+		return &types.ExternalService{ID: id, Kind: "OTHER", DisplayName: "Git", Config: `{"repos": []}`}, nil
+	}
+
+	const config = `{"url": "https://git.example.com", "repos": ["foo", "qux"]}`
+	repoupdater.MockExternalServiceDryRun = func(_ context.Context, svc api.ExternalService) (*protocol.ExternalServiceDryRunResult, error) {
+		want := api.ExternalService{ID: 3, Kind: "OTHER", DisplayName: "Git", Config: config}
+		if svc != want {
+			t.Errorf("got external service %+v, want %+v", svc, want)
+		}
+		return &protocol.ExternalServiceDryRunResult{
+			Added:      []api.RepoName{"git.example.com/qux"},
+			Removed:    []api.RepoName{"git.example.com/bar"},
+			Modified:   []api.RepoName{},
+			Unmodified: 1,
+		}, nil
+	}
+	defer func() { repoupdater.MockExternalServiceDryRun = nil }()
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+			Schema:  GraphQLSchema,
+			Query: `
+				{
+					externalServiceDryRun(id: "` + string(marshalExternalServiceID(3)) + `", kind: OTHER, config: ` + strconv.Quote(config) + `) {
+						added
+						removed
+						modified
+						unmodifiedCount
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"externalServiceDryRun": {
+						"added": ["git.example.com/qux"],
+						"removed": ["git.example.com/bar"],
+						"modified": [],
+						"unmodifiedCount": 1
+					}
+				}
+			`,
+		},
+	})
+}
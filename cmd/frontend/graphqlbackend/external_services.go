@@ -42,6 +42,13 @@ func (r *schemaResolver) AddExternalService(ctx context.Context, args *struct {
 	if err := db.ExternalServices.Create(ctx, conf.Get, externalService); err != nil {
 		return nil, err
 	}
+	backend.LogAuditEvent(ctx, backend.AuditEvent{
+		Action:     "addExternalService",
+		TargetKind: "ExternalService",
+		TargetID:   string(marshalExternalServiceID(externalService.ID)),
+		TargetName: externalService.DisplayName,
+		Changes:    []db.AuditLogChange{{Field: "kind", After: externalService.Kind}},
+	})
 
 	res := &externalServiceResolver{externalService: externalService}
 	if err := syncExternalService(ctx, externalService); err != nil {
@@ -88,6 +95,22 @@ func (*schemaResolver) UpdateExternalService(ctx context.Context, args *struct {
 		return nil, err
 	}
 
+	// The configuration may contain secrets, so only record that it changed.
+	var changes []db.AuditLogChange
+	if update.DisplayName != nil {
+		changes = append(changes, db.AuditLogChange{Field: "displayName", After: *update.DisplayName})
+	}
+	if update.Config != nil {
+		changes = append(changes, db.AuditLogChange{Field: "config"})
+	}
+	backend.LogAuditEvent(ctx, backend.AuditEvent{
+		Action:     "updateExternalService",
+		TargetKind: "ExternalService",
+		TargetID:   string(args.Input.ID),
+		TargetName: externalService.DisplayName,
+		Changes:    changes,
+	})
+
 	res := &externalServiceResolver{externalService: externalService}
 	if err = syncExternalService(ctx, externalService); err != nil {
 		res.warning = fmt.Sprintf("External service updated, but we encountered a problem while validating the external service: %s", err)
@@ -138,6 +161,12 @@ func (*schemaResolver) DeleteExternalService(ctx context.Context, args *struct {
 	if err := db.ExternalServices.Delete(ctx, id); err != nil {
 		return nil, err
 	}
+	backend.LogAuditEvent(ctx, backend.AuditEvent{
+		Action:     "deleteExternalService",
+		TargetKind: "ExternalService",
+		TargetID:   string(args.ExternalService),
+		TargetName: externalService.DisplayName,
+	})
 
 	if err = syncExternalService(ctx, externalService); err != nil {
 		return nil, errors.Wrap(err, "warning: external service deleted, but sync request failed")
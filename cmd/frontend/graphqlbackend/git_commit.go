@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/externallink"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
@@ -109,6 +110,12 @@ func (r *gitCommitResolver) Parents(ctx context.Context) ([]*gitCommitResolver,
 	return resolvers, nil
 }
 
+func (r *gitCommitResolver) DiscussionThreads(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+}) (*discussionThreadsConnectionResolver, error) {
+	return discussionThreadsOnDiffs(ctx, r.repo, args.ConnectionArgs, db.DiscussionThreadsTargetRepoDiff{HeadRevision: string(r.oid)})
+}
+
 func (r *gitCommitResolver) URL() (string, error) {
 	return r.repo.URL() + "/-/commit/" + string(r.inputRevOrImmutableRev()), nil
 }
@@ -41,6 +41,10 @@ type prometheusTracer struct {
 
 func (prometheusTracer) TraceField(ctx context.Context, label, typeName, fieldName string, trivial bool, args map[string]interface{}) (context.Context, trace.TraceFieldFinishFunc) {
 	traceCtx, finish := trace.OpenTracingTracer{}.TraceField(ctx, label, typeName, fieldName, trivial, args)
+	// 🚨 SECURITY: Check that the actor's access token (if any) permits selecting this field.
+	if err := checkFieldScope(ctx, typeName, fieldName); err != nil {
+		traceCtx = scopeDeniedContext{Context: traceCtx, err: err}
+	}
 	start := time.Now()
 	return traceCtx, func(err *gqlerrors.QueryError) {
 		graphqlFieldHistogram.WithLabelValues(typeName, fieldName, strconv.FormatBool(err != nil)).Observe(time.Since(start).Seconds())
@@ -181,6 +185,10 @@ func (r *schemaResolver) Root() *schemaResolver {
 }
 
 func (r *schemaResolver) Node(ctx context.Context, args *struct{ ID graphql.ID }) (*nodeResolver, error) {
+	// 🚨 SECURITY: Check that the actor's access token (if any) permits looking up this kind of node.
+	if err := checkNodeScope(ctx, args.ID); err != nil {
+		return nil, err
+	}
 	n, err := nodeByID(ctx, args.ID)
 	if err != nil {
 		return nil, err
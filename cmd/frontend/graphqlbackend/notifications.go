@@ -0,0 +1,266 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+func notificationKindToGraphQL(kind string) string {
+	switch kind {
+	case db.NotificationDiscussionThread:
+		return "DISCUSSION_THREAD"
+	case db.NotificationDiscussionComment:
+		return "DISCUSSION_COMMENT"
+	case db.NotificationDiscussionMention:
+		return "DISCUSSION_MENTION"
+	default:
+		panic("unexpected notification kind: " + kind)
+	}
+}
+
+func marshalNotificationID(id int64) graphql.ID { return relay.MarshalID("Notification", id) }
+
+func unmarshalNotificationID(id graphql.ID) (notificationID int64, err error) {
+	if kind := relay.UnmarshalKind(id); kind != "Notification" {
+		return 0, fmt.Errorf("expected graphql ID to have kind %q; got %q", "Notification", kind)
+	}
+	err = relay.UnmarshalSpec(id, &notificationID)
+	return
+}
+
+func (r *UserResolver) Notifications(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+	UnreadOnly bool
+}) (*notificationConnectionResolver, error) {
+	// 🚨 SECURITY: Only the user and site admins can list the user's notifications.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
+		return nil, err
+	}
+
+	opt := db.NotificationsListOptions{UserID: r.user.ID, UnreadOnly: args.UnreadOnly}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &notificationConnectionResolver{opt: opt}, nil
+}
+
+// notificationConnectionResolver resolves a list of a user's notifications.
+//
+// 🚨 SECURITY: When instantiating a notificationConnectionResolver value, the caller MUST check
+// permissions.
+type notificationConnectionResolver struct {
+	opt db.NotificationsListOptions
+
+	// cache results because they are used by multiple fields
+	once          sync.Once
+	notifications []*db.Notification
+	err           error
+}
+
+func (r *notificationConnectionResolver) compute(ctx context.Context) ([]*db.Notification, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.LimitOffset != nil {
+			tmp := *opt2.LimitOffset
+			opt2.LimitOffset = &tmp
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+
+		r.notifications, r.err = db.Notifications.List(ctx, opt2)
+	})
+	return r.notifications, r.err
+}
+
+func (r *notificationConnectionResolver) Nodes(ctx context.Context) ([]*notificationResolver, error) {
+	notifications, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(notifications) > r.opt.Limit {
+		notifications = notifications[:r.opt.Limit]
+	}
+
+	l := make([]*notificationResolver, len(notifications))
+	for i, n := range notifications {
+		l[i] = &notificationResolver{n: n}
+	}
+	return l, nil
+}
+
+func (r *notificationConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := db.Notifications.Count(ctx, r.opt)
+	return int32(count), err
+}
+
+func (r *notificationConnectionResolver) UnreadCount(ctx context.Context) (int32, error) {
+	count, err := db.Notifications.Count(ctx, db.NotificationsListOptions{UserID: r.opt.UserID, UnreadOnly: true})
+	return int32(count), err
+}
+
+func (r *notificationConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	notifications, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(notifications) > r.opt.Limit), nil
+}
+
+// notificationResolver resolves a user's notification.
+//
+// 🚨 SECURITY: When instantiating a notificationResolver value, the caller MUST check permissions.
+type notificationResolver struct {
+	n *db.Notification
+}
+
+func (r *notificationResolver) ID() graphql.ID { return marshalNotificationID(r.n.ID) }
+
+func (r *notificationResolver) Kind() string { return notificationKindToGraphQL(r.n.Kind) }
+
+func (r *notificationResolver) Actor(ctx context.Context) (*UserResolver, error) {
+	if r.n.ActorUserID == nil {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, *r.n.ActorUserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *notificationResolver) Thread(ctx context.Context) (*discussionThreadResolver, error) {
+	if r.n.ThreadID == nil {
+		return nil, nil
+	}
+	thread, err := db.DiscussionThreads.Get(ctx, *r.n.ThreadID)
+	if errcode.IsNotFound(err) {
+		return nil, nil // the thread was deleted
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &discussionThreadResolver{t: thread}, nil
+}
+
+func (r *notificationResolver) Comment(ctx context.Context) (*discussionCommentResolver, error) {
+	if r.n.CommentID == nil {
+		return nil, nil
+	}
+	comment, err := db.DiscussionComments.Get(ctx, *r.n.CommentID)
+	if errcode.IsNotFound(err) {
+		return nil, nil // the comment was deleted
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &discussionCommentResolver{c: comment}, nil
+}
+
+func (r *notificationResolver) Title() string { return r.n.Title }
+
+func (r *notificationResolver) Body() string { return r.n.Body }
+
+func (r *notificationResolver) URL() string { return r.n.URL }
+
+func (r *notificationResolver) CreatedAt() string { return r.n.CreatedAt.Format(time.RFC3339) }
+
+func (r *notificationResolver) ReadAt() *string {
+	if r.n.ReadAt == nil {
+		return nil
+	}
+	return strptr(r.n.ReadAt.Format(time.RFC3339))
+}
+
+func (r *schemaResolver) MarkNotificationsRead(ctx context.Context, args *struct {
+	User          graphql.ID
+	Notifications *[]graphql.ID
+}) (*EmptyResponse, error) {
+	userID, err := UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Only the user and site admins can mark the user's notifications as read.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	if args.Notifications != nil {
+		ids = make([]int64, len(*args.Notifications))
+		for i, id := range *args.Notifications {
+			if ids[i], err = unmarshalNotificationID(id); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if _, err := db.Notifications.MarkRead(ctx, userID, ids); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *UserResolver) NotificationSettings(ctx context.Context) (*notificationSettingsResolver, error) {
+	// 🚨 SECURITY: Only the user and site admins can view the user's notification settings (which
+	// include the user's Slack webhook URL).
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
+		return nil, err
+	}
+
+	settings, err := db.UserNotificationSettings.GetByUserID(ctx, r.user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &notificationSettingsResolver{settings: settings}, nil
+}
+
+func (r *schemaResolver) UpdateNotificationSettings(ctx context.Context, args *struct {
+	User            graphql.ID
+	InApp           bool
+	Email           bool
+	SlackWebhookURL *string
+}) (*notificationSettingsResolver, error) {
+	userID, err := UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Only the user and site admins can update the user's notification settings.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if args.SlackWebhookURL != nil {
+		if err := validateSavedSearchWebhook(*args.SlackWebhookURL, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	settings := &db.UserNotificationSettings{
+		UserID:          userID,
+		InApp:           args.InApp,
+		Email:           args.Email,
+		SlackWebhookURL: args.SlackWebhookURL,
+	}
+	if err := db.UserNotificationSettings.Update(ctx, settings); err != nil {
+		return nil, err
+	}
+	return &notificationSettingsResolver{settings: settings}, nil
+}
+
+// notificationSettingsResolver resolves a user's notification settings.
+//
+// 🚨 SECURITY: When instantiating a notificationSettingsResolver value, the caller MUST check
+// permissions.
+type notificationSettingsResolver struct {
+	settings *db.UserNotificationSettings
+}
+
+func (r *notificationSettingsResolver) InApp() bool { return r.settings.InApp }
+
+func (r *notificationSettingsResolver) Email() bool { return r.settings.Email }
+
+func (r *notificationSettingsResolver) SlackWebhookURL() *string { return r.settings.SlackWebhookURL }
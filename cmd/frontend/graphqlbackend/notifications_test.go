@@ -0,0 +1,72 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+)
+
+func TestMarkNotificationsRead(t *testing.T) {
+	defer resetMocks()
+
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: actor.FromContext(ctx).UID}, nil
+	}
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, Username: "u"}, nil
+	}
+	var (
+		markedUserID int32
+		markedIDs    []int64
+		marked       bool
+	)
+	db.Mocks.Notifications.MarkRead = func(ctx context.Context, userID int32, ids []int64) (int, error) {
+		markedUserID, markedIDs, marked = userID, ids, true
+		return len(ids), nil
+	}
+
+	notificationIDs := []graphql.ID{marshalNotificationID(3), marshalNotificationID(4)}
+	tests := map[string]struct {
+		ctx           context.Context
+		notifications *[]graphql.ID
+		wantIDs       []int64
+		wantErr       bool
+	}{
+		"some": {
+			ctx:           actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+			notifications: &notificationIDs,
+			wantIDs:       []int64{3, 4},
+		},
+		"all": {
+			ctx: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+		},
+		// 🚨 SECURITY: Users must not be able to mark other users' notifications as read.
+		"other user": {
+			ctx:     actor.WithActor(context.Background(), &actor.Actor{UID: 2}),
+			wantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			marked, markedIDs = false, nil
+			_, err := (&schemaResolver{}).MarkNotificationsRead(test.ctx, &struct {
+				User          graphql.ID
+				Notifications *[]graphql.ID
+			}{User: marshalUserID(1), Notifications: test.notifications})
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Errorf("got error %v, want error %v", err, test.wantErr)
+			}
+			if marked == test.wantErr {
+				t.Errorf("got marked %v, want %v", marked, !test.wantErr)
+			}
+			if marked && (markedUserID != 1 || !reflect.DeepEqual(markedIDs, test.wantIDs)) {
+				t.Errorf("got user %d IDs %v, want user 1 IDs %v", markedUserID, markedIDs, test.wantIDs)
+			}
+		})
+	}
+}
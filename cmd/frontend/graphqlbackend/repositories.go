@@ -366,6 +366,11 @@ func (r *schemaResolver) DeleteRepository(ctx context.Context, args *struct {
 	if err := db.Repos.Delete(ctx, id); err != nil {
 		return nil, err
 	}
+	backend.LogAuditEvent(ctx, backend.AuditEvent{
+		Action:     "deleteRepository",
+		TargetKind: "Repository",
+		TargetID:   string(args.Repository),
+	})
 	return &EmptyResponse{}, nil
 }
 
@@ -219,6 +219,9 @@ func (r *repositoryResolver) ToCommitSearchResult() (*commitSearchResultResolver
 func (r *repositoryResolver) ToCodemodResult() (*codemodResultResolver, bool) {
 	return nil, false
 }
+func (r *repositoryResolver) ToDiscussionSearchResult() (*discussionSearchResultResolver, bool) {
+	return nil, false
+}
 
 func (r *repositoryResolver) searchResultURIs() (string, string) {
 	return string(r.repo.Name), ""
@@ -303,6 +306,11 @@ func (*schemaResolver) ResolvePhabricatorDiff(ctx context.Context, args *struct
 		return commit, err
 	}
 
+	// 🚨 SECURITY: Only users with write access to the repository may create the commit.
+	if err := backend.CheckRepoWrite(ctx, repo); err != nil {
+		return nil, err
+	}
+
 	origin := ""
 	if phabRepo, err := db.Phabricator.GetByName(ctx, api.RepoName(args.RepoName)); err == nil {
 		origin = phabRepo.URL
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/sourcegraph/go-diff/diff"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/pkg/gitserver"
 	"github.com/sourcegraph/sourcegraph/pkg/vcs/git"
@@ -113,6 +115,30 @@ func (r *repositoryComparisonResolver) FileDiffs(args *struct {
 	}
 }
 
+func (r *repositoryComparisonResolver) DiscussionThreads(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+}) (*discussionThreadsConnectionResolver, error) {
+	if r.head == nil {
+		return nil, errors.New("comparison has no head commit")
+	}
+	head := string(r.head.OID())
+	base := devNullSHA
+	if r.base != nil {
+		base = string(r.base.OID())
+	}
+	diffs := []db.DiscussionThreadsTargetRepoDiff{{BaseRevision: &base, HeadRevision: head}}
+
+	// The diff of a commit is the diff between its first parent and it.
+	parent := devNullSHA
+	if len(r.head.parents) > 0 {
+		parent = string(r.head.parents[0])
+	}
+	if base == parent {
+		diffs = append(diffs, db.DiscussionThreadsTargetRepoDiff{HeadRevision: head})
+	}
+	return discussionThreadsOnDiffs(ctx, r.repo, args.ConnectionArgs, diffs...)
+}
+
 type fileDiffConnectionResolver struct {
 	cmp   *repositoryComparisonResolver // {base,head}{,RevSpec} and repo
 	first *int32
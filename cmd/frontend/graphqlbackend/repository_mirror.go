@@ -3,6 +3,7 @@ package graphqlbackend
 import (
 	"context"
 	"errors"
+	"strings"
 	"sync"
 	"time"
 
@@ -150,6 +151,21 @@ func (r *updateScheduleResolver) Total() int32 {
 	return int32(r.schedule.Total)
 }
 
+func (r *updateScheduleResolver) Demand() *string {
+	if r.schedule.Demand == "" {
+		return nil
+	}
+	demand := strings.ToUpper(r.schedule.Demand)
+	return &demand
+}
+
+func (r *updateScheduleResolver) IntervalReason() *string {
+	if r.schedule.IntervalReason == "" {
+		return nil
+	}
+	return &r.schedule.IntervalReason
+}
+
 func (r *repositoryMirrorInfoResolver) UpdateQueue(ctx context.Context) (*updateQueueResolver, error) {
 	info, err := r.repoUpdateSchedulerInfo(ctx)
 	if err != nil {
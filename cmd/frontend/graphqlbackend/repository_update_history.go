@@ -0,0 +1,147 @@
+package graphqlbackend
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+// maxFailingRepositories is the maximum number of repositories returned by
+// Site.failingRepositories.
+const maxFailingRepositories = 1000
+
+// limitFromFirst returns the number of items to return for a field with a
+// first argument, which must be non-negative. If first is not given or
+// exceeds max, max items are returned.
+func limitFromFirst(first *int32, max int) (int, error) {
+	if first == nil {
+		return max, nil
+	}
+	if *first < 0 {
+		return 0, errors.New("first must be a non-negative integer")
+	}
+	if int(*first) > max {
+		return max, nil
+	}
+	return int(*first), nil
+}
+
+func (r *repositoryMirrorInfoResolver) UpdateHistory(ctx context.Context, args *struct {
+	First *int32
+}) ([]*repositoryUpdateAttemptResolver, error) {
+	// 🚨 SECURITY: The Git output could reveal details of the code host and its
+	// configuration, so only allow site admins to see it.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	limit, err := limitFromFirst(args.First, db.MaxRepoUpdateAttempts)
+	if err != nil {
+		return nil, err
+	}
+	attempts, err := db.RepoUpdateAttempts.List(ctx, r.repository.repo.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*repositoryUpdateAttemptResolver, len(attempts))
+	for i, a := range attempts {
+		resolvers[i] = &repositoryUpdateAttemptResolver{attempt: *a}
+	}
+	return resolvers, nil
+}
+
+type repositoryUpdateAttemptResolver struct {
+	attempt api.RepoUpdateAttempt
+}
+
+func (r *repositoryUpdateAttemptResolver) Kind() string { return strings.ToUpper(r.attempt.Kind) }
+
+func (r *repositoryUpdateAttemptResolver) StartedAt() string {
+	return r.attempt.Started.Format(time.RFC3339)
+}
+
+func (r *repositoryUpdateAttemptResolver) DurationMilliseconds() int32 {
+	return int32(r.attempt.Duration / time.Millisecond)
+}
+
+func (r *repositoryUpdateAttemptResolver) Succeeded() bool { return !r.attempt.Failed() }
+
+func (r *repositoryUpdateAttemptResolver) Error() *string {
+	if !r.attempt.Failed() {
+		return nil
+	}
+	return &r.attempt.Error
+}
+
+func (r *repositoryUpdateAttemptResolver) Output() string { return r.attempt.Output }
+
+func (r *repositoryUpdateAttemptResolver) BytesReceived() float64 {
+	return float64(r.attempt.BytesReceived)
+}
+
+func (r *siteResolver) FailingRepositories(ctx context.Context, args *struct {
+	First                  *int32
+	MinConsecutiveFailures int32
+}) ([]*failingRepositoryResolver, error) {
+	// 🚨 SECURITY: Only site admins may list failing repositories, because the list
+	// includes the Git output of the failed attempts.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	limit, err := limitFromFirst(args.First, maxFailingRepositories)
+	if err != nil {
+		return nil, err
+	}
+	repos, err := db.RepoUpdateAttempts.ListFailing(ctx, int(args.MinConsecutiveFailures), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*failingRepositoryResolver, len(repos))
+	for i, repo := range repos {
+		resolvers[i] = &failingRepositoryResolver{repo: repo}
+	}
+	return resolvers, nil
+}
+
+type failingRepositoryResolver struct {
+	repo *types.FailingRepo
+}
+
+func (r *failingRepositoryResolver) Name() string { return string(r.repo.RepoName) }
+
+func (r *failingRepositoryResolver) Repository(ctx context.Context) (*repositoryResolver, error) {
+	repo, err := backend.Repos.Get(ctx, r.repo.RepoID)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &repositoryResolver{repo: repo}, nil
+}
+
+func (r *failingRepositoryResolver) ConsecutiveFailures() int32 {
+	return int32(r.repo.ConsecutiveFailures)
+}
+
+func (r *failingRepositoryResolver) LastAttempt() *repositoryUpdateAttemptResolver {
+	return &repositoryUpdateAttemptResolver{attempt: r.repo.LastAttempt}
+}
+
+func (r *failingRepositoryResolver) LastSucceededAt() *string {
+	if r.repo.LastSucceeded == nil {
+		return nil
+	}
+	s := r.repo.LastSucceeded.Format(time.RFC3339)
+	return &s
+}
@@ -0,0 +1,167 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+func TestSite_FailingRepositories(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+	backend.Mocks.Repos.Get = func(ctx context.Context, id api.RepoID) (*types.Repo, error) {
+		if id != 1 {
+			return nil, &errcode.Mock{IsNotFound: true}
+		}
+		return &types.Repo{ID: 1, Name: "github.com/foo/bar"}, nil
+	}
+
+	started := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	succeeded := started.Add(-72 * time.Hour)
+	db.Mocks.RepoUpdateAttempts.ListFailing = func(ctx context.Context, minConsecutiveFailures, limit int) ([]*types.FailingRepo, error) {
+		if want := 3; minConsecutiveFailures != want {
+			t.Errorf("got minConsecutiveFailures %d, want %d", minConsecutiveFailures, want)
+		}
+		if limit != maxFailingRepositories {
+			t.Errorf("got limit %d, want %d", limit, maxFailingRepositories)
+		}
+		return []*types.FailingRepo{
+			{
+				RepoID:              1,
+				RepoName:            "github.com/foo/bar",
+				ConsecutiveFailures: 12,
+				LastAttempt: api.RepoUpdateAttempt{
+					Kind:     "fetch",
+					Started:  started,
+					Duration: 1500 * time.Millisecond,
+					Error:    "failed to update: exit status 128",
+					Output:   "ERROR: Permission to foo/bar.git denied to deploy key\n",
+				},
+				LastSucceeded: &succeeded,
+			},
+			{
+				RepoID:              2,
+				RepoName:            "github.com/foo/deleted",
+				ConsecutiveFailures: 3,
+				LastAttempt:         api.RepoUpdateAttempt{Kind: "clone", Started: started, Error: "not cloneable"},
+			},
+		}, nil
+	}
+	defer func() { db.Mocks.RepoUpdateAttempts.ListFailing = nil }()
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: GraphQLSchema,
+			Query: `
+				{
+					site {
+						failingRepositories {
+							name
+							repository { name }
+							consecutiveFailures
+							lastAttempt {
+								kind
+								startedAt
+								durationMilliseconds
+								succeeded
+								error
+								output
+							}
+							lastSucceededAt
+						}
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"site": {
+						"failingRepositories": [
+							{
+								"name": "github.com/foo/bar",
+								"repository": { "name": "github.com/foo/bar" },
+								"consecutiveFailures": 12,
+								"lastAttempt": {
+									"kind": "FETCH",
+									"startedAt": "2019-06-01T12:00:00Z",
+									"durationMilliseconds": 1500,
+									"succeeded": false,
+									"error": "failed to update: exit status 128",
+									"output": "ERROR: Permission to foo/bar.git denied to deploy key\n"
+								},
+								"lastSucceededAt": "2019-05-29T12:00:00Z"
+							},
+							{
+								"name": "github.com/foo/deleted",
+								"repository": null,
+								"consecutiveFailures": 3,
+								"lastAttempt": {
+									"kind": "CLONE",
+									"startedAt": "2019-06-01T12:00:00Z",
+									"durationMilliseconds": 0,
+									"succeeded": false,
+									"error": "not cloneable",
+									"output": ""
+								},
+								"lastSucceededAt": null
+							}
+						]
+					}
+				}
+			`,
+		},
+	})
+}
+
+func TestLimitFromFirst(t *testing.T) {
+	int32Ptr := func(n int32) *int32 { return &n }
+	tests := []struct {
+		first   *int32
+		want    int
+		wantErr bool
+	}{
+		{first: nil, want: 20},
+		{first: int32Ptr(0), want: 0},
+		{first: int32Ptr(5), want: 5},
+		{first: int32Ptr(100), want: 20},
+		{first: int32Ptr(-1), wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := limitFromFirst(test.first, 20)
+		if (err != nil) != test.wantErr {
+			t.Errorf("limitFromFirst(%v): got error %v, want error %v", test.first, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("limitFromFirst(%v) = %d, want %d", test.first, got, test.want)
+		}
+	}
+}
+
+func TestSite_FailingRepositories_negativeFirst(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+	db.Mocks.RepoUpdateAttempts.ListFailing = func(context.Context, int, int) ([]*types.FailingRepo, error) {
+		t.Fatal("unexpected call to ListFailing")
+		return nil, nil
+	}
+	defer func() { db.Mocks.RepoUpdateAttempts.ListFailing = nil }()
+
+	first := int32(-1)
+	if _, err := (&siteResolver{}).FailingRepositories(context.Background(), &struct {
+		First                  *int32
+		MinConsecutiveFailures int32
+	}{First: &first, MinConsecutiveFailures: 3}); err == nil {
+		t.Fatal("expected an error for a negative first")
+	}
+}
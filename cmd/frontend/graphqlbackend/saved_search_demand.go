@@ -0,0 +1,81 @@
+package graphqlbackend
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/repoupdater"
+	"github.com/sourcegraph/sourcegraph/pkg/repoupdater/protocol"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// savedSearchDemandInterval is how often RecordSavedSearchDemand records the
+// repositories that saved searches are scoped to.
+const savedSearchDemandInterval = time.Hour
+
+// RecordSavedSearchDemand periodically tells repo-updater which repositories
+// the stored saved searches are scoped to, so that it keeps them more up to
+// date. repo-updater persists the demand, so it survives restarts of either
+// service. It runs until ctx is done.
+func RecordSavedSearchDemand(ctx context.Context) {
+	for {
+		if err := recordSavedSearchDemand(ctx); err != nil {
+			log15.Warn("Failed to record saved search demand.", "error", err)
+		}
+		select {
+		case <-time.After(savedSearchDemandInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func recordSavedSearchDemand(ctx context.Context) error {
+	savedSearches, err := db.SavedSearches.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	// 🚨 SECURITY: The resolved repositories are only used to schedule updates
+	// and are never shown to a user, so they are resolved without checking any
+	// user's repository permissions.
+	ctx = actor.WithActor(ctx, &actor.Actor{Internal: true})
+
+	seen := map[api.RepoID]struct{}{}
+	var ids []uint32
+	for _, s := range savedSearches {
+		q, err := query.ParseAndCheck(s.Config.Query)
+		if err != nil {
+			continue // the query-runner reports invalid queries to the saved search's owner
+		}
+
+		// Saved searches over all repositories say nothing about which ones
+		// users care about.
+		repoFilters, _ := q.RegexpPatterns(query.FieldRepo)
+		repoGroupFilters, _ := q.StringValues(query.FieldRepoGroup)
+		if len(repoFilters) == 0 && len(repoGroupFilters) == 0 {
+			continue
+		}
+
+		repoRevs, _, _, err := (&searchResolver{query: q}).resolveRepositories(ctx, nil)
+		if err != nil {
+			log15.Warn("Failed to resolve repositories of saved search.", "savedSearch", s.Spec.Key, "error", err)
+			continue
+		}
+		for _, rr := range repoRevs {
+			if _, ok := seen[rr.Repo.ID]; ok {
+				continue
+			}
+			seen[rr.Repo.ID] = struct{}{}
+			ids = append(ids, uint32(rr.Repo.ID))
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return repoupdater.DefaultClient.RecordRepoDemand(ctx, protocol.RepoDemandSavedSearch, ids...)
+}
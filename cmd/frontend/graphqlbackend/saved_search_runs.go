@@ -0,0 +1,76 @@
+package graphqlbackend
+
+import (
+	"context"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+)
+
+func (r savedSearchResolver) Runs(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+}) (*savedSearchRunConnectionResolver, error) {
+	// 🚨 SECURITY: Only users who may view the saved search may see its run history.
+	if err := checkSavedSearchOwnerAccess(ctx, r.s.UserID, r.s.OrgID); err != nil {
+		return nil, err
+	}
+
+	limit := 20
+	if args.First != nil {
+		limit = int(*args.First)
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	runs, totalCount, err := db.SavedSearchRuns.ListBySavedSearch(ctx, r.s.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &savedSearchRunConnectionResolver{runs: runs, totalCount: totalCount}, nil
+}
+
+type savedSearchRunConnectionResolver struct {
+	runs       []*db.SavedSearchRun
+	totalCount int
+}
+
+func (r *savedSearchRunConnectionResolver) Nodes() []*savedSearchRunResolver {
+	rs := make([]*savedSearchRunResolver, len(r.runs))
+	for i, run := range r.runs {
+		rs[i] = &savedSearchRunResolver{run: run}
+	}
+	return rs
+}
+
+func (r *savedSearchRunConnectionResolver) TotalCount() int32 {
+	return int32(r.totalCount)
+}
+
+func (r *savedSearchRunConnectionResolver) PageInfo() *graphqlutil.PageInfo {
+	return graphqlutil.HasNextPage(len(r.runs) < r.totalCount)
+}
+
+type savedSearchRunResolver struct {
+	run *db.SavedSearchRun
+}
+
+func (r *savedSearchRunResolver) ID() graphql.ID {
+	return relay.MarshalID("SavedSearchRun", r.run.ID)
+}
+
+func (r *savedSearchRunResolver) StartedAt() string {
+	return r.run.StartedAt.Format(time.RFC3339)
+}
+
+func (r *savedSearchRunResolver) DurationMilliseconds() int32 {
+	return int32(r.run.Duration / time.Millisecond)
+}
+
+func (r *savedSearchRunResolver) ResultCount() int32 { return r.run.ResultCount }
+
+func (r *savedSearchRunResolver) NewResultCount() int32 { return r.run.NewResultCount }
+
+func (r *savedSearchRunResolver) Error() *string { return r.run.Error }
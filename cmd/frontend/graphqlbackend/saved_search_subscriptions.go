@@ -0,0 +1,174 @@
+package graphqlbackend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+)
+
+func savedSearchSubscriptionChannelFromGraphQL(channel string) (string, error) {
+	switch channel {
+	case "EMAIL":
+		return db.SavedSearchSubscriptionEmail, nil
+	case "SLACK":
+		return db.SavedSearchSubscriptionSlack, nil
+	default:
+		return "", fmt.Errorf("invalid saved search subscription channel: %q", channel)
+	}
+}
+
+func savedSearchSubscriptionChannelToGraphQL(channel string) string {
+	switch channel {
+	case db.SavedSearchSubscriptionEmail:
+		return "EMAIL"
+	case db.SavedSearchSubscriptionSlack:
+		return "SLACK"
+	default:
+		panic("unexpected saved search subscription channel: " + channel)
+	}
+}
+
+func (r savedSearchResolver) Subscriptions(ctx context.Context) ([]*savedSearchSubscriptionResolver, error) {
+	// 🚨 SECURITY: Only users who may view the saved search may see who is subscribed to it.
+	if err := checkSavedSearchOwnerAccess(ctx, r.s.UserID, r.s.OrgID); err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := db.SavedSearchSubscriptions.ListBySavedSearch(ctx, r.s.ID)
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]*savedSearchSubscriptionResolver, len(subscriptions))
+	for i, s := range subscriptions {
+		rs[i] = &savedSearchSubscriptionResolver{subscription: s}
+	}
+	return rs, nil
+}
+
+func (r savedSearchResolver) ViewerSubscriptions(ctx context.Context) ([]string, error) {
+	user, err := CurrentUser(ctx)
+	if err != nil || user == nil {
+		return []string{}, err
+	}
+
+	subscriptions, err := db.SavedSearchSubscriptions.ListBySavedSearch(ctx, r.s.ID)
+	if err != nil {
+		return nil, err
+	}
+	channels := []string{}
+	for _, s := range subscriptions {
+		if s.UserID == user.DatabaseID() {
+			channels = append(channels, savedSearchSubscriptionChannelToGraphQL(s.Channel))
+		}
+	}
+	return channels, nil
+}
+
+// savedSearchSubscriptionResolver resolves a user's subscription to a saved search. The
+// subscription's Slack webhook URL is never exposed.
+type savedSearchSubscriptionResolver struct {
+	subscription *db.SavedSearchSubscription
+}
+
+func (r *savedSearchSubscriptionResolver) ID() graphql.ID {
+	return relay.MarshalID("SavedSearchSubscription", r.subscription.ID)
+}
+
+func (r *savedSearchSubscriptionResolver) User(ctx context.Context) (*UserResolver, error) {
+	return UserByIDInt32(ctx, r.subscription.UserID)
+}
+
+func (r *savedSearchSubscriptionResolver) Channel() string {
+	return savedSearchSubscriptionChannelToGraphQL(r.subscription.Channel)
+}
+
+func (r *savedSearchSubscriptionResolver) CreatedAt() string {
+	return r.subscription.CreatedAt.Format(time.RFC3339)
+}
+
+func (r *schemaResolver) SubscribeToSavedSearch(ctx context.Context, args *struct {
+	SavedSearch     graphql.ID
+	Channel         string
+	SlackWebhookURL *string
+}) (*savedSearchSubscriptionResolver, error) {
+	user, err := CurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, backend.ErrNotAuthenticated
+	}
+	savedSearchID, err := unmarshalSavedSearchID(args.SavedSearch)
+	if err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Only users who may view the saved search may subscribe to it.
+	if err := checkSavedSearchAccessByID(ctx, savedSearchID); err != nil {
+		return nil, err
+	}
+
+	channel, err := savedSearchSubscriptionChannelFromGraphQL(args.Channel)
+	if err != nil {
+		return nil, err
+	}
+	var slackWebhookURL *string
+	if channel == db.SavedSearchSubscriptionSlack {
+		if args.SlackWebhookURL == nil {
+			return nil, errors.New("a Slack webhook URL is required for Slack subscriptions")
+		}
+		if err := validateSavedSearchWebhook(*args.SlackWebhookURL, nil); err != nil {
+			return nil, err
+		}
+		slackWebhookURL = args.SlackWebhookURL
+	}
+
+	subscription, err := db.SavedSearchSubscriptions.Subscribe(ctx, savedSearchID, user.DatabaseID(), channel, slackWebhookURL)
+	if err != nil {
+		return nil, err
+	}
+	return &savedSearchSubscriptionResolver{subscription: subscription}, nil
+}
+
+func (r *schemaResolver) UnsubscribeFromSavedSearch(ctx context.Context, args *struct {
+	SavedSearch graphql.ID
+	Channel     string
+	User        *graphql.ID
+}) (*EmptyResponse, error) {
+	currentUser, err := CurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if currentUser == nil {
+		return nil, backend.ErrNotAuthenticated
+	}
+	userID := currentUser.DatabaseID()
+	if args.User != nil {
+		if userID, err = UnmarshalUserID(*args.User); err != nil {
+			return nil, err
+		}
+	}
+	// 🚨 SECURITY: Users may unsubscribe themselves (even if they may no longer view the saved
+	// search), but only site admins may unsubscribe other users.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	savedSearchID, err := unmarshalSavedSearchID(args.SavedSearch)
+	if err != nil {
+		return nil, err
+	}
+	channel, err := savedSearchSubscriptionChannelFromGraphQL(args.Channel)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.SavedSearchSubscriptions.Unsubscribe(ctx, savedSearchID, userID, channel); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
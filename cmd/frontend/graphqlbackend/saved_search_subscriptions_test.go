@@ -0,0 +1,126 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+func TestSubscribeToSavedSearch(t *testing.T) {
+	defer resetMocks()
+
+	ownerUserID := int32(1)
+	db.Mocks.SavedSearches.GetByID = func(ctx context.Context, id int32) (*api.SavedQuerySpecAndConfig, error) {
+		return &api.SavedQuerySpecAndConfig{Config: api.ConfigSavedQuery{Key: "52", UserID: &ownerUserID}}, nil
+	}
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: actor.FromContext(ctx).UID}, nil
+	}
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, Username: "u"}, nil
+	}
+	var subscribed bool
+	db.Mocks.SavedSearchSubscriptions.Subscribe = func(ctx context.Context, savedSearchID, userID int32, channel string, slackWebhookURL *string) (*db.SavedSearchSubscription, error) {
+		subscribed = true
+		if savedSearchID != 52 {
+			t.Errorf("got saved search ID %d, want 52", savedSearchID)
+		}
+		if userID != actor.FromContext(ctx).UID {
+			t.Errorf("got user ID %d, want the current user", userID)
+		}
+		return &db.SavedSearchSubscription{ID: 1, SavedSearchID: savedSearchID, UserID: userID, Channel: channel, SlackWebhookURL: slackWebhookURL}, nil
+	}
+
+	slackWebhookURL := "https://hooks.slack.com/services/x"
+	tests := map[string]struct {
+		ctx             context.Context
+		channel         string
+		slackWebhookURL *string
+		wantErr         bool
+	}{
+		"email": {
+			ctx:     actor.WithActor(context.Background(), &actor.Actor{UID: ownerUserID}),
+			channel: "EMAIL",
+		},
+		"slack": {
+			ctx:             actor.WithActor(context.Background(), &actor.Actor{UID: ownerUserID}),
+			channel:         "SLACK",
+			slackWebhookURL: &slackWebhookURL,
+		},
+		"slack without URL": {
+			ctx:     actor.WithActor(context.Background(), &actor.Actor{UID: ownerUserID}),
+			channel: "SLACK",
+			wantErr: true,
+		},
+		// 🚨 SECURITY: Users must not be able to subscribe to saved searches they may not view.
+		"other user": {
+			ctx:     actor.WithActor(context.Background(), &actor.Actor{UID: 2}),
+			channel: "EMAIL",
+			wantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			subscribed = false
+			_, err := (&schemaResolver{}).SubscribeToSavedSearch(test.ctx, &struct {
+				SavedSearch     graphql.ID
+				Channel         string
+				SlackWebhookURL *string
+			}{SavedSearch: marshalSavedSearchID(52), Channel: test.channel, SlackWebhookURL: test.slackWebhookURL})
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Errorf("got error %v, want error %v", err, test.wantErr)
+			}
+			if subscribed == test.wantErr {
+				t.Errorf("got subscribed %v, want %v", subscribed, !test.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnsubscribeFromSavedSearch(t *testing.T) {
+	defer resetMocks()
+
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: actor.FromContext(ctx).UID}, nil
+	}
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, Username: "u"}, nil
+	}
+	var unsubscribedUserID int32
+	db.Mocks.SavedSearchSubscriptions.Unsubscribe = func(ctx context.Context, savedSearchID, userID int32, channel string) error {
+		unsubscribedUserID = userID
+		return nil
+	}
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 2})
+	unsubscribe := func(user *graphql.ID) error {
+		unsubscribedUserID = 0
+		_, err := (&schemaResolver{}).UnsubscribeFromSavedSearch(ctx, &struct {
+			SavedSearch graphql.ID
+			Channel     string
+			User        *graphql.ID
+		}{SavedSearch: marshalSavedSearchID(52), Channel: "EMAIL", User: user})
+		return err
+	}
+
+	if err := unsubscribe(nil); err != nil {
+		t.Fatal(err)
+	}
+	if unsubscribedUserID != 2 {
+		t.Errorf("got unsubscribed user %d, want 2", unsubscribedUserID)
+	}
+
+	// 🚨 SECURITY: Non-site-admins must not be able to unsubscribe other users.
+	otherUser := marshalUserID(3)
+	if err := unsubscribe(&otherUser); err == nil {
+		t.Error("got nil error for unsubscribing other user")
+	}
+	if unsubscribedUserID != 0 {
+		t.Error("other user was unsubscribed")
+	}
+}
@@ -0,0 +1,266 @@
+package graphqlbackend
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/pkg/httpcli"
+)
+
+// checkSavedSearchOwnerAccess returns an error if the current user may not view or edit a saved
+// search owned by the given user or org.
+func checkSavedSearchOwnerAccess(ctx context.Context, userID, orgID *int32) error {
+	// 🚨 SECURITY: Only the owner user (or members of the owner org) and site admins may access the
+	// saved search.
+	switch {
+	case userID != nil:
+		return backend.CheckSiteAdminOrSameUser(ctx, *userID)
+	case orgID != nil:
+		return backend.CheckOrgAccess(ctx, *orgID)
+	default:
+		return errors.New("no Org ID or User ID associated with saved search")
+	}
+}
+
+// checkSavedSearchAccessByID is like checkSavedSearchOwnerAccess, but it looks up the saved search's
+// owner.
+func checkSavedSearchAccessByID(ctx context.Context, savedSearchID int32) error {
+	ss, err := db.SavedSearches.GetByID(ctx, savedSearchID)
+	if err != nil {
+		return err
+	}
+	return checkSavedSearchOwnerAccess(ctx, ss.Config.UserID, ss.Config.OrgID)
+}
+
+func (r savedSearchResolver) Webhooks(ctx context.Context) ([]*savedSearchWebhookResolver, error) {
+	// 🚨 SECURITY: Webhook URLs may contain credentials, so only show them to users who may edit the
+	// saved search.
+	if err := checkSavedSearchOwnerAccess(ctx, r.s.UserID, r.s.OrgID); err != nil {
+		return nil, err
+	}
+
+	webhooks, err := db.SavedSearchWebhooks.ListBySavedSearch(ctx, r.s.ID)
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]*savedSearchWebhookResolver, len(webhooks))
+	for i, w := range webhooks {
+		rs[i] = &savedSearchWebhookResolver{webhook: w}
+	}
+	return rs, nil
+}
+
+// savedSearchWebhookResolver resolves a saved search webhook. The webhook's secret is never exposed.
+type savedSearchWebhookResolver struct {
+	webhook *db.SavedSearchWebhook
+}
+
+func marshalSavedSearchWebhookID(id int32) graphql.ID {
+	return relay.MarshalID("SavedSearchWebhook", id)
+}
+
+func unmarshalSavedSearchWebhookID(id graphql.ID) (webhookID int32, err error) {
+	err = relay.UnmarshalSpec(id, &webhookID)
+	return
+}
+
+func (r *savedSearchWebhookResolver) ID() graphql.ID {
+	return marshalSavedSearchWebhookID(r.webhook.ID)
+}
+
+func (r *savedSearchWebhookResolver) URL() string { return r.webhook.URL }
+
+func (r *savedSearchWebhookResolver) CreatedAt() string {
+	return r.webhook.CreatedAt.Format(time.RFC3339)
+}
+
+func (r *savedSearchWebhookResolver) UpdatedAt() string {
+	return r.webhook.UpdatedAt.Format(time.RFC3339)
+}
+
+func (r *savedSearchWebhookResolver) Deliveries(ctx context.Context, args *struct {
+	First *int32
+}) (*savedSearchWebhookDeliveryConnectionResolver, error) {
+	limit := 20
+	if args.First != nil {
+		limit = int(*args.First)
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	deliveries, totalCount, err := db.SavedSearchWebhooks.ListDeliveries(ctx, r.webhook.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &savedSearchWebhookDeliveryConnectionResolver{deliveries: deliveries, totalCount: totalCount}, nil
+}
+
+type savedSearchWebhookDeliveryConnectionResolver struct {
+	deliveries []*db.SavedSearchWebhookDelivery
+	totalCount int
+}
+
+func (r *savedSearchWebhookDeliveryConnectionResolver) Nodes() []*savedSearchWebhookDeliveryResolver {
+	rs := make([]*savedSearchWebhookDeliveryResolver, len(r.deliveries))
+	for i, d := range r.deliveries {
+		rs[i] = &savedSearchWebhookDeliveryResolver{delivery: d}
+	}
+	return rs
+}
+
+func (r *savedSearchWebhookDeliveryConnectionResolver) TotalCount() int32 {
+	return int32(r.totalCount)
+}
+
+func (r *savedSearchWebhookDeliveryConnectionResolver) PageInfo() *graphqlutil.PageInfo {
+	return graphqlutil.HasNextPage(len(r.deliveries) < r.totalCount)
+}
+
+type savedSearchWebhookDeliveryResolver struct {
+	delivery *db.SavedSearchWebhookDelivery
+}
+
+func (r *savedSearchWebhookDeliveryResolver) ID() graphql.ID {
+	return relay.MarshalID("SavedSearchWebhookDelivery", r.delivery.ID)
+}
+
+func (r *savedSearchWebhookDeliveryResolver) Event() string { return r.delivery.Event }
+
+func (r *savedSearchWebhookDeliveryResolver) Payload() string { return r.delivery.Payload }
+
+func (r *savedSearchWebhookDeliveryResolver) State() string { return strings.ToUpper(r.delivery.State) }
+
+func (r *savedSearchWebhookDeliveryResolver) Attempts() int32 { return r.delivery.Attempts }
+
+func (r *savedSearchWebhookDeliveryResolver) ResponseStatusCode() *int32 {
+	return r.delivery.ResponseStatusCode
+}
+
+func (r *savedSearchWebhookDeliveryResolver) Error() *string { return r.delivery.Error }
+
+func (r *savedSearchWebhookDeliveryResolver) CreatedAt() string {
+	return r.delivery.CreatedAt.Format(time.RFC3339)
+}
+
+func (r *savedSearchWebhookDeliveryResolver) LastAttemptAt() *string {
+	return formatOptionalTime(r.delivery.LastAttemptAt)
+}
+
+func (r *savedSearchWebhookDeliveryResolver) NextAttemptAt() *string {
+	return formatOptionalTime(r.delivery.NextAttemptAt)
+}
+
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(time.RFC3339)
+	return &s
+}
+
+// validateSavedSearchWebhook returns an error if the webhook URL or secret is invalid.
+//
+// 🚨 SECURITY: This rejects URLs whose host is obviously not public (such as localhost or a private
+// IP address). It is not sufficient on its own, because host names may resolve to any address, so
+// the query-runner also checks the address when it connects (see httpcli.PublicAddressesOpt).
+func validateSavedSearchWebhook(webhookURL string, secret *string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("invalid webhook URL (must be an http or https URL)")
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errors.New("invalid webhook URL (must not refer to localhost)")
+	}
+	if ip := net.ParseIP(host); ip != nil && !httpcli.IsPublicIP(ip) {
+		return errors.New("invalid webhook URL (must not refer to a private, loopback, link-local, or unspecified IP address)")
+	}
+	if secret != nil && *secret == "" {
+		return errors.New("webhook secret must not be empty")
+	}
+	return nil
+}
+
+func (r *schemaResolver) AddSavedSearchWebhook(ctx context.Context, args *struct {
+	SavedSearch graphql.ID
+	URL         string
+	Secret      string
+}) (*savedSearchWebhookResolver, error) {
+	savedSearchID, err := unmarshalSavedSearchID(args.SavedSearch)
+	if err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Only users who may edit the saved search may add webhooks to it.
+	if err := checkSavedSearchAccessByID(ctx, savedSearchID); err != nil {
+		return nil, err
+	}
+	if err := validateSavedSearchWebhook(args.URL, &args.Secret); err != nil {
+		return nil, err
+	}
+
+	webhook, err := db.SavedSearchWebhooks.Create(ctx, savedSearchID, args.URL, args.Secret)
+	if err != nil {
+		return nil, err
+	}
+	return &savedSearchWebhookResolver{webhook: webhook}, nil
+}
+
+// savedSearchWebhookByID returns the webhook with the given GraphQL ID, if the current user may
+// edit its saved search.
+func savedSearchWebhookByID(ctx context.Context, id graphql.ID) (*db.SavedSearchWebhook, error) {
+	webhookID, err := unmarshalSavedSearchWebhookID(id)
+	if err != nil {
+		return nil, err
+	}
+	webhook, err := db.SavedSearchWebhooks.GetByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Only users who may edit the saved search may modify its webhooks.
+	if err := checkSavedSearchAccessByID(ctx, webhook.SavedSearchID); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (r *schemaResolver) UpdateSavedSearchWebhook(ctx context.Context, args *struct {
+	ID     graphql.ID
+	URL    string
+	Secret *string
+}) (*savedSearchWebhookResolver, error) {
+	webhook, err := savedSearchWebhookByID(ctx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSavedSearchWebhook(args.URL, args.Secret); err != nil {
+		return nil, err
+	}
+
+	webhook, err = db.SavedSearchWebhooks.Update(ctx, webhook.ID, args.URL, args.Secret)
+	if err != nil {
+		return nil, err
+	}
+	return &savedSearchWebhookResolver{webhook: webhook}, nil
+}
+
+func (r *schemaResolver) DeleteSavedSearchWebhook(ctx context.Context, args *struct {
+	ID graphql.ID
+}) (*EmptyResponse, error) {
+	webhook, err := savedSearchWebhookByID(ctx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.SavedSearchWebhooks.Delete(ctx, webhook.ID); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
@@ -0,0 +1,141 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+func TestAddSavedSearchWebhook(t *testing.T) {
+	defer resetMocks()
+
+	ownerUserID := int32(1)
+	db.Mocks.SavedSearches.GetByID = func(ctx context.Context, id int32) (*api.SavedQuerySpecAndConfig, error) {
+		return &api.SavedQuerySpecAndConfig{Config: api.ConfigSavedQuery{Key: "52", UserID: &ownerUserID}}, nil
+	}
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: actor.FromContext(ctx).UID}, nil
+	}
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, Username: "u"}, nil
+	}
+	var created bool
+	db.Mocks.SavedSearchWebhooks.Create = func(ctx context.Context, savedSearchID int32, url, secret string) (*db.SavedSearchWebhook, error) {
+		created = true
+		if savedSearchID != 52 {
+			t.Errorf("got saved search ID %d, want 52", savedSearchID)
+		}
+		return &db.SavedSearchWebhook{ID: 1, SavedSearchID: savedSearchID, URL: url, Secret: secret}, nil
+	}
+
+	savedSearchID := marshalSavedSearchID(52)
+	tests := map[string]struct {
+		ctx         context.Context
+		url, secret string
+		wantErr     bool
+	}{
+		"owner": {
+			ctx:    actor.WithActor(context.Background(), &actor.Actor{UID: ownerUserID}),
+			url:    "https://example.com/hook",
+			secret: "s",
+		},
+		// 🚨 SECURITY: Users must not be able to add webhooks to other users' saved searches.
+		"other user": {
+			ctx:     actor.WithActor(context.Background(), &actor.Actor{UID: 2}),
+			url:     "https://example.com/hook",
+			secret:  "s",
+			wantErr: true,
+		},
+		"invalid URL": {
+			ctx:     actor.WithActor(context.Background(), &actor.Actor{UID: ownerUserID}),
+			url:     "ftp://example.com/hook",
+			secret:  "s",
+			wantErr: true,
+		},
+		// 🚨 SECURITY: Webhooks must not be used to make requests to internal services.
+		"localhost URL": {
+			ctx:     actor.WithActor(context.Background(), &actor.Actor{UID: ownerUserID}),
+			url:     "http://localhost:3090/hook",
+			secret:  "s",
+			wantErr: true,
+		},
+		"link-local URL": {
+			ctx:     actor.WithActor(context.Background(), &actor.Actor{UID: ownerUserID}),
+			url:     "http://169.254.169.254/latest/meta-data/",
+			secret:  "s",
+			wantErr: true,
+		},
+		"private IP URL": {
+			ctx:     actor.WithActor(context.Background(), &actor.Actor{UID: ownerUserID}),
+			url:     "http://[fd00::1]/hook",
+			secret:  "s",
+			wantErr: true,
+		},
+		"empty secret": {
+			ctx:     actor.WithActor(context.Background(), &actor.Actor{UID: ownerUserID}),
+			url:     "https://example.com/hook",
+			wantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			created = false
+			_, err := (&schemaResolver{}).AddSavedSearchWebhook(test.ctx, &struct {
+				SavedSearch graphql.ID
+				URL         string
+				Secret      string
+			}{SavedSearch: savedSearchID, URL: test.url, Secret: test.secret})
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Errorf("got error %v, want error %v", err, test.wantErr)
+			}
+			if created == test.wantErr {
+				t.Errorf("got created %v, want %v", created, !test.wantErr)
+			}
+		})
+	}
+}
+
+func TestDeleteSavedSearchWebhook(t *testing.T) {
+	defer resetMocks()
+
+	ownerUserID := int32(1)
+	db.Mocks.SavedSearchWebhooks.GetByID = func(ctx context.Context, id int32) (*db.SavedSearchWebhook, error) {
+		return &db.SavedSearchWebhook{ID: id, SavedSearchID: 52}, nil
+	}
+	db.Mocks.SavedSearches.GetByID = func(ctx context.Context, id int32) (*api.SavedQuerySpecAndConfig, error) {
+		return &api.SavedQuerySpecAndConfig{Config: api.ConfigSavedQuery{Key: "52", UserID: &ownerUserID}}, nil
+	}
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: actor.FromContext(ctx).UID}, nil
+	}
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, Username: "u"}, nil
+	}
+	var deleted bool
+	db.Mocks.SavedSearchWebhooks.Delete = func(ctx context.Context, id int32) error {
+		deleted = true
+		return nil
+	}
+
+	// 🚨 SECURITY: Users must not be able to delete webhooks of other users' saved searches.
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 2})
+	if _, err := (&schemaResolver{}).DeleteSavedSearchWebhook(ctx, &struct{ ID graphql.ID }{ID: marshalSavedSearchWebhookID(3)}); err == nil {
+		t.Error("got nil error for other user")
+	}
+	if deleted {
+		t.Fatal("webhook was deleted by other user")
+	}
+
+	ctx = actor.WithActor(context.Background(), &actor.Actor{UID: ownerUserID})
+	if _, err := (&schemaResolver{}).DeleteSavedSearchWebhook(ctx, &struct{ ID graphql.ID }{ID: marshalSavedSearchWebhookID(3)}); err != nil {
+		t.Fatal(err)
+	}
+	if !deleted {
+		t.Error("webhook was not deleted")
+	}
+}
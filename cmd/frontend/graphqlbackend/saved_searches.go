@@ -3,13 +3,21 @@ package graphqlbackend
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/cmd/query-runner/queryrunnerapi"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/schedule"
 )
 
 type savedSearchResolver struct {
@@ -58,6 +66,8 @@ func savedSearchByID(ctx context.Context, id graphql.ID) (*savedSearchResolver,
 			UserID:          ss.Config.UserID,
 			OrgID:           ss.Config.OrgID,
 			SlackWebhookURL: ss.Config.SlackWebhookURL,
+			Schedule:        ss.Config.Schedule,
+			TimeoutSeconds:  ss.Config.TimeoutSeconds,
 		},
 	}
 	return savedSearch, nil
@@ -96,6 +106,69 @@ func (r savedSearchResolver) OrgID() *graphql.ID {
 }
 func (r savedSearchResolver) SlackWebhookURL() *string { return r.s.SlackWebhookURL }
 
+func (r savedSearchResolver) Schedule() *string { return r.s.Schedule }
+
+func (r savedSearchResolver) TimeoutSeconds() *int32 { return r.s.TimeoutSeconds }
+
+func (r savedSearchResolver) NextRunAt(ctx context.Context) (*string, error) {
+	state, err := r.runState(ctx)
+	if err != nil || state.NextRunAt == nil {
+		return nil, err
+	}
+	s := state.NextRunAt.Format(time.RFC3339)
+	return &s, nil
+}
+
+func (r savedSearchResolver) ConsecutiveFailures(ctx context.Context) (int32, error) {
+	state, err := r.runState(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int32(state.ConsecutiveFailures), nil
+}
+
+func (r savedSearchResolver) runState(ctx context.Context) (*api.SavedQueryRunState, error) {
+	// 🚨 SECURITY: Only users who may view the saved search may see its run state.
+	if err := checkSavedSearchOwnerAccess(ctx, r.s.UserID, r.s.OrgID); err != nil {
+		return nil, err
+	}
+	return db.SavedSearches.GetRunState(ctx, r.s.ID)
+}
+
+// maxSavedSearchTimeoutSeconds is the maximum timeout of a saved search's runs.
+const maxSavedSearchTimeoutSeconds = 30 * 60
+
+// validateSavedSearchSchedule returns an error if the saved search schedule or timeout is invalid.
+func validateSavedSearchSchedule(scheduleSpec *string, timeoutSeconds *int32) error {
+	if scheduleSpec != nil {
+		s, err := schedule.Parse(*scheduleSpec)
+		if err != nil {
+			return err
+		}
+		if s.Next(time.Now()).IsZero() {
+			return fmt.Errorf("invalid schedule %q: the saved search would never run", *scheduleSpec)
+		}
+	}
+	if timeoutSeconds != nil && (*timeoutSeconds <= 0 || *timeoutSeconds > maxSavedSearchTimeoutSeconds) {
+		return fmt.Errorf("invalid timeout %d: must be between 1 and %d seconds", *timeoutSeconds, maxSavedSearchTimeoutSeconds)
+	}
+	return nil
+}
+
+func (r savedSearchResolver) OwnerUser(ctx context.Context) (*UserResolver, error) {
+	if r.s.UserID == nil {
+		return nil, nil
+	}
+	return UserByIDInt32(ctx, *r.s.UserID)
+}
+
+func (r savedSearchResolver) OwnerOrg(ctx context.Context) (*OrgResolver, error) {
+	if r.s.OrgID == nil {
+		return nil, nil
+	}
+	return OrgByIDInt32(ctx, *r.s.OrgID)
+}
+
 func toSavedSearchResolver(entry types.SavedSearch) *savedSearchResolver {
 	return &savedSearchResolver{entry}
 }
@@ -120,6 +193,67 @@ func (r *schemaResolver) SavedSearches(ctx context.Context) ([]*savedSearchResol
 	return savedSearches, nil
 }
 
+func (r *schemaResolver) AllSavedSearches(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+	Query *string
+}) (*savedSearchConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins may list all saved searches.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	all, err := db.SavedSearches.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var query string
+	if args.Query != nil {
+		query = strings.ToLower(*args.Query)
+	}
+	var savedSearches []*savedSearchResolver
+	for _, ss := range all {
+		if query != "" && !strings.Contains(strings.ToLower(ss.Config.Description), query) && !strings.Contains(strings.ToLower(ss.Config.Query), query) {
+			continue
+		}
+		id, err := strconv.ParseInt(ss.Config.Key, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		savedSearches = append(savedSearches, toSavedSearchResolver(types.SavedSearch{
+			ID:              int32(id),
+			Description:     ss.Config.Description,
+			Query:           ss.Config.Query,
+			Notify:          ss.Config.Notify,
+			NotifySlack:     ss.Config.NotifySlack,
+			UserID:          ss.Config.UserID,
+			OrgID:           ss.Config.OrgID,
+			SlackWebhookURL: ss.Config.SlackWebhookURL,
+			Schedule:        ss.Config.Schedule,
+			TimeoutSeconds:  ss.Config.TimeoutSeconds,
+		}))
+	}
+	sort.Slice(savedSearches, func(i, j int) bool { return savedSearches[i].s.ID < savedSearches[j].s.ID })
+
+	conn := &savedSearchConnectionResolver{savedSearches: savedSearches, totalCount: len(savedSearches)}
+	if args.First != nil && int(*args.First) < len(savedSearches) {
+		conn.savedSearches = savedSearches[:*args.First]
+	}
+	return conn, nil
+}
+
+type savedSearchConnectionResolver struct {
+	savedSearches []*savedSearchResolver
+	totalCount    int
+}
+
+func (r *savedSearchConnectionResolver) Nodes() []*savedSearchResolver { return r.savedSearches }
+
+func (r *savedSearchConnectionResolver) TotalCount() int32 { return int32(r.totalCount) }
+
+func (r *savedSearchConnectionResolver) PageInfo() *graphqlutil.PageInfo {
+	return graphqlutil.HasNextPage(len(r.savedSearches) < r.totalCount)
+}
+
 func (r *schemaResolver) SendSavedSearchTestNotification(ctx context.Context, args *struct {
 	ID graphql.ID
 }) (*EmptyResponse, error) {
@@ -141,13 +275,19 @@ func (r *schemaResolver) SendSavedSearchTestNotification(ctx context.Context, ar
 }
 
 func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
-	Description string
-	Query       string
-	NotifyOwner bool
-	NotifySlack bool
-	OrgID       *graphql.ID
-	UserID      *graphql.ID
+	Description    string
+	Query          string
+	NotifyOwner    bool
+	NotifySlack    bool
+	OrgID          *graphql.ID
+	UserID         *graphql.ID
+	Schedule       *string
+	TimeoutSeconds *int32
 }) (*savedSearchResolver, error) {
+	if err := validateSavedSearchSchedule(args.Schedule, args.TimeoutSeconds); err != nil {
+		return nil, err
+	}
+
 	var userID *int32
 	var orgID *int32
 	// 🚨 SECURITY: Make sure the current user has permission to create a saved search for the specified user or org.
@@ -174,12 +314,14 @@ func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
 	}
 
 	ss, err := db.SavedSearches.Create(ctx, &types.SavedSearch{
-		Description: args.Description,
-		Query:       args.Query,
-		Notify:      args.NotifyOwner,
-		NotifySlack: args.NotifySlack,
-		UserID:      userID,
-		OrgID:       orgID,
+		Description:    args.Description,
+		Query:          args.Query,
+		Notify:         args.NotifyOwner,
+		NotifySlack:    args.NotifySlack,
+		UserID:         userID,
+		OrgID:          orgID,
+		Schedule:       args.Schedule,
+		TimeoutSeconds: args.TimeoutSeconds,
 	})
 	if err != nil {
 		return nil, err
@@ -189,14 +331,20 @@ func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
 }
 
 func (r *schemaResolver) UpdateSavedSearch(ctx context.Context, args *struct {
-	ID          graphql.ID
-	Description string
-	Query       string
-	NotifyOwner bool
-	NotifySlack bool
-	OrgID       *graphql.ID
-	UserID      *graphql.ID
+	ID             graphql.ID
+	Description    string
+	Query          string
+	NotifyOwner    bool
+	NotifySlack    bool
+	OrgID          *graphql.ID
+	UserID         *graphql.ID
+	Schedule       *string
+	TimeoutSeconds *int32
 }) (*savedSearchResolver, error) {
+	if err := validateSavedSearchSchedule(args.Schedule, args.TimeoutSeconds); err != nil {
+		return nil, err
+	}
+
 	var userID, orgID *int32
 	// 🚨 SECURITY: Make sure the current user has permission to update a saved search for the specified user or org.
 	if args.UserID != nil {
@@ -227,13 +375,15 @@ func (r *schemaResolver) UpdateSavedSearch(ctx context.Context, args *struct {
 	}
 
 	ss, err := db.SavedSearches.Update(ctx, &types.SavedSearch{
-		ID:          id,
-		Description: args.Description,
-		Query:       args.Query,
-		Notify:      args.NotifyOwner,
-		NotifySlack: args.NotifySlack,
-		UserID:      userID,
-		OrgID:       orgID,
+		ID:             id,
+		Description:    args.Description,
+		Query:          args.Query,
+		Notify:         args.NotifyOwner,
+		NotifySlack:    args.NotifySlack,
+		UserID:         userID,
+		OrgID:          orgID,
+		Schedule:       args.Schedule,
+		TimeoutSeconds: args.TimeoutSeconds,
 	})
 	if err != nil {
 		return nil, err
@@ -61,12 +61,14 @@ func TestCreateSavedSearch(t *testing.T) {
 	}
 	userID := marshalUserID(key)
 	savedSearches, err := (&schemaResolver{}).CreateSavedSearch(ctx, &struct {
-		Description string
-		Query       string
-		NotifyOwner bool
-		NotifySlack bool
-		OrgID       *graphql.ID
-		UserID      *graphql.ID
+		Description    string
+		Query          string
+		NotifyOwner    bool
+		NotifySlack    bool
+		OrgID          *graphql.ID
+		UserID         *graphql.ID
+		Schedule       *string
+		TimeoutSeconds *int32
 	}{Description: "test query", Query: "test type:diff", NotifyOwner: true, NotifySlack: false, OrgID: nil, UserID: &userID})
 	if err != nil {
 		t.Fatal(err)
@@ -90,6 +92,54 @@ func TestCreateSavedSearch(t *testing.T) {
 	}
 }
 
+func TestCreateSavedSearch_invalidSchedule(t *testing.T) {
+	ctx := context.Background()
+	defer resetMocks()
+
+	key := int32(1)
+	db.Mocks.SavedSearches.Create = func(ctx context.Context, newSavedSearch *types.SavedSearch) (*types.SavedSearch, error) {
+		t.Error("want saved search to not be created")
+		return nil, nil
+	}
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true, ID: key}, nil
+	}
+	userID := marshalUserID(key)
+	validSchedule, timeoutSeconds := "@daily", int32(60)
+	zeroTimeout, longTimeout := int32(0), int32(maxSavedSearchTimeoutSeconds+1)
+	tests := map[string]struct {
+		schedule       *string
+		timeoutSeconds *int32
+	}{
+		"too frequent": {schedule: strptr("@every 10s")},
+		"invalid cron": {schedule: strptr("0 9 * *")},
+		"never runs":   {schedule: strptr("0 0 31 2 *")},
+		"zero timeout": {schedule: &validSchedule, timeoutSeconds: &zeroTimeout},
+		"long timeout": {schedule: &validSchedule, timeoutSeconds: &longTimeout},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := (&schemaResolver{}).CreateSavedSearch(ctx, &struct {
+				Description    string
+				Query          string
+				NotifyOwner    bool
+				NotifySlack    bool
+				OrgID          *graphql.ID
+				UserID         *graphql.ID
+				Schedule       *string
+				TimeoutSeconds *int32
+			}{Description: "test query", Query: "test type:diff", NotifyOwner: true, UserID: &userID, Schedule: test.schedule, TimeoutSeconds: test.timeoutSeconds})
+			if err == nil {
+				t.Error("got nil error, want error")
+			}
+		})
+	}
+
+	if err := validateSavedSearchSchedule(&validSchedule, &timeoutSeconds); err != nil {
+		t.Errorf("valid schedule: got error %q", err)
+	}
+}
+
 func TestUpdateSavedSearch(t *testing.T) {
 	ctx := context.Background()
 	defer resetMocks()
@@ -106,13 +156,15 @@ func TestUpdateSavedSearch(t *testing.T) {
 	}
 	userID := marshalUserID(key)
 	savedSearches, err := (&schemaResolver{}).UpdateSavedSearch(ctx, &struct {
-		ID          graphql.ID
-		Description string
-		Query       string
-		NotifyOwner bool
-		NotifySlack bool
-		OrgID       *graphql.ID
-		UserID      *graphql.ID
+		ID             graphql.ID
+		Description    string
+		Query          string
+		NotifyOwner    bool
+		NotifySlack    bool
+		OrgID          *graphql.ID
+		UserID         *graphql.ID
+		Schedule       *string
+		TimeoutSeconds *int32
 	}{ID: marshalSavedSearchID(key), Description: "updated query description", Query: "test type:diff", NotifyOwner: true, NotifySlack: false, OrgID: nil, UserID: &userID})
 	if err != nil {
 		t.Fatal(err)
@@ -107,6 +107,24 @@ type Mutation {
     #
     # Only site admins may perform this mutation.
     deleteRepository(repository: ID!): EmptyResponse @deprecated(reason: "update external service exclude setting.")
+    # Sets the users that are explicitly allowed to read the repository, replacing the users that were
+    # previously allowed. If users is empty, no user is explicitly allowed to read the repository
+    # anymore.
+    #
+    # Explicit permissions are only enforced for repositories of Gitolite and other external services
+    # whose configuration has the "authorization" field set.
+    #
+    # Only site admins may perform this mutation.
+    setRepositoryPermissionsForUsers(repository: ID!, users: [UserIdentifierInput!]!): EmptyResponse!
+    # Sets the users that are explicitly allowed to read all repositories whose names match the pattern
+    # (a regular expression), replacing the users that were previously allowed. If users is empty,
+    # the pattern is removed.
+    #
+    # Explicit permissions are only enforced for repositories of Gitolite and other external services
+    # whose configuration has the "authorization" field set.
+    #
+    # Only site admins may perform this mutation.
+    setRepositoryPatternPermissionsForUsers(pattern: String!, users: [UserIdentifierInput!]!): EmptyResponse!
     # Creates a new user account.
     #
     # Only site admins may perform this mutation.
@@ -120,6 +138,31 @@ type Mutation {
     #
     # Only site admins may perform this mutation.
     randomizeUserPassword(user: ID!): RandomizeUserPasswordResult!
+    # Begins enrolling the current user in multi-factor authentication with a time-based one-time password
+    # (TOTP) from an authenticator app. The enrollment takes effect only after it is confirmed with
+    # Mutation.confirmTOTPEnrollment. Calling this mutation again before confirming replaces the pending
+    # enrollment.
+    #
+    # Multi-factor authentication applies only to signing in with a username and password (with the builtin
+    # authentication provider).
+    beginTOTPEnrollment: TOTPEnrollment!
+    # Confirms the current user's pending multi-factor authentication enrollment, which enables it. It returns
+    # the user's recovery codes, each of which may be used once instead of a code from the authenticator app.
+    confirmTOTPEnrollment(
+        # A code from the authenticator app (to verify that the user added the secret to it).
+        code: String!
+    ): [String!]!
+    # Replaces the current user's multi-factor authentication recovery codes and returns the new codes.
+    regenerateMFARecoveryCodes(
+        # A code from the authenticator app. (Recovery codes are not accepted.)
+        code: String!
+    ): [String!]!
+    # Disables multi-factor authentication for a user (for example, if the user lost their authenticator app
+    # and recovery codes). If multi-factor authentication is required for the user, the user must enroll again
+    # the next time they sign in.
+    #
+    # Only site admins may perform this mutation.
+    resetUserMFA(user: ID!): EmptyResponse!
     # Adds an email address to the user's account. The email address will be marked as unverified until the user
     # has followed the email verification process.
     #
@@ -150,6 +193,21 @@ type Mutation {
     # - Discussion threads and comments created by the user.
     #
     deleteUser(user: ID!, hard: Boolean): EmptyResponse
+    # Suspends a user. A suspended user can't sign in or use access tokens, and all of their sessions are
+    # revoked. Unlike deleting a user, suspending a user keeps all of their data (such as saved searches,
+    # settings and discussions).
+    #
+    # Only site admins may perform this mutation.
+    suspendUser(user: ID!): EmptyResponse
+    # Reactivates a suspended user.
+    #
+    # Only site admins may perform this mutation.
+    activateUser(user: ID!): EmptyResponse
+    # Approves a user who is pending approval (see the auth.userApprovalRequired critical configuration
+    # property), which allows the user to sign in.
+    #
+    # Only site admins may perform this mutation.
+    approveUser(user: ID!): EmptyResponse
     # Updates the current user's password. The oldPassword arg must match the user's current password.
     updatePassword(oldPassword: String!, newPassword: String!): EmptyResponse
     # Creates an access token that grants the privileges of the specified user (referred to as the access token's
@@ -161,14 +219,37 @@ type Mutation {
     # - "user:all": Full control of all resources accessible to the user account.
     # - "site-admin:sudo": Ability to perform any action as any other user. (Only site admins may create tokens
     #   with this scope.)
+    # - "site-admin:scim": Ability to provision users and organizations with the SCIM API. (Only site admins may
+    #   create tokens with this scope.)
+    # - "search:read": Ability to search.
+    # - "repo:read": Read access to repositories and their contents.
+    # - "repo:write": Ability to update repositories and discussions (implies "repo:read").
+    # - "settings:read": Read access to settings and saved searches.
+    # - "settings:write": Ability to change settings and saved searches (implies "settings:read").
+    # - "user:read": Read access to the user account, its emails and organizations.
+    # - "user:write": Ability to change the user account, its emails and organizations (implies "user:read").
+    #
+    # An access token whose scopes do not include "user:all" may only perform the API operations that its scopes
+    # permit. Such a token can't be used to create other access tokens or to perform site admin operations.
+    #
+    # If expiresAt (an RFC 3339 timestamp in the future) is given, the access token is invalid after that time.
     #
     # Only the user or site admins may perform this mutation.
-    createAccessToken(user: ID!, scopes: [String!]!, note: String!): CreateAccessTokenResult!
+    createAccessToken(user: ID!, scopes: [String!]!, note: String!, expiresAt: String): CreateAccessTokenResult!
     # Deletes and immediately revokes the specified access token, specified by either its ID or by the token
     # itself.
     #
     # Only site admins or the user who owns the token may perform this mutation.
     deleteAccessToken(byID: ID, byToken: String): EmptyResponse!
+    # Revokes a user session, which signs the user out of the browser or device that the session is for.
+    #
+    # Only site admins or the user who owns the session may perform this mutation.
+    revokeUserSession(session: ID!): EmptyResponse!
+    # Revokes all of the user's sessions, which signs the user out everywhere (including the current session, if
+    # it is the user's).
+    #
+    # Only site admins or the user may perform this mutation.
+    revokeAllUserSessions(user: ID!): EmptyResponse!
     # Deletes the association between an external account and its Sourcegraph user. It does NOT delete the external
     # account on the external service where it resides.
     #
@@ -243,6 +324,10 @@ type Mutation {
         url: String!
     ): EmptyResponse
     # Resolves a revision for a given diff from Phabricator.
+    #
+    # If the revision doesn't exist yet, it is created as a new commit in the repository. That
+    # requires write access to the repository, which must not be read-only (see the
+    # "repositories.readOnly" site configuration property).
     resolvePhabricatorDiff(
         # The name of the repository that the diff is based on.
         repoName: String!
@@ -328,6 +413,11 @@ type Mutation {
         notifySlack: Boolean!
         orgID: ID
         userID: ID
+        # The schedule on which the saved search runs (see SavedSearch.schedule), or null for the default
+        # schedule.
+        schedule: String
+        # The maximum number of seconds that a run may take (at most 1800), or null for the default timeout.
+        timeoutSeconds: Int
     ): SavedSearch!
     # Updates a saved search
     updateSavedSearch(
@@ -338,9 +428,90 @@ type Mutation {
         notifySlack: Boolean!
         orgID: ID
         userID: ID
+        # The schedule on which the saved search runs (see SavedSearch.schedule), or null for the default
+        # schedule.
+        schedule: String
+        # The maximum number of seconds that a run may take (at most 1800), or null for the default timeout.
+        timeoutSeconds: Int
     ): SavedSearch!
     # Deletes a saved search
     deleteSavedSearch(id: ID!): EmptyResponse
+    # Adds a webhook to a saved search. When the saved search has new results, a JSON payload describing the
+    # saved search and its new matches is POSTed to the webhook's URL.
+    #
+    # Only users who may edit the saved search may perform this mutation.
+    addSavedSearchWebhook(
+        # The ID of the saved search.
+        savedSearch: ID!
+        # The http or https URL that notifications are POSTed to.
+        url: String!
+        # The secret used to sign each request body. The X-Sourcegraph-Signature request header contains
+        # "sha256=" followed by the hex-encoded HMAC-SHA256 of the request body (keyed by the secret).
+        secret: String!
+    ): SavedSearchWebhook!
+    # Updates a saved search webhook.
+    #
+    # Only users who may edit the webhook's saved search may perform this mutation.
+    updateSavedSearchWebhook(
+        # The ID of the webhook.
+        id: ID!
+        # The http or https URL that notifications are POSTed to.
+        url: String!
+        # The new secret, or null to keep the current secret.
+        secret: String
+    ): SavedSearchWebhook!
+    # Deletes a saved search webhook (and its delivery log).
+    #
+    # Only users who may edit the webhook's saved search may perform this mutation.
+    deleteSavedSearchWebhook(id: ID!): EmptyResponse
+    # Subscribes the current user to notifications about a saved search's new results on the channel (in
+    # addition to the saved search's owner). If the user is already subscribed on the channel, the
+    # subscription's Slack webhook URL is updated.
+    #
+    # Only users who may view the saved search (its owner user, members of its owner org, and site admins) may
+    # perform this mutation.
+    subscribeToSavedSearch(
+        # The ID of the saved search.
+        savedSearch: ID!
+        # The channel on which to notify the user.
+        channel: SavedSearchSubscriptionChannel!
+        # The Slack webhook URL that messages are posted to. Required for the SLACK channel.
+        slackWebhookURL: String
+    ): SavedSearchSubscription!
+    # Unsubscribes a user from notifications about a saved search on the channel.
+    #
+    # Only site admins may unsubscribe users other than the current user.
+    unsubscribeFromSavedSearch(
+        # The ID of the saved search.
+        savedSearch: ID!
+        # The channel to unsubscribe from.
+        channel: SavedSearchSubscriptionChannel!
+        # The user to unsubscribe. Defaults to the current user.
+        user: ID
+    ): EmptyResponse
+    # Marks the user's notifications as read. Notifications that are not the user's are ignored.
+    #
+    # Only site admins or the user may perform this mutation.
+    markNotificationsRead(
+        # The user whose notifications to mark as read.
+        user: ID!
+        # The notifications to mark as read. If null, all of the user's notifications are marked as read.
+        notifications: [ID!]
+    ): EmptyResponse!
+    # Updates the channels on which the user receives notifications.
+    #
+    # Only site admins or the user may perform this mutation.
+    updateNotificationSettings(
+        # The user whose notification settings to update.
+        user: ID!
+        # Whether to show notifications in Sourcegraph.
+        inApp: Boolean!
+        # Whether to email notifications to the user's primary email address (if it is verified).
+        email: Boolean!
+        # The Slack webhook URL (e.g., of a direct message channel) that notifications are posted to, or null to
+        # not post notifications to Slack.
+        slackWebhookURL: String
+    ): NotificationSettings!
 }
 
 # A new external service.
@@ -363,6 +534,28 @@ input UpdateExternalServiceInput {
     config: String
 }
 
+# Identifies an existing user. Exactly one field must be set.
+input UserIdentifierInput {
+    # The user's username.
+    username: String
+    # One of the user's verified email addresses.
+    email: String
+    # An external account of the user.
+    externalAccount: ExternalAccountIdentifierInput
+}
+
+# Identifies an external account.
+input ExternalAccountIdentifierInput {
+    # The type of the external service (e.g., "gitlab").
+    serviceType: String!
+    # The ID of the external service (e.g., "https://gitlab.example.com/").
+    serviceID: String!
+    # The client ID of the external service, if any.
+    clientID: String
+    # The ID of the account on the external service.
+    accountID: String!
+}
+
 # A selection within a file.
 input DiscussionThreadTargetRepoSelectionInput {
     # The line that the selection started on (zero-based, inclusive).
@@ -445,7 +638,57 @@ input DiscussionThreadTargetRepoInput {
     revision: GitObjectID
 
     # The selection that the thread was referencing, if any.
+    #
+    # This must be null if 'diff' is specified (use DiscussionThreadTargetRepoDiffInput.selection instead).
     selection: DiscussionThreadTargetRepoSelectionInput
+
+    # The diff of a commit or comparison that the thread is on, if any. If
+    # specified, 'branch' and 'revision' must be null, and 'path' (if any) is
+    # the old or new path of a file in the diff.
+    diff: DiscussionThreadTargetRepoDiffInput
+}
+
+# The diff of a commit or of a comparison between two revisions that a
+# discussion thread is on.
+input DiscussionThreadTargetRepoDiffInput {
+    # The commit whose diff (against its first parent) the thread is on, or
+    # the head of the comparison that the thread is on.
+    head: GitObjectID!
+
+    # The base of the comparison that the thread is on, or null if the thread
+    # is on the diff of the 'head' commit.
+    base: GitObjectID
+
+    # The selected lines of the file diff, if any. This requires the 'path' of
+    # the DiscussionThreadTargetRepoInput.
+    selection: DiscussionThreadTargetRepoDiffSelectionInput
+}
+
+# A selection of lines in a file diff, from 'start' to 'end' (both inclusive,
+# in the order in which the lines appear in the diff).
+input DiscussionThreadTargetRepoDiffSelectionInput {
+    # The first selected line.
+    start: DiffPositionInput!
+
+    # The last selected line.
+    end: DiffPositionInput!
+}
+
+# A line on one side of a file diff.
+input DiffPositionInput {
+    # The side of the diff. Unchanged lines are on both sides.
+    side: DiffSide!
+
+    # The line in the file of that side (zero-based).
+    line: Int!
+}
+
+# A side of a file diff.
+enum DiffSide {
+    # The old (original) file.
+    OLD
+    # The new (changed) file.
+    NEW
 }
 
 # Describes the creation of a new thread around some target (e.g. a file in a repo).
@@ -473,6 +716,17 @@ input DiscussionThreadUpdateInput {
     # When non-null, indicates that the thread should be archived.
     archive: Boolean
 
+    # When non-null, indicates that the thread should be marked as resolved (true) or unresolved
+    # (false). The viewer is recorded as the user who resolved the thread. Resolving an
+    # already-resolved thread does not change who resolved it or when.
+    resolve: Boolean
+
+    # When non-null, indicates that the thread should be assigned to the user with this ID.
+    assignee: ID
+
+    # When true, indicates that the thread's assignee should be removed.
+    unassign: Boolean
+
     # When non-null, indicates that the thread should be deleted. Only admins
     # can perform this action.
     delete: Boolean
@@ -501,6 +755,13 @@ input DiscussionCommentUpdateInput {
     #
     # An error will be returned if the comment's canClearReports field is false.
     clearReports: Boolean
+
+    # When non-null, adds the viewer's reaction with this emoji to the comment. The emoji must be
+    # one of 👍, 👎, 😄, 🎉, 😕, ❤️, 🚀, or 👀.
+    addReaction: String
+
+    # When non-null, removes the viewer's reaction with this emoji from the comment.
+    removeReaction: String
 }
 
 # Mutations for discussions.
@@ -642,6 +903,14 @@ type RandomizeUserPasswordResult {
     resetPasswordURL: String
 }
 
+# The result for Mutation.beginTOTPEnrollment.
+type TOTPEnrollment {
+    # The TOTP secret (in base32 encoding) to enter in the authenticator app.
+    secret: String!
+    # The otpauth:// URL that authenticator apps accept to add the secret (usually presented as a QR code).
+    url: String!
+}
+
 # Input for a user satisfaction (NPS) survey submission.
 input SurveySubmissionInput {
     # User-provided email address, if there is no currently authenticated user. If there is, this value
@@ -677,6 +946,18 @@ type Query {
         # Returns the first n external services from the list.
         first: Int
     ): ExternalServiceConnection!
+    # Previews the changes to the set of repositories that syncing an external service with the given
+    # configuration would make, without saving the configuration or modifying any repositories. Only site
+    # admins may perform this query.
+    externalServiceDryRun(
+        # The ID of an existing external service whose configuration would be replaced. If omitted, the
+        # configuration is previewed as a new external service.
+        id: ID
+        # The kind of the external service.
+        kind: ExternalServiceKind!
+        # The configuration of the external service (JSONC).
+        config: String!
+    ): ExternalServiceDryRun!
     # List all repositories.
     repositories(
         # Returns the first n repositories from the list.
@@ -734,6 +1015,10 @@ type Query {
         tag: String
         # Returns users who have been active in a given period of time.
         activePeriod: UserActivePeriod
+        # Return only users in the given state.
+        #
+        # Only site admins may use this argument.
+        state: UserState
     ): UserConnection!
     # Looks up an organization by name.
     organization(name: String!): Org
@@ -772,6 +1057,10 @@ type Query {
         #
         # If the path ends with "/**", any path below that is matched.
         targetRepositoryPath: String
+        # When present, lists only the threads that are resolved (true) or unresolved (false).
+        resolved: Boolean
+        # When present, lists only the threads assigned to this user.
+        assigneeUserID: ID
     ): DiscussionThreadConnection!
     # Looks up a discussion thread by its DiscussionThread#idWithoutKind value.
     #
@@ -806,6 +1095,16 @@ type Query {
     ): Search
     # All saved searches configured for the current user, merged from all configurations.
     savedSearches: [SavedSearch!]!
+    # All saved searches on the site (including those owned by other users and orgs), for monitoring the
+    # saved searches that notify users of new results.
+    #
+    # Only site admins may perform this query.
+    allSavedSearches(
+        # Returns the first n saved searches from the list.
+        first: Int
+        # Return saved searches whose descriptions or queries match the query.
+        query: String
+    ): SavedSearchConnection!
     # All repository groups for the current user, merged from all configurations.
     repoGroups: [RepoGroup!]!
     # The current site.
@@ -866,7 +1165,7 @@ type Search {
 }
 
 # A search result.
-union SearchResult = FileMatch | CommitSearchResult | Repository | CodemodResult
+union SearchResult = FileMatch | CommitSearchResult | Repository | CodemodResult | DiscussionSearchResult
 
 # An object representing a markdown string.
 type Markdown {
@@ -1010,6 +1309,156 @@ type SavedSearch implements Node {
     orgID: ID
     # The Slack webhook URL associated with this saved search, if any.
     slackWebhookURL: String
+    # The webhooks that receive notifications about this saved search's new results.
+    webhooks: [SavedSearchWebhook!]!
+    # The owner if the owner is a user.
+    ownerUser: User
+    # The owner if the owner is an org.
+    ownerOrg: Org
+    # The users' subscriptions to notifications about this saved search's new results (in addition to the
+    # notifications that are sent to its owner).
+    subscriptions: [SavedSearchSubscription!]!
+    # The channels on which the current user is subscribed to notifications about this saved search.
+    viewerSubscriptions: [SavedSearchSubscriptionChannel!]!
+    # The saved search's most recent runs, newest first. Only the 100 most recent runs are kept.
+    runs(
+        # Returns the first n runs from the list.
+        first: Int
+    ): SavedSearchRunConnection!
+    # The schedule on which the saved search runs (if it notifies anyone of new results), either an interval
+    # such as "@every 1h" or a cron expression such as "0 9 * * 1-5" (evaluated in UTC). If null, the saved
+    # search runs at an interval proportional to the time that it takes to run.
+    schedule: String
+    # The maximum number of seconds that a run of the saved search may take. If null, the default timeout
+    # is used.
+    timeoutSeconds: Int
+    # When the saved search is next due to run (taking into account back-off after failed runs), or null if
+    # it is due now.
+    nextRunAt: String
+    # The number of consecutive runs (including the most recent) that failed. Saved searches whose runs fail
+    # are run less often.
+    consecutiveFailures: Int!
+}
+
+# A list of saved searches.
+type SavedSearchConnection {
+    # A list of saved searches.
+    nodes: [SavedSearch!]!
+    # The total count of saved searches in the connection. This total count may be larger than the number of
+    # nodes in this object when the result is paginated.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# A user's subscription to notifications about a saved search's new results.
+type SavedSearchSubscription {
+    # The unique ID of the subscription.
+    id: ID!
+    # The subscribed user.
+    user: User!
+    # The channel on which the user is notified.
+    channel: SavedSearchSubscriptionChannel!
+    # The date when the user subscribed.
+    createdAt: String!
+}
+
+# A channel on which a user is notified about a saved search's new results.
+enum SavedSearchSubscriptionChannel {
+    # Email the user (at their primary email address).
+    EMAIL
+    # Post a message to the subscription's Slack webhook URL.
+    SLACK
+}
+
+# A list of saved search runs.
+type SavedSearchRunConnection {
+    # A list of runs.
+    nodes: [SavedSearchRun!]!
+    # The total count of runs in the connection. This total count may be larger than the number of nodes in
+    # this object when the result is paginated.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# A run of a saved search by the query runner, which runs saved searches periodically to find new results.
+type SavedSearchRun {
+    # The unique ID of the run.
+    id: ID!
+    # The date when the run started.
+    startedAt: String!
+    # The duration of the run in milliseconds.
+    durationMilliseconds: Int!
+    # The number of matches in the search results.
+    resultCount: Int!
+    # The number of matches that were not in the results of the previous run.
+    newResultCount: Int!
+    # The error that occurred during the run, if any.
+    error: String
+}
+
+# A webhook that receives notifications about a saved search's new results.
+type SavedSearchWebhook {
+    # The unique ID of the webhook.
+    id: ID!
+    # The URL that notifications are POSTed to.
+    url: String!
+    # The date when the webhook was created.
+    createdAt: String!
+    # The date when the webhook was last updated.
+    updatedAt: String!
+    # The webhook's delivery log, newest first. Completed deliveries are kept for 30 days.
+    deliveries(
+        # Returns the first n deliveries from the list.
+        first: Int
+    ): SavedSearchWebhookDeliveryConnection!
+}
+
+# A list of saved search webhook deliveries.
+type SavedSearchWebhookDeliveryConnection {
+    # A list of deliveries.
+    nodes: [SavedSearchWebhookDelivery!]!
+    # The total count of deliveries in the connection. This total count may be larger than the number of nodes
+    # in this object when the result is paginated.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# A delivery of a notification to a saved search webhook. Failed deliveries are retried with exponential
+# backoff.
+type SavedSearchWebhookDelivery {
+    # The unique ID of the delivery.
+    id: ID!
+    # The type of the notification ("saved_search.new_results" or "saved_search.test").
+    event: String!
+    # The JSON request body.
+    payload: String!
+    # The state of the delivery.
+    state: SavedSearchWebhookDeliveryState!
+    # The number of delivery attempts so far.
+    attempts: Int!
+    # The HTTP status code of the response to the last attempt, if any.
+    responseStatusCode: Int
+    # The error from the last attempt, if it failed.
+    error: String
+    # The date when the delivery was created.
+    createdAt: String!
+    # The date of the last attempt, if any.
+    lastAttemptAt: String
+    # The date when the next attempt is due, if the delivery is pending.
+    nextAttemptAt: String
+}
+
+# The state of a saved search webhook delivery.
+enum SavedSearchWebhookDeliveryState {
+    # The delivery has not succeeded yet and will be attempted (or retried).
+    PENDING
+    # The delivery succeeded.
+    SUCCEEDED
+    # All attempts to deliver failed.
+    FAILED
 }
 
 # A search query description.
@@ -1060,6 +1509,23 @@ type CommitSearchResult implements GenericSearchResultInterface {
     diffPreview: HighlightedString
 }
 
+# A discussion thread whose title or comments matched a search query.
+type DiscussionSearchResult implements GenericSearchResultInterface {
+    # The discussion thread.
+    thread: DiscussionThread!
+    # URL to an icon that is displayed with every search result.
+    icon: String!
+    # A markdown string that is rendered prominently.
+    label: Markdown!
+    # The URL of the result (the thread's inline view).
+    url: String!
+    # A markdown string that is rendered less prominently.
+    detail: Markdown!
+    # The thread's comments that matched the search query (if only the title matched, this list
+    # is empty).
+    matches: [SearchResultMatch!]!
+}
+
 # The result of a code modification query.
 type CodemodResult implements GenericSearchResultInterface {
     # URL to an icon that is displayed with every search result.
@@ -1112,6 +1578,18 @@ type ExternalServiceConnection {
     pageInfo: PageInfo!
 }
 
+# The result of previewing an external service configuration (see Query.externalServiceDryRun).
+type ExternalServiceDryRun {
+    # The names of the repositories that would be added.
+    added: [String!]!
+    # The names of the repositories that would be removed.
+    removed: [String!]!
+    # The names of the repositories whose metadata would be updated.
+    modified: [String!]!
+    # The number of repositories that would be left unchanged.
+    unmodifiedCount: Int!
+}
+
 # A specific kind of external service.
 enum ExternalServiceKind {
     AWSCODECOMMIT
@@ -1178,6 +1656,9 @@ type Repository implements Node & GenericSearchResultInterface {
     uri: String! @deprecated(reason: "Use name.")
     # The repository's description.
     description: String!
+    # The users that are explicitly allowed to read this repository (not including users that are allowed
+    # to read it through a repository pattern). Only visible to site admins.
+    explicitlyPermittedUsers: [User!]!
     # The primary programming language in the repository.
     language: String!
     # DEPRECATED: All repositories are enabled. This field will be removed in 3.6.
@@ -1322,6 +1803,54 @@ type MirrorRepositoryInfo {
     updateSchedule: UpdateSchedule
     # The state of this repository in the update queue.
     updateQueue: UpdateQueue
+    # The recent attempts to clone or fetch this repository from the remote source repository, most recent
+    # first. The 20 most recent attempts are kept.
+    #
+    # Only site admins may access this field.
+    updateHistory(
+        # Returns the first n attempts from the list (at most 20). Must not be negative.
+        first: Int
+    ): [RepositoryUpdateAttempt!]!
+}
+
+# An attempt to clone or fetch a repository from its remote source repository.
+type RepositoryUpdateAttempt {
+    # Whether the attempt was a clone or a fetch.
+    kind: RepositoryUpdateAttemptKind!
+    # When the attempt started.
+    startedAt: String!
+    # How long the attempt took, in milliseconds.
+    durationMilliseconds: Int!
+    # Whether the attempt succeeded.
+    succeeded: Boolean!
+    # The error that the attempt failed with, or null if it succeeded.
+    error: String
+    # The (truncated) output of the Git command, with credentials redacted.
+    output: String!
+    # The number of bytes received from the remote, or 0 if Git did not report it.
+    bytesReceived: Float!
+}
+
+# The kind of a repository update attempt.
+enum RepositoryUpdateAttemptKind {
+    # The repository was cloned.
+    CLONE
+    # The repository was fetched.
+    FETCH
+}
+
+# A repository whose recent clone or fetch attempts have all failed.
+type FailingRepository {
+    # The name of the repository.
+    name: String!
+    # The repository, or null if it no longer exists on Sourcegraph.
+    repository: Repository
+    # The number of failed attempts since the last successful attempt.
+    consecutiveFailures: Int!
+    # The most recent (failed) attempt.
+    lastAttempt: RepositoryUpdateAttempt!
+    # When the last successful attempt finished, or null if there was none since gitserver last started.
+    lastSucceededAt: String
 }
 
 # The state of a repository in the update schedule.
@@ -1334,6 +1863,25 @@ type UpdateSchedule {
     index: Int!
     # The total number of repos in the schedule.
     total: Int!
+    # How much users are interested in the repository, based on how recently it was viewed or searched.
+    # Hot repositories are updated more often and cold repositories less often.
+    #
+    # Searches (including saved searches) only count as demand if they are scoped to 25 repositories or
+    # fewer. Demand is tracked in memory by repo-updater, so it is lost when repo-updater restarts, and no
+    # repository is considered cold until repo-updater has been receiving demand signals for 14 days.
+    demand: RepositoryDemand
+    # An explanation of how the interval was chosen.
+    intervalReason: String
+}
+
+# How much users are interested in a repository.
+enum RepositoryDemand {
+    # The repository was viewed or searched within the last day.
+    HOT
+    # The repository is neither hot nor cold.
+    WARM
+    # The repository was not viewed or searched within the last 14 days.
+    COLD
 }
 
 # The state of a repository in the update queue.
@@ -1429,6 +1977,12 @@ type RepositoryComparison {
         # Return the first n file diffs from the list.
         first: Int
     ): FileDiffConnection!
+    # The discussion threads on this comparison. If the base is the first parent of the head,
+    # this includes the threads on the diff of the head commit.
+    discussionThreads(
+        # Returns the first n threads from the list.
+        first: Int
+    ): DiscussionThreadConnection!
 }
 
 # A list of file diffs.
@@ -1816,6 +2370,11 @@ type GitCommit implements Node {
         # file paths returned in the list.
         includePatterns: [String!]
     ): SymbolConnection!
+    # The discussion threads on the diff of this commit (against its first parent).
+    discussionThreads(
+        # Returns the first n threads from the list.
+        first: Int
+    ): DiscussionThreadConnection!
 }
 
 # A set of Git behind/ahead counts for one commit relative to another.
@@ -2158,6 +2717,10 @@ type User implements Node & SettingsSubject {
     #
     # Only the user and site admins can access this field.
     siteAdmin: Boolean!
+    # The state of the user account.
+    #
+    # Only the user and site admins can access this field.
+    state: UserState!
     # The latest settings for the user.
     #
     # Only the user and site admins can access this field.
@@ -2186,6 +2749,10 @@ type User implements Node & SettingsSubject {
     #
     # Only the user and site admins can access this field.
     emails: [UserEmail!]!
+    # Whether the user has enabled multi-factor authentication (for signing in with a username and password).
+    #
+    # Only the user and site admins can access this field.
+    mfaEnabled: Boolean!
     # The user's access tokens (which grant to the holder the privileges of the user). This consists
     # of all access tokens whose subject is this user.
     #
@@ -2204,6 +2771,24 @@ type User implements Node & SettingsSubject {
     # Only the currently authenticated user can access this field. Site admins are not able to access sessions for
     # other users.
     session: Session!
+    # The user's active sessions (the browsers and devices where the user is signed in), most recently active
+    # first.
+    #
+    # Only the user and site admins can access this field.
+    sessions: [UserSession!]!
+    # The user's notifications, newest first.
+    #
+    # Only the user and site admins can access this field.
+    notifications(
+        # Returns the first n notifications from the list.
+        first: Int
+        # Only return notifications that have not been read.
+        unreadOnly: Boolean = false
+    ): NotificationConnection!
+    # The channels on which the user receives notifications.
+    #
+    # Only the user and site admins can access this field.
+    notificationSettings: NotificationSettings!
     # Whether the viewer has admin privileges on this user. The user has admin privileges on their own user, and
     # site admins have admin privileges on all users.
     viewerCanAdminister: Boolean!
@@ -2245,6 +2830,8 @@ type AccessToken implements Node {
     createdAt: String!
     # The date when the access token was last used to authenticate a request.
     lastUsedAt: String
+    # The date after which the access token is no longer valid, or null if it does not expire.
+    expiresAt: String
 }
 
 # A list of access tokens.
@@ -2258,6 +2845,53 @@ type AccessTokenConnection {
     pageInfo: PageInfo!
 }
 
+# A list of audit log entries.
+type AuditLogEntryConnection {
+    # A list of audit log entries.
+    nodes: [AuditLogEntry!]!
+    # The total count of audit log entries in the connection. This total count may be larger than the number of
+    # nodes in this object when the result is paginated.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# An entry in the audit log, which records a security-relevant action performed on the site.
+type AuditLogEntry {
+    # The user who performed the action, or null if the action was not performed by a user or the user
+    # has since been deleted.
+    actor: User
+    # The username of the user who performed the action, at the time of the action.
+    actorUsername: String!
+    # The IP address of the HTTP client (or proxy) that requested the action.
+    remoteAddr: String!
+    # The value of the X-Forwarded-For header of the request. This value is supplied by the client and
+    # must not be trusted.
+    forwardedFor: String!
+    # The name of the action (e.g., "setUserIsSiteAdmin").
+    action: String!
+    # The kind of the target of the action (e.g., "User").
+    targetKind: String!
+    # The ID of the target of the action (usually its GraphQL ID).
+    targetID: String!
+    # A human-readable name of the target of the action (e.g., the username).
+    targetName: String!
+    # The changes that the action made to the target.
+    changes: [AuditLogChange!]!
+    # The time when the action was performed.
+    createdAt: String!
+}
+
+# A change to a field of the target of an audited action.
+type AuditLogChange {
+    # The name of the field.
+    field: String!
+    # The value of the field before the action, or null if it is unknown or may be secret.
+    before: JSONValue
+    # The value of the field after the action, or null if it is unknown or may be secret.
+    after: JSONValue
+}
+
 # A list of authentication providers.
 type AuthProviderConnection {
     # A list of authentication providers.
@@ -2328,6 +2962,87 @@ type ExternalAccount implements Node {
 type Session {
     # Whether the user can sign out of this session on Sourcegraph.
     canSignOut: Boolean!
+    # Whether the user satisfied multi-factor authentication when signing in to this session.
+    mfaSatisfied: Boolean!
+}
+
+# A session of a user (a browser or device where the user is signed in).
+type UserSession {
+    # The unique ID of the session.
+    id: ID!
+    # The date when the user signed in to the session.
+    createdAt: String!
+    # The date when the session was last used. This is updated at most every few minutes.
+    lastActiveAt: String!
+    # The date when the session expires (unless it is used before then).
+    expiresAt: String!
+    # The IP address of the client when the session was last used.
+    remoteAddr: String!
+    # The User-Agent of the client when the session was last used.
+    userAgent: String!
+    # The type of the authentication provider that the user signed in with (e.g., "builtin" or "saml"), or null
+    # if unknown.
+    authProvider: String
+    # Whether this is the session that the viewer is using for the current request.
+    isCurrent: Boolean!
+}
+
+# A notification of an event for a user.
+type Notification {
+    # The unique ID of the notification.
+    id: ID!
+    # The kind of event that the notification is about.
+    kind: NotificationKind!
+    # The user who caused the event, if any.
+    actor: User
+    # The discussion thread that the event occurred in, if any.
+    thread: DiscussionThread
+    # The discussion comment that the event refers to, if any.
+    comment: DiscussionComment
+    # A short plain-text summary of the event.
+    title: String!
+    # A plain-text excerpt (e.g., of a comment's contents), or an empty string.
+    body: String!
+    # The URL to the subject of the event.
+    url: String!
+    # The date when the notification was created.
+    createdAt: String!
+    # The date when the user read the notification, or null if it is unread.
+    readAt: String
+}
+
+# The kind of event that a notification is about.
+enum NotificationKind {
+    # A discussion thread was created that the user is subscribed to.
+    DISCUSSION_THREAD
+    # A comment was added to a discussion thread that the user is subscribed to.
+    DISCUSSION_COMMENT
+    # The user was @mentioned in a discussion thread's title or a comment.
+    DISCUSSION_MENTION
+}
+
+# A list of notifications.
+type NotificationConnection {
+    # A list of notifications.
+    nodes: [Notification!]!
+    # The total count of notifications in the connection. This total count may be larger than the number of nodes
+    # in this object when the result is paginated.
+    totalCount: Int!
+    # The count of the user's unread notifications (regardless of whether the connection only includes unread
+    # notifications).
+    unreadCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# The channels on which a user receives notifications.
+type NotificationSettings {
+    # Whether notifications are shown in Sourcegraph.
+    inApp: Boolean!
+    # Whether notifications are emailed to the user's primary email address (if it is verified).
+    email: Boolean!
+    # The Slack webhook URL that notifications are posted to, if any.
+    slackWebhookURL: String
 }
 
 # An organization membership.
@@ -2473,6 +3188,29 @@ type DiscussionSelectionRange {
     endCharacter: Int!
 }
 
+# Where a discussion thread's selection is at a Git revision.
+type DiscussionSelectionRelocation {
+    # Whether the selected lines changed at the revision.
+    status: DiscussionSelectionRelocationStatus!
+
+    # The path of the file at the revision, or null if the file was deleted.
+    path: String
+
+    # The selection at the revision, or null if the selection is outdated.
+    range: DiscussionSelectionRange
+}
+
+# Whether the selected lines of a discussion thread changed at a Git revision.
+enum DiscussionSelectionRelocationStatus {
+    # The selected lines are unchanged, although they may have moved.
+    UNCHANGED
+    # The selected lines were edited, and were located by fuzzy matching.
+    CHANGED
+    # The selected lines could not be located (e.g. because they or the file
+    # were removed).
+    OUTDATED
+}
+
 # A selection within a file.
 type DiscussionThreadTargetRepoSelection {
     # The line that the selection started on (zero-based, inclusive).
@@ -2552,6 +3290,50 @@ type DiscussionThreadTargetRepo {
     # failed) null is returned and it should be assumed the selection does not
     # exist in this revision.
     relativeSelection(rev: String!): DiscussionSelectionRange
+
+    # Where the selection is at the given Git revision specifier
+    # (branch/commit/etc), accounting for changes to the file since the
+    # revision that the thread was created on.
+    #
+    # The selection is relocated through the diff between the two revisions
+    # (following file renames). If the selected lines changed, they (and the
+    # lines around them) are searched for in the file, exactly and then
+    # fuzzily. If they can't be found, the selection is outdated. If the thread
+    # has no revision (e.g., it was created on a branch, which may have moved
+    # since), the selected lines are searched for in the file at the thread's
+    # path.
+    #
+    # null is returned if the thread has no selection.
+    relocatedSelection(rev: String!): DiscussionSelectionRelocation
+
+    # The diff of a commit or comparison that the thread is on, if any.
+    diff: DiscussionThreadTargetRepoDiff
+}
+
+# The diff of a commit or of a comparison between two revisions that a
+# discussion thread is on.
+type DiscussionThreadTargetRepoDiff {
+    # The commit whose diff (against its first parent) the thread is on, or
+    # the head of the comparison that the thread is on.
+    head: GitRef!
+
+    # The base of the comparison that the thread is on, or null if the thread
+    # is on the diff of the 'head' commit.
+    base: GitRef
+
+    # The path of the file in the old revision, or null if the file was added
+    # (or the thread is not on a file).
+    oldPath: String
+
+    # The selected lines of the old file, or null if no lines of the old file
+    # were selected. The characters are always zero.
+    oldSelection: DiscussionSelectionRange
+
+    # The selected lines of the new file, or null if no lines of the new file
+    # were selected. The characters are always zero.
+    #
+    # If non-null, this is the same as DiscussionThreadTargetRepo.selection.
+    newSelection: DiscussionSelectionRange
 }
 
 # The target of a discussion thread. Today, the only possible target is a
@@ -2597,6 +3379,16 @@ type DiscussionThread implements Node {
     # The date when the discussion thread was archived (or null if it has not).
     archivedAt: String
 
+    # The date when the discussion thread was resolved (or null if it is unresolved).
+    resolvedAt: String
+
+    # The user who resolved the discussion thread (or null if it is unresolved or the user no
+    # longer exists).
+    resolvedBy: User
+
+    # The user the discussion thread is assigned to (or null if it is unassigned).
+    assignee: User
+
     # The comments in the discussion thread.
     comments(
         # Returns the first n comments from the list.
@@ -2644,6 +3436,9 @@ type DiscussionComment implements Node {
     # The date when the discussion thread was last updated.
     updatedAt: String!
 
+    # The reactions to the comment, grouped by emoji.
+    reactions: [DiscussionCommentReactionGroup!]!
+
     # Reports filed by users about this comment. Only admins will receive a non
     # empty list of reports.
     #
@@ -2665,6 +3460,21 @@ type DiscussionComment implements Node {
     canClearReports: Boolean!
 }
 
+# A group of the reactions to a discussion comment that have the same emoji.
+type DiscussionCommentReactionGroup {
+    # The emoji of the reactions (one of 👍, 👎, 😄, 🎉, 😕, ❤️, 🚀, or 👀).
+    emoji: String!
+
+    # The number of users who reacted with the emoji.
+    count: Int!
+
+    # The users who reacted with the emoji, in the order that they reacted.
+    users: [User!]!
+
+    # Whether the viewer reacted with the emoji.
+    viewerHasReacted: Boolean!
+}
+
 # A list of discussion threads.
 type DiscussionThreadConnection {
     # A list of discussion threads.
@@ -2725,6 +3535,14 @@ type DefaultSettings implements SettingsSubject {
         )
 }
 
+# The users that are explicitly allowed to read the repositories whose names match a pattern.
+type ExplicitRepositoryPermissionPattern {
+    # The regular expression matched against repository names.
+    pattern: String!
+    # The users that are allowed to read the matching repositories.
+    users: [User!]!
+}
+
 # A site is an installation of Sourcegraph that consists of one or more
 # servers that share the same configuration and database.
 #
@@ -2761,9 +3579,32 @@ type Site implements SettingsSubject {
         # Returns the first n access tokens from the list.
         first: Int
     ): AccessTokenConnection!
+    # The audit log of security-relevant actions performed on this site (such as changes to the site
+    # configuration, site admin status, access tokens and repository permissions), most recent first.
+    #
+    # Only site admins can access this field.
+    auditLog(
+        # Returns the first n entries from the list.
+        first: Int
+        # Only return entries for actions performed by this user.
+        actor: ID
+        # Only return entries for this action (e.g., "setUserIsSiteAdmin").
+        action: String
+        # Only return entries whose target is of this kind (e.g., "User").
+        targetKind: String
+        # Only return entries whose target has this ID (e.g., the GraphQL ID of a user).
+        targetID: String
+        # Only return entries for actions performed at or after this time (an RFC 3339 timestamp).
+        since: String
+        # Only return entries for actions performed before this time (an RFC 3339 timestamp).
+        until: String
+    ): AuditLogEntryConnection!
     # A list of all authentication providers. This information is visible to all viewers and does not contain any
     # secret information.
     authProviders: AuthProviderConnection!
+    # The repository patterns for which users are explicitly allowed to read the matching repositories.
+    # Only visible to site admins.
+    explicitRepositoryPermissionPatterns: [ExplicitRepositoryPermissionPattern!]!
     # A list of all user external accounts on this site.
     externalAccounts(
         # Returns the first n external accounts from the list.
@@ -2786,6 +3627,14 @@ type Site implements SettingsSubject {
     updateCheck: UpdateCheck!
     # Whether the site needs to be configured to add repositories.
     needsRepositoryConfiguration: Boolean!
+    # The repositories whose most recent clone or fetch attempts have all failed, ordered by the number of
+    # consecutive failures (descending). Only site admins may access this field.
+    failingRepositories(
+        # Returns the first n repositories from the list (at most 1000). Must not be negative.
+        first: Int
+        # The number of consecutive failed attempts after which a repository is considered failing.
+        minConsecutiveFailures: Int = 3
+    ): [FailingRepository!]!
     # Whether the site is over the limit for free user accounts, and a warning needs to be shown to all users.
     # Only applies if the site does not have a valid license.
     freeUsersExceeded: Boolean!
@@ -2990,6 +3839,17 @@ enum UserEvent {
     STAGEAUTOMATE
 }
 
+# The state of a user account.
+enum UserState {
+    # The user can sign in and use the site.
+    ACTIVE
+    # The user was suspended by a site admin. The user can't sign in or use access tokens, but their data is
+    # kept.
+    SUSPENDED
+    # The user was created automatically on sign-in and can't sign in until a site admin approves them.
+    PENDING_APPROVAL
+}
+
 # A period of time in which a set of users have been active.
 enum UserActivePeriod {
     # Since today at 00:00 UTC.
@@ -3703,6 +4563,8 @@ type ProductSubscriptionEvent {
 # The type of a StatusMessage
 enum StatusMessageType {
     CLONING
+    # Requests to a code host are being delayed because a consumer exhausted its request budget.
+    RATE_LIMIT
 }
 
 # A status message
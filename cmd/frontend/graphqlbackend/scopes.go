@@ -0,0 +1,225 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+)
+
+// rootTypes are the names of the GraphQL root operation types.
+var rootTypes = map[string]struct{}{
+	"Query":        {},
+	"Mutation":     {},
+	"Subscription": {},
+}
+
+// fieldScopes is the access token scope required to select each field, keyed by "Type.field". An
+// empty scope means that any access token may select the field (and its resolver performs the
+// necessary checks).
+//
+// 🚨 SECURITY: Fields of the root types that are not listed require the "user:all" scope, so that
+// new root fields are denied to restricted access tokens until they are added here. Fields of other
+// types can only be reached through a root field, so they are permitted unless they expose data
+// from a different area than the root fields that they are reached from.
+var fieldScopes = map[string]string{
+	"Query.__schema": "",
+	"Query.__type":   "",
+
+	"Query.root":                "",
+	"Query.node":                "", // checked by (*schemaResolver).Node
+	"Query.renderMarkdown":      "",
+	"Query.highlightCode":       "",
+	"Query.clientConfiguration": "",
+	"Query.statusMessages":      "",
+
+	"Query.search":     authz.ScopeSearchRead,
+	"Query.repoGroups": authz.ScopeSearchRead,
+
+	"Query.repository":         authz.ScopeRepoRead,
+	"Query.repositories":       authz.ScopeRepoRead,
+	"Query.phabricatorRepo":    authz.ScopeRepoRead,
+	"Query.discussionThreads":  authz.ScopeRepoRead,
+	"Query.discussionThread":   authz.ScopeRepoRead,
+	"Query.discussionComments": authz.ScopeRepoRead,
+
+	"Query.currentUser":   authz.ScopeUserRead,
+	"Query.user":          authz.ScopeUserRead,
+	"Query.users":         authz.ScopeUserRead,
+	"Query.organization":  authz.ScopeUserRead,
+	"Query.organizations": authz.ScopeUserRead,
+
+	"Query.settingsSubject":     authz.ScopeSettingsRead,
+	"Query.viewerSettings":      authz.ScopeSettingsRead,
+	"Query.viewerConfiguration": authz.ScopeSettingsRead,
+	"Query.savedSearches":       authz.ScopeSettingsRead,
+	"Query.extensionRegistry":   authz.ScopeSettingsRead,
+
+	"Query.externalServices":      authz.ScopeUserAll,
+	"Query.externalServiceDryRun": authz.ScopeUserAll,
+	"Query.allSavedSearches":      authz.ScopeUserAll,
+	"Query.site":                  authz.ScopeUserAll,
+	"Query.surveyResponses":       authz.ScopeUserAll,
+	"Query.topQueries":            authz.ScopeUserAll,
+	"Query.dotcom":                authz.ScopeUserAll,
+
+	"Mutation.discussions":            authz.ScopeRepoWrite,
+	"Mutation.resolvePhabricatorDiff": authz.ScopeRepoWrite,
+
+	"Mutation.settingsMutation":                authz.ScopeSettingsWrite,
+	"Mutation.configurationMutation":           authz.ScopeSettingsWrite,
+	"Mutation.createSavedSearch":               authz.ScopeSettingsWrite,
+	"Mutation.updateSavedSearch":               authz.ScopeSettingsWrite,
+	"Mutation.deleteSavedSearch":               authz.ScopeSettingsWrite,
+	"Mutation.sendSavedSearchTestNotification": authz.ScopeSettingsWrite,
+	"Mutation.addSavedSearchWebhook":           authz.ScopeSettingsWrite,
+	"Mutation.updateSavedSearchWebhook":        authz.ScopeSettingsWrite,
+	"Mutation.deleteSavedSearchWebhook":        authz.ScopeSettingsWrite,
+	"Mutation.subscribeToSavedSearch":          authz.ScopeSettingsWrite,
+	"Mutation.unsubscribeFromSavedSearch":      authz.ScopeSettingsWrite,
+
+	"Mutation.updateUser":                               authz.ScopeUserWrite,
+	"Mutation.updatePassword":                           authz.ScopeUserWrite,
+	"Mutation.addUserEmail":                             authz.ScopeUserWrite,
+	"Mutation.removeUserEmail":                          authz.ScopeUserWrite,
+	"Mutation.deleteAccessToken":                        authz.ScopeUserWrite,
+	"Mutation.revokeUserSession":                        authz.ScopeUserWrite,
+	"Mutation.revokeAllUserSessions":                    authz.ScopeUserWrite,
+	"Mutation.deleteExternalAccount":                    authz.ScopeUserWrite,
+	"Mutation.createOrganization":                       authz.ScopeUserWrite,
+	"Mutation.updateOrganization":                       authz.ScopeUserWrite,
+	"Mutation.inviteUserToOrganization":                 authz.ScopeUserWrite,
+	"Mutation.respondToOrganizationInvitation":          authz.ScopeUserWrite,
+	"Mutation.resendOrganizationInvitationNotification": authz.ScopeUserWrite,
+	"Mutation.revokeOrganizationInvitation":             authz.ScopeUserWrite,
+	"Mutation.addUserToOrganization":                    authz.ScopeUserWrite,
+	"Mutation.removeUserFromOrganization":               authz.ScopeUserWrite,
+	"Mutation.logUserEvent":                             authz.ScopeUserWrite,
+	"Mutation.markNotificationsRead":                    authz.ScopeUserWrite,
+	"Mutation.updateNotificationSettings":               authz.ScopeUserWrite,
+	"Mutation.submitSurvey":                             authz.ScopeUserWrite,
+
+	// Creating access tokens, changing authentication factors, and site admin operations.
+	"Mutation.createAccessToken":                       authz.ScopeUserAll,
+	"Mutation.beginTOTPEnrollment":                     authz.ScopeUserAll,
+	"Mutation.confirmTOTPEnrollment":                   authz.ScopeUserAll,
+	"Mutation.regenerateMFARecoveryCodes":              authz.ScopeUserAll,
+	"Mutation.resetUserMFA":                            authz.ScopeUserAll,
+	"Mutation.deleteOrganization":                      authz.ScopeUserAll,
+	"Mutation.addExternalService":                      authz.ScopeUserAll,
+	"Mutation.updateExternalService":                   authz.ScopeUserAll,
+	"Mutation.deleteExternalService":                   authz.ScopeUserAll,
+	"Mutation.setRepositoryEnabled":                    authz.ScopeUserAll,
+	"Mutation.setAllRepositoriesEnabled":               authz.ScopeUserAll,
+	"Mutation.checkMirrorRepositoryConnection":         authz.ScopeUserAll,
+	"Mutation.updateMirrorRepository":                  authz.ScopeUserAll,
+	"Mutation.updateAllMirrorRepositories":             authz.ScopeUserAll,
+	"Mutation.deleteRepository":                        authz.ScopeUserAll,
+	"Mutation.setRepositoryPermissionsForUsers":        authz.ScopeUserAll,
+	"Mutation.setRepositoryPatternPermissionsForUsers": authz.ScopeUserAll,
+	"Mutation.createUser":                              authz.ScopeUserAll,
+	"Mutation.randomizeUserPassword":                   authz.ScopeUserAll,
+	"Mutation.setUserEmailVerified":                    authz.ScopeUserAll,
+	"Mutation.deleteUser":                              authz.ScopeUserAll,
+	"Mutation.suspendUser":                             authz.ScopeUserAll,
+	"Mutation.activateUser":                            authz.ScopeUserAll,
+	"Mutation.approveUser":                             authz.ScopeUserAll,
+	"Mutation.setTag":                                  authz.ScopeUserAll,
+	"Mutation.addPhabricatorRepo":                      authz.ScopeUserAll,
+	"Mutation.updateSiteConfiguration":                 authz.ScopeUserAll,
+	"Mutation.setUserIsSiteAdmin":                      authz.ScopeUserAll,
+	"Mutation.reloadSite":                              authz.ScopeUserAll,
+	"Mutation.requestTrial":                            authz.ScopeUserAll,
+	"Mutation.extensionRegistry":                       authz.ScopeUserAll,
+	"Mutation.clearManagementConsolePlaintextPassword": authz.ScopeUserAll,
+	"Mutation.dotcom":                                  authz.ScopeUserAll,
+
+	"User.latestSettings":                  authz.ScopeSettingsRead,
+	"User.settingsCascade":                 authz.ScopeSettingsRead,
+	"User.configurationCascade":            authz.ScopeSettingsRead,
+	"Org.latestSettings":                   authz.ScopeSettingsRead,
+	"Org.settingsCascade":                  authz.ScopeSettingsRead,
+	"Org.configurationCascade":             authz.ScopeSettingsRead,
+	"DefaultSettings.latestSettings":       authz.ScopeSettingsRead,
+	"DefaultSettings.settingsCascade":      authz.ScopeSettingsRead,
+	"DefaultSettings.configurationCascade": authz.ScopeSettingsRead,
+	"Site.latestSettings":                  authz.ScopeSettingsRead,
+	"Site.settingsCascade":                 authz.ScopeSettingsRead,
+	"Site.configurationCascade":            authz.ScopeSettingsRead,
+	"SettingsSubject.latestSettings":       authz.ScopeSettingsRead,
+	"SettingsSubject.settingsCascade":      authz.ScopeSettingsRead,
+	"SettingsSubject.configurationCascade": authz.ScopeSettingsRead,
+
+	"User.emails":           authz.ScopeUserRead,
+	"User.accessTokens":     authz.ScopeUserRead,
+	"User.sessions":         authz.ScopeUserRead,
+	"User.externalAccounts": authz.ScopeUserRead,
+	"Site.accessTokens":     authz.ScopeUserAll,
+	"Site.externalAccounts": authz.ScopeUserAll,
+}
+
+// nodeKindScopes is the access token scope required to look up a node of each kind with
+// Query.node. Kinds that are not listed require the "user:all" scope.
+var nodeKindScopes = map[string]string{
+	"Repository":             authz.ScopeRepoRead,
+	"GitCommit":              authz.ScopeRepoRead,
+	"GitRef":                 authz.ScopeRepoRead,
+	"DiscussionThread":       authz.ScopeRepoRead,
+	"DiscussionComment":      authz.ScopeRepoRead,
+	"User":                   authz.ScopeUserRead,
+	"Org":                    authz.ScopeUserRead,
+	"OrganizationInvitation": authz.ScopeUserRead,
+	"AccessToken":            authz.ScopeUserRead,
+	"ExternalAccount":        authz.ScopeUserRead,
+	"SavedSearch":            authz.ScopeSettingsRead,
+	"RegistryExtension":      authz.ScopeSettingsRead,
+}
+
+// checkFieldScope returns an error if the actor was authenticated with an access token whose
+// scopes do not permit selecting the field.
+func checkFieldScope(ctx context.Context, typeName, fieldName string) error {
+	if actor.FromContext(ctx).Scopes == nil {
+		return nil // not restricted
+	}
+	scope, ok := fieldScopes[typeName+"."+fieldName]
+	if !ok {
+		if _, isRoot := rootTypes[typeName]; !isRoot {
+			return nil
+		}
+		scope = authz.ScopeUserAll
+	}
+	if scope == "" {
+		return nil
+	}
+	return backend.CheckActorScope(ctx, scope)
+}
+
+// scopeDeniedContext is returned by the tracer for a field whose selection is not permitted by the
+// actor's access token scopes. It reports itself as done with the scope error, which prevents the
+// GraphQL executor from calling the field's resolver and makes it report the error for the field.
+type scopeDeniedContext struct {
+	context.Context
+	err error
+}
+
+var closedChan = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
+func (c scopeDeniedContext) Done() <-chan struct{} { return closedChan }
+func (c scopeDeniedContext) Err() error            { return c.err }
+
+// checkNodeScope returns an error if the actor was authenticated with an access token whose
+// scopes do not permit looking up the node with Query.node.
+func checkNodeScope(ctx context.Context, id graphql.ID) error {
+	scope, ok := nodeKindScopes[relay.UnmarshalKind(id)]
+	if !ok {
+		scope = authz.ScopeUserAll
+	}
+	return backend.CheckActorScope(ctx, scope)
+}
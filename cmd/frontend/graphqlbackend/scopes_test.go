@@ -0,0 +1,167 @@
+package graphqlbackend
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+// schemaFields returns the names of the fields of each object and interface type in the GraphQL
+// schema.
+func schemaFields(t *testing.T) map[string][]string {
+	t.Helper()
+	result := GraphQLSchema.Exec(context.Background(), `{ __schema { types { name fields(includeDeprecated: true) { name } } } }`, "", nil)
+	if len(result.Errors) > 0 {
+		t.Fatal(result.Errors)
+	}
+	var data struct {
+		Schema struct {
+			Types []struct {
+				Name   string
+				Fields []struct{ Name string }
+			}
+		} `json:"__schema"`
+	}
+	if err := json.Unmarshal(result.Data, &data); err != nil {
+		t.Fatal(err)
+	}
+	fields := map[string][]string{}
+	for _, typ := range data.Schema.Types {
+		for _, field := range typ.Fields {
+			fields[typ.Name] = append(fields[typ.Name], field.Name)
+		}
+	}
+	return fields
+}
+
+// 🚨 SECURITY: This tests that every root field has an explicit access token scope, so that adding a
+// root field requires deciding which scopes permit selecting it.
+func TestFieldScopes_rootFieldsListed(t *testing.T) {
+	fields := schemaFields(t)
+	for _, typeName := range []string{"Query", "Mutation"} {
+		if len(fields[typeName]) == 0 {
+			t.Fatalf("no fields found for type %s", typeName)
+		}
+		for _, fieldName := range fields[typeName] {
+			if _, ok := fieldScopes[typeName+"."+fieldName]; !ok {
+				t.Errorf("field %s.%s has no entry in fieldScopes", typeName, fieldName)
+			}
+		}
+	}
+}
+
+func TestFieldScopes_fieldsExist(t *testing.T) {
+	fields := schemaFields(t)
+	for key := range fieldScopes {
+		parts := strings.SplitN(key, ".", 2)
+		if len(parts) != 2 {
+			t.Errorf("invalid fieldScopes key %q", key)
+			continue
+		}
+		if strings.HasPrefix(parts[1], "__") {
+			continue // introspection fields are not listed in the schema
+		}
+		found := false
+		for _, fieldName := range fields[parts[0]] {
+			if fieldName == parts[1] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("fieldScopes key %q does not refer to a field in the schema", key)
+		}
+	}
+}
+
+// 🚨 SECURITY: This tests that access tokens with restricted scopes may only select the fields that
+// their scopes permit.
+func TestCheckFieldScope(t *testing.T) {
+	tests := []struct {
+		scopes              []string
+		typeName, fieldName string
+		wantErr             bool
+	}{
+		{scopes: nil, typeName: "Query", fieldName: "site"},
+		{scopes: []string{authz.ScopeUserAll}, typeName: "Query", fieldName: "site"},
+		{scopes: []string{authz.ScopeSearchRead}, typeName: "Query", fieldName: "search"},
+		{scopes: []string{authz.ScopeSearchRead}, typeName: "Query", fieldName: "currentUser", wantErr: true},
+		{scopes: []string{authz.ScopeRepoRead}, typeName: "Query", fieldName: "repository"},
+		{scopes: []string{authz.ScopeRepoRead}, typeName: "Query", fieldName: "site", wantErr: true},
+		{scopes: []string{authz.ScopeRepoRead}, typeName: "Query", fieldName: "node"},
+		{scopes: []string{authz.ScopeRepoRead}, typeName: "Query", fieldName: "unlisted", wantErr: true},
+		{scopes: []string{authz.ScopeRepoRead}, typeName: "Repository", fieldName: "name"},
+		{scopes: []string{authz.ScopeUserRead}, typeName: "User", fieldName: "username"},
+		{scopes: []string{authz.ScopeUserRead}, typeName: "User", fieldName: "latestSettings", wantErr: true},
+		{scopes: []string{authz.ScopeRepoRead}, typeName: "User", fieldName: "emails", wantErr: true},
+		{scopes: []string{authz.ScopeUserWrite, authz.ScopeSettingsRead}, typeName: "Org", fieldName: "settingsCascade"},
+		{scopes: []string{authz.ScopeUserWrite}, typeName: "Mutation", fieldName: "updateUser"},
+		{scopes: []string{authz.ScopeUserRead}, typeName: "Mutation", fieldName: "updateUser", wantErr: true},
+		{scopes: []string{authz.ScopeUserWrite}, typeName: "Mutation", fieldName: "createAccessToken", wantErr: true},
+		{scopes: []string{authz.ScopeUserWrite}, typeName: "Site", fieldName: "accessTokens", wantErr: true},
+		{scopes: []string{authz.ScopeRepoRead}, typeName: "Subscription", fieldName: "x", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.typeName+"."+test.fieldName, func(t *testing.T) {
+			ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1, Scopes: test.scopes})
+			err := checkFieldScope(ctx, test.typeName, test.fieldName)
+			if (err != nil) != test.wantErr {
+				t.Errorf("got error %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+// 🚨 SECURITY: This tests that the scopes are enforced when a query is executed, and that the
+// resolvers of denied fields are not called.
+func TestGraphQLSchema_fieldScopes(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1, Username: "alice"}, nil
+	}
+	calledGetLatest := false
+	db.Mocks.Settings.GetLatest = func(context.Context, api.SettingsSubject) (*api.Settings, error) {
+		calledGetLatest = true
+		return nil, nil
+	}
+	defer resetMocks()
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1, Scopes: []string{authz.ScopeUserRead}})
+	result := GraphQLSchema.Exec(ctx, `{ currentUser { username latestSettings { id } } }`, "", nil)
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, authz.ScopeSettingsRead) {
+		t.Errorf("got errors %v, want an insufficient scope error for %q", result.Errors, authz.ScopeSettingsRead)
+	}
+	if calledGetLatest {
+		t.Error("resolver of denied field was called")
+	}
+	if want := `"username":"alice"`; !strings.Contains(string(result.Data), want) {
+		t.Errorf("got data %s, want it to contain %s", result.Data, want)
+	}
+
+	result = GraphQLSchema.Exec(ctx, `mutation { updateUser(user: "VXNlcjox") { alwaysNil } }`, "", nil)
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, authz.ScopeUserWrite) {
+		t.Errorf("got errors %v, want an insufficient scope error for %q", result.Errors, authz.ScopeUserWrite)
+	}
+}
+
+// 🚨 SECURITY: This tests that access tokens with restricted scopes may only look up nodes whose
+// kind their scopes permit.
+func TestCheckNodeScope(t *testing.T) {
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1, Scopes: []string{authz.ScopeRepoRead}})
+	if err := checkNodeScope(ctx, marshalRepositoryID(1)); err != nil {
+		t.Errorf("got error %v for repository node, want nil", err)
+	}
+	if err := checkNodeScope(ctx, marshalUserID(1)); err == nil {
+		t.Error("got nil error for user node, want error")
+	}
+	if err := checkNodeScope(ctx, "invalid"); err == nil {
+		t.Error("got nil error for invalid node ID, want error")
+	}
+}
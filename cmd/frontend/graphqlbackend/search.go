@@ -24,16 +24,20 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
 	searchquerytypes "github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query/types"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/endpoint"
 	"github.com/sourcegraph/sourcegraph/pkg/env"
 	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+	"github.com/sourcegraph/sourcegraph/pkg/repoupdater"
+	"github.com/sourcegraph/sourcegraph/pkg/repoupdater/protocol"
 	searchbackend "github.com/sourcegraph/sourcegraph/pkg/search/backend"
 	"github.com/sourcegraph/sourcegraph/pkg/trace"
 	"github.com/sourcegraph/sourcegraph/pkg/vcs"
 	"github.com/sourcegraph/sourcegraph/pkg/vcs/git"
 	"github.com/sourcegraph/sourcegraph/schema"
+	log15 "gopkg.in/inconshreveable/log15.v2"
 )
 
 // This file contains the root resolver for search. It currently has a lot of
@@ -249,10 +253,39 @@ func (r *searchResolver) resolveRepositories(ctx context.Context, effectiveRepoF
 		r.missingRepoRevs = missingRepoRevs
 		r.repoOverLimit = overLimit
 		r.repoErr = err
+		if err == nil {
+			recordSearchDemand(ctx, repoRevs)
+		}
 	}
 	return repoRevs, missingRepoRevs, overLimit, err
 }
 
+// maxReposForSearchDemand is the maximum number of repositories a search may
+// resolve to for it to count as demand for those repositories. Broad searches
+// across many repositories say little about which ones users care about.
+const maxReposForSearchDemand = 25
+
+// recordSearchDemand tells repo-updater that the given repositories were
+// searched by a user, so that it keeps them more up to date. Internal searches
+// (such as those the query-runner performs for saved searches) are not
+// recorded; saved search demand is recorded by RecordSavedSearchDemand.
+func recordSearchDemand(ctx context.Context, repoRevs []*search.RepositoryRevisions) {
+	if len(repoRevs) == 0 || len(repoRevs) > maxReposForSearchDemand || actor.FromContext(ctx).Internal {
+		return
+	}
+
+	ids := make([]uint32, len(repoRevs))
+	for i, rr := range repoRevs {
+		ids[i] = uint32(rr.Repo.ID)
+	}
+
+	goroutine.Go(func() {
+		if err := repoupdater.DefaultClient.RecordRepoDemand(context.Background(), protocol.RepoDemandSearch, ids...); err != nil {
+			log15.Warn("Failed to record search demand.", "error", err)
+		}
+	})
+}
+
 // a patternRevspec maps an include pattern to a list of revisions
 // for repos matching that pattern. "map" in this case does not mean
 // an actual map, because we want regexp matches, not identity matches.
@@ -364,12 +397,12 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 		tr.Finish()
 	}()
 
-	includePatterns := op.repoFilters
-	if includePatterns != nil {
-		// Copy to avoid race condition.
-		includePatterns = append([]string{}, includePatterns...)
+	// Metadata filters such as repo:topic:foo are not name patterns. Separating them also copies
+	// op.repoFilters, which avoids a race condition because includePatterns is mutated below.
+	includePatterns, excludePatterns, metadataFilters, err := parseRepoMetadataFilters(op.repoFilters, op.minusRepoFilters)
+	if err != nil {
+		return nil, nil, false, err
 	}
-	excludePatterns := op.minusRepoFilters
 
 	maxRepoListSize := maxReposToSearch()
 
@@ -402,8 +435,7 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 		return nil, nil, false, err
 	}
 
-	tr.LazyPrintf("Repos.List - start")
-	repos, err := db.Repos.List(ctx, db.ReposListOptions{
+	opt := db.ReposListOptions{
 		OnlyRepoIDs:     true,
 		IncludePatterns: includePatterns,
 		ExcludePattern:  unionRegExps(excludePatterns),
@@ -414,7 +446,11 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 		OnlyForks:    op.onlyForks,
 		NoArchived:   op.noArchived,
 		OnlyArchived: op.onlyArchived,
-	})
+	}
+	metadataFilters.apply(&opt)
+
+	tr.LazyPrintf("Repos.List - start")
+	repos, err := db.Repos.List(ctx, opt)
 	tr.LazyPrintf("Repos.List - done")
 	if err != nil {
 		return nil, nil, false, err
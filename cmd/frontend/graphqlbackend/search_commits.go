@@ -70,6 +70,9 @@ func (r *commitSearchResultResolver) ToCommitSearchResult() (*commitSearchResult
 func (r *commitSearchResultResolver) ToCodemodResult() (*codemodResultResolver, bool) {
 	return nil, false
 }
+func (r *commitSearchResultResolver) ToDiscussionSearchResult() (*discussionSearchResultResolver, bool) {
+	return nil, false
+}
 
 func (r *commitSearchResultResolver) searchResultURIs() (string, string) {
 	// Diffs aren't going to be returned with other types of results
@@ -0,0 +1,241 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/discussions"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/trace"
+)
+
+const (
+	// maxDiscussionSearchResultComments is the maximum number of matching
+	// comments that are returned for each discussion thread.
+	maxDiscussionSearchResultComments = 5
+
+	// maxDiscussionSearchResultMatchLength is the maximum length (in runes) of
+	// a matching comment's contents that is included in its match.
+	maxDiscussionSearchResultMatchLength = 500
+)
+
+var mockSearchDiscussions func(args *search.Args) ([]searchResultResolver, *searchResultsCommon, error)
+
+// searchDiscussions searches the titles and comments of the discussion threads
+// on the repositories in args.Repos, using Postgres full-text search.
+func searchDiscussions(ctx context.Context, args *search.Args, limit int32) (res []searchResultResolver, common *searchResultsCommon, err error) {
+	if mockSearchDiscussions != nil {
+		return mockSearchDiscussions(args)
+	}
+
+	fullTextQuery := discussionsFullTextQuery(args.Query)
+	if fullTextQuery == "" || len(args.Repos) == 0 {
+		return nil, nil, nil
+	}
+
+	// Only users who can use code discussions see them in search results.
+	// Search results of other types are still returned, so this is not an
+	// error.
+	if err := viewerCanUseDiscussions(ctx); err != nil {
+		return nil, nil, nil
+	}
+
+	tr, ctx := trace.New(ctx, "searchDiscussions", fmt.Sprintf("query: %q, numRepoRevs: %d", fullTextQuery, len(args.Repos)))
+	defer func() {
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: Only threads on the repositories in args.Repos may be returned. These have
+	// already been filtered by the repository permissions of the current user (by db.Repos.List).
+	repos := make(map[api.RepoID]*types.Repo, len(args.Repos))
+	repoIDs := make([]api.RepoID, 0, len(args.Repos))
+	for _, repoRev := range args.Repos {
+		if _, ok := repos[repoRev.Repo.ID]; !ok {
+			repos[repoRev.Repo.ID] = repoRev.Repo
+			repoIDs = append(repoIDs, repoRev.Repo.ID)
+		}
+	}
+
+	threads, err := db.DiscussionThreads.List(ctx, &db.DiscussionThreadsListOptions{
+		LimitOffset:   &db.LimitOffset{Limit: int(limit) + 1}, // so we can detect if the limit was hit
+		TargetRepoIDs: repoIDs,
+		FullTextQuery: &fullTextQuery,
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "DiscussionThreads.List")
+	}
+	common = &searchResultsCommon{}
+	if len(threads) > int(limit) {
+		common.limitHit = true
+		threads = threads[:limit]
+	}
+
+	// Look up the matching comments of all threads at once.
+	results := make([]*discussionSearchResultResolver, 0, len(threads))
+	byThreadID := make(map[int64]*discussionSearchResultResolver, len(threads))
+	threadIDs := make([]int64, 0, len(threads))
+	for _, thread := range threads {
+		if thread.TargetRepo == nil {
+			continue
+		}
+		repo, ok := repos[thread.TargetRepo.RepoID]
+		if !ok {
+			continue // 🚨 SECURITY: defensive; the DB query already excludes other repos
+		}
+		url := discussions.URLToInlineInRepo(repo, thread, nil)
+		if url == nil {
+			continue // can't link to this thread
+		}
+		result := &discussionSearchResultResolver{
+			thread:  thread,
+			repo:    repo,
+			url:     url.String(),
+			matches: []*searchResultMatchResolver{},
+		}
+		results = append(results, result)
+		byThreadID[thread.ID] = result
+		threadIDs = append(threadIDs, thread.ID)
+	}
+	if len(results) == 0 {
+		return nil, common, nil
+	}
+
+	// Each thread lists at most maxDiscussionSearchResultComments matching
+	// comments, so more comments than that are never needed.
+	comments, err := db.DiscussionComments.List(ctx, &db.DiscussionCommentsListOptions{
+		ThreadIDs:      threadIDs,
+		FullTextQuery:  &fullTextQuery,
+		LimitPerThread: maxDiscussionSearchResultComments,
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "DiscussionComments.List")
+	}
+	for _, comment := range comments {
+		result, ok := byThreadID[comment.ThreadID]
+		if !ok || len(result.matches) == maxDiscussionSearchResultComments {
+			continue // defensive; the DB query already limits the comments per thread
+		}
+		result.matches = append(result.matches, &searchResultMatchResolver{
+			url:        discussions.URLToInlineInRepo(result.repo, result.thread, comment).String(),
+			body:       truncateDiscussionSearchResultMatch(comment.Contents),
+			highlights: []*highlightedRange{},
+		})
+	}
+
+	res = make([]searchResultResolver, len(results))
+	for i, result := range results {
+		res[i] = result
+	}
+	return res, common, nil
+}
+
+// discussionsFullTextQuery returns the full-text search query for the
+// discussion search, which consists of the query's (non-negated) literal search
+// terms. Regular expression patterns can't be used in a full-text search, so
+// they are omitted (unless they match only a literal string, such as "foo").
+func discussionsFullTextQuery(q *query.Query) string {
+	var terms []string
+	for _, v := range q.Values(query.FieldDefault) {
+		if v.Not() {
+			continue
+		}
+		switch {
+		case v.String != nil:
+			terms = append(terms, *v.String)
+		case v.Regexp != nil:
+			if literal, complete := v.Regexp.LiteralPrefix(); complete && literal != "" {
+				terms = append(terms, literal)
+			}
+		}
+	}
+	return strings.Join(terms, " ")
+}
+
+func truncateDiscussionSearchResultMatch(s string) string {
+	if utf8.RuneCountInString(s) <= maxDiscussionSearchResultMatchLength {
+		return s
+	}
+	return string([]rune(s)[:maxDiscussionSearchResultMatchLength]) + "…"
+}
+
+// discussionSearchResultResolver is a resolver for the GraphQL type `DiscussionSearchResult`
+type discussionSearchResultResolver struct {
+	thread  *types.DiscussionThread
+	repo    *types.Repo
+	url     string                       // the URL to the thread's inline view
+	matches []*searchResultMatchResolver // the thread's matching comments
+}
+
+func (r *discussionSearchResultResolver) ToRepository() (*repositoryResolver, bool) {
+	return nil, false
+}
+func (r *discussionSearchResultResolver) ToFileMatch() (*fileMatchResolver, bool) { return nil, false }
+func (r *discussionSearchResultResolver) ToCommitSearchResult() (*commitSearchResultResolver, bool) {
+	return nil, false
+}
+func (r *discussionSearchResultResolver) ToCodemodResult() (*codemodResultResolver, bool) {
+	return nil, false
+}
+func (r *discussionSearchResultResolver) ToDiscussionSearchResult() (*discussionSearchResultResolver, bool) {
+	return r, true
+}
+
+func (r *discussionSearchResultResolver) searchResultURIs() (string, string) {
+	var path string
+	if r.thread.TargetRepo.Path != nil {
+		path = *r.thread.TargetRepo.Path
+	}
+	return string(r.repo.Name), path
+}
+func (r *discussionSearchResultResolver) resultCount() int32 {
+	if len(r.matches) == 0 {
+		return 1 // only the title matched
+	}
+	return int32(len(r.matches))
+}
+
+func (r *discussionSearchResultResolver) Thread() *discussionThreadResolver {
+	return &discussionThreadResolver{t: r.thread}
+}
+
+func (r *discussionSearchResultResolver) Icon() string {
+	return "data:image/svg+xml,%3Csvg xmlns='http://www.w3.org/2000/svg' style='width:24px;height:24px' viewBox='0 0 24 24'%3E%3Cpath fill='%23a2b0cd' d='M17,12V3A1,1 0 0,0 16,2H3A1,1 0 0,0 2,3V17L6,13H16A1,1 0 0,0 17,12M21,6H19V15H6V17A1,1 0 0,0 7,18H18L22,22V7A1,1 0 0,0 21,6Z' /%3E%3C/svg%3E"
+}
+
+func (r *discussionSearchResultResolver) Label() *markdownResolver {
+	repoURL := (&repositoryResolver{repo: r.repo}).URL()
+	escape := strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`).Replace
+	text := fmt.Sprintf("[%s](%s) › [%s](%s)", displayRepoName(string(r.repo.Name)), repoURL, escape(r.thread.Title), r.url)
+	return &markdownResolver{text: text}
+}
+
+func (r *discussionSearchResultResolver) URL() string {
+	return r.url
+}
+
+func (r *discussionSearchResultResolver) Detail() *markdownResolver {
+	text := "Discussion"
+	if r.thread.TargetRepo.Path != nil {
+		text += fmt.Sprintf(" on `%s`", *r.thread.TargetRepo.Path)
+	}
+	switch n := len(r.matches); n {
+	case 0:
+	case 1:
+		text += " · 1 matching comment"
+	default:
+		text += fmt.Sprintf(" · %d matching comments", n)
+	}
+	return &markdownResolver{text: text}
+}
+
+func (r *discussionSearchResultResolver) Matches() []*searchResultMatchResolver {
+	return r.matches
+}
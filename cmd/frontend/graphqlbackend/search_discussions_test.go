@@ -0,0 +1,131 @@
+package graphqlbackend
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+func TestSearchDiscussions(t *testing.T) {
+	defer resetMocks()
+	mockViewerCanUseDiscussions = func() error { return nil }
+	defer func() { mockViewerCanUseDiscussions = nil }()
+
+	repo := &types.Repo{ID: 1, Name: "github.com/a/b"}
+	db.Mocks.Repos.Get = func(ctx context.Context, id api.RepoID) (*types.Repo, error) {
+		return repo, nil
+	}
+	path := "f.go"
+	db.Mocks.DiscussionThreads.List = func(ctx context.Context, opt *db.DiscussionThreadsListOptions) ([]*types.DiscussionThread, error) {
+		// 🚨 SECURITY: Only threads on the searched (and therefore permitted) repositories may be
+		// returned.
+		if want := []api.RepoID{1}; !reflect.DeepEqual(opt.TargetRepoIDs, want) {
+			t.Errorf("got TargetRepoIDs %v, want %v", opt.TargetRepoIDs, want)
+		}
+		if want := "flaky test"; opt.FullTextQuery == nil || *opt.FullTextQuery != want {
+			t.Errorf("got FullTextQuery %v, want %q", opt.FullTextQuery, want)
+		}
+		return []*types.DiscussionThread{
+			{ID: 2, Title: "Flaky [CI]", TargetRepo: &types.DiscussionThreadTargetRepo{RepoID: 1, Path: &path}},
+		}, nil
+	}
+	db.Mocks.DiscussionComments.List = func(ctx context.Context, opt *db.DiscussionCommentsListOptions) ([]*types.DiscussionComment, error) {
+		if want := []int64{2}; !reflect.DeepEqual(opt.ThreadIDs, want) {
+			t.Errorf("got ThreadIDs %v, want %v", opt.ThreadIDs, want)
+		}
+		if opt.LimitPerThread != maxDiscussionSearchResultComments {
+			t.Errorf("got LimitPerThread %d, want %d", opt.LimitPerThread, maxDiscussionSearchResultComments)
+		}
+		return []*types.DiscussionComment{{ID: 3, ThreadID: 2, Contents: "this test is flaky"}}, nil
+	}
+
+	q, err := query.ParseAndCheck("flaky test type:discussion")
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, common, err := searchDiscussions(context.Background(), &search.Args{
+		Query: q,
+		Repos: []*search.RepositoryRevisions{{Repo: repo}},
+	}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if common.limitHit {
+		t.Error("got limitHit, want false")
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	r, ok := results[0].ToDiscussionSearchResult()
+	if !ok {
+		t.Fatalf("got result %T, want discussion search result", results[0])
+	}
+	if want := "/github.com/a/b/-/blob/f.go#tab=discussions&threadID=2"; r.URL() != want {
+		t.Errorf("got URL %q, want %q", r.URL(), want)
+	}
+	if want := `[a/b](/github.com/a/b) › [Flaky \[CI\]](/github.com/a/b/-/blob/f.go#tab=discussions&threadID=2)`; r.Label().text != want {
+		t.Errorf("got label %q, want %q", r.Label().text, want)
+	}
+	if want := "Discussion on `f.go` · 1 matching comment"; r.Detail().text != want {
+		t.Errorf("got detail %q, want %q", r.Detail().text, want)
+	}
+	matches := r.Matches()
+	if len(matches) != 1 || matches[0].url != "/github.com/a/b/-/blob/f.go#commentID=3&tab=discussions&threadID=2" || matches[0].body != "this test is flaky" {
+		t.Errorf("got matches %+v, want the matching comment", matches)
+	}
+}
+
+func TestSearchDiscussions_cannotUseDiscussions(t *testing.T) {
+	defer resetMocks()
+	mockViewerCanUseDiscussions = func() error { return errors.New("x") }
+	defer func() { mockViewerCanUseDiscussions = nil }()
+	db.Mocks.DiscussionThreads.List = func(ctx context.Context, opt *db.DiscussionThreadsListOptions) ([]*types.DiscussionThread, error) {
+		t.Error("want no discussion threads to be listed")
+		return nil, nil
+	}
+
+	q, err := query.ParseAndCheck("flaky test type:discussion")
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, _, err := searchDiscussions(context.Background(), &search.Args{
+		Query: q,
+		Repos: []*search.RepositoryRevisions{{Repo: &types.Repo{ID: 1, Name: "github.com/a/b"}}},
+	}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 0 {
+		t.Errorf("got %d results, want none", len(results))
+	}
+}
+
+func TestDiscussionsFullTextQuery(t *testing.T) {
+	tests := map[string]string{
+		"flaky test":               "flaky test",
+		`"flaky test" retry`:       "flaky test retry",
+		"type:discussion flaky":    "flaky",
+		`flak(y|iness) test`:       "test",
+		"fla.*ky":                  "",
+		`f\.go`:                    "f.go",
+		"repo:foo type:discussion": "",
+	}
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			q, err := query.ParseAndCheck(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := discussionsFullTextQuery(q); got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package graphqlbackend
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+)
+
+// repoMetadataFilters are the filters on code host metadata that can be given as values of the
+// repo: field instead of a repository name pattern:
+//
+//	repo:topic:payments       repositories labeled with the "payments" topic
+//	repo:stars:>100           repositories with more than 100 stars (also >=, <, <= and =)
+//	repo:visibility:private   private repositories (or public)
+//
+// They are evaluated in the database (see db.ReposListOptions), because the metadata is normalized
+// into columns of the repo table when repositories are synced.
+type repoMetadataFilters struct {
+	topics, excludeTopics []string
+	minStars, maxStars    *int
+	onlyPrivate           bool
+	noPrivate             bool
+}
+
+const (
+	repoTopicPrefix      = "topic:"
+	repoStarsPrefix      = "stars:"
+	repoVisibilityPrefix = "visibility:"
+)
+
+// parseRepoMetadataFilters separates the repo metadata filters from the repository name patterns
+// in the values of the repo: (include) and -repo: (exclude) fields.
+func parseRepoMetadataFilters(include, exclude []string) (includePatterns, excludePatterns []string, f repoMetadataFilters, err error) {
+	for _, p := range include {
+		ok, err := f.add(p, false)
+		if err != nil {
+			return nil, nil, f, err
+		}
+		if !ok {
+			includePatterns = append(includePatterns, p)
+		}
+	}
+	for _, p := range exclude {
+		ok, err := f.add(p, true)
+		if err != nil {
+			return nil, nil, f, err
+		}
+		if !ok {
+			excludePatterns = append(excludePatterns, p)
+		}
+	}
+	return includePatterns, excludePatterns, f, nil
+}
+
+// add adds the filter described by the repo: field value v to f. It reports whether v is a
+// metadata filter (as opposed to a repository name pattern).
+func (f *repoMetadataFilters) add(v string, negated bool) (bool, error) {
+	switch {
+	case strings.HasPrefix(v, repoTopicPrefix):
+		topic := strings.TrimPrefix(v, repoTopicPrefix)
+		if topic == "" {
+			return true, &badRequestError{fmt.Errorf("empty topic in repo:%s", v)}
+		}
+		if negated {
+			f.excludeTopics = append(f.excludeTopics, topic)
+		} else {
+			f.topics = append(f.topics, topic)
+		}
+
+	case strings.HasPrefix(v, repoStarsPrefix):
+		min, max, err := parseStarsRange(strings.TrimPrefix(v, repoStarsPrefix))
+		if err != nil {
+			return true, &badRequestError{fmt.Errorf("invalid repo:%s (%s)", v, err)}
+		}
+		if negated {
+			// Only one-sided ranges can be negated without a disjunction.
+			switch {
+			case min != nil && max == nil:
+				min, max = nil, intptr(*min-1)
+			case min == nil && max != nil:
+				min, max = intptr(*max+1), nil
+			default:
+				return true, &badRequestError{fmt.Errorf("-repo:%s is not supported, use repo:stars:<N or repo:stars:>N", v)}
+			}
+		}
+		if min != nil && (f.minStars == nil || *min > *f.minStars) {
+			f.minStars = min
+		}
+		if max != nil && (f.maxStars == nil || *max < *f.maxStars) {
+			f.maxStars = max
+		}
+
+	case strings.HasPrefix(v, repoVisibilityPrefix):
+		private := false
+		switch visibility := strings.ToLower(strings.TrimPrefix(v, repoVisibilityPrefix)); visibility {
+		case "private":
+			private = true
+		case "public":
+		default:
+			return true, &badRequestError{fmt.Errorf("invalid repo:%s (must be public or private)", v)}
+		}
+		if private != negated {
+			f.onlyPrivate = true
+		} else {
+			f.noPrivate = true
+		}
+
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// parseStarsRange parses a comparison such as ">100", ">=100", "<10", "<=10" or "42" into an
+// inclusive range of star counts.
+func parseStarsRange(s string) (min, max *int, err error) {
+	op := strings.TrimRight(s, "0123456789")
+	n, err := strconv.Atoi(s[len(op):])
+	if err != nil {
+		return nil, nil, fmt.Errorf("expected a number of stars")
+	}
+	switch op {
+	case ">":
+		return intptr(n + 1), nil, nil
+	case ">=":
+		return intptr(n), nil, nil
+	case "<":
+		return nil, intptr(n - 1), nil
+	case "<=":
+		return nil, intptr(n), nil
+	case "", "=":
+		return intptr(n), intptr(n), nil
+	}
+	return nil, nil, fmt.Errorf("unknown comparison %q", op)
+}
+
+// apply sets the options of opt that correspond to f.
+func (f *repoMetadataFilters) apply(opt *db.ReposListOptions) {
+	opt.Topics = f.topics
+	opt.ExcludeTopics = f.excludeTopics
+	opt.MinStars = f.minStars
+	opt.MaxStars = f.maxStars
+	opt.OnlyPrivate = f.onlyPrivate
+	opt.NoPrivate = f.noPrivate
+}
+
+func intptr(i int) *int { return &i }
@@ -0,0 +1,122 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+func TestParseRepoMetadataFilters(t *testing.T) {
+	tests := []struct {
+		include, exclude []string
+		wantInclude      []string
+		wantExclude      []string
+		want             repoMetadataFilters
+		wantErr          bool
+	}{
+		{
+			include:     []string{"^github\\.com/foo/", "topic:payments", "topic:api"},
+			exclude:     []string{"topic:deprecated", "bar"},
+			wantInclude: []string{"^github\\.com/foo/"},
+			wantExclude: []string{"bar"},
+			want: repoMetadataFilters{
+				topics:        []string{"payments", "api"},
+				excludeTopics: []string{"deprecated"},
+			},
+		},
+		{
+			include: []string{"stars:>100", "stars:<=1000"},
+			want:    repoMetadataFilters{minStars: intptr(101), maxStars: intptr(1000)},
+		},
+		{
+			include: []string{"stars:>=10", "stars:>100"},
+			want:    repoMetadataFilters{minStars: intptr(101)},
+		},
+		{
+			include: []string{"stars:42"},
+			want:    repoMetadataFilters{minStars: intptr(42), maxStars: intptr(42)},
+		},
+		{
+			exclude: []string{"stars:<10"},
+			want:    repoMetadataFilters{minStars: intptr(10)},
+		},
+		{
+			exclude: []string{"stars:42"},
+			wantErr: true,
+		},
+		{
+			include: []string{"stars:many"},
+			wantErr: true,
+		},
+		{
+			include: []string{"visibility:Private"},
+			want:    repoMetadataFilters{onlyPrivate: true},
+		},
+		{
+			exclude: []string{"visibility:private"},
+			want:    repoMetadataFilters{noPrivate: true},
+		},
+		{
+			include: []string{"visibility:internal"},
+			wantErr: true,
+		},
+		{
+			include: []string{"topic:"},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		include, exclude, f, err := parseRepoMetadataFilters(test.include, test.exclude)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q -%q: got nil error, want an error", test.include, test.exclude)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q -%q: %s", test.include, test.exclude, err)
+			continue
+		}
+		if !reflect.DeepEqual(include, test.wantInclude) || !reflect.DeepEqual(exclude, test.wantExclude) {
+			t.Errorf("%q -%q: got patterns %q -%q, want %q -%q", test.include, test.exclude, include, exclude, test.wantInclude, test.wantExclude)
+		}
+		if !reflect.DeepEqual(f, test.want) {
+			t.Errorf("%q -%q: got filters %+v, want %+v", test.include, test.exclude, f, test.want)
+		}
+	}
+}
+
+func TestResolveRepositories_metadataFilters(t *testing.T) {
+	var calledReposList bool
+	db.Mocks.Repos.List = func(_ context.Context, op db.ReposListOptions) ([]*types.Repo, error) {
+		calledReposList = true
+
+		want := db.ReposListOptions{
+			OnlyRepoIDs:     true,
+			IncludePatterns: []string{"foo"},
+			Enabled:         true,
+			LimitOffset:     &db.LimitOffset{Limit: maxReposToSearch() + 1},
+			Topics:          []string{"payments"},
+			MinStars:        intptr(101),
+			OnlyPrivate:     true,
+		}
+		if !reflect.DeepEqual(op, want) {
+			t.Errorf("got %+v, want %+v", op, want)
+		}
+		return nil, nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	_, _, _, err := resolveRepositories(context.Background(), resolveRepoOp{
+		repoFilters: []string{"foo", "topic:payments", "stars:>100", "visibility:private"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !calledReposList {
+		t.Error("!calledReposList")
+	}
+}
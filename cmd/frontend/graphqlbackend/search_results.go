@@ -446,6 +446,8 @@ loop:
 			})
 		case *codemodResultResolver:
 			continue
+		case *discussionSearchResultResolver:
+			addPoint(m.thread.CreatedAt)
 		default:
 			panic("SearchResults.Sparkline unexpected union type state")
 		}
@@ -995,6 +997,29 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 					commonMu.Unlock()
 				}
 			})
+		case "discussion":
+			wg := waitGroup(len(resultTypes) == 1)
+			wg.Add(1)
+			goroutine.Go(func() {
+				defer wg.Done()
+
+				discussionResults, discussionCommon, err := searchDiscussions(ctx, &args, r.maxResults())
+				if err != nil && !isContextError(ctx, err) {
+					multiErrMu.Lock()
+					multiErr = multierror.Append(multiErr, errors.Wrap(err, "discussion search failed"))
+					multiErrMu.Unlock()
+				}
+				if discussionResults != nil {
+					resultsMu.Lock()
+					results = append(results, discussionResults...)
+					resultsMu.Unlock()
+				}
+				if discussionCommon != nil {
+					commonMu.Lock()
+					common.update(*discussionCommon)
+					commonMu.Unlock()
+				}
+			})
 		case "codemod":
 			wg := waitGroup(true)
 			wg.Add(1)
@@ -1074,10 +1099,11 @@ func isContextError(ctx context.Context, err error) bool {
 //
 // Supported types:
 //
-//   - *repositoryResolver         // repo name match
-//   - *fileMatchResolver          // text match
-//   - *commitSearchResultResolver // diff or commit match
-//   - *codemodResultResolver      // code modification
+//   - *repositoryResolver             // repo name match
+//   - *fileMatchResolver              // text match
+//   - *commitSearchResultResolver     // diff or commit match
+//   - *codemodResultResolver          // code modification
+//   - *discussionSearchResultResolver // discussion thread title or comment match
 //
 // Note: Any new result types added here also need to be handled properly in search_results.go:301 (sparklines)
 type searchResultResolver interface {
@@ -1085,6 +1111,7 @@ type searchResultResolver interface {
 	ToFileMatch() (*fileMatchResolver, bool)
 	ToCommitSearchResult() (*commitSearchResultResolver, bool)
 	ToCodemodResult() (*codemodResultResolver, bool)
+	ToDiscussionSearchResult() (*discussionSearchResultResolver, bool)
 
 	// SearchResultURIs returns the repo name and file uri respectiveley
 	searchResultURIs() (string, string)
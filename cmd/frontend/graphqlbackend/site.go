@@ -14,6 +14,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/siteid"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/pkg/db/globalstatedb"
 	"github.com/sourcegraph/sourcegraph/pkg/version"
 
@@ -194,10 +195,37 @@ func (r *schemaResolver) UpdateSiteConfiguration(ctx context.Context, args *stru
 		return false, fmt.Errorf("blank site configuration is invalid (you can clear the site configuration by entering an empty JSON object: {})")
 	}
 	prev := globals.ConfigurationServerFrontendOnly.Raw()
+	before := prev
 	prev.Site = args.Input
 	// TODO(slimsag): future: actually pass lastID through to prevent race conditions
 	if err := globals.ConfigurationServerFrontendOnly.Write(ctx, prev); err != nil {
 		return false, err
 	}
+	backend.LogAuditEvent(ctx, backend.AuditEvent{
+		Action:     "updateSiteConfiguration",
+		TargetKind: "Site",
+		TargetID:   string(SiteGQLID()),
+		Changes:    siteConfigurationChanges(before, prev),
+	})
 	return globals.ConfigurationServerFrontendOnly.NeedServerRestart(), nil
 }
+
+// siteConfigurationChanges returns the site configuration properties that differ between the two
+// configurations, with their values before and after, for recording in the audit log. Secrets are
+// redacted.
+func siteConfigurationChanges(before, after conftypes.RawUnified) []db.AuditLogChange {
+	beforeCfg, err := conf.ParseConfig(before)
+	if err != nil {
+		return nil
+	}
+	afterCfg, err := conf.ParseConfig(after)
+	if err != nil {
+		return nil
+	}
+	fieldChanges := conf.Changes(beforeCfg, afterCfg)
+	changes := make([]db.AuditLogChange, len(fieldChanges))
+	for i, c := range fieldChanges {
+		changes[i] = db.AuditLogChange{Field: c.Field, Before: c.Before, After: c.After}
+	}
+	return changes
+}
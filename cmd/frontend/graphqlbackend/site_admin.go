@@ -31,7 +31,9 @@ func (*schemaResolver) DeleteUser(ctx context.Context, args *struct {
 		return nil, errors.New("unable to delete current user")
 	}
 
+	action := "deleteUser"
 	if args.Hard != nil && *args.Hard {
+		action = "hardDeleteUser"
 		if err := db.Users.HardDelete(ctx, userID); err != nil {
 			return nil, err
 		}
@@ -40,6 +42,11 @@ func (*schemaResolver) DeleteUser(ctx context.Context, args *struct {
 			return nil, err
 		}
 	}
+	backend.LogAuditEvent(ctx, backend.AuditEvent{
+		Action:     action,
+		TargetKind: "User",
+		TargetID:   string(args.User),
+	})
 	return &EmptyResponse{}, nil
 }
 
@@ -88,5 +95,11 @@ func (*schemaResolver) SetUserIsSiteAdmin(ctx context.Context, args *struct {
 	if err := db.Users.SetIsSiteAdmin(ctx, userID, args.SiteAdmin); err != nil {
 		return nil, err
 	}
+	backend.LogAuditEvent(ctx, backend.AuditEvent{
+		Action:     "setUserIsSiteAdmin",
+		TargetKind: "User",
+		TargetID:   string(args.UserID),
+		Changes:    []db.AuditLogChange{{Field: "siteAdmin", Before: !args.SiteAdmin, After: args.SiteAdmin}},
+	})
 	return &EmptyResponse{}, nil
 }
@@ -121,6 +121,9 @@ func (fm *fileMatchResolver) ToCommitSearchResult() (*commitSearchResultResolver
 func (r *fileMatchResolver) ToCodemodResult() (*codemodResultResolver, bool) {
 	return nil, false
 }
+func (r *fileMatchResolver) ToDiscussionSearchResult() (*discussionSearchResultResolver, bool) {
+	return nil, false
+}
 
 func (fm *fileMatchResolver) searchResultURIs() (string, string) {
 	return string(fm.repo.Name), fm.JPath
@@ -0,0 +1,105 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/auth/userpasswd"
+)
+
+func (r *UserResolver) MFAEnabled(ctx context.Context) (bool, error) {
+	// 🚨 SECURITY: Only the user and site admins can see whether the user has enabled MFA.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
+		return false, err
+	}
+	return userpasswd.MFAEnabled(ctx, r.user.ID)
+}
+
+type totpEnrollmentResolver struct {
+	enrollment *userpasswd.TOTPEnrollment
+}
+
+func (r *totpEnrollmentResolver) Secret() string { return r.enrollment.Secret }
+func (r *totpEnrollmentResolver) URL() string    { return r.enrollment.URL }
+
+func (*schemaResolver) BeginTOTPEnrollment(ctx context.Context) (*totpEnrollmentResolver, error) {
+	// 🚨 SECURITY: A user can only enroll themselves in MFA.
+	user, err := db.Users.GetByCurrentAuthUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment, err := userpasswd.BeginTOTPEnrollment(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &totpEnrollmentResolver{enrollment: enrollment}, nil
+}
+
+func (*schemaResolver) ConfirmTOTPEnrollment(ctx context.Context, args *struct {
+	Code string
+}) ([]string, error) {
+	// 🚨 SECURITY: A user can only enroll themselves in MFA.
+	user, err := db.Users.GetByCurrentAuthUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := userpasswd.ConfirmTOTPEnrollment(ctx, user.ID, args.Code)
+	if err != nil {
+		return nil, err
+	}
+	backend.LogAuditEvent(ctx, backend.AuditEvent{
+		Action:     "enableMFA",
+		TargetKind: "User",
+		TargetID:   string(MarshalUserID(user.ID)),
+	})
+	return recoveryCodes, nil
+}
+
+func (*schemaResolver) RegenerateMFARecoveryCodes(ctx context.Context, args *struct {
+	Code string
+}) ([]string, error) {
+	// 🚨 SECURITY: A user can only regenerate their own recovery codes.
+	user, err := db.Users.GetByCurrentAuthUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := userpasswd.RegenerateRecoveryCodes(ctx, user.ID, args.Code)
+	if err != nil {
+		return nil, err
+	}
+	backend.LogAuditEvent(ctx, backend.AuditEvent{
+		Action:     "regenerateMFARecoveryCodes",
+		TargetKind: "User",
+		TargetID:   string(MarshalUserID(user.ID)),
+	})
+	return recoveryCodes, nil
+}
+
+func (*schemaResolver) ResetUserMFA(ctx context.Context, args *struct {
+	User graphql.ID
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can reset a user's MFA.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	userID, err := UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.UserMFA.Delete(ctx, userID); err != nil {
+		return nil, err
+	}
+	backend.LogAuditEvent(ctx, backend.AuditEvent{
+		Action:     "resetUserMFA",
+		TargetKind: "User",
+		TargetID:   string(args.User),
+	})
+	return &EmptyResponse{}, nil
+}
@@ -0,0 +1,92 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+)
+
+// 🚨 SECURITY: This tests that only site admins can reset a user's MFA.
+func TestMutation_ResetUserMFA(t *testing.T) {
+	const uid2GQLID = "VXNlcjoy"
+
+	t.Run("non-site admin", func(t *testing.T) {
+		resetMocks()
+		db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+			return &types.User{ID: 1}, nil
+		}
+		db.Mocks.UserMFA.Delete = func(ctx context.Context, userID int32) error {
+			t.Error("unexpected call to UserMFA.Delete")
+			return nil
+		}
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		if _, err := (&schemaResolver{}).ResetUserMFA(ctx, &struct{ User graphql.ID }{User: uid2GQLID}); err == nil {
+			t.Error("got nil error, want non-nil")
+		}
+	})
+
+	t.Run("site admin", func(t *testing.T) {
+		resetMocks()
+		db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+			return &types.User{ID: 1, SiteAdmin: true}, nil
+		}
+		var calledDelete bool
+		db.Mocks.UserMFA.Delete = func(ctx context.Context, userID int32) error {
+			calledDelete = true
+			if want := int32(2); userID != want {
+				t.Errorf("got user ID %d, want %d", userID, want)
+			}
+			return nil
+		}
+		db.Mocks.AuditLog.Create = func(*db.AuditLogEntry) error { return nil }
+
+		gqltesting.RunTests(t, []*gqltesting.Test{
+			{
+				Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+				Schema:  GraphQLSchema,
+				Query: `
+				mutation {
+					resetUserMFA(user: "` + uid2GQLID + `") {
+						alwaysNil
+					}
+				}
+			`,
+				ExpectedResult: `
+				{
+					"resetUserMFA": {
+						"alwaysNil": null
+					}
+				}
+			`,
+			},
+		})
+		if !calledDelete {
+			t.Error("!calledDelete")
+		}
+	})
+}
+
+// 🚨 SECURITY: This tests that users can't see whether other users have enabled MFA.
+func TestUser_MFAEnabled(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: 1}, nil
+	}
+	db.Mocks.UserMFA.GetTOTP = func(ctx context.Context, userID int32) (*db.UserTOTP, error) {
+		return &db.UserTOTP{UserID: userID, Secret: "s"}, nil
+	}
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	if enabled, err := (&UserResolver{user: &types.User{ID: 1}}).MFAEnabled(ctx); err != nil || enabled {
+		t.Errorf("got %v (error %v), want false for pending enrollment", enabled, err)
+	}
+	if _, err := (&UserResolver{user: &types.User{ID: 2}}).MFAEnabled(ctx); err == nil {
+		t.Error("got nil error for another user, want non-nil")
+	}
+}
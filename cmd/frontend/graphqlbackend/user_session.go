@@ -18,6 +18,8 @@ func (r *UserResolver) Session(ctx context.Context) (*sessionResolver, error) {
 
 	var sr sessionResolver
 	if actor.FromSessionCookie {
+		sr.mfaSatisfied = actor.MFASatisfied
+
 		// The http-header auth provider is the only auth provider that a user can't sign out from.
 		for _, p := range conf.Get().Critical.AuthProviders {
 			if p.HttpHeader == nil {
@@ -30,7 +32,10 @@ func (r *UserResolver) Session(ctx context.Context) (*sessionResolver, error) {
 }
 
 type sessionResolver struct {
-	canSignOut bool
+	canSignOut   bool
+	mfaSatisfied bool
 }
 
 func (r *sessionResolver) CanSignOut() bool { return r.canSignOut }
+
+func (r *sessionResolver) MFASatisfied() bool { return r.mfaSatisfied }
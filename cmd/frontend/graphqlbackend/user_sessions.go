@@ -0,0 +1,117 @@
+package graphqlbackend
+
+import (
+	"context"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/session"
+)
+
+func (r *UserResolver) Sessions(ctx context.Context) ([]*userSessionResolver, error) {
+	// 🚨 SECURITY: Only the user and site admins can list the user's sessions.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
+		return nil, err
+	}
+
+	sessions, err := db.UserSessions.ListByUser(ctx, r.user.ID)
+	if err != nil {
+		return nil, err
+	}
+	rs := make([]*userSessionResolver, len(sessions))
+	for i, s := range sessions {
+		rs[i] = &userSessionResolver{session: *s}
+	}
+	return rs, nil
+}
+
+// userSessionResolver resolves a user session (from the user's session index).
+type userSessionResolver struct {
+	session db.UserSession
+}
+
+func marshalUserSessionID(id int64) graphql.ID { return relay.MarshalID("UserSession", id) }
+
+func unmarshalUserSessionID(id graphql.ID) (sessionID int64, err error) {
+	err = relay.UnmarshalSpec(id, &sessionID)
+	return
+}
+
+func (r *userSessionResolver) ID() graphql.ID { return marshalUserSessionID(r.session.ID) }
+
+func (r *userSessionResolver) CreatedAt() string { return r.session.CreatedAt.Format(time.RFC3339) }
+
+func (r *userSessionResolver) LastActiveAt() string {
+	return r.session.LastActiveAt.Format(time.RFC3339)
+}
+
+func (r *userSessionResolver) ExpiresAt() string { return r.session.ExpiresAt.Format(time.RFC3339) }
+
+func (r *userSessionResolver) RemoteAddr() string { return r.session.RemoteAddr }
+
+func (r *userSessionResolver) UserAgent() string { return r.session.UserAgent }
+
+func (r *userSessionResolver) AuthProvider() *string {
+	if r.session.AuthProvider == "" {
+		return nil
+	}
+	return &r.session.AuthProvider
+}
+
+func (r *userSessionResolver) IsCurrent(ctx context.Context) bool {
+	return session.CurrentSessionID(ctx) == r.session.ID
+}
+
+func (*schemaResolver) RevokeUserSession(ctx context.Context, args *struct {
+	Session graphql.ID
+}) (*EmptyResponse, error) {
+	sessionID, err := unmarshalUserSessionID(args.Session)
+	if err != nil {
+		return nil, err
+	}
+	s, err := db.UserSessions.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site admins and the user can revoke a user's session.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, s.UserID); err != nil {
+		return nil, err
+	}
+	if err := db.UserSessions.Delete(ctx, s.ID); err != nil {
+		return nil, err
+	}
+	backend.LogAuditEvent(ctx, backend.AuditEvent{
+		Action:     "revokeUserSession",
+		TargetKind: "User",
+		TargetID:   string(MarshalUserID(s.UserID)),
+		Changes:    []db.AuditLogChange{{Field: "session", Before: string(args.Session)}},
+	})
+	return &EmptyResponse{}, nil
+}
+
+func (*schemaResolver) RevokeAllUserSessions(ctx context.Context, args *struct {
+	User graphql.ID
+}) (*EmptyResponse, error) {
+	userID, err := UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site admins and the user can revoke a user's sessions.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := db.UserSessions.DeleteByUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	backend.LogAuditEvent(ctx, backend.AuditEvent{
+		Action:     "revokeAllUserSessions",
+		TargetKind: "User",
+		TargetID:   string(args.User),
+	})
+	return &EmptyResponse{}, nil
+}
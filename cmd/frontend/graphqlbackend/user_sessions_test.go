@@ -0,0 +1,151 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+)
+
+func TestUser_Sessions(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByUsername = func(ctx context.Context, username string) (*types.User, error) {
+		return &types.User{ID: 1, Username: username}, nil
+	}
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: 1}, nil
+	}
+	createdAt := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+	db.Mocks.UserSessions.ListByUser = func(ctx context.Context, userID int32) ([]*db.UserSession, error) {
+		if want := int32(1); userID != want {
+			t.Errorf("got user ID %d, want %d", userID, want)
+		}
+		return []*db.UserSession{{
+			ID:           2,
+			UserID:       1,
+			CreatedAt:    createdAt,
+			LastActiveAt: createdAt.Add(time.Hour),
+			ExpiresAt:    createdAt.Add(24 * time.Hour),
+			RemoteAddr:   "192.0.2.1",
+			UserAgent:    "ua",
+		}}, nil
+	}
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+			Schema:  GraphQLSchema,
+			Query: `
+				{
+					user(username: "alice") {
+						sessions {
+							id
+							createdAt
+							lastActiveAt
+							expiresAt
+							remoteAddr
+							userAgent
+							authProvider
+							isCurrent
+						}
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"user": {
+						"sessions": [
+							{
+								"id": "VXNlclNlc3Npb246Mg==",
+								"createdAt": "2018-06-01T00:00:00Z",
+								"lastActiveAt": "2018-06-01T01:00:00Z",
+								"expiresAt": "2018-06-02T00:00:00Z",
+								"remoteAddr": "192.0.2.1",
+								"userAgent": "ua",
+								"authProvider": null,
+								"isCurrent": false
+							}
+						]
+					}
+				}
+			`,
+		},
+	})
+}
+
+// 🚨 SECURITY: This tests that users can't revoke other users' sessions.
+func TestMutation_RevokeUserSession(t *testing.T) {
+	const session2GQLID = "VXNlclNlc3Npb246Mg==" // session 2 belongs to user 2
+
+	setup := func(t *testing.T, siteAdmin bool) (calledDelete *bool) {
+		resetMocks()
+		db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+			return &types.User{ID: 1, SiteAdmin: siteAdmin}, nil
+		}
+		db.Mocks.UserSessions.GetByID = func(ctx context.Context, id int64) (*db.UserSession, error) {
+			return &db.UserSession{ID: id, UserID: 2}, nil
+		}
+		calledDelete = new(bool)
+		db.Mocks.UserSessions.Delete = func(ctx context.Context, id int64) error {
+			*calledDelete = true
+			if want := int64(2); id != want {
+				t.Errorf("got session ID %d, want %d", id, want)
+			}
+			return nil
+		}
+		db.Mocks.AuditLog.Create = func(*db.AuditLogEntry) error { return nil }
+		return calledDelete
+	}
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+
+	t.Run("other user", func(t *testing.T) {
+		calledDelete := setup(t, false)
+		if _, err := (&schemaResolver{}).RevokeUserSession(ctx, &struct{ Session graphql.ID }{Session: session2GQLID}); err == nil {
+			t.Error("got nil error, want non-nil")
+		}
+		if *calledDelete {
+			t.Error("calledDelete")
+		}
+	})
+
+	t.Run("site admin", func(t *testing.T) {
+		calledDelete := setup(t, true)
+		if _, err := (&schemaResolver{}).RevokeUserSession(ctx, &struct{ Session graphql.ID }{Session: session2GQLID}); err != nil {
+			t.Fatal(err)
+		}
+		if !*calledDelete {
+			t.Error("!calledDelete")
+		}
+	})
+}
+
+// 🚨 SECURITY: This tests that users can't revoke other users' sessions.
+func TestMutation_RevokeAllUserSessions(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: 1}, nil
+	}
+	var deletedUserIDs []int32
+	db.Mocks.UserSessions.DeleteByUser = func(ctx context.Context, userID int32) error {
+		deletedUserIDs = append(deletedUserIDs, userID)
+		return nil
+	}
+	db.Mocks.AuditLog.Create = func(*db.AuditLogEntry) error { return nil }
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	if _, err := (&schemaResolver{}).RevokeAllUserSessions(ctx, &struct{ User graphql.ID }{User: MarshalUserID(2)}); err == nil {
+		t.Error("other user: got nil error, want non-nil")
+	}
+	if _, err := (&schemaResolver{}).RevokeAllUserSessions(ctx, &struct{ User graphql.ID }{User: MarshalUserID(1)}); err != nil {
+		t.Fatal(err)
+	}
+	if len(deletedUserIDs) != 1 || deletedUserIDs[0] != 1 {
+		t.Errorf("got deleted sessions of users %v, want [1]", deletedUserIDs)
+	}
+}
@@ -0,0 +1,95 @@
+package graphqlbackend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+func (r *UserResolver) State(ctx context.Context) (string, error) {
+	// 🚨 SECURITY: Only the user and admins are allowed to see the state of the user account.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
+		return "", err
+	}
+	state := r.user.State
+	if state == "" {
+		state = types.UserStateActive
+	}
+	return strings.ToUpper(string(state)), nil
+}
+
+// userStateFromGraphQL converts a GraphQL UserState enum value (e.g., "PENDING_APPROVAL") to the
+// corresponding types.UserState.
+func userStateFromGraphQL(state string) types.UserState {
+	return types.UserState(strings.ToLower(state))
+}
+
+func (*schemaResolver) SuspendUser(ctx context.Context, args *struct {
+	User graphql.ID
+}) (*EmptyResponse, error) {
+	return setUserState(ctx, args.User, "suspendUser", types.UserStateSuspended)
+}
+
+func (*schemaResolver) ActivateUser(ctx context.Context, args *struct {
+	User graphql.ID
+}) (*EmptyResponse, error) {
+	return setUserState(ctx, args.User, "activateUser", types.UserStateActive)
+}
+
+func (*schemaResolver) ApproveUser(ctx context.Context, args *struct {
+	User graphql.ID
+}) (*EmptyResponse, error) {
+	return setUserState(ctx, args.User, "approveUser", types.UserStateActive)
+}
+
+func setUserState(ctx context.Context, id graphql.ID, action string, state types.UserState) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can change the state of user accounts.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	currentUser, err := CurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if currentUser.ID() == id {
+		return nil, errors.New("unable to change the state of the current user")
+	}
+
+	userID, err := UnmarshalUserID(id)
+	if err != nil {
+		return nil, err
+	}
+	user, err := db.Users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	switch action {
+	case "approveUser":
+		if user.State != types.UserStatePendingApproval {
+			return nil, fmt.Errorf("user %q is not pending approval", user.Username)
+		}
+	case "activateUser":
+		if user.State != types.UserStateSuspended {
+			return nil, fmt.Errorf("user %q is not suspended", user.Username)
+		}
+	}
+
+	if err := db.Users.SetState(ctx, userID, state); err != nil {
+		return nil, err
+	}
+	backend.LogAuditEvent(ctx, backend.AuditEvent{
+		Action:     action,
+		TargetKind: "User",
+		TargetID:   string(id),
+		TargetName: user.Username,
+		Changes:    []db.AuditLogChange{{Field: "state", Before: user.State, After: state}},
+	})
+	return &EmptyResponse{}, nil
+}
@@ -0,0 +1,136 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+)
+
+func TestUsers_state(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1, SiteAdmin: true}, nil
+	}
+	db.Mocks.Users.List = func(ctx context.Context, opt *db.UsersListOptions) ([]*types.User, error) {
+		if want := types.UserStatePendingApproval; opt.State != want {
+			t.Errorf("got state %q, want %q", opt.State, want)
+		}
+		return []*types.User{{ID: 2, Username: "user2", State: types.UserStatePendingApproval}}, nil
+	}
+	db.Mocks.Users.Count = func(context.Context, *db.UsersListOptions) (int, error) { return 1, nil }
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+			Schema:  GraphQLSchema,
+			Query: `
+				{
+					users(state: PENDING_APPROVAL) {
+						nodes { username state }
+						totalCount
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"users": {
+						"nodes": [
+							{
+								"username": "user2",
+								"state": "PENDING_APPROVAL"
+							}
+						],
+						"totalCount": 1
+					}
+				}
+			`,
+		},
+	})
+}
+
+// 🚨 SECURITY: This tests that only site admins can suspend, reactivate and approve users.
+func TestMutation_SetUserState(t *testing.T) {
+	setup := func(t *testing.T, siteAdmin bool, state types.UserState) (gotState *types.UserState) {
+		resetMocks()
+		db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+			return &types.User{ID: 1, SiteAdmin: siteAdmin}, nil
+		}
+		db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+			return &types.User{ID: id, Username: "alice", State: state}, nil
+		}
+		gotState = new(types.UserState)
+		db.Mocks.Users.SetState = func(id int32, state types.UserState) error {
+			if want := int32(2); id != want {
+				t.Errorf("got user ID %d, want %d", id, want)
+			}
+			*gotState = state
+			return nil
+		}
+		db.Mocks.AuditLog.Create = func(*db.AuditLogEntry) error { return nil }
+		return gotState
+	}
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	args := &struct{ User graphql.ID }{User: MarshalUserID(2)}
+
+	t.Run("non-site admin", func(t *testing.T) {
+		gotState := setup(t, false, types.UserStateActive)
+		if _, err := (&schemaResolver{}).SuspendUser(ctx, args); err == nil {
+			t.Error("got nil error, want non-nil")
+		}
+		if *gotState != "" {
+			t.Errorf("got state %q, want unchanged", *gotState)
+		}
+	})
+
+	t.Run("suspend", func(t *testing.T) {
+		gotState := setup(t, true, types.UserStateActive)
+		if _, err := (&schemaResolver{}).SuspendUser(ctx, args); err != nil {
+			t.Fatal(err)
+		}
+		if *gotState != types.UserStateSuspended {
+			t.Errorf("got state %q, want %q", *gotState, types.UserStateSuspended)
+		}
+	})
+
+	t.Run("suspend current user", func(t *testing.T) {
+		setup(t, true, types.UserStateActive)
+		if _, err := (&schemaResolver{}).SuspendUser(ctx, &struct{ User graphql.ID }{User: MarshalUserID(1)}); err == nil {
+			t.Error("got nil error, want non-nil")
+		}
+	})
+
+	t.Run("activate", func(t *testing.T) {
+		gotState := setup(t, true, types.UserStateSuspended)
+		if _, err := (&schemaResolver{}).ActivateUser(ctx, args); err != nil {
+			t.Fatal(err)
+		}
+		if *gotState != types.UserStateActive {
+			t.Errorf("got state %q, want %q", *gotState, types.UserStateActive)
+		}
+	})
+
+	t.Run("approve user who is not pending approval", func(t *testing.T) {
+		gotState := setup(t, true, types.UserStateSuspended)
+		if _, err := (&schemaResolver{}).ApproveUser(ctx, args); err == nil {
+			t.Error("got nil error, want non-nil")
+		}
+		if *gotState != "" {
+			t.Errorf("got state %q, want unchanged", *gotState)
+		}
+	})
+
+	t.Run("approve", func(t *testing.T) {
+		gotState := setup(t, true, types.UserStatePendingApproval)
+		if _, err := (&schemaResolver{}).ApproveUser(ctx, args); err != nil {
+			t.Fatal(err)
+		}
+		if *gotState != types.UserStateActive {
+			t.Errorf("got state %q, want %q", *gotState, types.UserStateActive)
+		}
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"sync"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
@@ -14,12 +15,13 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 )
 
-func (r *schemaResolver) Users(args *struct {
+func (r *schemaResolver) Users(ctx context.Context, args *struct {
 	graphqlutil.ConnectionArgs
 	Query        *string
 	Tag          *string
 	ActivePeriod *string
-}) *userConnectionResolver {
+	State        *string
+}) (*userConnectionResolver, error) {
 	var opt db.UsersListOptions
 	if args.Query != nil {
 		opt.Query = *args.Query
@@ -27,8 +29,15 @@ func (r *schemaResolver) Users(args *struct {
 	if args.Tag != nil {
 		opt.Tag = *args.Tag
 	}
+	if args.State != nil {
+		// 🚨 SECURITY: Only site admins can list users by state (e.g., to see who is suspended).
+		if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+			return nil, err
+		}
+		opt.State = userStateFromGraphQL(*args.State)
+	}
 	args.ConnectionArgs.Set(&opt.LimitOffset)
-	return &userConnectionResolver{opt: opt, activePeriod: args.ActivePeriod}
+	return &userConnectionResolver{opt: opt, activePeriod: args.ActivePeriod}, nil
 }
 
 type userConnectionResolver struct {
@@ -34,6 +34,12 @@ func (*schemaResolver) CreateUser(ctx context.Context, args *struct {
 	if err != nil {
 		return nil, err
 	}
+	backend.LogAuditEvent(ctx, backend.AuditEvent{
+		Action:     "createUser",
+		TargetKind: "User",
+		TargetID:   string(marshalUserID(user.ID)),
+		TargetName: user.Username,
+	})
 	return &createUserResult{user: user}, nil
 }
 
@@ -46,6 +46,11 @@ func (*schemaResolver) RandomizeUserPassword(ctx context.Context, args *struct {
 	if err := db.Users.RandomizePasswordAndClearPasswordResetRateLimit(ctx, userID); err != nil {
 		return nil, err
 	}
+	backend.LogAuditEvent(ctx, backend.AuditEvent{
+		Action:     "randomizeUserPassword",
+		TargetKind: "User",
+		TargetID:   string(args.User),
+	})
 
 	return &randomizeUserPasswordResult{userID: userID}, nil
 }
@@ -10,3 +10,6 @@ var PreAuthMiddleware func(http.Handler) http.Handler
 // AfterDBInit is called after the database is initialized, and can be used to
 // e.g. launch background services that depend on the database.
 var AfterDBInit func()
+
+// AfterUserSignIn, if set, is called after a user signs in. It must not block.
+var AfterUserSignIn func(userID int32)
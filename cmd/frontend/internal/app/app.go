@@ -55,6 +55,8 @@ func NewHandler() http.Handler {
 	r.Get(router.SignUp).Handler(trace.TraceRoute(http.HandlerFunc(userpasswd.HandleSignUp)))
 	r.Get(router.SiteInit).Handler(trace.TraceRoute(http.HandlerFunc(userpasswd.HandleSiteInit)))
 	r.Get(router.SignIn).Handler(trace.TraceRoute(http.HandlerFunc(userpasswd.HandleSignIn)))
+	r.Get(router.SignInMFA).Handler(trace.TraceRoute(http.HandlerFunc(userpasswd.HandleSignInMFA)))
+	r.Get(router.SignInMFAEnroll).Handler(trace.TraceRoute(http.HandlerFunc(userpasswd.HandleSignInMFAEnroll)))
 	r.Get(router.SignOut).Handler(trace.TraceRoute(http.HandlerFunc(serveSignOut)))
 	r.Get(router.VerifyEmail).Handler(trace.TraceRoute(http.HandlerFunc(serveVerifyEmail)))
 	r.Get(router.ResetPasswordInit).Handler(trace.TraceRoute(http.HandlerFunc(userpasswd.HandleResetPasswordInit)))
@@ -16,6 +16,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/errcode"
 	"github.com/sourcegraph/sourcegraph/pkg/gitserver"
 	"github.com/sourcegraph/sourcegraph/pkg/repoupdater"
+	"github.com/sourcegraph/sourcegraph/pkg/repoupdater/protocol"
 	"github.com/sourcegraph/sourcegraph/pkg/routevar"
 	"github.com/sourcegraph/sourcegraph/pkg/vcs/git"
 	log15 "gopkg.in/inconshreveable/log15.v2"
@@ -189,6 +190,11 @@ func newCommon(w http.ResponseWriter, r *http.Request, title string, serveError
 			if err != nil {
 				log15.Error("EnqueueRepoUpdate", "error", err)
 			}
+
+			err = repoupdater.DefaultClient.RecordRepoDemand(ctx, protocol.RepoDemandView, uint32(common.Repo.ID))
+			if err != nil {
+				log15.Error("RecordRepoDemand", "error", err)
+			}
 		}()
 	}
 	return common, nil
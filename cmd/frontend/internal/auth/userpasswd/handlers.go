@@ -128,15 +128,21 @@ func handleSignUp(w http.ResponseWriter, r *http.Request, failIfNewUserIsNotInit
 		}
 	}
 
-	// Write the session cookie
-	if session.SetActor(w, r, actor, 0); err != nil {
-		httpLogAndError(w, "Could not create new user session", http.StatusInternalServerError)
-	}
-
 	// Track user data
 	if r.UserAgent() != "Sourcegraph e2etest-bot" {
 		go tracking.SyncUser(creds.Email, hubspotutil.SignupEventID, nil)
 	}
+
+	// 🚨 SECURITY: If the new user must use multi-factor authentication, don't sign in the user
+	// until the user has enrolled.
+	if handleMFASignIn(w, r, usr) {
+		return
+	}
+
+	// Write the session cookie
+	if err := session.SetSignedInActor(w, r, actor, 0, session.SignInInfo{AuthProvider: providerType}); err != nil {
+		httpLogAndError(w, "Could not create new user session", http.StatusInternalServerError)
+	}
 }
 
 func getByEmailOrUsername(ctx context.Context, emailOrUsername string) (*types.User, error) {
@@ -181,10 +187,22 @@ func HandleSignIn(w http.ResponseWriter, r *http.Request) {
 		httpLogAndError(w, "Authentication failed", http.StatusUnauthorized)
 		return
 	}
+	// 🚨 SECURITY: Suspended users and users pending approval can't sign in.
+	if !usr.State.Active() {
+		httpLogAndError(w, "Your user account is suspended or pending approval. Ask a site admin for help.", http.StatusForbidden, "userID", usr.ID, "state", usr.State)
+		return
+	}
+
+	// 🚨 SECURITY: If the user must use multi-factor authentication, don't sign in the user until
+	// the second factor is verified.
+	if handleMFASignIn(w, r, usr) {
+		return
+	}
+
 	actor := &actor.Actor{UID: usr.ID}
 
 	// Write the session cookie
-	if session.SetActor(w, r, actor, 0); err != nil {
+	if session.SetSignedInActor(w, r, actor, 0, session.SignInInfo{AuthProvider: providerType}); err != nil {
 		httpLogAndError(w, "Could not create new user session", http.StatusInternalServerError)
 		return
 	}
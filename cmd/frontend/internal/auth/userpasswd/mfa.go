@@ -0,0 +1,480 @@
+package userpasswd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/totp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/session"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// This file implements multi-factor authentication (MFA) for builtin auth users, using time-based
+// one-time passwords (TOTP) from an authenticator app as the second factor, and single-use
+// recovery codes as a fallback.
+//
+// When a user who has enabled MFA (or who is required to use MFA per the builtin auth provider's
+// requireMFA) signs in with a correct password, the user is not signed in yet. Instead, the pending
+// sign-in is recorded in the session, and the user must complete the sign-in by supplying a code
+// (or, if the user has not enrolled, by enrolling).
+
+const (
+	// numRecoveryCodes is the number of recovery codes that are generated for a user.
+	numRecoveryCodes = 10
+
+	// pendingMFASignInExpiry is how long a user has to complete a pending sign-in.
+	pendingMFASignInExpiry = 10 * time.Minute
+
+	// maxMFAAttempts is the number of consecutive incorrect codes that a user may supply (in any
+	// session) before the user is locked out for mfaLockoutBase and must enter their password
+	// again. Each further maxMFAAttempts incorrect codes double the lockout, up to mfaLockoutMax.
+	maxMFAAttempts = 5
+	mfaLockoutBase = time.Minute
+	mfaLockoutMax  = time.Hour
+
+	pendingMFASignInSessionKey = "mfaPendingSignIn"
+)
+
+func init() {
+	// 🚨 SECURITY: Reject the sessions of users who must use MFA but did not satisfy it when signing
+	// in with a password. The auth provider of a session is unknown if the session was created before
+	// it was recorded, in which case the user may have signed in with a password.
+	session.MFARequired = func(user *types.User, authProvider string) bool {
+		return (authProvider == providerType || authProvider == "") && mfaRequired(user)
+	}
+}
+
+// errInvalidMFACode occurs when an incorrect TOTP or recovery code is supplied.
+var errInvalidMFACode = errors.New("invalid multi-factor authentication code")
+
+// mfaRequired reports whether the user must use MFA to sign in with a password (per the builtin
+// auth provider's requireMFA).
+func mfaRequired(user *types.User) bool {
+	pc, _ := getProviderConfig()
+	if pc == nil {
+		return false
+	}
+	switch pc.RequireMFA {
+	case "all":
+		return true
+	case "site-admins":
+		return user.SiteAdmin
+	}
+	return false
+}
+
+// MFAEnabled reports whether the user has enabled MFA.
+func MFAEnabled(ctx context.Context, userID int32) (bool, error) {
+	t, err := db.UserMFA.GetTOTP(ctx, userID)
+	if errcode.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return t.EnabledAt != nil, nil
+}
+
+// TOTPEnrollment is the information a user needs to add Sourcegraph to their authenticator app.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URL    string `json:"url"` // the otpauth:// URL (usually presented as a QR code)
+}
+
+// BeginTOTPEnrollment generates a new TOTP secret for the user. It is not used to verify sign-ins
+// until the user confirms the enrollment (with ConfirmTOTPEnrollment).
+//
+// 🚨 SECURITY: The caller must ensure that the actor is the user.
+func BeginTOTPEnrollment(ctx context.Context, user *types.User) (*TOTPEnrollment, error) {
+	if pc, _ := getProviderConfig(); pc == nil {
+		return nil, errors.New("multi-factor authentication requires the builtin auth provider")
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.UserMFA.SetPendingTOTP(ctx, user.ID, secret); err != nil {
+		return nil, err
+	}
+	accountName := user.Username
+	if host := globals.ExternalURL().Host; host != "" {
+		accountName += "@" + host
+	}
+	return &TOTPEnrollment{Secret: secret, URL: totp.URL("Sourcegraph", accountName, secret)}, nil
+}
+
+// ConfirmTOTPEnrollment completes the user's pending TOTP enrollment if the code is correct, and
+// returns the user's new recovery codes.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is the user.
+func ConfirmTOTPEnrollment(ctx context.Context, userID int32, code string) (recoveryCodes []string, err error) {
+	t, err := db.UserMFA.GetTOTP(ctx, userID)
+	if errcode.IsNotFound(err) {
+		return nil, errors.New("no multi-factor authentication enrollment is in progress")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if t.EnabledAt != nil {
+		return nil, db.ErrTOTPAlreadyEnabled
+	}
+	counter, ok := totp.Validate(t.Secret, normalizeCode(code), time.Now())
+	if !ok {
+		return nil, errInvalidMFACode
+	}
+	recoveryCodes, err = generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.UserMFA.EnableTOTP(ctx, userID, counter, normalizeCodes(recoveryCodes)); err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes if the TOTP code is correct, and
+// returns the new recovery codes. (A recovery code is not accepted, so that a user can't use one
+// recovery code to obtain more.)
+//
+// 🚨 SECURITY: The caller must ensure that the actor is the user.
+func RegenerateRecoveryCodes(ctx context.Context, userID int32, code string) (recoveryCodes []string, err error) {
+	t, err := db.UserMFA.GetTOTP(ctx, userID)
+	if err != nil && !errcode.IsNotFound(err) {
+		return nil, err
+	}
+	if t == nil || t.EnabledAt == nil {
+		return nil, errors.New("multi-factor authentication is not enabled")
+	}
+	if ok, err := verifyTOTPCode(ctx, t, code); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, errInvalidMFACode
+	}
+	recoveryCodes, err = generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.UserMFA.SetRecoveryCodes(ctx, userID, normalizeCodes(recoveryCodes)); err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// verifyMFACode reports whether the code is a valid TOTP code or unused recovery code for the user.
+// A code can only be used once.
+//
+// 🚨 SECURITY: Any change to this function could allow users to sign in without a valid second
+// factor. Be careful.
+func verifyMFACode(ctx context.Context, userID int32, code string) (bool, error) {
+	t, err := db.UserMFA.GetTOTP(ctx, userID)
+	if errcode.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if t.EnabledAt == nil {
+		return false, nil
+	}
+	if ok, err := verifyTOTPCode(ctx, t, code); err != nil || ok {
+		return ok, err
+	}
+	return db.UserMFA.UseRecoveryCode(ctx, userID, normalizeCode(code))
+}
+
+func verifyTOTPCode(ctx context.Context, t *db.UserTOTP, code string) (bool, error) {
+	counter, ok := totp.Validate(t.Secret, normalizeCode(code), time.Now())
+	if !ok {
+		return false, nil
+	}
+	// 🚨 SECURITY: Reject codes that were already used.
+	return db.UserMFA.UseTOTPCounter(ctx, t.UserID, counter)
+}
+
+// normalizeCode removes the spaces and dashes that users might enter as part of a code (because
+// authenticator apps often display codes like "123 456", and recovery codes are shown like
+// "abcde-fghij").
+func normalizeCode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(code))
+}
+
+// normalizeCodes returns the normalized form of the recovery codes, which is the form in which they
+// are stored (so that the normalized codes that users supply match).
+func normalizeCodes(codes []string) []string {
+	normalized := make([]string, len(codes))
+	for i, code := range codes {
+		normalized[i] = normalizeCode(code)
+	}
+	return normalized
+}
+
+// generateRecoveryCodes returns numRecoveryCodes new random recovery codes (each with 50 bits of
+// entropy), formatted like "abcde-fghij".
+func generateRecoveryCodes() ([]string, error) {
+	codes := make([]string, numRecoveryCodes)
+	for i := range codes {
+		var b [5]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return nil, err
+		}
+		s := strings.ToLower(base32.StdEncoding.EncodeToString(b[:]))
+		codes[i] = s[:5] + "-" + s[5:]
+	}
+	return codes, nil
+}
+
+// pendingMFASignIn is stored in the session when a user signed in with a correct password but has
+// not yet completed MFA.
+//
+// The incorrect codes that the user supplied are counted per user (in the database), not per
+// pending sign-in, so that signing in with the password again does not allow more guesses.
+type pendingMFASignIn struct {
+	UserID int32     `json:"userID"`
+	Enroll bool      `json:"enroll"` // whether the user must enroll (instead of supplying a code)
+	Expiry time.Time `json:"expiry"`
+}
+
+// mfaSignInResponse is the response to a sign-in request that requires MFA to complete.
+type mfaSignInResponse struct {
+	// MFA is "verify" if the user must supply a code, or "enroll" if the user must enroll.
+	MFA string `json:"mfa"`
+}
+
+// handleMFASignIn is called after the user's password has been verified. If the user must use MFA,
+// it records the pending sign-in in the session, writes the response, and returns true. Otherwise it
+// returns false, and the caller should sign in the user.
+//
+// 🚨 SECURITY: Any change to this function could allow users to sign in without a valid second
+// factor. Be careful.
+func handleMFASignIn(w http.ResponseWriter, r *http.Request, usr *types.User) (handled bool) {
+	enabled, err := MFAEnabled(r.Context(), usr.ID)
+	if err != nil {
+		httpLogAndError(w, "Error checking multi-factor authentication", http.StatusInternalServerError, "err", err)
+		return true
+	}
+	if !enabled && !mfaRequired(usr) {
+		return false
+	}
+
+	pending := &pendingMFASignIn{
+		UserID: usr.ID,
+		Enroll: !enabled,
+		Expiry: time.Now().Add(pendingMFASignInExpiry),
+	}
+	if err := session.SetData(w, r, pendingMFASignInSessionKey, pending); err != nil {
+		httpLogAndError(w, "Could not create new user session", http.StatusInternalServerError, "err", err)
+		return true
+	}
+	resp := mfaSignInResponse{MFA: "verify"}
+	if pending.Enroll {
+		resp.MFA = "enroll"
+	}
+	writeJSON(w, resp)
+	return true
+}
+
+// getPendingMFASignIn returns the pending sign-in from the session, or nil if there is none (or it
+// expired).
+func getPendingMFASignIn(r *http.Request) (*pendingMFASignIn, error) {
+	var pending *pendingMFASignIn
+	if err := session.GetData(r, pendingMFASignInSessionKey, &pending); err != nil {
+		return nil, err
+	}
+	if pending == nil || pending.UserID == 0 || time.Now().After(pending.Expiry) {
+		return nil, nil
+	}
+	return pending, nil
+}
+
+// HandleSignInMFA accepts a POST containing a TOTP code or recovery code and, if it is valid,
+// completes the pending sign-in (started by HandleSignIn).
+func HandleSignInMFA(w http.ResponseWriter, r *http.Request) {
+	if handleEnabledCheck(w) {
+		return
+	}
+	pending, params, ok := decodeMFASignInRequest(w, r)
+	if !ok {
+		return
+	}
+	if pending.Enroll {
+		http.Error(w, "Multi-factor authentication enrollment is required.", http.StatusBadRequest)
+		return
+	}
+
+	// 🚨 SECURITY: Check the second factor.
+	valid, err := verifyMFACode(r.Context(), pending.UserID, params.Code)
+	if err != nil {
+		httpLogAndError(w, "Error checking multi-factor authentication code", http.StatusInternalServerError, "err", err)
+		return
+	}
+	if !valid {
+		handleInvalidMFACode(w, r, pending)
+		return
+	}
+	completeMFASignIn(w, r, pending.UserID)
+}
+
+// HandleSignInMFAEnroll enrolls a user who must use MFA but has not enrolled (during a pending
+// sign-in started by HandleSignIn).
+//
+// A POST with an empty code begins the enrollment, and the response contains the TOTP secret. A
+// POST with a code confirms the enrollment and completes the sign-in, and the response contains the
+// user's recovery codes.
+func HandleSignInMFAEnroll(w http.ResponseWriter, r *http.Request) {
+	if handleEnabledCheck(w) {
+		return
+	}
+	pending, params, ok := decodeMFASignInRequest(w, r)
+	if !ok {
+		return
+	}
+	if !pending.Enroll {
+		http.Error(w, "Multi-factor authentication is already enabled.", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	if params.Code == "" {
+		usr, err := db.Users.GetByID(ctx, pending.UserID)
+		if err != nil {
+			httpLogAndError(w, "Error looking up user", http.StatusInternalServerError, "err", err)
+			return
+		}
+		enrollment, err := BeginTOTPEnrollment(ctx, usr)
+		if err != nil {
+			httpLogAndError(w, "Error beginning multi-factor authentication enrollment", http.StatusInternalServerError, "err", err)
+			return
+		}
+		writeJSON(w, enrollment)
+		return
+	}
+
+	recoveryCodes, err := ConfirmTOTPEnrollment(ctx, pending.UserID, params.Code)
+	if err == errInvalidMFACode {
+		handleInvalidMFACode(w, r, pending)
+		return
+	}
+	if err != nil {
+		httpLogAndError(w, "Error completing multi-factor authentication enrollment", http.StatusInternalServerError, "err", err)
+		return
+	}
+	if !completeMFASignIn(w, r, pending.UserID) {
+		return
+	}
+	writeJSON(w, struct {
+		RecoveryCodes []string `json:"recoveryCodes"`
+	}{RecoveryCodes: recoveryCodes})
+}
+
+func decodeMFASignInRequest(w http.ResponseWriter, r *http.Request) (pending *pendingMFASignIn, params struct{ Code string }, ok bool) {
+	if r.Method != "POST" {
+		http.Error(w, fmt.Sprintf("Unsupported method %s", r.Method), http.StatusBadRequest)
+		return nil, params, false
+	}
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		http.Error(w, "Could not decode request body", http.StatusBadRequest)
+		return nil, params, false
+	}
+	pending, err := getPendingMFASignIn(r)
+	if err != nil {
+		httpLogAndError(w, "Error reading session", http.StatusInternalServerError, "err", err)
+		return nil, params, false
+	}
+	if pending == nil {
+		http.Error(w, "No sign-in is in progress (or it expired). Sign in again.", http.StatusUnauthorized)
+		return nil, params, false
+	}
+
+	// 🚨 SECURITY: Reject codes from users who supplied too many incorrect codes recently.
+	lockedUntil, err := db.UserMFA.LockedUntil(r.Context(), pending.UserID)
+	if err != nil {
+		httpLogAndError(w, "Error checking multi-factor authentication lockout", http.StatusInternalServerError, "err", err)
+		return nil, params, false
+	}
+	if wait := time.Until(lockedUntil); wait > 0 {
+		http.Error(w, fmt.Sprintf("Too many incorrect codes. Try again in %s.", wait.Round(time.Second)), http.StatusTooManyRequests)
+		return nil, params, false
+	}
+	return pending, params, true
+}
+
+// handleInvalidMFACode records a failed attempt to complete the pending sign-in and writes the
+// error response. After too many consecutive failed attempts (in any session), the user is locked
+// out and the pending sign-in is discarded.
+func handleInvalidMFACode(w http.ResponseWriter, r *http.Request, pending *pendingMFASignIn) {
+	ctx := r.Context()
+	failedAttempts, err := db.UserMFA.RecordFailedAttempt(ctx, pending.UserID)
+	if err != nil {
+		httpLogAndError(w, "Error recording failed multi-factor authentication attempt", http.StatusInternalServerError, "err", err)
+		return
+	}
+	lockout := mfaLockout(failedAttempts)
+	if lockout == 0 {
+		http.Error(w, "Incorrect code", http.StatusUnauthorized)
+		return
+	}
+
+	log15.Warn("Too many failed multi-factor authentication attempts.", "userID", pending.UserID, "failedAttempts", failedAttempts, "lockout", lockout)
+	if err := db.UserMFA.LockUntil(ctx, pending.UserID, time.Now().Add(lockout)); err != nil {
+		httpLogAndError(w, "Error locking out user", http.StatusInternalServerError, "err", err)
+		return
+	}
+	if err := session.SetData(w, r, pendingMFASignInSessionKey, nil); err != nil {
+		httpLogAndError(w, "Error writing session", http.StatusInternalServerError, "err", err)
+		return
+	}
+	http.Error(w, fmt.Sprintf("Too many incorrect codes. Wait %s and sign in again.", lockout), http.StatusUnauthorized)
+}
+
+// mfaLockout returns how long a user who supplied the given number of consecutive incorrect codes
+// is locked out, or 0 if the user may supply another code right away.
+func mfaLockout(failedAttempts int) time.Duration {
+	if failedAttempts < maxMFAAttempts || failedAttempts%maxMFAAttempts != 0 {
+		return 0
+	}
+	lockout := mfaLockoutBase
+	for i := maxMFAAttempts; i < failedAttempts && lockout < mfaLockoutMax; i += maxMFAAttempts {
+		lockout *= 2
+	}
+	if lockout > mfaLockoutMax {
+		lockout = mfaLockoutMax
+	}
+	return lockout
+}
+
+// completeMFASignIn discards the pending sign-in and signs in the user, recording in the session
+// that the user satisfied MFA.
+func completeMFASignIn(w http.ResponseWriter, r *http.Request, userID int32) bool {
+	if err := db.UserMFA.ResetFailedAttempts(r.Context(), userID); err != nil {
+		httpLogAndError(w, "Error resetting failed multi-factor authentication attempts", http.StatusInternalServerError, "err", err)
+		return false
+	}
+	if err := session.SetData(w, r, pendingMFASignInSessionKey, nil); err != nil {
+		httpLogAndError(w, "Error writing session", http.StatusInternalServerError, "err", err)
+		return false
+	}
+	if err := session.SetSignedInActor(w, r, &actor.Actor{UID: userID}, 0, session.SignInInfo{AuthProvider: providerType, MFASatisfied: true}); err != nil {
+		httpLogAndError(w, "Could not create new user session", http.StatusInternalServerError, "err", err)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log15.Error("Error writing JSON response.", "err", err)
+	}
+}
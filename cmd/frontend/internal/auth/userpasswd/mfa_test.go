@@ -0,0 +1,386 @@
+package userpasswd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/totp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/session"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// mfaTestClient sends requests to the sign-in handlers, keeping the session cookie between
+// requests (like a browser).
+type mfaTestClient struct {
+	cookies map[string]*http.Cookie
+}
+
+func (c *mfaTestClient) post(handler http.HandlerFunc, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	for _, cookie := range c.cookies {
+		req.AddCookie(cookie)
+	}
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+	for _, cookie := range rr.Result().Cookies() {
+		c.cookies[cookie.Name] = cookie
+	}
+	return rr
+}
+
+// actor returns the actor that the session cookie authenticates.
+func (c *mfaTestClient) actor() *actor.Actor {
+	var a *actor.Actor
+	c.post(session.CookieMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a = actor.FromContext(r.Context())
+	})).ServeHTTP, "")
+	return a
+}
+
+func setupMFATest(t *testing.T, requireMFA string) (client *mfaTestClient, userTOTP *db.UserTOTP, cleanup func()) {
+	conf.Mock(&conf.Unified{Critical: schema.CriticalConfiguration{AuthProviders: []schema.AuthProviders{{Builtin: &schema.BuiltinAuthProvider{Type: "builtin", RequireMFA: requireMFA}}}}})
+	cleanupSessionStore := session.ResetMockSessionStore(t)
+
+	user := &types.User{ID: 1, Username: "alice"}
+	db.Mocks.Users.GetByUsername = func(ctx context.Context, username string) (*types.User, error) {
+		if username != user.Username {
+			return nil, fmt.Errorf("user %q not found", username)
+		}
+		return user, nil
+	}
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return user, nil
+	}
+	db.Mocks.Users.IsPassword = func(ctx context.Context, id int32, password string) (bool, error) {
+		return id == user.ID && password == "p", nil
+	}
+
+	// An in-memory implementation of the db.UserMFA store (for a single user).
+	userTOTP = &db.UserTOTP{UserID: user.ID}
+	recoveryCodes := map[string]bool{} // code -> used
+	db.Mocks.UserMFA.GetTOTP = func(ctx context.Context, userID int32) (*db.UserTOTP, error) {
+		if userTOTP.Secret == "" {
+			return nil, &errNotFound{}
+		}
+		tmp := *userTOTP
+		return &tmp, nil
+	}
+	db.Mocks.UserMFA.SetPendingTOTP = func(ctx context.Context, userID int32, secret string) error {
+		if userTOTP.EnabledAt != nil {
+			return db.ErrTOTPAlreadyEnabled
+		}
+		userTOTP.Secret = secret
+		return nil
+	}
+	db.Mocks.UserMFA.EnableTOTP = func(ctx context.Context, userID int32, counter int64, codes []string) error {
+		now := time.Now()
+		userTOTP.EnabledAt = &now
+		userTOTP.LastUsedCounter = counter
+		recoveryCodes = map[string]bool{}
+		for _, code := range codes {
+			recoveryCodes[code] = false
+		}
+		return nil
+	}
+	db.Mocks.UserMFA.UseTOTPCounter = func(ctx context.Context, userID int32, counter int64) (bool, error) {
+		if userTOTP.EnabledAt == nil || counter <= userTOTP.LastUsedCounter {
+			return false, nil
+		}
+		userTOTP.LastUsedCounter = counter
+		return true, nil
+	}
+	db.Mocks.UserMFA.UseRecoveryCode = func(ctx context.Context, userID int32, code string) (bool, error) {
+		if used, ok := recoveryCodes[code]; !ok || used {
+			return false, nil
+		}
+		recoveryCodes[code] = true
+		return true, nil
+	}
+	var (
+		failedAttempts int
+		lockedUntil    time.Time
+	)
+	db.Mocks.UserMFA.RecordFailedAttempt = func(ctx context.Context, userID int32) (int, error) {
+		failedAttempts++
+		return failedAttempts, nil
+	}
+	db.Mocks.UserMFA.LockUntil = func(ctx context.Context, userID int32, t time.Time) error {
+		lockedUntil = t
+		return nil
+	}
+	db.Mocks.UserMFA.LockedUntil = func(ctx context.Context, userID int32) (time.Time, error) {
+		return lockedUntil, nil
+	}
+	db.Mocks.UserMFA.ResetFailedAttempts = func(ctx context.Context, userID int32) error {
+		failedAttempts, lockedUntil = 0, time.Time{}
+		return nil
+	}
+
+	client = &mfaTestClient{cookies: map[string]*http.Cookie{}}
+	return client, userTOTP, func() {
+		db.Mocks = db.MockStores{}
+		conf.Mock(nil)
+		cleanupSessionStore()
+	}
+}
+
+type errNotFound struct{}
+
+func (errNotFound) Error() string  { return "not found" }
+func (errNotFound) NotFound() bool { return true }
+
+func decodeJSON(t *testing.T, rr *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(rr.Body.Bytes(), v); err != nil {
+		t.Fatalf("decoding response body %q: %s", rr.Body.String(), err)
+	}
+}
+
+func TestSignIn_noMFA(t *testing.T) {
+	client, _, cleanup := setupMFATest(t, "none")
+	defer cleanup()
+
+	if rr := client.post(HandleSignIn, `{"email":"alice","password":"p"}`); rr.Code != http.StatusOK || rr.Body.Len() != 0 {
+		t.Fatalf("got %d %q, want 200 with empty body", rr.Code, rr.Body.String())
+	}
+	if a := client.actor(); a.UID != 1 || a.MFASatisfied {
+		t.Errorf("got actor %+v, want UID 1 without MFA", a)
+	}
+}
+
+// 🚨 SECURITY: This tests that suspended users can't sign in.
+func TestSignIn_suspended(t *testing.T) {
+	client, _, cleanup := setupMFATest(t, "none")
+	defer cleanup()
+	db.Mocks.Users.GetByUsername = func(ctx context.Context, username string) (*types.User, error) {
+		return &types.User{ID: 1, Username: "alice", State: types.UserStateSuspended}, nil
+	}
+
+	if rr := client.post(HandleSignIn, `{"email":"alice","password":"p"}`); rr.Code != http.StatusForbidden {
+		t.Fatalf("got %d %q, want 403", rr.Code, rr.Body.String())
+	}
+	if a := client.actor(); a.IsAuthenticated() {
+		t.Errorf("got authenticated actor %+v, want unauthenticated", a)
+	}
+}
+
+func TestSignIn_MFAEnrollAndVerify(t *testing.T) {
+	client, userTOTP, cleanup := setupMFATest(t, "all")
+	defer cleanup()
+
+	// The user must enroll before signing in.
+	var signInResp mfaSignInResponse
+	rr := client.post(HandleSignIn, `{"email":"alice","password":"p"}`)
+	decodeJSON(t, rr, &signInResp)
+	if signInResp.MFA != "enroll" {
+		t.Fatalf("got mfa %q, want %q", signInResp.MFA, "enroll")
+	}
+	if a := client.actor(); a.IsAuthenticated() {
+		t.Fatalf("got authenticated actor %+v before enrollment", a)
+	}
+
+	// Attempting to verify a code (instead of enrolling) fails.
+	if rr := client.post(HandleSignInMFA, `{"code":"123456"}`); rr.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+
+	var enrollment TOTPEnrollment
+	decodeJSON(t, client.post(HandleSignInMFAEnroll, `{"code":""}`), &enrollment)
+	if enrollment.Secret == "" || enrollment.Secret != userTOTP.Secret || !strings.HasPrefix(enrollment.URL, "otpauth://totp/") {
+		t.Fatalf("got enrollment %+v", enrollment)
+	}
+
+	// An incorrect code does not complete enrollment.
+	if rr := client.post(HandleSignInMFAEnroll, `{"code":"abcdef"}`); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("got status %d, want %d", rr.Code, http.StatusUnauthorized)
+	}
+
+	code, err := totp.Code(enrollment.Secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var enrollResp struct{ RecoveryCodes []string }
+	decodeJSON(t, client.post(HandleSignInMFAEnroll, fmt.Sprintf(`{"code":%q}`, code)), &enrollResp)
+	if len(enrollResp.RecoveryCodes) != numRecoveryCodes {
+		t.Fatalf("got %d recovery codes, want %d", len(enrollResp.RecoveryCodes), numRecoveryCodes)
+	}
+	if a := client.actor(); a.UID != 1 || !a.MFASatisfied {
+		t.Fatalf("got actor %+v, want UID 1 with MFA", a)
+	}
+
+	// Sign in again on a new client. Now the user must supply a code.
+	client = &mfaTestClient{cookies: map[string]*http.Cookie{}}
+	decodeJSON(t, client.post(HandleSignIn, `{"email":"alice","password":"p"}`), &signInResp)
+	if signInResp.MFA != "verify" {
+		t.Fatalf("got mfa %q, want %q", signInResp.MFA, "verify")
+	}
+
+	// The code that was used to enroll can't be reused.
+	if rr := client.post(HandleSignInMFA, fmt.Sprintf(`{"code":%q}`, code)); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("reused code: got status %d, want %d", rr.Code, http.StatusUnauthorized)
+	}
+	if a := client.actor(); a.IsAuthenticated() {
+		t.Fatalf("got authenticated actor %+v after incorrect code", a)
+	}
+
+	// A recovery code (entered in uppercase with spaces) can be used once.
+	recoveryCode := " " + strings.ToUpper(enrollResp.RecoveryCodes[0]) + " "
+	if rr := client.post(HandleSignInMFA, fmt.Sprintf(`{"code":%q}`, recoveryCode)); rr.Code != http.StatusOK {
+		t.Fatalf("recovery code: got status %d %q, want 200", rr.Code, rr.Body.String())
+	}
+	if a := client.actor(); a.UID != 1 || !a.MFASatisfied {
+		t.Fatalf("got actor %+v, want UID 1 with MFA", a)
+	}
+	if ok, err := verifyMFACode(context.Background(), 1, recoveryCode); err != nil || ok {
+		t.Errorf("reused recovery code: got %v (error %v), want false", ok, err)
+	}
+}
+
+// 🚨 SECURITY: This tests that creating the initial site admin account does not sign in the user
+// without multi-factor authentication if it is required.
+func TestSiteInit_MFARequired(t *testing.T) {
+	client, _, cleanup := setupMFATest(t, "site-admins")
+	defer cleanup()
+	db.Mocks.Users.Create = func(ctx context.Context, info db.NewUser) (*types.User, error) {
+		if !info.FailIfNotInitialUser {
+			t.Error("want FailIfNotInitialUser")
+		}
+		return &types.User{ID: 1, Username: info.Username, SiteAdmin: true}, nil
+	}
+
+	var signInResp mfaSignInResponse
+	decodeJSON(t, client.post(HandleSiteInit, `{"email":"alice@example.com","username":"alice","password":"p"}`), &signInResp)
+	if signInResp.MFA != "enroll" {
+		t.Fatalf("got mfa %q, want %q", signInResp.MFA, "enroll")
+	}
+	if a := client.actor(); a.IsAuthenticated() {
+		t.Fatalf("got authenticated actor %+v before enrollment", a)
+	}
+}
+
+// 🚨 SECURITY: This tests that sessions without MFA are rejected once MFA becomes required.
+func TestSession_MFABecomesRequired(t *testing.T) {
+	client, _, cleanup := setupMFATest(t, "none")
+	defer cleanup()
+
+	if rr := client.post(HandleSignIn, `{"email":"alice","password":"p"}`); rr.Code != http.StatusOK {
+		t.Fatalf("got %d %q, want 200", rr.Code, rr.Body.String())
+	}
+	if a := client.actor(); a.UID != 1 {
+		t.Fatalf("got actor %+v, want UID 1", a)
+	}
+
+	conf.Mock(&conf.Unified{Critical: schema.CriticalConfiguration{AuthProviders: []schema.AuthProviders{{Builtin: &schema.BuiltinAuthProvider{Type: "builtin", RequireMFA: "all"}}}}})
+	if a := client.actor(); a.IsAuthenticated() {
+		t.Errorf("got authenticated actor %+v without MFA after MFA became required", a)
+	}
+}
+
+// 🚨 SECURITY: test necessary to ensure security
+func TestSignIn_MFATooManyAttempts(t *testing.T) {
+	client, userTOTP, cleanup := setupMFATest(t, "none")
+	defer cleanup()
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	userTOTP.Secret = secret
+	userTOTP.EnabledAt = &now
+
+	signIn := func(client *mfaTestClient) {
+		t.Helper()
+		var signInResp mfaSignInResponse
+		decodeJSON(t, client.post(HandleSignIn, `{"email":"alice","password":"p"}`), &signInResp)
+		if signInResp.MFA != "verify" {
+			t.Fatalf("got mfa %q, want %q (MFA is enabled even though it is not required)", signInResp.MFA, "verify")
+		}
+	}
+	supplyWrongCodes := func(client *mfaTestClient, n int) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			if rr := client.post(HandleSignInMFA, `{"code":"wrong"}`); rr.Code != http.StatusUnauthorized {
+				t.Fatalf("got status %d, want %d", rr.Code, http.StatusUnauthorized)
+			}
+		}
+	}
+
+	// Signing in again with the password in another session does not reset the count of
+	// incorrect codes.
+	signIn(client)
+	supplyWrongCodes(client, maxMFAAttempts-1)
+	otherClient := &mfaTestClient{cookies: map[string]*http.Cookie{}}
+	signIn(otherClient)
+	supplyWrongCodes(otherClient, 1)
+
+	// The pending sign-in was discarded, so even a correct code is rejected.
+	code, err := totp.Code(secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rr := otherClient.post(HandleSignInMFA, fmt.Sprintf(`{"code":%q}`, code)); rr.Code != http.StatusUnauthorized || !strings.Contains(rr.Body.String(), "Sign in again") {
+		t.Fatalf("got %d %q, want 401 asking to sign in again", rr.Code, rr.Body.String())
+	}
+
+	// The user is locked out, in all sessions.
+	for _, c := range []*mfaTestClient{client, otherClient} {
+		signIn(c)
+		if rr := c.post(HandleSignInMFA, fmt.Sprintf(`{"code":%q}`, code)); rr.Code != http.StatusTooManyRequests {
+			t.Fatalf("got %d %q, want 429", rr.Code, rr.Body.String())
+		}
+		if a := c.actor(); a.IsAuthenticated() {
+			t.Fatalf("got authenticated actor %+v", a)
+		}
+	}
+}
+
+func TestMFALockout(t *testing.T) {
+	tests := map[int]time.Duration{
+		1:                     0,
+		maxMFAAttempts - 1:    0,
+		maxMFAAttempts:        mfaLockoutBase,
+		maxMFAAttempts + 1:    0,
+		2 * maxMFAAttempts:    2 * mfaLockoutBase,
+		3 * maxMFAAttempts:    4 * mfaLockoutBase,
+		1000 * maxMFAAttempts: mfaLockoutMax,
+	}
+	for failedAttempts, want := range tests {
+		if got := mfaLockout(failedAttempts); got != want {
+			t.Errorf("%d failed attempts: got lockout %s, want %s", failedAttempts, got, want)
+		}
+	}
+}
+
+func TestMFARequired(t *testing.T) {
+	tests := map[string]struct {
+		requireMFA string
+		siteAdmin  bool
+		want       bool
+	}{
+		"unset":                   {requireMFA: "", want: false},
+		"none":                    {requireMFA: "none", siteAdmin: true, want: false},
+		"site-admins, user":       {requireMFA: "site-admins", want: false},
+		"site-admins, site admin": {requireMFA: "site-admins", siteAdmin: true, want: true},
+		"all":                     {requireMFA: "all", want: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			conf.Mock(&conf.Unified{Critical: schema.CriticalConfiguration{AuthProviders: []schema.AuthProviders{{Builtin: &schema.BuiltinAuthProvider{Type: "builtin", RequireMFA: test.requireMFA}}}}})
+			defer conf.Mock(nil)
+			if got := mfaRequired(&types.User{SiteAdmin: test.siteAdmin}); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
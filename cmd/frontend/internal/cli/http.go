@@ -22,6 +22,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/session"
 	"github.com/sourcegraph/sourcegraph/pkg/actor"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/requestclient"
 	tracepkg "github.com/sourcegraph/sourcegraph/pkg/trace"
 	"github.com/sourcegraph/sourcegraph/pkg/version"
 )
@@ -69,6 +70,7 @@ func newExternalHTTPHandler(ctx context.Context) (http.Handler, error) {
 		h = hooks.PreAuthMiddleware(h)
 	}
 	h = tracepkg.Middleware(h)
+	h = requestclient.HTTPMiddleware(h)
 	h = middleware.SourcegraphComGoGetHandler(h)
 	h = middleware.BlackHole(h)
 	h = secureHeadersMiddleware(h)
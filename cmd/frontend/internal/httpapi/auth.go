@@ -2,6 +2,7 @@ package httpapi
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
@@ -18,6 +19,12 @@ func AccessTokenAuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Add("Vary", "Authorization")
 
+		// 🚨 SECURITY: SCIM requests are authenticated differently (see authenticateSCIMRequest).
+		if isSCIMRequest(r) {
+			authenticateSCIMRequest(next, w, r)
+			return
+		}
+
 		var sudoUser string
 		token := r.URL.Query().Get("token")
 
@@ -60,20 +67,32 @@ func AccessTokenAuthMiddleware(next http.Handler) http.Handler {
 			// Validate access token.
 			//
 			// 🚨 SECURITY: It's important we check for the correct scopes to know what this token
-			// is allowed to do.
-			var requiredScope string
-			if sudoUser == "" {
-				requiredScope = authz.ScopeUserAll
-			} else {
-				requiredScope = authz.ScopeSiteAdminSudo
+			// is allowed to do. Tokens with only fine-grained scopes are accepted by the API (whose
+			// operations check the scope they require, see backend.CheckActorScope) and, if they
+			// grant read access to repositories, by the app (e.g., to fetch raw files).
+			var requiredScopes []string
+			switch {
+			case sudoUser != "":
+				requiredScopes = []string{authz.ScopeSiteAdminSudo}
+			case isAPIRequest(r):
+				requiredScopes = authz.APIScopes
+			default:
+				requiredScopes = []string{authz.ScopeRepoRead}
 			}
-			subjectUserID, err := db.AccessTokens.Lookup(r.Context(), token, requiredScope)
+			subjectUserID, scopes, err := db.AccessTokens.Lookup(r.Context(), token, requiredScopes...)
 			if err != nil {
 				log15.Error("Invalid access token.", "token", token, "err", err)
 				http.Error(w, "Invalid access token.", http.StatusUnauthorized)
 				return
 			}
 
+			// Record the scopes of the token on the actor if they restrict what it may do. Sudo
+			// tokens and tokens with the "user:all" scope may do anything the user may do.
+			var actorScopes []string
+			if sudoUser == "" && !authz.ScopeGrants(scopes, authz.ScopeUserAll) {
+				actorScopes = scopes
+			}
+
 			// Determine the actor's user ID.
 			var actorUserID int32
 			if sudoUser == "" {
@@ -101,13 +120,23 @@ func AccessTokenAuthMiddleware(next http.Handler) http.Handler {
 					http.Error(w, message, http.StatusForbidden)
 					return
 				}
+				// 🚨 SECURITY: Suspended users can't use the site, even via sudo.
+				if !user.State.Active() {
+					http.Error(w, "Unable to sudo to a suspended user.", http.StatusForbidden)
+					return
+				}
 				actorUserID = user.ID
 				log15.Debug("HTTP request used sudo token.", "requestURI", r.URL.RequestURI(), "tokenSubjectUserID", subjectUserID, "actorUserID", actorUserID, "actorUsername", user.Username)
 			}
 
-			r = r.WithContext(actor.WithActor(r.Context(), &actor.Actor{UID: actorUserID}))
+			r = r.WithContext(actor.WithActor(r.Context(), &actor.Actor{UID: actorUserID, Scopes: actorScopes}))
 		}
 
 		next.ServeHTTP(w, r)
 	})
 }
+
+// isAPIRequest reports whether the request is for the HTTP API (as opposed to the app).
+func isAPIRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.URL.Path, "/.api/")
+}
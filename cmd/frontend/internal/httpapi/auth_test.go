@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
 	"testing"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
@@ -69,9 +70,9 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/", nil)
 		req.Header.Set("Authorization", "token badbad")
 		var calledAccessTokensLookup bool
-		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string, requiredScopes []string) (subjectUserID int32, scopes []string, err error) {
 			calledAccessTokensLookup = true
-			return 0, errors.New("x")
+			return 0, nil, errors.New("x")
 		}
 		defer func() { db.Mocks = db.MockStores{} }()
 		checkHTTPResponse(t, req, http.StatusUnauthorized, "Invalid access token.\n")
@@ -85,15 +86,15 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 			req, _ := http.NewRequest("GET", "/", nil)
 			req.Header.Set("Authorization", headerValue)
 			var calledAccessTokensLookup bool
-			db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+			db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string, requiredScopes []string) (subjectUserID int32, scopes []string, err error) {
 				calledAccessTokensLookup = true
 				if want := "abcdef"; tokenHexEncoded != want {
 					t.Errorf("got %q, want %q", tokenHexEncoded, want)
 				}
-				if want := authz.ScopeUserAll; requiredScope != want {
-					t.Errorf("got %q, want %q", requiredScope, want)
+				if want := []string{authz.ScopeRepoRead}; !reflect.DeepEqual(requiredScopes, want) {
+					t.Errorf("got %q, want %q", requiredScopes, want)
 				}
-				return 123, nil
+				return 123, []string{authz.ScopeUserAll}, nil
 			}
 			defer func() { db.Mocks = db.MockStores{} }()
 			checkHTTPResponse(t, req, http.StatusOK, "user 123")
@@ -109,15 +110,15 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req.Header.Set("Authorization", "token abcdef")
 		req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: 456}))
 		var calledAccessTokensLookup bool
-		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string, requiredScopes []string) (subjectUserID int32, scopes []string, err error) {
 			calledAccessTokensLookup = true
 			if want := "abcdef"; tokenHexEncoded != want {
 				t.Errorf("got %q, want %q", tokenHexEncoded, want)
 			}
-			if want := authz.ScopeUserAll; requiredScope != want {
-				t.Errorf("got %q, want %q", requiredScope, want)
+			if want := []string{authz.ScopeRepoRead}; !reflect.DeepEqual(requiredScopes, want) {
+				t.Errorf("got %q, want %q", requiredScopes, want)
 			}
-			return 123, nil
+			return 123, []string{authz.ScopeUserAll}, nil
 		}
 		defer func() { db.Mocks = db.MockStores{} }()
 		checkHTTPResponse(t, req, http.StatusOK, "user 123")
@@ -143,15 +144,15 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 			}
 			req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: 456}))
 			var calledAccessTokensLookup bool
-			db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+			db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string, requiredScopes []string) (subjectUserID int32, scopes []string, err error) {
 				calledAccessTokensLookup = true
 				if want := "abcdef"; tokenHexEncoded != want {
 					t.Errorf("got %q, want %q", tokenHexEncoded, want)
 				}
-				if want := authz.ScopeUserAll; requiredScope != want {
-					t.Errorf("got %q, want %q", requiredScope, want)
+				if want := []string{authz.ScopeRepoRead}; !reflect.DeepEqual(requiredScopes, want) {
+					t.Errorf("got %q, want %q", requiredScopes, want)
 				}
-				return 123, nil
+				return 123, []string{authz.ScopeUserAll}, nil
 			}
 			defer func() { db.Mocks = db.MockStores{} }()
 			checkHTTPResponse(t, req, http.StatusOK, "user 123")
@@ -161,19 +162,54 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		})
 	}
 
+	// 🚨 SECURITY: test necessary to ensure security
+	t.Run("valid non-sudo token with fine-grained scopes", func(t *testing.T) {
+		var gotActor *actor.Actor
+		handler := AccessTokenAuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotActor = actor.FromContext(r.Context())
+		}))
+		tests := map[string]struct {
+			tokenScopes    []string
+			wantActorScope []string
+		}{
+			"restricted":   {tokenScopes: []string{authz.ScopeRepoRead, authz.ScopeSearchRead}, wantActorScope: []string{authz.ScopeRepoRead, authz.ScopeSearchRead}},
+			"unrestricted": {tokenScopes: []string{authz.ScopeUserAll, authz.ScopeRepoRead}, wantActorScope: nil},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				req, _ := http.NewRequest("POST", "/.api/graphql", nil)
+				req.Header.Set("Authorization", "token abcdef")
+				db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string, requiredScopes []string) (subjectUserID int32, scopes []string, err error) {
+					if want := authz.APIScopes; !reflect.DeepEqual(requiredScopes, want) {
+						t.Errorf("got %q, want %q", requiredScopes, want)
+					}
+					return 123, test.tokenScopes, nil
+				}
+				defer func() { db.Mocks = db.MockStores{} }()
+				handler.ServeHTTP(httptest.NewRecorder(), req)
+				if gotActor == nil || gotActor.UID != 123 {
+					t.Fatalf("got actor %+v, want UID 123", gotActor)
+				}
+				if !reflect.DeepEqual(gotActor.Scopes, test.wantActorScope) {
+					t.Errorf("got actor scopes %q, want %q", gotActor.Scopes, test.wantActorScope)
+				}
+			})
+		}
+	})
+
 	t.Run("valid sudo token", func(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/", nil)
 		req.Header.Set("Authorization", `token-sudo token="abcdef",user="alice"`)
 		var calledAccessTokensLookup bool
-		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string, requiredScopes []string) (subjectUserID int32, scopes []string, err error) {
 			calledAccessTokensLookup = true
 			if want := "abcdef"; tokenHexEncoded != want {
 				t.Errorf("got %q, want %q", tokenHexEncoded, want)
 			}
-			if want := authz.ScopeSiteAdminSudo; requiredScope != want {
-				t.Errorf("got %q, want %q", requiredScope, want)
+			if want := []string{authz.ScopeSiteAdminSudo}; !reflect.DeepEqual(requiredScopes, want) {
+				t.Errorf("got %q, want %q", requiredScopes, want)
 			}
-			return 123, nil
+			return 123, []string{authz.ScopeSiteAdminSudo}, nil
 		}
 		var calledUsersGetByID bool
 		db.Mocks.Users.GetByID = func(ctx context.Context, userID int32) (*types.User, error) {
@@ -210,15 +246,15 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/", nil)
 		req.Header.Set("Authorization", `token-sudo token="abcdef",user="alice"`)
 		var calledAccessTokensLookup bool
-		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string, requiredScopes []string) (subjectUserID int32, scopes []string, err error) {
 			calledAccessTokensLookup = true
 			if want := "abcdef"; tokenHexEncoded != want {
 				t.Errorf("got %q, want %q", tokenHexEncoded, want)
 			}
-			if want := authz.ScopeSiteAdminSudo; requiredScope != want {
-				t.Errorf("got %q, want %q", requiredScope, want)
+			if want := []string{authz.ScopeSiteAdminSudo}; !reflect.DeepEqual(requiredScopes, want) {
+				t.Errorf("got %q, want %q", requiredScopes, want)
 			}
-			return 123, nil
+			return 123, []string{authz.ScopeSiteAdminSudo}, nil
 		}
 		var calledUsersGetByID bool
 		db.Mocks.Users.GetByID = func(ctx context.Context, userID int32) (*types.User, error) {
@@ -238,19 +274,35 @@ func TestAccessTokenAuthMiddleware(t *testing.T) {
 		}
 	})
 
+	t.Run("valid sudo token, suspended sudo user", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", `token-sudo token="abcdef",user="alice"`)
+		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string, requiredScopes []string) (subjectUserID int32, scopes []string, err error) {
+			return 123, []string{authz.ScopeSiteAdminSudo}, nil
+		}
+		db.Mocks.Users.GetByID = func(ctx context.Context, userID int32) (*types.User, error) {
+			return &types.User{ID: userID, SiteAdmin: true}, nil
+		}
+		db.Mocks.Users.GetByUsername = func(ctx context.Context, username string) (*types.User, error) {
+			return &types.User{ID: 456, State: types.UserStateSuspended}, nil
+		}
+		defer func() { db.Mocks = db.MockStores{} }()
+		checkHTTPResponse(t, req, http.StatusForbidden, "Unable to sudo to a suspended user.\n")
+	})
+
 	t.Run("valid sudo token, invalid sudo user", func(t *testing.T) {
 		req, _ := http.NewRequest("GET", "/", nil)
 		req.Header.Set("Authorization", `token-sudo token="abcdef",user="doesntexist"`)
 		var calledAccessTokensLookup bool
-		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded, requiredScope string) (subjectUserID int32, err error) {
+		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string, requiredScopes []string) (subjectUserID int32, scopes []string, err error) {
 			calledAccessTokensLookup = true
 			if want := "abcdef"; tokenHexEncoded != want {
 				t.Errorf("got %q, want %q", tokenHexEncoded, want)
 			}
-			if want := authz.ScopeSiteAdminSudo; requiredScope != want {
-				t.Errorf("got %q, want %q", requiredScope, want)
+			if want := []string{authz.ScopeSiteAdminSudo}; !reflect.DeepEqual(requiredScopes, want) {
+				t.Errorf("got %q, want %q", requiredScopes, want)
 			}
-			return 123, nil
+			return 123, []string{authz.ScopeSiteAdminSudo}, nil
 		}
 		var calledUsersGetByID bool
 		db.Mocks.Users.GetByID = func(ctx context.Context, userID int32) (*types.User, error) {
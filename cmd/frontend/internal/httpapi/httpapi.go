@@ -12,6 +12,8 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/gorilla/schema"
 	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/pkg/updatecheck"
 	apirouter "github.com/sourcegraph/sourcegraph/cmd/frontend/internal/httpapi/router"
@@ -37,11 +39,11 @@ func NewHandler(m *mux.Router) http.Handler {
 	m.StrictSlash(true)
 
 	// Set handlers for the installed routes.
-	m.Get(apirouter.RepoShield).Handler(trace.TraceRoute(handler(serveRepoShield)))
+	m.Get(apirouter.RepoShield).Handler(trace.TraceRoute(scopeHandler(authz.ScopeRepoRead, handler(serveRepoShield))))
 
-	m.Get(apirouter.RepoRefresh).Handler(trace.TraceRoute(handler(serveRepoRefresh)))
+	m.Get(apirouter.RepoRefresh).Handler(trace.TraceRoute(scopeHandler(authz.ScopeRepoWrite, handler(serveRepoRefresh))))
 
-	m.Get(apirouter.Telemetry).Handler(trace.TraceRoute(telemetryHandler))
+	m.Get(apirouter.Telemetry).Handler(trace.TraceRoute(scopeHandler(authz.ScopeUserWrite, telemetryHandler)))
 
 	if envvar.SourcegraphDotComMode() {
 		m.Path("/updates").Methods("GET").Name("updatecheck").Handler(trace.TraceRoute(http.HandlerFunc(updatecheck.Handler)))
@@ -54,11 +56,25 @@ func NewHandler(m *mux.Router) http.Handler {
 		log15.Error("skipping initialization of the LSIF HTTP API because the environment variable LSIF_SERVER_URL is not a valid URL", "parse_error", err, "value", lsifServerURLFromEnv)
 	} else {
 		proxy := httputil.NewSingleHostReverseProxy(lsifServerURL)
-		m.Get(apirouter.LSIFUpload).Handler(trace.TraceRoute(http.HandlerFunc(lsifUploadProxyHandler(proxy))))
-		m.Get(apirouter.LSIF).Handler(trace.TraceRoute(http.HandlerFunc(lsifProxyHandler(proxy))))
+		m.Get(apirouter.LSIFUpload).Handler(trace.TraceRoute(scopeHandler(authz.ScopeRepoWrite, http.HandlerFunc(lsifUploadProxyHandler(proxy)))))
+		m.Get(apirouter.LSIF).Handler(trace.TraceRoute(scopeHandler(authz.ScopeRepoRead, http.HandlerFunc(lsifProxyHandler(proxy)))))
 	}
 
-	m.Get(apirouter.Registry).Handler(trace.TraceRoute(handler(registry.HandleRegistry)))
+	m.Get(apirouter.Registry).Handler(trace.TraceRoute(scopeHandler(authz.ScopeSettingsRead, handler(registry.HandleRegistry))))
+
+	m.Get(apirouter.SCIMServiceProviderConfig).Handler(trace.TraceRoute(scimHandler(serveSCIMServiceProviderConfig)))
+	m.Get(apirouter.SCIMUsers).Handler(trace.TraceRoute(scimHandler(serveSCIMUsersList)))
+	m.Get(apirouter.SCIMUsersCreate).Handler(trace.TraceRoute(scimHandler(serveSCIMUsersCreate)))
+	m.Get(apirouter.SCIMUser).Handler(trace.TraceRoute(scimHandler(serveSCIMUsersGet)))
+	m.Get(apirouter.SCIMUserReplace).Handler(trace.TraceRoute(scimHandler(serveSCIMUsersReplace)))
+	m.Get(apirouter.SCIMUserPatch).Handler(trace.TraceRoute(scimHandler(serveSCIMUsersPatch)))
+	m.Get(apirouter.SCIMUserDelete).Handler(trace.TraceRoute(scimHandler(serveSCIMUsersDelete)))
+	m.Get(apirouter.SCIMGroups).Handler(trace.TraceRoute(scimHandler(serveSCIMGroupsList)))
+	m.Get(apirouter.SCIMGroupsCreate).Handler(trace.TraceRoute(scimHandler(serveSCIMGroupsCreate)))
+	m.Get(apirouter.SCIMGroup).Handler(trace.TraceRoute(scimHandler(serveSCIMGroupsGet)))
+	m.Get(apirouter.SCIMGroupReplace).Handler(trace.TraceRoute(scimHandler(serveSCIMGroupsReplace)))
+	m.Get(apirouter.SCIMGroupPatch).Handler(trace.TraceRoute(scimHandler(serveSCIMGroupsPatch)))
+	m.Get(apirouter.SCIMGroupDelete).Handler(trace.TraceRoute(scimHandler(serveSCIMGroupsDelete)))
 
 	m.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		log.Printf("API no route: %s %s from %s", r.Method, r.URL, r.Referer())
@@ -85,14 +101,24 @@ func NewInternalHandler(m *mux.Router) http.Handler {
 	m.Get(apirouter.PhabricatorRepoCreate).Handler(trace.TraceRoute(handler(servePhabricatorRepoCreate)))
 	m.Get(apirouter.ReposCreateIfNotExists).Handler(trace.TraceRoute(handler(serveReposCreateIfNotExists)))
 	m.Get(apirouter.ReposUpdateMetadata).Handler(trace.TraceRoute(handler(serveReposUpdateMetadata)))
+	m.Get(apirouter.ReposRecordUpdateAttempt).Handler(trace.TraceRoute(handler(serveReposRecordUpdateAttempt)))
 	m.Get(apirouter.ReposList).Handler(trace.TraceRoute(handler(serveReposList)))
 	m.Get(apirouter.ReposListEnabled).Handler(trace.TraceRoute(handler(serveReposListEnabled)))
+	m.Get(apirouter.ReposFilterReadable).Handler(trace.TraceRoute(handler(serveReposFilterReadable)))
 	m.Get(apirouter.ReposGetByName).Handler(trace.TraceRoute(handler(serveReposGetByName)))
 	m.Get(apirouter.SettingsGetForSubject).Handler(trace.TraceRoute(handler(serveSettingsGetForSubject)))
 	m.Get(apirouter.SavedQueriesListAll).Handler(trace.TraceRoute(handler(serveSavedQueriesListAll)))
 	m.Get(apirouter.SavedQueriesGetInfo).Handler(trace.TraceRoute(handler(serveSavedQueriesGetInfo)))
 	m.Get(apirouter.SavedQueriesSetInfo).Handler(trace.TraceRoute(handler(serveSavedQueriesSetInfo)))
 	m.Get(apirouter.SavedQueriesDeleteInfo).Handler(trace.TraceRoute(handler(serveSavedQueriesDeleteInfo)))
+	m.Get(apirouter.SavedQueriesGetResultFingerprints).Handler(trace.TraceRoute(handler(serveSavedQueriesGetResultFingerprints)))
+	m.Get(apirouter.SavedQueriesSetResultFingerprints).Handler(trace.TraceRoute(handler(serveSavedQueriesSetResultFingerprints)))
+	m.Get(apirouter.SavedQueriesCreateWebhookDeliveries).Handler(trace.TraceRoute(handler(serveSavedQueriesCreateWebhookDeliveries)))
+	m.Get(apirouter.SavedQueriesListDueWebhookDeliveries).Handler(trace.TraceRoute(handler(serveSavedQueriesListDueWebhookDeliveries)))
+	m.Get(apirouter.SavedQueriesRecordWebhookDeliveryAttempt).Handler(trace.TraceRoute(handler(serveSavedQueriesRecordWebhookDeliveryAttempt)))
+	m.Get(apirouter.SavedQueriesListSubscriptions).Handler(trace.TraceRoute(handler(serveSavedQueriesListSubscriptions)))
+	m.Get(apirouter.SavedQueriesRecordRun).Handler(trace.TraceRoute(handler(serveSavedQueriesRecordRun)))
+	m.Get(apirouter.SavedQueriesListRunStates).Handler(trace.TraceRoute(handler(serveSavedQueriesListRunStates)))
 	m.Get(apirouter.OrgsListUsers).Handler(trace.TraceRoute(handler(serveOrgsListUsers)))
 	m.Get(apirouter.OrgsGetByName).Handler(trace.TraceRoute(handler(serveOrgsGetByName)))
 	m.Get(apirouter.UsersGetByUsername).Handler(trace.TraceRoute(handler(serveUsersGetByUsername)))
@@ -127,6 +153,21 @@ func handler(h func(http.ResponseWriter, *http.Request) error) http.Handler {
 	}
 }
 
+// scopeHandler returns a handler that responds with an error if the request was authenticated with
+// an access token that does not grant the scope (see backend.CheckActorScope).
+//
+// 🚨 SECURITY: All API endpoints that do not check the scope themselves (as the GraphQL API does)
+// must use it.
+func scopeHandler(scope string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := backend.CheckActorScope(r.Context(), scope); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
 var schemaDecoder = schema.NewDecoder()
 
 func init() {
@@ -1,6 +1,7 @@
 package httpapi
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -13,8 +14,10 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
 	"github.com/sourcegraph/sourcegraph/pkg/gitserver"
 	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
 	"github.com/sourcegraph/sourcegraph/pkg/txemail"
@@ -78,6 +81,26 @@ func serveReposUpdateMetadata(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+func serveReposRecordUpdateAttempt(w http.ResponseWriter, r *http.Request) error {
+	var req api.ReposRecordUpdateAttemptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return err
+	}
+	repo, err := db.Repos.GetByName(r.Context(), req.Repo)
+	if errcode.IsNotFound(err) {
+		// The repository was deleted (or never added), so there is no history to record
+		// the attempt in.
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "Repos.GetByName failed")
+	}
+	if err := db.RepoUpdateAttempts.Record(r.Context(), repo.ID, &req.Attempt); err != nil {
+		return errors.Wrap(err, "RepoUpdateAttempts.Record failed")
+	}
+	return nil
+}
+
 func servePhabricatorRepoCreate(w http.ResponseWriter, r *http.Request) error {
 	var repo api.PhabricatorRepoCreateRequest
 	err := json.NewDecoder(r.Body).Decode(&repo)
@@ -231,6 +254,28 @@ func serveReposListEnabled(w http.ResponseWriter, r *http.Request) error {
 	return json.NewEncoder(w).Encode(names)
 }
 
+func serveReposFilterReadable(w http.ResponseWriter, r *http.Request) error {
+	var args api.ReposFilterReadableArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+
+	// 🚨 SECURITY: Look up the repositories as the user, so that only the repositories that the
+	// user is allowed to read are found.
+	ctx := actor.WithActor(r.Context(), &actor.Actor{UID: args.UserID})
+	readable := []api.RepoName{}
+	for _, name := range args.Repos {
+		if _, err := db.Repos.GetByName(ctx, name); err != nil {
+			if errcode.IsNotFound(err) {
+				continue
+			}
+			return errors.Wrap(err, "Repos.GetByName")
+		}
+		readable = append(readable, name)
+	}
+	return json.NewEncoder(w).Encode(readable)
+}
+
 func serveSavedQueriesListAll(w http.ResponseWriter, r *http.Request) error {
 	// List settings for all users, orgs, etc.
 	settings, err := db.SavedSearches.ListAll(r.Context())
@@ -311,6 +356,203 @@ func serveSavedQueriesDeleteInfo(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+func serveSavedQueriesGetResultFingerprints(w http.ResponseWriter, r *http.Request) error {
+	var savedSearchID int32
+	err := json.NewDecoder(r.Body).Decode(&savedSearchID)
+	if err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+	fingerprints, err := db.QueryRunnerState.GetResultFingerprints(r.Context(), savedSearchID)
+	if err != nil {
+		return errors.Wrap(err, "QueryRunnerState.GetResultFingerprints")
+	}
+	if err := json.NewEncoder(w).Encode(fingerprints); err != nil {
+		return errors.Wrap(err, "Encode")
+	}
+	return nil
+}
+
+func serveSavedQueriesSetResultFingerprints(w http.ResponseWriter, r *http.Request) error {
+	var req api.SavedQueryResultFingerprints
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+	err = db.QueryRunnerState.SetResultFingerprints(r.Context(), req.SavedSearchID, req.Fingerprints)
+	if err != nil {
+		return errors.Wrap(err, "QueryRunnerState.SetResultFingerprints")
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+	return nil
+}
+
+func serveSavedQueriesCreateWebhookDeliveries(w http.ResponseWriter, r *http.Request) error {
+	var args api.SavedQueryWebhookDeliveriesCreateArgs
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+	n, err := db.SavedSearchWebhooks.CreateDeliveries(r.Context(), args.SavedSearchID, args.Event, args.Payload)
+	if err != nil {
+		return errors.Wrap(err, "SavedSearchWebhooks.CreateDeliveries")
+	}
+	if err := json.NewEncoder(w).Encode(n); err != nil {
+		return errors.Wrap(err, "Encode")
+	}
+	return nil
+}
+
+// maxDueWebhookDeliveries is the maximum number of due saved search webhook deliveries that are
+// listed at once.
+const maxDueWebhookDeliveries = 100
+
+func serveSavedQueriesListDueWebhookDeliveries(w http.ResponseWriter, r *http.Request) error {
+	deliveries, err := db.SavedSearchWebhooks.ListDueDeliveries(r.Context(), maxDueWebhookDeliveries)
+	if err != nil {
+		return errors.Wrap(err, "SavedSearchWebhooks.ListDueDeliveries")
+	}
+
+	webhooks := map[int32]*db.SavedSearchWebhook{}
+	result := make([]*api.SavedQueryWebhookDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		webhook, ok := webhooks[d.WebhookID]
+		if !ok {
+			webhook, err = db.SavedSearchWebhooks.GetByID(r.Context(), d.WebhookID)
+			if err != nil {
+				return errors.Wrap(err, "SavedSearchWebhooks.GetByID")
+			}
+			webhooks[d.WebhookID] = webhook
+		}
+		result = append(result, &api.SavedQueryWebhookDelivery{
+			ID:       d.ID,
+			URL:      webhook.URL,
+			Secret:   webhook.Secret,
+			Event:    d.Event,
+			Payload:  d.Payload,
+			Attempts: d.Attempts,
+		})
+	}
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		return errors.Wrap(err, "Encode")
+	}
+	return nil
+}
+
+func serveSavedQueriesRecordWebhookDeliveryAttempt(w http.ResponseWriter, r *http.Request) error {
+	var attempt api.SavedQueryWebhookDeliveryAttempt
+	if err := json.NewDecoder(r.Body).Decode(&attempt); err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+	err := db.SavedSearchWebhooks.RecordDeliveryAttempt(r.Context(), attempt.DeliveryID, db.SavedSearchWebhookDeliveryAttempt{
+		Success:            attempt.Success,
+		ResponseStatusCode: attempt.ResponseStatusCode,
+		Error:              attempt.Error,
+		NextAttemptAt:      attempt.NextAttemptAt,
+	})
+	if err != nil {
+		return errors.Wrap(err, "SavedSearchWebhooks.RecordDeliveryAttempt")
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+	return nil
+}
+
+func serveSavedQueriesListSubscriptions(w http.ResponseWriter, r *http.Request) error {
+	var savedSearchID int32
+	if err := json.NewDecoder(r.Body).Decode(&savedSearchID); err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+	savedSearch, err := db.SavedSearches.GetByID(r.Context(), savedSearchID)
+	if err != nil {
+		return errors.Wrap(err, "SavedSearches.GetByID")
+	}
+	subscriptions, err := db.SavedSearchSubscriptions.ListBySavedSearch(r.Context(), savedSearchID)
+	if err != nil {
+		return errors.Wrap(err, "SavedSearchSubscriptions.ListBySavedSearch")
+	}
+	result := []*api.SavedQuerySubscription{}
+	for _, s := range subscriptions {
+		// 🚨 SECURITY: Users subscribe while they may view the saved search, but they may lose
+		// access afterwards (e.g., by leaving the org that owns it). Omit their subscriptions, so
+		// that they're no longer notified of its results.
+		ok, err := savedSearchSubscriberHasAccess(r.Context(), s.UserID, savedSearch)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		result = append(result, &api.SavedQuerySubscription{
+			UserID:          s.UserID,
+			Channel:         s.Channel,
+			SlackWebhookURL: s.SlackWebhookURL,
+		})
+	}
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		return errors.Wrap(err, "Encode")
+	}
+	return nil
+}
+
+// savedSearchSubscriberHasAccess reports whether the user may view the saved search, i.e., whether
+// they are its owner, a member of the org that owns it, or a site admin.
+func savedSearchSubscriberHasAccess(ctx context.Context, userID int32, savedSearch *api.SavedQuerySpecAndConfig) (bool, error) {
+	switch {
+	case savedSearch.Config.UserID != nil && *savedSearch.Config.UserID == userID:
+		return true, nil
+	case savedSearch.Config.OrgID != nil:
+		_, err := db.OrgMembers.GetByOrgIDAndUserID(ctx, *savedSearch.Config.OrgID, userID)
+		if err == nil {
+			return true, nil
+		}
+		if !errcode.IsNotFound(err) {
+			return false, errors.Wrap(err, "OrgMembers.GetByOrgIDAndUserID")
+		}
+	}
+	user, err := db.Users.GetByID(ctx, userID)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "Users.GetByID")
+	}
+	return user.SiteAdmin, nil
+}
+
+func serveSavedQueriesRecordRun(w http.ResponseWriter, r *http.Request) error {
+	var run api.SavedQueryRun
+	if err := json.NewDecoder(r.Body).Decode(&run); err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+	if err := db.SavedSearchRuns.Create(r.Context(), &db.SavedSearchRun{
+		SavedSearchID:  run.SavedSearchID,
+		StartedAt:      run.StartedAt,
+		Duration:       run.Duration,
+		ResultCount:    int32(run.ResultCount),
+		NewResultCount: int32(run.NewResultCount),
+		Error:          run.Error,
+	}); err != nil {
+		return errors.Wrap(err, "SavedSearchRuns.Create")
+	}
+	if err := db.SavedSearches.UpdateRunState(r.Context(), &run); err != nil {
+		return errors.Wrap(err, "SavedSearches.UpdateRunState")
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+	return nil
+}
+
+func serveSavedQueriesListRunStates(w http.ResponseWriter, r *http.Request) error {
+	states, err := db.SavedSearches.ListRunStates(r.Context())
+	if err != nil {
+		return errors.Wrap(err, "SavedSearches.ListRunStates")
+	}
+	if err := json.NewEncoder(w).Encode(states); err != nil {
+		return errors.Wrap(err, "Encode")
+	}
+	return nil
+}
+
 func serveSettingsGetForSubject(w http.ResponseWriter, r *http.Request) error {
 	var subject api.SettingsSubject
 	if err := json.NewDecoder(r.Body).Decode(&subject); err != nil {
@@ -0,0 +1,56 @@
+package httpapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+func TestSavedSearchSubscriberHasAccess(t *testing.T) {
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	// User 1 owns a saved search, users 1 and 2 are members of org 7, and user 3 is a site admin.
+	db.Mocks.OrgMembers.GetByOrgIDAndUserID = func(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+		if orgID == 7 && (userID == 1 || userID == 2) {
+			return &types.OrgMembership{OrgID: orgID, UserID: userID}, nil
+		}
+		return nil, &db.ErrOrgMemberNotFound{}
+	}
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		if id > 3 {
+			return nil, db.NewUserNotFoundError(id)
+		}
+		return &types.User{ID: id, SiteAdmin: id == 3}, nil
+	}
+
+	userID, orgID := int32(1), int32(7)
+	userSavedSearch := &api.SavedQuerySpecAndConfig{Config: api.ConfigSavedQuery{UserID: &userID}}
+	orgSavedSearch := &api.SavedQuerySpecAndConfig{Config: api.ConfigSavedQuery{OrgID: &orgID}}
+	tests := []struct {
+		name        string
+		userID      int32
+		savedSearch *api.SavedQuerySpecAndConfig
+		want        bool
+	}{
+		{name: "owner", userID: 1, savedSearch: userSavedSearch, want: true},
+		{name: "other user", userID: 2, savedSearch: userSavedSearch, want: false},
+		{name: "site admin", userID: 3, savedSearch: userSavedSearch, want: true},
+		{name: "org member", userID: 2, savedSearch: orgSavedSearch, want: true},
+		{name: "site admin not in org", userID: 3, savedSearch: orgSavedSearch, want: true},
+		{name: "deleted user", userID: 4, savedSearch: orgSavedSearch, want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := savedSearchSubscriberHasAccess(context.Background(), test.userID, test.savedSearch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
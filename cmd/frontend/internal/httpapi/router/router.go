@@ -17,6 +17,31 @@ const (
 	RepoRefresh = "repo.refresh"
 	Telemetry   = "telemetry"
 
+	SCIMServiceProviderConfig = "scim.service-provider-config"
+	SCIMUsers                 = "scim.users"
+	SCIMUsersCreate           = "scim.users.create"
+	SCIMUser                  = "scim.user"
+	SCIMUserReplace           = "scim.user.replace"
+	SCIMUserPatch             = "scim.user.patch"
+	SCIMUserDelete            = "scim.user.delete"
+	SCIMGroups                = "scim.groups"
+	SCIMGroupsCreate          = "scim.groups.create"
+	SCIMGroup                 = "scim.group"
+	SCIMGroupReplace          = "scim.group.replace"
+	SCIMGroupPatch            = "scim.group.patch"
+	SCIMGroupDelete           = "scim.group.delete"
+
+	SavedQueriesGetResultFingerprints        = "internal.saved-queries.get-result-fingerprints"
+	SavedQueriesSetResultFingerprints        = "internal.saved-queries.set-result-fingerprints"
+	SavedQueriesCreateWebhookDeliveries      = "internal.saved-queries.webhook-deliveries.create"
+	SavedQueriesListDueWebhookDeliveries     = "internal.saved-queries.webhook-deliveries.list-due"
+	SavedQueriesRecordWebhookDeliveryAttempt = "internal.saved-queries.webhook-deliveries.record-attempt"
+	SavedQueriesListSubscriptions            = "internal.saved-queries.list-subscriptions"
+	SavedQueriesRecordRun                    = "internal.saved-queries.record-run"
+	SavedQueriesListRunStates                = "internal.saved-queries.list-run-states"
+
+	ReposRecordUpdateAttempt = "internal.repos.record-update-attempt"
+
 	SavedQueriesListAll    = "internal.saved-queries.list-all"
 	SavedQueriesGetInfo    = "internal.saved-queries.get-info"
 	SavedQueriesSetInfo    = "internal.saved-queries.set-info"
@@ -39,6 +64,7 @@ const (
 	ReposInventory         = "internal.repos.inventory"
 	ReposList              = "internal.repos.list"
 	ReposListEnabled       = "internal.repos.list-enabled"
+	ReposFilterReadable    = "internal.repos.filter-readable"
 	ReposUpdateMetadata    = "internal.repos.update-metadata"
 	Configuration          = "internal.configuration"
 	SearchConfiguration    = "internal.search-configuration"
@@ -60,6 +86,7 @@ func New(base *mux.Router) *mux.Router {
 	addTelemetryRoute(base)
 	base.Path("/lsif/upload").Methods("POST").Name(LSIFUpload)
 	base.Path("/lsif/{rest:.*}").Methods("POST").Name(LSIF)
+	addSCIMRoutes(base)
 
 	// repo contains routes that are NOT specific to a revision. In these routes, the URL may not contain a revspec after the repo (that is, no "github.com/foo/bar@myrevspec").
 	repoPath := `/repos/` + routevar.Repo
@@ -73,6 +100,24 @@ func New(base *mux.Router) *mux.Router {
 	return base
 }
 
+// addSCIMRoutes adds the routes of the SCIM 2.0 API (https://tools.ietf.org/html/rfc7644).
+func addSCIMRoutes(base *mux.Router) {
+	scim := base.PathPrefix("/scim/v2").Subrouter()
+	scim.Path("/ServiceProviderConfig").Methods("GET").Name(SCIMServiceProviderConfig)
+	scim.Path("/Users").Methods("GET").Name(SCIMUsers)
+	scim.Path("/Users").Methods("POST").Name(SCIMUsersCreate)
+	scim.Path("/Users/{ID}").Methods("GET").Name(SCIMUser)
+	scim.Path("/Users/{ID}").Methods("PUT").Name(SCIMUserReplace)
+	scim.Path("/Users/{ID}").Methods("PATCH").Name(SCIMUserPatch)
+	scim.Path("/Users/{ID}").Methods("DELETE").Name(SCIMUserDelete)
+	scim.Path("/Groups").Methods("GET").Name(SCIMGroups)
+	scim.Path("/Groups").Methods("POST").Name(SCIMGroupsCreate)
+	scim.Path("/Groups/{ID}").Methods("GET").Name(SCIMGroup)
+	scim.Path("/Groups/{ID}").Methods("PUT").Name(SCIMGroupReplace)
+	scim.Path("/Groups/{ID}").Methods("PATCH").Name(SCIMGroupPatch)
+	scim.Path("/Groups/{ID}").Methods("DELETE").Name(SCIMGroupDelete)
+}
+
 // NewInternal creates a new API router for internal endpoints.
 func NewInternal(base *mux.Router) *mux.Router {
 	if base == nil {
@@ -85,6 +130,14 @@ func NewInternal(base *mux.Router) *mux.Router {
 	base.Path("/saved-queries/get-info").Methods("POST").Name(SavedQueriesGetInfo)
 	base.Path("/saved-queries/set-info").Methods("POST").Name(SavedQueriesSetInfo)
 	base.Path("/saved-queries/delete-info").Methods("POST").Name(SavedQueriesDeleteInfo)
+	base.Path("/saved-queries/get-result-fingerprints").Methods("POST").Name(SavedQueriesGetResultFingerprints)
+	base.Path("/saved-queries/set-result-fingerprints").Methods("POST").Name(SavedQueriesSetResultFingerprints)
+	base.Path("/saved-queries/webhook-deliveries/create").Methods("POST").Name(SavedQueriesCreateWebhookDeliveries)
+	base.Path("/saved-queries/webhook-deliveries/list-due").Methods("POST").Name(SavedQueriesListDueWebhookDeliveries)
+	base.Path("/saved-queries/webhook-deliveries/record-attempt").Methods("POST").Name(SavedQueriesRecordWebhookDeliveryAttempt)
+	base.Path("/saved-queries/list-subscriptions").Methods("POST").Name(SavedQueriesListSubscriptions)
+	base.Path("/saved-queries/record-run").Methods("POST").Name(SavedQueriesRecordRun)
+	base.Path("/saved-queries/list-run-states").Methods("POST").Name(SavedQueriesListRunStates)
 	base.Path("/settings/get-for-subject").Methods("POST").Name(SettingsGetForSubject)
 	base.Path("/orgs/list-users").Methods("POST").Name(OrgsListUsers)
 	base.Path("/orgs/get-by-name").Methods("POST").Name(OrgsGetByName)
@@ -104,7 +157,9 @@ func NewInternal(base *mux.Router) *mux.Router {
 	base.Path("/repos/inventory").Methods("POST").Name(ReposInventory)
 	base.Path("/repos/list").Methods("POST").Name(ReposList)
 	base.Path("/repos/list-enabled").Methods("POST").Name(ReposListEnabled)
+	base.Path("/repos/filter-readable").Methods("POST").Name(ReposFilterReadable)
 	base.Path("/repos/update-metadata").Methods("POST").Name(ReposUpdateMetadata)
+	base.Path("/repos/record-update-attempt").Methods("POST").Name(ReposRecordUpdateAttempt)
 	base.Path("/repos/{RepoName:.*}").Methods("POST").Name(ReposGetByName)
 	base.Path("/configuration").Methods("POST").Name(Configuration)
 	base.Path("/search/configuration").Methods("GET").Name(SearchConfiguration)
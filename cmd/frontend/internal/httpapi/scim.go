@@ -0,0 +1,369 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/handlerutil"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// The SCIM 2.0 API (https://tools.ietf.org/html/rfc7644) lets an identity provider provision users
+// and map its groups to organizations. It is served under /.api/scim/v2 and may only be used with
+// an access token that has the "site-admin:scim" scope and whose subject is a site admin.
+
+const (
+	scimContentType = "application/scim+json"
+
+	scimSchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimSchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimSchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimSchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	scimSchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+
+	scimDefaultCount = 100
+	scimMaxCount     = 1000
+)
+
+// isSCIMRequest reports whether the request is for the SCIM API.
+func isSCIMRequest(r *http.Request) bool {
+	return strings.HasPrefix(r.URL.Path, "/.api/scim/")
+}
+
+// scimSubjectKey is the context key for the ID of the user who authenticated a SCIM request.
+type scimSubjectKey struct{}
+
+// authenticateSCIMRequest authenticates a SCIM request with the access token in its
+// "Authorization" header. Unlike other API requests, SCIM requests must be authenticated: they are
+// rejected if the token is missing, invalid, lacks the "site-admin:scim" scope or belongs to a user
+// who is no longer a site admin.
+//
+// Identity providers send the token with the "Bearer" scheme, so it is accepted in addition to the
+// "token" scheme.
+func authenticateSCIMRequest(next http.Handler, w http.ResponseWriter, r *http.Request) {
+	var token string
+	if parts := strings.SplitN(r.Header.Get("Authorization"), " ", 2); len(parts) == 2 {
+		switch strings.ToLower(parts[0]) {
+		case "bearer", authz.SchemeToken:
+			token = strings.TrimSpace(parts[1])
+		}
+	}
+	if token == "" {
+		writeSCIMError(w, &scimError{Status: http.StatusUnauthorized, Detail: "An access token is required."})
+		return
+	}
+
+	if !(conf.AccessTokensAllow() == conf.AccessTokensAll || conf.AccessTokensAllow() == conf.AccessTokensAdmin) {
+		writeSCIMError(w, &scimError{Status: http.StatusUnauthorized, Detail: "Access token authorization is disabled."})
+		return
+	}
+
+	// 🚨 SECURITY: The token must have been created specifically for SCIM provisioning.
+	subjectUserID, _, err := db.AccessTokens.Lookup(r.Context(), token, authz.ScopeSiteAdminSCIM)
+	if err != nil {
+		log15.Error("Invalid SCIM access token.", "err", err)
+		writeSCIMError(w, &scimError{Status: http.StatusUnauthorized, Detail: "Invalid access token."})
+		return
+	}
+
+	// 🚨 SECURITY: Confirm that the token's subject is still a site admin, to prevent users from
+	// retaining provisioning privileges after being demoted.
+	if err := backend.CheckUserIsSiteAdmin(r.Context(), subjectUserID); err != nil {
+		log15.Error("SCIM access token's subject is not a site admin.", "subjectUserID", subjectUserID, "err", err)
+		writeSCIMError(w, &scimError{Status: http.StatusForbidden, Detail: "The subject user of a SCIM access token must be a site admin."})
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), scimSubjectKey{}, subjectUserID)
+	next.ServeHTTP(w, r.WithContext(actor.WithActor(ctx, &actor.Actor{UID: subjectUserID})))
+}
+
+// checkSCIMActor returns an error unless the actor was authenticated by authenticateSCIMRequest.
+func checkSCIMActor(ctx context.Context) error {
+	subjectUserID, ok := ctx.Value(scimSubjectKey{}).(int32)
+	a := actor.FromContext(ctx)
+	if !ok || a.FromSessionCookie || a.UID != subjectUserID {
+		return &scimError{Status: http.StatusUnauthorized, Detail: "A SCIM access token is required."}
+	}
+	return nil
+}
+
+// scimHandler is a wrapper func for SCIM API handlers.
+func scimHandler(h func(http.ResponseWriter, *http.Request) error) http.Handler {
+	return handlerutil.HandlerWithErrorReturn{
+		Handler: func(w http.ResponseWriter, r *http.Request) error {
+			// 🚨 SECURITY: Other middleware (such as the session cookie middleware) may have
+			// replaced the actor, so check again that the request was authenticated with a SCIM
+			// access token.
+			if err := checkSCIMActor(r.Context()); err != nil {
+				return err
+			}
+			w.Header().Set("Content-Type", scimContentType)
+			return h(w, r)
+		},
+		Error: handleSCIMError,
+	}
+}
+
+// scimError is an error response of the SCIM API (https://tools.ietf.org/html/rfc7644#section-3.12).
+type scimError struct {
+	Status   int
+	SCIMType string // e.g., "invalidFilter" or "uniqueness"
+	Detail   string
+}
+
+func (e *scimError) Error() string       { return e.Detail }
+func (e *scimError) HTTPStatusCode() int { return e.Status }
+
+func scimBadRequest(scimType, format string, args ...interface{}) *scimError {
+	return &scimError{Status: http.StatusBadRequest, SCIMType: scimType, Detail: fmt.Sprintf(format, args...)}
+}
+
+func handleSCIMError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	e, ok := err.(*scimError)
+	if !ok {
+		e = &scimError{Status: status}
+		switch {
+		case errcode.IsNotFound(err):
+			e.Status, e.Detail = http.StatusNotFound, "Resource not found."
+		case db.IsUsernameExists(err):
+			e.Status, e.SCIMType, e.Detail = http.StatusConflict, "uniqueness", "The username is already taken."
+		case db.IsEmailExists(err):
+			e.Status, e.SCIMType, e.Detail = http.StatusConflict, "uniqueness", "The email address is already in use."
+		default:
+			// Don't return the error message, since it may contain sensitive info.
+			e.Detail = http.StatusText(status)
+		}
+	}
+	if e.Status < 200 || e.Status >= 500 {
+		log15.Error("SCIM API handler error response", "method", r.Method, "request_uri", r.URL.RequestURI(), "status_code", e.Status, "error", err)
+	}
+	writeSCIMError(w, e)
+}
+
+func writeSCIMError(w http.ResponseWriter, e *scimError) {
+	w.Header().Set("Content-Type", scimContentType)
+	w.Header().Set("cache-control", "no-cache, max-age=0")
+	w.WriteHeader(e.Status)
+	_ = json.NewEncoder(w).Encode(struct {
+		Schemas  []string `json:"schemas"`
+		Status   string   `json:"status"`
+		SCIMType string   `json:"scimType,omitempty"`
+		Detail   string   `json:"detail,omitempty"`
+	}{
+		Schemas:  []string{scimSchemaError},
+		Status:   strconv.Itoa(e.Status),
+		SCIMType: e.SCIMType,
+		Detail:   e.Detail,
+	})
+}
+
+func writeSCIMResponse(w http.ResponseWriter, status int, v interface{}) error {
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(v)
+}
+
+// scimMeta is the "meta" attribute of a SCIM resource.
+type scimMeta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Location     string `json:"location,omitempty"`
+}
+
+// scimLocation returns the URL of the SCIM resource with the given type ("Users" or "Groups") and ID.
+func scimLocation(resourceType string, id int32) string {
+	return globals.ExternalURL().ResolveReference(&url.URL{Path: fmt.Sprintf("/.api/scim/v2/%s/%d", resourceType, id)}).String()
+}
+
+// logSCIMAuditEvent records a change that the identity provider made to a user or organization in
+// the audit log. The actor is the site admin whose access token authenticated the request.
+func logSCIMAuditEvent(ctx context.Context, action, targetKind string, targetID int32, targetName string, changes []db.AuditLogChange) {
+	backend.LogAuditEvent(ctx, backend.AuditEvent{
+		Action:     action,
+		TargetKind: targetKind,
+		TargetID:   string(relay.MarshalID(targetKind, targetID)),
+		TargetName: targetName,
+		Changes:    changes,
+	})
+}
+
+type scimListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+// scimListParams are the query parameters of a SCIM list request.
+type scimListParams struct {
+	StartIndex int // 1-based
+	Count      int
+
+	// FilterAttr and FilterValue are set if the request is filtered by "FilterAttr eq FilterValue".
+	FilterAttr, FilterValue string
+}
+
+// LimitOffset returns the DB limit and offset for the requested page.
+func (p *scimListParams) LimitOffset() *db.LimitOffset {
+	return &db.LimitOffset{Limit: p.Count, Offset: p.StartIndex - 1}
+}
+
+func parseSCIMListParams(r *http.Request) (*scimListParams, error) {
+	q := r.URL.Query()
+	p := &scimListParams{StartIndex: 1, Count: scimDefaultCount}
+	if v := q.Get("startIndex"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, scimBadRequest("invalidValue", "Invalid startIndex %q.", v)
+		}
+		if n > 1 {
+			p.StartIndex = n
+		}
+	}
+	if v := q.Get("count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, scimBadRequest("invalidValue", "Invalid count %q.", v)
+		}
+		switch {
+		case n < 0:
+			p.Count = 0
+		case n > scimMaxCount:
+			p.Count = scimMaxCount
+		default:
+			p.Count = n
+		}
+	}
+	if v := q.Get("filter"); v != "" {
+		var err error
+		if p.FilterAttr, p.FilterValue, err = parseSCIMFilter(v); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+var scimEqFilter = regexp.MustCompile(`^\s*([A-Za-z][\w.]*)\s+(?i:eq)\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// parseSCIMFilter parses a SCIM filter expression. Identity providers only use filters to look up
+// a resource by a unique attribute, so only filters of the form `attr eq "value"` are supported.
+func parseSCIMFilter(filter string) (attr, value string, err error) {
+	m := scimEqFilter.FindStringSubmatch(filter)
+	if m == nil {
+		return "", "", scimBadRequest("invalidFilter", "Unsupported filter %q (only filters of the form `attribute eq \"value\"` are supported).", filter)
+	}
+	if err := json.Unmarshal([]byte(`"`+m[2]+`"`), &value); err != nil {
+		return "", "", scimBadRequest("invalidFilter", "Invalid value in filter %q.", filter)
+	}
+	return m[1], value, nil
+}
+
+// parseSCIMID parses the ID of a user or group in the request URL.
+func parseSCIMID(r *http.Request) (int32, error) {
+	id, err := strconv.ParseInt(mux.Vars(r)["ID"], 10, 32)
+	if err != nil {
+		return 0, &scimError{Status: http.StatusNotFound, Detail: "Resource not found."}
+	}
+	return int32(id), nil
+}
+
+// scimPatchRequest is the body of a SCIM PATCH request (https://tools.ietf.org/html/rfc7644#section-3.5.2).
+type scimPatchRequest struct {
+	Operations []scimPatchOp `json:"Operations"`
+}
+
+type scimPatchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// expand returns the operation as a list of operations with paths. An operation without a path
+// has an object value whose attributes are the targets.
+func (op scimPatchOp) expand() ([]scimPatchOp, error) {
+	op.Op = strings.ToLower(op.Op)
+	switch op.Op {
+	case "add", "remove", "replace":
+	default:
+		return nil, scimBadRequest("invalidSyntax", "Unsupported patch operation %q.", op.Op)
+	}
+	if op.Path != "" {
+		return []scimPatchOp{op}, nil
+	}
+	if op.Op == "remove" {
+		return nil, scimBadRequest("noTarget", "A remove operation requires a path.")
+	}
+	var attrs map[string]json.RawMessage
+	if err := json.Unmarshal(op.Value, &attrs); err != nil {
+		return nil, scimBadRequest("invalidValue", "The value of a patch operation without a path must be an object.")
+	}
+	ops := make([]scimPatchOp, 0, len(attrs))
+	for attr, value := range attrs {
+		ops = append(ops, scimPatchOp{Op: op.Op, Path: attr, Value: value})
+	}
+	return ops, nil
+}
+
+// parseSCIMBool parses a boolean value. Some identity providers send booleans as the strings
+// "True" and "False".
+func parseSCIMBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b, nil
+		}
+	}
+	return false, scimBadRequest("invalidValue", "Invalid boolean value %s.", value)
+}
+
+func serveSCIMServiceProviderConfig(w http.ResponseWriter, r *http.Request) error {
+	type supported struct {
+		Supported bool `json:"supported"`
+	}
+	return writeSCIMResponse(w, http.StatusOK, map[string]interface{}{
+		"schemas": []string{scimSchemaServiceProviderConfig},
+		"patch":   supported{true},
+		"bulk": map[string]interface{}{
+			"supported":      false,
+			"maxOperations":  0,
+			"maxPayloadSize": 0,
+		},
+		"filter": map[string]interface{}{
+			"supported":  true,
+			"maxResults": scimMaxCount,
+		},
+		"changePassword": supported{false},
+		"sort":           supported{false},
+		"etag":           supported{false},
+		"authenticationSchemes": []map[string]interface{}{
+			{
+				"type":        "oauthbearertoken",
+				"name":        "OAuth Bearer Token",
+				"description": `Authentication with an access token that has the "site-admin:scim" scope`,
+				"primary":     true,
+			},
+		},
+		"meta": scimMeta{ResourceType: "ServiceProviderConfig"},
+	})
+}
@@ -0,0 +1,417 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+// scimGroup is the SCIM representation of a group (https://tools.ietf.org/html/rfc7643#section-4.2).
+// SCIM groups are mapped to organizations: the organization's name is derived from the group's
+// display name when the group is created, and its members are the group's members.
+type scimGroup struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	DisplayName string       `json:"displayName"`
+	Members     []scimMember `json:"members,omitempty"`
+	Meta        *scimMeta    `json:"meta,omitempty"`
+}
+
+type scimMember struct {
+	Value   string `json:"value"` // the user ID
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// memberIDs returns the IDs of the group's members.
+func (g *scimGroup) memberIDs() ([]int32, error) {
+	ids := make([]int32, 0, len(g.Members))
+	seen := map[int32]bool{}
+	for _, m := range g.Members {
+		id, err := strconv.ParseInt(m.Value, 10, 32)
+		if err != nil {
+			return nil, scimBadRequest("invalidValue", "Invalid member %q.", m.Value)
+		}
+		if !seen[int32(id)] {
+			seen[int32(id)] = true
+			ids = append(ids, int32(id))
+		}
+	}
+	return ids, nil
+}
+
+// applyPatch applies the operations of a SCIM PATCH request to the group resource.
+func (g *scimGroup) applyPatch(ops []scimPatchOp) error {
+	for _, op := range ops {
+		expanded, err := op.expand()
+		if err != nil {
+			return err
+		}
+		for _, op := range expanded {
+			if err := g.applyPatchOp(op); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (g *scimGroup) applyPatchOp(op scimPatchOp) error {
+	path := strings.ToLower(op.Path)
+	switch {
+	case path == "displayname":
+		if op.Op == "remove" {
+			return scimBadRequest("mutability", "The attribute %q cannot be removed.", op.Path)
+		}
+		if err := json.Unmarshal(op.Value, &g.DisplayName); err != nil {
+			return scimBadRequest("invalidValue", "Invalid value for %q.", op.Path)
+		}
+
+	case path == "members":
+		var members []scimMember
+		if len(op.Value) > 0 {
+			if err := json.Unmarshal(op.Value, &members); err != nil {
+				return scimBadRequest("invalidValue", "Invalid value for %q.", op.Path)
+			}
+		}
+		switch op.Op {
+		case "add":
+			g.Members = append(g.Members, members...)
+		case "replace":
+			g.Members = members
+		case "remove":
+			if members == nil {
+				g.Members = nil
+			}
+			for _, m := range members {
+				g.removeMember(m.Value)
+			}
+		}
+
+	case strings.HasPrefix(path, "members[") && strings.HasSuffix(path, "]"):
+		// A path like `members[value eq "5"]` identifies a single member.
+		attr, value, err := parseSCIMFilter(op.Path[len("members[") : len(op.Path)-1])
+		if err != nil {
+			return err
+		}
+		if op.Op != "remove" || strings.ToLower(attr) != "value" {
+			return scimBadRequest("invalidPath", "Unsupported path %q.", op.Path)
+		}
+		g.removeMember(value)
+	}
+	return nil
+}
+
+func (g *scimGroup) removeMember(value string) {
+	members := g.Members[:0]
+	for _, m := range g.Members {
+		if m.Value != value {
+			members = append(members, m)
+		}
+	}
+	g.Members = members
+}
+
+// toSCIMGroup returns the SCIM group resource for the organization. The members are omitted if
+// withMembers is false.
+func toSCIMGroup(ctx context.Context, org *types.Org, withMembers bool) (*scimGroup, error) {
+	g := &scimGroup{
+		Schemas:     []string{scimSchemaGroup},
+		ID:          strconv.Itoa(int(org.ID)),
+		DisplayName: org.Name,
+		Meta: &scimMeta{
+			ResourceType: "Group",
+			Created:      org.CreatedAt.Format(time.RFC3339),
+			LastModified: org.UpdatedAt.Format(time.RFC3339),
+			Location:     scimLocation("Groups", org.ID),
+		},
+	}
+	if org.DisplayName != nil && *org.DisplayName != "" {
+		g.DisplayName = *org.DisplayName
+	}
+	if !withMembers {
+		return g, nil
+	}
+
+	memberships, err := db.OrgMembers.GetByOrgID(ctx, org.ID)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]int32, len(memberships))
+	for i, m := range memberships {
+		userIDs[i] = m.UserID
+	}
+	users, err := listUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	g.Members = make([]scimMember, len(users))
+	for i, user := range users {
+		g.Members[i] = scimMember{
+			Value:   strconv.Itoa(int(user.ID)),
+			Display: user.Username,
+			Ref:     scimLocation("Users", user.ID),
+		}
+	}
+	return g, nil
+}
+
+func listUsersByIDs(ctx context.Context, userIDs []int32) ([]*types.User, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	return db.Users.List(ctx, &db.UsersListOptions{UserIDs: userIDs})
+}
+
+// getSCIMGroup returns the organization identified by the request URL.
+func getSCIMGroup(r *http.Request) (*types.Org, error) {
+	id, err := parseSCIMID(r)
+	if err != nil {
+		return nil, err
+	}
+	return db.Orgs.GetByID(r.Context(), id)
+}
+
+// scimGroupWithMembers reports whether the members of groups should be included in the response.
+func scimGroupWithMembers(r *http.Request) bool {
+	for _, attr := range strings.Split(r.URL.Query().Get("excludedAttributes"), ",") {
+		if strings.EqualFold(strings.TrimSpace(attr), "members") {
+			return false
+		}
+	}
+	return true
+}
+
+func serveSCIMGroupsList(w http.ResponseWriter, r *http.Request) error {
+	params, err := parseSCIMListParams(r)
+	if err != nil {
+		return err
+	}
+
+	var (
+		orgs  []*types.Org
+		total int
+	)
+	if params.FilterAttr != "" {
+		if !strings.EqualFold(params.FilterAttr, "displayName") {
+			return scimBadRequest("invalidFilter", "Filtering groups by %q is not supported.", params.FilterAttr)
+		}
+		if name, err := auth.NormalizeUsername(params.FilterValue); err == nil {
+			org, err := db.Orgs.GetByName(r.Context(), name)
+			if err != nil && !errcode.IsNotFound(err) {
+				return err
+			}
+			if org != nil {
+				total = 1
+				if params.StartIndex == 1 && params.Count > 0 {
+					orgs = []*types.Org{org}
+				}
+			}
+		}
+	} else {
+		if orgs, err = db.Orgs.List(r.Context(), &db.OrgsListOptions{LimitOffset: params.LimitOffset()}); err != nil {
+			return err
+		}
+		if total, err = db.Orgs.Count(r.Context(), db.OrgsListOptions{}); err != nil {
+			return err
+		}
+	}
+
+	withMembers := scimGroupWithMembers(r)
+	resources := make([]*scimGroup, len(orgs))
+	for i, org := range orgs {
+		if resources[i], err = toSCIMGroup(r.Context(), org, withMembers); err != nil {
+			return err
+		}
+	}
+	return writeSCIMResponse(w, http.StatusOK, &scimListResponse{
+		Schemas:      []string{scimSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   params.StartIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+func serveSCIMGroupsGet(w http.ResponseWriter, r *http.Request) error {
+	org, err := getSCIMGroup(r)
+	if err != nil {
+		return err
+	}
+	g, err := toSCIMGroup(r.Context(), org, scimGroupWithMembers(r))
+	if err != nil {
+		return err
+	}
+	return writeSCIMResponse(w, http.StatusOK, g)
+}
+
+func serveSCIMGroupsCreate(w http.ResponseWriter, r *http.Request) error {
+	var g scimGroup
+	if err := json.NewDecoder(r.Body).Decode(&g); err != nil {
+		return scimBadRequest("invalidSyntax", "Invalid request body.")
+	}
+	name, err := auth.NormalizeUsername(g.DisplayName)
+	if err != nil {
+		return scimBadRequest("invalidValue", "Invalid displayName: %s.", err)
+	}
+	memberIDs, err := g.memberIDs()
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.Orgs.GetByName(r.Context(), name); err == nil {
+		return &scimError{Status: http.StatusConflict, SCIMType: "uniqueness", Detail: "An organization with the name of the group already exists."}
+	} else if !errcode.IsNotFound(err) {
+		return err
+	}
+	org, err := db.Orgs.Create(r.Context(), name, &g.DisplayName)
+	if err != nil {
+		return err
+	}
+	memberChanges, err := syncSCIMGroupMembers(r.Context(), org.ID, memberIDs)
+	if err != nil {
+		return err
+	}
+	changes := []db.AuditLogChange{{Field: "name", After: org.Name}, {Field: "displayName", After: g.DisplayName}}
+	logSCIMAuditEvent(r.Context(), "scimCreateGroup", "Org", org.ID, org.Name, append(changes, memberChanges...))
+
+	created, err := toSCIMGroup(r.Context(), org, true)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Location", created.Meta.Location)
+	return writeSCIMResponse(w, http.StatusCreated, created)
+}
+
+func serveSCIMGroupsReplace(w http.ResponseWriter, r *http.Request) error {
+	org, err := getSCIMGroup(r)
+	if err != nil {
+		return err
+	}
+	var g scimGroup
+	if err := json.NewDecoder(r.Body).Decode(&g); err != nil {
+		return scimBadRequest("invalidSyntax", "Invalid request body.")
+	}
+	return updateSCIMGroup(w, r, org, &g)
+}
+
+func serveSCIMGroupsPatch(w http.ResponseWriter, r *http.Request) error {
+	org, err := getSCIMGroup(r)
+	if err != nil {
+		return err
+	}
+	var patch scimPatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		return scimBadRequest("invalidSyntax", "Invalid request body.")
+	}
+	g, err := toSCIMGroup(r.Context(), org, true)
+	if err != nil {
+		return err
+	}
+	if err := g.applyPatch(patch.Operations); err != nil {
+		return err
+	}
+	return updateSCIMGroup(w, r, org, g)
+}
+
+// updateSCIMGroup updates the organization to match the SCIM group resource. The organization's
+// name is not changed, only its display name.
+func updateSCIMGroup(w http.ResponseWriter, r *http.Request, org *types.Org, g *scimGroup) error {
+	memberIDs, err := g.memberIDs()
+	if err != nil {
+		return err
+	}
+	if g.DisplayName == "" {
+		return scimBadRequest("invalidValue", "The displayName of a group is required.")
+	}
+	var changes []db.AuditLogChange
+	if org.DisplayName == nil || *org.DisplayName != g.DisplayName {
+		change := db.AuditLogChange{Field: "displayName", After: g.DisplayName}
+		if org.DisplayName != nil {
+			change.Before = *org.DisplayName
+		}
+		if org, err = db.Orgs.Update(r.Context(), org.ID, &g.DisplayName); err != nil {
+			return err
+		}
+		changes = append(changes, change)
+	}
+	memberChanges, err := syncSCIMGroupMembers(r.Context(), org.ID, memberIDs)
+	if err != nil {
+		return err
+	}
+	changes = append(changes, memberChanges...)
+
+	// Identity providers push groups periodically, so only record updates that changed something.
+	if len(changes) > 0 {
+		logSCIMAuditEvent(r.Context(), "scimUpdateGroup", "Org", org.ID, org.Name, changes)
+	}
+
+	updated, err := toSCIMGroup(r.Context(), org, true)
+	if err != nil {
+		return err
+	}
+	return writeSCIMResponse(w, http.StatusOK, updated)
+}
+
+func serveSCIMGroupsDelete(w http.ResponseWriter, r *http.Request) error {
+	org, err := getSCIMGroup(r)
+	if err != nil {
+		return err
+	}
+	if err := db.Orgs.Delete(r.Context(), org.ID); err != nil {
+		return err
+	}
+	logSCIMAuditEvent(r.Context(), "scimDeleteGroup", "Org", org.ID, org.Name, nil)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// syncSCIMGroupMembers sets the members of the organization to the given users and returns the
+// changes for the audit log.
+func syncSCIMGroupMembers(ctx context.Context, orgID int32, userIDs []int32) ([]db.AuditLogChange, error) {
+	users, err := listUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) != len(userIDs) {
+		return nil, scimBadRequest("invalidValue", "A member of the group does not exist.")
+	}
+
+	memberships, err := db.OrgMembers.GetByOrgID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	isMember := make(map[int32]bool, len(memberships))
+	for _, m := range memberships {
+		isMember[m.UserID] = true
+	}
+	var changes []db.AuditLogChange
+	want := make(map[int32]bool, len(userIDs))
+	for _, userID := range userIDs {
+		want[userID] = true
+		if !isMember[userID] {
+			if _, err := db.OrgMembers.Create(ctx, orgID, userID); err != nil {
+				return nil, err
+			}
+			changes = append(changes, db.AuditLogChange{Field: "member", After: relay.MarshalID("User", userID)})
+		}
+	}
+	for _, m := range memberships {
+		if !want[m.UserID] {
+			if err := db.OrgMembers.Remove(ctx, orgID, m.UserID); err != nil {
+				return nil, err
+			}
+			changes = append(changes, db.AuditLogChange{Field: "member", Before: relay.MarshalID("User", m.UserID)})
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,296 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/httpapi/router"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+)
+
+// 🚨 SECURITY: test necessary to ensure security
+func TestAccessTokenAuthMiddleware_SCIM(t *testing.T) {
+	handler := AccessTokenAuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := checkSCIMActor(r.Context()); err != nil {
+			t.Errorf("unexpected SCIM actor error: %s", err)
+		}
+		fmt.Fprintf(w, "user %v", actor.FromContext(r.Context()).UID)
+	}))
+	checkStatus := func(t *testing.T, req *http.Request, wantStatusCode int) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != wantStatusCode {
+			t.Errorf("got response status %d, want %d", rr.Code, wantStatusCode)
+		}
+	}
+
+	t.Run("no token", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/.api/scim/v2/Users", nil)
+		req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: 456, FromSessionCookie: true}))
+		checkStatus(t, req, http.StatusUnauthorized)
+	})
+
+	t.Run("token without SCIM scope", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/.api/scim/v2/Users", nil)
+		req.Header.Set("Authorization", "Bearer abcdef")
+		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string, requiredScopes []string) (subjectUserID int32, scopes []string, err error) {
+			if want := []string{authz.ScopeSiteAdminSCIM}; !reflect.DeepEqual(requiredScopes, want) {
+				t.Errorf("got %q, want %q", requiredScopes, want)
+			}
+			return 0, nil, errors.New("x")
+		}
+		defer func() { db.Mocks = db.MockStores{} }()
+		checkStatus(t, req, http.StatusUnauthorized)
+	})
+
+	t.Run("subject is not a site admin", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/.api/scim/v2/Users", nil)
+		req.Header.Set("Authorization", "Bearer abcdef")
+		db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string, requiredScopes []string) (subjectUserID int32, scopes []string, err error) {
+			return 123, []string{authz.ScopeSiteAdminSCIM}, nil
+		}
+		db.Mocks.Users.GetByID = func(ctx context.Context, userID int32) (*types.User, error) {
+			return &types.User{ID: userID}, nil
+		}
+		defer func() { db.Mocks = db.MockStores{} }()
+		checkStatus(t, req, http.StatusForbidden)
+	})
+
+	for _, headerValue := range []string{"Bearer abcdef", "token abcdef"} {
+		t.Run("valid token: "+headerValue, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/.api/scim/v2/Users", nil)
+			req.Header.Set("Authorization", headerValue)
+			req = req.WithContext(actor.WithActor(context.Background(), &actor.Actor{UID: 456, FromSessionCookie: true}))
+			var calledAccessTokensLookup bool
+			db.Mocks.AccessTokens.Lookup = func(tokenHexEncoded string, requiredScopes []string) (subjectUserID int32, scopes []string, err error) {
+				calledAccessTokensLookup = true
+				if want := "abcdef"; tokenHexEncoded != want {
+					t.Errorf("got %q, want %q", tokenHexEncoded, want)
+				}
+				if want := []string{authz.ScopeSiteAdminSCIM}; !reflect.DeepEqual(requiredScopes, want) {
+					t.Errorf("got %q, want %q", requiredScopes, want)
+				}
+				return 123, []string{authz.ScopeSiteAdminSCIM}, nil
+			}
+			db.Mocks.Users.GetByID = func(ctx context.Context, userID int32) (*types.User, error) {
+				return &types.User{ID: userID, SiteAdmin: true}, nil
+			}
+			defer func() { db.Mocks = db.MockStores{} }()
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			if want := "user 123"; rr.Body.String() != want {
+				t.Errorf("got response body %q, want %q", rr.Body.String(), want)
+			}
+			if !calledAccessTokensLookup {
+				t.Error("!calledAccessTokensLookup")
+			}
+		})
+	}
+}
+
+func TestServeSCIMUsersGet(t *testing.T) {
+	defer func() { db.Mocks = db.MockStores{} }()
+	db.Mocks.Users.GetByID = func(ctx context.Context, userID int32) (*types.User, error) {
+		return &types.User{ID: userID, Username: "alice", DisplayName: "Alice", SiteAdmin: true}, nil
+	}
+	db.Mocks.UserEmails.ListByUser = func(userID int32) ([]*db.UserEmail, error) {
+		return []*db.UserEmail{{Email: "alice@example.com"}}, nil
+	}
+	db.Mocks.Orgs.GetByUserID = func(ctx context.Context, userID int32) ([]*types.Org, error) {
+		return []*types.Org{{ID: 3, Name: "eng"}}, nil
+	}
+	h := NewHandler(router.New(mux.NewRouter()))
+
+	// 🚨 SECURITY: test necessary to ensure security
+	t.Run("actor not authenticated by SCIM token", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/scim/v2/Users/2", nil)
+		ctx := context.WithValue(context.Background(), scimSubjectKey{}, int32(1))
+		req = req.WithContext(actor.WithActor(ctx, &actor.Actor{UID: 1, FromSessionCookie: true}))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("got response status %d, want %d", rr.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("user", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/scim/v2/Users/2", nil)
+		ctx := context.WithValue(context.Background(), scimSubjectKey{}, int32(1))
+		req = req.WithContext(actor.WithActor(ctx, &actor.Actor{UID: 1}))
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("got response status %d, want %d: %s", rr.Code, http.StatusOK, rr.Body)
+		}
+		var u scimUser
+		if err := json.Unmarshal(rr.Body.Bytes(), &u); err != nil {
+			t.Fatal(err)
+		}
+		if u.ID != "2" || u.UserName != "alice" || u.DisplayName != "Alice" {
+			t.Errorf("got user %+v", u)
+		}
+		if want := []scimEmail{{Value: "alice@example.com", Primary: true}}; !reflect.DeepEqual(u.Emails, want) {
+			t.Errorf("got emails %+v, want %+v", u.Emails, want)
+		}
+		if len(u.Groups) != 1 || u.Groups[0].Value != "3" {
+			t.Errorf("got groups %+v", u.Groups)
+		}
+	})
+}
+
+func TestServeSCIMUsersPatch_auditLog(t *testing.T) {
+	defer func() { db.Mocks = db.MockStores{} }()
+	verifiedAt := time.Now()
+	db.Mocks.Users.GetByID = func(ctx context.Context, userID int32) (*types.User, error) {
+		return &types.User{ID: userID, Username: "alice", State: types.UserStateActive}, nil
+	}
+	db.Mocks.UserEmails.ListByUser = func(userID int32) ([]*db.UserEmail, error) {
+		return []*db.UserEmail{{Email: "alice@example.com", VerifiedAt: &verifiedAt}}, nil
+	}
+	db.Mocks.Orgs.GetByUserID = func(ctx context.Context, userID int32) ([]*types.Org, error) {
+		return nil, nil
+	}
+	db.Mocks.Users.Update = func(userID int32, update db.UserUpdate) error { return nil }
+	db.Mocks.Users.SetState = func(id int32, state types.UserState) error { return nil }
+	var entries []*db.AuditLogEntry
+	db.Mocks.AuditLog.Create = func(e *db.AuditLogEntry) error {
+		entries = append(entries, e)
+		return nil
+	}
+	h := NewHandler(router.New(mux.NewRouter()))
+
+	req, _ := http.NewRequest("PATCH", "/scim/v2/Users/2", strings.NewReader(`{"Operations": [{"op": "replace", "path": "active", "value": false}]}`))
+	ctx := context.WithValue(context.Background(), scimSubjectKey{}, int32(1))
+	req = req.WithContext(actor.WithActor(ctx, &actor.Actor{UID: 1}))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got response status %d, want %d: %s", rr.Code, http.StatusOK, rr.Body)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("got %d audit log entries, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.Action != "scimUpdateUser" || e.ActorUserID != 1 || e.TargetKind != "User" || e.TargetName != "alice" {
+		t.Errorf("got audit log entry %+v", e)
+	}
+	if want := []db.AuditLogChange{{Field: "state", Before: types.UserStateActive, After: types.UserStateSuspended}}; !reflect.DeepEqual(e.Changes, want) {
+		t.Errorf("got changes %+v, want %+v", e.Changes, want)
+	}
+}
+
+func TestParseSCIMFilter(t *testing.T) {
+	tests := map[string]struct {
+		attr, value string
+		wantErr     bool
+	}{
+		`userName eq "alice"`:                {attr: "userName", value: "alice"},
+		`displayName EQ "Eng \"Core\""`:      {attr: "displayName", value: `Eng "Core"`},
+		`emails.value eq "a@b.com"`:          {attr: "emails.value", value: "a@b.com"},
+		`userName sw "a"`:                    {wantErr: true},
+		`userName eq "a" and active eq true`: {wantErr: true},
+	}
+	for filter, test := range tests {
+		t.Run(filter, func(t *testing.T) {
+			attr, value, err := parseSCIMFilter(filter)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if attr != test.attr || value != test.value {
+				t.Errorf("got %q eq %q, want %q eq %q", attr, value, test.attr, test.value)
+			}
+		})
+	}
+}
+
+func TestSCIMUser_applyPatch(t *testing.T) {
+	var patch scimPatchRequest
+	if err := json.Unmarshal([]byte(`{
+		"Operations": [
+			{"op": "Replace", "path": "emails[type eq \"work\"].value", "value": "new@example.com"},
+			{"op": "Replace", "path": "name.givenName", "value": "Alice"},
+			{"op": "Add", "path": "emails", "value": [{"value": "other@example.com"}]},
+			{"op": "Replace", "value": {"displayName": "Alice A", "active": "False"}}
+		]
+	}`), &patch); err != nil {
+		t.Fatal(err)
+	}
+
+	u := &scimUser{UserName: "alice", Emails: []scimEmail{{Value: "old@example.com", Primary: true}}}
+	if err := u.applyPatch(patch.Operations); err != nil {
+		t.Fatal(err)
+	}
+	state, err := u.state()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &scimUserState{
+		Username:    "alice",
+		DisplayName: "Alice A",
+		Emails:      []string{"new@example.com", "other@example.com"},
+		Active:      false,
+	}
+	if !reflect.DeepEqual(state, want) {
+		t.Errorf("got state %+v, want %+v", state, want)
+	}
+
+	if err := u.applyPatch([]scimPatchOp{{Op: "remove", Path: "userName"}}); err == nil {
+		t.Error("got nil error for removing userName, want error")
+	}
+}
+
+func TestSCIMGroup_applyPatch(t *testing.T) {
+	var patch scimPatchRequest
+	if err := json.Unmarshal([]byte(`{
+		"Operations": [
+			{"op": "add", "path": "members", "value": [{"value": "3"}, {"value": "4"}]},
+			{"op": "remove", "path": "members[value eq \"1\"]"},
+			{"op": "remove", "path": "members", "value": [{"value": "4"}]},
+			{"op": "replace", "path": "displayName", "value": "Engineering"}
+		]
+	}`), &patch); err != nil {
+		t.Fatal(err)
+	}
+
+	g := &scimGroup{DisplayName: "Eng", Members: []scimMember{{Value: "1"}, {Value: "2"}}}
+	if err := g.applyPatch(patch.Operations); err != nil {
+		t.Fatal(err)
+	}
+	if g.DisplayName != "Engineering" {
+		t.Errorf("got display name %q, want %q", g.DisplayName, "Engineering")
+	}
+	memberIDs, err := g.memberIDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int32{2, 3}; !reflect.DeepEqual(memberIDs, want) {
+		t.Errorf("got members %v, want %v", memberIDs, want)
+	}
+}
+
+func TestDiffEmails(t *testing.T) {
+	add, remove := diffEmails(
+		[]string{"a@example.com", "B@example.com", "c@example.com"},
+		[]string{"b@example.com", "d@example.com"},
+	)
+	if want := []string{"d@example.com"}; !reflect.DeepEqual(add, want) {
+		t.Errorf("got add %v, want %v", add, want)
+	}
+	if want := []string{"a@example.com", "c@example.com"}; !reflect.DeepEqual(remove, want) {
+		t.Errorf("got remove %v, want %v", remove, want)
+	}
+}
@@ -0,0 +1,539 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+// scimUser is the SCIM representation of a user (https://tools.ietf.org/html/rfc7643#section-4.1).
+// Attributes that Sourcegraph does not store are ignored.
+type scimUser struct {
+	Schemas     []string       `json:"schemas"`
+	ID          string         `json:"id,omitempty"`
+	UserName    string         `json:"userName"`
+	DisplayName string         `json:"displayName,omitempty"`
+	Name        *scimName      `json:"name,omitempty"`
+	Active      *scimBool      `json:"active,omitempty"`
+	Emails      []scimEmail    `json:"emails,omitempty"`
+	Groups      []scimGroupRef `json:"groups,omitempty"`
+	Meta        *scimMeta      `json:"meta,omitempty"`
+}
+
+type scimName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type scimEmail struct {
+	Value   string   `json:"value"`
+	Type    string   `json:"type,omitempty"`
+	Primary scimBool `json:"primary,omitempty"`
+}
+
+// scimGroupRef is a reference to a group in a user resource.
+type scimGroupRef struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// scimBool is a boolean that can also be unmarshaled from the strings "True" and "False".
+type scimBool bool
+
+func (b *scimBool) UnmarshalJSON(data []byte) error {
+	v, err := parseSCIMBool(data)
+	*b = scimBool(v)
+	return err
+}
+
+// scimUserState is the state of a user that is provisioned by the SCIM API.
+type scimUserState struct {
+	Username    string
+	DisplayName string
+	Emails      []string // the primary email first
+	Active      bool
+}
+
+// state returns the user state described by the SCIM user resource.
+func (u *scimUser) state() (*scimUserState, error) {
+	username, err := auth.NormalizeUsername(u.UserName)
+	if err != nil {
+		return nil, scimBadRequest("invalidValue", "Invalid userName: %s.", err)
+	}
+	s := &scimUserState{
+		Username:    username,
+		DisplayName: u.DisplayName,
+		Active:      u.Active == nil || bool(*u.Active),
+	}
+	if s.DisplayName == "" && u.Name != nil {
+		s.DisplayName = u.Name.Formatted
+		if s.DisplayName == "" {
+			s.DisplayName = strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+		}
+	}
+	seen := map[string]bool{}
+	for _, e := range u.Emails {
+		if e.Value == "" || seen[strings.ToLower(e.Value)] {
+			continue
+		}
+		seen[strings.ToLower(e.Value)] = true
+		if e.Primary {
+			s.Emails = append([]string{e.Value}, s.Emails...)
+		} else {
+			s.Emails = append(s.Emails, e.Value)
+		}
+	}
+	return s, nil
+}
+
+// applyPatch applies the operations of a SCIM PATCH request to the user resource.
+func (u *scimUser) applyPatch(ops []scimPatchOp) error {
+	for _, op := range ops {
+		expanded, err := op.expand()
+		if err != nil {
+			return err
+		}
+		for _, op := range expanded {
+			if err := u.applyPatchOp(op); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (u *scimUser) applyPatchOp(op scimPatchOp) error {
+	path := strings.ToLower(op.Path)
+	if op.Op == "remove" {
+		switch {
+		case path == "emails":
+			u.Emails = nil
+		case path == "name":
+			u.Name = nil
+		case path == "displayname":
+			u.DisplayName = ""
+		case path == "username", path == "active":
+			return scimBadRequest("mutability", "The attribute %q cannot be removed.", op.Path)
+		}
+		return nil
+	}
+
+	unmarshal := func(v interface{}) error {
+		if err := json.Unmarshal(op.Value, v); err != nil {
+			return scimBadRequest("invalidValue", "Invalid value for %q.", op.Path)
+		}
+		return nil
+	}
+	name := func() *scimName {
+		if u.Name == nil {
+			u.Name = &scimName{}
+		}
+		return u.Name
+	}
+	switch {
+	case path == "active":
+		v, err := parseSCIMBool(op.Value)
+		if err != nil {
+			return err
+		}
+		active := scimBool(v)
+		u.Active = &active
+	case path == "username":
+		return unmarshal(&u.UserName)
+	case path == "displayname":
+		return unmarshal(&u.DisplayName)
+	case path == "name":
+		return unmarshal(name())
+	case path == "name.formatted":
+		return unmarshal(&name().Formatted)
+	case path == "name.givenname":
+		return unmarshal(&name().GivenName)
+	case path == "name.familyname":
+		return unmarshal(&name().FamilyName)
+	case path == "emails":
+		var emails []scimEmail
+		if err := unmarshal(&emails); err != nil {
+			return err
+		}
+		if op.Op == "add" {
+			u.Emails = append(u.Emails, emails...)
+		} else {
+			u.Emails = emails
+		}
+	case strings.HasPrefix(path, "emails[") && strings.HasSuffix(path, "].value"):
+		// Some identity providers update the email address with a path like `emails[type eq
+		// "work"].value`. Sourcegraph doesn't store email types, so update the primary email.
+		var email string
+		if err := unmarshal(&email); err != nil {
+			return err
+		}
+		if len(u.Emails) == 0 {
+			u.Emails = []scimEmail{{Value: email, Primary: true}}
+		} else {
+			u.Emails[0].Value = email
+		}
+	}
+	return nil
+}
+
+// toSCIMUser returns the SCIM user resource for the user.
+func toSCIMUser(ctx context.Context, user *types.User) (*scimUser, error) {
+	emails, err := db.UserEmails.ListByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	orgs, err := db.Orgs.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	active := scimBool(user.State.Active())
+	u := &scimUser{
+		Schemas:     []string{scimSchemaUser},
+		ID:          strconv.Itoa(int(user.ID)),
+		UserName:    user.Username,
+		DisplayName: user.DisplayName,
+		Active:      &active,
+		Emails:      make([]scimEmail, len(emails)),
+		Groups:      make([]scimGroupRef, len(orgs)),
+		Meta: &scimMeta{
+			ResourceType: "User",
+			Created:      user.CreatedAt.Format(time.RFC3339),
+			LastModified: user.UpdatedAt.Format(time.RFC3339),
+			Location:     scimLocation("Users", user.ID),
+		},
+	}
+	if user.DisplayName != "" {
+		u.Name = &scimName{Formatted: user.DisplayName}
+	}
+	// The primary email is the oldest verified email (see (*db.userEmails).GetPrimaryEmail).
+	primary := -1
+	for i, e := range emails {
+		u.Emails[i] = scimEmail{Value: e.Email}
+		if primary == -1 && e.VerifiedAt != nil {
+			primary = i
+		}
+	}
+	if primary == -1 && len(emails) > 0 {
+		primary = 0
+	}
+	if primary != -1 {
+		u.Emails[primary].Primary = true
+	}
+	for i, org := range orgs {
+		u.Groups[i] = scimGroupRef{
+			Value:   strconv.Itoa(int(org.ID)),
+			Display: org.Name,
+			Ref:     scimLocation("Groups", org.ID),
+		}
+	}
+	return u, nil
+}
+
+// getSCIMUser returns the user identified by the request URL.
+func getSCIMUser(r *http.Request) (*types.User, error) {
+	id, err := parseSCIMID(r)
+	if err != nil {
+		return nil, err
+	}
+	return db.Users.GetByID(r.Context(), id)
+}
+
+func serveSCIMUsersList(w http.ResponseWriter, r *http.Request) error {
+	params, err := parseSCIMListParams(r)
+	if err != nil {
+		return err
+	}
+
+	var (
+		users []*types.User
+		total int
+	)
+	if params.FilterAttr != "" {
+		var user *types.User
+		switch strings.ToLower(params.FilterAttr) {
+		case "username":
+			if username, err := auth.NormalizeUsername(params.FilterValue); err == nil {
+				user, err = db.Users.GetByUsername(r.Context(), username)
+				if err != nil && !errcode.IsNotFound(err) {
+					return err
+				}
+			}
+		case "emails", "emails.value":
+			user, err = db.Users.GetByVerifiedEmail(r.Context(), params.FilterValue)
+			if err != nil && !errcode.IsNotFound(err) {
+				return err
+			}
+		default:
+			return scimBadRequest("invalidFilter", "Filtering users by %q is not supported.", params.FilterAttr)
+		}
+		if user != nil {
+			total = 1
+			if params.StartIndex == 1 && params.Count > 0 {
+				users = []*types.User{user}
+			}
+		}
+	} else {
+		opt := &db.UsersListOptions{LimitOffset: params.LimitOffset()}
+		if users, err = db.Users.List(r.Context(), opt); err != nil {
+			return err
+		}
+		if total, err = db.Users.Count(r.Context(), &db.UsersListOptions{}); err != nil {
+			return err
+		}
+	}
+
+	resources := make([]*scimUser, len(users))
+	for i, user := range users {
+		if resources[i], err = toSCIMUser(r.Context(), user); err != nil {
+			return err
+		}
+	}
+	return writeSCIMResponse(w, http.StatusOK, &scimListResponse{
+		Schemas:      []string{scimSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   params.StartIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+func serveSCIMUsersGet(w http.ResponseWriter, r *http.Request) error {
+	user, err := getSCIMUser(r)
+	if err != nil {
+		return err
+	}
+	u, err := toSCIMUser(r.Context(), user)
+	if err != nil {
+		return err
+	}
+	return writeSCIMResponse(w, http.StatusOK, u)
+}
+
+func serveSCIMUsersCreate(w http.ResponseWriter, r *http.Request) error {
+	var u scimUser
+	if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+		return scimBadRequest("invalidSyntax", "Invalid request body.")
+	}
+	state, err := u.state()
+	if err != nil {
+		return err
+	}
+	if !state.Active {
+		return scimBadRequest("invalidValue", "Inactive users cannot be created.")
+	}
+
+	newUser := db.NewUser{
+		Username:    state.Username,
+		DisplayName: state.DisplayName,
+		// 🚨 SECURITY: The identity provider is trusted to have verified the email addresses.
+		EmailIsVerified: true,
+	}
+	if len(state.Emails) > 0 {
+		newUser.Email = state.Emails[0]
+	}
+	user, err := db.Users.Create(r.Context(), newUser)
+	if err != nil {
+		return err
+	}
+	changes := []db.AuditLogChange{{Field: "username", After: user.Username}}
+	if newUser.Email != "" {
+		changes = append(changes, db.AuditLogChange{Field: "email", After: newUser.Email})
+	}
+	emailChanges, err := syncSCIMUserEmails(r.Context(), user.ID, state.Emails)
+	if err != nil {
+		return err
+	}
+	logSCIMAuditEvent(r.Context(), "scimCreateUser", "User", user.ID, user.Username, append(changes, emailChanges...))
+
+	created, err := toSCIMUser(r.Context(), user)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Location", created.Meta.Location)
+	return writeSCIMResponse(w, http.StatusCreated, created)
+}
+
+func serveSCIMUsersReplace(w http.ResponseWriter, r *http.Request) error {
+	user, err := getSCIMUser(r)
+	if err != nil {
+		return err
+	}
+	var u scimUser
+	if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+		return scimBadRequest("invalidSyntax", "Invalid request body.")
+	}
+	return updateSCIMUser(w, r, user, &u)
+}
+
+func serveSCIMUsersPatch(w http.ResponseWriter, r *http.Request) error {
+	user, err := getSCIMUser(r)
+	if err != nil {
+		return err
+	}
+	var patch scimPatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		return scimBadRequest("invalidSyntax", "Invalid request body.")
+	}
+	u, err := toSCIMUser(r.Context(), user)
+	if err != nil {
+		return err
+	}
+	if err := u.applyPatch(patch.Operations); err != nil {
+		return err
+	}
+	return updateSCIMUser(w, r, user, u)
+}
+
+// updateSCIMUser updates the user to match the SCIM user resource. Deactivating a user suspends it
+// (which keeps the user's data), and reactivating it makes it active again.
+func updateSCIMUser(w http.ResponseWriter, r *http.Request, user *types.User, u *scimUser) error {
+	state, err := u.state()
+	if err != nil {
+		return err
+	}
+
+	var changes []db.AuditLogChange
+	update := db.UserUpdate{DisplayName: &state.DisplayName}
+	if state.Username != user.Username {
+		update.Username = state.Username
+		changes = append(changes, db.AuditLogChange{Field: "username", Before: user.Username, After: state.Username})
+	}
+	if state.DisplayName != user.DisplayName {
+		changes = append(changes, db.AuditLogChange{Field: "displayName", Before: user.DisplayName, After: state.DisplayName})
+	}
+	if err := db.Users.Update(r.Context(), user.ID, update); err != nil {
+		return err
+	}
+	emailChanges, err := syncSCIMUserEmails(r.Context(), user.ID, state.Emails)
+	if err != nil {
+		return err
+	}
+	changes = append(changes, emailChanges...)
+	userState := types.UserStateActive
+	if !state.Active {
+		userState = types.UserStateSuspended
+	}
+	if user.State != userState {
+		if err := db.Users.SetState(r.Context(), user.ID, userState); err != nil {
+			return err
+		}
+		changes = append(changes, db.AuditLogChange{Field: "state", Before: user.State, After: userState})
+	}
+
+	// Identity providers push users periodically, so only record updates that changed something.
+	if len(changes) > 0 {
+		logSCIMAuditEvent(r.Context(), "scimUpdateUser", "User", user.ID, state.Username, changes)
+	}
+
+	user, err = db.Users.GetByID(r.Context(), user.ID)
+	if err != nil {
+		return err
+	}
+	updated, err := toSCIMUser(r.Context(), user)
+	if err != nil {
+		return err
+	}
+	return writeSCIMResponse(w, http.StatusOK, updated)
+}
+
+func serveSCIMUsersDelete(w http.ResponseWriter, r *http.Request) error {
+	user, err := getSCIMUser(r)
+	if err != nil {
+		return err
+	}
+	if err := db.Users.Delete(r.Context(), user.ID); err != nil {
+		return err
+	}
+	logSCIMAuditEvent(r.Context(), "scimDeleteUser", "User", user.ID, user.Username, nil)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// syncSCIMUserEmails sets the user's email addresses to the given (verified) addresses and returns
+// the changes for the audit log. If no addresses are given, the user's email addresses are left
+// unchanged.
+func syncSCIMUserEmails(ctx context.Context, userID int32, emails []string) ([]db.AuditLogChange, error) {
+	if len(emails) == 0 {
+		return nil, nil
+	}
+	existing, err := db.UserEmails.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	have := make([]string, len(existing))
+	for i, e := range existing {
+		have[i] = e.Email
+	}
+
+	var changes []db.AuditLogChange
+	add, remove := diffEmails(have, emails)
+	// Add before removing so that the user always has an email address.
+	//
+	// 🚨 SECURITY: The identity provider is trusted to have verified the email addresses.
+	for _, email := range add {
+		if err := db.UserEmails.Add(ctx, userID, email, nil); err != nil {
+			return nil, err
+		}
+		if err := db.UserEmails.SetVerified(ctx, userID, email, true); err != nil {
+			return nil, err
+		}
+		changes = append(changes, db.AuditLogChange{Field: "email", After: email})
+	}
+	for _, e := range existing {
+		if e.VerifiedAt == nil && containsFold(emails, e.Email) {
+			if err := db.UserEmails.SetVerified(ctx, userID, e.Email, true); err != nil {
+				return nil, err
+			}
+			changes = append(changes, db.AuditLogChange{Field: "verifiedEmail", After: e.Email})
+		}
+	}
+	for _, email := range remove {
+		if err := db.UserEmails.Remove(ctx, userID, email); err != nil {
+			return nil, err
+		}
+		changes = append(changes, db.AuditLogChange{Field: "email", Before: email})
+	}
+	return changes, nil
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// diffEmails returns the email addresses in want that are not in have, and the email addresses in
+// have that are not in want. Email addresses are compared case-insensitively.
+func diffEmails(have, want []string) (add, remove []string) {
+	haveSet := make(map[string]bool, len(have))
+	for _, email := range have {
+		haveSet[strings.ToLower(email)] = true
+	}
+	wantSet := make(map[string]bool, len(want))
+	for _, email := range want {
+		wantSet[strings.ToLower(email)] = true
+		if !haveSet[strings.ToLower(email)] {
+			add = append(add, email)
+		}
+	}
+	for _, email := range have {
+		if !wantSet[strings.ToLower(email)] {
+			remove = append(remove, email)
+		}
+	}
+	return add, remove
+}
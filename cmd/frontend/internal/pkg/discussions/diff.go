@@ -0,0 +1,128 @@
+package discussions
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/sourcegraph/go-diff/diff"
+)
+
+// DiffSide is one side (old or new) of a file diff.
+type DiffSide int
+
+const (
+	// DiffSideOld is the old (original) file of a file diff.
+	DiffSideOld DiffSide = iota
+
+	// DiffSideNew is the new (changed) file of a file diff.
+	DiffSideNew
+)
+
+func (s DiffSide) String() string {
+	if s == DiffSideOld {
+		return "old"
+	}
+	return "new"
+}
+
+// DiffPosition is a line on one side of a file diff.
+type DiffPosition struct {
+	Side DiffSide
+
+	// Line in the file of that side (zero-based).
+	Line int
+}
+
+// DiffSelection is a selection of lines in a file diff, mapped to the lines
+// of the old and new files.
+type DiffSelection struct {
+	// Old is the range of selected lines in the old file, or nil if no lines
+	// of the old file were selected (e.g., only added lines were selected).
+	Old *LineRange
+
+	// New is the range of selected lines in the new file, or nil if no lines
+	// of the new file were selected (e.g., only removed lines were selected).
+	New *LineRange
+}
+
+// diffLine is a line of a hunk body, with its zero-based line number in the
+// old and new files (-1 if the line is not in that file).
+type diffLine struct {
+	old, new int
+}
+
+// diffLines returns the lines of the hunks' bodies.
+func diffLines(hunks []*diff.Hunk) []diffLine {
+	var lines []diffLine
+	for _, hunk := range hunks {
+		// Hunk start lines are one-based, and zero for an empty side.
+		oldLine, newLine := int(hunk.OrigStartLine)-1, int(hunk.NewStartLine)-1
+		for _, line := range bytes.Split(bytes.TrimSuffix(hunk.Body, []byte("\n")), []byte("\n")) {
+			if len(line) == 0 {
+				// An empty context line whose leading space was stripped.
+				line = []byte(" ")
+			}
+			switch line[0] {
+			case '-':
+				lines = append(lines, diffLine{old: oldLine, new: -1})
+				oldLine++
+			case '+':
+				lines = append(lines, diffLine{old: -1, new: newLine})
+				newLine++
+			case '\\':
+				// "\ No newline at end of file"
+			default:
+				lines = append(lines, diffLine{old: oldLine, new: newLine})
+				oldLine++
+				newLine++
+			}
+		}
+	}
+	return lines
+}
+
+// SelectionForDiff maps the lines of a file diff from start to end (both
+// inclusive, in the order in which the lines appear in the diff) to the lines
+// of the old and new files.
+//
+// Unchanged (context) lines are on both sides, so a position on either side
+// matches them. An error is returned if start or end is not a line of the
+// hunks, or if end comes before start.
+func SelectionForDiff(hunks []*diff.Hunk, start, end DiffPosition) (*DiffSelection, error) {
+	lines := diffLines(hunks)
+
+	index := func(p DiffPosition) int {
+		for i, l := range lines {
+			if (p.Side == DiffSideOld && l.old == p.Line) || (p.Side == DiffSideNew && l.new == p.Line) {
+				return i
+			}
+		}
+		return -1
+	}
+	startIndex, endIndex := index(start), index(end)
+	if startIndex == -1 {
+		return nil, fmt.Errorf("selection start (%s line %d) is not in the diff", start.Side, start.Line)
+	}
+	if endIndex == -1 {
+		return nil, fmt.Errorf("selection end (%s line %d) is not in the diff", end.Side, end.Line)
+	}
+	if endIndex < startIndex {
+		return nil, fmt.Errorf("selection end (%s line %d) is before its start (%s line %d)", end.Side, end.Line, start.Side, start.Line)
+	}
+
+	sel := &DiffSelection{}
+	extend := func(r **LineRange, line int) {
+		if line == -1 {
+			return
+		}
+		if *r == nil {
+			*r = &LineRange{StartLine: line}
+		}
+		(*r).EndLine = line + 1
+	}
+	for _, l := range lines[startIndex : endIndex+1] {
+		extend(&sel.Old, l.old)
+		extend(&sel.New, l.new)
+	}
+	return sel, nil
+}
@@ -0,0 +1,114 @@
+package discussions
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/go-diff/diff"
+)
+
+func TestSelectionForDiff(t *testing.T) {
+	hunks := []*diff.Hunk{
+		{
+			// Old lines 2-6, new lines 2-5 (one-based).
+			OrigStartLine: 2,
+			OrigLines:     5,
+			NewStartLine:  2,
+			NewLines:      4,
+			Body: []byte(` a
+-b
+-c
++B
+ d
+
+`),
+		},
+		{
+			// Old lines 20-21, new lines 19-21 (one-based).
+			OrigStartLine: 20,
+			OrigLines:     2,
+			NewStartLine:  19,
+			NewLines:      3,
+			Body: []byte(` x
++y
+ z
+\ No newline at end of file
+`),
+		},
+	}
+	oldLine := func(line int) DiffPosition { return DiffPosition{Side: DiffSideOld, Line: line} }
+	newLine := func(line int) DiffPosition { return DiffPosition{Side: DiffSideNew, Line: line} }
+
+	tests := []struct {
+		name       string
+		start, end DiffPosition
+		want       *DiffSelection
+		wantErr    bool
+	}{
+		{
+			name:  "context line",
+			start: oldLine(1),
+			end:   newLine(1),
+			want:  &DiffSelection{Old: &LineRange{StartLine: 1, EndLine: 2}, New: &LineRange{StartLine: 1, EndLine: 2}},
+		},
+		{
+			name:  "removed lines",
+			start: oldLine(2),
+			end:   oldLine(3),
+			want:  &DiffSelection{Old: &LineRange{StartLine: 2, EndLine: 4}},
+		},
+		{
+			name:  "added line",
+			start: newLine(2),
+			end:   newLine(2),
+			want:  &DiffSelection{New: &LineRange{StartLine: 2, EndLine: 3}},
+		},
+		{
+			name:  "removed and added lines",
+			start: oldLine(2),
+			end:   newLine(2),
+			want:  &DiffSelection{Old: &LineRange{StartLine: 2, EndLine: 4}, New: &LineRange{StartLine: 2, EndLine: 3}},
+		},
+		{
+			name:  "whole hunk",
+			start: newLine(1),
+			end:   oldLine(5),
+			want:  &DiffSelection{Old: &LineRange{StartLine: 1, EndLine: 6}, New: &LineRange{StartLine: 1, EndLine: 5}},
+		},
+		{
+			name:  "second hunk",
+			start: newLine(19),
+			end:   newLine(20),
+			want:  &DiffSelection{Old: &LineRange{StartLine: 20, EndLine: 21}, New: &LineRange{StartLine: 19, EndLine: 21}},
+		},
+		{
+			name:    "line not in diff",
+			start:   newLine(10),
+			end:     newLine(19),
+			wantErr: true,
+		},
+		{
+			name:    "old line not in diff",
+			start:   oldLine(1),
+			end:     oldLine(10),
+			wantErr: true,
+		},
+		{
+			name:    "end before start",
+			start:   newLine(2),
+			end:     oldLine(2),
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := SelectionForDiff(hunks, test.start, test.end)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package mailreply
 
 import (
-	"bytes"
 	"encoding/base64"
 	"fmt"
 	"io"
@@ -51,27 +50,42 @@ func (m *Message) TextContent() ([]byte, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "ReadMessage")
 	}
-	body, err := ioutil.ReadAll(msg.Body)
-	if err != nil {
-		return nil, errors.Wrap(err, "ReadAll")
+	return messageTextContent(msg)
+}
+
+// messageTextContent returns the plain text contents of the message body. It
+// is used for messages read by both the IMAP worker and the SMTP receiver.
+func messageTextContent(msg *mail.Message) ([]byte, error) {
+	header := textproto.MIMEHeader(msg.Header)
+	mediaType, params := "text/plain", map[string]string{}
+	if _, ok := header["Content-Type"]; ok {
+		var err error
+		mediaType, params, err = mime.ParseMediaType(header.Get("Content-Type"))
+		if err != nil {
+			return nil, errors.Wrap(err, "ParseMediaType")
+		}
 	}
 
 	// If we already have a text/plain message body, return it directly.
-	if m.BodyStructure.MIMEType == "text" && m.BodyStructure.MIMESubType == "plain" {
+	if mediaType == "text/plain" {
+		body, err := ioutil.ReadAll(msg.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "ReadAll")
+		}
 		return body, nil
 	}
 
 	// If we don't have a multipart message body, we don't know how to find
 	// plain text in the message.
-	if m.BodyStructure.MIMEType != "multipart" {
+	if !strings.HasPrefix(mediaType, "multipart/") {
 		return nil, nil
 	}
 
-	boundary, ok := m.BodyStructure.Params["BOUNDARY"]
+	boundary, ok := params["boundary"]
 	if !ok {
 		return nil, errors.New("multipart message missing BOUNDARY parameter")
 	}
-	mr := multipart.NewReader(bytes.NewReader(body), boundary)
+	mr := multipart.NewReader(msg.Body, boundary)
 	for {
 		part, err := mr.NextPart()
 		if err == io.EOF {
@@ -140,7 +154,7 @@ func messagePartTextContent(part io.ReadCloser, header textproto.MIMEHeader) (io
 // NewMailReader returns a new reader that reads mail from the configured IMAP
 // server. If no IMAP server is configured nil, nil is returned.
 func NewMailReader() (*MailReader, error) {
-	if !conf.CanReadEmail() {
+	if conf.Get().EmailImap == nil {
 		return nil, nil
 	}
 	conf := conf.Get()
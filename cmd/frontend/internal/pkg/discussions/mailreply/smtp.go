@@ -0,0 +1,350 @@
+package mailreply
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+const (
+	// maxSMTPMessageSize is the maximum size (in bytes) of a message that the
+	// SMTP receiver accepts.
+	maxSMTPMessageSize = 10 << 20
+
+	// maxSMTPRecipients is the maximum number of recipients that the SMTP
+	// receiver accepts for a message.
+	maxSMTPRecipients = 100
+
+	// smtpTimeout is how long the SMTP receiver waits for each command (and
+	// the message data) before closing the connection.
+	smtpTimeout = 5 * time.Minute
+)
+
+// StartSMTPReceiver should be invoked only after the DB has been initialized.
+// If an SMTP receiver is configured (email.smtpReceiver), it starts listening
+// for email replies that are forwarded to it by a mail server and updates
+// discussion threads based on them. It restarts the receiver when its
+// configuration changes.
+//
+// It should be invoked in a separate goroutine.
+func StartSMTPReceiver() {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "localhost"
+	}
+
+	var (
+		mu       sync.Mutex
+		config   schema.SMTPReceiverConfig // the configuration of the running receiver, if any
+		listener net.Listener
+	)
+	conf.Watch(func() {
+		var newConfig schema.SMTPReceiverConfig
+		if c := conf.Get().EmailSmtpReceiver; c != nil {
+			newConfig = *c
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if newConfig == config {
+			return
+		}
+		if listener != nil {
+			listener.Close()
+			listener = nil
+		}
+		config = newConfig
+		if config.ListenAddress == "" {
+			return
+		}
+
+		// Unlike the IMAP worker, the receiver runs on every frontend instance,
+		// because each message is delivered to only one of them.
+		var err error
+		listener, err = net.Listen("tcp", config.ListenAddress)
+		if err != nil {
+			log15.Error("discussions: mailreply SMTP receiver: failed to listen", "address", config.ListenAddress, "error", err)
+			return
+		}
+		log15.Debug("discussions: mailreply SMTP receiver listening", "address", config.ListenAddress, "protocol", config.Protocol)
+		s := &smtpServer{
+			hostname: hostname,
+			lmtp:     config.Protocol == "lmtp",
+			domain:   replyDomain(config.Address),
+			post:     postReply,
+		}
+		go s.serve(listener)
+	})
+}
+
+// replyDomain returns the domain of the reply address (e.g., "example.com" for
+// "notifications@example.com").
+func replyDomain(address string) string {
+	return address[strings.LastIndex(address, "@")+1:]
+}
+
+// smtpServer receives email replies over SMTP (RFC 5321) or LMTP (RFC 2033).
+// It implements only what is needed to accept mail that is forwarded to it by
+// another mail server: it does not support TLS or authentication, and it never
+// relays mail.
+type smtpServer struct {
+	hostname string // the hostname to greet clients with
+	lmtp     bool   // whether to speak LMTP instead of SMTP
+	domain   string // the reply domain; mail to other domains is rejected
+
+	// post posts a reply as a comment once its reply token has been verified.
+	post func(ctx context.Context, userID int32, threadID int64, textContent []byte) error
+}
+
+func (s *smtpServer) serve(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				time.Sleep(time.Second)
+				continue
+			}
+			return // the listener was closed
+		}
+		go s.serveConn(conn)
+	}
+}
+
+func (s *smtpServer) serveConn(conn net.Conn) {
+	defer conn.Close()
+	sess := &smtpSession{smtpServer: s, conn: conn, text: textproto.NewConn(conn)}
+	if err := sess.run(context.Background()); err != nil {
+		log15.Debug("discussions: mailreply SMTP receiver: connection closed", "remote", conn.RemoteAddr(), "error", err)
+	}
+}
+
+// smtpSession is the state of a connection to the SMTP receiver.
+type smtpSession struct {
+	*smtpServer
+	conn net.Conn
+	text *textproto.Conn
+
+	// The state of the current mail transaction.
+	inTransaction bool
+	recipients    []smtpRecipient
+}
+
+// smtpRecipient is a recipient of a message, whose reply token has been
+// verified.
+type smtpRecipient struct {
+	userID   int32
+	threadID int64
+}
+
+func (s *smtpSession) reply(code int, format string, args ...interface{}) error {
+	return s.text.PrintfLine("%d %s", code, fmt.Sprintf(format, args...))
+}
+
+func (s *smtpSession) reset() {
+	s.inTransaction = false
+	s.recipients = nil
+}
+
+func (s *smtpSession) run(ctx context.Context) error {
+	protocol, hello := "ESMTP", "EHLO"
+	if s.lmtp {
+		protocol, hello = "LMTP", "LHLO"
+	}
+	if err := s.reply(220, "%s %s Sourcegraph", s.hostname, protocol); err != nil {
+		return err
+	}
+	for {
+		if err := s.conn.SetDeadline(time.Now().Add(smtpTimeout)); err != nil {
+			return err
+		}
+		line, err := s.text.ReadLine()
+		if err != nil {
+			return err
+		}
+		verb, arg := line, ""
+		if i := strings.IndexByte(line, ' '); i != -1 {
+			verb, arg = line[:i], strings.TrimSpace(line[i+1:])
+		}
+
+		switch verb = strings.ToUpper(verb); verb {
+		case "HELO", "EHLO", "LHLO":
+			if (verb == "LHLO") != s.lmtp {
+				err = s.reply(500, "5.5.1 Use %s", hello)
+				break
+			}
+			s.reset()
+			if verb == "HELO" {
+				err = s.reply(250, "%s", s.hostname)
+				break
+			}
+			for _, ext := range []string{s.hostname, "8BITMIME", "ENHANCEDSTATUSCODES"} {
+				if err = s.text.PrintfLine("250-%s", ext); err != nil {
+					break
+				}
+			}
+			if err == nil {
+				err = s.reply(250, "SIZE %d", maxSMTPMessageSize)
+			}
+
+		case "MAIL":
+			if s.inTransaction {
+				err = s.reply(503, "5.5.1 Nested MAIL command")
+				break
+			}
+			if _, ok := parseSMTPPath(arg, "FROM:"); !ok {
+				err = s.reply(501, "5.5.4 Syntax: MAIL FROM:<address>")
+				break
+			}
+			s.inTransaction = true
+			err = s.reply(250, "2.1.0 OK")
+
+		case "RCPT":
+			if !s.inTransaction {
+				err = s.reply(503, "5.5.1 Need MAIL command")
+				break
+			}
+			address, ok := parseSMTPPath(arg, "TO:")
+			if !ok {
+				err = s.reply(501, "5.5.4 Syntax: RCPT TO:<address>")
+				break
+			}
+			if len(s.recipients) >= maxSMTPRecipients {
+				err = s.reply(452, "4.5.3 Too many recipients")
+				break
+			}
+			code, msg := s.addRecipient(ctx, address)
+			err = s.reply(code, "%s", msg)
+
+		case "DATA":
+			if len(s.recipients) == 0 {
+				err = s.reply(503, "5.5.1 Need RCPT command")
+				break
+			}
+			if err = s.reply(354, "Start mail input; end with <CRLF>.<CRLF>"); err != nil {
+				break
+			}
+			dot := s.text.DotReader()
+			data, readErr := ioutil.ReadAll(io.LimitReader(dot, maxSMTPMessageSize+1))
+			if readErr != nil {
+				return readErr
+			}
+			code, msg := 552, "5.3.4 Message too big"
+			if len(data) > maxSMTPMessageSize {
+				if _, err := io.Copy(ioutil.Discard, dot); err != nil {
+					return err
+				}
+			} else {
+				code, msg = s.deliver(ctx, data)
+			}
+
+			// LMTP requires a reply for each recipient.
+			replies := 1
+			if s.lmtp {
+				replies = len(s.recipients)
+			}
+			for i := 0; i < replies && err == nil; i++ {
+				err = s.reply(code, "%s", msg)
+			}
+			s.reset()
+
+		case "RSET":
+			s.reset()
+			err = s.reply(250, "2.0.0 OK")
+
+		case "NOOP":
+			err = s.reply(250, "2.0.0 OK")
+
+		case "VRFY":
+			err = s.reply(252, "2.5.0 Cannot VRFY user")
+
+		case "QUIT":
+			return s.reply(221, "2.0.0 Bye")
+
+		default:
+			err = s.reply(502, "5.5.2 Command not recognized")
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// addRecipient verifies the reply token in the recipient address and, if it
+// is valid, adds the recipient to the current mail transaction. It returns the
+// reply to the RCPT command.
+func (s *smtpSession) addRecipient(ctx context.Context, address string) (code int, msg string) {
+	i := strings.LastIndex(address, "@")
+	if i == -1 || !strings.EqualFold(address[i+1:], s.domain) {
+		return 550, "5.7.1 Relaying denied"
+	}
+
+	// 🚨 SECURITY: Only accept mail for recipients with a valid reply token.
+	userID, threadID, err := verifyReplyToken(ctx, []string{address[:i]})
+	if err == db.ErrInvalidToken || err == errNoReplyToken {
+		log15.Debug("discussions: mailreply SMTP receiver: rejecting recipient without valid authorization token", "address", address)
+		return 550, "5.1.1 Mailbox unavailable"
+	}
+	if err != nil {
+		return 451, "4.3.0 Temporary failure, try again later"
+	}
+	s.recipients = append(s.recipients, smtpRecipient{userID: userID, threadID: threadID})
+	return 250, "2.1.5 OK"
+}
+
+// deliver posts the message data as a reply and returns the reply to the DATA
+// command.
+//
+// Like the IMAP worker, it posts a message addressed to several reply
+// addresses only once, using the first one.
+func (s *smtpSession) deliver(ctx context.Context, data []byte) (code int, msg string) {
+	m, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return 554, "5.6.0 Malformed message"
+	}
+	textContent, err := messageTextContent(m)
+	if err != nil {
+		log15.Debug("discussions: mailreply SMTP receiver: error while reading TextContent", "error", err)
+		return 554, "5.6.0 Unable to read message text"
+	}
+
+	recipient := s.recipients[0]
+	err = s.post(ctx, recipient.userID, recipient.threadID, textContent)
+	if err == errEmptyReply {
+		log15.Debug("discussions: mailreply SMTP receiver: ignoring email with no effective content", "subject", m.Header.Get("Subject"), "content", string(textContent))
+		return 250, "2.0.0 OK" // accept and ignore empty replies
+	}
+	if err != nil {
+		log15.Error("discussions: mailreply SMTP receiver: error while adding comment to thread", "error", err)
+		return 451, "4.3.0 Temporary failure, try again later"
+	}
+	return 250, "2.0.0 OK"
+}
+
+// parseSMTPPath parses the address out of the argument of a MAIL or RCPT
+// command (e.g., "alice@example.com" from "FROM:<alice@example.com> SIZE=123"
+// with prefix "FROM:"). Any parameters are ignored.
+func parseSMTPPath(arg, prefix string) (string, bool) {
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", false
+	}
+	arg = strings.TrimSpace(arg[len(prefix):])
+	end := strings.IndexByte(arg, '>')
+	if !strings.HasPrefix(arg, "<") || end == -1 {
+		return "", false
+	}
+	return arg[1:end], true
+}
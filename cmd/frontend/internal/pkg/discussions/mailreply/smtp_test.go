@@ -0,0 +1,106 @@
+package mailreply
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+)
+
+func TestSMTPServer(t *testing.T) {
+	defer func() { db.Mocks = db.MockStores{} }()
+	db.Mocks.DiscussionMailReplyTokens.Get = func(ctx context.Context, token string) (int32, int64, error) {
+		if token != "secret" {
+			return 0, 0, db.ErrInvalidToken
+		}
+		return 1, 2, nil
+	}
+
+	type post struct {
+		userID      int32
+		threadID    int64
+		textContent string
+	}
+	var posts []post
+	s := &smtpServer{
+		hostname: "sourcegraph.example.com",
+		domain:   "example.com",
+		post: func(ctx context.Context, userID int32, threadID int64, textContent []byte) error {
+			posts = append(posts, post{userID, threadID, string(textContent)})
+			return nil
+		},
+	}
+	serverConn, clientConn := net.Pipe()
+	go s.serveConn(serverConn)
+	c, err := smtp.NewClient(clientConn, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := c.Hello("mail.example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	wantCode := func(err error, code int) {
+		t.Helper()
+		if e, ok := err.(*textproto.Error); !ok || e.Code != code {
+			t.Errorf("got error %v, want code %d", err, code)
+		}
+	}
+
+	if err := c.Mail("alice@example.org"); err != nil {
+		t.Fatal(err)
+	}
+	// 🚨 SECURITY: Recipients without a valid reply token, or outside of the reply domain, must be
+	// rejected.
+	wantCode(c.Rcpt("notifications@example.com"), 550)
+	wantCode(c.Rcpt("notifications+invalid@example.com"), 550)
+	wantCode(c.Rcpt("notifications+secret@example.org"), 550)
+	if err := c.Rcpt("notifications+secret@EXAMPLE.com"); err != nil {
+		t.Fatal(err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("Subject: Re: Foo\r\nContent-Type: text/plain\r\n\r\nLooks good!\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if want := (post{userID: 1, threadID: 2, textContent: "Looks good!\n"}); len(posts) != 1 || posts[0] != want {
+		t.Errorf("got posts %+v, want %+v", posts, want)
+	}
+
+	// The transaction is reset after the message is delivered.
+	wantCode(c.Rcpt("notifications+secret@example.com"), 503)
+	if err := c.Quit(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseSMTPPath(t *testing.T) {
+	tests := map[string]struct {
+		arg, prefix string
+		want        string
+		wantOK      bool
+	}{
+		"address":    {arg: "FROM:<alice@example.com>", prefix: "FROM:", want: "alice@example.com", wantOK: true},
+		"parameters": {arg: "to: <alice@example.com> NOTIFY=NEVER", prefix: "TO:", want: "alice@example.com", wantOK: true},
+		"null path":  {arg: "FROM:<>", prefix: "FROM:", want: "", wantOK: true},
+		"no prefix":  {arg: "<alice@example.com>", prefix: "FROM:"},
+		"no angles":  {arg: "FROM:alice@example.com", prefix: "FROM:"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := parseSMTPPath(test.arg, test.prefix)
+			if got != test.want || ok != test.wantOK {
+				t.Errorf("got %q, %v, want %q, %v", got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+}
@@ -1,5 +1,5 @@
-// Package mailreply implements an IMAP inbox monitor to consume email replies
-// to discussions.
+// Package mailreply implements an IMAP inbox monitor and an SMTP receiver to
+// consume email replies to discussions.
 package mailreply
 
 import (
@@ -24,7 +24,7 @@ import (
 // It should be invoked in a separate goroutine.
 func StartWorker() {
 	conf.Watch(func() {
-		if !conf.CanReadEmail() {
+		if conf.Get().EmailImap == nil {
 			return
 		}
 
@@ -66,42 +66,17 @@ func workForever(ctx context.Context) {
 			return errors.Wrap(err, "ReadUnread")
 		}
 		for msg := range ch {
-			// 🚨 SECURITY: Check that one of the messages "to" addresses
-			// includes a valid sub-address authorization token. e.g.
-			// "notifications+SomeSecret123@sourcegraph.com". This guarantees
-			// that this email came from the user we sent the notification to
-			// previously (whereas e.g. relying on the "From" address field
-			// would be completely insecure doing to being easily spoofed).
-			//
-			// See https://tools.ietf.org/html/rfc5233 for details on sub-addressing.
-			var (
-				haveAuthorization bool
-				userID            int32
-				threadID          int64
-			)
-			for _, toAddress := range msg.Envelope.To {
-				// Parse the token ("SomeSecret123") out of the mailbox name ("notifications+SomeSecret123").
-				split := strings.Split(toAddress.MailboxName, "+")
-				if len(split) < 2 {
-					continue
-				}
-				token := split[len(split)-1]
-
-				// Verify the token.
-				userID, threadID, err = db.DiscussionMailReplyTokens.Get(ctx, token)
-				if err == db.ErrInvalidToken {
-					log15.Debug("discussions: mailreply worker: ignoring email with invalid authorization token", "subject", msg.Envelope.Subject, "mailbox_name", toAddress.MailboxName)
-					msg.MarkSeenAndDeleted()
-					break // Invalid token / attacker
-				}
-				if err != nil {
-					log15.Error("discussions: mailreply worker: error while looking up token", "error", err)
-					continue
-				}
-				haveAuthorization = true
-				break
+			mailboxNames := make([]string, len(msg.Envelope.To))
+			for i, toAddress := range msg.Envelope.To {
+				mailboxNames[i] = toAddress.MailboxName
+			}
+			userID, threadID, err := verifyReplyToken(ctx, mailboxNames)
+			if err == db.ErrInvalidToken {
+				log15.Debug("discussions: mailreply worker: ignoring email with invalid authorization token", "subject", msg.Envelope.Subject, "mailbox_names", mailboxNames)
+				msg.MarkSeenAndDeleted()
+				continue // Invalid token / attacker
 			}
-			if !haveAuthorization {
+			if err != nil {
 				continue // ignore the message
 			}
 
@@ -111,18 +86,12 @@ func workForever(ctx context.Context) {
 				continue
 			}
 
-			contents := strings.TrimSpace(string(trimGmailReplyQuote(textContent)))
-			if contents == "" {
+			err = postReply(ctx, userID, threadID, textContent)
+			if err == errEmptyReply {
 				log15.Debug("discussions: mailreply worker: ignoring email with no effective content", "subject", msg.Envelope.Subject, "content", string(textContent))
 				msg.MarkSeenAndDeleted()
 				continue // ignore empty replies
 			}
-
-			_, err = discussions.InsecureAddCommentToThread(ctx, &types.DiscussionComment{
-				ThreadID:     threadID,
-				AuthorUserID: userID,
-				Contents:     contents,
-			})
 			if err != nil {
 				log15.Error("discussions: mailreply worker: error while adding comment to thread", "error", err)
 				continue
@@ -148,6 +117,69 @@ func workForever(ctx context.Context) {
 	}
 }
 
+var errNoReplyToken = errors.New("no reply token in recipient addresses")
+
+// verifyReplyToken verifies the reply token in the given recipient mailbox
+// names, and returns the user and discussion thread that it grants posting
+// replies as and to. It is used by both the IMAP worker and the SMTP receiver.
+//
+// 🚨 SECURITY: The token must be taken from one of the message's "to"
+// addresses, as a sub-address authorization token (e.g.
+// "notifications+SomeSecret123@sourcegraph.com"). This guarantees that the
+// email came from the user we sent the notification to previously (whereas
+// e.g. relying on the "From" address field would be completely insecure due
+// to being easily spoofed).
+//
+// See https://tools.ietf.org/html/rfc5233 for details on sub-addressing.
+//
+// It returns db.ErrInvalidToken if a mailbox name has an invalid token, and
+// errNoReplyToken (or the error from looking up a token) if none of the
+// mailbox names have a valid token.
+func verifyReplyToken(ctx context.Context, mailboxNames []string) (userID int32, threadID int64, err error) {
+	err = errNoReplyToken
+	for _, mailboxName := range mailboxNames {
+		// Parse the token ("SomeSecret123") out of the mailbox name ("notifications+SomeSecret123").
+		split := strings.Split(mailboxName, "+")
+		if len(split) < 2 {
+			continue
+		}
+		token := split[len(split)-1]
+
+		// Verify the token.
+		userID, threadID, err = db.DiscussionMailReplyTokens.Get(ctx, token)
+		if err == db.ErrInvalidToken {
+			return 0, 0, err // Invalid token / attacker
+		}
+		if err != nil {
+			log15.Error("discussions: mailreply: error while looking up token", "error", err)
+			continue
+		}
+		return userID, threadID, nil
+	}
+	return 0, 0, err
+}
+
+var errEmptyReply = errors.New("email reply has no effective content")
+
+// postReply adds the text content of an email reply (with any quotation of
+// the email being replied to trimmed) as a comment by the user to the
+// discussion thread. It returns errEmptyReply if there is nothing left to
+// post.
+//
+// The caller must have verified the reply token using verifyReplyToken.
+func postReply(ctx context.Context, userID int32, threadID int64, textContent []byte) error {
+	contents := strings.TrimSpace(string(trimGmailReplyQuote(textContent)))
+	if contents == "" {
+		return errEmptyReply
+	}
+	_, err := discussions.InsecureAddCommentToThread(ctx, &types.DiscussionComment{
+		ThreadID:     threadID,
+		AuthorUserID: userID,
+		Contents:     contents,
+	})
+	return err
+}
+
 var gmailQuoteMatch = regexp.MustCompile(`(\r\n|\n).*On .* at .*, (.|\r\n|\n)*wrote\:(.|\r\n|\n)*(\r\n|\n)+(>.*(\r\n|\n))+(.|\r\n|\n)*`)
 
 // trimGmailReplyQuote trims the gmail reply quotation out of the given
@@ -12,6 +12,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/discussions/mentions"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/markdown"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/notifications"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/errcode"
@@ -126,11 +127,6 @@ func (n *notifier) subscribers(ctx context.Context) ([]string, error) {
 }
 
 func (n *notifier) notifyUsername(ctx context.Context, username string) error {
-	if !conf.CanSendEmail() {
-		// Can't send email, so we have nothing to do.
-		return nil
-	}
-
 	user, err := db.Users.GetByUsername(ctx, username)
 	if err != nil {
 		return errors.Wrap(err, "GetByUsername")
@@ -140,6 +136,58 @@ func (n *notifier) notifyUsername(ctx context.Context, username string) error {
 		return nil
 	}
 
+	url, err := URLToInlineComment(ctx, n.thread, n.comment)
+	if err != nil {
+		return errors.Wrap(err, "URLToInlineComment")
+	}
+	if url == nil {
+		return nil // can't generate a link to this thread target type
+	}
+
+	commentAuthor, err := db.Users.GetByID(ctx, n.comment.AuthorUserID)
+	if err != nil {
+		return errors.Wrap(err, "CommentAuthor: GetByID")
+	}
+
+	kind, title := n.describe(username, commentAuthor.Username)
+	return notifications.Notify(ctx, user.ID, &notifications.Notification{
+		Kind:        kind,
+		ActorUserID: &n.eventAuthorUserID,
+		ThreadID:    &n.thread.ID,
+		CommentID:   &n.comment.ID,
+		Title:       title,
+		Body:        n.comment.Contents,
+		URL:         url,
+		SendEmail: func(ctx context.Context, userID int32, url string) error {
+			return n.sendEmail(ctx, userID, commentAuthor, url)
+		},
+	})
+}
+
+// describe returns the kind of notification that the user with the given
+// username receives about the event, and the notification's title.
+func (n *notifier) describe(username, authorUsername string) (kind, title string) {
+	mentioned := mentions.Parse(n.comment.Contents)
+	if n.typ == newThreadNotification {
+		mentioned = append(mentioned, mentions.Parse(n.thread.Title)...)
+	}
+	for _, mention := range mentioned {
+		if strings.EqualFold(mention, username) {
+			return db.NotificationDiscussionMention, fmt.Sprintf("@%s mentioned you in %s", authorUsername, n.thread.Title)
+		}
+	}
+	if n.typ == newThreadNotification {
+		return db.NotificationDiscussionThread, fmt.Sprintf("@%s started a discussion: %s", authorUsername, n.thread.Title)
+	}
+	return db.NotificationDiscussionComment, fmt.Sprintf("@%s commented on %s", authorUsername, n.thread.Title)
+}
+
+func (n *notifier) sendEmail(ctx context.Context, userID int32, commentAuthor *types.User, url string) error {
+	if !conf.CanSendEmail() {
+		// Can't send email, so we have nothing to do.
+		return nil
+	}
+
 	var (
 		replyTo    *string
 		messageID  *string
@@ -152,13 +200,12 @@ func (n *notifier) notifyUsername(ctx context.Context, username string) error {
 		// 🚨 SECURITY: It is crucial that the user ID and thread ID passed here
 		// are correct, as the token effectively grants anonymous posting in the
 		// specified thread on the specified user's behalf.
-		secureToken, err := db.DiscussionMailReplyTokens.Generate(ctx, user.ID, n.thread.ID)
+		secureToken, err := db.DiscussionMailReplyTokens.Generate(ctx, userID, n.thread.ID)
 		if err != nil {
 			return errors.Wrap(err, "DiscussionMailReplyTokens.Generate")
 		}
 
-		conf := conf.Get()
-		emailParts := strings.Split(conf.EmailImap.Username, "@")
+		emailParts := strings.Split(conf.EmailReplyAddress(), "@")
 		secureReplyTo := fmt.Sprintf("%s+%s@%s", emailParts[0], secureToken, emailParts[1])
 		replyTo = &secureReplyTo
 
@@ -191,18 +238,7 @@ func (n *notifier) notifyUsername(ctx context.Context, username string) error {
 		}
 	}
 
-	url, err := URLToInlineComment(ctx, n.thread, n.comment)
-	if err != nil {
-		return errors.Wrap(err, "URLToInlineComment")
-	}
-	if url == nil {
-		return nil // can't generate a link to this thread target type
-	}
-	q := url.Query()
-	q.Set("utm_source", "email")
-	url.RawQuery = q.Encode()
-
-	email, verified, err := db.UserEmails.GetPrimaryEmail(ctx, user.ID)
+	email, verified, err := db.UserEmails.GetPrimaryEmail(ctx, userID)
 	if err != nil && !errcode.IsNotFound(err) {
 		return errors.Wrap(err, "GetPrimaryEmail")
 	}
@@ -238,10 +274,6 @@ func (n *notifier) notifyUsername(ctx context.Context, username string) error {
 		}
 	}
 
-	commentAuthor, err := db.Users.GetByID(ctx, n.comment.AuthorUserID)
-	if err != nil {
-		return errors.Wrap(err, "CommentAuthor: GetByID")
-	}
 	fromName := commentAuthor.DisplayName
 	if fromName == "" {
 		fromName = commentAuthor.Username
@@ -273,7 +305,7 @@ func (n *notifier) notifyUsername(ctx context.Context, username string) error {
 			CommentAuthorUsername: commentAuthor.Username,
 			CommentContents:       n.comment.Contents,
 			CommentContentsHTML:   template.HTML(markdown.Render(n.comment.Contents, nil)),
-			URL:                   url.String(),
+			URL:                   url,
 			UniqueValue:           fmt.Sprint(n.comment.ID),
 			CanReply:              conf.CanReadEmail(),
 
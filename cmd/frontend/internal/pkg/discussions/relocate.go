@@ -0,0 +1,298 @@
+package discussions
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/go-diff/diff"
+)
+
+// RelocationStatus describes what happened to a selection at another revision
+// of its file.
+type RelocationStatus int
+
+const (
+	// RelocationUnchanged means that the selected lines are unchanged, although
+	// they may have moved.
+	RelocationUnchanged RelocationStatus = iota
+
+	// RelocationChanged means that the selected lines were edited, and were
+	// located by fuzzy matching.
+	RelocationChanged
+
+	// RelocationOutdated means that the selected lines could not be located
+	// (e.g. because they were removed or rewritten).
+	RelocationOutdated
+)
+
+// Selection is a selection of lines in a file, with the contents of the
+// selected lines and the lines around them (see LinesForSelection).
+type Selection struct {
+	LineRange
+	LinesBefore, Lines, LinesAfter []string
+}
+
+// Relocation is where a selection is at another revision of its file.
+type Relocation struct {
+	Status RelocationStatus
+
+	// Range is the range of the selected lines at the other revision, or nil
+	// if the selection is outdated.
+	Range *LineRange
+}
+
+const (
+	// maxFuzzyDistance is how far (in lines) from where the diff says the
+	// selected lines should be that fuzzy matching looks for them.
+	maxFuzzyDistance = 200
+
+	// minFuzzySimilarity is the minimum similarity (from 0 to 1) of the
+	// selected lines, and the minimum mean similarity of the selected lines and
+	// of the lines around them, for a fuzzy match.
+	minFuzzySimilarity = 0.6
+
+	// minMovedContextSimilarity is the minimum similarity of the lines around
+	// the selected lines for an exact match of the selected lines that is not
+	// unique in the file.
+	minMovedContextSimilarity = 0.5
+)
+
+// RelocateSelection returns where sel (a selection in the old file) is in the
+// new file, given the hunks of the diff between the old and new files and the
+// contents of the new file. If the file is unchanged, hunks is empty.
+//
+// The selected lines are first mapped through the diff. If any of them
+// changed, they are searched for in the new file (see FindSelection).
+func RelocateSelection(sel Selection, hunks []*diff.Hunk, newContent string) Relocation {
+	startLine, unchanged := mapOldLine(hunks, sel.StartLine)
+	for line := sel.StartLine + 1; unchanged && line < sel.EndLine; line++ {
+		newLine, ok := mapOldLine(hunks, line)
+		unchanged = ok && newLine == startLine+(line-sel.StartLine)
+	}
+	if unchanged {
+		endLine := startLine
+		if sel.EndLine > sel.StartLine {
+			endLine += sel.EndLine - sel.StartLine
+		}
+		return Relocation{Status: RelocationUnchanged, Range: &LineRange{StartLine: startLine, EndLine: endLine}}
+	}
+	return FindSelection(sel, newContent, startLine)
+}
+
+// hunkStart returns the zero-based line at which a side of a hunk starts.
+// Hunk start lines are one-based, except for empty sides (whose start line is
+// the line before the hunk).
+func hunkStart(startLine, lines int32) int {
+	if lines == 0 {
+		return int(startLine)
+	}
+	return int(startLine) - 1
+}
+
+// mapOldLine returns the line of the new file that the old file's line maps
+// to, and whether the line is unchanged. For a removed line, the returned line
+// is where the line would have been in the new file.
+func mapOldLine(hunks []*diff.Hunk, line int) (int, bool) {
+	delta := 0
+	for _, hunk := range hunks {
+		oldStart, newStart := hunkStart(hunk.OrigStartLine, hunk.OrigLines), hunkStart(hunk.NewStartLine, hunk.NewLines)
+		if line < oldStart {
+			break
+		}
+		if line < oldStart+int(hunk.OrigLines) {
+			// The line is in this hunk.
+			next := newStart
+			for _, l := range diffLines([]*diff.Hunk{hunk}) {
+				if l.old == line {
+					if l.new == -1 {
+						return next, false
+					}
+					return l.new, true
+				}
+				if l.new != -1 {
+					next = l.new + 1
+				}
+			}
+		}
+		delta = (newStart + int(hunk.NewLines)) - (oldStart + int(hunk.OrigLines))
+	}
+	return line + delta, true
+}
+
+// FindSelection searches for the selected lines of sel in newContent, and
+// returns where they are.
+//
+// If the selected lines are in the file exactly (and unambiguously, judging by
+// the lines around them; see MatchSelection), they are unchanged. Otherwise, the most similar
+// lines near the expected line are a fuzzy match, if they are similar enough.
+// Matches closer to the expected line are preferred.
+func FindSelection(sel Selection, newContent string, expectedLine int) Relocation {
+	if len(sel.Lines) == 0 {
+		return Relocation{Status: RelocationOutdated}
+	}
+	newLines := strings.Split(newContent, "\n")
+	n := len(sel.Lines)
+
+	// MatchSelection may match only the lines around the selected lines, so
+	// check that the selected lines are unchanged.
+	if r := MatchSelection(sel, newContent); r != nil && r.StartLine >= 0 && r.StartLine+n <= len(newLines) && equalLines(sel.Lines, newLines[r.StartLine:r.StartLine+n]) {
+		return Relocation{Status: RelocationUnchanged, Range: &LineRange{StartLine: r.StartLine, EndLine: r.StartLine + n}}
+	}
+
+	contextSimilarity := func(line int) float64 {
+		before := len(sel.LinesBefore)
+		if before+len(sel.LinesAfter) == 0 {
+			return 1
+		}
+		var sum float64
+		for i, l := range sel.LinesBefore {
+			if j := line - before + i; j >= 0 {
+				sum += lineSimilarity(l, newLines[j])
+			}
+		}
+		for i, l := range sel.LinesAfter {
+			if j := line + n + i; j < len(newLines) {
+				sum += lineSimilarity(l, newLines[j])
+			}
+		}
+		return sum / float64(before+len(sel.LinesAfter))
+	}
+	distance := func(line int) int {
+		if line < expectedLine {
+			return expectedLine - line
+		}
+		return line - expectedLine
+	}
+	found := func(status RelocationStatus, line int) Relocation {
+		return Relocation{Status: status, Range: &LineRange{StartLine: line, EndLine: line + n}}
+	}
+
+	// Look for the selected lines anywhere in the file (they may have moved).
+	var (
+		exactMatches     int
+		bestExact        = -1
+		bestExactContext float64
+	)
+	for line := 0; line+n <= len(newLines); line++ {
+		if !equalLines(sel.Lines, newLines[line:line+n]) {
+			continue
+		}
+		exactMatches++
+		similarity := contextSimilarity(line)
+		if bestExact == -1 || similarity > bestExactContext || (similarity == bestExactContext && distance(line) < distance(bestExact)) {
+			bestExact, bestExactContext = line, similarity
+		}
+	}
+	if exactMatches == 1 || (exactMatches > 1 && bestExactContext >= minMovedContextSimilarity) {
+		return found(RelocationUnchanged, bestExact)
+	}
+
+	// Look for similar lines near where the selected lines should be.
+	var (
+		bestFuzzy      = -1
+		bestFuzzyScore float64
+	)
+	for line := expectedLine - maxFuzzyDistance; line <= expectedLine+maxFuzzyDistance; line++ {
+		if line < 0 || line+n > len(newLines) {
+			continue
+		}
+		var sum float64
+		for i, l := range sel.Lines {
+			sum += lineSimilarity(l, newLines[line+i])
+		}
+		similarity := sum / float64(n)
+		if similarity < minFuzzySimilarity {
+			continue
+		}
+		score := (similarity + contextSimilarity(line)) / 2
+		if score < minFuzzySimilarity {
+			continue
+		}
+		if bestFuzzy == -1 || score > bestFuzzyScore || (score == bestFuzzyScore && distance(line) < distance(bestFuzzy)) {
+			bestFuzzy, bestFuzzyScore = line, score
+		}
+	}
+	if bestFuzzy != -1 {
+		return found(RelocationChanged, bestFuzzy)
+	}
+	return Relocation{Status: RelocationOutdated}
+}
+
+// minMatchLines is the minimum number of consecutive lines (of the selected
+// lines and the lines around them) that MatchSelection searches for.
+const minMatchLines = 4
+
+// MatchSelection searches for the selected lines of sel, together with the
+// lines around them, in newContent. If they are not found exactly once, lines
+// are removed from the top (or else the bottom) until they are, or until fewer
+// than 4 lines remain. It returns where the selected lines are, or nil if no
+// match was found.
+//
+// The selected lines themselves may have changed if only the lines around them
+// matched.
+func MatchSelection(sel Selection, newContent string) *LineRange {
+	search := func(searchForLines []string) (int, bool) {
+		if len(searchForLines) < minMatchLines {
+			// We do not have enough search lines to find a good match.
+			return 0, false
+		}
+		s := strings.Join(searchForLines, "\n")
+		if strings.Count(newContent, s) != 1 {
+			// The lines we are searching for produced no matches or too many
+			// matches.
+			return 0, false
+		}
+		return strings.Count(newContent[:strings.Index(newContent, s)], "\n"), true
+	}
+	found := func(startLine int) *LineRange {
+		return &LineRange{StartLine: startLine, EndLine: startLine + len(sel.Lines)}
+	}
+
+	allLines := append(append(append([]string{}, sel.LinesBefore...), sel.Lines...), sel.LinesAfter...)
+	for removeLines := 0; removeLines <= len(allLines); removeLines++ {
+		// Try removing N lines from the top.
+		if line, ok := search(allLines[removeLines:]); ok {
+			return found(line + len(sel.LinesBefore) - removeLines)
+		}
+
+		// Try removing N lines from the bottom.
+		if line, ok := search(allLines[:len(allLines)-removeLines]); ok {
+			return found(line + len(sel.LinesBefore))
+		}
+	}
+	return nil
+}
+
+func equalLines(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// lineSimilarity returns the similarity of two lines from 0 (different) to 1
+// (equal, ignoring leading and trailing whitespace), using the Sørensen–Dice
+// coefficient of their character bigrams.
+func lineSimilarity(a, b string) float64 {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	if a == b {
+		return 1
+	}
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < 2 || len(rb) < 2 {
+		return 0
+	}
+	bigrams := make(map[[2]rune]int, len(ra)-1)
+	for i := 0; i+1 < len(ra); i++ {
+		bigrams[[2]rune{ra[i], ra[i+1]}]++
+	}
+	var common int
+	for i := 0; i+1 < len(rb); i++ {
+		if bg := [2]rune{rb[i], rb[i+1]}; bigrams[bg] > 0 {
+			bigrams[bg]--
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(ra)-1+len(rb)-1)
+}
@@ -0,0 +1,182 @@
+package discussions
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/go-diff/diff"
+)
+
+func TestRelocateSelection(t *testing.T) {
+	oldContent := "package a\n\nimport \"fmt\"\n\nfunc hello(name string) {\n\tfmt.Println(\"hello\", name)\n}\n\nfunc bye() {\n\tfmt.Println(\"bye\")\n}\n"
+	sel := func(startLine, endLine int) Selection {
+		linesBefore, lines, linesAfter := LinesForSelection(oldContent, LineRange{StartLine: startLine, EndLine: endLine})
+		return Selection{LineRange: LineRange{StartLine: startLine, EndLine: endLine}, LinesBefore: linesBefore, Lines: lines, LinesAfter: linesAfter}
+	}
+
+	tests := []struct {
+		name       string
+		sel        Selection
+		hunks      []*diff.Hunk
+		newContent string
+		want       Relocation
+	}{
+		{
+			name:       "file unchanged",
+			sel:        sel(4, 7),
+			newContent: oldContent,
+			want:       Relocation{Status: RelocationUnchanged, Range: &LineRange{StartLine: 4, EndLine: 7}},
+		},
+		{
+			name: "lines added before",
+			sel:  sel(4, 7),
+			hunks: []*diff.Hunk{{
+				OrigStartLine: 1, OrigLines: 3, NewStartLine: 1, NewLines: 4,
+				Body: []byte(" package a\n \n+// Package a says hello.\n import \"fmt\"\n"),
+			}},
+			newContent: "package a\n\n// Package a says hello.\nimport \"fmt\"\n\nfunc hello(name string) {\n\tfmt.Println(\"hello\", name)\n}\n\nfunc bye() {\n\tfmt.Println(\"bye\")\n}\n",
+			want:       Relocation{Status: RelocationUnchanged, Range: &LineRange{StartLine: 5, EndLine: 8}},
+		},
+		{
+			name: "lines removed after",
+			sel:  sel(4, 7),
+			hunks: []*diff.Hunk{{
+				OrigStartLine: 7, OrigLines: 5, NewStartLine: 7, NewLines: 1,
+				Body: []byte(" }\n-\n-func bye() {\n-\tfmt.Println(\"bye\")\n-}\n"),
+			}},
+			newContent: "package a\n\nimport \"fmt\"\n\nfunc hello(name string) {\n\tfmt.Println(\"hello\", name)\n}\n",
+			want:       Relocation{Status: RelocationUnchanged, Range: &LineRange{StartLine: 4, EndLine: 7}},
+		},
+		{
+			name: "selected line edited",
+			sel:  sel(5, 6),
+			hunks: []*diff.Hunk{{
+				OrigStartLine: 5, OrigLines: 3, NewStartLine: 5, NewLines: 3,
+				Body: []byte(" func hello(name string) {\n-\tfmt.Println(\"hello\", name)\n+\tfmt.Println(\"hello,\", name)\n }\n"),
+			}},
+			newContent: "package a\n\nimport \"fmt\"\n\nfunc hello(name string) {\n\tfmt.Println(\"hello,\", name)\n}\n\nfunc bye() {\n\tfmt.Println(\"bye\")\n}\n",
+			want:       Relocation{Status: RelocationChanged, Range: &LineRange{StartLine: 5, EndLine: 6}},
+		},
+		{
+			name: "selected lines moved",
+			sel:  sel(8, 11),
+			hunks: []*diff.Hunk{{
+				OrigStartLine: 4, OrigLines: 8, NewStartLine: 4, NewLines: 8,
+				Body: []byte(" \n+func bye() {\n+\tfmt.Println(\"bye\")\n+}\n+\n func hello(name string) {\n \tfmt.Println(\"hello\", name)\n }\n-\n-func bye() {\n-\tfmt.Println(\"bye\")\n-}\n"),
+			}},
+			newContent: "package a\n\nimport \"fmt\"\n\nfunc bye() {\n\tfmt.Println(\"bye\")\n}\n\nfunc hello(name string) {\n\tfmt.Println(\"hello\", name)\n}\n",
+			want:       Relocation{Status: RelocationUnchanged, Range: &LineRange{StartLine: 4, EndLine: 7}},
+		},
+		{
+			name: "selected lines removed",
+			sel:  sel(8, 11),
+			hunks: []*diff.Hunk{{
+				OrigStartLine: 7, OrigLines: 5, NewStartLine: 7, NewLines: 1,
+				Body: []byte(" }\n-\n-func bye() {\n-\tfmt.Println(\"bye\")\n-}\n"),
+			}},
+			newContent: "package a\n\nimport \"fmt\"\n\nfunc hello(name string) {\n\tfmt.Println(\"hello\", name)\n}\n",
+			want:       Relocation{Status: RelocationOutdated},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := RelocateSelection(test.sel, test.hunks, test.newContent)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v (range %+v), want %+v (range %+v)", got, got.Range, test.want, test.want.Range)
+			}
+		})
+	}
+}
+
+func TestFindSelection(t *testing.T) {
+	sel := Selection{
+		LineRange:   LineRange{StartLine: 3, EndLine: 4},
+		LinesBefore: []string{"a", "b", "c"},
+		Lines:       []string{"}"},
+		LinesAfter:  []string{"d", "e", "f"},
+	}
+	tests := []struct {
+		name         string
+		newContent   []string
+		expectedLine int
+		want         Relocation
+	}{
+		{
+			name:       "ambiguous exact match, disambiguated by context",
+			newContent: []string{"}", "x", "a", "b", "c", "}", "d", "e", "f", "}"},
+			want:       Relocation{Status: RelocationUnchanged, Range: &LineRange{StartLine: 5, EndLine: 6}},
+		},
+		{
+			name:       "ambiguous exact match without context",
+			newContent: []string{"}", "x", "y", "}"},
+			want:       Relocation{Status: RelocationOutdated},
+		},
+		{
+			name:         "selected line changed, lines around it unchanged",
+			newContent:   []string{"a", "b", "c", "} // x", "d", "e", "f"},
+			expectedLine: 3,
+			want:         Relocation{Status: RelocationOutdated},
+		},
+		{
+			name:         "not found",
+			newContent:   []string{"x", "y", "z"},
+			expectedLine: 1,
+			want:         Relocation{Status: RelocationOutdated},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := FindSelection(sel, strings.Join(test.newContent, "\n"), test.expectedLine)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v (range %+v), want %+v (range %+v)", got, got.Range, test.want, test.want.Range)
+			}
+		})
+	}
+}
+
+func TestMatchSelection(t *testing.T) {
+	sel := Selection{
+		LineRange:   LineRange{StartLine: 3, EndLine: 4},
+		LinesBefore: []string{"0", "1", "2"},
+		Lines:       []string{"3"},
+		LinesAfter:  []string{"4", "5", "6"},
+	}
+	tests := []struct {
+		name       string
+		newContent string
+		want       *LineRange
+	}{
+		{name: "unchanged", newContent: "0\n1\n2\n3\n4\n5\n6", want: &LineRange{StartLine: 3, EndLine: 4}},
+		{name: "added lines before", newContent: "a\nb\n0\n1\n2\n3\n4\n5\n6", want: &LineRange{StartLine: 5, EndLine: 6}},
+		{name: "removed lines before", newContent: "3\n4\n5\n6", want: &LineRange{StartLine: 0, EndLine: 1}},
+		{name: "removed lines after", newContent: "a\n0\n1\n2\n3\n", want: &LineRange{StartLine: 4, EndLine: 5}},
+		{name: "ambiguous", newContent: "0\n1\n2\n3\n4\n5\n6\n0\n1\n2\n3\n4\n5\n6", want: nil},
+		{name: "not found", newContent: "0\n2\n3\n1\n", want: nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := MatchSelection(sel, test.newContent); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLineSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"foo", "foo", 1},
+		{"  foo", "foo\t", 1},
+		{"abcd", "wxyz", 0},
+		{"abcd", "abce", 2.0 / 3},
+		{"a", "b", 0},
+	}
+	for _, test := range tests {
+		if got := lineSimilarity(test.a, test.b); got != test.want {
+			t.Errorf("lineSimilarity(%q, %q): got %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
@@ -13,7 +13,8 @@ import (
 )
 
 // URLToInlineThread returns a URL to the discussion thread's 'inline' view
-// (i.e. the filepath/blob view).
+// (i.e. the filepath/blob view, or the commit/comparison view for threads on a
+// diff).
 //
 // Returns nil, nil if the thread does not have an inline thread view. e.g.,
 // for threads created not on a file but on something else.
@@ -22,7 +23,8 @@ func URLToInlineThread(ctx context.Context, thread *types.DiscussionThread) (*ur
 }
 
 // URLToInlineComment returns a URL to the discussion thread comment's 'inline'
-// view (i.e. the filepath/blob view).
+// view (i.e. the filepath/blob view, or the commit/comparison view for threads
+// on a diff).
 //
 // Returns nil, nil if the thread does not have an inline thread view. e.g.,
 // for threads created not on a file but on something else.
@@ -31,6 +33,20 @@ func URLToInlineComment(ctx context.Context, thread *types.DiscussionThread, com
 }
 
 func urlToInline(ctx context.Context, t *types.DiscussionThread, c *types.DiscussionComment) (*url.URL, error) {
+	if t.TargetRepo == nil {
+		return nil, nil // can't generate a link to this target type
+	}
+	repo, err := db.Repos.Get(ctx, t.TargetRepo.RepoID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db.Repos.Get")
+	}
+	return URLToInlineInRepo(repo, t, c), nil
+}
+
+// URLToInlineInRepo is like URLToInlineComment (or URLToInlineThread, if c is
+// nil), except that it doesn't look up the thread's target repository. The
+// repo must be the thread's target repository.
+func URLToInlineInRepo(repo *types.Repo, t *types.DiscussionThread, c *types.DiscussionComment) *url.URL {
 	var u *url.URL
 	switch {
 	case t.TargetRepo != nil:
@@ -38,14 +54,16 @@ func urlToInline(ctx context.Context, t *types.DiscussionThread, c *types.Discus
 		// - repo renames
 		// - file paths not existing on the default branch (or at all).
 
-		repo, err := db.Repos.Get(ctx, t.TargetRepo.RepoID)
-		if err != nil {
-			return nil, errors.Wrap(err, "db.Repos.Get")
-		}
-		if t.TargetRepo.Path == nil {
-			return nil, nil // Can't generate a link to this yet, we don't have a UI for it yet.
+		switch {
+		case t.TargetRepo.DiffBaseRevision != nil:
+			u = &url.URL{Path: path.Join("/", string(repo.Name), "/-/compare/", *t.TargetRepo.DiffBaseRevision+"..."+*t.TargetRepo.DiffHeadRevision)}
+		case t.TargetRepo.DiffHeadRevision != nil:
+			u = &url.URL{Path: path.Join("/", string(repo.Name), "/-/commit/", *t.TargetRepo.DiffHeadRevision)}
+		case t.TargetRepo.Path == nil:
+			return nil // Can't generate a link to this yet, we don't have a UI for it yet.
+		default:
+			u = &url.URL{Path: path.Join("/", string(repo.Name), "/-/blob/", *t.TargetRepo.Path)}
 		}
-		u = &url.URL{Path: path.Join("/", string(repo.Name), "/-/blob/", *t.TargetRepo.Path)}
 
 		fragment := url.Values{}
 		fragment.Set("tab", "discussions")
@@ -54,12 +72,12 @@ func urlToInline(ctx context.Context, t *types.DiscussionThread, c *types.Discus
 			fragment.Set("commentID", strconv.FormatInt(c.ID, 10))
 		}
 		encFragment := fragment.Encode()
-		if t.TargetRepo.StartLine != nil {
+		if t.TargetRepo.StartLine != nil && !t.TargetRepo.HasDiff() {
 			encFragment = fmt.Sprintf("L%d&%s", *t.TargetRepo.StartLine+1, encFragment)
 		}
 		u.Fragment = encFragment
 	default:
-		return nil, nil // can't generate a link to this target type
+		return nil // can't generate a link to this target type
 	}
-	return u, nil
+	return u
 }
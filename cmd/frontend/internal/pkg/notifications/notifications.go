@@ -0,0 +1,98 @@
+// Package notifications delivers notifications of events to users on the channels (in-app, email,
+// and Slack) that they have enabled in their notification settings.
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/pkg/slack"
+)
+
+// Notification is a notification of an event, to be delivered to a user.
+type Notification struct {
+	Kind        string // the kind of event (e.g., db.NotificationDiscussionComment)
+	ActorUserID *int32 // the user who caused the event, if any
+	ThreadID    *int64 // the discussion thread the event occurred in, if any
+	CommentID   *int64 // the discussion comment the event refers to, if any
+	Title       string // a short plain-text summary of the event (e.g., "@alice commented on Foo")
+	Body        string // an optional plain-text excerpt (e.g., of a comment's contents)
+
+	// URL is the absolute URL to the subject of the event. The channel the notification was
+	// delivered on is added to it as the utm_source query parameter.
+	URL *url.URL
+
+	// SendEmail, if set, sends the notification to the user by email. It is only called if the
+	// user has enabled email notifications. Callers provide it because emails are richer than
+	// the other channels' messages (e.g., they may be replied to).
+	SendEmail func(ctx context.Context, userID int32, url string) error
+}
+
+// Notify delivers the notification to the user on each channel that the user has enabled. It
+// attempts every channel even if delivery on one fails, and returns the errors for all channels
+// that failed.
+//
+// Callers are responsible for not notifying users of events that they may not see.
+func Notify(ctx context.Context, userID int32, n *Notification) error {
+	settings, err := db.UserNotificationSettings.GetByUserID(ctx, userID)
+	if err != nil {
+		return errors.Wrap(err, "UserNotificationSettings.GetByUserID")
+	}
+
+	var result error
+	if settings.InApp {
+		if err := db.Notifications.Create(ctx, &db.Notification{
+			UserID:      userID,
+			Kind:        n.Kind,
+			ActorUserID: n.ActorUserID,
+			ThreadID:    n.ThreadID,
+			CommentID:   n.CommentID,
+			Title:       n.Title,
+			Body:        n.Body,
+			URL:         n.urlWithSource("notification"),
+		}); err != nil {
+			result = multierror.Append(result, errors.Wrap(err, "Notifications.Create"))
+		}
+	}
+	if settings.Email && n.SendEmail != nil {
+		if err := n.SendEmail(ctx, userID, n.urlWithSource("email")); err != nil {
+			result = multierror.Append(result, errors.Wrap(err, "email"))
+		}
+	}
+	if settings.SlackWebhookURL != nil && *settings.SlackWebhookURL != "" {
+		if err := slack.Post(n.slackPayload(), *settings.SlackWebhookURL); err != nil {
+			result = multierror.Append(result, errors.Wrap(err, "Slack"))
+		}
+	}
+	return result
+}
+
+func (n *Notification) urlWithSource(source string) string {
+	u := *n.URL
+	q := u.Query()
+	q.Set("utm_source", source)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func (n *Notification) slackPayload() *slack.Payload {
+	text := fmt.Sprintf("<%s|%s>", n.urlWithSource("slack"), slackEscape(n.Title))
+	if n.Body != "" {
+		text += "\n>>>" + slackEscape(n.Body)
+	}
+	return &slack.Payload{
+		Username:  "sourcegraph-bot",
+		IconEmoji: ":bell:",
+		Text:      text,
+	}
+}
+
+// slackEscape escapes the characters that have special meaning in Slack message text.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,97 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/pkg/slack"
+)
+
+func TestNotify(t *testing.T) {
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	var slackPayloads []*slack.Payload
+	slackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p slack.Payload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Error(err)
+		}
+		slackPayloads = append(slackPayloads, &p)
+	}))
+	defer slackServer.Close()
+
+	u, err := url.Parse("https://example.com/r/-/blob/f#tab=discussions&threadID=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := &Notification{
+		Kind:  db.NotificationDiscussionMention,
+		Title: "@alice mentioned you in <Foo>",
+		Body:  "hi @bob",
+		URL:   u,
+	}
+
+	tests := []struct {
+		name                 string
+		settings             *db.UserNotificationSettings
+		wantInApp, wantEmail bool
+		wantSlack            bool
+	}{
+		{
+			name:      "default",
+			settings:  db.DefaultUserNotificationSettings(1),
+			wantInApp: true,
+			wantEmail: true,
+		},
+		{
+			name:      "Slack only",
+			settings:  &db.UserNotificationSettings{UserID: 1, SlackWebhookURL: &slackServer.URL},
+			wantSlack: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			slackPayloads = nil
+			db.Mocks.UserNotificationSettings.GetByUserID = func(context.Context, int32) (*db.UserNotificationSettings, error) {
+				return test.settings, nil
+			}
+			var created *db.Notification
+			db.Mocks.Notifications.Create = func(_ context.Context, n *db.Notification) error {
+				created = n
+				return nil
+			}
+			var emailURL string
+			n.SendEmail = func(_ context.Context, userID int32, url string) error {
+				emailURL = url
+				return nil
+			}
+
+			if err := Notify(context.Background(), 1, n); err != nil {
+				t.Fatal(err)
+			}
+			if test.wantInApp != (created != nil) {
+				t.Errorf("got in-app notification %+v, want %v", created, test.wantInApp)
+			} else if created != nil && (created.UserID != 1 || created.Title != n.Title) {
+				t.Errorf("got in-app notification %+v", created)
+			}
+			if want := "https://example.com/r/-/blob/f?utm_source=email#tab=discussions&threadID=1"; test.wantEmail && emailURL != want {
+				t.Errorf("got email URL %q, want %q", emailURL, want)
+			} else if !test.wantEmail && emailURL != "" {
+				t.Error("got email, want none")
+			}
+			if test.wantSlack {
+				want := "<https://example.com/r/-/blob/f?utm_source=slack#tab=discussions&threadID=1|@alice mentioned you in &lt;Foo&gt;>\n>>>hi @bob"
+				if len(slackPayloads) != 1 || slackPayloads[0].Text != want {
+					t.Errorf("got Slack payloads %+v, want one with text %q", slackPayloads, want)
+				}
+			} else if len(slackPayloads) != 0 {
+				t.Errorf("got Slack payloads %+v, want none", slackPayloads)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Package totp implements time-based one-time passwords (TOTP, RFC 6238) as used by authenticator
+// apps such as Google Authenticator.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// period is the time step (in seconds) for which each code is valid.
+	period = 30
+
+	// digits is the number of digits in each code.
+	digits = 6
+
+	// skew is the number of time steps before and after the current time step whose codes are
+	// also accepted, to allow for clock drift and for the time it takes the user to enter the code.
+	skew = 1
+
+	// secretSize is the size (in bytes) of generated secrets (160 bits, as recommended by RFC 4226).
+	secretSize = 20
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a new random secret, encoded in unpadded base32 (which is the encoding
+// that authenticator apps expect).
+func GenerateSecret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+// URL returns the otpauth:// URL for the secret, which authenticator apps accept (usually as a QR
+// code) to add an account.
+func URL(issuer, accountName, secret string) string {
+	u := url.URL{
+		Scheme: "otpauth",
+		Host:   "totp",
+		Path:   "/" + issuer + ":" + accountName,
+		RawQuery: url.Values{
+			"secret":    []string{secret},
+			"issuer":    []string{issuer},
+			"algorithm": []string{"SHA1"},
+			"digits":    []string{fmt.Sprint(digits)},
+			"period":    []string{fmt.Sprint(period)},
+		}.Encode(),
+	}
+	return u.String()
+}
+
+// Validate reports whether code is a valid code for the secret at time t. If so, it also returns the
+// time step counter that the code is for. Callers must record the counter and reject codes whose
+// counter is not greater than the last one used, to prevent a code from being used more than once.
+//
+// 🚨 SECURITY: The comparison is performed in constant time.
+func Validate(secret, code string, t time.Time) (counter int64, ok bool) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil || len(code) != digits {
+		return 0, false
+	}
+	current := t.Unix() / period
+	for c := current - skew; c <= current+skew; c++ {
+		if subtle.ConstantTimeCompare([]byte(generateCode(key, c)), []byte(code)) == 1 {
+			return c, true
+		}
+	}
+	return 0, false
+}
+
+// Code returns the code for the secret at time t. It is intended for use in tests.
+func Code(secret string, t time.Time) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", err
+	}
+	return generateCode(key, t.Unix()/period), nil
+}
+
+// generateCode implements the HOTP algorithm (RFC 4226 section 5.3).
+func generateCode(key []byte, counter int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0F
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7FFFFFFF
+	return fmt.Sprintf("%0*d", digits, value%1000000)
+}
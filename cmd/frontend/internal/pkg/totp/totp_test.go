@@ -0,0 +1,98 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 test secret from RFC 6238 Appendix B.
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// The test vectors in RFC 6238 Appendix B are 8-digit codes; the last 6 digits are the 6-digit
+	// codes.
+	tests := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, want := range tests {
+		got, err := Code(rfcSecret, time.Unix(unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("at %d: got %q, want %q", unix, got, want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	code, err := Code(rfcSecret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("current code", func(t *testing.T) {
+		counter, ok := Validate(rfcSecret, code, now)
+		if !ok {
+			t.Fatal("!ok")
+		}
+		if want := now.Unix() / period; counter != want {
+			t.Errorf("got counter %d, want %d", counter, want)
+		}
+	})
+
+	t.Run("previous and next time steps", func(t *testing.T) {
+		for _, d := range []time.Duration{-period * time.Second, period * time.Second} {
+			if _, ok := Validate(rfcSecret, code, now.Add(d)); !ok {
+				t.Errorf("%s: !ok", d)
+			}
+		}
+	})
+
+	t.Run("expired code", func(t *testing.T) {
+		if _, ok := Validate(rfcSecret, code, now.Add(3*period*time.Second)); ok {
+			t.Error("ok")
+		}
+	})
+
+	t.Run("wrong code", func(t *testing.T) {
+		for _, code := range []string{"", "000000", "1234567", "abcdef"} {
+			if _, ok := Validate(rfcSecret, code, now); ok {
+				t.Errorf("%q: ok", code)
+			}
+		}
+	})
+
+	t.Run("generated secret", func(t *testing.T) {
+		secret, err := GenerateSecret()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(secret, "=") {
+			t.Errorf("secret %q is padded", secret)
+		}
+		code, err := Code(secret, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := Validate(secret, code, now); !ok {
+			t.Error("!ok")
+		}
+	})
+}
+
+func TestURL(t *testing.T) {
+	got := URL("Sourcegraph", "alice", "JBSWY3DPEHPK3PXP")
+	want := "otpauth://totp/Sourcegraph:alice?algorithm=SHA1&digits=6&issuer=Sourcegraph&period=30&secret=JBSWY3DPEHPK3PXP"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"net/textproto"
 	"strings"
@@ -12,11 +13,14 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/hooks"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/actor"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/env"
 	"github.com/sourcegraph/sourcegraph/pkg/errcode"
 	"github.com/sourcegraph/sourcegraph/pkg/redispool"
+	"github.com/sourcegraph/sourcegraph/pkg/requestclient"
 
 	log15 "gopkg.in/inconshreveable/log15.v2"
 
@@ -56,6 +60,40 @@ type sessionInfo struct {
 	Actor        *actor.Actor  `json:"actor"`
 	LastActive   time.Time     `json:"lastActive"`
 	ExpiryPeriod time.Duration `json:"expiryPeriod"`
+
+	// MFASatisfied is whether the user satisfied multi-factor authentication when signing in.
+	MFASatisfied bool `json:"mfaSatisfied,omitempty"`
+
+	// AuthProvider is the type of the auth provider that the user signed in with. It is empty for
+	// sessions created before it was recorded (and for sessions created with SetActor).
+	AuthProvider string `json:"authProvider,omitempty"`
+
+	// SessionID is the ID of the session in the user's session index (see db.UserSessions). It is 0
+	// for sessions created before the index existed.
+	SessionID int64 `json:"sessionID,omitempty"`
+}
+
+// sessionIndex is the index of users' sessions, which is used to list and revoke them. A session
+// that is not in the index is rejected. It is a variable so that tests can replace it.
+var sessionIndex interface {
+	Create(ctx context.Context, s *db.UserSession) (*db.UserSession, error)
+	GetByID(ctx context.Context, id int64) (*db.UserSession, error)
+	Touch(ctx context.Context, id int64, expiresAt time.Time, remoteAddr, userAgent string) error
+	Delete(ctx context.Context, id int64) error
+} = db.UserSessions
+
+// MFARequired, if set, reports whether the user must satisfy multi-factor authentication when
+// signing in with the auth provider (which is empty if it is unknown). Sessions of such users that
+// did not satisfy multi-factor authentication are rejected, so that the users must sign in again.
+var MFARequired func(user *types.User, authProvider string) bool
+
+type sessionIDKey struct{}
+
+// CurrentSessionID returns the ID (in the user's session index) of the session that was used to
+// authenticate the current actor, or 0 if the actor was not authenticated with a session cookie.
+func CurrentSessionID(ctx context.Context) int64 {
+	id, _ := ctx.Value(sessionIDKey{}).(int64)
+	return id
 }
 
 // SetSessionStore sets the backing store used for storing sessions on the server. It should be called exactly once.
@@ -201,6 +239,38 @@ func GetData(r *http.Request, key string, value interface{}) error {
 //
 // If expiryPeriod is 0, the default expiry period is used.
 func SetActor(w http.ResponseWriter, r *http.Request, actor *actor.Actor, expiryPeriod time.Duration) error {
+	return setActor(w, r, actor, expiryPeriod, SignInInfo{})
+}
+
+// SignInInfo describes how a user signed in. It is recorded in the session and the user's session
+// index.
+type SignInInfo struct {
+	// AuthProvider is the type of the auth provider that the user signed in with (e.g., "builtin").
+	AuthProvider string
+
+	// MFASatisfied is whether the user satisfied multi-factor authentication.
+	//
+	// 🚨 SECURITY: It must only be true if the caller verified the user's second factor.
+	MFASatisfied bool
+}
+
+// SetSignedInActor is like SetActor, but it also records how the user signed in. It should be
+// called by auth providers when a user signs in.
+func SetSignedInActor(w http.ResponseWriter, r *http.Request, actor *actor.Actor, expiryPeriod time.Duration, info SignInInfo) error {
+	return setActor(w, r, actor, expiryPeriod, info)
+}
+
+func setActor(w http.ResponseWriter, r *http.Request, actor *actor.Actor, expiryPeriod time.Duration, signIn SignInInfo) error {
+	// If the session is being replaced (by signing out or signing in again), remove the old session
+	// from the index. An error reading the old session is ignored, because the bad value will be
+	// overwritten.
+	var prev *sessionInfo
+	if hasSessionCookie(r) && GetData(r, "actor", &prev) == nil && prev != nil && prev.SessionID != 0 {
+		if err := sessionIndex.Delete(r.Context(), prev.SessionID); err != nil && !errcode.IsNotFound(err) {
+			log15.Error("Error removing replaced session from index.", "sessionID", prev.SessionID, "error", err)
+		}
+	}
+
 	var value *sessionInfo
 	if actor != nil {
 		if expiryPeriod == 0 {
@@ -210,9 +280,51 @@ func SetActor(w http.ResponseWriter, r *http.Request, actor *actor.Actor, expiry
 				expiryPeriod = defaultExpiryPeriod
 			}
 		}
-		value = &sessionInfo{Actor: actor, ExpiryPeriod: expiryPeriod, LastActive: time.Now()}
+		value = &sessionInfo{Actor: actor, ExpiryPeriod: expiryPeriod, LastActive: time.Now(), MFASatisfied: signIn.MFASatisfied, AuthProvider: signIn.AuthProvider}
+		if actor.IsAuthenticated() {
+			id, err := addToSessionIndex(r, actor.UID, expiryPeriod, signIn.AuthProvider)
+			if err != nil {
+				return err
+			}
+			value.SessionID = id
+		}
+	}
+	if err := SetData(w, r, "actor", value); err != nil {
+		return err
+	}
+	if actor.IsAuthenticated() && hooks.AfterUserSignIn != nil {
+		hooks.AfterUserSignIn(actor.UID)
+	}
+	return nil
+}
+
+// addToSessionIndex adds a new session for the user to the session index and returns its ID.
+func addToSessionIndex(r *http.Request, userID int32, expiryPeriod time.Duration, authProvider string) (int64, error) {
+	remoteAddr, userAgent := requestClientInfo(r)
+	s, err := sessionIndex.Create(r.Context(), &db.UserSession{
+		UserID:       userID,
+		ExpiresAt:    time.Now().Add(expiryPeriod),
+		RemoteAddr:   remoteAddr,
+		UserAgent:    userAgent,
+		AuthProvider: authProvider,
+	})
+	if err != nil {
+		return 0, errors.WithMessage(err, "adding session to index")
 	}
-	return SetData(w, r, "actor", value)
+	return s.ID, nil
+}
+
+// requestClientInfo returns the IP address and User-Agent of the client that made the request, to
+// record in the session index.
+func requestClientInfo(r *http.Request) (remoteAddr, userAgent string) {
+	if client := requestclient.FromContext(r.Context()); client != nil {
+		remoteAddr = client.IP
+	} else if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		remoteAddr = host
+	} else {
+		remoteAddr = r.RemoteAddr
+	}
+	return remoteAddr, r.UserAgent()
 }
 
 func hasSessionCookie(r *http.Request) bool {
@@ -330,7 +442,8 @@ func authenticateByCookie(r *http.Request, w http.ResponseWriter) context.Contex
 		}
 
 		// Check that user still exists.
-		if _, err := db.Users.GetByID(r.Context(), info.Actor.UID); err != nil {
+		user, err := db.Users.GetByID(r.Context(), info.Actor.UID)
+		if err != nil {
 			if errcode.IsNotFound(err) {
 				_ = deleteSession(w, r) // clear the bad value
 			} else {
@@ -341,17 +454,56 @@ func authenticateByCookie(r *http.Request, w http.ResponseWriter) context.Contex
 			return r.Context() // not authenticated
 		}
 
+		// 🚨 SECURITY: Check that the user was not suspended (and is not pending approval).
+		if !user.State.Active() {
+			_ = deleteSession(w, r)
+			return actor.WithActor(r.Context(), &actor.Actor{})
+		}
+
+		// 🚨 SECURITY: Check that the user satisfied multi-factor authentication if it is required
+		// (for example, because it became required after the user signed in).
+		if !info.MFASatisfied && MFARequired != nil && MFARequired(user, info.AuthProvider) {
+			_ = deleteSession(w, r)
+			return actor.WithActor(r.Context(), &actor.Actor{})
+		}
+
+		// Check that the session was not revoked. Sessions created before the session index existed
+		// are added to it.
+		renew := time.Since(info.LastActive) > 5*time.Minute
+		if info.SessionID == 0 {
+			id, err := addToSessionIndex(r, info.Actor.UID, info.ExpiryPeriod, "")
+			if err != nil {
+				log15.Error("Error adding existing session to index.", "uid", info.Actor.UID, "error", err)
+				return r.Context() // not authenticated
+			}
+			info.SessionID = id
+			renew = true
+		} else if s, err := sessionIndex.GetByID(r.Context(), info.SessionID); err != nil || s.UserID != info.Actor.UID {
+			if err == nil || errcode.IsNotFound(err) {
+				_ = deleteSession(w, r) // the session was revoked
+				return actor.WithActor(r.Context(), &actor.Actor{})
+			}
+			// Don't delete session, for the same reason as above.
+			log15.Error("Error looking up session in index.", "sessionID", info.SessionID, "error", err)
+			return r.Context() // not authenticated
+		}
+
 		// Renew session
-		if time.Since(info.LastActive) > 5*time.Minute {
+		if renew {
 			info.LastActive = time.Now()
 			if err := SetData(w, r, "actor", info); err != nil {
 				log15.Error("error renewing session", "error", err)
 				return r.Context()
 			}
+			remoteAddr, userAgent := requestClientInfo(r)
+			if err := sessionIndex.Touch(r.Context(), info.SessionID, info.LastActive.Add(info.ExpiryPeriod), remoteAddr, userAgent); err != nil {
+				log15.Error("Error updating session in index.", "sessionID", info.SessionID, "error", err)
+			}
 		}
 
 		info.Actor.FromSessionCookie = true
-		return actor.WithActor(r.Context(), info.Actor)
+		info.Actor.MFASatisfied = info.MFASatisfied
+		return context.WithValue(actor.WithActor(r.Context(), info.Actor), sessionIDKey{}, info.SessionID)
 	}
 
 	return r.Context()
@@ -170,6 +170,164 @@ func TestSessionExpiry(t *testing.T) {
 	}
 }
 
+func TestSetSignedInActor(t *testing.T) {
+	cleanup := ResetMockSessionStore(t)
+	defer cleanup()
+
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id}, nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	for _, mfa := range []bool{false, true} {
+		w := httptest.NewRecorder()
+		if err := SetSignedInActor(w, httptest.NewRequest("GET", "/", nil), &actor.Actor{UID: 123}, time.Hour, SignInInfo{AuthProvider: "builtin", MFASatisfied: mfa}); err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest("GET", "/", nil)
+		for _, cookie := range w.Result().Cookies() {
+			req.AddCookie(cookie)
+		}
+		ctx := authenticateByCookie(req, httptest.NewRecorder())
+		want := &actor.Actor{UID: 123, FromSessionCookie: true, MFASatisfied: mfa}
+		if gotActor := actor.FromContext(ctx); !reflect.DeepEqual(gotActor, want) {
+			t.Errorf("got actor %+v, want %+v", gotActor, want)
+		}
+
+		s, err := sessionIndex.GetByID(ctx, CurrentSessionID(ctx))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.UserID != 123 || s.AuthProvider != "builtin" || s.RemoteAddr != "192.0.2.1" {
+			t.Errorf("got indexed session %+v", s)
+		}
+	}
+}
+
+func TestMFARequired(t *testing.T) {
+	cleanup := ResetMockSessionStore(t)
+	defer cleanup()
+
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id}, nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	var gotAuthProvider string
+	MFARequired = func(user *types.User, authProvider string) bool {
+		gotAuthProvider = authProvider
+		return true
+	}
+	defer func() { MFARequired = nil }()
+
+	for _, mfa := range []bool{false, true} {
+		w := httptest.NewRecorder()
+		if err := SetSignedInActor(w, httptest.NewRequest("GET", "/", nil), &actor.Actor{UID: 123}, time.Hour, SignInInfo{AuthProvider: "builtin", MFASatisfied: mfa}); err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest("GET", "/", nil)
+		for _, cookie := range w.Result().Cookies() {
+			req.AddCookie(cookie)
+		}
+		// 🚨 SECURITY: Sessions that did not satisfy required MFA must not be authenticated.
+		if a := actor.FromContext(authenticateByCookie(req, httptest.NewRecorder())); a.IsAuthenticated() != mfa {
+			t.Errorf("MFA satisfied %v: got actor %+v", mfa, a)
+		}
+		if !mfa && gotAuthProvider != "builtin" {
+			t.Errorf("got auth provider %q, want %q", gotAuthProvider, "builtin")
+		}
+	}
+}
+
+func TestSessionIndex(t *testing.T) {
+	cleanup := ResetMockSessionStore(t)
+	defer cleanup()
+
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id}, nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	newSession := func() *http.Request {
+		t.Helper()
+		w := httptest.NewRecorder()
+		if err := SetActor(w, httptest.NewRequest("GET", "/", nil), &actor.Actor{UID: 123}, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+		req := httptest.NewRequest("GET", "/", nil)
+		for _, cookie := range w.Result().Cookies() {
+			req.AddCookie(cookie)
+		}
+		return req
+	}
+	authenticate := func(req *http.Request) (*actor.Actor, int64) {
+		ctx := authenticateByCookie(req, httptest.NewRecorder())
+		return actor.FromContext(ctx), CurrentSessionID(ctx)
+	}
+
+	t.Run("revoked", func(t *testing.T) {
+		req := newSession()
+		a, id := authenticate(req)
+		if !a.IsAuthenticated() || id == 0 {
+			t.Fatalf("got actor %+v with session ID %d, want authenticated", a, id)
+		}
+		if err := sessionIndex.Delete(context.Background(), id); err != nil {
+			t.Fatal(err)
+		}
+		if a, _ := authenticate(req); a.IsAuthenticated() {
+			t.Errorf("got authenticated actor %+v for revoked session", a)
+		}
+	})
+
+	t.Run("signed out", func(t *testing.T) {
+		req := newSession()
+		_, id := authenticate(req)
+		if err := SetActor(httptest.NewRecorder(), req, nil, 0); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := sessionIndex.GetByID(context.Background(), id); !errcode.IsNotFound(err) {
+			t.Errorf("got error %v, want signing out to remove the session from the index", err)
+		}
+	})
+
+	t.Run("suspended", func(t *testing.T) {
+		req := newSession()
+		if a, _ := authenticate(req); !a.IsAuthenticated() {
+			t.Fatalf("got actor %+v, want authenticated", a)
+		}
+		db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+			return &types.User{ID: id, State: types.UserStateSuspended}, nil
+		}
+		defer func() {
+			db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+				return &types.User{ID: id}, nil
+			}
+		}()
+		if a, _ := authenticate(req); a.IsAuthenticated() {
+			t.Errorf("got authenticated actor %+v for suspended user", a)
+		}
+	})
+
+	t.Run("created before the session index existed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+		if err := SetData(w, req, "actor", &sessionInfo{Actor: &actor.Actor{UID: 123}, LastActive: time.Now(), ExpiryPeriod: time.Hour}); err != nil {
+			t.Fatal(err)
+		}
+		req = httptest.NewRequest("GET", "/", nil)
+		for _, cookie := range w.Result().Cookies() {
+			req.AddCookie(cookie)
+		}
+		a, id := authenticate(req)
+		if !a.IsAuthenticated() || id == 0 {
+			t.Fatalf("got actor %+v with session ID %d, want authenticated and indexed", a, id)
+		}
+		if _, err := sessionIndex.GetByID(context.Background(), id); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
 func TestCookieMiddleware(t *testing.T) {
 	cleanup := ResetMockSessionStore(t)
 	defer cleanup()
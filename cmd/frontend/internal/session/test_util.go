@@ -1,12 +1,18 @@
 package session
 
 import (
+	"context"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gorilla/securecookie"
 	"github.com/gorilla/sessions"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
 )
 
 func ResetMockSessionStore(t *testing.T) (cleanup func()) {
@@ -23,7 +29,61 @@ func ResetMockSessionStore(t *testing.T) (cleanup func()) {
 	}()
 
 	SetSessionStore(sessions.NewFilesystemStore(tempdir, securecookie.GenerateRandomKey(2048)))
+	sessionIndex = &mockSessionIndex{sessions: map[int64]db.UserSession{}}
 	return func() {
 		os.RemoveAll(tempdir)
+		sessionIndex = db.UserSessions
 	}
 }
+
+// mockSessionIndex is an in-memory session index for tests.
+type mockSessionIndex struct {
+	mu       sync.Mutex
+	nextID   int64
+	sessions map[int64]db.UserSession
+}
+
+func (m *mockSessionIndex) Create(ctx context.Context, s *db.UserSession) (*db.UserSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	created := *s
+	created.ID = m.nextID
+	created.CreatedAt = time.Now()
+	created.LastActiveAt = created.CreatedAt
+	m.sessions[created.ID] = created
+	return &created, nil
+}
+
+func (m *mockSessionIndex) GetByID(ctx context.Context, id int64) (*db.UserSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, &errcode.Mock{Message: fmt.Sprintf("user session not found: %d", id), IsNotFound: true}
+	}
+	return &s, nil
+}
+
+func (m *mockSessionIndex) Touch(ctx context.Context, id int64, expiresAt time.Time, remoteAddr, userAgent string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if s, ok := m.sessions[id]; ok {
+		s.LastActiveAt = time.Now()
+		s.ExpiresAt = expiresAt
+		s.RemoteAddr = remoteAddr
+		s.UserAgent = userAgent
+		m.sessions[id] = s
+	}
+	return nil
+}
+
+func (m *mockSessionIndex) Delete(ctx context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.sessions[id]; !ok {
+		return &errcode.Mock{Message: fmt.Sprintf("user session not found: %d", id), IsNotFound: true}
+	}
+	delete(m.sessions, id)
+	return nil
+}
@@ -17,6 +17,10 @@ type DiscussionThread struct {
 	ArchivedAt   *time.Time
 	UpdatedAt    time.Time
 	DeletedAt    *time.Time
+
+	ResolvedAt       *time.Time
+	ResolvedByUserID *int32
+	AssigneeUserID   *int32
 }
 
 // DiscussionThreadTargetRepo mirrors the underlying discussion_threads_target_repo field types exactly.
@@ -36,6 +40,20 @@ type DiscussionThreadTargetRepo struct {
 	LinesBefore    *[]string
 	Lines          *[]string
 	LinesAfter     *[]string
+
+	// The diff fields are set for threads on the diff of a commit (only
+	// DiffHeadRevision is set) or on the diff of a comparison between two
+	// revisions. For these threads, Path is the file's path in the head
+	// revision (or the base revision if the file was deleted) and the
+	// selection fields above describe the selected lines of the new file, if
+	// any.
+	DiffBaseRevision *string
+	DiffHeadRevision *string
+	OldPath          *string
+	OldStartLine     *int32
+	OldEndLine       *int32
+	NewStartLine     *int32
+	NewEndLine       *int32
 }
 
 // HasSelection tells if the selection fields are present or not. If one field
@@ -44,6 +62,11 @@ func (d *DiscussionThreadTargetRepo) HasSelection() bool {
 	return d.StartLine != nil || d.EndLine != nil || d.StartCharacter != nil || d.EndCharacter != nil || d.LinesBefore != nil || d.Lines != nil || d.LinesAfter != nil
 }
 
+// HasDiff tells if the thread is on the diff of a commit or comparison.
+func (d *DiscussionThreadTargetRepo) HasDiff() bool {
+	return d.DiffHeadRevision != nil
+}
+
 // DiscussionComment mirrors the underlying discussion_comments field types exactly.
 // It intentionally does not try to e.g. alleviate null fields.
 type DiscussionComment struct {
@@ -56,3 +79,11 @@ type DiscussionComment struct {
 	DeletedAt    *time.Time
 	Reports      []string
 }
+
+// DiscussionCommentReaction mirrors the underlying discussion_comment_reactions field types exactly.
+type DiscussionCommentReaction struct {
+	CommentID int64
+	UserID    int32
+	Emoji     string
+	CreatedAt time.Time
+}
@@ -10,4 +10,6 @@ type SavedSearch struct {
 	UserID          *int32  // if non-nil, the owner is this user. UserID/OrgID are mutually exclusive.
 	OrgID           *int32  // if non-nil, the owner is this organization. UserID/OrgID are mutually exclusive.
 	SlackWebhookURL *string // if non-nil && NotifySlack == true, indicates that this Slack webhook URL should be used instead of the owners default Slack webhook.
+	Schedule        *string // if non-nil, the schedule on which the saved search is run (see package schedule). Otherwise a default schedule is used.
+	TimeoutSeconds  *int32  // if non-nil, the maximum number of seconds that each run of the saved search may take
 }
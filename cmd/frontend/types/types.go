@@ -63,6 +63,16 @@ type ExternalService struct {
 	DeletedAt   *time.Time
 }
 
+// FailingRepo is a repository whose most recent clone or fetch attempts have
+// all failed.
+type FailingRepo struct {
+	RepoID              api.RepoID
+	RepoName            api.RepoName
+	ConsecutiveFailures int                   // the number of failed attempts since the last successful attempt
+	LastAttempt         api.RepoUpdateAttempt // the most recent (failed) attempt
+	LastSucceeded       *time.Time            // when the last successful attempt finished, if it is still recorded
+}
+
 type GlobalState struct {
 	SiteID      string
 	Initialized bool // whether the initial site admin account has been created
@@ -78,6 +88,36 @@ type User struct {
 	UpdatedAt   time.Time
 	SiteAdmin   bool
 	Tags        []string
+	State       UserState
+}
+
+// UserState is the state of a user account.
+type UserState string
+
+const (
+	// UserStateActive is the state of a user who can sign in and use the site.
+	UserStateActive UserState = "active"
+	// UserStateSuspended is the state of a user who was suspended by a site admin. The user can't
+	// sign in or use access tokens, but their data is kept.
+	UserStateSuspended UserState = "suspended"
+	// UserStatePendingApproval is the state of a new user who must be approved by a site admin
+	// before they can sign in (see the auth.userApprovalRequired critical config property).
+	UserStatePendingApproval UserState = "pending_approval"
+)
+
+// Active reports whether a user in state s may sign in and use the site. The zero value is treated
+// as active.
+func (s UserState) Active() bool {
+	return s == "" || s == UserStateActive
+}
+
+// Valid reports whether s is a known user state.
+func (s UserState) Valid() bool {
+	switch s {
+	case UserStateActive, UserStateSuspended, UserStatePendingApproval:
+		return true
+	}
+	return false
 }
 
 type Org struct {
@@ -12,6 +12,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/gitserver/protocol"
 	log15 "gopkg.in/inconshreveable/log15.v2"
 )
@@ -25,6 +26,14 @@ func (s *Server) handleCreateCommitFromPatch(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// 🚨 SECURITY: The frontend checks that the user has write access to the repository (see
+	// backend.CheckRepoWrite). Protected repositories are additionally rejected here so that no
+	// caller can create commits in them.
+	if conf.IsRepoReadOnly(req.Repo) {
+		http.Error(w, fmt.Sprintf("gitserver: repository %s is read-only", req.Repo), http.StatusForbidden)
+		return
+	}
+
 	repo := string(protocol.NormalizeRepo(req.Repo))
 	repoGitDir := filepath.Join(s.ReposDir, repo, ".git")
 	if _, err := os.Stat(repoGitDir); os.IsNotExist(err) {
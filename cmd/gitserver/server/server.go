@@ -136,6 +136,12 @@ type Server struct {
 
 	repoUpdateLocksMu sync.Mutex // protects the map below and also updates to locks.once
 	repoUpdateLocks   map[api.RepoName]*locks
+
+	// updateAttempts is the queue of clone and fetch attempts to record in
+	// the repositories' update history. Use s.recordUpdateAttempt() instead
+	// of using it directly.
+	updateAttempts     chan queuedUpdateAttempt
+	updateAttemptsOnce sync.Once
 }
 
 type locks struct {
@@ -690,7 +696,9 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 		return "", err // err will be a context error
 	}
 	defer cancel()
+	start := time.Now()
 	if err := s.isCloneable(ctx, url); err != nil {
+		s.recordUpdateAttempt(repo, "clone", url, start, nil, err)
 		return "", fmt.Errorf("error cloning repo: repo %s (%s) not cloneable: %s", repo, url, err)
 	}
 
@@ -751,7 +759,10 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 		defer pw.Close()
 		go readCloneProgress(repo, url, lock, pr)
 
-		if output, err := s.runWithRemoteOpts(ctx, cmd, pw); err != nil {
+		start := time.Now()
+		output, err := s.runWithRemoteOpts(ctx, cmd, pw)
+		s.recordUpdateAttempt(repo, "clone", url, start, output, err)
+		if err != nil {
 			return errors.Wrapf(err, "clone failed. Output: %s", string(output))
 		}
 
@@ -1189,7 +1200,9 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 		}
 	}
 
-	cmd := exec.CommandContext(ctx, "git", "fetch", "--prune", url, "+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*", "+refs/pull/*:refs/pull/*")
+	// --progress makes git report the number of bytes received, which is
+	// recorded in the update history.
+	cmd := exec.CommandContext(ctx, "git", "fetch", "--prune", "--progress", url, "+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*", "+refs/pull/*:refs/pull/*")
 	cmd.Dir = dir
 
 	// drop temporary pack files after a fetch. this function won't
@@ -1198,7 +1211,10 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 	// when the cleanup happens, just that it does.
 	defer s.cleanTmpFiles(dir)
 
-	if output, err := s.runWithRemoteOpts(ctx, cmd, nil); err != nil {
+	start := time.Now()
+	output, err := s.runWithRemoteOpts(ctx, cmd, nil)
+	s.recordUpdateAttempt(repo, "fetch", url, start, output, err)
+	if err != nil {
 		log15.Error("Failed to update", "repo", repo, "error", err, "output", string(output))
 		return errors.Wrap(err, "failed to update")
 	}
@@ -1213,7 +1229,7 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 	// try to fetch HEAD from origin
 	cmd = exec.CommandContext(ctx, "git", "remote", "show", url)
 	cmd.Dir = path.Join(s.ReposDir, string(repo))
-	output, err := s.runWithRemoteOpts(ctx, cmd, nil)
+	output, err = s.runWithRemoteOpts(ctx, cmd, nil)
 	if err != nil {
 		log15.Error("Failed to fetch remote info", "repo", repo, "error", err, "output", string(output))
 		return errors.Wrap(err, "failed to fetch remote info")
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/mutablelimiter"
 )
 
@@ -341,6 +342,14 @@ func TestCloneRepo(t *testing.T) {
 		cloneLimiter:     mutablelimiter.New(1),
 		cloneableLimiter: mutablelimiter.New(1),
 	}
+
+	recorded := make(chan api.RepoUpdateAttempt, 10)
+	api.MockReposRecordUpdateAttempt = func(repo api.RepoName, attempt api.RepoUpdateAttempt) error {
+		recorded <- attempt
+		return nil
+	}
+	defer func() { api.MockReposRecordUpdateAttempt = nil }()
+
 	_, err := s.cloneRepo(context.Background(), "example.com/foo/bar", remote, nil)
 	if err != nil {
 		t.Fatal(err)
@@ -364,6 +373,15 @@ func TestCloneRepo(t *testing.T) {
 		t.Fatal("failed to clone")
 	}
 
+	select {
+	case a := <-recorded:
+		if a.Kind != "clone" || a.Failed() {
+			t.Fatalf("expected a successful clone attempt, got %+v", a)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the clone attempt to be recorded")
+	}
+
 	// Test blocking with a failure (already exists since we didn't specify overwrite)
 	_, err = s.cloneRepo(context.Background(), "example.com/foo/bar", remote, &cloneOptions{Block: true})
 	if !os.IsExist(errors.Cause(err)) {
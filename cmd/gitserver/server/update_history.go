@@ -0,0 +1,135 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+const (
+	// maxUpdateAttemptOutput is the number of bytes of git output recorded
+	// per attempt. The end of the output is kept, because that is where git
+	// reports errors.
+	maxUpdateAttemptOutput = 4096
+
+	// recordUpdateAttemptTimeout is how long to wait for the frontend to
+	// record an attempt before giving up.
+	recordUpdateAttemptTimeout = 10 * time.Second
+
+	// updateAttemptQueueSize is the number of attempts that can wait to be
+	// recorded. Attempts are dropped when the queue is full (e.g. because
+	// the frontend is unavailable), so that recording never holds up
+	// updates or uses unbounded memory.
+	updateAttemptQueueSize = 1000
+)
+
+type queuedUpdateAttempt struct {
+	repo    api.RepoName
+	attempt api.RepoUpdateAttempt
+}
+
+// recordUpdateAttempt records a clone or fetch attempt of repo from url that
+// started at start and produced output and err. It must be called as soon as
+// the attempt finishes, because the attempt's duration is measured up to the
+// call. The attempt is sent to the frontend, which stores it in the
+// repository's update history, in the background so that a slow or
+// unavailable frontend does not hold up updates.
+func (s *Server) recordUpdateAttempt(repo api.RepoName, kind, url string, start time.Time, output []byte, err error) {
+	s.updateAttemptsOnce.Do(func() {
+		s.updateAttempts = make(chan queuedUpdateAttempt, updateAttemptQueueSize)
+		go s.sendUpdateAttempts()
+	})
+
+	a := queuedUpdateAttempt{repo: repo, attempt: newUpdateAttempt(kind, url, start, output, err)}
+	select {
+	case s.updateAttempts <- a:
+	default:
+		log15.Warn("Dropping repository update attempt because too many are waiting to be recorded", "repo", repo, "kind", kind)
+	}
+}
+
+// sendUpdateAttempts sends the queued update attempts to the frontend, one at
+// a time.
+func (s *Server) sendUpdateAttempts() {
+	for a := range s.updateAttempts {
+		ctx, cancel := context.WithTimeout(context.Background(), recordUpdateAttemptTimeout)
+		if err := api.InternalClient.ReposRecordUpdateAttempt(ctx, a.repo, a.attempt); err != nil {
+			log15.Warn("Failed to record repository update attempt", "repo", a.repo, "kind", a.attempt.Kind, "error", err)
+		}
+		cancel()
+	}
+}
+
+// newUpdateAttempt returns the record of a clone or fetch attempt from url
+// that started at start and produced output and err.
+func newUpdateAttempt(kind, url string, start time.Time, output []byte, err error) api.RepoUpdateAttempt {
+	// 🚨 SECURITY: The output and error could include the clone url, which may
+	// contain a sensitive token.
+	redactor := newURLRedactor(url)
+
+	a := api.RepoUpdateAttempt{
+		Kind:          kind,
+		Started:       start,
+		Duration:      time.Since(start),
+		Output:        redactor.redact(updateAttemptOutput(output)),
+		BytesReceived: bytesReceived(output),
+	}
+	if err != nil {
+		a.Error = redactor.redact(err.Error())
+	}
+	return a
+}
+
+// gitProgressLine matches the progress lines that git writes when cloning or
+// fetching with --progress.
+var gitProgressLine = regexp.MustCompile(`^(remote: )?(Enumerating|Counting|Compressing|Receiving|Resolving|Checking|Total) `)
+
+// updateAttemptOutput returns the part of output worth remembering: the
+// non-progress lines, truncated to the last maxUpdateAttemptOutput bytes.
+func updateAttemptOutput(output []byte) string {
+	var buf bytes.Buffer
+	for _, line := range bytes.FieldsFunc(output, func(r rune) bool { return r == '\r' || r == '\n' }) {
+		if gitProgressLine.Match(line) {
+			continue
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	b := buf.Bytes()
+	if len(b) > maxUpdateAttemptOutput {
+		b = b[len(b)-maxUpdateAttemptOutput:]
+	}
+	return string(b)
+}
+
+// receivedObjectsSize matches the transfer size in git's "Receiving objects"
+// progress line, e.g. "Receiving objects: 100% (2148/2148), 1.21 MiB | 515.00 KiB/s, done."
+var receivedObjectsSize = regexp.MustCompile(`Receiving objects: [^\r\n]*?, ([0-9.]+) (bytes|KiB|MiB|GiB)`)
+
+// bytesReceived returns the number of bytes that git reported receiving in
+// output, or 0 if it did not report it (e.g. because nothing was fetched).
+func bytesReceived(output []byte) int64 {
+	m := receivedObjectsSize.FindAllSubmatch(output, -1)
+	if len(m) == 0 {
+		return 0
+	}
+	last := m[len(m)-1]
+	n, err := strconv.ParseFloat(string(last[1]), 64)
+	if err != nil {
+		return 0
+	}
+	switch string(last[2]) {
+	case "KiB":
+		n *= 1 << 10
+	case "MiB":
+		n *= 1 << 20
+	case "GiB":
+		n *= 1 << 30
+	}
+	return int64(n)
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+func TestRecordUpdateAttempt(t *testing.T) {
+	s := &Server{}
+	url := "https://token@github.com/foo/bar"
+	output := "Cloning into bare repository '/tmp/clone-1/.git'...\r\n" +
+		"remote: Counting objects: 100% (10/10), done.\n" +
+		"Receiving objects:  50% (5/10), 1.00 MiB | 1.00 MiB/s\r" +
+		"Receiving objects: 100% (10/10), 2.50 MiB | 1.00 MiB/s, done.\n" +
+		"fatal: unable to access '" + url + "/': The requested URL returned error: 403\n"
+
+	recorded := make(chan api.RepoUpdateAttempt, 1)
+	api.MockReposRecordUpdateAttempt = func(repo api.RepoName, attempt api.RepoUpdateAttempt) error {
+		if repo != "github.com/foo/bar" {
+			t.Errorf("got repo %q, want github.com/foo/bar", repo)
+		}
+		recorded <- attempt
+		return nil
+	}
+	defer func() { api.MockReposRecordUpdateAttempt = nil }()
+
+	s.recordUpdateAttempt("github.com/foo/bar", "clone", url, time.Now(), []byte(output), errors.New("exit status 128"))
+
+	var a api.RepoUpdateAttempt
+	select {
+	case a = <-recorded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the attempt to be recorded")
+	}
+	if !a.Failed() || a.Kind != "clone" {
+		t.Errorf("unexpected attempt %+v", a)
+	}
+	if want := int64(2.5 * (1 << 20)); a.BytesReceived != want {
+		t.Errorf("got %d bytes received, want %d", a.BytesReceived, want)
+	}
+	wantOutput := "Cloning into bare repository '/tmp/clone-1/.git'...\n" +
+		"fatal: unable to access 'https://<redacted>@github.com/foo/bar/': The requested URL returned error: 403\n"
+	if a.Output != wantOutput {
+		t.Errorf("got output %q, want %q", a.Output, wantOutput)
+	}
+	if strings.Contains(a.Output, "token") {
+		t.Error("output contains credentials")
+	}
+}
+
+func TestRecordUpdateAttempt_queueFull(t *testing.T) {
+	// A full queue (e.g. because the frontend is unavailable) must not block
+	// updates.
+	s := &Server{updateAttempts: make(chan queuedUpdateAttempt, 1)}
+	s.updateAttemptsOnce.Do(func() {})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 3; i++ {
+			s.recordUpdateAttempt("github.com/foo/bar", "fetch", "https://github.com/foo/bar", time.Now(), nil, nil)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("recordUpdateAttempt blocked on a full queue")
+	}
+	if n := len(s.updateAttempts); n != 1 {
+		t.Errorf("got %d queued attempts, want 1", n)
+	}
+}
+
+func TestBytesReceived(t *testing.T) {
+	tests := map[string]int64{
+		"":                                     0,
+		"Receiving objects: 100% (3/3), done.": 0,
+		"Receiving objects: 100% (3/3), 215 bytes | 215.00 KiB/s, done.": 215,
+		"Receiving objects: 100% (2148/2148), 292.00 KiB | 515.00 KiB/s": 292 * 1024,
+	}
+	for output, want := range tests {
+		if got := bytesReceived([]byte(output)); got != want {
+			t.Errorf("bytesReceived(%q) = %d, want %d", output, got, want)
+		}
+	}
+}
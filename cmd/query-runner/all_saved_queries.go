@@ -193,5 +193,10 @@ func serveTestNotification(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if err := createWebhookDeliveries(r.Context(), args.SavedSearch.Spec, args.SavedSearch.Config, webhookEventTest, nil); err != nil {
+		writeError(w, fmt.Errorf("error sending webhook notifications: %s", err))
+		return
+	}
+
 	log15.Info("saved query test notification sent", "spec", args.SavedSearch.Spec, "key", args.SavedSearch.Spec.Key)
 }
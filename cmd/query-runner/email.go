@@ -23,66 +23,105 @@ func canSendEmail(ctx context.Context) error {
 	return nil
 }
 
-func (n *notifier) emailNotify(ctx context.Context) {
+func (n *notifier) emailNotify(ctx context.Context) error {
+	var emailRecipients recipients
+	for _, recipient := range n.recipients {
+		if recipient.email {
+			emailRecipients = append(emailRecipients, recipient)
+		}
+	}
+	if len(emailRecipients) == 0 {
+		return nil
+	}
 	if err := canSendEmail(ctx); err != nil {
-		log15.Error("Failed to send email notification for saved search.", "error", err)
-		return
+		return err
 	}
 
-	// Send tx emails asynchronously.
-	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
-		defer cancel()
-
-		for _, recipient := range n.recipients {
-			ownership := "the" // example: "new search results have been found for {{.Ownership}} saved search"
-			if n.spec.Subject.User != nil && *n.spec.Subject.User == recipient.spec.userID {
-				ownership = "your"
-			}
-			if n.spec.Subject.Org != nil {
-				ownership = "your organization's"
-			}
-
-			plural := ""
-			if n.results.Data.Search.Results.ApproximateResultCount != "1" {
-				plural = "s"
-			}
-			if err := sendEmail(ctx, recipient.spec.userID, "results", newSearchResultsEmailTemplates, struct {
-				URL                    string
-				Description            string
-				Query                  string
-				ApproximateResultCount string
-				Ownership              string
-				PluralResults          string
-			}{
-				URL:                    searchURL(n.newQuery, utmSourceEmail),
-				Description:            n.query.Description,
-				Query:                  n.query.Query,
-				ApproximateResultCount: n.results.Data.Search.Results.ApproximateResultCount,
-				Ownership:              ownership,
-				PluralResults:          plural,
-			}); err != nil {
-				log15.Error("Failed to send email notification for new saved search results.", "userID", recipient.spec.userID, "error", err)
-			}
+	var failed bool
+	for _, recipient := range emailRecipients {
+		newMatches, err := n.recipientMatches(ctx, recipient)
+		if err != nil {
+			log15.Error("Failed to determine the new saved search results that the email notification recipient can read.", "userID", recipient.spec.userID, "error", err)
+			failed = true
+			continue
+		}
+		if len(newMatches) == 0 {
+			continue // the recipient can't read any of the new results
+		}
+
+		ownership := "the" // example: "new search results have been found for {{.Ownership}} saved search"
+		if n.spec.Subject.User != nil && *n.spec.Subject.User == recipient.spec.userID {
+			ownership = "your"
+		}
+		if n.spec.Subject.Org != nil {
+			ownership = "your organization's"
+		}
+
+		plural := ""
+		if len(newMatches) != 1 {
+			plural = "s"
+		}
+		matches, omittedMatches := notificationMatches(newMatches, utmSourceEmail)
+		data := struct {
+			URL            string
+			Description    string
+			Query          string
+			NewResultCount int
+			Ownership      string
+			PluralResults  string
+			Matches        []notificationMatch
+			OmittedMatches int
+		}{
+			URL:            searchURL(n.query.Query, utmSourceEmail),
+			Description:    n.query.Description,
+			Query:          n.query.Query,
+			NewResultCount: len(newMatches),
+			Ownership:      ownership,
+			PluralResults:  plural,
+			Matches:        matches,
+			OmittedMatches: omittedMatches,
 		}
-	}()
+		if err := retryNotify(ctx, func() error {
+			ctx, cancel := context.WithTimeout(ctx, time.Minute)
+			defer cancel()
+			return sendEmail(ctx, recipient.spec.userID, "results", newSearchResultsEmailTemplates, data)
+		}); err != nil {
+			log15.Error("Failed to send email notification for new saved search results.", "userID", recipient.spec.userID, "error", err)
+			failed = true
+		}
+	}
+	if failed {
+		return errors.New("failed to send email notifications to some recipients")
+	}
+	return nil
 }
 
 var newSearchResultsEmailTemplates = txemail.MustValidate(txtypes.Templates{
-	Subject: `[{{.ApproximateResultCount}} new result{{.PluralResults}}] {{.Description}}`,
+	Subject: `[{{.NewResultCount}} new result{{.PluralResults}}] {{.Description}}`,
 	Text: `
-{{.ApproximateResultCount}} new search result{{.PluralResults}} found for {{.Ownership}} saved search:
+{{.NewResultCount}} new search result{{.PluralResults}} found for {{.Ownership}} saved search:
 
   "{{.Description}}"
-
-View the new result{{.PluralResults}} on Sourcegraph: {{.URL}}
+{{range .Matches}}
+- {{.Repo}}{{with .Path}} {{.}}{{end}}{{with .Preview}}
+  {{.}}{{end}}
+  {{.URL}}
+{{end}}{{if .OmittedMatches}}
+...and {{.OmittedMatches}} more.
+{{end}}
+View all search results on Sourcegraph: {{.URL}}
 `,
 	HTML: `
-<strong>{{.ApproximateResultCount}}</strong> new search result{{.PluralResults}} found for {{.Ownership}} saved search:
+<strong>{{.NewResultCount}}</strong> new search result{{.PluralResults}} found for {{.Ownership}} saved search:
 
 <p style="padding-left: 16px">&quot;{{.Description}}&quot;</p>
 
-<p><a href="{{.URL}}">View the new result{{.PluralResults}} on Sourcegraph</a></p>
+<ul>
+{{range .Matches}}<li><a href="{{.URL}}">{{.Repo}}{{with .Path}} &rsaquo; {{.}}{{end}}</a>{{with .Preview}}<br><code>{{.}}</code>{{end}}</li>
+{{end}}</ul>
+{{if .OmittedMatches}}<p>...and {{.OmittedMatches}} more.</p>{{end}}
+
+<p><a href="{{.URL}}">View all search results on Sourcegraph</a></p>
 `,
 })
 
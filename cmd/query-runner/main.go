@@ -11,10 +11,13 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	multierror "github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 	"gopkg.in/inconshreveable/log15.v2"
 
@@ -26,7 +29,12 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/tracer"
 )
 
-var forceRunInterval = env.Get("FORCE_RUN_INTERVAL", "", "Force an interval to run saved queries at, instead of assuming query execution time * 30 (query that takes 2s to run, runs every 60s)")
+var (
+	forceRunInterval    = env.Get("FORCE_RUN_INTERVAL", "", "Force an interval to run saved queries at, instead of using their schedule or assuming query execution time * 30 (query that takes 2s to run, runs every 60s)")
+	defaultTimeout      = env.Get("SAVED_QUERY_DEFAULT_TIMEOUT", "5m", "The maximum duration of a run of a saved query that doesn't specify its own timeout")
+	concurrency         = env.Get("SAVED_QUERY_CONCURRENCY", "2", "The maximum number of saved queries that run concurrently")
+	concurrencyPerOwner = env.Get("SAVED_QUERY_CONCURRENCY_PER_OWNER", "1", "The maximum number of saved queries of a single user or organization that run concurrently")
+)
 
 const port = "3183"
 
@@ -53,6 +61,8 @@ func main() {
 
 	http.HandleFunc(queryrunnerapi.PathTestNotification, serveTestNotification)
 
+	go runWebhookDeliveries(ctx)
+
 	go func() {
 		err := executor.run(ctx)
 		if err != nil {
@@ -83,10 +93,21 @@ func writeError(w http.ResponseWriter, err error) {
 // it will send one notification on server startup, effectively.
 var debugPretendSavedQueryResultsExist = false
 
-var executor = &executorT{}
+var executor = &executorT{running: map[int32]bool{}}
 
 type executorT struct {
 	forceRunInterval *time.Duration
+	defaultTimeout   time.Duration
+	limiter          *concurrencyLimiter
+
+	mu sync.Mutex
+	// running records the saved searches (by ID) that are running (true) or whose run finished
+	// (false) since the run states were last listed. Finished runs are only forgotten before the
+	// run states are listed again, so that a listing that predates the recording of a run doesn't
+	// cause the saved search to run again immediately.
+	running map[int32]bool
+
+	unrecorded unrecordedRuns
 }
 
 func (e *executorT) run(ctx context.Context) error {
@@ -99,6 +120,20 @@ func (e *executorT) run(ctx context.Context) error {
 		}
 		e.forceRunInterval = &forceRunInterval
 	}
+	var err error
+	e.defaultTimeout, err = time.ParseDuration(defaultTimeout)
+	if err != nil {
+		return errors.Wrap(err, "parsing SAVED_QUERY_DEFAULT_TIMEOUT")
+	}
+	max, err := strconv.Atoi(concurrency)
+	if err != nil || max <= 0 {
+		return fmt.Errorf("invalid SAVED_QUERY_CONCURRENCY %q (must be a positive integer)", concurrency)
+	}
+	maxPerOwner, err := strconv.Atoi(concurrencyPerOwner)
+	if err != nil || maxPerOwner <= 0 {
+		return fmt.Errorf("invalid SAVED_QUERY_CONCURRENCY_PER_OWNER %q (must be a positive integer)", concurrencyPerOwner)
+	}
+	e.limiter = newConcurrencyLimiter(max, maxPerOwner)
 
 	// TODO(slimsag): Make gitserver notify us about repositories being updated
 	// as we could avoid executing queries if repositories haven't updated
@@ -117,109 +152,193 @@ func (e *executorT) run(ctx context.Context) error {
 		}
 		oldList = allSavedQueries
 
-		start := time.Now()
-		for spec, config := range allSavedQueries {
-			err := e.runQuery(ctx, spec, config)
-			if err != nil {
-				log15.Error("executor: failed to run query", "error", err, "query_description", config.Description)
+		e.forgetFinishedRuns()
+		states, err := api.InternalClient.SavedQueriesListRunStates(ctx)
+		if err != nil {
+			log15.Error("executor: error fetching saved queries run states (trying again in 5s)", "error", err)
+			time.Sleep(5 * time.Second)
+			continue
+		}
+		e.unrecorded.retry(ctx, states)
+
+		// Start the saved queries that are due, as long as the concurrency limits allow. Those
+		// that can't start now are started in a later iteration.
+		for _, q := range dueQueries(allSavedQueries, states, time.Now()) {
+			if e.limiter.full() {
+				break
 			}
+			e.start(ctx, q)
 		}
 
-		// If running all the queries didn't take very long (due to them
-		// erroring out quickly, or if we had zero to run, or if they very
-		// quickly produced zero results), then sleep for a few second to
-		// prevent busy waiting and needlessly polling the DB.
-		if time.Since(start) < time.Second {
-			time.Sleep(5 * time.Second)
+		// Wait a few seconds before checking again, to prevent busy waiting and needlessly
+		// polling the DB.
+		time.Sleep(5 * time.Second)
+	}
+}
+
+// forgetFinishedRuns forgets the saved searches whose run finished, so that they are run again
+// when they're due.
+func (e *executorT) forgetFinishedRuns() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for id, running := range e.running {
+		if !running {
+			delete(e.running, id)
 		}
 	}
 }
 
-// runQuery runs the given query if an appropriate amount of time has elapsed
-// since it last ran.
-func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSavedQuery) error {
-	if !query.Notify && !query.NotifySlack {
-		// No need to run this query because there will be nobody to notify.
-		return nil
+// start runs the due saved search in a separate goroutine, unless it is already running or the
+// concurrency limits don't allow it to run now.
+func (e *executorT) start(ctx context.Context, q dueQuery) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.running[q.savedSearchID]; ok {
+		return
 	}
-	if !strings.Contains(query.Query, "type:diff") && !strings.Contains(query.Query, "type:commit") {
-		// TODO(slimsag): we temporarily do not support non-commit search
-		// queries, since those do not support the after:"time" operator.
-		return nil
+	owner := q.spec.Subject.String()
+	if !e.limiter.tryAcquire(owner) {
+		return
 	}
+	e.running[q.savedSearchID] = true
+
+	go func() {
+		defer func() {
+			e.limiter.release(owner)
+			e.mu.Lock()
+			e.running[q.savedSearchID] = false
+			e.mu.Unlock()
+		}()
+		if err := e.runQuery(ctx, q); err != nil {
+			log15.Error("executor: failed to run query", "error", err, "query_description", q.query.Description)
+		}
+	}()
+}
+
+// runQuery runs the given query and records when it should run next.
+func (e *executorT) runQuery(ctx context.Context, q dueQuery) error {
+	spec, query := q.spec, q.query
 
 	info, err := api.InternalClient.SavedQueriesGetInfo(ctx, query.Query)
 	if err != nil {
-		return errors.Wrap(err, "SavedQueriesGetInfo")
-	}
-
-	// If the saved query was executed recently in the past, then skip it to
-	// avoid putting too much pressure on searcher/gitserver.
-	if info != nil {
-		// We assume a run interval of 30x that which it takes to execute the
-		// query. For example, a query which takes 2s to execute will run (2s*30)
-		// every minute.
-		//
-		// Additionally, in case queries run very quickly (e.g. our after:
-		// queries with no results often return in ~15ms), we impose a minimum
-		// run interval of 10s.
-		runInterval := info.ExecDuration * 30
-		if runInterval < 10*time.Second {
-			runInterval = 10 * time.Second
-		}
-		if e.forceRunInterval != nil {
-			runInterval = *e.forceRunInterval
-		}
-		if time.Since(info.LastExecuted) < runInterval {
-			return nil // too early to run the query
-		}
+		// Record the failed run, so that the saved search is retried with back-off instead of
+		// immediately.
+		err = errors.Wrap(err, "SavedQueriesGetInfo")
+		now, consecutiveFailures := time.Now(), q.consecutiveFailures(err)
+		e.recordRun(ctx, &api.SavedQueryRun{
+			SavedSearchID: q.savedSearchID,
+			StartedAt:     now,
+			NextRunAt:     nextRunAt(query, e.forceRunInterval, now, 0, consecutiveFailures),
+		}, err, consecutiveFailures)
+		return err
 	}
 
-	// Construct a new query which finds search results introduced after the
-	// last time we queried.
-	var latestKnownResult time.Time
-	if info != nil {
-		latestKnownResult = info.LatestResult
-	} else {
-		// We've never executed this search query before, so use the current
-		// time. We'll most certainly find nothing, which is okay.
-		latestKnownResult = time.Now()
+	// Perform the search, determine which of its matches are new, and mark
+	// the saved query as having been executed in the database. We do this
+	// regardless of whether or not the search query fails in order to avoid
+	// e.g. failed saved queries from executing constantly and potentially
+	// causing harm to the system. Failing saved queries are retried less
+	// often (see failureBackoff).
+	start := time.Now()
+	timeout := runTimeout(query, e.defaultTimeout)
+	searchCtx, cancel := context.WithTimeout(ctx, timeout)
+	v, execDuration, searchErr := performSearch(searchCtx, query.Query)
+	if searchErr != nil && searchCtx.Err() == context.DeadlineExceeded {
+		searchErr = fmt.Errorf("search timed out after %s", timeout)
 	}
-	afterTime := latestKnownResult.UTC().Format(time.RFC3339)
-	newQuery := strings.Join([]string{query.Query, fmt.Sprintf(`after:"%s"`, afterTime)}, " ")
-	if debugPretendSavedQueryResultsExist {
-		debugPretendSavedQueryResultsExist = false
-		newQuery = query.Query
+	cancel()
+	var (
+		resultCount      int
+		newMatches       []*resultMatch
+		nextFingerprints []string
+	)
+	if searchErr == nil {
+		resultCount, newMatches, nextFingerprints, searchErr = compareResults(ctx, q.savedSearchID, v)
+	}
+	duration := time.Since(start)
+
+	// Send notifications for new search results, and then record the fingerprints of the results
+	// so that the next run only notifies about matches that are newer. Each recipient and channel
+	// is retried on its own (see retryNotify), so the fingerprints are recorded even if some
+	// notifications couldn't be sent; otherwise the recipients and channels that did receive them
+	// would be notified about the same matches again. Both happen before the run is recorded, so
+	// that the next run compares its results to these fingerprints.
+	if searchErr == nil {
+		if err := notify(ctx, spec, query, newMatches); err != nil {
+			log15.Error("executor: failed to send notifications", "savedSearchID", q.savedSearchID, "error", err)
+		}
+		if err := api.InternalClient.SavedQueriesSetResultFingerprints(ctx, q.savedSearchID, nextFingerprints); err != nil {
+			searchErr = errors.Wrap(err, "SavedQueriesSetResultFingerprints")
+		}
 	}
 
-	// Perform the search and mark the saved query as having been executed in
-	// the database. We do this regardless of whether or not the search query
-	// fails in order to avoid e.g. failed saved queries from executing
-	// constantly and potentially causing harm to the system. We'll retry at
-	// our normal interval, regardless of errors.
-	v, execDuration, searchErr := performSearch(ctx, newQuery)
+	consecutiveFailures := q.consecutiveFailures(searchErr)
+	e.recordRun(ctx, &api.SavedQueryRun{
+		SavedSearchID:  q.savedSearchID,
+		StartedAt:      start,
+		Duration:       duration,
+		ResultCount:    resultCount,
+		NewResultCount: len(newMatches),
+		NextRunAt:      nextRunAt(query, e.forceRunInterval, time.Now(), duration, consecutiveFailures),
+	}, searchErr, consecutiveFailures)
+	latestResult := time.Now()
+	if len(newMatches) == 0 && info != nil {
+		latestResult = info.LatestResult
+	}
 	if err := api.InternalClient.SavedQueriesSetInfo(ctx, &api.SavedQueryInfo{
 		Query:        query.Query,
 		LastExecuted: time.Now(),
-		LatestResult: latestResultTime(info, v, searchErr),
+		LatestResult: latestResult,
 		ExecDuration: execDuration,
 	}); err != nil {
 		return errors.Wrap(err, "SavedQueriesSetInfo")
 	}
+	return searchErr
+}
+
+// recordRun records the run of a saved search (and the error that occurred
+// during the run, if any) in the saved search's run history, which also
+// persists when the saved search should run next. If recording fails, the run
+// is recorded again later (see unrecordedRuns).
+func (e *executorT) recordRun(ctx context.Context, run *api.SavedQueryRun, runErr error, consecutiveFailures int) {
+	if runErr != nil {
+		errStr := runErr.Error()
+		run.Error = &errStr
+	}
+	if err := api.InternalClient.SavedQueriesRecordRun(ctx, run); err != nil {
+		log15.Error("executor: failed to record saved search run (will retry)", "savedSearchID", run.SavedSearchID, "error", err)
+		e.unrecorded.add(run, consecutiveFailures)
+		return
+	}
+	e.unrecorded.remove(run.SavedSearchID)
+}
 
-	if searchErr != nil {
-		return searchErr
+// compareResults compares the matches in the saved search's search results to
+// the fingerprints that were last recorded (i.e., when the last notification
+// was sent). It returns the number of matches, the matches that are new, and
+// the fingerprints to record once the new matches have been notified.
+//
+// If no fingerprints were recorded before (because the saved search has never
+// run), then no matches are new and the results become the baseline.
+func compareResults(ctx context.Context, savedSearchID int32, v *gqlSearchResponse) (resultCount int, newMatches []*resultMatch, nextFingerprints []string, err error) {
+	prevFingerprints, err := api.InternalClient.SavedQueriesGetResultFingerprints(ctx, savedSearchID)
+	if err != nil {
+		return 0, nil, nil, errors.Wrap(err, "SavedQueriesGetResultFingerprints")
 	}
 
-	// Send notifications for new search results in a separate goroutine, so
-	// that we don't block other search queries from running in sequence (which
-	// is done intentionally, to ensure no overloading of searcher/gitserver).
-	go func() {
-		if err := notify(context.Background(), spec, query, newQuery, v); err != nil {
-			log15.Error("executor: failed to send notifications", "error", err)
-		}
-	}()
-	return nil
+	results := v.Data.Search.Results
+	matches := resultMatches(results.Results)
+	complete := !results.LimitHit && len(results.Cloning) == 0 && len(results.Timedout) == 0
+	nextFingerprints = nextResultFingerprints(matches, prevFingerprints, complete)
+
+	if debugPretendSavedQueryResultsExist {
+		debugPretendSavedQueryResultsExist = false
+		return len(matches), matches, nextFingerprints, nil
+	}
+	if prevFingerprints == nil {
+		return len(matches), nil, nextFingerprints, nil
+	}
+	return len(matches), newResultMatches(matches, prevFingerprints), nextFingerprints, nil
 }
 
 func performSearch(ctx context.Context, query string) (v *gqlSearchResponse, execDuration time.Duration, err error) {
@@ -250,37 +369,22 @@ func performSearch(ctx context.Context, query string) (v *gqlSearchResponse, exe
 		// out, so try again in a few seconds.
 		attempts++
 		log15.Warn("executor: failed to run query found 0 search results due to cloning or timed out repos (retrying in 5s)", "cloning", cloning, "timedout", timedout, "query", query)
-		time.Sleep(5 * time.Second)
-	}
-}
-
-func latestResultTime(prevInfo *api.SavedQueryInfo, v *gqlSearchResponse, searchErr error) time.Time {
-	if searchErr != nil || len(v.Data.Search.Results.Results) == 0 {
-		// Error performing the search, or there were no results. Assume the
-		// previous info's result time.
-		if prevInfo != nil {
-			return prevInfo.LatestResult
+		select {
+		case <-time.After(5 * time.Second):
+		case <-ctx.Done():
+			return nil, execDuration, ctx.Err()
 		}
-		return time.Now()
 	}
-
-	// Results are ordered chronologically, so first result is the latest.
-	t, err := extractTime(v.Data.Search.Results.Results[0])
-	if err != nil {
-		// Error already logged by extractTime.
-		return time.Now()
-	}
-	return *t
 }
 
 var externalURL *url.URL
 
 // notify handles sending notifications for new search results.
-func notify(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSavedQuery, newQuery string, results *gqlSearchResponse) error {
-	if len(results.Data.Search.Results.Results) == 0 {
+func notify(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSavedQuery, newMatches []*resultMatch) error {
+	if len(newMatches) == 0 {
 		return nil
 	}
-	log15.Info("sending notifications", "new_results", len(results.Data.Search.Results.Results), "description", query.Description)
+	log15.Info("sending notifications", "new_results", len(newMatches), "description", query.Description)
 
 	// Determine which users to notify.
 	recipients, err := getNotificationRecipients(ctx, spec, query)
@@ -288,27 +392,180 @@ func notify(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSave
 		return err
 	}
 
-	// Send slack notifications.
 	n := &notifier{
 		spec:       spec,
 		query:      query,
-		newQuery:   newQuery,
-		results:    results,
+		newMatches: newMatches,
 		recipients: recipients,
 	}
 
-	// Send Slack and email notifications.
-	n.slackNotify(ctx)
-	n.emailNotify(ctx)
-	return nil
+	// Send Slack, email, and webhook notifications.
+	var errs *multierror.Error
+	if err := n.slackNotify(ctx); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err := n.emailNotify(ctx); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	if err := n.webhookNotify(ctx); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	return errs.ErrorOrNil()
+}
+
+// notifyAttempts is the maximum number of attempts to send a notification to a single recipient
+// over a single channel.
+const notifyAttempts = 3
+
+// notifyRetryDelay is the delay before retrying to send a notification.
+var notifyRetryDelay = 10 * time.Second
+
+// retryNotify calls send until it succeeds, at most notifyAttempts times. Callers retry each
+// recipient and channel on its own, so that a failure doesn't cause the same notification to be
+// sent again to the recipients and over the channels that already received it.
+func retryNotify(ctx context.Context, send func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := send()
+		if err == nil || attempt == notifyAttempts {
+			return err
+		}
+		select {
+		case <-time.After(notifyRetryDelay):
+		case <-ctx.Done():
+			return err
+		}
+	}
 }
 
 type notifier struct {
 	spec       api.SavedQueryIDSpec
 	query      api.ConfigSavedQuery
-	newQuery   string
-	results    *gqlSearchResponse
+	newMatches []*resultMatch // the new matches, in the order of the search results
 	recipients recipients
+
+	readable   map[int32]map[string]bool // the repositories that each user can read (see readableRepos)
+	orgMembers []int32                   // the members of the org that owns the saved search, once listed
+}
+
+// recipientMatches returns the new matches that may be included in a notification to the
+// recipient. Notifications to an org (its Slack messages) may only include the matches that all
+// of its members can read.
+func (n *notifier) recipientMatches(ctx context.Context, r *recipient) ([]*resultMatch, error) {
+	if r.spec.userID != 0 {
+		return n.matchesReadableBy(ctx, []int32{r.spec.userID})
+	}
+	userIDs, err := n.ownerUserIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return n.matchesReadableBy(ctx, userIDs)
+}
+
+// ownerUserIDs returns the user who owns the saved search, or the members of the org that owns
+// it.
+func (n *notifier) ownerUserIDs(ctx context.Context) ([]int32, error) {
+	if n.spec.Subject.User != nil {
+		return []int32{*n.spec.Subject.User}, nil
+	}
+	if n.orgMembers == nil && n.spec.Subject.Org != nil {
+		orgMembers, err := api.InternalClient.OrgsListUsers(ctx, *n.spec.Subject.Org)
+		if err != nil {
+			return nil, errors.Wrap(err, "OrgsListUsers")
+		}
+		n.orgMembers = orgMembers
+	}
+	return n.orgMembers, nil
+}
+
+// matchesReadableBy returns the new matches in the repositories that all of the users can read.
+//
+// 🚨 SECURITY: Saved searches run without checking repository permissions, so the matches must be
+// filtered by the permissions of the users who receive a notification. Otherwise the previews of
+// code in repositories that a user can't read would be sent to them.
+func (n *notifier) matchesReadableBy(ctx context.Context, userIDs []int32) ([]*resultMatch, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	matches := n.newMatches
+	for _, userID := range userIDs {
+		readable, err := n.readableRepos(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		var filtered []*resultMatch
+		for _, m := range matches {
+			if readable[m.Repo] {
+				filtered = append(filtered, m)
+			}
+		}
+		matches = filtered
+	}
+	return matches, nil
+}
+
+// readableRepos returns the set of repositories (of the new matches) that the user can read.
+func (n *notifier) readableRepos(ctx context.Context, userID int32) (map[string]bool, error) {
+	if readable, ok := n.readable[userID]; ok {
+		return readable, nil
+	}
+	var repos []api.RepoName
+	seen := map[string]bool{}
+	for _, m := range n.newMatches {
+		if !seen[m.Repo] {
+			seen[m.Repo] = true
+			repos = append(repos, api.RepoName(m.Repo))
+		}
+	}
+	names, err := api.InternalClient.ReposFilterReadable(ctx, userID, repos)
+	if err != nil {
+		return nil, errors.Wrap(err, "ReposFilterReadable")
+	}
+	readable := make(map[string]bool, len(names))
+	for _, name := range names {
+		readable[string(name)] = true
+	}
+	if n.readable == nil {
+		n.readable = map[int32]map[string]bool{}
+	}
+	n.readable[userID] = readable
+	return readable, nil
+}
+
+// maxNotificationMatches is the maximum number of new matches that are listed in a notification.
+const maxNotificationMatches = 10
+
+// maxNotificationPreviewLength is the maximum length of a match's preview in a notification.
+const maxNotificationPreviewLength = 200
+
+// notificationMatch is a new match that is listed in a notification. Its fields are exported
+// because it is used as email template data.
+type notificationMatch struct {
+	Repo    string
+	Path    string
+	Preview string
+	URL     string
+}
+
+// notificationMatches returns the new matches to list in a notification and the number of new
+// matches that are omitted from the list.
+func notificationMatches(newMatches []*resultMatch, utmSource string) (matches []notificationMatch, omitted int) {
+	if len(newMatches) > maxNotificationMatches {
+		newMatches, omitted = newMatches[:maxNotificationMatches], len(newMatches)-maxNotificationMatches
+	}
+	matches = make([]notificationMatch, len(newMatches))
+	for i, m := range newMatches {
+		preview := strings.TrimSpace(m.Preview)
+		if r := []rune(preview); len(r) > maxNotificationPreviewLength {
+			preview = string(r[:maxNotificationPreviewLength]) + "…"
+		}
+		matches[i] = notificationMatch{
+			Repo:    m.Repo,
+			Path:    m.Path,
+			Preview: preview,
+			URL:     matchURL(m.URL, utmSource),
+		}
+	}
+	return matches, omitted
 }
 
 const (
@@ -316,20 +573,28 @@ const (
 	utmSourceSlack = "saved-search-slack"
 )
 
-func searchURL(query, utmSource string) string {
+func getExternalURL() *url.URL {
 	if externalURL == nil {
 		// Determine the external URL.
 		externalURLStr, err := api.InternalClient.ExternalURL(context.Background())
 		if err != nil {
 			log15.Error("failed to get ExternalURL", err)
-			return ""
+			return nil
 		}
 		externalURL, err = url.Parse(externalURLStr)
 		if err != nil {
 			log15.Error("failed to parse ExternalURL", err)
-			return ""
+			return nil
 		}
 	}
+	return externalURL
+}
+
+func searchURL(query, utmSource string) string {
+	externalURL := getExternalURL()
+	if externalURL == nil {
+		return ""
+	}
 
 	// Construct URL to the search query.
 	u := externalURL.ResolveReference(&url.URL{Path: "search"})
@@ -340,6 +605,24 @@ func searchURL(query, utmSource string) string {
 	return u.String()
 }
 
+// matchURL returns the absolute URL to a search result match, given its URL path (such as
+// "/github.com/foo/bar/-/blob/baz.go#L3").
+func matchURL(matchURLPath, utmSource string) string {
+	externalURL := getExternalURL()
+	if externalURL == nil {
+		return ""
+	}
+	u, err := externalURL.Parse(matchURLPath)
+	if err != nil {
+		log15.Error("failed to parse search result URL", "url", matchURLPath, "error", err)
+		return ""
+	}
+	q := u.Query()
+	q.Set("utm_source", utmSource)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
 func logEvent(userID int32, email, eventName, eventType string) {
 	eventlogger.LogEvent(userID, email, eventName, json.RawMessage(fmt.Sprintf(`{"saved_searches": {"event_type": "%s"}}`, eventType)))
 }
@@ -3,7 +3,9 @@ package main
 import (
 	"context"
 	"fmt"
+	"strconv"
 
+	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 )
 
@@ -26,6 +28,10 @@ type recipient struct {
 	spec  recipientSpec // the recipient's identity
 	email bool          // send an email to the recipient
 	slack bool          // post a Slack message to the recipient
+
+	// slackWebhookURL is the Slack webhook URL of a user's Slack subscription to the saved search.
+	// If empty, Slack messages are posted to the saved search's Slack webhook URL.
+	slackWebhookURL string
 }
 
 func (r *recipient) String() string {
@@ -74,6 +80,35 @@ func getNotificationRecipients(ctx context.Context, spec api.SavedQueryIDSpec, q
 		})
 	}
 
+	// Notify the users who subscribed to the saved search. Only the subscriptions of the users who
+	// may (still) view the saved search are listed.
+	if query.HasSubscriptions {
+		savedSearchID, err := strconv.ParseInt(spec.Key, 10, 32)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing saved search ID")
+		}
+		subscriptions, err := api.InternalClient.SavedQueriesListSubscriptions(ctx, int32(savedSearchID))
+		if err != nil {
+			return nil, errors.Wrap(err, "SavedQueriesListSubscriptions")
+		}
+		for _, s := range subscriptions {
+			r := recipient{spec: recipientSpec{userID: s.UserID}}
+			switch s.Channel {
+			case "email":
+				r.email = true
+			case "slack":
+				if s.SlackWebhookURL == nil {
+					continue
+				}
+				r.slack = true
+				r.slackWebhookURL = *s.SlackWebhookURL
+			default:
+				continue
+			}
+			recipients.add(r)
+		}
+	}
+
 	return recipients, nil
 }
 
@@ -84,7 +119,16 @@ type recipients []*recipient
 func (rs *recipients) add(r recipient) {
 	for _, r2 := range *rs {
 		if r.spec == r2.spec {
-			// Merge into existing recipient.
+			// Merge into existing recipient. The saved search's own Slack webhook URL (used when
+			// slackWebhookURL is empty) takes precedence over the URL of a user's subscription.
+			switch {
+			case r2.slack && r2.slackWebhookURL == "":
+				// Keep using the saved search's Slack webhook URL.
+			case r.slack && r.slackWebhookURL == "":
+				r2.slackWebhookURL = ""
+			case r2.slackWebhookURL == "":
+				r2.slackWebhookURL = r.slackWebhookURL
+			}
 			r2.email = r2.email || r.email
 			r2.slack = r2.slack || r.slack
 			return
@@ -124,6 +168,9 @@ func diffNotificationRecipients(old, new recipients) (removed, added recipients)
 			email: old.email && !new.email,
 			slack: old.slack && !new.slack,
 		}
+		if removed.slack {
+			removed.slackWebhookURL = old.slackWebhookURL
+		}
 		if *removed == empty {
 			removed = nil
 		}
@@ -132,6 +179,9 @@ func diffNotificationRecipients(old, new recipients) (removed, added recipients)
 			email: new.email && !old.email,
 			slack: new.slack && !old.slack,
 		}
+		if added.slack {
+			added.slackWebhookURL = new.slackWebhookURL
+		}
 		if *added == empty {
 			added = nil
 		}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"strconv"
 	"testing"
@@ -61,6 +62,65 @@ func TestGetNotificationRecipients(t *testing.T) {
 			t.Errorf("got %v, want %v", recipients, want)
 		}
 	})
+
+	t.Run("subscriptions", func(t *testing.T) {
+		slackWebhookURL := "https://hooks.slack.com/services/x"
+		api.MockSavedQueriesListSubscriptions = func(savedSearchID int32) ([]*api.SavedQuerySubscription, error) {
+			if want := int32(7); savedSearchID != want {
+				t.Errorf("got %d, want %d", savedSearchID, want)
+			}
+			return []*api.SavedQuerySubscription{
+				{UserID: 123, Channel: "slack", SlackWebhookURL: &slackWebhookURL},
+				{UserID: 4, Channel: "email"},
+				{UserID: 4, Channel: "slack", SlackWebhookURL: &slackWebhookURL},
+			}, nil
+		}
+		defer func() { api.MockSavedQueriesListSubscriptions = nil }()
+		recipients, err := getNotificationRecipients(ctx,
+			api.SavedQueryIDSpec{
+				Subject: api.SettingsSubject{User: &onetwothree},
+				Key:     "7",
+			},
+			api.ConfigSavedQuery{
+				Notify:           true,
+				HasSubscriptions: true,
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []*recipient{
+			{spec: recipientSpec{userID: 123}, email: true, slack: true, slackWebhookURL: slackWebhookURL},
+			{spec: recipientSpec{userID: 4}, email: true, slack: true, slackWebhookURL: slackWebhookURL},
+		}; !reflect.DeepEqual(recipients, want) {
+			t.Errorf("got %v, want %v", recipients, want)
+		}
+	})
+
+	t.Run("subscription of owner with Slack notifications", func(t *testing.T) {
+		slackWebhookURL := "https://hooks.slack.com/services/x"
+		api.MockSavedQueriesListSubscriptions = func(savedSearchID int32) ([]*api.SavedQuerySubscription, error) {
+			return []*api.SavedQuerySubscription{{UserID: 123, Channel: "slack", SlackWebhookURL: &slackWebhookURL}}, nil
+		}
+		defer func() { api.MockSavedQueriesListSubscriptions = nil }()
+		recipients, err := getNotificationRecipients(ctx,
+			api.SavedQueryIDSpec{
+				Subject: api.SettingsSubject{User: &onetwothree},
+				Key:     "7",
+			},
+			api.ConfigSavedQuery{
+				NotifySlack:      true,
+				HasSubscriptions: true,
+			},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The saved search's own Slack webhook URL is used, not the subscription's.
+		if want := []*recipient{{spec: recipientSpec{userID: 123}, slack: true}}; !reflect.DeepEqual(recipients, want) {
+			t.Errorf("got %v, want %v", recipients, want)
+		}
+	})
 }
 
 func TestDiffNotificationRecipients(t *testing.T) {
@@ -141,3 +201,72 @@ func TestDiffNotificationRecipients(t *testing.T) {
 		})
 	}
 }
+
+func TestNotifierRecipientMatches(t *testing.T) {
+	ctx := context.Background()
+
+	// User 1 can read repo a, user 2 can read repos a and b, and user 3 can read repo b.
+	readable := map[int32][]api.RepoName{1: {"a"}, 2: {"a", "b"}, 3: {"b"}}
+	api.MockReposFilterReadable = func(userID int32, repos []api.RepoName) ([]api.RepoName, error) {
+		if want := []api.RepoName{"a", "b", "c"}; !reflect.DeepEqual(repos, want) {
+			t.Errorf("got repos %v, want %v", repos, want)
+		}
+		return readable[userID], nil
+	}
+	defer func() { api.MockReposFilterReadable = nil }()
+	api.MockOrgsListUsers = func(orgID int32) ([]int32, error) { return []int32{1, 2}, nil }
+	defer func() { api.MockOrgsListUsers = nil }()
+
+	a, b, c := &resultMatch{Repo: "a"}, &resultMatch{Repo: "b"}, &resultMatch{Repo: "c"}
+	orgID := int32(9)
+	n := &notifier{
+		spec:       api.SavedQueryIDSpec{Subject: api.SettingsSubject{Org: &orgID}},
+		newMatches: []*resultMatch{a, b, c, a},
+	}
+	tests := map[string]struct {
+		recipient *recipient
+		want      []*resultMatch
+	}{
+		"user":             {recipient: &recipient{spec: recipientSpec{userID: 2}}, want: []*resultMatch{a, b, a}},
+		"user without any": {recipient: &recipient{spec: recipientSpec{userID: 4}}, want: nil},
+		"org":              {recipient: &recipient{spec: recipientSpec{orgID: 9}}, want: []*resultMatch{a, a}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			matches, err := n.recipientMatches(ctx, test.recipient)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(matches, test.want) {
+				t.Errorf("got %v, want %v", matches, test.want)
+			}
+		})
+	}
+}
+
+func TestRetryNotify(t *testing.T) {
+	orig := notifyRetryDelay
+	notifyRetryDelay = 0
+	defer func() { notifyRetryDelay = orig }()
+
+	var calls int
+	err := retryNotify(context.Background(), func() error {
+		calls++
+		if calls < 2 {
+			return errors.New("x")
+		}
+		return nil
+	})
+	if err != nil || calls != 2 {
+		t.Errorf("got error %v after %d calls, want success after 2 calls", err, calls)
+	}
+
+	calls = 0
+	err = retryNotify(context.Background(), func() error {
+		calls++
+		return errors.New("x")
+	})
+	if err == nil || calls != notifyAttempts {
+		t.Errorf("got error %v after %d calls, want failure after %d calls", err, calls, notifyAttempts)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// resultMatch is a single match in the results of a saved search: a line or symbol in a file, a
+// file (if the file matched only by its path), a commit, or a repository.
+type resultMatch struct {
+	Repo    string // the name of the repository containing the match
+	Path    string // the file path (empty for commit and repository matches)
+	Preview string // the line preview, symbol name, or commit subject (if any)
+	URL     string // the URL path (relative to the external URL) to the match
+
+	fingerprint string
+}
+
+// maxResultFingerprints is the maximum number of result fingerprints that are recorded for a saved
+// search. It bounds the size of the recorded set when it is merged across incomplete result sets
+// (see nextResultFingerprints).
+const maxResultFingerprints = 10000
+
+// fingerprint returns a stable identifier for a match that is derived from the given components.
+//
+// The fingerprint intentionally omits information that changes without the match itself changing
+// (such as line numbers, which change when lines are added above the match), so that a match is
+// only reported as new when it is genuinely new.
+func fingerprint(components ...string) string {
+	h := sha256.Sum256([]byte(strings.Join(components, "\x00")))
+	return hex.EncodeToString(h[:16])
+}
+
+// resultMatches returns the individual matches in the search results.
+func resultMatches(results []*gqlSearchResult) []*resultMatch {
+	var matches []*resultMatch
+	for _, r := range results {
+		switch r.Typename {
+		case "FileMatch":
+			if r.Repository == nil || r.File == nil {
+				continue
+			}
+			repo, path := r.Repository.Name, r.File.Path
+
+			// The same line may occur more than once in a file, so distinguish identical lines by
+			// their occurrence.
+			occurrences := map[string]int{}
+			for _, lm := range r.LineMatches {
+				occurrences[lm.Preview]++
+				matches = append(matches, &resultMatch{
+					Repo:        repo,
+					Path:        path,
+					Preview:     lm.Preview,
+					URL:         fmt.Sprintf("%s#L%d", r.File.URL, lm.LineNumber+1),
+					fingerprint: fingerprint("line", repo, path, lm.Preview, fmt.Sprint(occurrences[lm.Preview])),
+				})
+			}
+			for _, sym := range r.Symbols {
+				matches = append(matches, &resultMatch{
+					Repo:        repo,
+					Path:        path,
+					Preview:     sym.Name,
+					URL:         sym.URL,
+					fingerprint: fingerprint("symbol", repo, path, sym.Kind, sym.Name),
+				})
+			}
+			if len(r.LineMatches) == 0 && len(r.Symbols) == 0 {
+				// The file matched by its path.
+				matches = append(matches, &resultMatch{
+					Repo:        repo,
+					Path:        path,
+					URL:         r.File.URL,
+					fingerprint: fingerprint("path", repo, path),
+				})
+			}
+
+		case "CommitSearchResult":
+			if r.Commit == nil {
+				continue
+			}
+			matches = append(matches, &resultMatch{
+				Repo:        r.Commit.Repository.Name,
+				Preview:     r.Commit.Subject,
+				URL:         r.Commit.URL,
+				fingerprint: fingerprint("commit", r.Commit.Repository.Name, r.Commit.OID),
+			})
+
+		case "Repository":
+			matches = append(matches, &resultMatch{
+				Repo:        r.Name,
+				URL:         r.URL,
+				fingerprint: fingerprint("repo", r.Name),
+			})
+		}
+	}
+	return matches
+}
+
+// newResultMatches returns the matches whose fingerprints are not in prevFingerprints (i.e., the
+// matches that are new since the fingerprints were recorded).
+func newResultMatches(matches []*resultMatch, prevFingerprints []string) []*resultMatch {
+	prev := make(map[string]struct{}, len(prevFingerprints))
+	for _, f := range prevFingerprints {
+		prev[f] = struct{}{}
+	}
+	var newMatches []*resultMatch
+	for _, m := range matches {
+		if _, seen := prev[m.fingerprint]; !seen {
+			newMatches = append(newMatches, m)
+			prev[m.fingerprint] = struct{}{} // don't report duplicate matches twice
+		}
+	}
+	return newMatches
+}
+
+// nextResultFingerprints returns the fingerprints to record for the matches.
+//
+// If the results are incomplete (e.g., because the result limit was hit or some repositories timed
+// out), a previously seen match that is missing from the results may still exist, so the previous
+// fingerprints are retained. Otherwise it would be reported as new when it next appears in the
+// results.
+func nextResultFingerprints(matches []*resultMatch, prevFingerprints []string, complete bool) []string {
+	fingerprints := make([]string, 0, len(matches))
+	seen := make(map[string]struct{}, len(matches))
+	add := func(f string) {
+		if _, ok := seen[f]; !ok && len(fingerprints) < maxResultFingerprints {
+			seen[f] = struct{}{}
+			fingerprints = append(fingerprints, f)
+		}
+	}
+	for _, m := range matches {
+		add(m.fingerprint)
+	}
+	if !complete {
+		for _, f := range prevFingerprints {
+			add(f)
+		}
+	}
+	return fingerprints
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func testSearchResults(t *testing.T, data string) []*gqlSearchResult {
+	var results []*gqlSearchResult
+	if err := json.Unmarshal([]byte(data), &results); err != nil {
+		t.Fatal(err)
+	}
+	return results
+}
+
+func TestResultMatches(t *testing.T) {
+	results := testSearchResults(t, `[
+		{
+			"__typename": "FileMatch",
+			"repository": {"name": "r"},
+			"file": {"path": "a.go", "url": "/r/-/blob/a.go"},
+			"symbols": [],
+			"lineMatches": [
+				{"preview": "foo()", "lineNumber": 1},
+				{"preview": "bar()", "lineNumber": 4},
+				{"preview": "foo()", "lineNumber": 9}
+			]
+		},
+		{
+			"__typename": "FileMatch",
+			"repository": {"name": "r"},
+			"file": {"path": "b.go", "url": "/r/-/blob/b.go"},
+			"symbols": [{"name": "Foo", "kind": "FUNCTION", "url": "/r/-/blob/b.go#L3:6-3:9"}],
+			"lineMatches": []
+		},
+		{
+			"__typename": "FileMatch",
+			"repository": {"name": "r"},
+			"file": {"path": "foo.txt", "url": "/r/-/blob/foo.txt"},
+			"symbols": [],
+			"lineMatches": []
+		},
+		{
+			"__typename": "CommitSearchResult",
+			"commit": {"repository": {"name": "r"}, "oid": "abc", "subject": "Add foo", "url": "/r/-/commit/abc"}
+		},
+		{
+			"__typename": "Repository",
+			"name": "foo",
+			"url": "/foo"
+		}
+	]`)
+
+	type match struct{ Repo, Path, Preview, URL string }
+	want := []match{
+		{Repo: "r", Path: "a.go", Preview: "foo()", URL: "/r/-/blob/a.go#L2"},
+		{Repo: "r", Path: "a.go", Preview: "bar()", URL: "/r/-/blob/a.go#L5"},
+		{Repo: "r", Path: "a.go", Preview: "foo()", URL: "/r/-/blob/a.go#L10"},
+		{Repo: "r", Path: "b.go", Preview: "Foo", URL: "/r/-/blob/b.go#L3:6-3:9"},
+		{Repo: "r", Path: "foo.txt", URL: "/r/-/blob/foo.txt"},
+		{Repo: "r", Preview: "Add foo", URL: "/r/-/commit/abc"},
+		{Repo: "foo", URL: "/foo"},
+	}
+
+	matches := resultMatches(results)
+	var got []match
+	fingerprints := map[string]struct{}{}
+	for _, m := range matches {
+		got = append(got, match{Repo: m.Repo, Path: m.Path, Preview: m.Preview, URL: m.URL})
+		fingerprints[m.fingerprint] = struct{}{}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got matches %+v, want %+v", got, want)
+	}
+	if len(fingerprints) != len(matches) {
+		t.Errorf("got %d distinct fingerprints, want %d", len(fingerprints), len(matches))
+	}
+}
+
+func TestNewResultMatches(t *testing.T) {
+	before := resultMatches(testSearchResults(t, `[
+		{
+			"__typename": "FileMatch",
+			"repository": {"name": "r"},
+			"file": {"path": "a.go", "url": "/r/-/blob/a.go"},
+			"lineMatches": [{"preview": "foo()", "lineNumber": 1}]
+		},
+		{
+			"__typename": "CommitSearchResult",
+			"commit": {"repository": {"name": "r"}, "oid": "abc", "subject": "Add foo", "url": "/r/-/commit/abc"}
+		}
+	]`))
+	prevFingerprints := nextResultFingerprints(before, nil, true)
+
+	// Lines were added above the existing match (so its line number changed), the same line was
+	// added again below it, and a new commit was found.
+	after := resultMatches(testSearchResults(t, `[
+		{
+			"__typename": "CommitSearchResult",
+			"commit": {"repository": {"name": "r"}, "oid": "def", "subject": "Add more foo", "url": "/r/-/commit/def"}
+		},
+		{
+			"__typename": "FileMatch",
+			"repository": {"name": "r"},
+			"file": {"path": "a.go", "url": "/r/-/blob/a.go"},
+			"lineMatches": [{"preview": "foo()", "lineNumber": 3}, {"preview": "foo()", "lineNumber": 7}]
+		},
+		{
+			"__typename": "CommitSearchResult",
+			"commit": {"repository": {"name": "r"}, "oid": "abc", "subject": "Add foo", "url": "/r/-/commit/abc"}
+		}
+	]`))
+
+	var got []string
+	for _, m := range newResultMatches(after, prevFingerprints) {
+		got = append(got, m.URL)
+	}
+	if want := []string{"/r/-/commit/def", "/r/-/blob/a.go#L8"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got new matches %v, want %v", got, want)
+	}
+}
+
+func TestNextResultFingerprints(t *testing.T) {
+	matches := []*resultMatch{{fingerprint: "b"}, {fingerprint: "c"}, {fingerprint: "b"}}
+
+	t.Run("complete", func(t *testing.T) {
+		got := nextResultFingerprints(matches, []string{"a", "b"}, true)
+		if want := []string{"b", "c"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("incomplete", func(t *testing.T) {
+		got := nextResultFingerprints(matches, []string{"a", "b"}, false)
+		if want := []string{"b", "c", "a"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	log15 "gopkg.in/inconshreveable/log15.v2"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/schedule"
+)
+
+// minDefaultRunInterval is the minimum interval between runs of a saved search that has no
+// schedule.
+const minDefaultRunInterval = 10 * time.Second
+
+// neverRunRecheckInterval is how often a saved search whose schedule never matches (such as
+// "0 0 31 2 *") is checked again, in case its schedule was changed.
+const neverRunRecheckInterval = 24 * time.Hour
+
+// Saved searches whose runs fail are run less often: after N consecutive failures, the next run
+// is delayed by at least initialFailureBackoff * 2^(N-1), up to maxFailureBackoff.
+const (
+	initialFailureBackoff = time.Minute
+	maxFailureBackoff     = 6 * time.Hour
+)
+
+// failureBackoff returns the minimum delay before the next run of a saved search whose most
+// recent runs failed.
+func failureBackoff(consecutiveFailures int) time.Duration {
+	if consecutiveFailures <= 0 {
+		return 0
+	}
+	backoff := initialFailureBackoff
+	for i := 1; i < consecutiveFailures && backoff < maxFailureBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxFailureBackoff {
+		backoff = maxFailureBackoff
+	}
+	return backoff
+}
+
+// nextRunAt returns when a saved search should run next, given that its most recent run
+// finished at finishedAt after running for duration.
+//
+// If the saved search has a schedule, it is used. Otherwise, we assume a run interval of 30x that
+// which it takes to execute the query. For example, a query which takes 2s to execute will run
+// (2s*30) every minute. The forceRunInterval (if non-nil) overrides both.
+func nextRunAt(query api.ConfigSavedQuery, forceRunInterval *time.Duration, finishedAt time.Time, duration time.Duration, consecutiveFailures int) time.Time {
+	var next time.Time
+	switch {
+	case forceRunInterval != nil:
+		next = finishedAt.Add(*forceRunInterval)
+	case query.Schedule != nil:
+		s, err := schedule.Parse(*query.Schedule)
+		if err != nil {
+			// The schedule is validated when the saved search is saved, so this should never
+			// happen. Fall back to the default schedule.
+			log15.Error("executor: invalid saved search schedule", "schedule", *query.Schedule, "error", err)
+			break
+		}
+		next = s.Next(finishedAt)
+		if next.IsZero() {
+			// The schedule is validated when the saved search is saved, so this should never
+			// happen either. Don't fall back to the (much more frequent) default schedule for a
+			// saved search that is not supposed to run.
+			log15.Error("executor: saved search schedule never matches", "schedule", *query.Schedule)
+			next = finishedAt.Add(neverRunRecheckInterval)
+		}
+	}
+	if next.IsZero() {
+		// In case queries run very quickly (e.g. queries with no results often return in
+		// ~15ms), we impose a minimum run interval.
+		runInterval := duration * 30
+		if runInterval < minDefaultRunInterval {
+			runInterval = minDefaultRunInterval
+		}
+		next = finishedAt.Add(runInterval)
+	}
+
+	if backoffUntil := finishedAt.Add(failureBackoff(consecutiveFailures)); backoffUntil.After(next) {
+		next = backoffUntil
+	}
+	return next
+}
+
+// runTimeout returns the maximum duration of a run of the saved search.
+func runTimeout(query api.ConfigSavedQuery, defaultTimeout time.Duration) time.Duration {
+	if query.TimeoutSeconds != nil {
+		return time.Duration(*query.TimeoutSeconds) * time.Second
+	}
+	return defaultTimeout
+}
+
+// dueQuery is a saved search that is due to run.
+type dueQuery struct {
+	spec          api.SavedQueryIDSpec
+	query         api.ConfigSavedQuery
+	savedSearchID int32
+	state         *api.SavedQueryRunState // nil if the saved search has no run state yet
+}
+
+// dueQueries returns the saved searches that are due to run at now, in the order in which they
+// should run (the longest-overdue first). Saved searches that have nobody to notify are never due.
+func dueQueries(savedQueries map[api.SavedQueryIDSpec]api.ConfigSavedQuery, states map[int32]*api.SavedQueryRunState, now time.Time) []dueQuery {
+	var due []dueQuery
+	for spec, query := range savedQueries {
+		if !query.Notify && !query.NotifySlack && !query.HasWebhooks && !query.HasSubscriptions {
+			// No need to run this query because there will be nobody to notify.
+			continue
+		}
+		id, err := strconv.ParseInt(spec.Key, 10, 32)
+		if err != nil {
+			log15.Error("executor: invalid saved search ID", "key", spec.Key, "error", err)
+			continue
+		}
+		state := states[int32(id)]
+		if state != nil && state.NextRunAt != nil && state.NextRunAt.After(now) {
+			continue // too early to run the query
+		}
+		due = append(due, dueQuery{spec: spec, query: query, savedSearchID: int32(id), state: state})
+	}
+
+	// Saved searches that never ran (or were just updated) run first, then the saved searches
+	// that have been due the longest.
+	nextRunAt := func(q dueQuery) *time.Time {
+		if q.state == nil {
+			return nil
+		}
+		return q.state.NextRunAt
+	}
+	sort.Slice(due, func(i, j int) bool {
+		a, b := nextRunAt(due[i]), nextRunAt(due[j])
+		switch {
+		case a == nil && b == nil:
+			return due[i].savedSearchID < due[j].savedSearchID
+		case a == nil || b == nil:
+			return a == nil
+		case !a.Equal(*b):
+			return a.Before(*b)
+		default:
+			return due[i].savedSearchID < due[j].savedSearchID
+		}
+	})
+	return due
+}
+
+// consecutiveFailures returns the number of consecutive failed runs of the saved search,
+// including a run that just finished with runErr.
+func (q dueQuery) consecutiveFailures(runErr error) int {
+	if runErr == nil {
+		return 0
+	}
+	n := 1
+	if q.state != nil {
+		n += q.state.ConsecutiveFailures
+	}
+	return n
+}
+
+// unrecordedRuns are the runs of saved searches that could not be recorded, keyed by saved search
+// ID. Until a run is recorded, it determines when its saved search runs next (instead of the
+// listed run state, which predates the run), so that a saved search whose run could not be
+// recorded isn't run again immediately.
+type unrecordedRuns struct {
+	mu   sync.Mutex
+	runs map[int32]*unrecordedRun
+}
+
+type unrecordedRun struct {
+	run                 *api.SavedQueryRun
+	consecutiveFailures int
+}
+
+// add remembers the run, whose recording failed.
+func (u *unrecordedRuns) add(run *api.SavedQueryRun, consecutiveFailures int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.runs == nil {
+		u.runs = map[int32]*unrecordedRun{}
+	}
+	u.runs[run.SavedSearchID] = &unrecordedRun{run: run, consecutiveFailures: consecutiveFailures}
+}
+
+// remove forgets the unrecorded run of the saved search (if any), because a later run of it was
+// recorded.
+func (u *unrecordedRuns) remove(savedSearchID int32) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.runs, savedSearchID)
+}
+
+// retry tries again to record the unrecorded runs, and applies them to the listed run states.
+func (u *unrecordedRuns) retry(ctx context.Context, states map[int32]*api.SavedQueryRunState) {
+	u.mu.Lock()
+	runs := make([]*unrecordedRun, 0, len(u.runs))
+	for _, r := range u.runs {
+		runs = append(runs, r)
+	}
+	u.mu.Unlock()
+
+	for _, r := range runs {
+		run := r.run
+		if err := api.InternalClient.SavedQueriesRecordRun(ctx, run); err != nil {
+			log15.Error("executor: failed to record saved search run (will retry)", "savedSearchID", run.SavedSearchID, "error", err)
+		} else {
+			u.mu.Lock()
+			if u.runs[run.SavedSearchID] == r {
+				delete(u.runs, run.SavedSearchID)
+			}
+			u.mu.Unlock()
+		}
+		states[run.SavedSearchID] = &api.SavedQueryRunState{
+			SavedSearchID:       run.SavedSearchID,
+			NextRunAt:           &run.NextRunAt,
+			LastRunAt:           &run.StartedAt,
+			LastRunDuration:     run.Duration,
+			LastRunError:        run.Error,
+			ConsecutiveFailures: r.consecutiveFailures,
+		}
+	}
+}
+
+// concurrencyLimiter limits the number of saved searches that run concurrently, both in total and
+// per owner (user or organization), so that one owner's saved searches can't starve others'.
+type concurrencyLimiter struct {
+	max, maxPerOwner int
+
+	mu      sync.Mutex
+	total   int
+	byOwner map[string]int
+}
+
+func newConcurrencyLimiter(max, maxPerOwner int) *concurrencyLimiter {
+	return &concurrencyLimiter{max: max, maxPerOwner: maxPerOwner, byOwner: map[string]int{}}
+}
+
+// tryAcquire reports whether a saved search of the owner may run now. If so, the caller must call
+// release when the run is done.
+func (l *concurrencyLimiter) tryAcquire(owner string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.total >= l.max || l.byOwner[owner] >= l.maxPerOwner {
+		return false
+	}
+	l.total++
+	l.byOwner[owner]++
+	return true
+}
+
+func (l *concurrencyLimiter) release(owner string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.total--
+	if l.byOwner[owner]--; l.byOwner[owner] <= 0 {
+		delete(l.byOwner, owner)
+	}
+}
+
+// full reports whether the total concurrency limit is reached.
+func (l *concurrencyLimiter) full() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.total >= l.max
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+func TestFailureBackoff(t *testing.T) {
+	tests := map[int]time.Duration{
+		0:   0,
+		1:   time.Minute,
+		2:   2 * time.Minute,
+		5:   16 * time.Minute,
+		9:   256 * time.Minute,
+		10:  maxFailureBackoff,
+		100: maxFailureBackoff,
+	}
+	for consecutiveFailures, want := range tests {
+		if got := failureBackoff(consecutiveFailures); got != want {
+			t.Errorf("%d failures: got %s, want %s", consecutiveFailures, got, want)
+		}
+	}
+}
+
+func TestNextRunAt(t *testing.T) {
+	finishedAt := time.Date(2019, 3, 6, 10, 15, 30, 0, time.UTC)
+	hourly := "@hourly"
+	invalid := "every hour"
+	never := "0 0 31 2 *"
+	forced := 2 * time.Minute
+
+	tests := []struct {
+		name                string
+		schedule            *string
+		forceRunInterval    *time.Duration
+		duration            time.Duration
+		consecutiveFailures int
+		want                time.Time
+	}{
+		{
+			name:     "default schedule",
+			duration: 4 * time.Second,
+			want:     finishedAt.Add(2 * time.Minute),
+		},
+		{
+			name:     "default schedule with minimum interval",
+			duration: 15 * time.Millisecond,
+			want:     finishedAt.Add(minDefaultRunInterval),
+		},
+		{
+			name:     "schedule",
+			schedule: &hourly,
+			duration: time.Hour,
+			want:     time.Date(2019, 3, 6, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "invalid schedule",
+			schedule: &invalid,
+			duration: time.Second,
+			want:     finishedAt.Add(30 * time.Second),
+		},
+		{
+			name:     "schedule that never matches",
+			schedule: &never,
+			duration: time.Second,
+			want:     finishedAt.Add(neverRunRecheckInterval),
+		},
+		{
+			name:             "forced interval",
+			schedule:         &hourly,
+			forceRunInterval: &forced,
+			duration:         time.Second,
+			want:             finishedAt.Add(forced),
+		},
+		{
+			name:                "backoff later than schedule",
+			schedule:            &hourly,
+			duration:            time.Second,
+			consecutiveFailures: 7,
+			want:                finishedAt.Add(64 * time.Minute),
+		},
+		{
+			name:                "backoff earlier than schedule",
+			schedule:            &hourly,
+			duration:            time.Second,
+			consecutiveFailures: 1,
+			want:                time.Date(2019, 3, 6, 11, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query := api.ConfigSavedQuery{Schedule: test.schedule}
+			got := nextRunAt(query, test.forceRunInterval, finishedAt, test.duration, test.consecutiveFailures)
+			if !got.Equal(test.want) {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDueQueries(t *testing.T) {
+	now := time.Date(2019, 3, 6, 10, 0, 0, 0, time.UTC)
+	past1, past2, future := now.Add(-time.Hour), now.Add(-time.Minute), now.Add(time.Minute)
+	spec := func(key string) api.SavedQueryIDSpec {
+		userID := int32(1)
+		return api.SavedQueryIDSpec{Subject: api.SettingsSubject{User: &userID}, Key: key}
+	}
+	notify := api.ConfigSavedQuery{Notify: true}
+
+	savedQueries := map[api.SavedQueryIDSpec]api.ConfigSavedQuery{
+		spec("1"): notify,                                    // due since past2
+		spec("2"): notify,                                    // due since past1
+		spec("3"): notify,                                    // not due yet
+		spec("4"): notify,                                    // never ran
+		spec("5"): {},                                        // nobody to notify
+		spec("6"): {HasSubscriptions: true},                  // updated (so next run is unset)
+		spec("7"): {HasWebhooks: true, Query: "type:commit"}, // due at now
+	}
+	states := map[int32]*api.SavedQueryRunState{
+		1: {SavedSearchID: 1, NextRunAt: &past2},
+		2: {SavedSearchID: 2, NextRunAt: &past1},
+		3: {SavedSearchID: 3, NextRunAt: &future},
+		5: {SavedSearchID: 5, NextRunAt: &past1},
+		6: {SavedSearchID: 6, ConsecutiveFailures: 0},
+		7: {SavedSearchID: 7, NextRunAt: &now},
+	}
+
+	var got []int32
+	for _, q := range dueQueries(savedQueries, states, now) {
+		got = append(got, q.savedSearchID)
+		if q.state != states[q.savedSearchID] {
+			t.Errorf("saved search %d: got state %+v, want %+v", q.savedSearchID, q.state, states[q.savedSearchID])
+		}
+	}
+	if want := []int32{4, 6, 2, 1, 7}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestConcurrencyLimiter(t *testing.T) {
+	l := newConcurrencyLimiter(2, 1)
+	if !l.tryAcquire("user 1") {
+		t.Fatal("want user 1 to acquire")
+	}
+	if l.tryAcquire("user 1") {
+		t.Fatal("want user 1 to be limited by the per-owner limit")
+	}
+	if !l.tryAcquire("org 1") {
+		t.Fatal("want org 1 to acquire")
+	}
+	if !l.full() {
+		t.Fatal("want limiter to be full")
+	}
+	if l.tryAcquire("user 2") {
+		t.Fatal("want user 2 to be limited by the total limit")
+	}
+	l.release("user 1")
+	if l.full() {
+		t.Fatal("want limiter to not be full")
+	}
+	if !l.tryAcquire("user 1") {
+		t.Fatal("want user 1 to acquire after release")
+	}
+}
+
+func TestUnrecordedRuns(t *testing.T) {
+	var recorded []int32
+	fail := true
+	api.MockSavedQueriesRecordRun = func(run *api.SavedQueryRun) error {
+		if fail {
+			return errors.New("x")
+		}
+		recorded = append(recorded, run.SavedSearchID)
+		return nil
+	}
+	defer func() { api.MockSavedQueriesRecordRun = nil }()
+
+	now := time.Date(2019, 3, 6, 10, 0, 0, 0, time.UTC)
+	next := now.Add(time.Hour)
+	var u unrecordedRuns
+	u.add(&api.SavedQueryRun{SavedSearchID: 1, StartedAt: now, NextRunAt: next}, 2)
+	u.add(&api.SavedQueryRun{SavedSearchID: 2, StartedAt: now, NextRunAt: next}, 0)
+	u.remove(2) // a later run of saved search 2 was recorded
+
+	// While recording still fails, the unrecorded run determines when the saved search runs next.
+	past := now.Add(-time.Hour)
+	states := map[int32]*api.SavedQueryRunState{1: {SavedSearchID: 1, NextRunAt: &past}}
+	u.retry(context.Background(), states)
+	want := &api.SavedQueryRunState{SavedSearchID: 1, NextRunAt: &next, LastRunAt: &now, ConsecutiveFailures: 2}
+	if !reflect.DeepEqual(states[1], want) {
+		t.Errorf("got state %+v, want %+v", states[1], want)
+	}
+	if len(dueQueries(map[api.SavedQueryIDSpec]api.ConfigSavedQuery{{Key: "1"}: {Notify: true}}, states, now)) != 0 {
+		t.Error("want saved search 1 to not be due")
+	}
+
+	// Once recording succeeds, the run is forgotten.
+	fail = false
+	u.retry(context.Background(), map[int32]*api.SavedQueryRunState{})
+	u.retry(context.Background(), map[int32]*api.SavedQueryRunState{})
+	if want := []int32{1}; !reflect.DeepEqual(recorded, want) {
+		t.Errorf("got recorded runs %v, want %v", recorded, want)
+	}
+}
@@ -1,34 +1,78 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/pkg/errors"
 	log15 "gopkg.in/inconshreveable/log15.v2"
 
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/slack"
 )
 
-func (n *notifier) slackNotify(ctx context.Context) {
+func (n *notifier) slackNotify(ctx context.Context) error {
+	var failed bool
+	for _, recipient := range n.recipients {
+		if !recipient.slack {
+			continue
+		}
+		newMatches, err := n.recipientMatches(ctx, recipient)
+		if err != nil {
+			log15.Error("Failed to determine the new saved search results that the Slack notification recipient can read.", "recipient", recipient, "error", err)
+			failed = true
+			continue
+		}
+		if len(newMatches) == 0 {
+			continue // the recipient can't read any of the new results
+		}
+		text := n.slackText(newMatches)
+		if err := retryNotify(ctx, func() error {
+			return slackNotify(ctx, recipient, text, n.query.SlackWebhookURL)
+		}); err != nil {
+			log15.Error("Failed to post Slack notification message.", "recipient", recipient, "text", text, "error", err)
+			failed = true
+		}
+	}
+	// TODO(Dan): find all users in the recipient list and log events for all of them
+	logEvent(0, "", "SavedSearchSlackNotificationSent", "results")
+	if failed {
+		return errors.New("failed to post Slack notification messages to some recipients")
+	}
+	return nil
+}
+
+// slackText returns the text of a Slack message about the new matches.
+func (n *notifier) slackText(newMatches []*resultMatch) string {
 	plural := ""
-	if n.results.Data.Search.Results.ApproximateResultCount != "1" {
+	if len(newMatches) != 1 {
 		plural = "s"
 	}
 
-	text := fmt.Sprintf(`*%s* new result%s found for saved search <%s|"%s">`,
-		n.results.Data.Search.Results.ApproximateResultCount,
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `*%d* new result%s found for saved search <%s|"%s">`,
+		len(newMatches),
 		plural,
-		searchURL(n.newQuery, utmSourceSlack),
+		searchURL(n.query.Query, utmSourceSlack),
 		n.query.Description,
 	)
-	for _, recipient := range n.recipients {
-		if err := slackNotify(ctx, recipient, text, n.query.SlackWebhookURL); err != nil {
-			log15.Error("Failed to post Slack notification message.", "recipient", recipient, "text", text, "error", err)
+	matches, omittedMatches := notificationMatches(newMatches, utmSourceSlack)
+	for _, m := range matches {
+		label := m.Repo
+		if m.Path != "" {
+			label += " › " + m.Path
+		}
+		fmt.Fprintf(&buf, "\n• <%s|%s>", m.URL, slackEscape(label))
+		if m.Preview != "" {
+			fmt.Fprintf(&buf, " `%s`", slackEscape(strings.Replace(m.Preview, "`", "'", -1)))
 		}
 	}
-	// TODO(Dan): find all users in the recipient list and log events for all of them
-	logEvent(0, "", "SavedSearchSlackNotificationSent", "results")
+	if omittedMatches > 0 {
+		fmt.Fprintf(&buf, "\n...and %d more.", omittedMatches)
+	}
+	return buf.String()
 }
 
 func slackNotifySubscribed(ctx context.Context, recipient *recipient, query api.SavedQuerySpecAndConfig) error {
@@ -61,6 +105,9 @@ func slackNotify(ctx context.Context, recipient *recipient, text string, slackWe
 	if !recipient.slack {
 		return nil
 	}
+	if recipient.slackWebhookURL != "" {
+		slackWebhookURL = &recipient.slackWebhookURL
+	}
 
 	if slackWebhookURL == nil || *slackWebhookURL == "" {
 		return fmt.Errorf("unable to send Slack notification because recipient (%s) has no Slack webhook URL configured", recipient.spec)
@@ -76,3 +123,8 @@ func slackNotify(ctx context.Context, recipient *recipient, text string, slackWe
 	client := slack.New(*slackWebhookURL, true)
 	return slack.Post(payload, client.WebhookURL)
 }
+
+// slackEscape escapes the characters that have special meaning in Slack message text.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
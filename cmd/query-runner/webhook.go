@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/pkg/errors"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/httpcli"
+)
+
+// Saved search webhook events (the "event" payload field and X-Sourcegraph-Event header).
+const (
+	webhookEventNewResults = "saved_search.new_results"
+	webhookEventTest       = "saved_search.test"
+)
+
+// maxWebhookMatches is the maximum number of new matches that are included in a webhook payload.
+const maxWebhookMatches = 100
+
+// webhookPayload is the JSON request body of a saved search webhook delivery.
+type webhookPayload struct {
+	Event          string             `json:"event"`
+	SavedSearch    webhookSavedSearch `json:"savedSearch"`
+	NewResultCount int                `json:"newResultCount"`
+	Matches        []webhookMatch     `json:"matches"` // the first maxWebhookMatches new matches
+}
+
+type webhookSavedSearch struct {
+	ID          string `json:"id"` // the GraphQL ID
+	Description string `json:"description"`
+	Query       string `json:"query"`
+	URL         string `json:"url"` // the URL to the search results
+}
+
+type webhookMatch struct {
+	Repository string `json:"repository"`
+	Path       string `json:"path,omitempty"`
+	Preview    string `json:"preview,omitempty"` // the line preview, symbol name, or commit subject
+	URL        string `json:"url"`
+}
+
+const utmSourceWebhook = "saved-search-webhook"
+
+// createWebhookDeliveries creates a pending delivery of the event to each of the saved search's
+// webhooks. The deliveries are sent by the webhook delivery worker (see runWebhookDeliveries).
+func createWebhookDeliveries(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSavedQuery, event string, newMatches []*resultMatch) error {
+	if !query.HasWebhooks {
+		return nil
+	}
+	savedSearchID, err := strconv.ParseInt(spec.Key, 10, 32)
+	if err != nil {
+		return errors.Wrap(err, "parsing saved search ID")
+	}
+
+	payload := webhookPayload{
+		Event: event,
+		SavedSearch: webhookSavedSearch{
+			ID:          string(relay.MarshalID("SavedSearch", int32(savedSearchID))),
+			Description: query.Description,
+			Query:       query.Query,
+			URL:         searchURL(query.Query, utmSourceWebhook),
+		},
+		NewResultCount: len(newMatches),
+		Matches:        []webhookMatch{},
+	}
+	for i, m := range newMatches {
+		if i == maxWebhookMatches {
+			break
+		}
+		payload.Matches = append(payload.Matches, webhookMatch{
+			Repository: m.Repo,
+			Path:       m.Path,
+			Preview:    m.Preview,
+			URL:        matchURL(m.URL, utmSourceWebhook),
+		})
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	n, err := api.InternalClient.SavedQueriesCreateWebhookDeliveries(ctx, api.SavedQueryWebhookDeliveriesCreateArgs{
+		SavedSearchID: int32(savedSearchID),
+		Event:         event,
+		Payload:       string(body),
+	})
+	if err != nil {
+		return errors.Wrap(err, "SavedQueriesCreateWebhookDeliveries")
+	}
+	log15.Debug("Created saved search webhook deliveries.", "event", event, "count", n, "description", query.Description)
+	return nil
+}
+
+func (n *notifier) webhookNotify(ctx context.Context) error {
+	if !n.query.HasWebhooks {
+		return nil
+	}
+	// Webhooks are configured by the owner of the saved search, so they may only receive the
+	// matches that the owner (or all members of the owning org) can read.
+	userIDs, err := n.ownerUserIDs(ctx)
+	if err != nil {
+		return err
+	}
+	newMatches, err := n.matchesReadableBy(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+	if len(newMatches) == 0 {
+		return nil
+	}
+	if err := retryNotify(ctx, func() error {
+		return createWebhookDeliveries(ctx, n.spec, n.query, webhookEventNewResults, newMatches)
+	}); err != nil {
+		log15.Error("Failed to create webhook deliveries for new saved search results.", "description", n.query.Description, "error", err)
+		return err
+	}
+	return nil
+}
+
+// webhookRetryDelays are the delays before retrying a failed webhook delivery. The i'th element is
+// the delay after the i'th failed attempt (starting at 0). A delivery is marked as failed when all
+// retries have failed.
+var webhookRetryDelays = []time.Duration{
+	1 * time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+	6 * time.Hour,
+}
+
+// webhookHTTPClient is the HTTP client for webhook deliveries.
+//
+// 🚨 SECURITY: Webhook URLs are user-specified, so the client must only connect to public
+// addresses. Otherwise users could make requests to internal services (and see their response
+// status codes).
+var webhookHTTPClient = func() *http.Client {
+	cli, err := httpcli.NewFactory(nil, httpcli.PublicAddressesOpt).Client()
+	if err != nil {
+		panic(err)
+	}
+	cli.Timeout = 30 * time.Second
+	return cli
+}()
+
+// webhookDeliveryConcurrency is the maximum number of webhook deliveries that are sent
+// concurrently.
+const webhookDeliveryConcurrency = 10
+
+// runWebhookDeliveries periodically sends the saved search webhook deliveries that are due. It
+// never returns.
+func runWebhookDeliveries(ctx context.Context) {
+	for {
+		deliveries, err := api.InternalClient.SavedQueriesListDueWebhookDeliveries(ctx)
+		if err != nil {
+			log15.Error("Failed to list due saved search webhook deliveries.", "error", err)
+		}
+		// Send the deliveries with a bounded number of workers, so that slow webhook endpoints
+		// don't hold up the other deliveries. All deliveries are finished before listing the due
+		// deliveries again, so that no delivery is sent twice concurrently.
+		sem := make(chan struct{}, webhookDeliveryConcurrency)
+		var wg sync.WaitGroup
+		for _, d := range deliveries {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(d *api.SavedQueryWebhookDelivery) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				attempt := deliverWebhook(ctx, d, time.Now())
+				if attempt.Error != nil {
+					log15.Warn("Saved search webhook delivery attempt failed.", "delivery", d.ID, "url", d.URL, "attempts", d.Attempts+1, "willRetry", attempt.NextAttemptAt != nil, "error", *attempt.Error)
+				}
+				if err := api.InternalClient.SavedQueriesRecordWebhookDeliveryAttempt(ctx, attempt); err != nil {
+					log15.Error("Failed to record saved search webhook delivery attempt.", "delivery", d.ID, "error", err)
+				}
+			}(d)
+		}
+		wg.Wait()
+		if len(deliveries) == 0 {
+			time.Sleep(10 * time.Second)
+		}
+	}
+}
+
+// deliverWebhook attempts to deliver the saved search webhook notification and returns the outcome
+// of the attempt (including when to retry, if it failed).
+func deliverWebhook(ctx context.Context, d *api.SavedQueryWebhookDelivery, now time.Time) *api.SavedQueryWebhookDeliveryAttempt {
+	attempt := &api.SavedQueryWebhookDeliveryAttempt{DeliveryID: d.ID}
+
+	statusCode, err := postWebhook(ctx, d)
+	if statusCode != 0 {
+		statusCode := int32(statusCode)
+		attempt.ResponseStatusCode = &statusCode
+	}
+	if err == nil {
+		attempt.Success = true
+		return attempt
+	}
+
+	errStr := err.Error()
+	attempt.Error = &errStr
+	if int(d.Attempts) < len(webhookRetryDelays) {
+		nextAttemptAt := now.Add(webhookRetryDelays[d.Attempts])
+		attempt.NextAttemptAt = &nextAttemptAt
+	}
+	return attempt
+}
+
+// postWebhook sends the webhook request and returns the HTTP response status code (or 0 if there
+// was no response). It returns an error if the request failed or the response status was not 2xx.
+func postWebhook(ctx context.Context, d *api.SavedQueryWebhookDelivery) (statusCode int, err error) {
+	req, err := http.NewRequest("POST", d.URL, bytes.NewReader([]byte(d.Payload)))
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Sourcegraph-Webhook")
+	req.Header.Set("X-Sourcegraph-Event", d.Event)
+	req.Header.Set("X-Sourcegraph-Delivery", strconv.Itoa(int(d.ID)))
+	req.Header.Set("X-Sourcegraph-Signature", webhookSignature(d.Secret, []byte(d.Payload)))
+
+	resp, err := webhookHTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1024*1024))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected HTTP response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// webhookSignature returns the value of the X-Sourcegraph-Signature header for the request body:
+// "sha256=" followed by the hex-encoded HMAC-SHA256 of the body, keyed by the webhook's secret.
+func webhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+func TestDeliverWebhook(t *testing.T) {
+	const payload = `{"event":"saved_search.test"}`
+
+	var statusCode int
+	var gotHeader http.Header
+	var gotBody string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header
+		body, _ := ioutil.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(statusCode)
+	}))
+	defer s.Close()
+
+	// The test server listens on a loopback address, which the default client does not connect to
+	// (see TestDeliverWebhook_nonPublicAddress).
+	orig := webhookHTTPClient
+	webhookHTTPClient = &http.Client{Timeout: 5 * time.Second}
+	defer func() { webhookHTTPClient = orig }()
+
+	now := time.Now()
+	newDelivery := func(attempts int32) *api.SavedQueryWebhookDelivery {
+		return &api.SavedQueryWebhookDelivery{ID: 7, URL: s.URL, Secret: "s", Event: webhookEventTest, Payload: payload, Attempts: attempts}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		statusCode = http.StatusNoContent
+		attempt := deliverWebhook(context.Background(), newDelivery(0), now)
+		if !attempt.Success || attempt.Error != nil || attempt.NextAttemptAt != nil || attempt.DeliveryID != 7 {
+			t.Errorf("got attempt %+v, want success", attempt)
+		}
+		if attempt.ResponseStatusCode == nil || *attempt.ResponseStatusCode != http.StatusNoContent {
+			t.Errorf("got response status code %v, want %d", attempt.ResponseStatusCode, http.StatusNoContent)
+		}
+		if gotBody != payload {
+			t.Errorf("got body %q, want %q", gotBody, payload)
+		}
+		for name, want := range map[string]string{
+			"Content-Type":            "application/json",
+			"X-Sourcegraph-Event":     webhookEventTest,
+			"X-Sourcegraph-Delivery":  "7",
+			"X-Sourcegraph-Signature": webhookSignature("s", []byte(payload)),
+		} {
+			if got := gotHeader.Get(name); got != want {
+				t.Errorf("got header %s %q, want %q", name, got, want)
+			}
+		}
+	})
+
+	t.Run("failure with retry", func(t *testing.T) {
+		statusCode = http.StatusInternalServerError
+		attempt := deliverWebhook(context.Background(), newDelivery(1), now)
+		if attempt.Success || attempt.Error == nil {
+			t.Errorf("got attempt %+v, want failure", attempt)
+		}
+		if want := now.Add(webhookRetryDelays[1]); attempt.NextAttemptAt == nil || !attempt.NextAttemptAt.Equal(want) {
+			t.Errorf("got next attempt at %v, want %v", attempt.NextAttemptAt, want)
+		}
+	})
+
+	t.Run("final failure", func(t *testing.T) {
+		statusCode = http.StatusInternalServerError
+		attempt := deliverWebhook(context.Background(), newDelivery(int32(len(webhookRetryDelays))), now)
+		if attempt.Success || attempt.Error == nil || attempt.NextAttemptAt != nil {
+			t.Errorf("got attempt %+v, want final failure", attempt)
+		}
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		d := newDelivery(0)
+		d.URL = "http://127.0.0.1:0"
+		attempt := deliverWebhook(context.Background(), d, now)
+		if attempt.Success || attempt.Error == nil || attempt.ResponseStatusCode != nil || attempt.NextAttemptAt == nil {
+			t.Errorf("got attempt %+v, want failure with retry and no response status code", attempt)
+		}
+	})
+}
+
+func TestDeliverWebhook_nonPublicAddress(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("webhook was delivered to a non-public address")
+	}))
+	defer s.Close()
+
+	for _, url := range []string{s.URL, "http://169.254.169.254/latest/meta-data/", "http://10.0.0.1/"} {
+		d := &api.SavedQueryWebhookDelivery{ID: 7, URL: url, Event: webhookEventTest, Payload: "{}"}
+		attempt := deliverWebhook(context.Background(), d, time.Now())
+		if attempt.Success || attempt.Error == nil || !strings.Contains(*attempt.Error, "non-public address") {
+			t.Errorf("%s: got attempt %+v, want failure due to non-public address", url, attempt)
+		}
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	// Computed with: printf '{"a":1}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=aa9e2e3575f5d7098b6caccd790888c36d5fdb63342a73bada2d6a51747a8494"
+	if got := webhookSignature("secret", []byte(`{"a":1}`)); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
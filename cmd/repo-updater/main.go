@@ -98,6 +98,9 @@ func main() {
 	}
 
 	scheduler := repos.NewUpdateScheduler()
+	if err := scheduler.UseDemandStore(ctx, repos.NewDBStore(ctx, db, sql.TxOptions{})); err != nil {
+		log15.Error("Failed to load repository demand, demand will not be persisted.", "error", err)
+	}
 	server := repoupdater.Server{
 		Store:           store,
 		Scheduler:       scheduler,
@@ -97,8 +97,11 @@ func (s BitbucketCloudSource) makeRepo(r *bitbucketcloud.Repo) *Repo {
 			ServiceID:   host.String(),
 		},
 		Description: r.Description,
+		Language:    r.Language,
 		Fork:        r.Parent != nil,
 		Enabled:     true,
+		Private:     r.IsPrivate,
+		PushedAt:    timeValue(r.UpdatedOn),
 		Sources: map[string]*SourceInfo{
 			urn: {
 				ID:       urn,
@@ -183,6 +183,7 @@ func (s BitbucketServerSource) makeRepo(repo *bitbucketserver.Repo) *Repo {
 		Description: repo.Name,
 		Fork:        repo.Origin != nil,
 		Enabled:     true,
+		Private:     !repo.Public,
 		Sources: map[string]*SourceInfo{
 			urn: {
 				ID:       urn,
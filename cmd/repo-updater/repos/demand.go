@@ -0,0 +1,272 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/repoupdater/protocol"
+)
+
+const (
+	// hotDemandWindow is how long a repository stays hot after a demand signal.
+	hotDemandWindow = 24 * time.Hour
+
+	// coldDemandWindow is how long a repository must go without demand signals
+	// before it is considered cold.
+	coldDemandWindow = 14 * 24 * time.Hour
+
+	// hotMaxDelay is the maximum amount of time between scheduled updates for a
+	// hot repository.
+	hotMaxDelay = time.Hour
+
+	// coldMaxDelay is the maximum amount of time between scheduled updates for a
+	// cold repository.
+	coldMaxDelay = 48 * time.Hour
+
+	// coldBackoffFactor is the factor by which the update interval of a cold
+	// repository is increased.
+	coldBackoffFactor = 4
+)
+
+// temperature is how much users are interested in a repository.
+type temperature string
+
+const (
+	hot  temperature = "hot"  // there was demand recently (within hotDemandWindow)
+	warm temperature = "warm" // neither hot nor cold
+	cold temperature = "cold" // there was no demand for a long time (coldDemandWindow)
+)
+
+// repoDemand is the demand for a single repository.
+type repoDemand struct {
+	// Last is the last time each kind of demand signal was recorded.
+	Last map[protocol.RepoDemandKind]time.Time
+
+	// Temperature is the temperature that the current interval is based on.
+	Temperature temperature
+	// IntervalReason explains how the current interval was chosen.
+	IntervalReason string
+}
+
+// demandTracker tracks the demand signals of repositories (views, searches and
+// saved searches) and adjusts their update intervals accordingly:
+//
+// Hot repositories are enqueued with a higher priority when they are due and
+// their interval is capped at hotMaxDelay.
+//
+// Cold repositories back off coldBackoffFactor times further than the commit
+// frequency heuristic suggests, up to coldMaxDelay.
+//
+// Warm repositories are scheduled based on their commit frequency alone.
+//
+// Demand signals are persisted in a DemandStore (if set), so that the demand
+// survives restarts. Until demand signals have been received for
+// coldDemandWindow, no repository is considered cold.
+type demandTracker struct {
+	mu sync.Mutex
+
+	// since is when the first demand signal was recorded.
+	since time.Time
+	repos map[uint32]*repoDemand
+}
+
+func newDemandTracker() *demandTracker {
+	return &demandTracker{repos: make(map[uint32]*repoDemand)}
+}
+
+// RepoDemandRecord is the persisted demand of one kind for a repository.
+type RepoDemandRecord struct {
+	RepoID  uint32
+	Kind    protocol.RepoDemandKind
+	FirstAt time.Time // the first time a signal of this kind was recorded
+	LastAt  time.Time // the last time a signal of this kind was recorded
+}
+
+// A DemandStore persists the demand signals of repositories.
+type DemandStore interface {
+	ListRepoDemand(ctx context.Context) ([]*RepoDemandRecord, error)
+	RecordRepoDemand(ctx context.Context, kind protocol.RepoDemandKind, at time.Time, ids ...uint32) error
+}
+
+// load adds the persisted demand signals to the tracked demand.
+func (d *demandTracker) load(records []*RepoDemandRecord) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, r := range records {
+		if d.since.IsZero() || r.FirstAt.Before(d.since) {
+			d.since = r.FirstAt
+		}
+
+		demand := d.repos[r.RepoID]
+		if demand == nil {
+			demand = &repoDemand{Last: map[protocol.RepoDemandKind]time.Time{}}
+			d.repos[r.RepoID] = demand
+		}
+		if r.LastAt.After(demand.Last[r.Kind]) {
+			demand.Last[r.Kind] = r.LastAt
+		}
+	}
+}
+
+// record records a demand signal of the given kind for the repository with
+// the given ID at now.
+func (d *demandTracker) record(kind protocol.RepoDemandKind, id uint32, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.since.IsZero() {
+		d.since = now
+	}
+
+	demand := d.repos[id]
+	if demand == nil {
+		demand = &repoDemand{Last: map[protocol.RepoDemandKind]time.Time{}}
+		d.repos[id] = demand
+	}
+	if now.After(demand.Last[kind]) {
+		demand.Last[kind] = now
+	}
+}
+
+// forget removes the demand of the repository with the given ID.
+func (d *demandTracker) forget(id uint32) {
+	d.mu.Lock()
+	delete(d.repos, id)
+	d.mu.Unlock()
+}
+
+// temperature returns the temperature of the repository with the given ID at
+// now, together with a description of the demand it is based on.
+func (d *demandTracker) temperature(id uint32, now time.Time) (temperature, string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.temperatureLocked(id, now)
+}
+
+func (d *demandTracker) temperatureLocked(id uint32, now time.Time) (temperature, string) {
+	var (
+		lastKind protocol.RepoDemandKind
+		lastAt   time.Time
+	)
+	if demand := d.repos[id]; demand != nil {
+		for kind, at := range demand.Last {
+			if at.After(lastAt) || (at.Equal(lastAt) && kind < lastKind) {
+				lastKind, lastAt = kind, at
+			}
+		}
+	}
+
+	if !lastAt.IsZero() && now.Sub(lastAt) <= hotDemandWindow {
+		return hot, fmt.Sprintf("%s %s ago", describeDemandKind(lastKind), now.Sub(lastAt).Round(time.Second))
+	}
+
+	// Repositories without demand signals are only cold if we have been
+	// receiving demand signals for long enough to tell.
+	quietSince := lastAt
+	if d.since.After(quietSince) {
+		quietSince = d.since
+	}
+	if !d.since.IsZero() && now.Sub(quietSince) > coldDemandWindow {
+		if lastAt.IsZero() {
+			return cold, fmt.Sprintf("no demand in the last %s", coldDemandWindow)
+		}
+		return cold, fmt.Sprintf("last %s %s ago", describeDemandKind(lastKind), now.Sub(lastAt).Round(time.Second))
+	}
+
+	if lastAt.IsZero() {
+		return warm, "no recent demand"
+	}
+	return warm, fmt.Sprintf("last %s %s ago", describeDemandKind(lastKind), now.Sub(lastAt).Round(time.Second))
+}
+
+func describeDemandKind(kind protocol.RepoDemandKind) string {
+	switch kind {
+	case protocol.RepoDemandView:
+		return "viewed"
+	case protocol.RepoDemandSearch:
+		return "searched"
+	case protocol.RepoDemandSavedSearch:
+		return "searched by a saved search"
+	}
+	return string(kind)
+}
+
+// priority returns the priority with which the repository with the given ID
+// is enqueued when it is due for an update.
+func (d *demandTracker) priority(id uint32, now time.Time) priority {
+	if t, _ := d.temperature(id, now); t == hot {
+		return priorityMedium
+	}
+	return priorityLow
+}
+
+// adjust returns the update interval for the repository with the given ID,
+// given the interval computed from its commit frequency, and records why that
+// interval was chosen.
+func (d *demandTracker) adjust(id uint32, interval time.Duration, now time.Time) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	temp, demandReason := d.temperatureLocked(id, now)
+
+	adjusted, max := interval, maxDelay
+	reason := fmt.Sprintf("half the time since the repository last changed is %s", interval)
+	switch temp {
+	case hot:
+		max = hotMaxDelay
+	case cold:
+		adjusted, max = interval*coldBackoffFactor, coldMaxDelay
+		reason += fmt.Sprintf(", backed off %dx to %s", coldBackoffFactor, adjusted)
+	}
+
+	switch {
+	case adjusted > max:
+		reason += fmt.Sprintf(", capped at the %s maximum of %s", temp, max)
+		adjusted = max
+	case adjusted < minDelay:
+		reason += fmt.Sprintf(", raised to the minimum of %s", minDelay)
+		adjusted = minDelay
+	}
+
+	d.setIntervalReasonLocked(id, temp, fmt.Sprintf("%s (%s): %s", temp, demandReason, reason))
+	return adjusted
+}
+
+// setIntervalReason records the temperature that the current interval of the
+// repository with the given ID is based on, and why it was chosen.
+func (d *demandTracker) setIntervalReason(id uint32, temp temperature, reason string) {
+	d.mu.Lock()
+	d.setIntervalReasonLocked(id, temp, reason)
+	d.mu.Unlock()
+}
+
+func (d *demandTracker) setIntervalReasonLocked(id uint32, temp temperature, reason string) {
+	demand := d.repos[id]
+	if demand == nil {
+		demand = &repoDemand{Last: map[protocol.RepoDemandKind]time.Time{}}
+		d.repos[id] = demand
+	}
+	demand.Temperature = temp
+	demand.IntervalReason = reason
+}
+
+// info returns a copy of the demand of the repository with the given ID, or
+// nil if there is none.
+func (d *demandTracker) info(id uint32) *repoDemand {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	demand := d.repos[id]
+	if demand == nil {
+		return nil
+	}
+	c := *demand
+	c.Last = make(map[protocol.RepoDemandKind]time.Time, len(demand.Last))
+	for kind, at := range demand.Last {
+		c.Last[kind] = at
+	}
+	return &c
+}
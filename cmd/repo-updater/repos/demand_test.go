@@ -0,0 +1,203 @@
+package repos
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/repoupdater/protocol"
+)
+
+func TestDemandTracker_adjust(t *testing.T) {
+	now := defaultTime.Add(30 * 24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		since    time.Time // when the tracker started receiving signals
+		last     map[protocol.RepoDemandKind]time.Time
+		interval time.Duration
+		want     time.Duration
+		wantTemp temperature
+		reason   string
+	}{
+		{
+			name:     "no signals received yet",
+			interval: 2 * time.Hour,
+			want:     2 * time.Hour,
+			wantTemp: warm,
+			reason:   "warm (no recent demand): half the time since the repository last changed is 2h0m0s",
+		},
+		{
+			name:     "viewed recently",
+			since:    defaultTime,
+			last:     map[protocol.RepoDemandKind]time.Time{protocol.RepoDemandView: now.Add(-2 * time.Hour)},
+			interval: 6 * time.Hour,
+			want:     hotMaxDelay,
+			wantTemp: hot,
+			reason:   "hot (viewed 2h0m0s ago): half the time since the repository last changed is 6h0m0s, capped at the hot maximum of 1h0m0s",
+		},
+		{
+			name:  "most recent signal wins",
+			since: defaultTime,
+			last: map[protocol.RepoDemandKind]time.Time{
+				protocol.RepoDemandView:        now.Add(-20 * 24 * time.Hour),
+				protocol.RepoDemandSavedSearch: now.Add(-time.Minute),
+			},
+			interval: 10 * time.Minute,
+			want:     10 * time.Minute,
+			wantTemp: hot,
+			reason:   "hot (searched by a saved search 1m0s ago): half the time since the repository last changed is 10m0s",
+		},
+		{
+			name:     "searched a few days ago",
+			since:    defaultTime,
+			last:     map[protocol.RepoDemandKind]time.Time{protocol.RepoDemandSearch: now.Add(-72 * time.Hour)},
+			interval: 12 * time.Hour,
+			want:     maxDelay,
+			wantTemp: warm,
+			reason:   "warm (last searched 72h0m0s ago): half the time since the repository last changed is 12h0m0s, capped at the warm maximum of 8h0m0s",
+		},
+		{
+			name:     "never viewed",
+			since:    defaultTime,
+			interval: 2 * time.Hour,
+			want:     8 * time.Hour,
+			wantTemp: cold,
+			reason:   "cold (no demand in the last 336h0m0s): half the time since the repository last changed is 2h0m0s, backed off 4x to 8h0m0s",
+		},
+		{
+			name:     "not viewed for weeks",
+			since:    defaultTime,
+			last:     map[protocol.RepoDemandKind]time.Time{protocol.RepoDemandView: now.Add(-20 * 24 * time.Hour)},
+			interval: 24 * time.Hour,
+			want:     coldMaxDelay,
+			wantTemp: cold,
+			reason:   "cold (last viewed 480h0m0s ago): half the time since the repository last changed is 24h0m0s, backed off 4x to 96h0m0s, capped at the cold maximum of 48h0m0s",
+		},
+		{
+			name:     "not enough history to tell whether it is cold",
+			since:    now.Add(-time.Hour),
+			interval: 10 * time.Second,
+			want:     minDelay,
+			wantTemp: warm,
+			reason:   "warm (no recent demand): half the time since the repository last changed is 10s, raised to the minimum of 45s",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := newDemandTracker()
+			d.since = test.since
+			if test.last != nil {
+				d.repos[1] = &repoDemand{Last: test.last}
+			}
+
+			if got := d.adjust(1, test.interval, now); got != test.want {
+				t.Errorf("got interval %s, want %s", got, test.want)
+			}
+
+			info := d.info(1)
+			if info.Temperature != test.wantTemp {
+				t.Errorf("got temperature %q, want %q", info.Temperature, test.wantTemp)
+			}
+			if info.IntervalReason != test.reason {
+				t.Errorf("got reason\n%q\nwant\n%q", info.IntervalReason, test.reason)
+			}
+
+			wantPriority := priorityLow
+			if test.wantTemp == hot {
+				wantPriority = priorityMedium
+			}
+			if got := d.priority(1, now); got != wantPriority {
+				t.Errorf("got priority %d, want %d", got, wantPriority)
+			}
+		})
+	}
+}
+
+func TestUpdateScheduler_RecordDemand(t *testing.T) {
+	a := &configuredRepo2{ID: 1, Name: "a", URL: "a.com"}
+	b := &configuredRepo2{ID: 2, Name: "b", URL: "b.com"}
+
+	r, stop := startRecording()
+	defer stop()
+
+	s := NewUpdateScheduler()
+	setupInitialSchedule(s, []*scheduledRepoUpdate{
+		{Repo: a, Interval: 10 * time.Minute, Due: defaultTime.Add(5 * time.Minute)},
+		{Repo: b, Interval: maxDelay, Due: defaultTime.Add(6 * time.Hour)},
+	})
+
+	s.RecordDemand(protocol.RepoDemandView, a.ID, b.ID, 3)
+
+	// b is due sooner, a was already due within hotMaxDelay.
+	verifySchedule(t, s, []*scheduledRepoUpdate{
+		{Repo: a, Interval: 10 * time.Minute, Due: defaultTime.Add(5 * time.Minute)},
+		{Repo: b, Interval: hotMaxDelay, Due: defaultTime.Add(hotMaxDelay)},
+	})
+	verifyScheduleRecording(t, s, []time.Duration{5 * time.Minute}, 1, r)
+
+	if info := s.demand.info(b.ID); info == nil || info.IntervalReason != "hot (viewed 0s ago): capped at the hot maximum of 1h0m0s" {
+		t.Errorf("unexpected demand for b: %+v", info)
+	}
+
+	// Hot repos are enqueued with a higher priority when they are due.
+	s.demand.forget(b.ID)
+	setupInitialSchedule(s, []*scheduledRepoUpdate{
+		{Repo: a, Interval: 10 * time.Minute, Due: defaultTime},
+		{Repo: b, Interval: 10 * time.Minute, Due: defaultTime},
+	})
+	s.runSchedule()
+	verifyQueue(t, s, []*repoUpdate{
+		{Repo: a, Priority: priorityMedium, Seq: 1},
+		{Repo: b, Priority: priorityLow, Seq: 2},
+	})
+}
+
+type fakeDemandStore struct {
+	records  []*RepoDemandRecord
+	recorded []*RepoDemandRecord
+}
+
+func (s *fakeDemandStore) ListRepoDemand(context.Context) ([]*RepoDemandRecord, error) {
+	return s.records, nil
+}
+
+func (s *fakeDemandStore) RecordRepoDemand(ctx context.Context, kind protocol.RepoDemandKind, at time.Time, ids ...uint32) error {
+	for _, id := range ids {
+		s.recorded = append(s.recorded, &RepoDemandRecord{RepoID: id, Kind: kind, FirstAt: at, LastAt: at})
+	}
+	return nil
+}
+
+func TestUpdateScheduler_UseDemandStore(t *testing.T) {
+	now := defaultTime.Add(30 * 24 * time.Hour)
+	_, stop := startRecording()
+	defer stop()
+
+	store := &fakeDemandStore{records: []*RepoDemandRecord{
+		{RepoID: 1, Kind: protocol.RepoDemandView, FirstAt: defaultTime, LastAt: now.Add(-time.Hour)},
+		{RepoID: 1, Kind: protocol.RepoDemandSearch, FirstAt: defaultTime.Add(time.Hour), LastAt: now.Add(-2 * time.Hour)},
+	}}
+	s := NewUpdateScheduler()
+	if err := s.UseDemandStore(context.Background(), store); err != nil {
+		t.Fatal(err)
+	}
+
+	// The demand persisted before a restart is used: repo 1 is hot, and repo 2
+	// is cold because demand has been tracked for longer than coldDemandWindow.
+	if temp, reason := s.demand.temperature(1, now); temp != hot || reason != "viewed 1h0m0s ago" {
+		t.Errorf("repo 1: got %s (%s), want hot", temp, reason)
+	}
+	if temp, _ := s.demand.temperature(2, now); temp != cold {
+		t.Errorf("repo 2: got %s, want cold", temp)
+	}
+
+	// New demand signals are persisted.
+	s.RecordDemand(protocol.RepoDemandSavedSearch, 2)
+	want := []*RepoDemandRecord{{RepoID: 2, Kind: protocol.RepoDemandSavedSearch, FirstAt: defaultTime, LastAt: defaultTime}}
+	if !reflect.DeepEqual(store.recorded, want) {
+		t.Errorf("got recorded demand %+v, want %+v", store.recorded, want)
+	}
+}
@@ -154,9 +154,14 @@ func (s GithubSource) makeRepo(r *github.Repository) *Repo {
 		)),
 		ExternalRepo: github.ExternalRepoSpec(r, *s.baseURL),
 		Description:  r.Description,
+		Language:     r.PrimaryLanguage,
 		Fork:         r.IsFork,
 		Enabled:      true,
 		Archived:     r.IsArchived,
+		Private:      r.IsPrivate,
+		Stars:        r.StargazerCount,
+		Topics:       r.Topics,
+		PushedAt:     r.PushedAt,
 		Sources: map[string]*SourceInfo{
 			urn: {
 				ID:       urn,
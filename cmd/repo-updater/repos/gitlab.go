@@ -116,6 +116,10 @@ func (s GitLabSource) makeRepo(proj *gitlab.Project) *Repo {
 		Fork:         proj.ForkedFromProject != nil,
 		Enabled:      true,
 		Archived:     proj.Archived,
+		Private:      proj.Visibility == gitlab.Private, // internal projects are visible to every user of the instance
+		Stars:        proj.StarCount,
+		Topics:       proj.TagList,
+		PushedAt:     timeValue(proj.LastActivityAt),
 		Sources: map[string]*SourceInfo{
 			urn: {
 				ID:       urn,
@@ -3,6 +3,9 @@ package repos
 import (
 	"reflect"
 	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitlab"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func Test_projectQueryToURL(t *testing.T) {
@@ -48,3 +51,24 @@ func Test_projectQueryToURL(t *testing.T) {
 		}
 	}
 }
+
+func TestGitLabSource_makeRepo_Private(t *testing.T) {
+	s, err := newGitLabSource(&ExternalService{ID: 1, Kind: "GITLAB"}, &schema.GitLabConnection{Url: "https://gitlab.com"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[gitlab.Visibility]bool{
+		gitlab.Private:  true,
+		gitlab.Internal: false,
+		gitlab.Public:   false,
+	}
+	for visibility, want := range tests {
+		proj := &gitlab.Project{
+			ProjectCommon: gitlab.ProjectCommon{ID: 1, PathWithNamespace: "a/b"},
+			Visibility:    visibility,
+		}
+		if got := s.makeRepo(proj).Private; got != want {
+			t.Errorf("%s: got Private %v, want %v", visibility, got, want)
+		}
+	}
+}
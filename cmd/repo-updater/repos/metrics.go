@@ -78,6 +78,12 @@ var (
 		Name:      "sched_manual_fetch",
 		Help:      "Incremented each time the scheduler updates a repository due to user traffic.",
 	})
+	schedDemandSignals = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "src",
+		Subsystem: "repoupdater",
+		Name:      "sched_demand_signals",
+		Help:      "Incremented each time the scheduler records a demand signal (view, search or saved search) for a repository.",
+	}, []string{"kind"})
 	schedKnownRepos = promauto.NewGauge(prometheus.GaugeOpts{
 		Namespace: "src",
 		Subsystem: "repoupdater",
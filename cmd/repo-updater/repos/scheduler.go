@@ -3,6 +3,7 @@ package repos
 import (
 	"container/heap"
 	"context"
+	"fmt"
 	"sort"
 	"sync"
 	"time"
@@ -90,6 +91,10 @@ const (
 // then the next update will be scheduled 6 hours from then.
 // This heuristic is simple to compute and has nice backoff properties.
 //
+// The interval is adjusted by the demand for the repo (see demandTracker): repos that were
+// recently viewed or searched are updated at least every hour and are queued with a higher
+// priority, while repos that nobody has looked at for a long time back off further.
+//
 // When it is time for a repo to update, the scheduler inserts the repo into a queue.
 //
 // A worker continuously dequeues repos and sends updates to gitserver, but its concurrency
@@ -103,6 +108,8 @@ type updateScheduler struct {
 
 	updateQueue *updateQueue
 	schedule    *schedule
+	demand      *demandTracker
+	demandStore DemandStore // if set, demand signals are persisted in it
 }
 
 // A configuredRepo2 represents the configuration data for a given repo from
@@ -135,6 +142,7 @@ func NewUpdateScheduler() *updateScheduler {
 			index:  make(map[uint32]*scheduledRepoUpdate),
 			wakeup: make(chan struct{}, notifyChanBuffer),
 		},
+		demand: newDemandTracker(),
 	}
 }
 
@@ -165,7 +173,7 @@ func (s *updateScheduler) runSchedule() {
 		}
 
 		schedAutoFetch.Inc()
-		s.updateQueue.enqueue(repoUpdate.Repo, priorityLow)
+		s.updateQueue.enqueue(repoUpdate.Repo, s.demand.priority(repoUpdate.Repo.ID, timeNow()))
 		repoUpdate.Due = timeNow().Add(repoUpdate.Interval)
 		heap.Fix(s.schedule, 0)
 	}
@@ -209,7 +217,7 @@ func (s *updateScheduler) runUpdateLoop(ctx context.Context) {
 					// This is the heuristic that is described in the updateScheduler documentation.
 					// Update that documentation if you update this logic.
 					interval := resp.LastFetched.Sub(*resp.LastChanged) / 2
-					s.schedule.updateInterval(repo, interval)
+					s.schedule.setInterval(repo, s.demand.adjust(repo.ID, interval, timeNow()))
 				}
 			}(ctx, repo, cancel)
 		}
@@ -274,6 +282,8 @@ func (s *updateScheduler) remove(r *Repo) {
 	if s.updateQueue.remove(repo, false) {
 		log15.Debug("scheduler.updateQueue.removed", "repo", r.Name)
 	}
+
+	s.demand.forget(repo.ID)
 }
 
 func configuredRepo2FromRepo(r *Repo) *configuredRepo2 {
@@ -307,6 +317,7 @@ func (s *updateScheduler) updateSource(source string, newList sourceRepoMap) {
 			s.schedule.remove(repo)
 			updating := false // don't immediately remove repos that are already updating; they will automatically get removed when the update finishes
 			s.updateQueue.remove(repo, updating)
+			s.demand.forget(repo.ID)
 		}
 	}
 
@@ -336,11 +347,53 @@ func (s *updateScheduler) UpdateOnce(id uint32, name api.RepoName, url string) {
 	s.updateQueue.enqueue(repo, priorityHigh)
 }
 
+// RecordDemand records a demand signal of the given kind for each of the
+// repos with the given IDs. The repos become hot, so their next update is
+// moved forward to be due within hotMaxDelay.
+func (s *updateScheduler) RecordDemand(kind protocol.RepoDemandKind, ids ...uint32) {
+	now := timeNow()
+	for _, id := range ids {
+		schedDemandSignals.WithLabelValues(string(kind)).Inc()
+		s.demand.record(kind, id, now)
+		if s.schedule.expedite(id, hotMaxDelay) {
+			_, demandReason := s.demand.temperature(id, now)
+			s.demand.setIntervalReason(id, hot, fmt.Sprintf("hot (%s): capped at the hot maximum of %s", demandReason, hotMaxDelay))
+		}
+	}
+
+	if s.demandStore != nil && len(ids) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := s.demandStore.RecordRepoDemand(ctx, kind, now, ids...); err != nil {
+			log15.Error("Failed to persist repository demand.", "kind", kind, "error", err)
+		}
+	}
+}
+
+// UseDemandStore loads the demand signals persisted in store and persists
+// the demand signals recorded from now on in it. It must be called before
+// the scheduler is used.
+func (s *updateScheduler) UseDemandStore(ctx context.Context, store DemandStore) error {
+	records, err := store.ListRepoDemand(ctx)
+	if err != nil {
+		return err
+	}
+	s.demand.load(records)
+	s.demandStore = store
+	return nil
+}
+
+// scheduleDumpEntry is the state of a repo in the schedule for debugging.
+type scheduleDumpEntry struct {
+	scheduledRepoUpdate
+	Demand *repoDemand `json:",omitempty"`
+}
+
 // DebugDump returns the state of the update scheduler for debugging.
 func (s *updateScheduler) DebugDump() interface{} {
 	data := struct {
 		UpdateQueue []*repoUpdate
-		Schedule    []*scheduledRepoUpdate
+		Schedule    []scheduleDumpEntry
 		SourceRepos map[string][]configuredRepo2
 	}{
 		SourceRepos: map[string][]configuredRepo2{},
@@ -373,7 +426,10 @@ func (s *updateScheduler) DebugDump() interface{} {
 
 	for len(schedule.heap) > 0 {
 		update := heap.Pop(&schedule).(*scheduledRepoUpdate)
-		data.Schedule = append(data.Schedule, update)
+		data.Schedule = append(data.Schedule, scheduleDumpEntry{
+			scheduledRepoUpdate: *update,
+			Demand:              s.demand.info(update.Repo.ID),
+		})
 	}
 
 	s.updateQueue.mu.Lock()
@@ -414,6 +470,16 @@ func (s *updateScheduler) ScheduleInfo(id uint32) *protocol.RepoUpdateSchedulerI
 	}
 	s.schedule.mu.Unlock()
 
+	if result.Schedule != nil {
+		temp, demandReason := s.demand.temperature(id, timeNow())
+		result.Schedule.Demand = string(temp)
+		if demand := s.demand.info(id); demand != nil && demand.IntervalReason != "" {
+			result.Schedule.IntervalReason = demand.IntervalReason
+		} else {
+			result.Schedule.IntervalReason = fmt.Sprintf("%s (%s): the repository has not been updated since it was scheduled", temp, demandReason)
+		}
+	}
+
 	s.updateQueue.mu.Lock()
 	if update := s.updateQueue.index[id]; update != nil {
 		result.Queue = &protocol.RepoQueueState{
@@ -454,7 +520,8 @@ type updateQueue struct {
 type priority int
 
 const (
-	priorityLow priority = iota
+	priorityLow    priority = iota
+	priorityMedium          // scheduled updates of hot repos
 	priorityHigh
 )
 
@@ -653,23 +720,30 @@ func (s *schedule) upsert(repo *configuredRepo2) (updated bool) {
 	return false
 }
 
-// updateInterval updates the update interval of a repo in the schedule.
+// updateInterval updates the update interval of a repo in the schedule,
+// bounded by minDelay and maxDelay.
 // It does nothing if the repo is not in the schedule.
 func (s *schedule) updateInterval(repo *configuredRepo2, interval time.Duration) {
+	switch {
+	case interval > maxDelay:
+		interval = maxDelay
+	case interval < minDelay:
+		interval = minDelay
+	}
+	s.setInterval(repo, interval)
+}
+
+// setInterval sets the update interval of a repo in the schedule. Unlike
+// updateInterval, it does not bound the interval.
+// It does nothing if the repo is not in the schedule.
+func (s *schedule) setInterval(repo *configuredRepo2, interval time.Duration) {
 	if repo.ID == 0 {
 		panic("repo.id is zero")
 	}
 
 	s.mu.Lock()
 	if update := s.index[repo.ID]; update != nil {
-		switch {
-		case interval > maxDelay:
-			update.Interval = maxDelay
-		case interval < minDelay:
-			update.Interval = minDelay
-		default:
-			update.Interval = interval
-		}
+		update.Interval = interval
 		update.Due = timeNow().Add(update.Interval)
 		log15.Debug("updated repo", "repo", repo.Name, "due", update.Due.Sub(timeNow()))
 		heap.Fix(s, update.Index)
@@ -678,6 +752,32 @@ func (s *schedule) updateInterval(repo *configuredRepo2, interval time.Duration)
 	s.mu.Unlock()
 }
 
+// expedite caps the update interval of the repo with the given ID at within
+// and moves its next update forward to be due within that time. It reports
+// whether the schedule of the repo changed. It does nothing if the repo is not
+// in the schedule.
+func (s *schedule) expedite(id uint32, within time.Duration) (changed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	update := s.index[id]
+	if update == nil {
+		return false
+	}
+
+	if update.Interval > within {
+		update.Interval = within
+		changed = true
+	}
+	if due := timeNow().Add(within); update.Due.After(due) {
+		update.Due = due
+		heap.Fix(s, update.Index)
+		s.rescheduleTimer()
+		changed = true
+	}
+	return changed
+}
+
 // remove removes a repo from the schedule.
 func (s *schedule) remove(repo *configuredRepo2) (removed bool) {
 	if repo.ID == 0 {
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/db/dbutil"
@@ -18,6 +19,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitolite"
+	"github.com/sourcegraph/sourcegraph/pkg/repoupdater/protocol"
 )
 
 // A Store exposes methods to read and write repos and external services.
@@ -303,7 +305,11 @@ SELECT
   archived,
   fork,
   sources,
-  metadata
+  metadata,
+  private,
+  stars,
+  topics,
+  pushed_at
 FROM repo
 WHERE id > %s
 AND %s
@@ -402,6 +408,51 @@ func listAllRepoNamesQuery(cursor, limit int64) *sqlf.Query {
 	return sqlf.Sprintf(listAllRepoNamesQueryFmtstr, cursor, limit)
 }
 
+// ListRepoDemand lists the persisted demand signals of all repos.
+func (s DBStore) ListRepoDemand(ctx context.Context) (demand []*RepoDemandRecord, _ error) {
+	_, _, err := s.list(ctx, sqlf.Sprintf(listRepoDemandQueryFmtstr), func(sc scanner) (last, count int64, err error) {
+		var r RepoDemandRecord
+		if err = sc.Scan(&r.RepoID, &r.Kind, &r.FirstAt, &r.LastAt); err != nil {
+			return 0, 0, err
+		}
+		demand = append(demand, &r)
+		return int64(r.RepoID), 1, nil
+	})
+	return demand, err
+}
+
+const listRepoDemandQueryFmtstr = `
+-- source: cmd/repo-updater/repos/store.go:DBStore.ListRepoDemand
+SELECT repo_id, kind, first_at, last_at FROM repo_demand
+`
+
+// RecordRepoDemand persists a demand signal of the given kind at the given time for each of the
+// repos with the given IDs. IDs of repos that do not exist are ignored.
+func (s DBStore) RecordRepoDemand(ctx context.Context, kind protocol.RepoDemandKind, at time.Time, ids ...uint32) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	repoIDs := make([]int64, len(ids))
+	for i, id := range ids {
+		repoIDs[i] = int64(id)
+	}
+
+	q := sqlf.Sprintf(recordRepoDemandQueryFmtstr, kind, at, at, pq.Array(repoIDs))
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+const recordRepoDemandQueryFmtstr = `
+-- source: cmd/repo-updater/repos/store.go:DBStore.RecordRepoDemand
+INSERT INTO repo_demand (repo_id, kind, first_at, last_at)
+SELECT id, %s, %s, %s FROM repo WHERE id = ANY(%s)
+ON CONFLICT (repo_id, kind) DO UPDATE SET last_at = GREATEST(repo_demand.last_at, excluded.last_at)
+`
+
 // a paginatedQuery returns a query with the given pagination
 // parameters
 type paginatedQuery func(cursor, limit int64) *sqlf.Query
@@ -524,6 +575,10 @@ func batchReposQuery(fmtstr string, repos []*Repo) (_ *sqlf.Query, err error) {
 		Fork                bool            `json:"fork"`
 		Sources             json.RawMessage `json:"sources"`
 		Metadata            json.RawMessage `json:"metadata"`
+		Private             bool            `json:"private"`
+		Stars               int             `json:"stars"`
+		Topics              []string        `json:"topics"`
+		PushedAt            *time.Time      `json:"pushed_at,omitempty"`
 	}
 
 	records := make([]record, 0, len(repos))
@@ -538,6 +593,11 @@ func batchReposQuery(fmtstr string, repos []*Repo) (_ *sqlf.Query, err error) {
 			return nil, errors.Wrapf(err, "batchReposQuery: metadata marshalling failed")
 		}
 
+		topics := r.Topics
+		if topics == nil {
+			topics = []string{}
+		}
+
 		records = append(records, record{
 			ID:                  r.ID,
 			Name:                r.Name,
@@ -555,6 +615,10 @@ func batchReposQuery(fmtstr string, repos []*Repo) (_ *sqlf.Query, err error) {
 			Fork:                r.Fork,
 			Sources:             sources,
 			Metadata:            metadata,
+			Private:             r.Private,
+			Stars:               r.Stars,
+			Topics:              topics,
+			PushedAt:            nullTimeColumn(r.PushedAt.UTC()),
 		})
 	}
 
@@ -598,7 +662,11 @@ WITH batch AS (
       archived              boolean,
       fork                  boolean,
       sources               jsonb,
-      metadata              jsonb
+      metadata              jsonb,
+      private               boolean,
+      stars                 integer,
+      topics                jsonb,
+      pushed_at             timestamptz
     )
   )
   WITH ORDINALITY
@@ -622,7 +690,11 @@ updated AS (
     archived              = batch.archived,
     fork                  = batch.fork,
     sources               = batch.sources,
-    metadata              = batch.metadata
+    metadata              = batch.metadata,
+    private               = batch.private,
+    stars                 = batch.stars,
+    topics                = ARRAY(SELECT jsonb_array_elements_text(batch.topics)),
+    pushed_at             = batch.pushed_at
   FROM batch
   WHERE repo.id = batch.id
   RETURNING repo.*
@@ -643,7 +715,11 @@ SELECT
   updated.archived,
   updated.fork,
   updated.sources,
-  updated.metadata
+  updated.metadata,
+  updated.private,
+  updated.stars,
+  updated.topics,
+  updated.pushed_at
 FROM updated
 LEFT JOIN batch ON batch.id = updated.id
 ORDER BY batch.ordinality
@@ -673,7 +749,11 @@ inserted AS (
     archived,
     fork,
     sources,
-    metadata
+    metadata,
+    private,
+    stars,
+    topics,
+    pushed_at
   )
   SELECT
     name,
@@ -690,7 +770,11 @@ inserted AS (
     archived,
     fork,
     sources,
-    metadata
+    metadata,
+    private,
+    stars,
+    ARRAY(SELECT jsonb_array_elements_text(topics)),
+    pushed_at
   FROM batch
   RETURNING repo.*
 )
@@ -710,7 +794,11 @@ SELECT
   inserted.archived,
   inserted.fork,
   inserted.sources,
-  inserted.metadata
+  inserted.metadata,
+  inserted.private,
+  inserted.stars,
+  inserted.topics,
+  inserted.pushed_at
 FROM inserted
 LEFT JOIN batch ON batch.name = inserted.name
 ORDER BY batch.ordinality
@@ -790,6 +878,7 @@ func scanExternalService(svc *ExternalService, s scanner) error {
 
 func scanRepo(r *Repo, s scanner) error {
 	var sources, metadata json.RawMessage
+	var topics []string
 	err := s.Scan(
 		&r.ID,
 		&r.Name,
@@ -807,11 +896,20 @@ func scanRepo(r *Repo, s scanner) error {
 		&r.Fork,
 		&sources,
 		&metadata,
+		&r.Private,
+		&r.Stars,
+		pq.Array(&topics),
+		&dbutil.NullTime{Time: &r.PushedAt},
 	)
 	if err != nil {
 		return err
 	}
 
+	r.Topics = nil
+	if len(topics) > 0 {
+		r.Topics = topics
+	}
+
 	if err = json.Unmarshal(sources, &r.Sources); err != nil {
 		return errors.Wrap(err, "scanRepo: failed to unmarshal sources")
 	}
@@ -19,6 +19,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitolite"
+	"github.com/sourcegraph/sourcegraph/pkg/repoupdater/protocol"
 	"github.com/sourcegraph/sourcegraph/pkg/trace"
 	log15 "gopkg.in/inconshreveable/log15.v2"
 )
@@ -351,6 +352,10 @@ func testStoreUpsertRepos(store repos.Store) func(*testing.T) {
 			Description: "The description",
 			Language:    "barlang",
 			Enabled:     true,
+			Private:     true,
+			Stars:       42,
+			Topics:      []string{"payments", "api"},
+			PushedAt:    now,
 			CreatedAt:   now,
 			ExternalRepo: api.ExternalRepoSpec{
 				ID:          "AAAAA==",
@@ -899,6 +904,62 @@ func testDBStoreTransact(store *repos.DBStore) func(*testing.T) {
 	}
 }
 
+func testDBStoreRepoDemand(store *repos.DBStore) func(*testing.T) {
+	repo := repos.Repo{
+		Name:      "github.com/foo/bar",
+		URI:       "github.com/foo/bar",
+		Enabled:   true,
+		CreatedAt: time.Now(),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          "AAAAA==",
+			ServiceType: "github",
+			ServiceID:   "http://github.com",
+		},
+		Sources:  map[string]*repos.SourceInfo{},
+		Metadata: new(github.Repository),
+	}
+
+	return func(t *testing.T) {
+		ctx := context.Background()
+		t.Run("", transact(ctx, store, func(t testing.TB, tx repos.Store) {
+			stored := mkRepos(2, &repo)
+			if err := tx.UpsertRepos(ctx, stored...); err != nil {
+				t.Fatalf("UpsertRepos error: %s", err)
+			}
+			demandStore := tx.(*noopTxStore).Store.(repos.DemandStore)
+
+			first := time.Now().UTC().Truncate(time.Second)
+			// The unknown repo ID is ignored.
+			if err := demandStore.RecordRepoDemand(ctx, protocol.RepoDemandView, first, stored[0].ID, 1<<31-1); err != nil {
+				t.Fatal(err)
+			}
+			// An earlier signal does not move the last signal back.
+			for _, at := range []time.Time{first.Add(time.Hour), first.Add(time.Minute)} {
+				if err := demandStore.RecordRepoDemand(ctx, protocol.RepoDemandView, at, stored[0].ID); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			have, err := demandStore.ListRepoDemand(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := []*repos.RepoDemandRecord{{
+				RepoID:  stored[0].ID,
+				Kind:    protocol.RepoDemandView,
+				FirstAt: first,
+				LastAt:  first.Add(time.Hour),
+			}}
+			for _, r := range have {
+				r.FirstAt, r.LastAt = r.FirstAt.UTC(), r.LastAt.UTC()
+			}
+			if diff := pretty.Compare(have, want); diff != "" {
+				t.Errorf("ListRepoDemand:\n%s", diff)
+			}
+		}))
+	}
+}
+
 func mkRepos(n int, base ...*repos.Repo) repos.Repos {
 	if len(base) == 0 {
 		return nil
@@ -11,6 +11,7 @@ import (
 	otlog "github.com/opentracing/opentracing-go/log"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/ratelimit"
 	"github.com/sourcegraph/sourcegraph/pkg/trace"
 	"gopkg.in/inconshreveable/log15.v2"
 )
@@ -161,6 +162,61 @@ func (s *Syncer) SyncSubset(ctx context.Context, sourcedSubset ...*Repo) (diff D
 	return diff, nil
 }
 
+// DryRun computes the Diff that syncing the given external service would produce, without writing
+// anything to the store. It is used to preview the effect of an external service configuration
+// before saving it.
+//
+// The Diff is scoped to the given external service: Deleted contains the stored repos it would no
+// longer yield (a full sync only deletes them if no other external service yields them either)
+// and sources of other external services are ignored when detecting modified repos. An external
+// service without an ID is treated as a new one, so all the repos it yields are added.
+func (s *Syncer) DryRun(ctx context.Context, svc *ExternalService) (diff Diff, err error) {
+	tr, ctx := trace.New(ctx, "Syncer.DryRun", svc.URN())
+	defer func() {
+		tr.LogFields(
+			otlog.Int("added.count", len(diff.Added)),
+			otlog.Int("modified.count", len(diff.Modified)),
+			otlog.Int("deleted.count", len(diff.Deleted)),
+			otlog.Int("unmodified.count", len(diff.Unmodified)),
+		)
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	srcs, err := s.sourcer(svc)
+	if err != nil {
+		return Diff{}, errors.Wrap(err, "syncer.dry-run.sourcer")
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, sourceTimeout)
+	defer cancel()
+
+	sourced, err := srcs.ListRepos(listCtx)
+	if err != nil {
+		return Diff{}, errors.Wrap(err, "syncer.dry-run.sourced")
+	}
+
+	var stored Repos
+	if svc.ID != 0 {
+		all, err := s.store.ListRepos(ctx, StoreListReposArgs{Kinds: []string{svc.Kind}})
+		if err != nil {
+			return Diff{}, errors.Wrap(err, "syncer.dry-run.store.list-repos")
+		}
+
+		urn := svc.URN()
+		for _, r := range all {
+			if src, ok := r.Sources[urn]; ok {
+				// Clone, since NewDiff updates the stored repos in place.
+				r = r.Clone()
+				r.Sources = map[string]*SourceInfo{urn: src}
+				stored = append(stored, r)
+			}
+		}
+	}
+
+	return NewDiff(sourced, stored), nil
+}
+
 func (s *Syncer) upserts(diff Diff) []*Repo {
 	now := s.now()
 	upserts := make([]*Repo, 0, len(diff.Added)+len(diff.Deleted)+len(diff.Modified))
@@ -317,6 +373,7 @@ func (s *Syncer) sourced(ctx context.Context) ([]*Repo, error) {
 	ctx, cancel := context.WithTimeout(ctx, sourceTimeout)
 	defer cancel()
 
+	ctx = ratelimit.WithConsumer(ctx, ratelimit.ConsumerSync)
 	return srcs.ListRepos(ctx)
 }
 
@@ -748,6 +748,109 @@ func testSyncSubset(s repos.Store) func(*testing.T) {
 	}
 }
 
+func TestSyncer_DryRun(t *testing.T) {
+	t.Parallel()
+
+	svc := &repos.ExternalService{ID: 1, Kind: "GITHUB"}
+	other := &repos.ExternalService{ID: 2, Kind: "GITHUB"}
+
+	newRepo := func(name, id string, svcs ...*repos.ExternalService) *repos.Repo {
+		var urns []string
+		for _, svc := range svcs {
+			urns = append(urns, svc.URN())
+		}
+		return (&repos.Repo{
+			Name:        name,
+			Description: "The description",
+			Enabled:     true,
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          id,
+				ServiceType: "github",
+				ServiceID:   "https://github.com/",
+			},
+		}).With(repos.Opt.RepoSources(urns...))
+	}
+
+	foo := newRepo("github.com/org/foo", "foo", svc, other)
+	bar := newRepo("github.com/org/bar", "bar", svc)
+	baz := newRepo("github.com/org/baz", "baz", other)
+	unchanged := newRepo("github.com/org/unchanged", "unchanged", svc, other)
+	qux := newRepo("github.com/org/qux", "qux")
+
+	ctx := context.Background()
+	store := new(repos.FakeStore)
+	stored := repos.Repos{foo, bar, baz, unchanged}.Clone()
+	if err := store.UpsertRepos(ctx, stored...); err != nil {
+		t.Fatal(err)
+	}
+	stored = stored.Clone() // the fake store keeps the upserted pointers
+
+	for _, tc := range []struct {
+		name    string
+		svc     *repos.ExternalService
+		sourced repos.Repos
+		want    map[string][]string
+	}{
+		{
+			name: "existing external service",
+			svc:  svc,
+			sourced: repos.Repos{
+				foo.With(func(r *repos.Repo) { r.Description = "A new description" }),
+				unchanged,
+				qux,
+			},
+			want: map[string][]string{
+				"added":      {"github.com/org/qux"},
+				"deleted":    {"github.com/org/bar"},
+				"modified":   {"github.com/org/foo"},
+				"unmodified": {"github.com/org/unchanged"},
+			},
+		},
+		{
+			name:    "new external service",
+			svc:     &repos.ExternalService{Kind: "GITHUB"},
+			sourced: repos.Repos{foo, qux},
+			want: map[string][]string{
+				"added": {"github.com/org/foo", "github.com/org/qux"},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			sourcer := repos.NewFakeSourcer(nil, repos.NewFakeSource(tc.svc, nil, tc.sourced.Clone()...))
+			syncer := repos.NewSyncer(store, sourcer, nil, time.Now)
+
+			diff, err := syncer.DryRun(ctx, tc.svc)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			have := map[string][]string{}
+			for state, rs := range map[string]repos.Repos{
+				"added":      diff.Added,
+				"deleted":    diff.Deleted,
+				"modified":   diff.Modified,
+				"unmodified": diff.Unmodified,
+			} {
+				if names := rs.Names(); len(names) > 0 {
+					sort.Strings(names)
+					have[state] = names
+				}
+			}
+			if d := cmp.Diff(tc.want, have); d != "" {
+				t.Errorf("diff:\n%s", d)
+			}
+
+			// Nothing is written to the store.
+			all, err := store.ListRepos(ctx, repos.StoreListReposArgs{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			repos.Assert.ReposEqual(stored...)(t, all)
+		})
+	}
+}
+
 func TestDiff(t *testing.T) {
 	t.Parallel()
 
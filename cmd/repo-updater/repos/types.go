@@ -540,6 +540,14 @@ type Repo struct {
 	Enabled bool
 	// Archived is whether the repository has been archived.
 	Archived bool
+	// Private is whether the repository is private on the code host.
+	Private bool
+	// Stars is the number of users who starred the repository on the code host.
+	Stars int
+	// Topics are the topics (or tags) the repository is labeled with on the code host.
+	Topics []string
+	// PushedAt is when the repository was last pushed to, as reported by the code host.
+	PushedAt time.Time
 	// CreatedAt is when this repository was created on Sourcegraph.
 	CreatedAt time.Time
 	// UpdatedAt is when this repository's metadata was last updated on Sourcegraph.
@@ -637,6 +645,22 @@ func (r *Repo) Update(n *Repo) (modified bool) {
 		r.Fork, modified = n.Fork, true
 	}
 
+	if r.Private != n.Private {
+		r.Private, modified = n.Private, true
+	}
+
+	if r.Stars != n.Stars {
+		r.Stars, modified = n.Stars, true
+	}
+
+	if (len(r.Topics) > 0 || len(n.Topics) > 0) && !reflect.DeepEqual(r.Topics, n.Topics) {
+		r.Topics, modified = n.Topics, true
+	}
+
+	if !r.PushedAt.Equal(n.PushedAt) {
+		r.PushedAt, modified = n.PushedAt, true
+	}
+
 	if !reflect.DeepEqual(r.Sources, n.Sources) {
 		r.Sources, modified = n.Sources, true
 	}
@@ -660,6 +684,9 @@ func (r *Repo) Clone() *Repo {
 			clone.Sources[k] = v
 		}
 	}
+	if r.Topics != nil {
+		clone.Topics = append([]string{}, r.Topics...)
+	}
 	return &clone
 }
 
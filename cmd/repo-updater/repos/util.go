@@ -4,6 +4,7 @@ import (
 	"crypto/x509"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/pkg/httpcli"
 
@@ -83,3 +84,11 @@ func setUserinfoBestEffort(rawurl, username, password string) string {
 
 	return u.String()
 }
+
+// timeValue returns the time t points to, or the zero time if t is nil.
+func timeValue(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
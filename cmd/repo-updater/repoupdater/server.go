@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -17,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitlab"
+	"github.com/sourcegraph/sourcegraph/pkg/ratelimit"
 	"github.com/sourcegraph/sourcegraph/pkg/repoupdater/protocol"
 	"github.com/sourcegraph/sourcegraph/pkg/trace"
 	log15 "gopkg.in/inconshreveable/log15.v2"
@@ -33,6 +35,7 @@ type Server struct {
 		UpdateQueueLen() int
 		UpdateOnce(id uint32, name api.RepoName, url string)
 		ScheduleInfo(id uint32) *protocol.RepoUpdateSchedulerInfoResult
+		RecordDemand(kind protocol.RepoDemandKind, ids ...uint32)
 	}
 	GitserverClient interface {
 		ListCloned(context.Context) ([]string, error)
@@ -50,8 +53,10 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/repo-lookup", s.handleRepoLookup)
 	mux.HandleFunc("/repo-external-services", s.handleRepoExternalServices)
 	mux.HandleFunc("/enqueue-repo-update", s.handleEnqueueRepoUpdate)
+	mux.HandleFunc("/record-repo-demand", s.handleRecordRepoDemand)
 	mux.HandleFunc("/exclude-repo", s.handleExcludeRepo)
 	mux.HandleFunc("/sync-external-service", s.handleExternalServiceSync)
+	mux.HandleFunc("/external-service-dry-run", s.handleExternalServiceDryRun)
 	mux.HandleFunc("/status-messages", s.handleStatusMessages)
 	return mux
 }
@@ -286,6 +291,16 @@ func (s *Server) enqueueRepoUpdate(ctx context.Context, req *protocol.RepoUpdate
 	}, http.StatusOK, nil
 }
 
+func (s *Server) handleRecordRepoDemand(w http.ResponseWriter, r *http.Request) {
+	var req protocol.RepoDemandRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respond(w, http.StatusBadRequest, err)
+		return
+	}
+	s.Scheduler.RecordDemand(req.Kind, req.IDs...)
+	respond(w, http.StatusOK, nil)
+}
+
 func (s *Server) handleExternalServiceSync(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithCancel(r.Context())
 	defer cancel()
@@ -351,6 +366,42 @@ func (s *Server) handleExternalServiceSync(w http.ResponseWriter, r *http.Reques
 	}
 }
 
+func (s *Server) handleExternalServiceDryRun(w http.ResponseWriter, r *http.Request) {
+	var req protocol.ExternalServiceDryRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	diff, err := s.Syncer.DryRun(r.Context(), &repos.ExternalService{
+		ID:          req.ExternalService.ID,
+		Kind:        req.ExternalService.Kind,
+		DisplayName: req.ExternalService.DisplayName,
+		Config:      req.ExternalService.Config,
+	})
+	if err != nil {
+		log15.Error("server.external-service-dry-run", "kind", req.ExternalService.Kind, "error", err)
+		respond(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	respond(w, http.StatusOK, &protocol.ExternalServiceDryRunResult{
+		Added:      sortedRepoNames(diff.Added),
+		Removed:    sortedRepoNames(diff.Deleted),
+		Modified:   sortedRepoNames(diff.Modified),
+		Unmodified: len(diff.Unmodified),
+	})
+}
+
+func sortedRepoNames(rs repos.Repos) []api.RepoName {
+	names := make([]api.RepoName, 0, len(rs))
+	for _, r := range rs {
+		names = append(names, api.RepoName(r.Name))
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
 var mockRepoLookup func(protocol.RepoLookupArgs) (*protocol.RepoLookupResult, error)
 
 func (s *Server) repoLookup(ctx context.Context, args protocol.RepoLookupArgs) (result *protocol.RepoLookupResult, err error) {
@@ -449,11 +500,29 @@ func (s *Server) handleStatusMessages(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
+	budgets, err := ratelimit.Statuses()
+	if err != nil {
+		log15.Warn("Failed to get code host request budgets", "error", err)
+	}
+	for _, b := range budgets {
+		if !b.Throttled(rateLimitStatusWindow) {
+			continue
+		}
+		resp.Messages = append(resp.Messages, protocol.StatusMessage{
+			Message: fmt.Sprintf("Requests to %s for %s are being throttled: %d of %d requests per hour remaining.", b.Service, b.Consumer, b.Remaining, b.Budget),
+			Type:    protocol.RateLimitStatusMessage,
+		})
+	}
+
 	log15.Debug("TRACE handleStatusMessages", "messages", resp.Messages)
 
 	respond(w, http.StatusOK, resp)
 }
 
+// rateLimitStatusWindow is how long after a request was throttled by a code host request budget
+// we keep reporting it in status messages.
+const rateLimitStatusWindow = 5 * time.Minute
+
 func (s *Server) computeNotClonedCount(ctx context.Context) (uint64, error) {
 	// Coarse lock so we single flight the expensive computation.
 	s.notClonedCountMu.Lock()
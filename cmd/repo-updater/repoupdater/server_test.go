@@ -542,6 +542,76 @@ func TestServer_RepoExternalServices(t *testing.T) {
 	}
 }
 
+func TestServer_ExternalServiceDryRun(t *testing.T) {
+	svc := &repos.ExternalService{ID: 1, Kind: "GITHUB"}
+
+	stored := (&repos.Repo{
+		Name: "github.com/foo/stored",
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          "stored",
+			ServiceType: "github",
+			ServiceID:   "http://github.com",
+		},
+		Metadata: new(github.Repository),
+	}).With(repos.Opt.RepoSources(svc.URN()))
+
+	sourced := stored.With(func(r *repos.Repo) {
+		r.Name = "github.com/foo/sourced"
+		r.ExternalRepo.ID = "sourced"
+	})
+
+	ctx := context.Background()
+	store := new(repos.FakeStore)
+	must(store.UpsertRepos(ctx, stored.Clone()))
+
+	for _, tc := range []struct {
+		name    string
+		sourcer repos.Sourcer
+		res     *protocol.ExternalServiceDryRunResult
+		err     string
+	}{
+		{
+			name:    "diff",
+			sourcer: repos.NewFakeSourcer(nil, repos.NewFakeSource(svc, nil, sourced)),
+			res: &protocol.ExternalServiceDryRunResult{
+				Added:    []api.RepoName{"github.com/foo/sourced"},
+				Removed:  []api.RepoName{"github.com/foo/stored"},
+				Modified: []api.RepoName{},
+			},
+			err: "<nil>",
+		},
+		{
+			name:    "source error",
+			sourcer: repos.NewFakeSourcer(nil, repos.NewFakeSource(svc, errors.New("boom"))),
+			err:     "syncer.dry-run.sourced: 1 error occurred:\n\t* boom\n\n",
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			s := &Server{Store: store, Syncer: repos.NewSyncer(store, tc.sourcer, nil, time.Now)}
+			srv := httptest.NewServer(s.Handler())
+			defer srv.Close()
+			cli := repoupdater.Client{URL: srv.URL}
+
+			res, err := cli.ExternalServiceDryRun(ctx, api.ExternalService{ID: svc.ID, Kind: svc.Kind})
+			if have, want := fmt.Sprint(err), tc.err; have != want {
+				t.Errorf("have err: %q, want: %q", have, want)
+			}
+
+			if have, want := res, tc.res; !reflect.DeepEqual(have, want) {
+				t.Errorf("response:\n%s", cmp.Diff(have, want))
+			}
+
+			// Nothing is written to the store.
+			all, err := store.ListRepos(ctx, repos.StoreListReposArgs{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			repos.Assert.ReposEqual(stored)(t, all)
+		})
+	}
+}
+
 func TestServer_StatusMessages(t *testing.T) {
 	testCases := []struct {
 		name            string
@@ -988,6 +1058,7 @@ func (s *fakeScheduler) UpdateOnce(_ uint32, _ api.RepoName, _ string) {}
 func (s *fakeScheduler) ScheduleInfo(id uint32) *protocol.RepoUpdateSchedulerInfoResult {
 	return &protocol.RepoUpdateSchedulerInfoResult{}
 }
+func (s *fakeScheduler) RecordDemand(protocol.RepoDemandKind, ...uint32) {}
 
 type fakeGitserverClient struct {
 	listClonedResponse []string
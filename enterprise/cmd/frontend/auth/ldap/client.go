@@ -0,0 +1,134 @@
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+	ldapv3 "gopkg.in/ldap.v3"
+)
+
+const (
+	// noAttributes is the attribute list that requests that no attributes be returned (RFC 4511
+	// section 4.5.1.8).
+	noAttributes = "1.1"
+
+	defaultOperationTimeout = 30 * time.Second
+)
+
+// dial connects to the LDAP server specified in the provider config (with the ldap:// or ldaps://
+// scheme). If pc.StartTLS is true and the URL's scheme is ldap://, the connection is upgraded to TLS
+// before it is returned.
+func dial(ctx context.Context, pc *schema.LDAPAuthProvider) (*ldapv3.Conn, error) {
+	u, err := url.Parse(pc.Url)
+	if err != nil {
+		return nil, err
+	}
+	host, port := u.Hostname(), u.Port()
+	var useTLS bool
+	switch u.Scheme {
+	case "ldap":
+		if port == "" {
+			port = "389"
+		}
+	case "ldaps":
+		useTLS = true
+		if port == "" {
+			port = "636"
+		}
+	default:
+		return nil, fmt.Errorf("unsupported LDAP URL scheme %q (must be ldap or ldaps)", u.Scheme)
+	}
+	tlsConfig, err := newTLSConfig(pc, host)
+	if err != nil {
+		return nil, err
+	}
+
+	netConn, err := (&net.Dialer{Timeout: 10 * time.Second}).DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, err
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(defaultOperationTimeout)
+	}
+	if err := netConn.SetDeadline(deadline); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	if useTLS {
+		tlsConn := tls.Client(netConn, tlsConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+		netConn = tlsConn
+	}
+
+	c := ldapv3.NewConn(netConn, useTLS)
+	c.Start()
+	if pc.StartTLS && !useTLS {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// newTLSConfig returns the TLS config for connections to the LDAP server with the given host name.
+func newTLSConfig(pc *schema.LDAPAuthProvider, serverName string) (*tls.Config, error) {
+	config := &tls.Config{ServerName: serverName}
+	if pc.Tls == nil {
+		return config, nil
+	}
+	config.InsecureSkipVerify = pc.Tls.InsecureSkipVerify
+	if pc.Tls.Ca != "" {
+		pool := x509.NewCertPool()
+		if ok := pool.AppendCertsFromPEM([]byte(pc.Tls.Ca)); !ok {
+			return nil, errors.New("invalid certificate in LDAP auth provider tls.ca")
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// search searches the subtree rooted at baseDN for entries that match the filter and returns the
+// requested attributes of the entries. If sizeLimit is nonzero, at most that many entries may be
+// returned (and the server returns an error with the result code LDAPResultSizeLimitExceeded if
+// more entries match). Referrals are not followed.
+func search(c *ldapv3.Conn, baseDN, filter string, attrs []string, sizeLimit int) ([]*ldapv3.Entry, error) {
+	result, err := c.Search(ldapv3.NewSearchRequest(
+		baseDN,
+		ldapv3.ScopeWholeSubtree,
+		ldapv3.NeverDerefAliases,
+		sizeLimit,
+		0, // no time limit (the connection has a deadline)
+		false,
+		filter,
+		attrs,
+		nil,
+	))
+	if err != nil {
+		return nil, err
+	}
+	return result.Entries, nil
+}
+
+// attributeValue returns the first value of the entry's attribute, or "" if the entry has no such
+// attribute. Attribute descriptions are case-insensitive.
+func attributeValue(e *ldapv3.Entry, attr string) string {
+	for _, a := range e.Attributes {
+		if strings.EqualFold(a.Name, attr) && len(a.Values) > 0 {
+			return a.Values[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,113 @@
+package ldap
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+	ldapv3 "gopkg.in/ldap.v3"
+)
+
+// getProviderConfig returns the LDAP auth provider config (with defaults applied). At most 1 can be
+// specified in site config; if there is more than 1, it returns multiple == true (which the caller
+// should handle by returning an error and refusing to proceed with auth).
+func getProviderConfig() (pc *schema.LDAPAuthProvider, multiple bool) {
+	for _, p := range conf.Get().Critical.AuthProviders {
+		if p.Ldap != nil {
+			if pc != nil {
+				return pc, true // multiple LDAP auth providers
+			}
+			pc = withDefaults(p.Ldap)
+		}
+	}
+	return pc, false
+}
+
+// withDefaults returns a copy of the provider config with the default values of unset properties
+// filled in.
+func withDefaults(pc *schema.LDAPAuthProvider) *schema.LDAPAuthProvider {
+	tmp := *pc
+	if tmp.UserSearchFilter == "" {
+		tmp.UserSearchFilter = "(uid={username})"
+	}
+	if tmp.UsernameAttribute == "" {
+		tmp.UsernameAttribute = "uid"
+	}
+	if tmp.EmailAttribute == "" {
+		tmp.EmailAttribute = "mail"
+	}
+	if tmp.DisplayNameAttribute == "" {
+		tmp.DisplayNameAttribute = "cn"
+	}
+	if tmp.GroupSearchFilter == "" {
+		tmp.GroupSearchFilter = "(member={dn})"
+	}
+	return &tmp
+}
+
+// allowSignup reports whether users without a Sourcegraph account may sign in (creating an account).
+func allowSignup(pc *schema.LDAPAuthProvider) bool {
+	return pc.AllowSignup == nil || *pc.AllowSignup
+}
+
+// expandFilter replaces the placeholders in a filter from site config with the (escaped) values.
+//
+// 🚨 SECURITY: The values must be escaped to prevent LDAP injection.
+func expandFilter(filter, username, dn string) string {
+	return strings.NewReplacer(
+		"{username}", ldapv3.EscapeFilter(username),
+		"{dn}", ldapv3.EscapeFilter(dn),
+	).Replace(filter)
+}
+
+// validateFilter returns an error if the filter is not a valid LDAP search filter (RFC 4515).
+func validateFilter(filter string) error {
+	if _, err := ldapv3.CompileFilter(filter); err != nil {
+		return fmt.Errorf("invalid LDAP filter %q: %s", filter, err)
+	}
+	return nil
+}
+
+func init() {
+	conf.ContributeValidator(validateConfig)
+}
+
+func validateConfig(c conf.Unified) (problems []string) {
+	var ldapAuthProviders int
+	for _, p := range c.Critical.AuthProviders {
+		if p.Ldap == nil {
+			continue
+		}
+		ldapAuthProviders++
+
+		pc := withDefaults(p.Ldap)
+		if u, err := url.Parse(pc.Url); err != nil || (u.Scheme != "ldap" && u.Scheme != "ldaps") || u.Host == "" {
+			problems = append(problems, fmt.Sprintf("LDAP auth provider url %q must be an ldap:// or ldaps:// URL", pc.Url))
+		}
+		if pc.BindDN != "" && pc.BindPassword == "" {
+			problems = append(problems, "LDAP auth provider bindPassword must be set if bindDN is set")
+		}
+		if _, err := newTLSConfig(pc, ""); err != nil {
+			problems = append(problems, err.Error())
+		}
+		if err := validateFilter(expandFilter(pc.UserSearchFilter, "u", "")); err != nil {
+			problems = append(problems, fmt.Sprintf("LDAP auth provider userSearchFilter: %s", err))
+		}
+		if !strings.Contains(pc.UserSearchFilter, "{username}") {
+			problems = append(problems, "LDAP auth provider userSearchFilter must contain {username}")
+		}
+		if pc.GroupSearchBase != "" {
+			if err := validateFilter(expandFilter(pc.GroupSearchFilter, "u", "cn=u")); err != nil {
+				problems = append(problems, fmt.Sprintf("LDAP auth provider groupSearchFilter: %s", err))
+			}
+		} else if len(pc.GroupOrgMap) > 0 {
+			problems = append(problems, "LDAP auth provider groupOrgMap has no effect unless groupSearchBase is set")
+		}
+	}
+	if ldapAuthProviders >= 2 {
+		problems = append(problems, `at most 1 ldap auth provider may be used`)
+	}
+	return problems
+}
@@ -0,0 +1,108 @@
+package ldap
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestValidateCustom(t *testing.T) {
+	tests := map[string]struct {
+		input        conf.Unified
+		wantProblems []string
+	}{
+		"single": {
+			input: conf.Unified{Critical: schema.CriticalConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldaps://ldap.example.com", UserSearchBase: "dc=example,dc=com"}},
+				},
+			}},
+			wantProblems: nil,
+		},
+		"multiple": {
+			input: conf.Unified{Critical: schema.CriticalConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldap://a.example.com", UserSearchBase: "dc=example,dc=com"}},
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldap://b.example.com", UserSearchBase: "dc=example,dc=com"}},
+				},
+			}},
+			wantProblems: []string{"at most 1"},
+		},
+		"invalid url": {
+			input: conf.Unified{Critical: schema.CriticalConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "https://ldap.example.com", UserSearchBase: "dc=example,dc=com"}},
+				},
+			}},
+			wantProblems: []string{"must be an ldap:// or ldaps:// URL"},
+		},
+		"bindDN without bindPassword": {
+			input: conf.Unified{Critical: schema.CriticalConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldap://ldap.example.com", UserSearchBase: "dc=example,dc=com", BindDN: "cn=admin,dc=example,dc=com"}},
+				},
+			}},
+			wantProblems: []string{"bindPassword must be set"},
+		},
+		"userSearchFilter without username": {
+			input: conf.Unified{Critical: schema.CriticalConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldap://ldap.example.com", UserSearchBase: "dc=example,dc=com", UserSearchFilter: "(objectClass=person)"}},
+				},
+			}},
+			wantProblems: []string{"must contain {username}"},
+		},
+		"invalid userSearchFilter": {
+			input: conf.Unified{Critical: schema.CriticalConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldap://ldap.example.com", UserSearchBase: "dc=example,dc=com", UserSearchFilter: "(&(uid={username})"}},
+				},
+			}},
+			wantProblems: []string{"userSearchFilter: invalid LDAP filter"},
+		},
+		"invalid tls.ca": {
+			input: conf.Unified{Critical: schema.CriticalConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldaps://ldap.example.com", UserSearchBase: "dc=example,dc=com", Tls: &schema.LDAPTLSConfig{Ca: "-----BEGIN CERTIFICATE-----\ninvalid"}}},
+				},
+			}},
+			wantProblems: []string{"invalid certificate in LDAP auth provider tls.ca"},
+		},
+		"groupOrgMap without groupSearchBase": {
+			input: conf.Unified{Critical: schema.CriticalConfiguration{
+				AuthProviders: []schema.AuthProviders{
+					{Ldap: &schema.LDAPAuthProvider{Type: "ldap", Url: "ldap://ldap.example.com", UserSearchBase: "dc=example,dc=com", GroupOrgMap: map[string][]string{"cn=eng,dc=example,dc=com": {"eng"}}}},
+				},
+			}},
+			wantProblems: []string{"groupOrgMap has no effect"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			conf.TestValidator(t, test.input, validateConfig, test.wantProblems)
+		})
+	}
+}
+
+func TestExpandFilter(t *testing.T) {
+	tests := []struct {
+		filter, username, dn string
+		want                 string
+	}{
+		{filter: "(uid={username})", username: "alice", want: "(uid=alice)"},
+		{filter: "(uid={username})", username: "*", want: `(uid=\2a)`},
+		{filter: "(uid={username})", username: "a)(uid=*", want: `(uid=a\29\28uid=\2a)`},
+		{filter: "(member={dn})", dn: `cn=a\,b,dc=example`, want: `(member=cn=a\5c,b,dc=example)`},
+		{filter: "(cn={username})", username: "Ålice", want: `(cn=\c3\85lice)`},
+	}
+	for _, test := range tests {
+		got := expandFilter(test.filter, test.username, test.dn)
+		if got != test.want {
+			t.Errorf("%q (username %q, dn %q): got %q, want %q", test.filter, test.username, test.dn, got, test.want)
+		}
+		if err := validateFilter(got); err != nil {
+			t.Errorf("%q: %s", got, err)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package ldap
+
+import (
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+)
+
+// Watch for configuration changes related to the LDAP auth provider.
+func init() {
+	go func() {
+		conf.Watch(func() {
+			newPC, _ := getProviderConfig()
+			if newPC == nil {
+				providers.Update("ldap", nil)
+				return
+			}
+			providers.Update("ldap", []providers.Provider{&provider{c: newPC}})
+		})
+	}()
+}
@@ -0,0 +1,107 @@
+// Package ldap implements auth via an LDAP directory (such as Active Directory or OpenLDAP).
+package ldap
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/external/session"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+const providerType = "ldap"
+
+// loginPath is the path of the endpoint that accepts a POST of the sign-in form.
+const loginPath = auth.AuthURLPrefix + "/ldap/login"
+
+// Middleware handles the LDAP sign-in form submission endpoint. Unlike the SSO auth providers, it
+// does not redirect unauthenticated users anywhere, because they sign in with a form on the
+// Sourcegraph sign-in page (like the builtin auth provider).
+var Middleware = &auth.Middleware{
+	API: func(next http.Handler) http.Handler { return next },
+	App: func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == loginPath {
+				handleSignIn(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	},
+}
+
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// handleSignIn accepts a POST containing LDAP username-password credentials and authenticates the
+// current session if the credentials are valid.
+//
+// 🚨 SECURITY
+func handleSignIn(w http.ResponseWriter, r *http.Request) {
+	pc, multiple := getProviderConfig()
+	if multiple {
+		log15.Error("At most 1 LDAP auth provider may be set in site config.")
+		http.Error(w, "Misconfigured LDAP auth provider.", http.StatusInternalServerError)
+		return
+	}
+	if pc == nil {
+		http.Error(w, "LDAP auth provider is not enabled.", http.StatusNotFound)
+		return
+	}
+	if r.Method != "POST" {
+		http.Error(w, fmt.Sprintf("Unsupported method %s", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 🚨 SECURITY: This endpoint is not behind the CSRF middleware (which runs after the auth
+	// middleware), so require a custom header that cross-origin requests can't set without a CORS
+	// preflight. This prevents login CSRF (signing the victim into the attacker's account).
+	if r.Header.Get("X-Requested-With") != "Sourcegraph" {
+		http.Error(w, "Missing X-Requested-With header.", http.StatusBadRequest)
+		return
+	}
+
+	var creds credentials
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		http.Error(w, "Could not decode request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	u, err := authenticate(ctx, pc, creds.Username, creds.Password)
+	if err == errInvalidCredentials {
+		http.Error(w, "Authentication failed", http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		log15.Error("Error authenticating with LDAP.", "username", creds.Username, "err", err)
+		http.Error(w, "Authentication failed unexpectedly. Contact a site admin for help.", http.StatusInternalServerError)
+		return
+	}
+
+	userID, safeErrMsg, err := getOrCreateUser(ctx, pc, u)
+	if err != nil {
+		log15.Error("Error looking up or creating user for LDAP directory entry.", "dn", u.dn, "err", err)
+		http.Error(w, safeErrMsg, http.StatusInternalServerError)
+		return
+	}
+
+	if len(pc.GroupOrgMap) > 0 {
+		// Failing to sync org memberships should not prevent the user from signing in.
+		if err := syncOrgMemberships(ctx, userID, pc.GroupOrgMap, u.groups); err != nil {
+			log15.Error("Error syncing organization memberships from LDAP groups.", "userID", userID, "dn", u.dn, "err", err)
+		}
+	}
+
+	// Write the session cookie
+	if err := session.SetSignedInActor(w, r, &actor.Actor{UID: userID}, 0, session.SignInInfo{AuthProvider: providerType}); err != nil {
+		log15.Error("Error creating session for LDAP user.", "userID", userID, "err", err)
+		http.Error(w, "Could not create new user session", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,99 @@
+package ldap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/external/session"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestMiddleware(t *testing.T) {
+	s := newTestServer(t, testDirectory...)
+	defer s.close()
+
+	conf.Mock(&conf.Unified{Critical: schema.CriticalConfiguration{AuthProviders: []schema.AuthProviders{{Ldap: &schema.LDAPAuthProvider{
+		Type:           "ldap",
+		Url:            s.url,
+		BindDN:         "cn=admin,dc=example,dc=com",
+		BindPassword:   "adminpw",
+		UserSearchBase: "ou=people,dc=example,dc=com",
+	}}}}})
+	defer conf.Mock(nil)
+
+	cleanup := session.ResetMockSessionStore(t)
+	defer cleanup()
+
+	var calledMock bool
+	auth.MockGetAndSaveUser = func(ctx context.Context, op auth.GetAndSaveUserOp) (userID int32, safeErrMsg string, err error) {
+		calledMock = true
+		if op.ExternalAccount.ServiceType == "ldap" && op.ExternalAccount.ServiceID == s.url && op.ExternalAccount.AccountID == "uid=alice,ou=people,dc=example,dc=com" &&
+			op.UserProps.Username == "alice" && op.UserProps.Email == "alice@example.com" && op.UserProps.EmailIsVerified && op.CreateIfNotExist {
+			return 1, "", nil
+		}
+		return 0, "safeErr", fmt.Errorf("account %v not found in mock", op.ExternalAccount)
+	}
+	defer func() { auth.MockGetAndSaveUser = nil }()
+
+	handler := Middleware.App(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "next")
+	}))
+
+	doRequest := func(method, path, body string, header bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if header {
+			req.Header.Set("X-Requested-With", "Sourcegraph")
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("other path", func(t *testing.T) {
+		if got, want := doRequest("GET", "/", "", false).Body.String(), "next"; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("GET", func(t *testing.T) {
+		if got, want := doRequest("GET", loginPath, "", true).Code, http.StatusMethodNotAllowed; got != want {
+			t.Errorf("got status %d, want %d", got, want)
+		}
+	})
+
+	t.Run("missing X-Requested-With header", func(t *testing.T) {
+		if got, want := doRequest("POST", loginPath, `{"username":"alice","password":"alicepw"}`, false).Code, http.StatusBadRequest; got != want {
+			t.Errorf("got status %d, want %d", got, want)
+		}
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		calledMock = false
+		if got, want := doRequest("POST", loginPath, `{"username":"alice","password":"wrong"}`, true).Code, http.StatusUnauthorized; got != want {
+			t.Errorf("got status %d, want %d", got, want)
+		}
+		if calledMock {
+			t.Error("calledMock")
+		}
+	})
+
+	t.Run("valid credentials", func(t *testing.T) {
+		calledMock = false
+		rr := doRequest("POST", loginPath, `{"username":"alice","password":"alicepw"}`, true)
+		if got, want := rr.Code, http.StatusOK; got != want {
+			t.Errorf("got status %d, want %d (body %q)", got, want, rr.Body.String())
+		}
+		if !calledMock {
+			t.Error("!calledMock")
+		}
+		if len(rr.Result().Cookies()) == 0 {
+			t.Error("got no session cookie")
+		}
+	})
+}
@@ -0,0 +1,285 @@
+package ldap
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ber "gopkg.in/asn1-ber.v1"
+	ldapv3 "gopkg.in/ldap.v3"
+)
+
+// testEntry is an entry in the directory of a testServer.
+type testEntry struct {
+	dn       string
+	password string // if empty, binds as this entry fail (except for unauthenticated binds)
+	attrs    map[string][]string
+}
+
+// testServer is an in-process LDAP server that serves a fixed directory. It implements just enough
+// of the protocol to exercise the client in this package.
+type testServer struct {
+	url     string
+	entries []testEntry
+
+	l net.Listener
+
+	mu    sync.Mutex
+	binds []string // DNs of all bind requests received
+}
+
+func newTestServer(t *testing.T, entries ...testEntry) *testServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return startTestServer("ldap://"+l.Addr().String(), l, entries)
+}
+
+// newTLSTestServer returns a testServer that serves over TLS (ldaps://) with the given certificate.
+func newTLSTestServer(t *testing.T, cert tls.Certificate, entries ...testEntry) *testServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return startTestServer("ldaps://"+l.Addr().String(), tls.NewListener(l, &tls.Config{Certificates: []tls.Certificate{cert}}), entries)
+}
+
+func startTestServer(url string, l net.Listener, entries []testEntry) *testServer {
+	s := &testServer{url: url, entries: entries, l: l}
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serveConn(c)
+		}
+	}()
+	return s
+}
+
+func (s *testServer) close() { s.l.Close() }
+
+func (s *testServer) bindDNs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.binds...)
+}
+
+func (s *testServer) serveConn(c net.Conn) {
+	defer c.Close()
+	for {
+		msg, err := ber.ReadPacket(c)
+		if err != nil || len(msg.Children) < 2 {
+			return
+		}
+		msgID, _ := msg.Children[0].Value.(int64)
+		op := msg.Children[1]
+		var responses []*ber.Packet
+		switch {
+		case isOp(op, ldapv3.ApplicationBindRequest):
+			responses = []*ber.Packet{s.handleBind(op)}
+		case isOp(op, ldapv3.ApplicationSearchRequest):
+			responses = s.handleSearch(op)
+		case isOp(op, ldapv3.ApplicationUnbindRequest):
+			return
+		default:
+			responses = []*ber.Packet{ldapResult(ldapv3.ApplicationExtendedResponse, ldapv3.LDAPResultProtocolError, "unsupported operation")}
+		}
+		for _, resp := range responses {
+			envelope := ber.NewSequence("LDAP Response")
+			envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, msgID, "Message ID"))
+			envelope.AppendChild(resp)
+			if _, err := c.Write(envelope.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func isOp(p *ber.Packet, op ber.Tag) bool {
+	return p.ClassType == ber.ClassApplication && p.Tag == op
+}
+
+// str returns the content of a primitive packet as a string.
+func str(p *ber.Packet) string { return p.Data.String() }
+
+func octetString(s string) *ber.Packet {
+	return ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, s, "")
+}
+
+func ldapResult(op ber.Tag, code int64, message string) *ber.Packet {
+	p := ber.Encode(ber.ClassApplication, ber.TypeConstructed, op, nil, "")
+	p.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "Result Code"))
+	p.AppendChild(octetString(""))
+	p.AppendChild(octetString(message))
+	return p
+}
+
+func (s *testServer) handleBind(op *ber.Packet) *ber.Packet {
+	dn, password := str(op.Children[1]), str(op.Children[2])
+	s.mu.Lock()
+	s.binds = append(s.binds, dn)
+	s.mu.Unlock()
+
+	if password == "" {
+		// Like many real servers, treat anonymous and unauthenticated binds (RFC 4513 section
+		// 5.1.2) as successful.
+		return ldapResult(ldapv3.ApplicationBindResponse, ldapv3.LDAPResultSuccess, "")
+	}
+	for _, e := range s.entries {
+		if strings.EqualFold(e.dn, dn) && e.password != "" && e.password == password {
+			return ldapResult(ldapv3.ApplicationBindResponse, ldapv3.LDAPResultSuccess, "")
+		}
+	}
+	return ldapResult(ldapv3.ApplicationBindResponse, ldapv3.LDAPResultInvalidCredentials, "invalid credentials")
+}
+
+func (s *testServer) handleSearch(op *ber.Packet) []*ber.Packet {
+	baseDN := strings.ToLower(str(op.Children[0]))
+	sizeLimit, _ := op.Children[3].Value.(int64)
+	filter := op.Children[6]
+	var attrs []string
+	for _, a := range op.Children[7].Children {
+		attrs = append(attrs, str(a))
+	}
+
+	var responses []*ber.Packet
+	for _, e := range s.entries {
+		if !strings.HasSuffix(strings.ToLower(e.dn), baseDN) || !matchFilter(filter, e) {
+			continue
+		}
+		if sizeLimit > 0 && int64(len(responses)) == sizeLimit {
+			return append(responses, ldapResult(ldapv3.ApplicationSearchResultDone, ldapv3.LDAPResultSizeLimitExceeded, ""))
+		}
+		attrsPacket := ber.NewSequence("Attributes")
+		for _, attr := range attrs {
+			if attr == noAttributes {
+				continue
+			}
+			values := e.attrs[strings.ToLower(attr)]
+			if len(values) == 0 {
+				continue
+			}
+			valuesPacket := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+			for _, v := range values {
+				valuesPacket.AppendChild(octetString(v))
+			}
+			attrPacket := ber.NewSequence("Attribute")
+			attrPacket.AppendChild(octetString(attr))
+			attrPacket.AppendChild(valuesPacket)
+			attrsPacket.AppendChild(attrPacket)
+		}
+		entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldapv3.ApplicationSearchResultEntry, nil, "Search Result Entry")
+		entry.AppendChild(octetString(e.dn))
+		entry.AppendChild(attrsPacket)
+		responses = append(responses, entry)
+	}
+	return append(responses, ldapResult(ldapv3.ApplicationSearchResultDone, ldapv3.LDAPResultSuccess, ""))
+}
+
+// matchFilter reports whether the entry matches the BER-encoded filter. Values are compared
+// case-insensitively. The special attribute "dn" refers to the entry's DN.
+func matchFilter(f *ber.Packet, e testEntry) bool {
+	values := func(attr string) []string {
+		if strings.EqualFold(attr, "dn") {
+			return []string{e.dn}
+		}
+		return e.attrs[strings.ToLower(attr)]
+	}
+	switch f.Tag {
+	case ldapv3.FilterAnd:
+		for _, c := range f.Children {
+			if !matchFilter(c, e) {
+				return false
+			}
+		}
+		return true
+	case ldapv3.FilterOr:
+		for _, c := range f.Children {
+			if matchFilter(c, e) {
+				return true
+			}
+		}
+		return false
+	case ldapv3.FilterNot:
+		return !matchFilter(f.Children[0], e)
+	case ldapv3.FilterPresent:
+		return len(values(str(f))) > 0
+	case ldapv3.FilterEqualityMatch:
+		for _, v := range values(str(f.Children[0])) {
+			if strings.EqualFold(v, str(f.Children[1])) {
+				return true
+			}
+		}
+		return false
+	case ldapv3.FilterSubstrings:
+		for _, v := range values(str(f.Children[0])) {
+			if matchSubstrings(strings.ToLower(v), f.Children[1].Children) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+func matchSubstrings(v string, substrings []*ber.Packet) bool {
+	for _, sub := range substrings {
+		s := strings.ToLower(str(sub))
+		switch sub.Tag {
+		case ldapv3.FilterSubstringsInitial:
+			if !strings.HasPrefix(v, s) {
+				return false
+			}
+			v = v[len(s):]
+		case ldapv3.FilterSubstringsAny:
+			i := strings.Index(v, s)
+			if i == -1 {
+				return false
+			}
+			v = v[i+len(s):]
+		case ldapv3.FilterSubstringsFinal:
+			if !strings.HasSuffix(v, s) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// newTestCertificate returns a self-signed certificate for 127.0.0.1 and its PEM encoding.
+func newTestCertificate(t *testing.T) (tls.Certificate, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ldap test server"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	return cert, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
@@ -0,0 +1,194 @@
+package ldap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+	"github.com/sourcegraph/sourcegraph/schema"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+	ldapv3 "gopkg.in/ldap.v3"
+)
+
+// errInvalidCredentials is returned by authenticate when the username or password is incorrect.
+var errInvalidCredentials = errors.New("invalid username or password")
+
+// directoryUser is the information about a user from the LDAP directory.
+type directoryUser struct {
+	dn          string
+	username    string
+	email       string
+	displayName string
+	groups      []string // DNs of the groups that the user is a member of
+}
+
+// authenticate verifies the username and password against the LDAP directory and returns
+// information about the user. If the credentials are incorrect, it returns errInvalidCredentials.
+//
+// 🚨 SECURITY: Any change to this function could allow users to sign in without valid credentials.
+// Be careful.
+func authenticate(ctx context.Context, pc *schema.LDAPAuthProvider, username, password string) (*directoryUser, error) {
+	// 🚨 SECURITY: A bind with a DN and an empty password is an "unauthenticated bind" (RFC 4513
+	// section 5.1.2), which many servers report as successful. Never attempt it.
+	if username == "" || password == "" {
+		return nil, errInvalidCredentials
+	}
+
+	c, err := dial(ctx, pc)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	bindServiceAccount := func() error {
+		if pc.BindDN == "" {
+			return nil
+		}
+		if err := c.Bind(pc.BindDN, pc.BindPassword); err != nil {
+			return fmt.Errorf("bind as service account %q: %s", pc.BindDN, err)
+		}
+		return nil
+	}
+	if err := bindServiceAccount(); err != nil {
+		return nil, err
+	}
+
+	// Look up the user's directory entry. Request 2 entries so we can detect ambiguous filters.
+	entries, err := search(c, pc.UserSearchBase, expandFilter(pc.UserSearchFilter, username, ""), []string{pc.UsernameAttribute, pc.EmailAttribute, pc.DisplayNameAttribute}, 2)
+	if err != nil && !ldapv3.IsErrorWithCode(err, ldapv3.LDAPResultSizeLimitExceeded) {
+		return nil, fmt.Errorf("search for user: %s", err)
+	}
+	if err != nil || len(entries) > 1 {
+		return nil, fmt.Errorf("userSearchFilter matched multiple directory entries for username %q", username)
+	}
+	if len(entries) == 0 {
+		return nil, errInvalidCredentials
+	}
+	e := entries[0]
+
+	// 🚨 SECURITY: Verify the password by binding as the user.
+	if err := c.Bind(e.DN, password); err != nil {
+		if ldapv3.IsErrorWithCode(err, ldapv3.LDAPResultInvalidCredentials) {
+			return nil, errInvalidCredentials
+		}
+		return nil, fmt.Errorf("bind as user: %s", err)
+	}
+
+	u := &directoryUser{
+		dn:          e.DN,
+		username:    attributeValue(e, pc.UsernameAttribute),
+		email:       attributeValue(e, pc.EmailAttribute),
+		displayName: attributeValue(e, pc.DisplayNameAttribute),
+	}
+	if u.username == "" {
+		u.username = username
+	}
+
+	if pc.GroupSearchBase != "" {
+		// The user may not be permitted to search for groups, so search as the service account.
+		if err := bindServiceAccount(); err != nil {
+			return nil, err
+		}
+		groups, err := search(c, pc.GroupSearchBase, expandFilter(pc.GroupSearchFilter, username, e.DN), []string{noAttributes}, 0)
+		if err != nil {
+			return nil, fmt.Errorf("search for groups: %s", err)
+		}
+		for _, g := range groups {
+			u.groups = append(u.groups, g.DN)
+		}
+	}
+	return u, nil
+}
+
+// accountData is the data stored for each LDAP external account.
+type accountData struct {
+	DN     string   `json:"dn"`
+	Groups []string `json:"groups,omitempty"`
+}
+
+// getOrCreateUser returns the ID of the Sourcegraph user for the directory user, creating the user
+// if necessary (and if allowed).
+func getOrCreateUser(ctx context.Context, pc *schema.LDAPAuthProvider, u *directoryUser) (userID int32, safeErrMsg string, err error) {
+	username, err := auth.NormalizeUsername(u.username)
+	if err != nil {
+		return 0, fmt.Sprintf("Error normalizing the username %q. See https://docs.sourcegraph.com/admin/auth/#username-normalization.", u.username), err
+	}
+
+	var data extsvc.ExternalAccountData
+	data.SetAccountData(accountData{DN: u.dn, Groups: u.groups})
+	return auth.GetAndSaveUser(ctx, auth.GetAndSaveUserOp{
+		UserProps: db.NewUser{
+			Username:        username,
+			Email:           u.email,
+			EmailIsVerified: u.email != "", // email addresses from the directory are assumed to be verified
+			DisplayName:     u.displayName,
+		},
+		ExternalAccount: extsvc.ExternalAccountSpec{
+			ServiceType: providerType,
+			ServiceID:   pc.Url,
+			AccountID:   u.dn,
+		},
+		ExternalAccountData: data,
+		CreateIfNotExist:    allowSignup(pc),
+	})
+}
+
+// syncOrgMemberships ensures that the user is a member of exactly those organizations (among the
+// organizations in groupOrgMap) that groupOrgMap maps the user's groups to.
+func syncOrgMemberships(ctx context.Context, userID int32, groupOrgMap map[string][]string, groups []string) error {
+	isMemberOfGroup := func(group string) bool {
+		for _, g := range groups {
+			if strings.EqualFold(g, group) { // DNs are case-insensitive
+				return true
+			}
+		}
+		return false
+	}
+
+	wantOrgs := map[string]bool{} // mapped org name -> whether the user should be a member
+	for group, orgs := range groupOrgMap {
+		for _, org := range orgs {
+			wantOrgs[org] = wantOrgs[org] || isMemberOfGroup(group)
+		}
+	}
+	orgNames := make([]string, 0, len(wantOrgs))
+	for org := range wantOrgs {
+		orgNames = append(orgNames, org)
+	}
+	sort.Strings(orgNames)
+
+	for _, orgName := range orgNames {
+		org, err := db.Orgs.GetByName(ctx, orgName)
+		if err != nil {
+			if _, ok := err.(*db.OrgNotFoundError); ok {
+				log15.Warn("Organization in LDAP auth provider groupOrgMap does not exist.", "org", orgName)
+				continue
+			}
+			return err
+		}
+
+		_, err = db.OrgMembers.GetByOrgIDAndUserID(ctx, org.ID, userID)
+		if err != nil && !errcode.IsNotFound(err) {
+			return err
+		}
+		isMember := err == nil
+
+		switch want := wantOrgs[orgName]; {
+		case want && !isMember:
+			if _, err := db.OrgMembers.Create(ctx, org.ID, userID); err != nil {
+				return err
+			}
+		case !want && isMember:
+			if err := db.OrgMembers.Remove(ctx, org.ID, userID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package ldap
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+var testDirectory = []testEntry{
+	{
+		dn:       "cn=admin,dc=example,dc=com",
+		password: "adminpw",
+	},
+	{
+		dn:       "uid=alice,ou=people,dc=example,dc=com",
+		password: "alicepw",
+		attrs: map[string][]string{
+			"uid":  {"alice"},
+			"mail": {"alice@example.com"},
+			"cn":   {"Alice Smith"},
+		},
+	},
+	{
+		dn:       "uid=bob,ou=people,dc=example,dc=com",
+		password: "bobpw",
+		attrs: map[string][]string{
+			"uid": {"bob"},
+		},
+	},
+	{
+		dn: "cn=engineering,ou=groups,dc=example,dc=com",
+		attrs: map[string][]string{
+			"member": {"uid=alice,ou=people,dc=example,dc=com", "uid=bob,ou=people,dc=example,dc=com"},
+		},
+	},
+	{
+		dn: "cn=admins,ou=groups,dc=example,dc=com",
+		attrs: map[string][]string{
+			"member": {"uid=alice,ou=people,dc=example,dc=com"},
+		},
+	},
+}
+
+func TestAuthenticate(t *testing.T) {
+	s := newTestServer(t, testDirectory...)
+	defer s.close()
+
+	pc := withDefaults(&schema.LDAPAuthProvider{
+		Url:             s.url,
+		BindDN:          "cn=admin,dc=example,dc=com",
+		BindPassword:    "adminpw",
+		UserSearchBase:  "ou=people,dc=example,dc=com",
+		GroupSearchBase: "ou=groups,dc=example,dc=com",
+	})
+	ctx := context.Background()
+
+	t.Run("valid credentials", func(t *testing.T) {
+		u, err := authenticate(ctx, pc, "alice", "alicepw")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &directoryUser{
+			dn:          "uid=alice,ou=people,dc=example,dc=com",
+			username:    "alice",
+			email:       "alice@example.com",
+			displayName: "Alice Smith",
+			groups:      []string{"cn=engineering,ou=groups,dc=example,dc=com", "cn=admins,ou=groups,dc=example,dc=com"},
+		}
+		if !reflect.DeepEqual(u, want) {
+			t.Errorf("got %+v, want %+v", u, want)
+		}
+	})
+
+	t.Run("valid credentials, missing attributes", func(t *testing.T) {
+		u, err := authenticate(ctx, pc, "bob", "bobpw")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.username != "bob" || u.email != "" || u.displayName != "" || len(u.groups) != 1 {
+			t.Errorf("got %+v", u)
+		}
+	})
+
+	for name, test := range map[string]struct{ username, password string }{
+		"wrong password":   {"alice", "bobpw"},
+		"unknown user":     {"carol", "carolpw"},
+		"empty password":   {"alice", ""}, // the test server (like many real servers) allows unauthenticated binds
+		"empty username":   {"", "alicepw"},
+		"filter injection": {"*", "alicepw"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := authenticate(ctx, pc, test.username, test.password); err != errInvalidCredentials {
+				t.Errorf("got error %v, want %v", err, errInvalidCredentials)
+			}
+		})
+	}
+
+	t.Run("binds", func(t *testing.T) {
+		before := len(s.bindDNs())
+		if _, err := authenticate(ctx, pc, "alice", "alicepw"); err != nil {
+			t.Fatal(err)
+		}
+		got := s.bindDNs()[before:]
+		want := []string{"cn=admin,dc=example,dc=com", "uid=alice,ou=people,dc=example,dc=com", "cn=admin,dc=example,dc=com"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got binds %q, want %q", got, want)
+		}
+	})
+
+	t.Run("ambiguous userSearchFilter", func(t *testing.T) {
+		pc := *pc
+		pc.UserSearchFilter = "(|(uid={username})(uid=b*))"
+		_, err := authenticate(ctx, &pc, "alice", "alicepw")
+		if err == nil || err == errInvalidCredentials {
+			t.Errorf("got error %v, want error about multiple entries", err)
+		}
+	})
+
+	t.Run("wrong service account password", func(t *testing.T) {
+		pc := *pc
+		pc.BindPassword = "wrong"
+		_, err := authenticate(ctx, &pc, "alice", "alicepw")
+		if err == nil || err == errInvalidCredentials {
+			t.Errorf("got error %v, want service account bind error", err)
+		}
+	})
+}
+
+func TestAuthenticate_TLS(t *testing.T) {
+	cert, certPEM := newTestCertificate(t)
+	s := newTLSTestServer(t, cert, testDirectory...)
+	defer s.close()
+
+	ctx := context.Background()
+	authenticateWithTLS := func(tlsConfig *schema.LDAPTLSConfig) error {
+		pc := withDefaults(&schema.LDAPAuthProvider{
+			Url:            s.url,
+			Tls:            tlsConfig,
+			UserSearchBase: "ou=people,dc=example,dc=com",
+		})
+		_, err := authenticate(ctx, pc, "alice", "alicepw")
+		return err
+	}
+
+	// 🚨 SECURITY: The server's certificate must be verified unless explicitly configured otherwise.
+	if err := authenticateWithTLS(nil); err == nil {
+		t.Error("got nil error for untrusted certificate, want error")
+	}
+	if err := authenticateWithTLS(&schema.LDAPTLSConfig{Ca: certPEM}); err != nil {
+		t.Errorf("got error %v with tls.ca, want nil", err)
+	}
+	if err := authenticateWithTLS(&schema.LDAPTLSConfig{InsecureSkipVerify: true}); err != nil {
+		t.Errorf("got error %v with tls.insecureSkipVerify, want nil", err)
+	}
+}
+
+func TestSyncOrgMemberships(t *testing.T) {
+	orgs := map[string]int32{"eng": 1, "admins": 2, "other": 3}
+	db.Mocks.Orgs.GetByName = func(ctx context.Context, name string) (*types.Org, error) {
+		if id, ok := orgs[name]; ok {
+			return &types.Org{ID: id, Name: name}, nil
+		}
+		return nil, &db.OrgNotFoundError{}
+	}
+	// The user is initially a member of orgs 2 and 3.
+	members := map[int32]bool{2: true, 3: true}
+	db.Mocks.OrgMembers.GetByOrgIDAndUserID = func(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+		if members[orgID] {
+			return &types.OrgMembership{OrgID: orgID, UserID: userID}, nil
+		}
+		return nil, &db.ErrOrgMemberNotFound{}
+	}
+	db.Mocks.OrgMembers.Create = func(ctx context.Context, orgID, userID int32) (*types.OrgMembership, error) {
+		members[orgID] = true
+		return &types.OrgMembership{OrgID: orgID, UserID: userID}, nil
+	}
+	db.Mocks.OrgMembers.Remove = func(ctx context.Context, orgID, userID int32) error {
+		delete(members, orgID)
+		return nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	groupOrgMap := map[string][]string{
+		"cn=engineering,ou=groups,dc=example,dc=com": {"eng", "missing"},
+		"cn=admins,ou=groups,dc=example,dc=com":      {"admins"},
+	}
+	groups := []string{"CN=Engineering,OU=Groups,DC=example,DC=com", "cn=unmapped,ou=groups,dc=example,dc=com"}
+	if err := syncOrgMemberships(context.Background(), 1, groupOrgMap, groups); err != nil {
+		t.Fatal(err)
+	}
+
+	// The user is added to org 1 (via the case-insensitive group DN match) and removed from org 2.
+	// Org 3 is not in groupOrgMap, so the membership is left alone.
+	if want := map[int32]bool{1: true, 3: true}; !reflect.DeepEqual(members, want) {
+		t.Errorf("got memberships %v, want %v", members, want)
+	}
+}
@@ -56,7 +56,7 @@ func SessionIssuer(s SessionIssuerHelper, sessionKey string) http.Handler {
 			http.Error(w, "Authentication failed. Try signing in again (and clearing cookies for the current site). The error was: OAuth token was expired.", http.StatusInternalServerError)
 			return
 		}
-		if err := session.SetActor(w, r, actr, expiryDuration); err != nil { // TODO: test session expiration
+		if err := session.SetSignedInActor(w, r, actr, expiryDuration, session.SignInInfo{AuthProvider: s.SessionData(token).ID.Type}); err != nil { // TODO: test session expiration
 			log15.Error("OAuth failed: could not initiate session.", "error", err)
 			http.Error(w, "Authentication failed. Try signing in again (and clearing cookies for the current site). The error was: could not initiate session.", http.StatusInternalServerError)
 			return
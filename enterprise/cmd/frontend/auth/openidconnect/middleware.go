@@ -239,7 +239,7 @@ func authHandler(w http.ResponseWriter, r *http.Request) {
 		// if !idToken.Expiry.IsZero() {
 		// 	exp = time.Until(idToken.Expiry)
 		// }
-		if err := session.SetActor(w, r, actr, exp); err != nil {
+		if err := session.SetSignedInActor(w, r, actr, exp, session.SignInInfo{AuthProvider: p.ConfigID().Type}); err != nil {
 			log15.Error("OpenID Connect auth failed: could not initiate session.", "error", err)
 			http.Error(w, "Authentication failed. Try signing in again (and clearing cookies for the current site). The error was: could not initiate session.", http.StatusInternalServerError)
 			return
@@ -154,7 +154,7 @@ func samlSPHandler(w http.ResponseWriter, r *http.Request) {
 		// if info.SessionNotOnOrAfter != nil {
 		// 	exp = time.Until(*info.SessionNotOnOrAfter)
 		// }
-		if err := session.SetActor(w, r, actor, exp); err != nil {
+		if err := session.SetSignedInActor(w, r, actor, exp, session.SignInInfo{AuthProvider: p.ConfigID().Type}); err != nil {
 			log15.Error("Error setting SAML-authenticated actor in session.", "err", err)
 			http.Error(w, "Error starting SAML-authenticated session. Try signing in again.", http.StatusInternalServerError)
 			return
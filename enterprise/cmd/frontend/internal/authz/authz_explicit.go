@@ -0,0 +1,93 @@
+package authz
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/explicit"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitolite"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func gitoliteProviders(conns []*schema.GitoliteConnection) (
+	authzProviders []authz.Provider,
+	seriousProblems []string,
+	warnings []string,
+) {
+	seen := map[string]bool{}
+	for _, c := range conns {
+		if c.Authorization == nil {
+			continue
+		}
+		serviceID := gitolite.ServiceID(c.Host)
+		if seen[serviceID] {
+			continue
+		}
+		seen[serviceID] = true
+		authzProviders = append(authzProviders, explicit.NewProvider(gitolite.ServiceType, serviceID))
+	}
+	return authzProviders, seriousProblems, warnings
+}
+
+func otherExternalServiceProviders(conns []*schema.OtherExternalServiceConnection) (
+	authzProviders []authz.Provider,
+	seriousProblems []string,
+	warnings []string,
+) {
+	seen := map[string]bool{}
+	for _, c := range conns {
+		if c.Authorization == nil {
+			continue
+		}
+		serviceIDs, err := otherExternalServiceIDs(c)
+		if err != nil {
+			seriousProblems = append(seriousProblems, err.Error())
+			continue
+		}
+		for _, serviceID := range serviceIDs {
+			if seen[serviceID] {
+				continue
+			}
+			seen[serviceID] = true
+			authzProviders = append(authzProviders, explicit.NewProvider("other", serviceID))
+		}
+	}
+	return authzProviders, seriousProblems, warnings
+}
+
+// otherExternalServiceIDs returns the distinct service IDs of the repositories of the given
+// connection. It must compute them the same way repo-updater does: the service ID of a repository
+// is its clone URL without path and query.
+func otherExternalServiceIDs(c *schema.OtherExternalServiceConnection) ([]string, error) {
+	var base *url.URL
+	if c.Url != "" {
+		var err error
+		if base, err = url.Parse(c.Url); err != nil {
+			return nil, fmt.Errorf("Could not parse URL for other external service %q: %s", c.Url, err)
+		}
+	}
+
+	var serviceIDs []string
+	seen := map[string]bool{}
+	for _, repo := range c.Repos {
+		var (
+			u   *url.URL
+			err error
+		)
+		if base == nil {
+			u, err = url.Parse(repo)
+		} else {
+			u, err = base.Parse(repo)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Could not parse clone URL of other external service repository %q: %s", repo, err)
+		}
+		u.Path, u.RawQuery = "", ""
+		if serviceID := u.String(); !seen[serviceID] {
+			seen[serviceID] = true
+			serviceIDs = append(serviceIDs, serviceID)
+		}
+	}
+	return serviceIDs, nil
+}
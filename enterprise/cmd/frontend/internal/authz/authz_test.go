@@ -478,6 +478,8 @@ type fakeStore struct {
 	gitlabs          []*schema.GitLabConnection
 	githubs          []*schema.GitHubConnection
 	bitbucketServers []*schema.BitbucketServerConnection
+	gitolites        []*schema.GitoliteConnection
+	others           []*schema.OtherExternalServiceConnection
 }
 
 func (s fakeStore) ListGitHubConnections(context.Context) ([]*schema.GitHubConnection, error) {
@@ -491,3 +493,11 @@ func (s fakeStore) ListGitLabConnections(context.Context) ([]*schema.GitLabConne
 func (s fakeStore) ListBitbucketServerConnections(context.Context) ([]*schema.BitbucketServerConnection, error) {
 	return s.bitbucketServers, nil
 }
+
+func (s fakeStore) ListGitoliteConnections(context.Context) ([]*schema.GitoliteConnection, error) {
+	return s.gitolites, nil
+}
+
+func (s fakeStore) ListOtherExternalServicesConnections(context.Context) ([]*schema.OtherExternalServiceConnection, error) {
+	return s.others, nil
+}
@@ -0,0 +1,114 @@
+// Package explicit contains an authorization provider for repositories whose code host has no
+// permissions of its own (such as Gitolite and "other" repositories). Site admins explicitly grant
+// users access to such repositories, and the grants are stored in the explicit_repo_permissions
+// table.
+package explicit
+
+import (
+	"context"
+	"regexp"
+
+	otlog "github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+	"github.com/sourcegraph/sourcegraph/pkg/trace"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// Provider is an implementation of authz.UserProvider that grants users read access to the
+// repositories of a code host that site admins explicitly granted them access to, either directly
+// or through a repository name pattern. The code host has no notion of user accounts, so users
+// are identified by their Sourcegraph user ID.
+type Provider struct {
+	codeHost *extsvc.CodeHost
+}
+
+var _ authz.UserProvider = ((*Provider)(nil))
+
+// NewProvider returns a new explicit permissions provider for the repositories with the given
+// external service type and ID.
+func NewProvider(serviceType, serviceID string) *Provider {
+	return &Provider{codeHost: &extsvc.CodeHost{ServiceType: serviceType, ServiceID: serviceID}}
+}
+
+// ServiceID returns the service ID of the repositories this provider is responsible for.
+func (p *Provider) ServiceID() string { return p.codeHost.ServiceID }
+
+// ServiceType returns the service type of the repositories this provider is responsible for.
+func (p *Provider) ServiceType() string { return p.codeHost.ServiceType }
+
+// Validate implements the authz.Provider interface. The provider has no configuration that can be
+// invalid.
+func (p *Provider) Validate() []string { return nil }
+
+// FetchAccount implements the authz.Provider interface. The code host has no notion of user
+// accounts, so it always returns nil.
+func (p *Provider) FetchAccount(context.Context, *types.User, []*extsvc.ExternalAccount) (*extsvc.ExternalAccount, error) {
+	return nil, nil
+}
+
+// RepoPerms implements the authz.Provider interface. The code host has no notion of user
+// accounts, so no account grants access to any repository. Callers use UserRepoPerms instead.
+func (p *Provider) RepoPerms(context.Context, *extsvc.ExternalAccount, []*types.Repo) ([]authz.RepoPerms, error) {
+	return nil, nil
+}
+
+// UserRepoPerms implements the authz.UserProvider interface. It grants read access to the
+// repositories the user was explicitly granted access to. Unauthenticated users have no access.
+func (p *Provider) UserRepoPerms(ctx context.Context, user *types.User, repos []*types.Repo) (perms []authz.RepoPerms, err error) {
+	tr, ctx := trace.New(ctx, "explicit.authz.provider.UserRepoPerms", "")
+	defer func() {
+		if user != nil {
+			tr.LogFields(otlog.Int32("user.id", user.ID))
+		}
+		tr.LogFields(
+			otlog.Int("repos.count", len(repos)),
+			otlog.Int("perms.count", len(perms)),
+		)
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	if user == nil {
+		return nil, nil
+	}
+
+	granted, err := db.ExplicitRepoPermissions.GetForUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	repoIDs := make(map[api.RepoID]bool, len(granted.RepoIDs))
+	for _, id := range granted.RepoIDs {
+		repoIDs[id] = true
+	}
+	patterns := make([]*regexp.Regexp, 0, len(granted.Patterns))
+	for _, pattern := range granted.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			// Patterns are validated when they are stored, so this should never happen.
+			log15.Warn("Ignoring invalid explicit repository permissions pattern.", "pattern", pattern, "error", err)
+			continue
+		}
+		patterns = append(patterns, re)
+	}
+
+	for _, repo := range repos {
+		if repoIDs[repo.ID] || matchesAny(patterns, string(repo.Name)) {
+			perms = append(perms, authz.RepoPerms{Repo: repo, Perms: authz.Read})
+		}
+	}
+	return perms, nil
+}
+
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, re := range patterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
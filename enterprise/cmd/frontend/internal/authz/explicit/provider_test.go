@@ -0,0 +1,75 @@
+package explicit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+func TestProvider_UserRepoPerms(t *testing.T) {
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	p := NewProvider("gitolite", "git@gitolite.example.com")
+
+	db.Mocks.ExplicitRepoPermissions.GetForUser = func(ctx context.Context, userID int32) (*db.ExplicitUserRepoPermissions, error) {
+		if userID != 1 {
+			return &db.ExplicitUserRepoPermissions{}, nil
+		}
+		return &db.ExplicitUserRepoPermissions{
+			RepoIDs:  []api.RepoID{1},
+			Patterns: []string{"^gitolite.example.com/team/", "("}, // invalid patterns are ignored
+		}, nil
+	}
+
+	repos := []*types.Repo{
+		{ID: 1, Name: "gitolite.example.com/a"},
+		{ID: 2, Name: "gitolite.example.com/b"},
+		{ID: 3, Name: "gitolite.example.com/team/c"},
+	}
+
+	tests := []struct {
+		name    string
+		user    *types.User
+		repoIDs []api.RepoID
+	}{
+		{name: "granted user", user: &types.User{ID: 1}, repoIDs: []api.RepoID{1, 3}},
+		{name: "other user", user: &types.User{ID: 2}, repoIDs: nil},
+		{name: "unauthenticated", user: nil, repoIDs: nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			perms, err := p.UserRepoPerms(context.Background(), test.user, repos)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var repoIDs []api.RepoID
+			for _, perm := range perms {
+				if perm.Perms != authz.Read {
+					t.Errorf("got perms %v on repo %d, want read", perm.Perms, perm.Repo.ID)
+				}
+				repoIDs = append(repoIDs, perm.Repo.ID)
+			}
+			if !reflect.DeepEqual(repoIDs, test.repoIDs) {
+				t.Errorf("got repos %v, want %v", repoIDs, test.repoIDs)
+			}
+		})
+	}
+}
+
+func TestProvider_FetchAccount(t *testing.T) {
+	p := NewProvider("gitolite", "git@gitolite.example.com")
+
+	// No external account must be created (and saved) for the user.
+	acct, err := p.FetchAccount(context.Background(), &types.User{ID: 1}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acct != nil {
+		t.Errorf("got account %+v, want nil", acct)
+	}
+}
@@ -20,8 +20,9 @@ type userRepoCacheKey struct {
 }
 
 type userRepoCacheVal struct {
-	Read bool
-	TTL  time.Duration
+	Read  bool
+	Write bool
+	TTL   time.Duration
 }
 
 func publicRepoCacheKey(ghrepoID string) string {
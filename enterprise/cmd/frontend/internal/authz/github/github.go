@@ -47,7 +47,7 @@ var _ authz.Provider = ((*Provider)(nil))
 // RepoPerms implements the authz.Provider interface.
 //
 // It computes permissions by keeping track of two classes of info:
-// * Whether a given user can access a given repository
+// * Whether a given user can access (and push to) a given repository
 // * Whether a given repository is public
 //
 // For each repo in the input set, we look first to see if the above information is cached in Redis.
@@ -83,18 +83,14 @@ func (p *Provider) RepoPerms(ctx context.Context, userAccount *extsvc.ExternalAc
 		remainingPublic = nextRemainingPublic
 		return nil
 	}
-	populatePerms := func(checkAccess func(ctx context.Context, userAccount *extsvc.ExternalAccount, repos []*types.Repo) (map[string]bool, error)) error {
+	populatePerms := func(checkAccess func(ctx context.Context, userAccount *extsvc.ExternalAccount, repos []*types.Repo) (map[string]authz.Perms, error)) error {
 		nextRemaining := []*types.Repo{}
-		canAccess, err := checkAccess(ctx, userAccount, remaining)
+		userPerms, err := checkAccess(ctx, userAccount, remaining)
 		if err != nil {
 			return err
 		}
 		for _, repo := range remaining {
-			if canAcc, isExplicit := canAccess[repo.ExternalRepo.ID]; isExplicit {
-				p := authz.None
-				if canAcc {
-					p = authz.Read
-				}
+			if p, isExplicit := userPerms[repo.ExternalRepo.ID]; isExplicit {
 				perms = append(perms, authz.RepoPerms{Repo: repo, Perms: p})
 				continue
 			}
@@ -218,11 +214,10 @@ func (p *Provider) fetchPublicRepos(ctx context.Context, repos []*types.Repo) (m
 }
 
 // fetchAndSetUserRepos accepts a user account and a set of repos. It returns a map from repository
-// external ID to true/false indicating whether the given user has read access to each repo. If a
-// repo ID is missing from the return map, the user does not have read access to that repo. As a
-// side effect, it caches the fetched repos (whether the given user has access to each and whether
-// each is public).
-func (p *Provider) fetchAndSetUserRepos(ctx context.Context, userAccount *extsvc.ExternalAccount, repos []*types.Repo) (isAllowed map[string]bool, err error) {
+// external ID to the permissions the given user has on each repo. If a repo ID is missing from the
+// return map, the user does not have read access to that repo. As a side effect, it caches the
+// fetched repos (the given user's permissions on each and whether each is public).
+func (p *Provider) fetchAndSetUserRepos(ctx context.Context, userAccount *extsvc.ExternalAccount, repos []*types.Repo) (userPerms map[string]authz.Perms, err error) {
 	if userAccount == nil {
 		return nil, nil
 	}
@@ -234,14 +229,21 @@ func (p *Provider) fetchAndSetUserRepos(ctx context.Context, userAccount *extsvc
 		i++
 	}
 
-	canAccess, isPublic, err := p.fetchUserRepos(ctx, userAccount, repoIDs)
+	canAccess, canWrite, isPublic, err := p.fetchUserRepos(ctx, userAccount, repoIDs)
 	if err != nil {
 		return nil, err
 	}
-	userRepos := make(map[string]bool)
+	userRepos := make(map[string]authz.Perms)
 	publicRepos := make(map[string]bool)
 	for _, r := range repos {
-		userRepos[r.ExternalRepo.ID] = canAccess[r.ExternalRepo.ID]
+		perms := authz.None
+		if canAccess[r.ExternalRepo.ID] {
+			perms = authz.Read
+			if canWrite[r.ExternalRepo.ID] {
+				perms |= authz.Write
+			}
+		}
+		userRepos[r.ExternalRepo.ID] = perms
 		publicRepos[r.ExternalRepo.ID] = isPublic[r.ExternalRepo.ID]
 	}
 
@@ -254,19 +256,22 @@ func (p *Provider) fetchAndSetUserRepos(ctx context.Context, userAccount *extsvc
 	return userRepos, nil
 }
 
-// setCachedUserRepos updates the cache with a map from GitHub repo ID to true/false indicating
-// whether the user can access the repo. The GitHub repo ID is the GraphQL API ID ("repository node
-// ID").
+// setCachedUserRepos updates the cache with a map from GitHub repo ID to the user's permissions on
+// the repo. The GitHub repo ID is the GraphQL API ID ("repository node ID").
 //
 // Internally, it sets a separate cache key for each user and repo ID.
-func (p *Provider) setCachedUserRepos(ctx context.Context, userAccount *extsvc.ExternalAccount, isAllowed map[string]bool) error {
-	setArgs := make([][2]string, 0, len(isAllowed))
-	for k, v := range isAllowed {
+func (p *Provider) setCachedUserRepos(ctx context.Context, userAccount *extsvc.ExternalAccount, userPerms map[string]authz.Perms) error {
+	setArgs := make([][2]string, 0, len(userPerms))
+	for k, v := range userPerms {
 		rkey, err := json.Marshal(userRepoCacheKey{User: userAccount.AccountID, Repo: k})
 		if err != nil {
 			return err
 		}
-		rval, err := json.Marshal(userRepoCacheVal{Read: v, TTL: p.cacheTTL})
+		rval, err := json.Marshal(userRepoCacheVal{
+			Read:  v.Include(authz.Read),
+			Write: v.Include(authz.Write),
+			TTL:   p.cacheTTL,
+		})
 		if err != nil {
 			return err
 		}
@@ -276,10 +281,10 @@ func (p *Provider) setCachedUserRepos(ctx context.Context, userAccount *extsvc.E
 	return nil
 }
 
-// getCachedUserRepos accepts a user account and set of repos and returns a map from repo ID to
-// true/false indicating whether the user can access the repo. The returned map may be incomplete
-// (i.e., not every input repo may be represented in the key set) due to cache incompleteness.
-func (p *Provider) getCachedUserRepos(ctx context.Context, userAccount *extsvc.ExternalAccount, repos []*types.Repo) (cachedUserRepos map[string]bool, err error) {
+// getCachedUserRepos accepts a user account and set of repos and returns a map from repo ID to the
+// user's permissions on the repo. The returned map may be incomplete (i.e., not every input repo
+// may be represented in the key set) due to cache incompleteness.
+func (p *Provider) getCachedUserRepos(ctx context.Context, userAccount *extsvc.ExternalAccount, repos []*types.Repo) (cachedUserRepos map[string]authz.Perms, err error) {
 	if userAccount == nil {
 		return nil, nil
 	}
@@ -302,7 +307,7 @@ func (p *Provider) getCachedUserRepos(ctx context.Context, userAccount *extsvc.E
 	if len(cacheVals) == 0 {
 		return nil, nil
 	}
-	cachedIsAllowed := make(map[string]bool)
+	cachedPerms := make(map[string]authz.Perms)
 	for i, v := range cacheVals {
 		if len(v) == 0 {
 			continue
@@ -316,15 +321,28 @@ func (p *Provider) getCachedUserRepos(ctx context.Context, userAccount *extsvc.E
 			// if the cache TTL is now less than the cache entry TTL, invalidate that entry
 			continue
 		}
-		cachedIsAllowed[repoList[i]] = val.Read
+		perms := authz.None
+		if val.Read {
+			perms = authz.Read
+			if val.Write {
+				perms |= authz.Write
+			}
+		}
+		cachedPerms[repoList[i]] = perms
 	}
-	return cachedIsAllowed, nil
+	return cachedPerms, nil
 }
 
-func (p *Provider) fetchUserRepos(ctx context.Context, userAccount *extsvc.ExternalAccount, repoIDs []string) (canAccess map[string]bool, isPublic map[string]bool, err error) {
+// fetchUserRepos fetches from the GitHub API whether the given user can access and push to the
+// given repos, and whether each repo is public.
+//
+// Push access is derived from the repository's viewerPermission, which is not available on older
+// GitHub Enterprise instances. In that case, the user is conservatively treated as having read-only
+// access.
+func (p *Provider) fetchUserRepos(ctx context.Context, userAccount *extsvc.ExternalAccount, repoIDs []string) (canAccess, canWrite, isPublic map[string]bool, err error) {
 	_, tok, err := github.GetExternalAccountData(&userAccount.ExternalAccountData)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	// Batch fetch repos from API
@@ -336,7 +354,7 @@ func (p *Provider) fetchUserRepos(ctx context.Context, userAccount *extsvc.Exter
 		}
 		ghReposBatch, err := p.client.GetRepositoriesByNodeIDFromAPI(ctx, tok.AccessToken, repoIDs[i:j])
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 		for k, v := range ghReposBatch {
 			ghRepos[k] = v
@@ -348,12 +366,14 @@ func (p *Provider) fetchUserRepos(ctx context.Context, userAccount *extsvc.Exter
 		isPublic[r.ID] = !r.IsPrivate
 	}
 	canAccess = make(map[string]bool)
+	canWrite = make(map[string]bool)
 	for _, rid := range repoIDs {
-		_, exists := ghRepos[rid]
+		r, exists := ghRepos[rid]
 		canAccess[rid] = exists
+		canWrite[rid] = exists && (r.ViewerPermission == "ADMIN" || r.ViewerPermission == "WRITE")
 	}
 
-	return canAccess, isPublic, nil
+	return canAccess, canWrite, isPublic, nil
 }
 
 // fetchUserRepo fetches whether the given user can access the given repo from the GitHub API.
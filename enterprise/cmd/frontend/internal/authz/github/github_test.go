@@ -208,10 +208,10 @@ func TestProvider_RepoPerms(t *testing.T) {
 
 func Test_fetchUserRepos(t *testing.T) {
 	githubMock := newMockGitHub([]*github.Repository{
-		{ID: "u0/private", IsPrivate: true},
-		{ID: "u0/public"},
+		{ID: "u0/private", IsPrivate: true, ViewerPermission: "ADMIN"},
+		{ID: "u0/public", ViewerPermission: "WRITE"},
 		{ID: "u1/private", IsPrivate: true},
-		{ID: "u1/public"},
+		{ID: "u1/public", ViewerPermission: "READ"},
 		{ID: "u99/private", IsPrivate: true},
 		{ID: "u99/public"},
 	}, map[string][]string{
@@ -225,7 +225,7 @@ func Test_fetchUserRepos(t *testing.T) {
 	defer func() { github.MaxNodeIDs = oldMaxNodeIDs }()
 
 	provider := NewProvider(mustURL(t, "https://github.com"), "base-token", 0, make(authz.MockCache))
-	canAccess, isPublic, err := provider.fetchUserRepos(context.Background(), ua("u0", "t0"), []string{
+	canAccess, canWrite, isPublic, err := provider.fetchUserRepos(context.Background(), ua("u0", "t0"), []string{
 		"u0/private",
 		"u0/public",
 		"u1/private",
@@ -245,6 +245,14 @@ func Test_fetchUserRepos(t *testing.T) {
 		"u99/private": false,
 		"u99/public":  true,
 	}
+	wantCanWrite := map[string]bool{
+		"u0/private":  true,
+		"u0/public":   true,
+		"u1/private":  false,
+		"u1/public":   false,
+		"u99/private": false,
+		"u99/public":  false,
+	}
 	wantIsPublic := map[string]bool{
 		"u0/private": false,
 		"u0/public":  true,
@@ -255,6 +263,9 @@ func Test_fetchUserRepos(t *testing.T) {
 	if !reflect.DeepEqual(canAccess, wantCanAccess) {
 		t.Errorf("canAccess %+v != wantCanAccess %+v", canAccess, wantCanAccess)
 	}
+	if !reflect.DeepEqual(canWrite, wantCanWrite) {
+		t.Errorf("canWrite %+v != wantCanWrite %+v", canWrite, wantCanWrite)
+	}
 	if !reflect.DeepEqual(isPublic, wantIsPublic) {
 		t.Errorf("isPublic %+v != wantIsPublic %+v", isPublic, wantIsPublic)
 	}
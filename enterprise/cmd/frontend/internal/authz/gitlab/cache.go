@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitlab"
 )
 
@@ -25,9 +26,25 @@ type userRepoCacheVal struct {
 	// Read is whether or not the repository can be read by the user specified in the key
 	Read bool
 
+	// Write is whether or not the user specified in the key can push to the repository (i.e., has
+	// at least "Developer" access)
+	Write bool
+
 	TTL time.Duration
 }
 
+// perms returns the repository permissions described by the cache value.
+func (v userRepoCacheVal) perms() authz.Perms {
+	perms := authz.None
+	if v.Read {
+		perms = authz.Read
+		if v.Write {
+			perms |= authz.Write
+		}
+	}
+	return perms
+}
+
 func cacheGetUserRepo(c cache, gitlabAccountID string, gitlabProjID int, ttl time.Duration) (v userRepoCacheVal, exists bool) {
 	k := userProjCacheKey(gitlabAccountID, gitlabProjID)
 	b, exists := c.Get(k)
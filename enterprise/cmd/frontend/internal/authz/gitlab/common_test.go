@@ -45,6 +45,10 @@ type mockGitLab struct {
 	// Projects in each list are also metadata-accessible.
 	privateRepo map[int32][]int
 
+	// developers is a map from project ID to the IDs of users with "Developer" (push) access. These
+	// users must also be granted access to the project via the other fields.
+	developers map[int][]int32
+
 	// oauthToks is a map from OAuth token to GitLab user account ID
 	oauthToks map[string]int32
 
@@ -78,6 +82,9 @@ type mockGitLabOp struct {
 	// project metadata, but not project repository contents. A "content" user can access both.
 	privateProjs map[int][2][]int32
 
+	// developers is a map from project ID to the IDs of users with "Developer" (push) access to it
+	developers map[int][]int32
+
 	// oauthToks is a map from OAuth tokens to the corresponding GitLab user ID
 	oauthToks map[string]int32
 
@@ -114,6 +121,7 @@ func newMockGitLab(op mockGitLabOp) mockGitLab {
 		users:          op.users,
 		privateGuest:   privateGuest,
 		privateRepo:    privateRepo,
+		developers:     op.developers,
 		oauthToks:      op.oauthToks,
 		sudoTok:        op.sudoTok,
 		madeGetProject: map[string]map[gitlab.GetProjectOp]int{},
@@ -132,6 +140,16 @@ func (m *mockGitLab) GetProject(c *gitlab.Client, ctx context.Context, op gitlab
 	if !ok {
 		return nil, gitlab.ErrNotFound
 	}
+	acctID := m.getAcctID(c)
+	for _, u := range m.developers[op.ID] {
+		if u == acctID && acctID != 0 {
+			projWithPerms := *proj
+			projWithPerms.Permissions = &gitlab.ProjectPermissions{
+				ProjectAccess: &gitlab.MemberAccess{AccessLevel: gitlab.DeveloperAccess},
+			}
+			proj = &projWithPerms
+		}
+	}
 	if proj.Visibility == gitlab.Public {
 		return proj, nil
 	}
@@ -139,7 +157,6 @@ func (m *mockGitLab) GetProject(c *gitlab.Client, ctx context.Context, op gitlab
 		return proj, nil
 	}
 
-	acctID := m.getAcctID(c)
 	for _, accessibleProjID := range append(m.privateGuest[acctID], m.privateRepo[acctID]...) {
 		if accessibleProjID == op.ID {
 			return proj, nil
@@ -83,30 +83,24 @@ func (p *GitLabOAuthAuthzProvider) RepoPerms(ctx context.Context, account *extsv
 	remaining := repos
 	perms := make([]authz.RepoPerms, 0, len(remaining))
 
-	// Populate perms using cached repository visibility information.
 	for _, repo := range remaining {
 		projID, err := strconv.Atoi(repo.ExternalRepo.ID)
 		if err != nil {
 			return nil, errors.Wrap(err, "GitLab repo external ID did not parse to int")
 		}
 
-		if vis, exists := cacheGetRepoVisibility(p.cache, projID, p.cacheTTL); exists {
-			if v := vis.Visibility; v == gitlab.Public || (v == gitlab.Internal && accountID != "") {
-				perms = append(perms, authz.RepoPerms{Repo: repo, Perms: authz.Read})
-				continue
-			}
-		}
-
-		// Populate perms using cached user-can-access-repository information.
+		// Populate perms using cached user-can-access-repository information for authenticated
+		// users (who may also be able to push to public and internal repositories, so visibility
+		// alone does not determine their permissions), and using cached repository visibility
+		// information for unauthenticated users.
 		if accountID != "" {
 			if userRepo, exists := cacheGetUserRepo(p.cache, accountID, projID, p.cacheTTL); exists {
-				rp := authz.RepoPerms{Repo: repo}
-				if userRepo.Read {
-					rp.Perms = authz.Read
-				}
-				perms = append(perms, rp)
+				perms = append(perms, authz.RepoPerms{Repo: repo, Perms: userRepo.perms()})
 				continue
 			}
+		} else if vis, exists := cacheGetRepoVisibility(p.cache, projID, p.cacheTTL); exists && vis.Visibility == gitlab.Public {
+			perms = append(perms, authz.RepoPerms{Repo: repo, Perms: authz.Read})
+			continue
 		}
 
 		// Populate perms for the remaining repos (nextRemaining) by fetching directly from the GitLab
@@ -120,14 +114,20 @@ func (p *GitLabOAuthAuthzProvider) RepoPerms(ctx context.Context, account *extsv
 			oauthToken = tok.AccessToken
 		}
 
-		isAccessible, vis, isContentAccessible, err := p.fetchProjVis(ctx, oauthToken, projID)
+		isAccessible, vis, isContentAccessible, canWrite, err := p.fetchProjVis(ctx, oauthToken, projID)
 		if err != nil {
 			log15.Error("Failed to fetch visibility for GitLab project", "projectID", projID, "gitlabHost", p.codeHost.BaseURL.String(), "error", err)
 			continue
 		}
 		if isAccessible {
+			userRepo := userRepoCacheVal{Read: isContentAccessible, Write: isContentAccessible && canWrite, TTL: p.cacheTTL}
+
 			// Set perms
-			perms = append(perms, authz.RepoPerms{Repo: repo, Perms: authz.Read})
+			rp := authz.RepoPerms{Repo: repo, Perms: authz.Read}
+			if accountID != "" {
+				rp.Perms = userRepo.perms()
+			}
+			perms = append(perms, rp)
 
 			// Update visibility cache
 			err := cacheSetRepoVisibility(p.cache, projID, repoVisibilityCacheVal{Visibility: vis, TTL: p.cacheTTL})
@@ -135,9 +135,9 @@ func (p *GitLabOAuthAuthzProvider) RepoPerms(ctx context.Context, account *extsv
 				return nil, errors.Wrap(err, "could not set cached repo visibility")
 			}
 
-			// Update userRepo cache if the visibility is private
-			if vis == gitlab.Private {
-				err := cacheSetUserRepo(p.cache, accountID, projID, userRepoCacheVal{Read: isContentAccessible, TTL: p.cacheTTL})
+			// Update userRepo cache if the user is authenticated
+			if accountID != "" {
+				err := cacheSetUserRepo(p.cache, accountID, projID, userRepo)
 				if err != nil {
 					return nil, errors.Wrap(err, "could not set cached user repo")
 				}
@@ -161,11 +161,12 @@ func (p *GitLabOAuthAuthzProvider) RepoPerms(ctx context.Context, account *extsv
 // fetchProjVis fetches a repository's visibility with usr's credentials. It returns:
 // - whether the project is accessible to the user,
 // - the visibility if the repo is accessible (otherwise this is empty),
-// - whether the repository contents are accessible to usr, and
+// - whether the repository contents are accessible to usr,
+// - whether usr can push to the repository (has at least "Developer" access), and
 // - any error encountered in fetching (not including an error due to the repository not being visible);
 //   if the error is non-nil, all other return values should be disregraded
 func (p *GitLabOAuthAuthzProvider) fetchProjVis(ctx context.Context, oauthToken string, projID int) (
-	isAccessible bool, vis gitlab.Visibility, isContentAccessible bool, err error,
+	isAccessible bool, vis gitlab.Visibility, isContentAccessible bool, canWrite bool, err error,
 ) {
 	proj, err := p.clientProvider.GetOAuthClient(oauthToken).GetProject(ctx, gitlab.GetProjectOp{
 		ID:       projID,
@@ -173,18 +174,20 @@ func (p *GitLabOAuthAuthzProvider) fetchProjVis(ctx context.Context, oauthToken
 	})
 	if err != nil {
 		if errCode := gitlab.HTTPErrorCode(err); errCode == http.StatusNotFound {
-			return false, "", false, nil
+			return false, "", false, false, nil
 		}
-		return false, "", false, err
+		return false, "", false, false, err
 	}
 
 	// If we get here, the project is accessible to the user (user has at least "Guest" permissions
 	// on the project).
 
+	canWrite = proj.Permissions.AccessLevel() >= gitlab.DeveloperAccess
+
 	if proj.Visibility == gitlab.Public || proj.Visibility == gitlab.Internal {
 		// All authenticated users can read the contents of all internal/public projects
 		// (https://docs.gitlab.com/ee/user/permissions.html).
-		return true, proj.Visibility, true, nil
+		return true, proj.Visibility, true, canWrite, nil
 	}
 
 	// If project visibility is private and its accessible to user, we still need to check if the user
@@ -195,9 +198,9 @@ func (p *GitLabOAuthAuthzProvider) fetchProjVis(ctx context.Context, oauthToken
 		CommonOp: gitlab.CommonOp{NoCache: true},
 	}); err != nil {
 		if errCode := gitlab.HTTPErrorCode(err); errCode == http.StatusNotFound {
-			return true, proj.Visibility, false, nil
+			return true, proj.Visibility, false, false, nil
 		}
-		return false, "", false, err
+		return false, "", false, false, err
 	}
-	return true, proj.Visibility, true, nil
+	return true, proj.Visibility, true, canWrite, nil
 }
@@ -192,6 +192,94 @@ func Test_GitLab_RepoPerms(t *testing.T) {
 	}
 }
 
+// Test_GitLab_RepoPerms_write tests that users with "Developer" access to a project are granted
+// write permissions on the repository, regardless of the project's visibility.
+func Test_GitLab_RepoPerms_write(t *testing.T) {
+	gitlabMock := newMockGitLab(mockGitLabOp{
+		t: t,
+		publicProjs: []int{ // public projects
+			991,
+		},
+		internalProjs: []int{ // internal projects
+			981,
+		},
+		privateProjs: map[int][2][]int32{ // private projects
+			10: {
+				{}, // guests
+				{ // content ("full access")
+					1,
+					2,
+				},
+			},
+		},
+		developers: map[int][]int32{
+			10:  {1},
+			991: {2},
+		},
+		oauthToks: map[string]int32{
+			"oauth-u1": 1,
+			"oauth-u2": 2,
+		},
+	})
+	gitlab.MockGetProject = gitlabMock.GetProject
+	gitlab.MockListTree = gitlabMock.ListTree
+
+	repos := []*types.Repo{
+		repo("u1/repo1", gitlab.ServiceType, "https://gitlab.mine/", "10"),
+		repo("internal/repo1", gitlab.ServiceType, "https://gitlab.mine/", "981"),
+		repo("public/repo1", gitlab.ServiceType, "https://gitlab.mine/", "991"),
+	}
+
+	ctx := context.Background()
+	authzProvider := NewOAuthProvider(GitLabOAuthAuthzProviderOp{
+		BaseURL:   mustURL(t, "https://gitlab.mine"),
+		MockCache: make(mockCache),
+	})
+	calls := []struct {
+		description string
+		account     *extsvc.ExternalAccount
+		expPerms    []authz.RepoPerms
+	}{
+		{
+			description: "u1 can push to its private repo",
+			account:     acct(t, 1, "gitlab", "https://gitlab.mine/", "1", "oauth-u1"),
+			expPerms: []authz.RepoPerms{
+				{Repo: repos[0], Perms: authz.Read | authz.Write},
+				{Repo: repos[1], Perms: authz.Read},
+				{Repo: repos[2], Perms: authz.Read},
+			},
+		},
+		{
+			description: "u2 can push to the public repo",
+			account:     acct(t, 2, "gitlab", "https://gitlab.mine/", "2", "oauth-u2"),
+			expPerms: []authz.RepoPerms{
+				{Repo: repos[0], Perms: authz.Read},
+				{Repo: repos[1], Perms: authz.Read},
+				{Repo: repos[2], Perms: authz.Read | authz.Write},
+			},
+		},
+		{
+			description: "unauthenticated users can't push",
+			account:     nil,
+			expPerms: []authz.RepoPerms{
+				{Repo: repos[2], Perms: authz.Read},
+			},
+		},
+	}
+	for i := 0; i < 2; i++ { // run twice (once uncached, once cached)
+		for _, c := range calls {
+			t.Logf("Call %q (iter %d)", c.description, i)
+			perms, err := authzProvider.RepoPerms(ctx, c.account, repos)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(perms, c.expPerms) {
+				t.Errorf("expected %s, but got %s", asJSON(t, c.expPerms), asJSON(t, perms))
+			}
+		}
+	}
+}
+
 func Test_GitLab_RepoPerms_cache(t *testing.T) {
 	gitlabMock := newMockGitLab(mockGitLabOp{
 		t: t,
@@ -15,6 +15,8 @@ type ExternalServicesStore interface {
 	ListGitLabConnections(context.Context) ([]*schema.GitLabConnection, error)
 	ListGitHubConnections(context.Context) ([]*schema.GitHubConnection, error)
 	ListBitbucketServerConnections(context.Context) ([]*schema.BitbucketServerConnection, error)
+	ListGitoliteConnections(context.Context) ([]*schema.GitoliteConnection, error)
+	ListOtherExternalServicesConnections(context.Context) ([]*schema.OtherExternalServiceConnection, error)
 }
 
 // ProvidersFromConfig returns the set of permission-related providers derived from the site config.
@@ -67,5 +69,23 @@ func ProvidersFromConfig(
 		warnings = append(warnings, warns...)
 	}
 
+	if gitolites, err := s.ListGitoliteConnections(ctx); err != nil {
+		seriousProblems = append(seriousProblems, fmt.Sprintf("Could not load Gitolite external service configs: %s", err))
+	} else {
+		ps, problems, warns := gitoliteProviders(gitolites)
+		authzProviders = append(authzProviders, ps...)
+		seriousProblems = append(seriousProblems, problems...)
+		warnings = append(warnings, warns...)
+	}
+
+	if others, err := s.ListOtherExternalServicesConnections(ctx); err != nil {
+		seriousProblems = append(seriousProblems, fmt.Sprintf("Could not load other external service configs: %s", err))
+	} else {
+		ps, problems, warns := otherExternalServiceProviders(others)
+		authzProviders = append(authzProviders, ps...)
+		seriousProblems = append(seriousProblems, problems...)
+		warnings = append(warnings, warns...)
+	}
+
 	return allowAccessByDefault, authzProviders, seriousProblems, warnings
 }
@@ -0,0 +1,217 @@
+package authz
+
+import (
+	"context"
+	"time"
+
+	"github.com/RoaringBitmap/roaring"
+	multierror "github.com/hashicorp/go-multierror"
+	otlog "github.com/opentracing/opentracing-go/log"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+	"github.com/sourcegraph/sourcegraph/pkg/ratelimit"
+	"github.com/sourcegraph/sourcegraph/pkg/trace"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+const (
+	// permsSyncTick is how often the syncer looks for users whose permissions are due to be synced.
+	permsSyncTick = time.Minute
+
+	// permsSyncBatchSize is the maximum number of users whose permissions are synced per tick.
+	permsSyncBatchSize = 100
+
+	// permsSyncPageSize is the number of repositories passed to an authz provider at once.
+	permsSyncPageSize = 1000
+)
+
+// PermsSyncer syncs the repository permissions of users from all authz providers into the
+// user_repo_permissions table in the background, so that repository queries can enforce them with
+// a join instead of asking the authz providers on the request path.
+//
+// A user's permissions are synced when they sign in (see ScheduleUser) and then every
+// permissions.backgroundSync.userInterval minutes. The syncer does nothing unless
+// permissions.backgroundSync.enabled is set in the site configuration.
+type PermsSyncer struct {
+	// signIns receives the IDs of users whose permissions should be synced as soon as possible.
+	signIns chan int32
+	clock   func() time.Time
+}
+
+// NewPermsSyncer returns a new permissions syncer. Call Run to start it.
+func NewPermsSyncer() *PermsSyncer {
+	return &PermsSyncer{
+		signIns: make(chan int32, 1000),
+		clock:   func() time.Time { return time.Now().UTC().Truncate(time.Microsecond) },
+	}
+}
+
+// ScheduleUser requests that the permissions of the given user be synced as soon as possible. It
+// does not block. If too many users are waiting to be synced, the request is dropped, and the user's
+// permissions are synced on schedule instead.
+func (s *PermsSyncer) ScheduleUser(userID int32) {
+	select {
+	case s.signIns <- userID:
+	default:
+	}
+}
+
+// Run syncs permissions until ctx is canceled.
+func (s *PermsSyncer) Run(ctx context.Context) {
+	ticker := time.NewTicker(permsSyncTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case userID := <-s.signIns:
+			if conf.PermissionsBackgroundSyncEnabled() {
+				s.syncUserAndLog(ctx, userID)
+			}
+
+		case <-ticker.C:
+			if !conf.PermissionsBackgroundSyncEnabled() {
+				continue
+			}
+
+			syncedBefore := s.clock().Add(-conf.PermissionsUserSyncInterval())
+			userIDs, err := db.UserRepoPermissions.ListUsersToSync(ctx, syncedBefore, permsSyncBatchSize)
+			if err != nil {
+				log15.Error("Listing users whose repository permissions are due to be synced failed.", "error", err)
+				continue
+			}
+			for _, userID := range userIDs {
+				s.syncUserAndLog(ctx, userID)
+			}
+		}
+	}
+}
+
+func (s *PermsSyncer) syncUserAndLog(ctx context.Context, userID int32) {
+	if err := s.SyncUser(ctx, userID); err != nil {
+		log15.Error("Syncing repository permissions failed.", "user", userID, "error", err)
+	}
+}
+
+// SyncUser syncs the repository permissions of the given user from all authz providers.
+func (s *PermsSyncer) SyncUser(ctx context.Context, userID int32) (err error) {
+	tr, ctx := trace.New(ctx, "PermsSyncer.SyncUser", "")
+	defer func() {
+		tr.LogFields(otlog.Int32("user.id", userID))
+		tr.SetError(err)
+		tr.Finish()
+	}()
+
+	// 🚨 SECURITY: The syncer needs to see all repositories to ask the authz providers about them.
+	// Nothing it lists is returned to a user.
+	ctx = actor.WithActor(ctx, &actor.Actor{Internal: true})
+
+	// Requests made by authz providers to code hosts count against the permissions budget.
+	ctx = ratelimit.WithConsumer(ctx, ratelimit.ConsumerPermissions)
+
+	user, err := db.Users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.SiteAdmin {
+		return nil // site admins have access to all repositories
+	}
+
+	_, authzProviders := authz.GetProviders()
+	if len(authzProviders) == 0 {
+		return nil
+	}
+
+	accts, err := db.ExternalAccounts.List(ctx, db.ExternalAccountsListOptions{UserID: user.ID})
+	if err != nil {
+		return err
+	}
+
+	var errs *multierror.Error
+	for _, authzProvider := range authzProviders {
+		if err := s.syncUserProvider(ctx, user, accts, authzProvider); err != nil {
+			errs = multierror.Append(errs, errors.Wrap(err, authzProvider.ServiceID()))
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// syncUserProvider syncs the permissions of the given user on the repositories owned by the given
+// authz provider.
+func (s *PermsSyncer) syncUserProvider(ctx context.Context, user *types.User, accts []*extsvc.ExternalAccount, authzProvider authz.Provider) error {
+	// Providers that identify users by their Sourcegraph user don't need an external account.
+	userProvider, isUserProvider := authzProvider.(authz.UserProvider)
+
+	// Determine the external account to use, the same way authzFilter does.
+	var providerAcct *extsvc.ExternalAccount
+	for _, acct := range accts {
+		if acct.ServiceID == authzProvider.ServiceID() && acct.ServiceType == authzProvider.ServiceType() {
+			providerAcct = acct
+			break
+		}
+	}
+	if providerAcct == nil && !isUserProvider {
+		acct, err := authzProvider.FetchAccount(ctx, user, accts)
+		if err != nil {
+			return errors.Wrap(err, "fetching account")
+		}
+		if acct != nil {
+			if err := db.ExternalAccounts.AssociateUserAndSave(ctx, user.ID, acct.ExternalAccountSpec, acct.ExternalAccountData); err != nil {
+				return err
+			}
+			providerAcct = acct
+		}
+	}
+
+	ids := roaring.NewBitmap()
+	opt := db.ReposListOptions{
+		Enabled:     true,
+		ServiceID:   authzProvider.ServiceID(),
+		LimitOffset: &db.LimitOffset{Limit: permsSyncPageSize},
+	}
+	for {
+		repos, err := db.Repos.List(ctx, opt)
+		if err != nil {
+			return err
+		}
+		if len(repos) == 0 {
+			break
+		}
+
+		var perms []authz.RepoPerms
+		if isUserProvider {
+			perms, err = userProvider.UserRepoPerms(ctx, user, repos)
+		} else {
+			perms, err = authzProvider.RepoPerms(ctx, providerAcct, repos)
+		}
+		if err != nil {
+			return err
+		}
+		for _, p := range perms {
+			if p.Perms.Include(authz.Read) {
+				ids.Add(uint32(p.Repo.ID))
+			}
+		}
+
+		if len(repos) < opt.Limit {
+			break
+		}
+		opt.Offset += opt.Limit
+	}
+
+	return db.UserRepoPermissions.Upsert(ctx, &db.UserRepoPermissions{
+		UserID:      user.ID,
+		Perm:        authz.Read,
+		ServiceType: authzProvider.ServiceType(),
+		ServiceID:   authzProvider.ServiceID(),
+		RepoIDs:     ids,
+		UpdatedAt:   s.clock(),
+	})
+}
@@ -0,0 +1,152 @@
+package authz
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+)
+
+// permsProvider is an authz provider that grants access to the repositories with the given IDs to
+// the external account with the given account ID.
+type permsProvider struct {
+	codeHost  *extsvc.CodeHost
+	accountID string
+	repoIDs   map[api.RepoID]bool
+}
+
+func newPermsProvider(serviceURL, accountID string, repoIDs ...api.RepoID) *permsProvider {
+	u, _ := url.Parse(serviceURL)
+	p := &permsProvider{
+		codeHost:  extsvc.NewCodeHost(u, "fake"),
+		accountID: accountID,
+		repoIDs:   map[api.RepoID]bool{},
+	}
+	for _, id := range repoIDs {
+		p.repoIDs[id] = true
+	}
+	return p
+}
+
+func (p *permsProvider) RepoPerms(ctx context.Context, acct *extsvc.ExternalAccount, repos []*types.Repo) ([]authz.RepoPerms, error) {
+	if acct == nil || acct.AccountID != p.accountID {
+		return nil, nil
+	}
+	var perms []authz.RepoPerms
+	for _, r := range repos {
+		if p.repoIDs[r.ID] {
+			perms = append(perms, authz.RepoPerms{Repo: r, Perms: authz.Read})
+		}
+	}
+	return perms, nil
+}
+
+func (p *permsProvider) FetchAccount(ctx context.Context, user *types.User, current []*extsvc.ExternalAccount) (*extsvc.ExternalAccount, error) {
+	return &extsvc.ExternalAccount{
+		UserID: user.ID,
+		ExternalAccountSpec: extsvc.ExternalAccountSpec{
+			ServiceType: p.codeHost.ServiceType,
+			ServiceID:   p.codeHost.ServiceID,
+			AccountID:   p.accountID,
+		},
+	}, nil
+}
+
+func (p *permsProvider) ServiceType() string { return p.codeHost.ServiceType }
+func (p *permsProvider) ServiceID() string   { return p.codeHost.ServiceID }
+func (p *permsProvider) Validate() []string  { return nil }
+
+func TestPermsSyncer_SyncUser(t *testing.T) {
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	a := newPermsProvider("https://a.example.com/", "alice-a", 1, 3)
+	b := newPermsProvider("https://b.example.com/", "alice-b", 4, 5)
+	authz.SetProviders(true, []authz.Provider{a, b})
+	defer authz.SetProviders(true, nil)
+
+	repos := []*types.Repo{
+		{ID: 1, ExternalRepo: api.ExternalRepoSpec{ServiceID: a.ServiceID()}},
+		{ID: 2, ExternalRepo: api.ExternalRepoSpec{ServiceID: a.ServiceID()}},
+		{ID: 3, ExternalRepo: api.ExternalRepoSpec{ServiceID: a.ServiceID()}},
+		{ID: 4, ExternalRepo: api.ExternalRepoSpec{ServiceID: b.ServiceID()}},
+		{ID: 5, ExternalRepo: api.ExternalRepoSpec{ServiceID: b.ServiceID()}},
+		{ID: 6, ExternalRepo: api.ExternalRepoSpec{ServiceID: "https://other.example.com/"}},
+	}
+
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, Username: "alice"}, nil
+	}
+	db.Mocks.ExternalAccounts.List = func(opt db.ExternalAccountsListOptions) ([]*extsvc.ExternalAccount, error) {
+		acct, _ := a.FetchAccount(context.Background(), &types.User{ID: opt.UserID}, nil)
+		return []*extsvc.ExternalAccount{acct}, nil
+	}
+	var associated []string
+	db.Mocks.ExternalAccounts.AssociateUserAndSave = func(userID int32, spec extsvc.ExternalAccountSpec, data extsvc.ExternalAccountData) error {
+		associated = append(associated, spec.ServiceID)
+		return nil
+	}
+	db.Mocks.Repos.List = func(ctx context.Context, opt db.ReposListOptions) ([]*types.Repo, error) {
+		if !actor.FromContext(ctx).Internal {
+			t.Error("repositories must be listed as the internal actor")
+		}
+		if opt.Offset > 0 {
+			return nil, nil
+		}
+		var rs []*types.Repo
+		for _, r := range repos {
+			if r.ExternalRepo.ServiceID == opt.ServiceID {
+				rs = append(rs, r)
+			}
+		}
+		return rs, nil
+	}
+	stored := map[string][]uint32{}
+	db.Mocks.UserRepoPermissions.Upsert = func(ctx context.Context, p *db.UserRepoPermissions) error {
+		if p.UserID != 42 || p.Perm != authz.Read || p.UpdatedAt.IsZero() {
+			t.Errorf("unexpected permissions: %+v", p)
+		}
+		stored[p.ServiceID] = p.RepoIDs.ToArray()
+		return nil
+	}
+
+	s := NewPermsSyncer()
+	if err := s.SyncUser(context.Background(), 42); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{b.ServiceID()}; !reflect.DeepEqual(associated, want) {
+		t.Errorf("got associated accounts %v, want %v", associated, want)
+	}
+	want := map[string][]uint32{
+		a.ServiceID(): {1, 3},
+		b.ServiceID(): {4, 5},
+	}
+	if !reflect.DeepEqual(stored, want) {
+		t.Errorf("got stored permissions %v, want %v", stored, want)
+	}
+}
+
+func TestPermsSyncer_ScheduleUser(t *testing.T) {
+	s := NewPermsSyncer()
+	s.signIns = make(chan int32, 1)
+
+	s.ScheduleUser(1)
+	s.ScheduleUser(2) // dropped, must not block
+
+	select {
+	case id := <-s.signIns:
+		if id != 1 {
+			t.Errorf("got user %d, want 1", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("user was not scheduled")
+	}
+}
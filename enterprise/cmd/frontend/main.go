@@ -52,6 +52,10 @@ func main() {
 			}
 		}()
 		go licensing.StartMaxUserCount(&usersStore{})
+
+		permsSyncer := iauthz.NewPermsSyncer()
+		hooks.AfterUserSignIn = permsSyncer.ScheduleUser
+		go permsSyncer.Run(ctx)
 	}
 
 	debug, _ := strconv.ParseBool(os.Getenv("DEBUG"))
@@ -0,0 +1,12 @@
+BEGIN;
+
+DROP INDEX IF EXISTS repo_stars_idx;
+DROP INDEX IF EXISTS repo_topics_gin_idx;
+
+ALTER TABLE repo
+DROP COLUMN IF EXISTS private,
+DROP COLUMN IF EXISTS stars,
+DROP COLUMN IF EXISTS topics,
+DROP COLUMN IF EXISTS pushed_at;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+ALTER TABLE repo
+ADD COLUMN private boolean NOT NULL DEFAULT false,
+ADD COLUMN stars integer NOT NULL DEFAULT 0,
+ADD COLUMN topics text[] NOT NULL DEFAULT '{}',
+ADD COLUMN pushed_at timestamp with time zone;
+
+CREATE INDEX repo_stars_idx ON repo USING btree (stars);
+CREATE INDEX repo_topics_gin_idx ON repo USING gin (topics);
+
+COMMIT;
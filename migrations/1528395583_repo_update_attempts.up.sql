@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE repo_update_attempts (
+    id bigserial PRIMARY KEY,
+    repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    kind text NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    duration_ms integer NOT NULL,
+    error text,
+    output text NOT NULL DEFAULT '',
+    bytes_received bigint NOT NULL DEFAULT 0
+);
+CREATE INDEX repo_update_attempts_repo_id_idx ON repo_update_attempts(repo_id, id);
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE user_repo_permissions (
+  user_id      integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  permission   text NOT NULL,
+  service_type text NOT NULL,
+  service_id   text NOT NULL,
+  repo_ids     integer[] NOT NULL,
+  updated_at   timestamptz NOT NULL
+);
+
+ALTER TABLE user_repo_permissions
+ADD CONSTRAINT user_repo_permissions_unique
+UNIQUE (user_id, permission, service_type, service_id);
+
+CREATE INDEX user_repo_permissions_updated_at_idx ON user_repo_permissions USING btree (updated_at);
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+CREATE TABLE explicit_repo_permissions (
+    id serial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    repo_id integer REFERENCES repo(id) ON DELETE CASCADE,
+    repo_pattern text,
+    permission text NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT explicit_repo_permissions_target_check CHECK ((repo_id IS NULL) <> (repo_pattern IS NULL))
+);
+
+CREATE UNIQUE INDEX explicit_repo_permissions_repo_unique ON explicit_repo_permissions(user_id, repo_id, permission) WHERE repo_id IS NOT NULL;
+CREATE UNIQUE INDEX explicit_repo_permissions_pattern_unique ON explicit_repo_permissions(user_id, repo_pattern, permission) WHERE repo_pattern IS NOT NULL;
+CREATE INDEX explicit_repo_permissions_repo_id_idx ON explicit_repo_permissions(repo_id);
+
+COMMIT;
@@ -0,0 +1,29 @@
+BEGIN;
+
+CREATE TABLE audit_log (
+    id bigserial PRIMARY KEY,
+    actor_user_id integer, -- not a foreign key, so that entries outlive the user
+    actor_username text NOT NULL DEFAULT '',
+    remote_addr text NOT NULL DEFAULT '',
+    forwarded_for text NOT NULL DEFAULT '',
+    action text NOT NULL,
+    target_kind text NOT NULL,
+    target_id text NOT NULL,
+    target_name text NOT NULL DEFAULT '',
+    changes jsonb NOT NULL DEFAULT '[]',
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX audit_log_created_at ON audit_log(created_at);
+CREATE INDEX audit_log_actor_user_id ON audit_log(actor_user_id);
+CREATE INDEX audit_log_target ON audit_log(target_kind, target_id);
+
+-- The audit log is append-only.
+CREATE FUNCTION audit_log_reject_modification() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE PROCEDURE audit_log_reject_modification();
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE user_totp (
+    user_id integer PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret text NOT NULL,
+    enabled_at timestamp with time zone, -- NULL while enrollment is pending
+    last_used_counter bigint NOT NULL DEFAULT 0, -- to prevent reuse of codes
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE TABLE user_mfa_recovery_codes (
+    id serial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash text NOT NULL,
+    used_at timestamp with time zone,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX user_mfa_recovery_codes_user_id ON user_mfa_recovery_codes(user_id);
+
+COMMIT;
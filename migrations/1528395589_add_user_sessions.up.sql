@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE user_sessions (
+    id bigserial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    last_active_at timestamp with time zone NOT NULL DEFAULT now(),
+    expires_at timestamp with time zone NOT NULL,
+    remote_addr text NOT NULL DEFAULT '',
+    user_agent text NOT NULL DEFAULT '',
+    auth_provider text NOT NULL DEFAULT ''
+);
+CREATE INDEX user_sessions_user_id ON user_sessions(user_id);
+
+COMMIT;
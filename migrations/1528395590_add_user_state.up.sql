@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE users ADD COLUMN state text NOT NULL DEFAULT 'active';
+ALTER TABLE users ADD CONSTRAINT users_state_valid CHECK (state IN ('active', 'suspended', 'pending_approval'));
+CREATE INDEX users_state ON users(state) WHERE state <> 'active';
+
+COMMIT;
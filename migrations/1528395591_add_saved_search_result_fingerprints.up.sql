@@ -0,0 +1,9 @@
+BEGIN;
+
+CREATE TABLE saved_search_result_fingerprints (
+    saved_search_id integer PRIMARY KEY REFERENCES saved_searches(id) ON DELETE CASCADE,
+    fingerprints text[] NOT NULL,
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS saved_search_webhook_deliveries;
+DROP TABLE IF EXISTS saved_search_webhooks;
+
+COMMIT;
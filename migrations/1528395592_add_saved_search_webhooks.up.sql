@@ -0,0 +1,30 @@
+BEGIN;
+
+CREATE TABLE saved_search_webhooks (
+    id serial PRIMARY KEY,
+    saved_search_id integer NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    url text NOT NULL,
+    secret text NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX saved_search_webhooks_saved_search_id ON saved_search_webhooks(saved_search_id);
+
+CREATE TABLE saved_search_webhook_deliveries (
+    id serial PRIMARY KEY,
+    webhook_id integer NOT NULL REFERENCES saved_search_webhooks(id) ON DELETE CASCADE,
+    event text NOT NULL,
+    payload text NOT NULL,
+    state text NOT NULL DEFAULT 'pending',
+    attempts integer NOT NULL DEFAULT 0,
+    response_status_code integer,
+    error text,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    last_attempt_at timestamp with time zone,
+    next_attempt_at timestamp with time zone,
+    CONSTRAINT saved_search_webhook_deliveries_state_valid CHECK (state IN ('pending', 'succeeded', 'failed'))
+);
+CREATE INDEX saved_search_webhook_deliveries_webhook_id ON saved_search_webhook_deliveries(webhook_id, created_at DESC);
+CREATE INDEX saved_search_webhook_deliveries_next_attempt_at ON saved_search_webhook_deliveries(next_attempt_at) WHERE state='pending';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS saved_search_runs;
+DROP TABLE IF EXISTS saved_search_subscriptions;
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN;
+
+CREATE TABLE saved_search_subscriptions (
+    id serial PRIMARY KEY,
+    saved_search_id integer NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel text NOT NULL,
+    slack_webhook_url text,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT saved_search_subscriptions_channel_valid CHECK (channel IN ('email', 'slack')),
+    CONSTRAINT saved_search_subscriptions_slack_webhook_url_required CHECK (channel <> 'slack' OR slack_webhook_url IS NOT NULL)
+);
+CREATE UNIQUE INDEX saved_search_subscriptions_unique ON saved_search_subscriptions(saved_search_id, user_id, channel);
+CREATE INDEX saved_search_subscriptions_user_id ON saved_search_subscriptions(user_id);
+
+CREATE TABLE saved_search_runs (
+    id serial PRIMARY KEY,
+    saved_search_id integer NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    started_at timestamp with time zone NOT NULL,
+    duration_ms integer NOT NULL,
+    result_count integer NOT NULL DEFAULT 0,
+    new_result_count integer NOT NULL DEFAULT 0,
+    error text
+);
+CREATE INDEX saved_search_runs_saved_search_id ON saved_search_runs(saved_search_id, started_at DESC);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+ALTER TABLE saved_searches DROP COLUMN IF EXISTS schedule;
+ALTER TABLE saved_searches DROP COLUMN IF EXISTS timeout_seconds;
+ALTER TABLE saved_searches DROP COLUMN IF EXISTS next_run_at;
+ALTER TABLE saved_searches DROP COLUMN IF EXISTS last_run_at;
+ALTER TABLE saved_searches DROP COLUMN IF EXISTS last_run_duration_ms;
+ALTER TABLE saved_searches DROP COLUMN IF EXISTS last_run_error;
+ALTER TABLE saved_searches DROP COLUMN IF EXISTS consecutive_failures;
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+ALTER TABLE saved_searches ADD COLUMN schedule text;
+ALTER TABLE saved_searches ADD COLUMN timeout_seconds integer;
+ALTER TABLE saved_searches ADD CONSTRAINT saved_searches_timeout_seconds_positive CHECK (timeout_seconds > 0);
+
+-- The query runner's scheduling state and the stats of the most recent run.
+ALTER TABLE saved_searches ADD COLUMN next_run_at timestamp with time zone;
+ALTER TABLE saved_searches ADD COLUMN last_run_at timestamp with time zone;
+ALTER TABLE saved_searches ADD COLUMN last_run_duration_ms integer;
+ALTER TABLE saved_searches ADD COLUMN last_run_error text;
+ALTER TABLE saved_searches ADD COLUMN consecutive_failures integer NOT NULL DEFAULT 0;
+
+COMMIT;
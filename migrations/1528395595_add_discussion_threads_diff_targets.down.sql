@@ -0,0 +1,12 @@
+BEGIN;
+
+DROP INDEX IF EXISTS discussion_threads_target_repo_diff_idx;
+ALTER TABLE discussion_threads_target_repo DROP COLUMN IF EXISTS diff_base_revision;
+ALTER TABLE discussion_threads_target_repo DROP COLUMN IF EXISTS diff_head_revision;
+ALTER TABLE discussion_threads_target_repo DROP COLUMN IF EXISTS old_path;
+ALTER TABLE discussion_threads_target_repo DROP COLUMN IF EXISTS old_start_line;
+ALTER TABLE discussion_threads_target_repo DROP COLUMN IF EXISTS old_end_line;
+ALTER TABLE discussion_threads_target_repo DROP COLUMN IF EXISTS new_start_line;
+ALTER TABLE discussion_threads_target_repo DROP COLUMN IF EXISTS new_end_line;
+
+COMMIT;
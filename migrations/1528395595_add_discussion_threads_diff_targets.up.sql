@@ -0,0 +1,18 @@
+BEGIN;
+
+-- Threads on the diff of a commit (only diff_head_revision is set) or on the
+-- diff of a comparison between two revisions (both are set).
+ALTER TABLE discussion_threads_target_repo ADD COLUMN diff_base_revision text;
+ALTER TABLE discussion_threads_target_repo ADD COLUMN diff_head_revision text;
+ALTER TABLE discussion_threads_target_repo ADD CONSTRAINT discussion_threads_target_repo_diff_base_requires_head CHECK (diff_base_revision IS NULL OR diff_head_revision IS NOT NULL);
+
+-- The selected lines of a file diff, in the old and new file.
+ALTER TABLE discussion_threads_target_repo ADD COLUMN old_path text;
+ALTER TABLE discussion_threads_target_repo ADD COLUMN old_start_line integer;
+ALTER TABLE discussion_threads_target_repo ADD COLUMN old_end_line integer;
+ALTER TABLE discussion_threads_target_repo ADD COLUMN new_start_line integer;
+ALTER TABLE discussion_threads_target_repo ADD COLUMN new_end_line integer;
+
+CREATE INDEX discussion_threads_target_repo_diff_idx ON discussion_threads_target_repo(repo_id, diff_head_revision, diff_base_revision) WHERE diff_head_revision IS NOT NULL;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS user_notification_settings;
+DROP TABLE IF EXISTS notifications;
+
+COMMIT;
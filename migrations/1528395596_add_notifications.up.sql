@@ -0,0 +1,27 @@
+BEGIN;
+
+CREATE TABLE notifications (
+    id bigserial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind text NOT NULL,
+    actor_user_id integer REFERENCES users(id) ON DELETE SET NULL,
+    thread_id bigint REFERENCES discussion_threads(id) ON DELETE CASCADE,
+    comment_id bigint REFERENCES discussion_comments(id) ON DELETE CASCADE,
+    title text NOT NULL,
+    body text NOT NULL DEFAULT '',
+    url text NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    read_at timestamp with time zone
+);
+CREATE INDEX notifications_user_id ON notifications(user_id, id);
+CREATE INDEX notifications_user_id_unread ON notifications(user_id, id) WHERE read_at IS NULL;
+
+CREATE TABLE user_notification_settings (
+    user_id integer PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    in_app boolean NOT NULL DEFAULT true,
+    email boolean NOT NULL DEFAULT true,
+    slack_webhook_url text,
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+COMMIT;
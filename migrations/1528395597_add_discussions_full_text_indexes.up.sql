@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Full-text indexes for type:discussion searches. Queries must use the same
+-- expressions for the indexes to be used (see discussionsFullTextMatch).
+CREATE INDEX discussion_threads_title_fts_idx ON discussion_threads USING gin(to_tsvector('english', title));
+CREATE INDEX discussion_comments_contents_fts_idx ON discussion_comments USING gin(to_tsvector('english', contents));
+
+COMMIT;
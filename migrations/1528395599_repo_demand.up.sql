@@ -0,0 +1,11 @@
+BEGIN;
+
+CREATE TABLE repo_demand (
+    repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    kind text NOT NULL,
+    first_at timestamp with time zone NOT NULL,
+    last_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (repo_id, kind)
+);
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+CREATE TABLE user_mfa_failed_attempts (
+    user_id integer PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    count integer NOT NULL, -- consecutive incorrect codes since the last successful sign-in
+    locked_until timestamp with time zone, -- no codes are accepted before this time
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+COMMIT;
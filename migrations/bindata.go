@@ -225,6 +225,44 @@
 // 1528395580_create_user_permissions_table.up.sql (331B)
 // 1528395581_allows_dots_in_usernames.down.sql (349B)
 // 1528395581_allows_dots_in_usernames.up.sql (355B)
+// 1528395582_repo_normalized_metadata.down.sql (237B)
+// 1528395582_repo_normalized_metadata.up.sql (344B)
+// 1528395583_repo_update_attempts.down.sql (60B)
+// 1528395583_repo_update_attempts.up.sql (444B)
+// 1528395584_create_user_repo_permissions.down.sql (61B)
+// 1528395584_create_user_repo_permissions.up.sql (527B)
+// 1528395585_create_explicit_repo_permissions.down.sql (65B)
+// 1528395585_create_explicit_repo_permissions.up.sql (831B)
+// 1528395586_add_access_token_expiry.down.sql (77B)
+// 1528395586_add_access_token_expiry.up.sql (91B)
+// 1528395587_add_audit_log.down.sql (106B)
+// 1528395587_add_audit_log.up.sql (1.04kB)
+// 1528395588_add_user_mfa.down.sql (95B)
+// 1528395588_add_user_mfa.up.sql (712B)
+// 1528395589_add_user_sessions.down.sql (53B)
+// 1528395589_add_user_sessions.up.sql (518B)
+// 1528395590_add_user_state.down.sql (64B)
+// 1528395590_add_user_state.up.sql (263B)
+// 1528395591_add_saved_search_result_fingerprints.down.sql (72B)
+// 1528395591_add_saved_search_result_fingerprints.up.sql (254B)
+// 1528395592_add_saved_search_webhooks.down.sql (115B)
+// 1528395592_add_saved_search_webhooks.up.sql (1.316kB)
+// 1528395593_add_saved_search_subscriptions_and_runs.down.sql (106B)
+// 1528395593_add_saved_search_subscriptions_and_runs.up.sql (1.241kB)
+// 1528395594_add_saved_search_schedules.down.sql (473B)
+// 1528395594_add_saved_search_schedules.up.sql (688B)
+// 1528395595_add_discussion_threads_diff_targets.down.sql (644B)
+// 1528395595_add_discussion_threads_diff_targets.up.sql (1.114kB)
+// 1528395596_add_notifications.down.sql (102B)
+// 1528395596_add_notifications.up.sql (1.026kB)
+// 1528395597_add_discussions_full_text_indexes.down.sql (131B)
+// 1528395597_add_discussions_full_text_indexes.up.sql (396B)
+// 1528395598_add_discussion_thread_resolution_assignee_reactions.down.sql (342B)
+// 1528395598_add_discussion_thread_resolution_assignee_reactions.up.sql (746B)
+// 1528395599_repo_demand.down.sql (51B)
+// 1528395599_repo_demand.up.sql (266B)
+// 1528395600_add_user_mfa_failed_attempts.down.sql (64B)
+// 1528395600_add_user_mfa_failed_attempts.up.sql (373B)
 
 package migrations
 
@@ -4793,6 +4831,766 @@ func _1528395581_allows_dots_in_usernamesUpSql() (*asset, error) {
 	return a, nil
 }
 
+var __1528395582_repo_normalized_metadataDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\xf0\xf4\x73\x71\x8d\x50\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x4a\x2d\xc8\x8f\x2f\x2e\x49\x2c\x2a\x8e\xcf\x4c\xa9\xb0\xc6\xa3\xa8\x24\xbf\x20\x33\xb9\x38\x3e\x3d\x33\x0f\xa2\x92\xcb\xd1\x27\xc4\x35\x48\x21\xc4\xd1\xc9\xc7\x15\x6c\x0c\x44\xaf\xb3\xbf\x4f\xa8\xaf\x1f\x92\xe6\x82\xa2\xcc\xb2\xc4\x92\x54\x1d\x1c\xd2\x60\xbb\x71\x49\x42\xec\xc4\x25\x5b\x50\x5a\x9c\x91\x9a\x12\x9f\x58\x62\xcd\xc5\xe5\xec\xef\xeb\xeb\x19\x62\xcd\x05\x18\x00\x49\xf9\x56\xfa\xed\x00\x00\x00")
+
+func _1528395582_repo_normalized_metadataDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395582_repo_normalized_metadataDownSql,
+		"1528395582_repo_normalized_metadata.down.sql",
+	)
+}
+
+func _1528395582_repo_normalized_metadataDownSql() (*asset, error) {
+	bytes, err := _1528395582_repo_normalized_metadataDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395582_repo_normalized_metadata.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xdd, 0x91, 0xed, 0x2e, 0xb6, 0x26, 0x9b, 0x7d, 0x17, 0x0, 0x8a, 0x22, 0xae, 0x8, 0x8, 0x2a, 0xf4, 0xcf, 0x7a, 0x62, 0x4a, 0x16, 0x65, 0xde, 0xea, 0xf3, 0x93, 0x58, 0xe2, 0x18, 0xf7, 0xa3}}
+	return a, nil
+}
+
+var __1528395582_repo_normalized_metadataUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x8f\xc1\x6a\xf3\x30\x10\x06\xef\x7a\x8a\xef\x96\x04\xfe\xc3\x7f\xf7\xc9\xb1\xd5\x60\x90\x65\x48\x65\x28\x94\x62\x94\x66\xeb\x08\x12\x49\x48\xdb\x36\xb4\xf4\xdd\x0b\xca\xa5\x21\x39\xee\xee\x0c\xcc\xae\xe5\xa6\xd3\x95\x10\xb5\x32\x72\x0b\x53\xaf\x95\x44\xa2\x18\x44\xdd\xb6\x68\x06\x35\xf6\x1a\x31\xb9\x0f\xcb\x84\x5d\x08\x47\xb2\x1e\x7a\x30\xd0\xa3\x52\x68\xe5\x43\x3d\x2a\x83\x37\x7b\xcc\xf4\xef\xaf\x92\xd9\xa6\x0c\xe7\x99\x66\x4a\xb7\xc2\xff\x2b\x98\x43\x74\xaf\x19\x4c\x67\x7e\x7e\xb9\x85\x17\xdf\x3f\x8b\x2b\x3e\xbe\xe7\x03\xed\x27\xcb\x60\x77\xa2\xcc\xf6\x14\xf1\xe9\xf8\x50\x46\x7c\x05\x4f\x95\x10\xcd\x56\xd6\x46\xa2\xd3\xad\x7c\x2a\x1f\x4d\xa5\x69\x72\xfb\x33\x06\x5d\x36\x18\x1f\x3b\xbd\xc1\x8e\x13\x11\x96\xe5\xbc\xaa\xee\x88\x97\xbe\x69\x76\xfe\x8e\x3d\x3b\x8f\xe5\x85\x58\x55\x42\x34\x43\xdf\x77\xa6\x12\xbf\x03\x00\x55\x64\xf9\x61\x58\x01\x00\x00")
+
+func _1528395582_repo_normalized_metadataUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395582_repo_normalized_metadataUpSql,
+		"1528395582_repo_normalized_metadata.up.sql",
+	)
+}
+
+func _1528395582_repo_normalized_metadataUpSql() (*asset, error) {
+	bytes, err := _1528395582_repo_normalized_metadataUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395582_repo_normalized_metadata.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x65, 0x7c, 0x3d, 0x51, 0x29, 0xe5, 0xf1, 0xee, 0xfb, 0xfe, 0xa3, 0x7, 0xe8, 0xbb, 0x4d, 0xc9, 0x19, 0xae, 0xc9, 0x9b, 0x8e, 0xef, 0xae, 0xb8, 0xe1, 0x40, 0x55, 0xa6, 0x36, 0xb1, 0xd9, 0xe6}}
+	return a, nil
+}
+
+var __1528395583_repo_update_attemptsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x3c\x00\xc3\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x70\x6f\x5f\x75\x70\x64\x61\x74\x65\x5f\x61\x74\x74\x65\x6d\x70\x74\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xb2\x61\x50\x11\x3c\x00\x00\x00")
+
+func _1528395583_repo_update_attemptsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395583_repo_update_attemptsDownSql,
+		"1528395583_repo_update_attempts.down.sql",
+	)
+}
+
+func _1528395583_repo_update_attemptsDownSql() (*asset, error) {
+	bytes, err := _1528395583_repo_update_attemptsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395583_repo_update_attempts.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa4, 0xca, 0x7d, 0x39, 0x64, 0xc9, 0x5a, 0x84, 0xdf, 0x90, 0xd5, 0xda, 0x9a, 0xf7, 0xab, 0x93, 0x3f, 0x6a, 0x62, 0x9a, 0x6e, 0xc3, 0x3, 0x96, 0x1d, 0x8a, 0x4a, 0xe, 0xbb, 0xca, 0x2, 0x2c}}
+	return a, nil
+}
+
+var __1528395583_repo_update_attemptsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\x90\x4f\x4f\xc2\x40\x10\xc5\xef\xfb\x29\xde\x0d\x48\x38\x78\xef\xa9\xb4\x83\x69\x2c\xc5\x94\x92\xc8\x69\xb3\xb8\x13\x9c\x68\xff\x64\x3b\x55\xf4\xd3\x1b\x5b\x34\x51\x39\xce\xee\xef\xbd\x99\xfc\x56\x74\x9b\x15\x91\x31\x49\x49\x71\x45\xa8\xe2\x55\x4e\x08\xdc\xb5\x76\xe8\xbc\x53\xb6\x4e\x95\xeb\x4e\x7b\xcc\x0d\x00\x88\xc7\x51\x4e\x3d\x07\x71\x2f\xb8\x2f\xb3\x4d\x5c\x1e\x70\x47\x87\xe5\xf8\x3b\x06\xc5\x43\x1a\xe5\x13\x07\x14\xdb\x0a\xc5\x3e\xcf\x51\xd2\x9a\x4a\x2a\x12\xda\x8d\xe5\x73\xf1\x0b\x6c\x0b\xa4\x94\x53\x45\x48\xe2\x5d\x12\xa7\x34\x75\x3c\x4b\xe3\xa1\x7c\xd6\x9f\xf4\xf4\xde\xab\x0b\xca\xde\x3a\x85\x4a\xcd\xbd\xba\xba\xc3\x9b\xe8\xd3\x38\xe2\xa3\x6d\xf8\x4f\xc2\x0f\xc1\xa9\xb4\x8d\xad\xfb\x7f\x17\x4d\x9d\x1c\x42\x1b\xc6\x65\xd3\xdc\x0e\xda\x0d\xfa\x7b\x3b\x52\x5a\xc7\xfb\xbc\xc2\x6c\x36\x41\xc7\x77\xe5\xde\x06\x7e\x64\x79\xe5\x51\x87\x34\x57\xf0\x1b\xb3\x88\xbe\xb5\x66\x45\x4a\x0f\x57\xb5\xda\x8b\x32\x2b\xfe\xfc\x65\xe4\x1a\x33\xbf\x30\x4b\x88\x5f\x44\xc6\x24\xdb\xcd\x26\xab\x22\xf3\x39\x00\x1e\xe3\x6b\xf2\xbc\x01\x00\x00")
+
+func _1528395583_repo_update_attemptsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395583_repo_update_attemptsUpSql,
+		"1528395583_repo_update_attempts.up.sql",
+	)
+}
+
+func _1528395583_repo_update_attemptsUpSql() (*asset, error) {
+	bytes, err := _1528395583_repo_update_attemptsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395583_repo_update_attempts.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x15, 0xbc, 0x9, 0x19, 0xbd, 0x20, 0xb2, 0x1b, 0x18, 0x49, 0x54, 0x73, 0x3e, 0x52, 0x1, 0xcd, 0xd8, 0xe2, 0xb5, 0x9d, 0xa, 0xe0, 0x97, 0xa7, 0xa1, 0x81, 0x1d, 0x55, 0x1a, 0xde, 0xed, 0xf5}}
+	return a, nil
+}
+
+var __1528395584_create_user_repo_permissionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x3d\x00\xc2\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x75\x73\x65\x72\x5f\x72\x65\x70\x6f\x5f\x70\x65\x72\x6d\x69\x73\x73\x69\x6f\x6e\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xf3\x29\x91\x47\x3d\x00\x00\x00")
+
+func _1528395584_create_user_repo_permissionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395584_create_user_repo_permissionsDownSql,
+		"1528395584_create_user_repo_permissions.down.sql",
+	)
+}
+
+func _1528395584_create_user_repo_permissionsDownSql() (*asset, error) {
+	bytes, err := _1528395584_create_user_repo_permissionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395584_create_user_repo_permissions.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3f, 0x44, 0x5, 0x21, 0x91, 0x40, 0xbc, 0x50, 0xb1, 0xdc, 0x84, 0x30, 0x6d, 0x1, 0xc8, 0xb7, 0x6d, 0xfe, 0xda, 0x65, 0x9b, 0x94, 0xd4, 0xb5, 0x42, 0xb0, 0x5d, 0x40, 0x70, 0xf0, 0xd7, 0xa2}}
+	return a, nil
+}
+
+var __1528395584_create_user_repo_permissionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x91\xc1\x6a\x84\x30\x10\x86\xef\x79\x8a\x39\xba\xb0\x6f\xe0\x29\xab\xd3\x25\xe0\x46\xaa\x11\x0a\xa5\x04\xdb\x0c\x65\x0e\xba\x36\x89\x65\xdb\xa7\x2f\xda\xa2\xbb\xe0\x36\xb7\xf0\x7f\xf3\xcf\xcc\x3f\x07\x3c\x2a\x9d\x0a\x91\x55\x28\x0d\x82\x91\x87\x02\x61\x0c\xe4\xad\xa7\xe1\x6c\x07\xf2\x1d\x87\xc0\xe7\x3e\x40\x22\xe0\x57\x61\x07\xf3\xe3\x3e\xd2\x3b\x79\xd0\xa5\x01\xdd\x14\x05\x54\xf8\x80\x15\xea\x0c\xeb\x19\x0c\x09\xbb\x1d\x94\x1a\x72\x2c\xd0\x20\x64\xb2\xce\x64\x8e\x7b\x01\xb0\xfa\x02\x40\xa4\x4b\x5c\x4c\x26\x35\x90\xff\xe4\x37\xb2\xf1\x6b\xa0\xfb\x2a\xbb\xad\xda\x79\x6c\x76\xe1\x7a\xc2\xe7\x97\x1b\x64\x1c\x5c\x1b\xc9\xd9\x36\x4e\x06\xdc\x51\x88\x6d\x37\xc4\xef\x05\x12\xbb\x54\x08\x59\x18\xac\xfe\x0b\x44\xc8\x3c\x87\xac\xd4\xb5\xa9\xa4\xd2\x66\x1b\xb2\x63\xcf\x1f\x23\x89\x46\xab\xc7\x06\x21\xf9\x0b\x70\x7f\x95\xc0\xfe\x66\xdf\xf5\xc7\x6e\xb7\x1e\x46\xe9\x1c\x9f\xee\xb5\x58\xf6\xb1\xec\x2e\x53\xe0\x9b\x1c\x34\xb5\xd2\x47\x78\x8d\x9e\x08\x92\xb5\x68\xee\x52\x9e\x4e\xca\xa4\xe2\x67\x00\x58\x5c\xd4\xf4\x0f\x02\x00\x00")
+
+func _1528395584_create_user_repo_permissionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395584_create_user_repo_permissionsUpSql,
+		"1528395584_create_user_repo_permissions.up.sql",
+	)
+}
+
+func _1528395584_create_user_repo_permissionsUpSql() (*asset, error) {
+	bytes, err := _1528395584_create_user_repo_permissionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395584_create_user_repo_permissions.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x89, 0xf, 0x7b, 0x5d, 0x7b, 0x97, 0x8c, 0xe1, 0x35, 0xa5, 0x59, 0xff, 0xc2, 0x78, 0x87, 0xdb, 0x6b, 0xa2, 0xce, 0x0, 0x82, 0x6e, 0xb8, 0x45, 0x87, 0xc7, 0x60, 0xb7, 0x20, 0x36, 0xe7, 0xd1}}
+	return a, nil
+}
+
+var __1528395585_create_explicit_repo_permissionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x41\x00\xbe\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x65\x78\x70\x6c\x69\x63\x69\x74\x5f\x72\x65\x70\x6f\x5f\x70\x65\x72\x6d\x69\x73\x73\x69\x6f\x6e\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x3f\x46\x96\x67\x41\x00\x00\x00")
+
+func _1528395585_create_explicit_repo_permissionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395585_create_explicit_repo_permissionsDownSql,
+		"1528395585_create_explicit_repo_permissions.down.sql",
+	)
+}
+
+func _1528395585_create_explicit_repo_permissionsDownSql() (*asset, error) {
+	bytes, err := _1528395585_create_explicit_repo_permissionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395585_create_explicit_repo_permissions.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xea, 0x8e, 0x80, 0xdb, 0x37, 0x15, 0x3b, 0x42, 0xa, 0xab, 0x69, 0x20, 0x52, 0x73, 0x9, 0xec, 0x13, 0xfd, 0x7b, 0x65, 0x75, 0xa, 0x28, 0x39, 0x78, 0x56, 0x9d, 0x45, 0xdf, 0xf8, 0x98, 0xac}}
+	return a, nil
+}
+
+var __1528395585_create_explicit_repo_permissionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x51\xdb\x6e\xb2\x40\x10\xbe\xe7\x29\xe6\x12\x12\xdf\xc0\x3f\x7f\x82\x30\xd6\x8d\xb8\xb4\xb0\xa4\xf5\x8a\x10\x98\xe8\xa4\x0a\x74\x59\xa3\xe9\xd3\x37\x2e\x78\x68\x52\xed\xe1\x72\xe7\x9b\x6f\xf6\x3b\x4c\xf0\x41\xc8\xb1\xe3\x04\x09\xfa\x0a\x41\xf9\x93\x08\x81\x0e\xed\x86\x4b\x36\xb9\xa6\xb6\xc9\x5b\xd2\x5b\xee\x3a\x6e\xea\x0e\x5c\x07\x00\x80\x2b\xe8\x48\x73\xb1\x81\xc7\x44\x2c\xfc\x64\x09\x73\x5c\x8e\x2c\xb4\xeb\x48\xe7\x5c\x01\xd7\x86\x56\xa4\x41\xc6\x0a\x64\x16\x45\x90\xe0\x14\x13\x94\x01\xa6\x76\xa7\x73\xb9\xf2\x20\x96\x10\x62\x84\x0a\x21\xf0\xd3\xc0\x0f\xb1\x3f\x62\xbf\xbd\x3a\x72\xc5\x3d\x42\xdf\x52\xdb\xc2\x18\xd2\x35\x18\x3a\x98\x7e\x7c\xf1\x60\x87\x67\x59\x3d\x5a\x6a\x2a\x0c\x55\x79\x61\xc0\xf0\x96\x3a\x53\x6c\x5b\xd8\xb3\x59\xdb\x27\xbc\x37\x35\x5d\x8c\x84\x38\xf5\xb3\x48\x41\xdd\xec\x5d\xaf\xe7\x07\xb1\x4c\x55\xe2\x0b\xa9\x6e\x47\x97\x9b\x42\xaf\xc8\xe4\xe5\x9a\xca\x57\x08\x66\x18\xcc\xc1\x75\x4f\x4e\x45\x6a\x8f\x7b\xf0\xef\x3f\xb8\x9f\x3c\x9c\x10\xcf\xf1\x2e\x35\x65\x52\x3c\x65\x08\x42\x86\xf8\x72\xe7\x4b\x3b\xd8\xd5\xfc\xb6\xa3\x63\x5e\x37\x17\xdd\xa1\xb5\xd1\x29\xf9\xd1\x55\x60\x1e\x3c\xcf\x30\xc1\x73\x29\x22\x3d\x67\x31\xfe\xa5\x9e\xc1\xd3\x1f\x24\x0d\xcc\x9b\xba\x06\xfc\x4b\x71\x3f\x4a\x89\xab\x9c\xab\xc3\x7d\x49\xc3\xa2\xed\x21\x5e\x2c\x84\x1a\x3b\x1f\x03\x00\x46\x00\x54\x7f\x3f\x03\x00\x00")
+
+func _1528395585_create_explicit_repo_permissionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395585_create_explicit_repo_permissionsUpSql,
+		"1528395585_create_explicit_repo_permissions.up.sql",
+	)
+}
+
+func _1528395585_create_explicit_repo_permissionsUpSql() (*asset, error) {
+	bytes, err := _1528395585_create_explicit_repo_permissionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395585_create_explicit_repo_permissions.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x52, 0xd6, 0xa9, 0xd7, 0x9e, 0xc9, 0xb9, 0x5a, 0x10, 0x55, 0x86, 0x99, 0x28, 0x11, 0xcc, 0xd8, 0x4a, 0xca, 0x56, 0xbe, 0x62, 0xaf, 0x92, 0xf9, 0xfa, 0x90, 0x88, 0x67, 0x12, 0x5e, 0x2e, 0x16}}
+	return a, nil
+}
+
+var __1528395586_add_access_token_expiryDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x4d\x00\xb2\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x61\x63\x63\x65\x73\x73\x5f\x74\x6f\x6b\x65\x6e\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x65\x78\x70\x69\x72\x65\x73\x5f\x61\x74\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xfa\xc7\x84\x27\x4d\x00\x00\x00")
+
+func _1528395586_add_access_token_expiryDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395586_add_access_token_expiryDownSql,
+		"1528395586_add_access_token_expiry.down.sql",
+	)
+}
+
+func _1528395586_add_access_token_expiryDownSql() (*asset, error) {
+	bytes, err := _1528395586_add_access_token_expiryDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395586_add_access_token_expiry.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe9, 0xf4, 0xa, 0x25, 0x55, 0xaa, 0xae, 0x58, 0x3c, 0x51, 0x71, 0x39, 0x6b, 0x80, 0xd2, 0xe4, 0xa4, 0xa0, 0xf3, 0xca, 0xd3, 0x94, 0x7b, 0xf5, 0xb3, 0x32, 0xd6, 0x27, 0xad, 0x2a, 0x5c, 0x23}}
+	return a, nil
+}
+
+var __1528395586_add_access_token_expiryUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x5b\x00\xa4\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x61\x63\x63\x65\x73\x73\x5f\x74\x6f\x6b\x65\x6e\x73\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x65\x78\x70\x69\x72\x65\x73\x5f\x61\x74\x20\x74\x69\x6d\x65\x73\x74\x61\x6d\x70\x20\x77\x69\x74\x68\x20\x74\x69\x6d\x65\x20\x7a\x6f\x6e\x65\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x51\x00\x9b\x79\x5b\x00\x00\x00")
+
+func _1528395586_add_access_token_expiryUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395586_add_access_token_expiryUpSql,
+		"1528395586_add_access_token_expiry.up.sql",
+	)
+}
+
+func _1528395586_add_access_token_expiryUpSql() (*asset, error) {
+	bytes, err := _1528395586_add_access_token_expiryUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395586_add_access_token_expiry.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x54, 0x37, 0x2e, 0x84, 0x31, 0xab, 0x9f, 0x76, 0xde, 0xc1, 0x34, 0x2b, 0xae, 0xce, 0xda, 0x4d, 0x9c, 0xd5, 0x4, 0x47, 0x1d, 0x5d, 0x6e, 0xdd, 0xc3, 0xe5, 0xe, 0x32, 0x6d, 0x21, 0xe5, 0xdb}}
+	return a, nil
+}
+
+var __1528395587_add_audit_logDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x6a\x00\x95\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x61\x75\x64\x69\x74\x5f\x6c\x6f\x67\x3b\x0a\x44\x52\x4f\x50\x20\x46\x55\x4e\x43\x54\x49\x4f\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x61\x75\x64\x69\x74\x5f\x6c\x6f\x67\x5f\x72\x65\x6a\x65\x63\x74\x5f\x6d\x6f\x64\x69\x66\x69\x63\x61\x74\x69\x6f\x6e\x28\x29\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x65\xca\xc2\xaf\x6a\x00\x00\x00")
+
+func _1528395587_add_audit_logDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395587_add_audit_logDownSql,
+		"1528395587_add_audit_log.down.sql",
+	)
+}
+
+func _1528395587_add_audit_logDownSql() (*asset, error) {
+	bytes, err := _1528395587_add_audit_logDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395587_add_audit_log.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x4f, 0x11, 0x7b, 0x95, 0xeb, 0xad, 0x1f, 0x5e, 0xa8, 0x8, 0x34, 0x35, 0x0, 0x2a, 0x38, 0x53, 0x66, 0x48, 0x61, 0x7e, 0x2c, 0x4b, 0xb6, 0x7a, 0x63, 0x1e, 0xf1, 0x6e, 0xaf, 0x3e, 0x60, 0x82}}
+	return a, nil
+}
+
+var __1528395587_add_audit_logUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x53\x51\x6f\x9b\x4c\x10\x7c\xbf\x5f\x31\x0f\x96\x6c\x4b\xf6\xf7\x07\x78\x22\x78\xed\x0f\x95\x80\x75\x01\x35\x51\x55\xa1\x8b\x59\xe3\x4b\xf0\x9d\x7b\x5c\x9a\xa6\xbf\xbe\x02\x37\x71\x48\x9b\xb8\x8f\xdc\xcc\xce\xee\xcc\x2e\x17\xb4\x8a\xd3\x40\x88\x48\x52\x98\x13\xf2\xf0\x22\x21\xa8\x87\x4a\xfb\xb2\xb1\x35\x26\x02\x00\x74\x85\x5b\x5d\xb7\xec\xb4\x6a\xb0\x96\xf1\x65\x28\x6f\xf0\x89\x6e\x66\x3d\xaa\x36\xde\xba\xf2\xa1\x65\x57\xea\x0a\xda\x78\xae\xd9\xcd\x30\x9f\xc3\x58\x0f\x85\xad\x75\xac\x6b\x83\x7b\x7e\x9a\xa1\xb5\xf0\x3b\xe5\xc1\xc6\x3b\xcd\x2d\xec\x83\x6f\xf4\x77\x86\xdf\x31\x3a\x89\x37\x8a\x46\xed\x19\x9e\x7f\x78\xa4\x59\x8e\xb4\x48\x12\x2c\x68\x19\x16\x49\x8e\xf1\xf8\xd8\xde\xf1\xde\x7a\x2e\x55\x55\xb9\x33\xcc\xad\x75\x8f\xca\x55\x5c\x95\x5b\x7b\x8e\xab\x36\x5e\x5b\x33\x24\x1d\x11\xaf\x5c\xcd\xbe\xbc\xd7\xa6\xfa\x00\xd6\x1f\x81\xff\xe0\x6a\xb3\x53\xa6\xe6\x16\x77\xad\x35\xb7\x7f\xa1\x7d\xf9\xfa\x4c\x74\xac\x3c\x57\xa5\xf2\xf0\x7a\xcf\xad\x57\xfb\x03\x1e\xb5\xdf\xf5\x9f\xf8\x69\x0d\xff\x59\x6e\xec\xe3\x64\x2a\xa6\xc1\xf3\xda\xe3\x74\x41\xd7\xa7\xb5\x97\xaf\x44\xb3\xf4\xf4\x3e\x39\xbd\xbf\x5f\x3b\x3c\x87\x41\xf9\x00\x7a\x5f\xe1\x18\xe1\xb0\xf4\x77\x72\x5d\xea\xb3\x53\xc6\xd3\x40\x88\xf9\x1c\xf9\x8e\x8f\x5d\xd0\xdd\xac\x6e\xa1\x0e\x07\x36\xd5\xdc\x9a\xe6\xe9\xbf\xe7\x2e\xcb\x22\x8d\xf2\xf8\xb5\x68\xe9\xf8\x8e\x37\xbe\xdc\xdb\x4a\x6f\xf5\x46\x75\x1b\x9f\x4c\x21\x29\x2f\x64\x7a\x05\xef\x74\x5d\xb3\x43\x78\x85\xd1\x48\xf4\x3f\x4a\x9f\xb8\x0c\xe3\x2b\x02\x5d\x47\xb4\xee\xf5\xc6\x2f\x82\x6f\x5a\x8f\x03\x41\xe9\x22\x10\xa3\x11\x92\x30\x5d\x15\xe1\x8a\x70\x68\x0e\x75\xfb\xad\x79\xf1\x9e\xcb\x78\xb5\x22\xf9\x6a\xa8\xa3\x40\xd9\xcd\x8e\x0b\x5a\x66\x92\x50\xac\x17\x9d\x83\x4c\x62\x41\x09\xe5\x34\x88\xa6\x9f\x69\x99\x49\x50\x18\xfd\x0f\x99\x7d\x06\x5d\x53\x54\xe4\x84\xb5\xcc\x22\x5a\x14\x92\xce\x59\x0e\x84\x88\xb2\xcb\xcb\x38\x0f\xc4\xaf\x01\x00\xa6\x37\x86\xee\x10\x04\x00\x00")
+
+func _1528395587_add_audit_logUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395587_add_audit_logUpSql,
+		"1528395587_add_audit_log.up.sql",
+	)
+}
+
+func _1528395587_add_audit_logUpSql() (*asset, error) {
+	bytes, err := _1528395587_add_audit_logUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395587_add_audit_log.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x95, 0xe6, 0xb8, 0xca, 0x5b, 0xb7, 0xe6, 0xaa, 0x53, 0xd7, 0x42, 0x8e, 0x1c, 0xe9, 0x1e, 0x21, 0x14, 0x11, 0xf7, 0xb2, 0xc8, 0x32, 0x51, 0xff, 0xff, 0xdc, 0x2, 0x49, 0x6b, 0x33, 0x5d, 0xfc}}
+	return a, nil
+}
+
+var __1528395588_add_user_mfaDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x5f\x00\xa0\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x75\x73\x65\x72\x5f\x6d\x66\x61\x5f\x72\x65\x63\x6f\x76\x65\x72\x79\x5f\x63\x6f\x64\x65\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x75\x73\x65\x72\x5f\x74\x6f\x74\x70\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x53\x13\x21\x2f\x5f\x00\x00\x00")
+
+func _1528395588_add_user_mfaDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395588_add_user_mfaDownSql,
+		"1528395588_add_user_mfa.down.sql",
+	)
+}
+
+func _1528395588_add_user_mfaDownSql() (*asset, error) {
+	bytes, err := _1528395588_add_user_mfaDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395588_add_user_mfa.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf4, 0x26, 0x2e, 0x9, 0xc1, 0xd2, 0xb3, 0xfc, 0x57, 0x1f, 0x37, 0x47, 0xfc, 0x6c, 0xeb, 0x37, 0x8e, 0x6a, 0xa2, 0x15, 0xc7, 0x3e, 0xc5, 0x10, 0x3, 0xa1, 0x4f, 0x4d, 0x33, 0x13, 0x11, 0x2a}}
+	return a, nil
+}
+
+var __1528395588_add_user_mfaUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x91\x4f\xab\xa3\x30\x14\xc5\xf7\x7e\x8a\xb3\xb4\x60\x61\xf6\x5d\x59\x4d\x07\x19\xab\x83\xb5\x30\x5d\x85\x54\x6f\x6b\x40\x13\x49\x62\x3b\x33\x9f\xfe\xd1\xb4\xef\x0f\xbc\xb6\xf0\xe0\x2d\x2f\x1e\x7f\x27\xf7\x77\x97\xec\x67\x56\x2c\x82\x20\xa9\x58\x5c\x33\xd4\xf1\x32\x67\x98\x2c\x19\xee\xb4\x1b\x11\x06\x00\xae\xb3\x6c\x21\x95\xa3\x23\x19\xfc\xae\xb2\x75\x5c\xed\xf0\x8b\xed\x50\xb1\x15\xab\x58\x91\xb0\x8d\x8f\xd9\x50\xb6\x33\x94\x05\x52\x96\xb3\x9a\x21\x89\x37\x49\x9c\xb2\xc8\x73\x2c\x35\x86\x1c\x1c\xfd\x75\x28\xca\x1a\xc5\x36\xcf\xaf\x5f\x48\x89\x7d\x4f\x2d\x17\x0e\x4e\x0e\x64\x9d\x18\x46\x9c\xa5\xeb\xfc\x88\xff\x5a\x51\x84\xf9\xdc\xff\x81\x73\x27\x7b\x02\x29\xa3\xfb\x7e\x20\xe5\x20\x2d\x46\x52\xad\x54\x47\x0f\xeb\x85\x75\x7c\xb2\xd4\xf2\x46\x4f\xca\x91\xc1\x5e\x1e\xa5\x7a\xef\x44\xca\x56\xf1\x36\xaf\xf1\xc3\x43\x9d\xc6\x68\xe8\x74\x21\x19\x9a\x2c\x41\x1f\xd0\xe8\x96\xac\xa7\x35\x86\x84\x7b\xfe\xb4\xcf\x60\xa5\xcf\xe1\x2c\x98\xdd\x15\x3b\x1c\x04\x37\xd4\xe8\x13\x99\x7f\xdc\xf7\xdc\x34\xcb\x16\x96\x8c\x14\xfd\x47\xc1\xd1\xdd\x0b\xbc\x15\x7e\x45\xff\xa5\x8b\x77\xc2\x76\xf7\x2e\x30\xd9\xe7\x3b\x46\xdf\x20\xe3\xe6\x22\x2b\x52\xf6\xe7\x91\x0b\xfe\xba\x6a\x59\x3c\x8a\x84\xb7\x88\xd7\x5b\xae\xd7\x59\xbd\x08\x5e\x06\x00\xc7\xba\x4e\x0e\xc8\x02\x00\x00")
+
+func _1528395588_add_user_mfaUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395588_add_user_mfaUpSql,
+		"1528395588_add_user_mfa.up.sql",
+	)
+}
+
+func _1528395588_add_user_mfaUpSql() (*asset, error) {
+	bytes, err := _1528395588_add_user_mfaUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395588_add_user_mfa.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xfa, 0xb3, 0x31, 0xa4, 0xf5, 0xf, 0xe4, 0x5c, 0x3a, 0x25, 0x7, 0x4a, 0xd8, 0xe8, 0x59, 0x29, 0x13, 0xac, 0xc5, 0x54, 0x70, 0x3f, 0xe2, 0x99, 0x5b, 0x3f, 0x3b, 0x3d, 0x37, 0xf4, 0xd2, 0xec}}
+	return a, nil
+}
+
+var __1528395589_add_user_sessionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x35\x00\xca\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x75\x73\x65\x72\x5f\x73\x65\x73\x73\x69\x6f\x6e\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xf0\xf5\x9e\x39\x35\x00\x00\x00")
+
+func _1528395589_add_user_sessionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395589_add_user_sessionsDownSql,
+		"1528395589_add_user_sessions.down.sql",
+	)
+}
+
+func _1528395589_add_user_sessionsDownSql() (*asset, error) {
+	bytes, err := _1528395589_add_user_sessionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395589_add_user_sessions.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x71, 0x84, 0xe, 0x16, 0xc6, 0xbe, 0xc7, 0x8c, 0xbf, 0xa8, 0xf6, 0x76, 0x6c, 0xe2, 0x7, 0x68, 0x1b, 0x50, 0x5f, 0xeb, 0x7, 0x2d, 0xbc, 0x48, 0xbc, 0x4c, 0x13, 0xbf, 0x79, 0x6c, 0x5f, 0x5c}}
+	return a, nil
+}
+
+var __1528395589_add_user_sessionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x91\xcf\x6a\xc3\x30\x0c\xc6\xef\x7e\x0a\xdd\x9a\xc0\xde\x20\x27\x37\x51\x47\x58\xea\x8c\xd4\x85\xf5\x64\xbc\x5a\xb4\x82\x36\x29\xb6\xfa\x87\x3d\xfd\x20\x5d\xd9\xca\x18\x1b\x3b\x8a\x4f\xdf\x4f\xa0\xdf\x14\x1f\x6b\x53\x28\x55\x76\xa8\x2d\x82\xd5\xd3\x06\xe1\x98\x28\xba\x44\x29\xf1\xd0\x27\xc8\x14\x00\x00\x07\x78\xe5\x4d\xa2\xc8\x7e\x07\xcf\x5d\x3d\xd7\xdd\x0a\x9e\x70\xf5\x30\xa6\x63\x83\x03\x70\x2f\xb4\xa1\x08\xa6\xb5\x60\x96\x4d\x03\x1d\xce\xb0\x43\x53\xe2\x62\xa4\xa6\x8c\x43\x0e\xad\x81\x0a\x1b\xb4\x08\xa5\x5e\x94\xba\xc2\x2b\x64\x1d\xc9\x0b\x05\xe7\x05\x84\xf7\x94\xc4\xef\x0f\x70\x66\xd9\x8e\x23\xbc\x0d\x3d\x7d\x82\x2b\x9c\xe9\x65\x63\xa1\x1f\xce\x59\x7e\xed\xef\x7c\x12\xe7\xd7\xc2\x27\xfa\x37\x83\x2e\x07\x8e\x94\xfe\xd4\xbf\x5e\x8d\xb4\x1f\x84\x9c\x0f\x21\x82\xd0\x45\xbe\xe3\x27\x93\x2f\x4f\xf2\x1b\xea\xe5\x97\x45\x7f\x94\xad\x3b\xc4\xe1\xc4\x81\x7e\x86\xaa\xbc\xb8\x69\xab\x4d\x85\x2f\xf7\xda\xdc\x4d\x49\x6b\xee\x83\xec\x23\xc8\x0b\xa5\xca\x76\x3e\xaf\x6d\xa1\xde\x07\x00\xe9\x9e\xa6\xb1\x06\x02\x00\x00")
+
+func _1528395589_add_user_sessionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395589_add_user_sessionsUpSql,
+		"1528395589_add_user_sessions.up.sql",
+	)
+}
+
+func _1528395589_add_user_sessionsUpSql() (*asset, error) {
+	bytes, err := _1528395589_add_user_sessionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395589_add_user_sessions.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x2, 0x41, 0xbf, 0x25, 0x9b, 0xde, 0x87, 0xcb, 0x7, 0x57, 0xcc, 0x79, 0xb7, 0xfb, 0x11, 0xd6, 0x6a, 0xa1, 0x42, 0x87, 0x3d, 0xed, 0x18, 0x45, 0x22, 0xb2, 0x42, 0x57, 0x35, 0xb9, 0x1, 0x5}}
+	return a, nil
+}
+
+var __1528395590_add_user_stateDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x40\x00\xbf\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x75\x73\x65\x72\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x74\x61\x74\x65\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x1a\xf8\xbb\x24\x40\x00\x00\x00")
+
+func _1528395590_add_user_stateDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395590_add_user_stateDownSql,
+		"1528395590_add_user_state.down.sql",
+	)
+}
+
+func _1528395590_add_user_stateDownSql() (*asset, error) {
+	bytes, err := _1528395590_add_user_stateDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395590_add_user_state.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x72, 0x3e, 0x88, 0x72, 0x10, 0xa6, 0xf0, 0x66, 0x57, 0x83, 0x9c, 0x29, 0xb2, 0xc1, 0x2, 0x4f, 0x6f, 0x82, 0x51, 0xe2, 0x38, 0x67, 0xf9, 0xc3, 0x37, 0xb, 0x42, 0xef, 0x5a, 0xe8, 0x1c, 0x70}}
+	return a, nil
+}
+
+var __1528395590_add_user_stateUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\xcf\xc1\x6a\xc3\x30\x0c\xc6\xf1\xbb\x9f\xe2\xbb\xb9\x81\xbe\x41\xc6\xc0\x75\xb4\xd5\xcc\x51\xc0\x53\xd8\x6e\xc1\x2c\x66\x04\x4a\x17\x6a\x37\xec\xf1\xc7\x9a\x0d\x72\xe9\x4d\xfa\x1f\x7e\x48\x07\x7a\x76\x5c\x2b\x65\xbc\x50\x80\x98\x83\x27\x5c\x73\xba\x64\x98\xa6\x81\xed\x7c\xdf\x32\x72\x89\x25\xa1\xa4\xef\x02\xee\x04\xdc\x7b\x8f\x86\x9e\x4c\xef\x05\x3a\x7e\x94\x69\x49\xba\xbe\x4b\xf0\xab\x04\xe3\x58\xd6\x38\xdc\xb0\x61\x89\xa7\x69\x84\x3d\x92\x7d\xc1\xee\x96\xe0\x18\xbb\x7f\x6d\x0f\x9d\xaf\x79\x4e\xe7\x31\x8d\x7a\x0f\xfd\x3b\x4d\xe7\xcf\x21\xce\xf3\xe5\x6b\x89\x27\x5d\x55\xb5\xb2\x81\x8c\x10\x1c\x37\xf4\xbe\xc5\xd1\xf1\xba\xae\x70\x85\xb7\x23\x05\xfa\xfb\xe2\xe1\x71\x73\xb2\xb2\x5d\xdb\x3a\xa9\xd5\xcf\x00\xdb\xa7\x87\xb7\x07\x01\x00\x00")
+
+func _1528395590_add_user_stateUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395590_add_user_stateUpSql,
+		"1528395590_add_user_state.up.sql",
+	)
+}
+
+func _1528395590_add_user_stateUpSql() (*asset, error) {
+	bytes, err := _1528395590_add_user_stateUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395590_add_user_state.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xed, 0x85, 0xf, 0x3a, 0x40, 0xc7, 0xa6, 0x3b, 0x1a, 0x35, 0xd0, 0x78, 0xc5, 0x96, 0x80, 0x43, 0xc6, 0x8c, 0x9d, 0x24, 0x89, 0x95, 0xea, 0x19, 0x46, 0x3a, 0x79, 0x8, 0x43, 0x59, 0xef, 0x60}}
+	return a, nil
+}
+
+var __1528395591_add_saved_search_result_fingerprintsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x48\x00\xb7\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x61\x76\x65\x64\x5f\x73\x65\x61\x72\x63\x68\x5f\x72\x65\x73\x75\x6c\x74\x5f\x66\x69\x6e\x67\x65\x72\x70\x72\x69\x6e\x74\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xa3\xcd\x76\x5f\x48\x00\x00\x00")
+
+func _1528395591_add_saved_search_result_fingerprintsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395591_add_saved_search_result_fingerprintsDownSql,
+		"1528395591_add_saved_search_result_fingerprints.down.sql",
+	)
+}
+
+func _1528395591_add_saved_search_result_fingerprintsDownSql() (*asset, error) {
+	bytes, err := _1528395591_add_saved_search_result_fingerprintsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395591_add_saved_search_result_fingerprints.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3c, 0x49, 0x88, 0x6f, 0x6f, 0x6c, 0xf5, 0xc6, 0x73, 0x69, 0xa4, 0xa1, 0xab, 0xd2, 0xfa, 0x5d, 0x27, 0xbc, 0xe0, 0xaf, 0xd3, 0x90, 0x8c, 0x82, 0xd1, 0xb4, 0x1b, 0x60, 0x23, 0x44, 0xae, 0xb4}}
+	return a, nil
+}
+
+var __1528395591_add_saved_search_result_fingerprintsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\xcd\x41\x6a\xc3\x30\x14\x04\xd0\xbd\x4e\x31\x4b\x1b\x7a\x03\xaf\x14\xfb\xa7\x98\xda\x72\x51\x94\x45\x28\xc5\x88\xea\x37\x11\x34\x8a\x91\x7e\x9a\xd2\xd3\x17\x52\x08\x78\x39\xf0\x66\x66\x43\xcf\xbd\x69\x94\x6a\x2d\x69\x47\x70\x7a\x33\x10\x8a\xff\xe6\x30\x17\xf6\xf9\xe3\x34\x67\x2e\xd7\x2f\x99\x3f\x63\x3a\x72\x5e\x72\x4c\x52\x50\x29\x00\x6b\x16\x03\x62\x12\x3e\x72\xc6\xab\xed\x47\x6d\x0f\x78\xa1\x03\x2c\x6d\xc9\x92\x69\x69\xb7\xe2\x5c\xaa\x18\x6a\x4c\x06\x1d\x0d\xe4\x08\xad\xde\xb5\xba\xa3\xa7\xfb\xf0\xea\x4b\xf8\x47\xde\xde\x61\x26\x07\xb3\x1f\x86\x7f\x71\x5d\x82\x17\x0e\xb3\x17\x48\x3c\x73\x11\x7f\x5e\x70\x8b\x72\xba\x47\xfc\x5e\x12\x3f\x1a\xe8\x68\xab\xf7\x83\x43\xba\xdc\xaa\x5a\xd5\x8d\x52\xed\x34\x8e\xbd\x6b\xd4\xdf\x00\xe8\x01\x95\x92\xfe\x00\x00\x00")
+
+func _1528395591_add_saved_search_result_fingerprintsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395591_add_saved_search_result_fingerprintsUpSql,
+		"1528395591_add_saved_search_result_fingerprints.up.sql",
+	)
+}
+
+func _1528395591_add_saved_search_result_fingerprintsUpSql() (*asset, error) {
+	bytes, err := _1528395591_add_saved_search_result_fingerprintsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395591_add_saved_search_result_fingerprints.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x14, 0xdc, 0x50, 0x43, 0x8a, 0xf2, 0xb4, 0xcf, 0xa2, 0x92, 0xcf, 0xa5, 0x3, 0x8e, 0xba, 0x85, 0x2d, 0x40, 0x69, 0x24, 0xd9, 0x5e, 0xdc, 0xcc, 0x34, 0x2d, 0xcf, 0xf0, 0x5b, 0x59, 0x87, 0xbd}}
+	return a, nil
+}
+
+var __1528395592_add_saved_search_webhooksDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x73\x00\x8c\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x61\x76\x65\x64\x5f\x73\x65\x61\x72\x63\x68\x5f\x77\x65\x62\x68\x6f\x6f\x6b\x5f\x64\x65\x6c\x69\x76\x65\x72\x69\x65\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x61\x76\x65\x64\x5f\x73\x65\x61\x72\x63\x68\x5f\x77\x65\x62\x68\x6f\x6f\x6b\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x90\x47\xd0\xb7\x73\x00\x00\x00")
+
+func _1528395592_add_saved_search_webhooksDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395592_add_saved_search_webhooksDownSql,
+		"1528395592_add_saved_search_webhooks.down.sql",
+	)
+}
+
+func _1528395592_add_saved_search_webhooksDownSql() (*asset, error) {
+	bytes, err := _1528395592_add_saved_search_webhooksDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395592_add_saved_search_webhooks.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe1, 0x28, 0x26, 0xcf, 0x18, 0x85, 0x71, 0xed, 0xa5, 0xa4, 0x75, 0x1e, 0x1c, 0xbc, 0x54, 0x6f, 0xd1, 0xa5, 0x4a, 0x16, 0x96, 0x21, 0x49, 0x8c, 0x4e, 0xfd, 0x0, 0x53, 0x9a, 0xe8, 0xa0, 0xc9}}
+	return a, nil
+}
+
+var __1528395592_add_saved_search_webhooksUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x52\x4d\x6f\xdb\x30\x0c\xbd\xfb\x57\xf0\x16\x1b\xe8\x61\xf7\x60\x07\x57\x66\x57\xa3\x8e\x32\x38\x2e\xb6\x9e\x04\xcd\xe2\x1a\x61\x8e\x64\x48\x4a\xd2\xed\xd7\x0f\x71\xe2\x7c\xcd\xcb\x92\xa1\x47\x91\xef\xf1\x51\xef\xf1\x1e\x3f\xe5\x7c\x1c\x45\xac\xc4\xb4\x42\xa8\xd2\xfb\x02\xc1\xcb\x15\x29\xe1\x49\xba\x7a\x2e\xd6\xf4\x6d\x6e\xed\x0f\x0f\x71\x04\x00\xa0\x15\x78\x72\x5a\x36\xf0\xb9\xcc\x27\x69\xf9\x02\x4f\xf8\x72\xd7\xb5\x4e\x68\x5a\x81\x36\x81\x5e\xc9\x01\x9f\x56\xc0\x9f\x8b\x02\x4a\x7c\xc0\x12\x39\xc3\xd9\x89\x04\xf9\x58\xab\x04\xa6\x1c\x32\x2c\xb0\x42\x60\xe9\x8c\xa5\x19\x6e\xa7\x2e\x5d\x03\x81\xde\xc2\x7e\xcc\x4e\x8c\x6a\x47\x61\xa8\x53\x3b\x92\x81\x94\x90\x01\x82\x5e\x90\x0f\x72\xd1\xc2\x5a\x87\x79\xf7\x84\x5f\xd6\xd0\x9e\x01\x19\x3e\xa4\xcf\x45\x05\xc6\xae\xe3\x64\x27\xd8\xaa\xff\xe4\x47\xc9\xb8\x37\x32\xe7\x19\x7e\x1d\x36\x52\x9c\x54\xb5\xda\x7c\x7c\x10\x18\x9f\x01\x93\x6b\x72\x12\x8a\x1a\xbd\x22\xa7\xe9\x8a\xc4\x7a\xce\x0d\x61\x1d\xb6\xbb\x90\x19\xad\xc8\x0c\x66\xd3\xca\x9f\x8d\x95\x6a\xa8\xe5\x83\x0c\x74\xda\xd8\xbb\x3b\x6a\xc9\x28\x6d\x5e\x47\x5b\xa8\x0c\x81\x16\x6d\xf0\x7f\x2e\xdd\x13\x3e\x6c\x81\x8e\x7c\x6b\x8d\x27\xb1\x19\xbe\xf4\xa2\xb6\x8a\x7a\xd2\x6e\x53\xe7\xac\xeb\x54\xdf\xe7\x78\x1a\xe9\x83\xd8\xed\x77\x69\xc8\x16\x6d\xe8\xed\x06\x34\x9b\xf2\x59\x55\xa6\x39\xaf\xfe\x95\x7c\xf7\x5f\x12\x2b\xd9\x68\x05\xec\x11\xd9\x13\xc4\x5d\x09\x72\x0e\xf1\xc1\x4d\x18\xf9\x65\x5d\x13\x29\x52\x9b\xc7\x77\xa9\x1b\x52\xa3\xe4\xba\x43\x3e\xd6\xeb\x4b\x7f\xbf\xe6\x23\x74\x7c\x40\xdf\x1d\x1b\x9e\xe1\x8c\xdd\x2a\x7c\xee\xe0\x15\xea\x67\x94\x04\xbe\x3c\x62\x89\xd0\xf9\xf3\x71\xef\xcd\x38\x8a\xd8\x74\x32\xc9\xab\x71\xf4\x7b\x00\xf4\xab\x22\xb5\x24\x05\x00\x00")
+
+func _1528395592_add_saved_search_webhooksUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395592_add_saved_search_webhooksUpSql,
+		"1528395592_add_saved_search_webhooks.up.sql",
+	)
+}
+
+func _1528395592_add_saved_search_webhooksUpSql() (*asset, error) {
+	bytes, err := _1528395592_add_saved_search_webhooksUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395592_add_saved_search_webhooks.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x77, 0x5b, 0x4a, 0x48, 0x5, 0xda, 0x54, 0x78, 0x9d, 0xb8, 0x29, 0xce, 0x64, 0xb, 0x3e, 0x4c, 0xc, 0x7b, 0x40, 0xdd, 0xff, 0xb0, 0xf2, 0x81, 0xe3, 0xd5, 0xb3, 0x6, 0xa3, 0xfe, 0x86, 0xa0}}
+	return a, nil
+}
+
+var __1528395593_add_saved_search_subscriptions_and_runsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x6a\x00\x95\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x61\x76\x65\x64\x5f\x73\x65\x61\x72\x63\x68\x5f\x72\x75\x6e\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x73\x61\x76\x65\x64\x5f\x73\x65\x61\x72\x63\x68\x5f\x73\x75\x62\x73\x63\x72\x69\x70\x74\x69\x6f\x6e\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x38\xda\xd5\x9e\x6a\x00\x00\x00")
+
+func _1528395593_add_saved_search_subscriptions_and_runsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395593_add_saved_search_subscriptions_and_runsDownSql,
+		"1528395593_add_saved_search_subscriptions_and_runs.down.sql",
+	)
+}
+
+func _1528395593_add_saved_search_subscriptions_and_runsDownSql() (*asset, error) {
+	bytes, err := _1528395593_add_saved_search_subscriptions_and_runsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395593_add_saved_search_subscriptions_and_runs.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd2, 0x14, 0x1c, 0x59, 0x91, 0x49, 0x84, 0x10, 0xd5, 0x4, 0x2b, 0x9b, 0xf2, 0x95, 0x6e, 0x52, 0x0, 0xbe, 0x4e, 0xb1, 0xd, 0x87, 0xd1, 0xbc, 0x58, 0x1, 0x4b, 0x61, 0xe2, 0x63, 0xe7, 0xf8}}
+	return a, nil
+}
+
+var __1528395593_add_saved_search_subscriptions_and_runsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc4\x53\x4f\x6f\x9b\x4e\x10\xbd\xf3\x29\xe6\x66\x90\x7c\xf8\xdd\xfd\x53\x25\x02\x93\x16\x05\x2f\x2d\x60\xa9\x39\xad\x36\x30\xaa\x57\xc1\x4b\x32\xbb\x1b\x57\xfd\xf4\x95\xf9\x93\xd4\xb2\x45\x9a\x53\x8f\xb0\x6f\xde\x7b\x33\xf3\xe6\x06\x3f\x67\x62\x13\x04\x49\x89\x71\x8d\x50\xc7\x37\x39\x82\x55\x2f\xd4\x4a\x4b\x8a\x9b\xbd\xb4\xfe\xc1\x36\xac\x9f\x9c\xee\x8d\x85\x30\x00\x00\xd0\x2d\x58\x62\xad\x3a\xf8\x5a\x66\xdb\xb8\xbc\x87\x3b\xbc\x5f\x0f\x4f\x67\xb5\xba\x05\x6d\x1c\xfd\x20\x06\x51\xd4\x20\x76\x79\x0e\x25\xde\x62\x89\x22\xc1\xea\x4c\x87\x6c\xa8\xdb\x08\x0a\x01\x29\xe6\x58\x23\x24\x71\x95\xc4\x29\x8e\xac\xde\x12\xbf\xc7\x76\xc2\x2c\x92\x34\x7b\x65\x0c\x75\xe0\xe8\xa7\x7b\x65\x98\x5c\x77\xaa\x79\x94\x47\x7a\xd8\xf7\xfd\xa3\xf4\x3c\x82\xa6\x32\x26\xe5\xa8\x95\xca\x81\xd3\x07\xb2\x4e\x1d\x9e\xe0\xa8\xdd\x7e\xf8\x84\x5f\xbd\xa1\x37\x3f\x29\xde\xc6\xbb\xbc\x06\xd3\x1f\xc3\x68\xac\x4f\x0a\x51\xd5\x65\x9c\x89\x7a\x61\xb0\x72\x32\x27\x5f\x54\xa7\x5b\x48\xbe\x60\x72\x07\xe1\xec\x38\x13\x10\xae\xe8\xa0\x74\xb7\x5a\xc3\x6a\x30\xbb\x8a\x3e\x44\x7f\xd1\xa0\x64\x7a\xf6\x9a\xe9\x42\xeb\xff\x4f\xb3\x02\x14\xe5\x95\xc1\x64\xd5\x6b\xb7\x51\x10\x6d\xe6\xe4\xec\x44\xf6\x6d\x87\x90\x89\x14\xbf\x2f\x19\xf1\x46\x3f\x7b\x82\x42\x2c\x80\xc2\xb3\x27\xdd\xae\xe7\xfd\xaf\x61\x72\xf9\xa6\xfb\xbe\xe0\x14\x9d\x65\xc5\x09\x15\x2d\x9e\x02\xfb\x7f\x77\x01\xd6\x29\xfe\xdb\x14\x8e\x15\xad\x67\x75\x6a\x4e\x1e\xec\x85\x87\x11\xc1\x64\x7d\xe7\x64\xd3\x7b\xe3\x2e\x6d\xce\x51\xfe\x6f\x04\x1b\x3a\xca\x0f\x15\x10\x73\xcf\xc3\x1d\x05\x8b\xeb\x3a\x4d\x55\x9e\xfd\xb9\xb2\x2d\xf6\xd7\x62\xf1\xc7\x50\x52\xac\x92\x61\x7b\xc5\x76\x9b\xd5\x9b\xe0\xf7\x00\xf8\x4b\xae\xf8\xd9\x04\x00\x00")
+
+func _1528395593_add_saved_search_subscriptions_and_runsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395593_add_saved_search_subscriptions_and_runsUpSql,
+		"1528395593_add_saved_search_subscriptions_and_runs.up.sql",
+	)
+}
+
+func _1528395593_add_saved_search_subscriptions_and_runsUpSql() (*asset, error) {
+	bytes, err := _1528395593_add_saved_search_subscriptions_and_runsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395593_add_saved_search_subscriptions_and_runs.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x76, 0xfb, 0xa8, 0xbd, 0x5e, 0xb2, 0x7f, 0x4a, 0xef, 0xa3, 0x95, 0xf7, 0x8e, 0x62, 0x2e, 0x33, 0xc1, 0xfa, 0xa0, 0x9a, 0x58, 0x92, 0xa3, 0xaa, 0xe0, 0xd8, 0x3a, 0x1, 0x7e, 0xf7, 0x3c, 0x1b}}
+	return a, nil
+}
+
+var __1528395594_add_saved_search_schedulesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\xcc\x4b\x0a\xc3\x20\x10\x00\xd0\xbd\xa7\x98\x7b\xb8\x4a\x52\x5b\x84\x7c\x4a\x62\xa1\x3b\x11\x9d\x12\x21\x51\x98\xd1\xd0\xe3\xf7\x0c\x4d\x7b\x80\xf7\x5a\x75\xd3\xa3\x14\xa2\xe9\x8d\x9a\xc1\x34\x6d\xaf\x80\xdd\x81\xc1\x32\x3a\xf2\x2b\x32\x5c\xe6\xe9\x0e\xdd\xd4\x3f\x86\x11\xf4\x15\xd4\x53\x2f\x66\x01\xf6\x2b\x86\xba\xa1\xfc\x9e\x96\xb8\x63\xae\xc5\x32\xfa\x9c\x02\x9f\x18\x12\xbe\x8b\xa5\x9a\xac\x2b\x27\xf4\xe6\xf8\x1f\x3a\x54\x72\x25\xe6\x64\x77\xfe\xa5\x41\xa2\x4c\x27\x02\x9f\x13\xa3\xaf\x25\x1e\x68\x5f\x2e\x6e\x95\x90\xa5\x10\xdd\x34\x0c\xda\x48\xf1\x19\x00\xb3\x7e\x85\x71\xd9\x01\x00\x00")
+
+func _1528395594_add_saved_search_schedulesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395594_add_saved_search_schedulesDownSql,
+		"1528395594_add_saved_search_schedules.down.sql",
+	)
+}
+
+func _1528395594_add_saved_search_schedulesDownSql() (*asset, error) {
+	bytes, err := _1528395594_add_saved_search_schedulesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395594_add_saved_search_schedules.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x8b, 0xad, 0xbc, 0xbd, 0xe5, 0x92, 0xf5, 0xe0, 0x20, 0x64, 0xcf, 0x32, 0xdf, 0x13, 0x68, 0x8c, 0x14, 0x5f, 0x8, 0xb7, 0x39, 0x1e, 0xdf, 0x8c, 0x44, 0xe1, 0x3, 0x48, 0x9a, 0x89, 0xda, 0x5}}
+	return a, nil
+}
+
+var __1528395594_add_saved_search_schedulesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x52\xc1\x6a\xeb\x30\x10\xbc\xeb\x2b\xe6\xf6\x5e\x0f\x29\xb9\x1b\x0a\x8e\xe3\xb6\xa1\x8e\x03\xa9\x72\x16\x42\xde\xc4\x82\x58\x4a\xb5\xab\x34\xed\xd7\x97\x04\x5a\x68\x4e\x0e\xf4\x38\x2c\x33\xb3\xb3\xb3\xb3\xfa\x69\xd1\x16\x4a\x95\x8d\xae\xd7\xd0\xe5\xac\xa9\xc1\xf6\x48\x9d\x61\xb2\xc9\xf5\xc4\x28\xe7\x73\x54\xab\x66\xb3\x6c\xc1\xae\xa7\x2e\xef\x09\x42\x27\x29\x46\x92\xc4\x0f\x14\xb3\x18\x26\x17\x43\xc7\xf0\x41\x68\x47\x69\x04\xbd\x7d\xd5\xeb\x72\xd1\xea\xab\xa9\xb9\x52\x34\x87\xc8\x5e\xfc\x91\x50\x3d\xd7\xd5\x0b\xfe\x5f\x3b\x3e\x60\x7a\x57\x28\x35\x99\x40\xf7\x84\xb7\x4c\xe9\x03\x29\x87\x40\xe9\x1f\x7f\x67\xf2\x61\x07\x16\x2b\x04\x1b\x3a\x48\x4f\x17\xc4\x88\xdb\x0b\x18\x22\x0b\x12\x39\x0a\x72\xa6\xde\x8f\xcc\x1e\xe8\x24\x26\xe5\x60\xac\xe0\xbc\x15\x8b\x1d\x0e\x78\xf7\xd2\x5f\x20\x3e\x63\xa0\xb1\x77\xdc\x5b\xfe\x7b\xad\x2e\x27\x2b\x3e\x06\x33\xdc\x52\xcc\x6f\x0d\x4a\x29\xa6\x9b\x5e\xc2\xc5\xc0\xe4\xf2\xb9\x33\xb3\xb5\x7e\x9f\x13\xfd\xd8\xa3\x5d\x69\xb4\x9b\xa6\xc1\xbc\x7e\x2c\x37\x8d\xc6\xb4\x50\xaa\x5a\x2d\x97\x0b\x5d\xa8\xaf\x01\x00\xe8\xef\xc3\xc8\xb0\x02\x00\x00")
+
+func _1528395594_add_saved_search_schedulesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395594_add_saved_search_schedulesUpSql,
+		"1528395594_add_saved_search_schedules.up.sql",
+	)
+}
+
+func _1528395594_add_saved_search_schedulesUpSql() (*asset, error) {
+	bytes, err := _1528395594_add_saved_search_schedulesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395594_add_saved_search_schedules.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x74, 0x2, 0x95, 0x4e, 0x28, 0x74, 0x41, 0x5b, 0xb0, 0x4c, 0xe7, 0x38, 0x3, 0xb9, 0xa9, 0xf9, 0xc5, 0xe1, 0xf4, 0x90, 0x2d, 0x1c, 0xf0, 0xbf, 0x75, 0xcc, 0x98, 0xca, 0xea, 0x1b, 0x64, 0x7f}}
+	return a, nil
+}
+
+var __1528395595_add_discussion_threads_diff_targetsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\xce\x4b\x0e\xc2\x20\x14\x85\xe1\x39\xab\x60\x1f\x1d\xf5\x81\x86\xa4\x0f\xd3\x62\xd2\xd9\x0d\x7a\x2f\x96\xa4\x81\x06\xf0\xb1\x7c\x53\x27\xc6\x91\x13\x16\x70\xbe\xff\x54\xe2\x28\xfb\x82\xb1\x66\x1c\x4e\x5c\xf6\x8d\x98\xb9\x3c\x70\x31\xcb\x49\x4d\x1c\x6d\xbc\xde\x63\xb4\xde\x41\x5a\x02\x69\x8c\x90\x74\xb8\x51\x82\x40\x9b\x07\xb4\xc6\x80\xc5\x57\xc1\xca\x56\x89\x91\xab\xb2\x6a\xc5\x9f\x11\xff\x84\xea\xa1\x3d\x77\xfd\x4f\xc9\x18\xb8\xe8\x48\x10\xe8\x61\xf7\x62\x2e\x74\x21\x8d\x39\x51\xbf\x22\x6c\x3a\x2d\x99\xa8\x98\x74\x48\xb0\x5a\x47\x99\x40\x72\x98\x8b\x73\xf4\xcc\xfb\x6f\x07\xbf\xff\x58\x3d\x74\x9d\x54\x05\x7b\x0f\x00\x4f\x66\x58\x98\x84\x02\x00\x00")
+
+func _1528395595_add_discussion_threads_diff_targetsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395595_add_discussion_threads_diff_targetsDownSql,
+		"1528395595_add_discussion_threads_diff_targets.down.sql",
+	)
+}
+
+func _1528395595_add_discussion_threads_diff_targetsDownSql() (*asset, error) {
+	bytes, err := _1528395595_add_discussion_threads_diff_targetsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395595_add_discussion_threads_diff_targets.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x34, 0x73, 0x35, 0x57, 0x4c, 0x71, 0x4c, 0xf0, 0x8d, 0x8e, 0x81, 0xcd, 0x5, 0xfe, 0x88, 0x67, 0x8c, 0xcc, 0x13, 0x1a, 0x29, 0xbc, 0x15, 0x87, 0xa7, 0xda, 0xf9, 0x9a, 0xee, 0x7a, 0x4d, 0xc0}}
+	return a, nil
+}
+
+var __1528395595_add_discussion_threads_diff_targetsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x92\xc1\x6e\xdb\x30\x10\x44\xef\xfa\x8a\x39\xda\x80\xd3\x1f\xd0\x49\x91\x89\x46\xa8\x2c\x01\x0a\x83\xf6\x46\xd0\xe6\x2a\x22\xa0\x90\x2e\xb9\xa9\xd2\xbf\x2f\x28\x35\x68\x83\x18\x10\x10\xe7\xa2\x83\x38\xfb\x30\xbb\x33\xb7\xe2\x6b\xd5\xe4\x59\x76\x73\x03\x39\x04\xd2\x26\xc2\x3b\xf0\x40\x30\xb6\xef\xe1\x7b\x68\x9c\xfc\xd3\x93\x65\x6c\xbc\x1b\x7f\xcf\xbf\xd5\x40\xda\xa8\x40\xbf\x6c\xb4\xde\xc1\x46\x44\xe2\x2d\x7c\xf8\x3b\x9b\x68\x6f\xc6\xcf\x3a\xd8\xe8\x1d\x8e\xc4\x13\x91\x03\x4f\x1e\xaf\xe3\x11\x9b\xa3\xe7\x01\x3a\xd0\x8c\xf9\x92\x15\xb5\x14\x1d\x64\x71\x5b\x0b\x18\x1b\x4f\xcf\x31\xe9\x14\x2f\xfe\x14\xeb\xf0\x48\xac\x02\x9d\x3d\x8a\xfd\x1e\x65\x5b\x3f\x1c\x9a\xc5\xd8\x51\x47\xfa\x67\x8c\xe9\x85\xf3\x6b\x70\x6f\xf7\xfc\x20\xae\xb9\x97\x5d\x51\x35\x72\x45\xad\xfe\x5f\xe0\xe7\xb3\x0d\x14\xe7\x3b\xa3\xbc\x13\xe5\x37\x6c\x2e\xec\x57\xdd\xa3\x79\xa8\x6b\xb4\xdd\x25\xbb\xe9\xb5\x95\xb3\x62\xfb\x9a\x70\x3a\xf1\x48\x27\x26\x83\xd1\x3a\x8a\x4b\x42\xbd\x1d\x97\xbc\x77\xb0\x73\x80\xf0\xa3\x81\x76\x06\x8e\xa6\xf9\xf5\xa3\xa1\xf8\xd1\xa8\xb3\xe6\xe1\xaa\x28\x12\x24\xb2\x0e\xac\x92\x67\x58\xc7\xf4\x48\xe1\x1a\x1a\x39\xf3\x29\x2c\x47\xd3\x27\x3a\x4b\xb4\xf7\xce\xb2\xb2\x13\x85\x14\xa8\x9a\xbd\xf8\xb1\x42\x5b\x3a\x64\xcd\x0b\xda\x66\x45\xba\x49\x1f\x65\xcd\xee\x42\x75\x76\x78\x5f\xb6\x2d\xbe\xdf\x89\x4e\xac\x14\x2d\xcf\xb2\xb2\x3d\x1c\x2a\x99\x67\x7f\x06\x00\x94\xae\xd9\x95\x5a\x04\x00\x00")
+
+func _1528395595_add_discussion_threads_diff_targetsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395595_add_discussion_threads_diff_targetsUpSql,
+		"1528395595_add_discussion_threads_diff_targets.up.sql",
+	)
+}
+
+func _1528395595_add_discussion_threads_diff_targetsUpSql() (*asset, error) {
+	bytes, err := _1528395595_add_discussion_threads_diff_targetsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395595_add_discussion_threads_diff_targets.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x1d, 0x44, 0x72, 0x9f, 0x19, 0xc2, 0x9d, 0xb2, 0x4e, 0xb6, 0x46, 0x5c, 0xec, 0xc6, 0x65, 0x2b, 0x40, 0xaf, 0x4b, 0xe8, 0x10, 0xa9, 0x64, 0xc7, 0x6d, 0x6, 0x30, 0x11, 0x4b, 0x41, 0x2e, 0x2b}}
+	return a, nil
+}
+
+var __1528395596_add_notificationsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x66\x00\x99\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x75\x73\x65\x72\x5f\x6e\x6f\x74\x69\x66\x69\x63\x61\x74\x69\x6f\x6e\x5f\x73\x65\x74\x74\x69\x6e\x67\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x6e\x6f\x74\x69\x66\x69\x63\x61\x74\x69\x6f\x6e\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x23\x75\xb6\xcd\x66\x00\x00\x00")
+
+func _1528395596_add_notificationsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395596_add_notificationsDownSql,
+		"1528395596_add_notifications.down.sql",
+	)
+}
+
+func _1528395596_add_notificationsDownSql() (*asset, error) {
+	bytes, err := _1528395596_add_notificationsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395596_add_notifications.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb9, 0x9e, 0x73, 0xe9, 0x77, 0x71, 0x67, 0xd1, 0x7d, 0xbf, 0x1e, 0xca, 0x1b, 0x50, 0xb8, 0xa0, 0x94, 0x3d, 0x77, 0x7c, 0x1f, 0x69, 0x72, 0xa5, 0x46, 0x56, 0x61, 0xb0, 0xe6, 0x67, 0x79, 0xd8}}
+	return a, nil
+}
+
+var __1528395596_add_notificationsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x92\xc1\x8e\xda\x40\x0c\x86\xef\x79\x0a\xdf\x16\xa4\x7d\x03\x4e\xd9\xe0\x6d\xa3\x86\x50\x85\xac\xda\x3d\x8d\x26\x19\x17\x2c\x92\x19\x34\x63\x44\xdb\xa7\xaf\x48\x00\x11\x76\x15\x50\x8f\x19\xfb\xff\x62\xfb\xff\x5f\xf0\x4b\x9a\xcf\xa2\x28\x29\x30\x2e\x11\xca\xf8\x25\x43\xb0\x4e\xf8\x17\xd7\x5a\xd8\xd9\x00\x93\x08\x00\x80\x0d\x54\xbc\x0e\xe4\x59\x37\xf0\xbd\x48\x17\x71\xf1\x0e\xdf\xf0\xfd\xb9\xab\xee\x03\x79\xc5\x06\xd8\x0a\xad\xc9\x43\xbe\x2c\x21\x7f\xcb\x32\x28\xf0\x15\x0b\xcc\x13\x5c\x75\x3d\x61\xc2\x66\x0a\xcb\x1c\xe6\x98\x61\x89\x90\xc4\xab\x24\x9e\x63\x0f\xd9\xb2\x35\x20\xf4\x5b\x2e\xf2\xfe\x5d\xd7\xe2\xbc\xba\xfd\xc5\x1d\xf2\x0a\xaf\x11\xb2\xf1\xa4\xcd\x51\x5e\xf1\x9a\xad\x5c\xab\x0d\x87\x7a\x1f\x02\x3b\xab\xfa\xb6\xd1\x21\x6b\xd7\xb6\x64\xe5\x2e\xea\xd4\x37\xca\x12\x96\x86\x3e\xdb\xb8\x72\xe6\xcf\xf0\x1d\xe6\xf8\x1a\xbf\x65\x25\x3c\x3d\x9d\x2e\xee\x9b\xcf\x94\xb5\x27\x2d\x64\x94\x16\x10\x6e\x29\x88\x6e\x77\x70\x60\xd9\x74\x9f\xf0\xd7\x59\xfa\xc8\xb4\xee\x30\x99\xf6\xfa\xe3\xfe\x63\xe2\x68\x3a\x3b\x67\x25\xcd\xe7\xf8\x73\x98\x95\x8b\x49\xcb\x7c\x58\x98\x9c\x0a\xcf\xc0\xe6\x21\x82\xda\xdb\xe3\x28\xe3\x20\xf8\xf1\x15\x0b\xbc\xcc\x9c\xae\xba\xb5\x6e\xd3\xdc\x29\xae\x21\x2a\x90\x08\xdb\xf5\x39\xda\xb7\xc9\xba\x4a\xf7\xbd\x94\x0d\xec\x64\xab\xf4\x6e\x07\x95\x73\x0d\x69\xfb\xf1\xcc\xe2\xf7\xd4\x5f\x99\x5a\xcd\xcd\x23\x8d\xa1\xd1\xf5\x56\x1d\xa8\xda\x38\xb7\x55\x67\xcf\x4f\x09\xd8\x99\xff\xb4\xfa\x68\x62\x94\x2c\x17\x8b\xb4\x9c\x45\xff\x06\x00\x9a\x0a\x09\x8a\x02\x04\x00\x00")
+
+func _1528395596_add_notificationsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395596_add_notificationsUpSql,
+		"1528395596_add_notifications.up.sql",
+	)
+}
+
+func _1528395596_add_notificationsUpSql() (*asset, error) {
+	bytes, err := _1528395596_add_notificationsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395596_add_notifications.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x52, 0x6a, 0x4d, 0x85, 0x60, 0x67, 0x4e, 0xa9, 0xc5, 0xee, 0xe5, 0x3c, 0x54, 0xd4, 0x1c, 0xdc, 0x53, 0x2d, 0x92, 0x36, 0x12, 0xa7, 0x59, 0x86, 0x67, 0xe6, 0xfd, 0xf6, 0x7d, 0xa1, 0xe6, 0x12}}
+	return a, nil
+}
+
+var __1528395597_add_discussions_full_text_indexesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x72\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\xf0\xf4\x73\x71\x8d\x50\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\xc9\x2c\x4e\x2e\x2d\x2e\xce\xcc\xcf\x8b\x2f\xc9\x28\x4a\x4d\x4c\x29\x8e\x2f\xc9\x2c\xc9\x49\x8d\x4f\x2b\x29\x8e\xcf\x4c\xa9\xb0\x26\xa8\x2d\x39\x3f\x37\x37\x35\xaf\xa4\x38\x3e\x39\x3f\xaf\x04\xcc\x80\x6b\xe5\x72\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x0c\x00\xb6\xe0\x84\xb6\x83\x00\x00\x00")
+
+func _1528395597_add_discussions_full_text_indexesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395597_add_discussions_full_text_indexesDownSql,
+		"1528395597_add_discussions_full_text_indexes.down.sql",
+	)
+}
+
+func _1528395597_add_discussions_full_text_indexesDownSql() (*asset, error) {
+	bytes, err := _1528395597_add_discussions_full_text_indexesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395597_add_discussions_full_text_indexes.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x4, 0x3b, 0xfe, 0x3e, 0x3b, 0x94, 0xa9, 0xea, 0x62, 0xd4, 0x43, 0x2, 0x7, 0xd8, 0x5c, 0x14, 0xf1, 0xb6, 0xea, 0xb6, 0x22, 0x41, 0xcf, 0x6e, 0xbc, 0xe4, 0x92, 0x7c, 0x27, 0x81, 0x78, 0x3a}}
+	return a, nil
+}
+
+var __1528395597_add_discussions_full_text_indexesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x8e\xc1\x4e\xeb\x30\x10\x45\xf7\xf9\x8a\xd9\x35\x91\x5e\xfa\x01\x2f\x2b\x28\xa1\xca\x22\xa9\x80\x20\xb1\xb3\x82\x73\x5b\x5b\x4a\xec\xca\x33\x46\xe6\xef\x51\x22\x0a\x2c\x8a\xd8\xcd\xe2\xdc\x73\xe6\xb6\xde\x37\x5d\x95\x65\x65\x49\xf7\x71\x9a\x4a\x41\x12\xb2\x6e\x44\x02\xd3\xd1\x07\x92\xf7\x33\xfe\x8f\x96\x75\x64\xb6\xde\x11\x63\x08\xda\x80\xb7\xf4\x10\x11\x2c\x98\xe6\xc8\x42\x91\x41\x62\x40\x3c\xcc\x58\x64\x48\xe7\x80\x75\xf1\xa9\x31\xf8\xd2\x8a\xa7\x57\x2c\x8b\x91\x72\x06\xe8\xdb\xce\xcb\x0f\x3d\x92\xb4\x83\x68\x53\x6c\xb3\xdd\x63\x7d\xd3\xd7\xd4\x74\x77\xf5\xcb\x0f\x4e\x89\x09\x18\x46\x56\x62\x65\x82\x3a\x0a\x2b\x3b\x26\x3a\x74\x57\x18\x7a\x7e\x6a\xba\x3d\x9d\xac\xcb\xc5\x2b\xe1\x37\x68\xf1\x21\xdf\xc0\x9d\x26\xcb\x66\xf3\x8f\x56\x49\x51\x54\xbf\xd6\xb4\x9f\x67\x38\x61\xa5\xbd\x93\xf5\xb8\x5e\xbc\x70\x7f\x27\x2f\xa2\xa5\x9a\xed\x0e\x6d\xdb\xf4\x55\xf6\x31\x00\xeb\x7f\x6c\x06\x8c\x01\x00\x00")
+
+func _1528395597_add_discussions_full_text_indexesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395597_add_discussions_full_text_indexesUpSql,
+		"1528395597_add_discussions_full_text_indexes.up.sql",
+	)
+}
+
+func _1528395597_add_discussions_full_text_indexesUpSql() (*asset, error) {
+	bytes, err := _1528395597_add_discussions_full_text_indexesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395597_add_discussions_full_text_indexes.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x9b, 0x77, 0x51, 0xf3, 0x78, 0xa1, 0xa6, 0xe4, 0x79, 0x97, 0xa3, 0xb2, 0x30, 0x18, 0xa3, 0x9b, 0xd4, 0x36, 0x83, 0x67, 0x9, 0xab, 0x32, 0x4d, 0x8b, 0xce, 0x7e, 0x81, 0xf4, 0x15, 0xef, 0x99}}
+	return a, nil
+}
+
+var __1528395598_add_discussion_thread_resolution_assignee_reactionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\xce\x4d\xca\xc2\x30\x10\xc6\xf1\x7d\x4e\x91\x7b\x64\xd5\x8f\xbc\x2f\x81\x7e\x48\x1b\xa1\xbb\x21\x36\x83\x0e\xd8\x04\x32\xa9\xe8\xed\x05\x51\x10\x75\x25\x1e\xe0\xff\x7b\x9e\x52\xff\x9b\x4e\x09\x51\x0f\xfd\x46\xda\xa2\x6c\xb4\x34\x7f\x52\x4f\x66\xb4\xa3\xf4\xc4\xf3\xca\x4c\x31\xc0\x1c\x97\x05\x43\x86\x84\x6e\xce\x14\x03\x3f\x1a\xd3\xd5\x7a\xfa\xdc\xe4\x43\x42\xe7\x19\x1c\x33\xed\x03\x22\xac\x8c\x09\xc8\x03\xf9\xb3\x12\x45\x63\xf5\x70\x9f\x7c\x8f\xe4\xed\x50\xd5\x37\xdb\xb6\x7b\xd2\x5f\xa9\x2f\x99\x84\x1c\x8f\x27\xf4\xb0\xbb\xfc\x4a\x72\x59\x09\x51\xf5\x6d\x6b\xac\x12\xd7\x01\x00\x41\x66\x8d\x76\x56\x01\x00\x00")
+
+func _1528395598_add_discussion_thread_resolution_assignee_reactionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395598_add_discussion_thread_resolution_assignee_reactionsDownSql,
+		"1528395598_add_discussion_thread_resolution_assignee_reactions.down.sql",
+	)
+}
+
+func _1528395598_add_discussion_thread_resolution_assignee_reactionsDownSql() (*asset, error) {
+	bytes, err := _1528395598_add_discussion_thread_resolution_assignee_reactionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395598_add_discussion_thread_resolution_assignee_reactions.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb, 0xbe, 0xbb, 0x42, 0x51, 0x5b, 0x19, 0x35, 0xcb, 0x41, 0x7b, 0x39, 0x79, 0x1a, 0xb9, 0xaf, 0xa0, 0x3e, 0x77, 0x95, 0x25, 0xa0, 0x65, 0x49, 0x16, 0xea, 0x1c, 0x77, 0xdb, 0x15, 0xd3, 0xe3}}
+	return a, nil
+}
+
+var __1528395598_add_discussion_thread_resolution_assignee_reactionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x92\xc1\x6e\xea\x30\x10\x45\xf7\xf9\x8a\x59\x26\x12\x7f\x90\x95\x49\x86\xa7\xe8\x25\x4e\x15\x8c\x54\x56\x56\x88\x47\x30\x55\x63\x57\xb1\x29\xb4\x5f\x5f\x41\x84\x68\x03\xaa\x54\x96\x89\xc7\xe7\xdc\x3b\xf2\x1c\xff\x15\x32\x8d\x22\x51\x2a\x6c\x40\x89\x79\x89\x60\xd8\x77\x7b\xef\xd9\x59\x1d\x76\x03\xb5\xc6\x83\xc8\x73\xc8\xea\x72\x55\x49\x18\xc8\xbb\xd7\x77\x32\xba\x0d\x10\xb8\x27\x1f\xda\xfe\x0d\x0e\x1c\x76\xe7\x4f\xf8\x74\x96\xd2\x47\x78\x9b\x0f\xbd\xf7\x34\x68\x36\xc0\x36\xd0\x96\x06\x68\x70\x81\x0d\xca\x0c\x97\x70\x3a\xf2\x31\x9b\x04\x6a\x09\x39\x96\xa8\x10\x96\xa8\x40\xae\xca\xf2\x2f\xba\xd6\x7b\xde\x5a\xa2\xc7\x5d\x59\x83\x42\x21\x14\x32\xc7\xe7\x3b\x32\x3d\x35\x68\x36\xc7\x53\xe8\xdb\xd1\x78\x3a\x9a\xa4\xd1\x05\x7f\xd3\xa5\x73\x7d\x4f\x36\xe8\x81\xda\x2e\xb0\xb3\x1e\xe2\x08\x00\xe0\xf2\x9f\x0d\x6c\x78\xcb\x36\x80\xac\xc7\xa8\xdf\x2b\xdd\x82\xa6\x05\x33\xb1\xcc\x44\x8e\xb3\x33\x74\xba\x9c\x7b\xc8\x7b\x5b\xfa\x01\xa1\xde\xbd\x30\x04\x3a\x5e\x23\x8d\xf4\x6e\xa0\x36\xfc\xfe\x82\xae\xc6\x1c\x17\x62\x55\x2a\xb0\xee\x10\x27\xe3\xfd\xa7\xa6\xa8\x44\xb3\x86\xff\xb8\x86\xf8\xda\x7f\x76\x89\x3d\x1b\xd5\x49\x74\xde\x67\x5d\x55\x85\x4a\xa3\xaf\x01\x00\xfa\x76\x48\x08\xea\x02\x00\x00")
+
+func _1528395598_add_discussion_thread_resolution_assignee_reactionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395598_add_discussion_thread_resolution_assignee_reactionsUpSql,
+		"1528395598_add_discussion_thread_resolution_assignee_reactions.up.sql",
+	)
+}
+
+func _1528395598_add_discussion_thread_resolution_assignee_reactionsUpSql() (*asset, error) {
+	bytes, err := _1528395598_add_discussion_thread_resolution_assignee_reactionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395598_add_discussion_thread_resolution_assignee_reactions.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc0, 0xc9, 0x15, 0xec, 0xe2, 0xb5, 0xa6, 0x77, 0xbf, 0x90, 0x36, 0xed, 0xf1, 0x6, 0x64, 0x2b, 0x29, 0x4d, 0x99, 0xc0, 0x5a, 0x70, 0xf6, 0xdb, 0x44, 0xfd, 0xd6, 0x3a, 0x9c, 0x89, 0x34, 0x92}}
+	return a, nil
+}
+
+var __1528395599_repo_demandDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x33\x00\xcc\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x70\x6f\x5f\x64\x65\x6d\x61\x6e\x64\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xad\x37\xf7\x84\x33\x00\x00\x00")
+
+func _1528395599_repo_demandDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395599_repo_demandDownSql,
+		"1528395599_repo_demand.down.sql",
+	)
+}
+
+func _1528395599_repo_demandDownSql() (*asset, error) {
+	bytes, err := _1528395599_repo_demandDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395599_repo_demand.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf4, 0x58, 0xfd, 0x7b, 0xf0, 0x8f, 0x7a, 0x23, 0x65, 0xbf, 0xc8, 0x37, 0x22, 0x48, 0x17, 0x31, 0x9f, 0xb6, 0xf, 0x76, 0xd0, 0x2f, 0xdd, 0x9b, 0x91, 0x4f, 0xf2, 0xee, 0x13, 0x62, 0xb1, 0xf3}}
+	return a, nil
+}
+
+var __1528395599_repo_demandUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x8f\xb1\x4a\xc6\x30\x14\x85\xf7\x3c\xc5\x19\x5b\xf8\xdf\xa0\x53\x9a\x5e\xa5\x98\xa6\x92\xc6\xa1\x53\x09\x24\x6a\xd0\xa6\xa5\xbd\xa0\xf8\xf4\x42\x2a\x0e\x6e\x8e\xf7\xe3\xbb\x87\x73\x5a\xba\xef\x4d\x23\x84\xb2\x24\x1d\xc1\xc9\x56\x13\x8e\xb8\x6f\x4b\x88\xab\xcf\x01\x95\x00\x70\x91\x14\x90\x32\xc7\x97\x78\xc0\x8c\x0e\xe6\x49\x6b\x58\xba\x23\x4b\x46\xd1\x54\x9c\x2a\x85\x1a\xa3\x41\x47\x9a\x1c\x41\xc9\x49\xc9\x8e\x6e\x25\xe3\x2d\xe5\x00\x8e\x9f\xfc\xfb\x7d\xf1\xe7\x74\x9c\xbc\x78\x06\xa7\x35\x9e\xec\xd7\x1d\x1f\x89\x5f\xcb\x89\xaf\x2d\xc7\x3f\xfe\xbb\xff\x97\xfe\x68\xfb\x41\xda\x19\x0f\x34\xa3\xfa\xd9\x71\x2b\x65\x6a\x51\x37\x42\xa8\x71\x18\x7a\xd7\x88\xef\x01\x00\x2c\x80\x5f\x9b\x0a\x01\x00\x00")
+
+func _1528395599_repo_demandUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395599_repo_demandUpSql,
+		"1528395599_repo_demand.up.sql",
+	)
+}
+
+func _1528395599_repo_demandUpSql() (*asset, error) {
+	bytes, err := _1528395599_repo_demandUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395599_repo_demand.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x43, 0xfd, 0x88, 0x36, 0x6b, 0x2a, 0xf9, 0x83, 0x87, 0xef, 0x98, 0xa6, 0x74, 0x16, 0x11, 0x4c, 0xef, 0x5e, 0x8b, 0x7b, 0xda, 0x12, 0x99, 0x20, 0xb8, 0x7d, 0x7, 0x12, 0x3, 0x89, 0x5b, 0xd7}}
+	return a, nil
+}
+
+var __1528395600_add_user_mfa_failed_attemptsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x40\x00\xbf\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x75\x73\x65\x72\x5f\x6d\x66\x61\x5f\x66\x61\x69\x6c\x65\x64\x5f\x61\x74\x74\x65\x6d\x70\x74\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xd4\x91\x4b\x4e\x40\x00\x00\x00")
+
+func _1528395600_add_user_mfa_failed_attemptsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395600_add_user_mfa_failed_attemptsDownSql,
+		"1528395600_add_user_mfa_failed_attempts.down.sql",
+	)
+}
+
+func _1528395600_add_user_mfa_failed_attemptsDownSql() (*asset, error) {
+	bytes, err := _1528395600_add_user_mfa_failed_attemptsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395600_add_user_mfa_failed_attempts.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xdf, 0xc7, 0xb6, 0x9, 0x7f, 0x4a, 0xeb, 0xb0, 0x72, 0xa7, 0x7e, 0x9b, 0xaa, 0xa2, 0x8, 0xf0, 0x4c, 0x83, 0x8b, 0x27, 0x47, 0xeb, 0x4e, 0x1e, 0xbe, 0xd9, 0xfb, 0xe3, 0x69, 0x5f, 0x44, 0x58}}
+	return a, nil
+}
+
+var __1528395600_add_user_mfa_failed_attemptsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\xd0\xb1\x6e\xc2\x30\x10\x06\xe0\xdd\x4f\xf1\x8f\x20\xc1\x13\x30\x85\x70\x54\xa8\x21\x54\x21\x0c\x4c\x91\x6b\x5f\xc0\x6a\x62\x47\xf1\xb9\x48\x7d\xfa\xaa\x6e\xc5\xd6\xf1\x74\xbf\xbe\x5f\x77\x5b\x7a\x39\xd4\x1b\xa5\xca\x86\x8a\x96\xd0\x16\xdb\x8a\x90\x22\xcf\xdd\xd8\xeb\xae\xd7\x6e\x60\xdb\x69\x11\x1e\x27\x89\x58\x28\x00\xbf\x6b\x67\xe1\xbc\xf0\x8d\x67\xbc\x35\x87\x63\xd1\x5c\xf1\x4a\x57\x34\xb4\xa7\x86\xea\x92\xce\x39\x16\x17\xce\x2e\x71\xaa\xb1\xa3\x8a\x5a\x42\x59\x9c\xcb\x62\x47\xab\xec\x98\x90\xbc\x3c\x95\xfa\xd4\xa2\xbe\x54\xd5\x0a\xeb\x35\x4c\xf0\x91\x4d\x12\xf7\xc9\x70\xde\x84\x79\x66\x23\x30\xc1\x72\x44\x74\xde\x30\xe4\xce\x18\x74\x14\xc4\x64\x0c\xc7\xd8\xa7\x01\xd1\xdd\xfc\xda\xf9\x8c\x0f\xc1\x7c\xb0\xed\x92\x17\x37\x40\xdc\xc8\x51\xf4\x38\xe1\xe1\xe4\x9e\x47\x7c\x05\xcf\xb9\xcb\x87\x3f\x58\xcf\x0c\x6d\x0c\x4f\xc2\x16\xef\xdc\x87\xf9\xa7\xc6\xc5\x9c\xcf\x68\x9a\xac\x96\xfc\x90\x7f\xc9\xe7\x1d\xd8\xd1\xbe\xb8\x54\x2d\x7c\x78\x2c\x96\x6a\xb9\x51\xaa\x3c\x1d\x8f\x87\x76\xa3\xbe\x07\x00\x0f\x3b\x13\xeb\x75\x01\x00\x00")
+
+func _1528395600_add_user_mfa_failed_attemptsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395600_add_user_mfa_failed_attemptsUpSql,
+		"1528395600_add_user_mfa_failed_attempts.up.sql",
+	)
+}
+
+func _1528395600_add_user_mfa_failed_attemptsUpSql() (*asset, error) {
+	bytes, err := _1528395600_add_user_mfa_failed_attemptsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395600_add_user_mfa_failed_attempts.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x6d, 0x60, 0x12, 0xb4, 0x11, 0x49, 0x3, 0xe7, 0x79, 0xff, 0x2a, 0xdb, 0x90, 0x96, 0x9a, 0x62, 0xd8, 0x60, 0xc3, 0x92, 0x82, 0x4, 0x94, 0x5f, 0x13, 0x5, 0x2a, 0x9, 0xea, 0xc5, 0x14, 0x5b}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -5333,6 +6131,82 @@ var _bindata = map[string]func() (*asset, error){
 	"1528395581_allows_dots_in_usernames.down.sql": _1528395581_allows_dots_in_usernamesDownSql,
 
 	"1528395581_allows_dots_in_usernames.up.sql": _1528395581_allows_dots_in_usernamesUpSql,
+
+	"1528395582_repo_normalized_metadata.down.sql": _1528395582_repo_normalized_metadataDownSql,
+
+	"1528395582_repo_normalized_metadata.up.sql": _1528395582_repo_normalized_metadataUpSql,
+
+	"1528395583_repo_update_attempts.down.sql": _1528395583_repo_update_attemptsDownSql,
+
+	"1528395583_repo_update_attempts.up.sql": _1528395583_repo_update_attemptsUpSql,
+
+	"1528395584_create_user_repo_permissions.down.sql": _1528395584_create_user_repo_permissionsDownSql,
+
+	"1528395584_create_user_repo_permissions.up.sql": _1528395584_create_user_repo_permissionsUpSql,
+
+	"1528395585_create_explicit_repo_permissions.down.sql": _1528395585_create_explicit_repo_permissionsDownSql,
+
+	"1528395585_create_explicit_repo_permissions.up.sql": _1528395585_create_explicit_repo_permissionsUpSql,
+
+	"1528395586_add_access_token_expiry.down.sql": _1528395586_add_access_token_expiryDownSql,
+
+	"1528395586_add_access_token_expiry.up.sql": _1528395586_add_access_token_expiryUpSql,
+
+	"1528395587_add_audit_log.down.sql": _1528395587_add_audit_logDownSql,
+
+	"1528395587_add_audit_log.up.sql": _1528395587_add_audit_logUpSql,
+
+	"1528395588_add_user_mfa.down.sql": _1528395588_add_user_mfaDownSql,
+
+	"1528395588_add_user_mfa.up.sql": _1528395588_add_user_mfaUpSql,
+
+	"1528395589_add_user_sessions.down.sql": _1528395589_add_user_sessionsDownSql,
+
+	"1528395589_add_user_sessions.up.sql": _1528395589_add_user_sessionsUpSql,
+
+	"1528395590_add_user_state.down.sql": _1528395590_add_user_stateDownSql,
+
+	"1528395590_add_user_state.up.sql": _1528395590_add_user_stateUpSql,
+
+	"1528395591_add_saved_search_result_fingerprints.down.sql": _1528395591_add_saved_search_result_fingerprintsDownSql,
+
+	"1528395591_add_saved_search_result_fingerprints.up.sql": _1528395591_add_saved_search_result_fingerprintsUpSql,
+
+	"1528395592_add_saved_search_webhooks.down.sql": _1528395592_add_saved_search_webhooksDownSql,
+
+	"1528395592_add_saved_search_webhooks.up.sql": _1528395592_add_saved_search_webhooksUpSql,
+
+	"1528395593_add_saved_search_subscriptions_and_runs.down.sql": _1528395593_add_saved_search_subscriptions_and_runsDownSql,
+
+	"1528395593_add_saved_search_subscriptions_and_runs.up.sql": _1528395593_add_saved_search_subscriptions_and_runsUpSql,
+
+	"1528395594_add_saved_search_schedules.down.sql": _1528395594_add_saved_search_schedulesDownSql,
+
+	"1528395594_add_saved_search_schedules.up.sql": _1528395594_add_saved_search_schedulesUpSql,
+
+	"1528395595_add_discussion_threads_diff_targets.down.sql": _1528395595_add_discussion_threads_diff_targetsDownSql,
+
+	"1528395595_add_discussion_threads_diff_targets.up.sql": _1528395595_add_discussion_threads_diff_targetsUpSql,
+
+	"1528395596_add_notifications.down.sql": _1528395596_add_notificationsDownSql,
+
+	"1528395596_add_notifications.up.sql": _1528395596_add_notificationsUpSql,
+
+	"1528395597_add_discussions_full_text_indexes.down.sql": _1528395597_add_discussions_full_text_indexesDownSql,
+
+	"1528395597_add_discussions_full_text_indexes.up.sql": _1528395597_add_discussions_full_text_indexesUpSql,
+
+	"1528395598_add_discussion_thread_resolution_assignee_reactions.down.sql": _1528395598_add_discussion_thread_resolution_assignee_reactionsDownSql,
+
+	"1528395598_add_discussion_thread_resolution_assignee_reactions.up.sql": _1528395598_add_discussion_thread_resolution_assignee_reactionsUpSql,
+
+	"1528395599_repo_demand.down.sql": _1528395599_repo_demandDownSql,
+
+	"1528395599_repo_demand.up.sql": _1528395599_repo_demandUpSql,
+
+	"1528395600_add_user_mfa_failed_attempts.down.sql": _1528395600_add_user_mfa_failed_attemptsDownSql,
+
+	"1528395600_add_user_mfa_failed_attempts.up.sql": _1528395600_add_user_mfa_failed_attemptsUpSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -5376,231 +6250,269 @@ type bintree struct {
 }
 
 var _bintree = &bintree{nil, map[string]*bintree{
-	"1503574972_extensions.down.sql":                              {_1503574972_extensionsDownSql, map[string]*bintree{}},
-	"1503574972_extensions.up.sql":                                {_1503574972_extensionsUpSql, map[string]*bintree{}},
-	"1503575261_repos.down.sql":                                   {_1503575261_reposDownSql, map[string]*bintree{}},
-	"1503575261_repos.up.sql":                                     {_1503575261_reposUpSql, map[string]*bintree{}},
-	"1503575588_global_deps.down.sql":                             {_1503575588_global_depsDownSql, map[string]*bintree{}},
-	"1503575588_global_deps.up.sql":                               {_1503575588_global_depsUpSql, map[string]*bintree{}},
-	"1504637681_orgs.down.sql":                                    {_1504637681_orgsDownSql, map[string]*bintree{}},
-	"1504637681_orgs.up.sql":                                      {_1504637681_orgsUpSql, map[string]*bintree{}},
-	"1504821553_add_org_constraints.down.sql":                     {_1504821553_add_org_constraintsDownSql, map[string]*bintree{}},
-	"1504821553_add_org_constraints.up.sql":                       {_1504821553_add_org_constraintsUpSql, map[string]*bintree{}},
-	"1505517457_rename_org_members_columns.down.sql":              {_1505517457_rename_org_members_columnsDownSql, map[string]*bintree{}},
-	"1505517457_rename_org_members_columns.up.sql":                {_1505517457_rename_org_members_columnsUpSql, map[string]*bintree{}},
-	"1505882864_update_org_members.down.sql":                      {_1505882864_update_org_membersDownSql, map[string]*bintree{}},
-	"1505882864_update_org_members.up.sql":                        {_1505882864_update_org_membersUpSql, map[string]*bintree{}},
-	"1506466653_add_users.down.sql":                               {_1506466653_add_usersDownSql, map[string]*bintree{}},
-	"1506466653_add_users.up.sql":                                 {_1506466653_add_usersUpSql, map[string]*bintree{}},
-	"1506646657_alter_orgs_citext.down.sql":                       {_1506646657_alter_orgs_citextDownSql, map[string]*bintree{}},
-	"1506646657_alter_orgs_citext.up.sql":                         {_1506646657_alter_orgs_citextUpSql, map[string]*bintree{}},
-	"1506710237_user_org_constraint_updates.down.sql":             {_1506710237_user_org_constraint_updatesDownSql, map[string]*bintree{}},
-	"1506710237_user_org_constraint_updates.up.sql":               {_1506710237_user_org_constraint_updatesUpSql, map[string]*bintree{}},
-	"1506989402_add_tags.down.sql":                                {_1506989402_add_tagsDownSql, map[string]*bintree{}},
-	"1506989402_add_tags.up.sql":                                  {_1506989402_add_tagsUpSql, map[string]*bintree{}},
-	"1507422179_remove_org_members_columns.down.sql":              {_1507422179_remove_org_members_columnsDownSql, map[string]*bintree{}},
-	"1507422179_remove_org_members_columns.up.sql":                {_1507422179_remove_org_members_columnsUpSql, map[string]*bintree{}},
-	"1507656459_add_org_settings.down.sql":                        {_1507656459_add_org_settingsDownSql, map[string]*bintree{}},
-	"1507656459_add_org_settings.up.sql":                          {_1507656459_add_org_settingsUpSql, map[string]*bintree{}},
-	"1507755085_add_editor_beta_tags.down.sql":                    {_1507755085_add_editor_beta_tagsDownSql, map[string]*bintree{}},
-	"1507755085_add_editor_beta_tags.up.sql":                      {_1507755085_add_editor_beta_tagsUpSql, map[string]*bintree{}},
-	"1507828928_add-slack-webhook-url.down.sql":                   {_1507828928_addSlackWebhookUrlDownSql, map[string]*bintree{}},
-	"1507828928_add-slack-webhook-url.up.sql":                     {_1507828928_addSlackWebhookUrlUpSql, map[string]*bintree{}},
-	"1508361685_add_phabricator_repos.down.sql":                   {_1508361685_add_phabricator_reposDownSql, map[string]*bintree{}},
-	"1508361685_add_phabricator_repos.up.sql":                     {_1508361685_add_phabricator_reposUpSql, map[string]*bintree{}},
-	"1508795218_update_constraints.down.sql":                      {_1508795218_update_constraintsDownSql, map[string]*bintree{}},
-	"1508795218_update_constraints.up.sql":                        {_1508795218_update_constraintsUpSql, map[string]*bintree{}},
-	"1509599098_add-users-provider-column.down.sql":               {_1509599098_addUsersProviderColumnDownSql, map[string]*bintree{}},
-	"1509599098_add-users-provider-column.up.sql":                 {_1509599098_addUsersProviderColumnUpSql, map[string]*bintree{}},
-	"1509645961_rename-users-auth0_id-to-uid.down.sql":            {_1509645961_renameUsersAuth0_idToUidDownSql, map[string]*bintree{}},
-	"1509645961_rename-users-auth0_id-to-uid.up.sql":              {_1509645961_renameUsersAuth0_idToUidUpSql, map[string]*bintree{}},
-	"1510709195_add_server_user_events_table.down.sql":            {_1510709195_add_server_user_events_tableDownSql, map[string]*bintree{}},
-	"1510709195_add_server_user_events_table.up.sql":              {_1510709195_add_server_user_events_tableUpSql, map[string]*bintree{}},
-	"1511004249_generalize_org_settings.down.sql":                 {_1511004249_generalize_org_settingsDownSql, map[string]*bintree{}},
-	"1511004249_generalize_org_settings.up.sql":                   {_1511004249_generalize_org_settingsUpSql, map[string]*bintree{}},
-	"1511011666_add_user_settings.down.sql":                       {_1511011666_add_user_settingsDownSql, map[string]*bintree{}},
-	"1511011666_add_user_settings.up.sql":                         {_1511011666_add_user_settingsUpSql, map[string]*bintree{}},
-	"1511365156_pkgs_and_global_dep_to_repo_foreign_key.down.sql": {_1511365156_pkgs_and_global_dep_to_repo_foreign_keyDownSql, map[string]*bintree{}},
-	"1511365156_pkgs_and_global_dep_to_repo_foreign_key.up.sql":   {_1511365156_pkgs_and_global_dep_to_repo_foreign_keyUpSql, map[string]*bintree{}},
-	"1511852763_user_invite_quota.down.sql":                       {_1511852763_user_invite_quotaDownSql, map[string]*bintree{}},
-	"1511852763_user_invite_quota.up.sql":                         {_1511852763_user_invite_quotaUpSql, map[string]*bintree{}},
-	"1512437090_update_phabricator_repos.down.sql":                {_1512437090_update_phabricator_reposDownSql, map[string]*bintree{}},
-	"1512437090_update_phabricator_repos.up.sql":                  {_1512437090_update_phabricator_reposUpSql, map[string]*bintree{}},
-	"1512998571_repo_nullable.down.sql":                           {_1512998571_repo_nullableDownSql, map[string]*bintree{}},
-	"1512998571_repo_nullable.up.sql":                             {_1512998571_repo_nullableUpSql, map[string]*bintree{}},
-	"1513000124_rm_repos_cols.down.sql":                           {_1513000124_rm_repos_colsDownSql, map[string]*bintree{}},
-	"1513000124_rm_repos_cols.up.sql":                             {_1513000124_rm_repos_colsUpSql, map[string]*bintree{}},
-	"1513188842_add_app_config_table.down.sql":                    {_1513188842_add_app_config_tableDownSql, map[string]*bintree{}},
-	"1513188842_add_app_config_table.up.sql":                      {_1513188842_add_app_config_tableUpSql, map[string]*bintree{}},
-	"1513578663_user-passwords.down.sql":                          {_1513578663_userPasswordsDownSql, map[string]*bintree{}},
-	"1513578663_user-passwords.up.sql":                            {_1513578663_userPasswordsUpSql, map[string]*bintree{}},
-	"1513800341_update_username_orgname_regex.down.sql":           {_1513800341_update_username_orgname_regexDownSql, map[string]*bintree{}},
-	"1513800341_update_username_orgname_regex.up.sql":             {_1513800341_update_username_orgname_regexUpSql, map[string]*bintree{}},
-	"1514312401_add_site_admin_column_to_users.down.sql":          {_1514312401_add_site_admin_column_to_usersDownSql, map[string]*bintree{}},
-	"1514312401_add_site_admin_column_to_users.up.sql":            {_1514312401_add_site_admin_column_to_usersUpSql, map[string]*bintree{}},
-	"1514534085_add_orgs_deleted_at.down.sql":                     {_1514534085_add_orgs_deleted_atDownSql, map[string]*bintree{}},
-	"1514534085_add_orgs_deleted_at.up.sql":                       {_1514534085_add_orgs_deleted_atUpSql, map[string]*bintree{}},
-	"1514536731_add_org_members_user_fkey.down.sql":               {_1514536731_add_org_members_user_fkeyDownSql, map[string]*bintree{}},
-	"1514536731_add_org_members_user_fkey.up.sql":                 {_1514536731_add_org_members_user_fkeyUpSql, map[string]*bintree{}},
-	"1514691735_rename_deployment_configuration.down.sql":         {_1514691735_rename_deployment_configurationDownSql, map[string]*bintree{}},
-	"1514691735_rename_deployment_configuration.up.sql":           {_1514691735_rename_deployment_configurationUpSql, map[string]*bintree{}},
-	"1514693059_user_emails_table.down.sql":                       {_1514693059_user_emails_tableDownSql, map[string]*bintree{}},
-	"1514693059_user_emails_table.up.sql":                         {_1514693059_user_emails_tableUpSql, map[string]*bintree{}},
-	"1514702776_add_settings_user_fkey.down.sql":                  {_1514702776_add_settings_user_fkeyDownSql, map[string]*bintree{}},
-	"1514702776_add_settings_user_fkey.up.sql":                    {_1514702776_add_settings_user_fkeyUpSql, map[string]*bintree{}},
-	"1514713044_rename_users_auth_id_to_external_id.down.sql":     {_1514713044_rename_users_auth_id_to_external_idDownSql, map[string]*bintree{}},
-	"1514713044_rename_users_auth_id_to_external_id.up.sql":       {_1514713044_rename_users_auth_id_to_external_idUpSql, map[string]*bintree{}},
-	"1514714572_external_provider.down.sql":                       {_1514714572_external_providerDownSql, map[string]*bintree{}},
-	"1514714572_external_provider.up.sql":                         {_1514714572_external_providerUpSql, map[string]*bintree{}},
-	"1514718560_external_provider_and_id.down.sql":                {_1514718560_external_provider_and_idDownSql, map[string]*bintree{}},
-	"1514718560_external_provider_and_id.up.sql":                  {_1514718560_external_provider_and_idUpSql, map[string]*bintree{}},
-	"1514876826_site_id.down.sql":                                 {_1514876826_site_idDownSql, map[string]*bintree{}},
-	"1514876826_site_id.up.sql":                                   {_1514876826_site_idUpSql, map[string]*bintree{}},
-	"1514937919_remove_user_activity_table.down.sql":              {_1514937919_remove_user_activity_tableDownSql, map[string]*bintree{}},
-	"1514937919_remove_user_activity_table.up.sql":                {_1514937919_remove_user_activity_tableUpSql, map[string]*bintree{}},
-	"1515125883_repo_blocked_to_enabled.down.sql":                 {_1515125883_repo_blocked_to_enabledDownSql, map[string]*bintree{}},
-	"1515125883_repo_blocked_to_enabled.up.sql":                   {_1515125883_repo_blocked_to_enabledUpSql, map[string]*bintree{}},
-	"1515651962_drop_has_subject_constraint.down.sql":             {_1515651962_drop_has_subject_constraintDownSql, map[string]*bintree{}},
-	"1515651962_drop_has_subject_constraint.up.sql":               {_1515651962_drop_has_subject_constraintUpSql, map[string]*bintree{}},
-	"1516491388_remove_repo_private.down.sql":                     {_1516491388_remove_repo_privateDownSql, map[string]*bintree{}},
-	"1516491388_remove_repo_private.up.sql":                       {_1516491388_remove_repo_privateUpSql, map[string]*bintree{}},
-	"1516608575_repo_cleanup.down.sql":                            {_1516608575_repo_cleanupDownSql, map[string]*bintree{}},
-	"1516608575_repo_cleanup.up.sql":                              {_1516608575_repo_cleanupUpSql, map[string]*bintree{}},
-	"1516834731_add_saved_queries.down.sql":                       {_1516834731_add_saved_queriesDownSql, map[string]*bintree{}},
-	"1516834731_add_saved_queries.up.sql":                         {_1516834731_add_saved_queriesUpSql, map[string]*bintree{}},
-	"1517129075_repo_external.down.sql":                           {_1517129075_repo_externalDownSql, map[string]*bintree{}},
-	"1517129075_repo_external.up.sql":                             {_1517129075_repo_externalUpSql, map[string]*bintree{}},
-	"1518102181_cert_cache.down.sql":                              {_1518102181_cert_cacheDownSql, map[string]*bintree{}},
-	"1518102181_cert_cache.up.sql":                                {_1518102181_cert_cacheUpSql, map[string]*bintree{}},
-	"1518581786_remove_site_config_telemetry.down.sql":            {_1518581786_remove_site_config_telemetryDownSql, map[string]*bintree{}},
-	"1518581786_remove_site_config_telemetry.up.sql":              {_1518581786_remove_site_config_telemetryUpSql, map[string]*bintree{}},
-	"1518581860_add_site_config_initialized.down.sql":             {_1518581860_add_site_config_initializedDownSql, map[string]*bintree{}},
-	"1518581860_add_site_config_initialized.up.sql":               {_1518581860_add_site_config_initializedUpSql, map[string]*bintree{}},
-	"1519507899_drop_global_dep_private.down.sql":                 {_1519507899_drop_global_dep_privateDownSql, map[string]*bintree{}},
-	"1519507899_drop_global_dep_private.up.sql":                   {_1519507899_drop_global_dep_privateUpSql, map[string]*bintree{}},
-	"1520588597_user_emails_unique_verified_only.down.sql":        {_1520588597_user_emails_unique_verified_onlyDownSql, map[string]*bintree{}},
-	"1520588597_user_emails_unique_verified_only.up.sql":          {_1520588597_user_emails_unique_verified_onlyUpSql, map[string]*bintree{}},
-	"1520708880_users_display_name_nullable.down.sql":             {_1520708880_users_display_name_nullableDownSql, map[string]*bintree{}},
-	"1520708880_users_display_name_nullable.up.sql":               {_1520708880_users_display_name_nullableUpSql, map[string]*bintree{}},
-	"1522555179_create_access_tokens_table.down.sql":              {_1522555179_create_access_tokens_tableDownSql, map[string]*bintree{}},
-	"1522555179_create_access_tokens_table.up.sql":                {_1522555179_create_access_tokens_tableUpSql, map[string]*bintree{}},
-	"1522961518_create_survey_responses_table.down.sql":           {_1522961518_create_survey_responses_tableDownSql, map[string]*bintree{}},
-	"1522961518_create_survey_responses_table.up.sql":             {_1522961518_create_survey_responses_tableUpSql, map[string]*bintree{}},
-	"1524535307_remove_survey_responses_updated_at.down.sql":      {_1524535307_remove_survey_responses_updated_atDownSql, map[string]*bintree{}},
-	"1524535307_remove_survey_responses_updated_at.up.sql":        {_1524535307_remove_survey_responses_updated_atUpSql, map[string]*bintree{}},
-	"1524724144_add_access_tokens_fields.down.sql":                {_1524724144_add_access_tokens_fieldsDownSql, map[string]*bintree{}},
-	"1524724144_add_access_tokens_fields.up.sql":                  {_1524724144_add_access_tokens_fieldsUpSql, map[string]*bintree{}},
-	"1524942857_trim_site_config.down.sql":                        {_1524942857_trim_site_configDownSql, map[string]*bintree{}},
-	"1524942857_trim_site_config.up.sql":                          {_1524942857_trim_site_configUpSql, map[string]*bintree{}},
-	"1524949295_simplify_initialization.down.sql":                 {_1524949295_simplify_initializationDownSql, map[string]*bintree{}},
-	"1524949295_simplify_initialization.up.sql":                   {_1524949295_simplify_initializationUpSql, map[string]*bintree{}},
-	"1525150355_add_access_tokens_scopes.down.sql":                {_1525150355_add_access_tokens_scopesDownSql, map[string]*bintree{}},
-	"1525150355_add_access_tokens_scopes.up.sql":                  {_1525150355_add_access_tokens_scopesUpSql, map[string]*bintree{}},
-	"1525961108_user_unique_among_non-deleted.down.sql":           {_1525961108_user_unique_among_nonDeletedDownSql, map[string]*bintree{}},
-	"1525961108_user_unique_among_non-deleted.up.sql":             {_1525961108_user_unique_among_nonDeletedUpSql, map[string]*bintree{}},
-	"1526364839_user_multiple_external_accounts.down.sql":         {_1526364839_user_multiple_external_accountsDownSql, map[string]*bintree{}},
-	"1526364839_user_multiple_external_accounts.up.sql":           {_1526364839_user_multiple_external_accountsUpSql, map[string]*bintree{}},
-	"1526804768_add_external_account_client.down.sql":             {_1526804768_add_external_account_clientDownSql, map[string]*bintree{}},
-	"1526804768_add_external_account_client.up.sql":               {_1526804768_add_external_account_clientUpSql, map[string]*bintree{}},
-	"1527691234_reuse_org_name.down.sql":                          {_1527691234_reuse_org_nameDownSql, map[string]*bintree{}},
-	"1527691234_reuse_org_name.up.sql":                            {_1527691234_reuse_org_nameUpSql, map[string]*bintree{}},
-	"1528179233_drop_code_comments_tables.down.sql":               {_1528179233_drop_code_comments_tablesDownSql, map[string]*bintree{}},
-	"1528179233_drop_code_comments_tables.up.sql":                 {_1528179233_drop_code_comments_tablesUpSql, map[string]*bintree{}},
-	"1528277031_create_org_invitations_table.down.sql":            {_1528277031_create_org_invitations_tableDownSql, map[string]*bintree{}},
-	"1528277031_create_org_invitations_table.up.sql":              {_1528277031_create_org_invitations_tableUpSql, map[string]*bintree{}},
-	"1528395534_.down.sql":                                        {_1528395534_DownSql, map[string]*bintree{}},
-	"1528395534_.up.sql":                                          {_1528395534_UpSql, map[string]*bintree{}},
-	"1528395535_.down.sql":                                        {_1528395535_DownSql, map[string]*bintree{}},
-	"1528395535_.up.sql":                                          {_1528395535_UpSql, map[string]*bintree{}},
-	"1528395536_.down.sql":                                        {_1528395536_DownSql, map[string]*bintree{}},
-	"1528395536_.up.sql":                                          {_1528395536_UpSql, map[string]*bintree{}},
-	"1528395537_.down.sql":                                        {_1528395537_DownSql, map[string]*bintree{}},
-	"1528395537_.up.sql":                                          {_1528395537_UpSql, map[string]*bintree{}},
-	"1528395538_.down.sql":                                        {_1528395538_DownSql, map[string]*bintree{}},
-	"1528395538_.up.sql":                                          {_1528395538_UpSql, map[string]*bintree{}},
-	"1528395539_.down.sql":                                        {_1528395539_DownSql, map[string]*bintree{}},
-	"1528395539_.up.sql":                                          {_1528395539_UpSql, map[string]*bintree{}},
-	"1528395540_.down.sql":                                        {_1528395540_DownSql, map[string]*bintree{}},
-	"1528395540_.up.sql":                                          {_1528395540_UpSql, map[string]*bintree{}},
-	"1528395541_.down.sql":                                        {_1528395541_DownSql, map[string]*bintree{}},
-	"1528395541_.up.sql":                                          {_1528395541_UpSql, map[string]*bintree{}},
-	"1528395542_.down.sql":                                        {_1528395542_DownSql, map[string]*bintree{}},
-	"1528395542_.up.sql":                                          {_1528395542_UpSql, map[string]*bintree{}},
-	"1528395544_.down.sql":                                        {_1528395544_DownSql, map[string]*bintree{}},
-	"1528395544_.up.sql":                                          {_1528395544_UpSql, map[string]*bintree{}},
-	"1528395545_.down.sql":                                        {_1528395545_DownSql, map[string]*bintree{}},
-	"1528395545_.up.sql":                                          {_1528395545_UpSql, map[string]*bintree{}},
-	"1528395546_.down.sql":                                        {_1528395546_DownSql, map[string]*bintree{}},
-	"1528395546_.up.sql":                                          {_1528395546_UpSql, map[string]*bintree{}},
-	"1528395547_.down.sql":                                        {_1528395547_DownSql, map[string]*bintree{}},
-	"1528395547_.up.sql":                                          {_1528395547_UpSql, map[string]*bintree{}},
-	"1528395548_.down.sql":                                        {_1528395548_DownSql, map[string]*bintree{}},
-	"1528395548_.up.sql":                                          {_1528395548_UpSql, map[string]*bintree{}},
-	"1528395549_.down.sql":                                        {_1528395549_DownSql, map[string]*bintree{}},
-	"1528395549_.up.sql":                                          {_1528395549_UpSql, map[string]*bintree{}},
-	"1528395550_.down.sql":                                        {_1528395550_DownSql, map[string]*bintree{}},
-	"1528395550_.up.sql":                                          {_1528395550_UpSql, map[string]*bintree{}},
-	"1528395551_.down.sql":                                        {_1528395551_DownSql, map[string]*bintree{}},
-	"1528395551_.up.sql":                                          {_1528395551_UpSql, map[string]*bintree{}},
-	"1528395552_.down.sql":                                        {_1528395552_DownSql, map[string]*bintree{}},
-	"1528395552_.up.sql":                                          {_1528395552_UpSql, map[string]*bintree{}},
-	"1528395553_.down.sql":                                        {_1528395553_DownSql, map[string]*bintree{}},
-	"1528395553_.up.sql":                                          {_1528395553_UpSql, map[string]*bintree{}},
-	"1528395554_oss_fake_migration.down.sql":                      {_1528395554_oss_fake_migrationDownSql, map[string]*bintree{}},
-	"1528395554_oss_fake_migration.up.sql":                        {_1528395554_oss_fake_migrationUpSql, map[string]*bintree{}},
-	"1528395555_.down.sql":                                        {_1528395555_DownSql, map[string]*bintree{}},
-	"1528395555_.up.sql":                                          {_1528395555_UpSql, map[string]*bintree{}},
-	"1528395556_.down.sql":                                        {_1528395556_DownSql, map[string]*bintree{}},
-	"1528395556_.up.sql":                                          {_1528395556_UpSql, map[string]*bintree{}},
-	"1528395557_.down.sql":                                        {_1528395557_DownSql, map[string]*bintree{}},
-	"1528395557_.up.sql":                                          {_1528395557_UpSql, map[string]*bintree{}},
-	"1528395558_.down.sql":                                        {_1528395558_DownSql, map[string]*bintree{}},
-	"1528395558_.up.sql":                                          {_1528395558_UpSql, map[string]*bintree{}},
-	"1528395559_.down.sql":                                        {_1528395559_DownSql, map[string]*bintree{}},
-	"1528395559_.up.sql":                                          {_1528395559_UpSql, map[string]*bintree{}},
-	"1528395560_.down.sql":                                        {_1528395560_DownSql, map[string]*bintree{}},
-	"1528395560_.up.sql":                                          {_1528395560_UpSql, map[string]*bintree{}},
-	"1528395561_.down.sql":                                        {_1528395561_DownSql, map[string]*bintree{}},
-	"1528395561_.up.sql":                                          {_1528395561_UpSql, map[string]*bintree{}},
-	"1528395562_.down.sql":                                        {_1528395562_DownSql, map[string]*bintree{}},
-	"1528395562_.up.sql":                                          {_1528395562_UpSql, map[string]*bintree{}},
-	"1528395563_.down.sql":                                        {_1528395563_DownSql, map[string]*bintree{}},
-	"1528395563_.up.sql":                                          {_1528395563_UpSql, map[string]*bintree{}},
-	"1528395564_.down.sql":                                        {_1528395564_DownSql, map[string]*bintree{}},
-	"1528395564_.up.sql":                                          {_1528395564_UpSql, map[string]*bintree{}},
-	"1528395565_.down.sql":                                        {_1528395565_DownSql, map[string]*bintree{}},
-	"1528395565_.up.sql":                                          {_1528395565_UpSql, map[string]*bintree{}},
-	"1528395566_.down.sql":                                        {_1528395566_DownSql, map[string]*bintree{}},
-	"1528395566_.up.sql":                                          {_1528395566_UpSql, map[string]*bintree{}},
-	"1528395567_.down.sql":                                        {_1528395567_DownSql, map[string]*bintree{}},
-	"1528395567_.up.sql":                                          {_1528395567_UpSql, map[string]*bintree{}},
-	"1528395568_.down.sql":                                        {_1528395568_DownSql, map[string]*bintree{}},
-	"1528395568_.up.sql":                                          {_1528395568_UpSql, map[string]*bintree{}},
-	"1528395569_.down.sql":                                        {_1528395569_DownSql, map[string]*bintree{}},
-	"1528395569_.up.sql":                                          {_1528395569_UpSql, map[string]*bintree{}},
-	"1528395570_.down.sql":                                        {_1528395570_DownSql, map[string]*bintree{}},
-	"1528395570_.up.sql":                                          {_1528395570_UpSql, map[string]*bintree{}},
-	"1528395571_.down.sql":                                        {_1528395571_DownSql, map[string]*bintree{}},
-	"1528395571_.up.sql":                                          {_1528395571_UpSql, map[string]*bintree{}},
-	"1528395572_.down.sql":                                        {_1528395572_DownSql, map[string]*bintree{}},
-	"1528395572_.up.sql":                                          {_1528395572_UpSql, map[string]*bintree{}},
-	"1528395573_recent_searches.down.sql":                         {_1528395573_recent_searchesDownSql, map[string]*bintree{}},
-	"1528395573_recent_searches.up.sql":                           {_1528395573_recent_searchesUpSql, map[string]*bintree{}},
-	"1528395574_.down.sql":                                        {_1528395574_DownSql, map[string]*bintree{}},
-	"1528395574_.up.sql":                                          {_1528395574_UpSql, map[string]*bintree{}},
-	"1528395575_.down.sql":                                        {_1528395575_DownSql, map[string]*bintree{}},
-	"1528395575_.up.sql":                                          {_1528395575_UpSql, map[string]*bintree{}},
-	"1528395576_.down.sql":                                        {_1528395576_DownSql, map[string]*bintree{}},
-	"1528395576_.up.sql":                                          {_1528395576_UpSql, map[string]*bintree{}},
-	"1528395577_.up.sql":                                          {_1528395577_UpSql, map[string]*bintree{}},
-	"1528395578_.down.sql":                                        {_1528395578_DownSql, map[string]*bintree{}},
-	"1528395578_.up.sql":                                          {_1528395578_UpSql, map[string]*bintree{}},
-	"1528395579_.down.sql":                                        {_1528395579_DownSql, map[string]*bintree{}},
-	"1528395579_.up.sql":                                          {_1528395579_UpSql, map[string]*bintree{}},
-	"1528395580_create_user_permissions_table.down.sql":           {_1528395580_create_user_permissions_tableDownSql, map[string]*bintree{}},
-	"1528395580_create_user_permissions_table.up.sql":             {_1528395580_create_user_permissions_tableUpSql, map[string]*bintree{}},
-	"1528395581_allows_dots_in_usernames.down.sql":                {_1528395581_allows_dots_in_usernamesDownSql, map[string]*bintree{}},
-	"1528395581_allows_dots_in_usernames.up.sql":                  {_1528395581_allows_dots_in_usernamesUpSql, map[string]*bintree{}},
+	"1503574972_extensions.down.sql":                                          {_1503574972_extensionsDownSql, map[string]*bintree{}},
+	"1503574972_extensions.up.sql":                                            {_1503574972_extensionsUpSql, map[string]*bintree{}},
+	"1503575261_repos.down.sql":                                               {_1503575261_reposDownSql, map[string]*bintree{}},
+	"1503575261_repos.up.sql":                                                 {_1503575261_reposUpSql, map[string]*bintree{}},
+	"1503575588_global_deps.down.sql":                                         {_1503575588_global_depsDownSql, map[string]*bintree{}},
+	"1503575588_global_deps.up.sql":                                           {_1503575588_global_depsUpSql, map[string]*bintree{}},
+	"1504637681_orgs.down.sql":                                                {_1504637681_orgsDownSql, map[string]*bintree{}},
+	"1504637681_orgs.up.sql":                                                  {_1504637681_orgsUpSql, map[string]*bintree{}},
+	"1504821553_add_org_constraints.down.sql":                                 {_1504821553_add_org_constraintsDownSql, map[string]*bintree{}},
+	"1504821553_add_org_constraints.up.sql":                                   {_1504821553_add_org_constraintsUpSql, map[string]*bintree{}},
+	"1505517457_rename_org_members_columns.down.sql":                          {_1505517457_rename_org_members_columnsDownSql, map[string]*bintree{}},
+	"1505517457_rename_org_members_columns.up.sql":                            {_1505517457_rename_org_members_columnsUpSql, map[string]*bintree{}},
+	"1505882864_update_org_members.down.sql":                                  {_1505882864_update_org_membersDownSql, map[string]*bintree{}},
+	"1505882864_update_org_members.up.sql":                                    {_1505882864_update_org_membersUpSql, map[string]*bintree{}},
+	"1506466653_add_users.down.sql":                                           {_1506466653_add_usersDownSql, map[string]*bintree{}},
+	"1506466653_add_users.up.sql":                                             {_1506466653_add_usersUpSql, map[string]*bintree{}},
+	"1506646657_alter_orgs_citext.down.sql":                                   {_1506646657_alter_orgs_citextDownSql, map[string]*bintree{}},
+	"1506646657_alter_orgs_citext.up.sql":                                     {_1506646657_alter_orgs_citextUpSql, map[string]*bintree{}},
+	"1506710237_user_org_constraint_updates.down.sql":                         {_1506710237_user_org_constraint_updatesDownSql, map[string]*bintree{}},
+	"1506710237_user_org_constraint_updates.up.sql":                           {_1506710237_user_org_constraint_updatesUpSql, map[string]*bintree{}},
+	"1506989402_add_tags.down.sql":                                            {_1506989402_add_tagsDownSql, map[string]*bintree{}},
+	"1506989402_add_tags.up.sql":                                              {_1506989402_add_tagsUpSql, map[string]*bintree{}},
+	"1507422179_remove_org_members_columns.down.sql":                          {_1507422179_remove_org_members_columnsDownSql, map[string]*bintree{}},
+	"1507422179_remove_org_members_columns.up.sql":                            {_1507422179_remove_org_members_columnsUpSql, map[string]*bintree{}},
+	"1507656459_add_org_settings.down.sql":                                    {_1507656459_add_org_settingsDownSql, map[string]*bintree{}},
+	"1507656459_add_org_settings.up.sql":                                      {_1507656459_add_org_settingsUpSql, map[string]*bintree{}},
+	"1507755085_add_editor_beta_tags.down.sql":                                {_1507755085_add_editor_beta_tagsDownSql, map[string]*bintree{}},
+	"1507755085_add_editor_beta_tags.up.sql":                                  {_1507755085_add_editor_beta_tagsUpSql, map[string]*bintree{}},
+	"1507828928_add-slack-webhook-url.down.sql":                               {_1507828928_addSlackWebhookUrlDownSql, map[string]*bintree{}},
+	"1507828928_add-slack-webhook-url.up.sql":                                 {_1507828928_addSlackWebhookUrlUpSql, map[string]*bintree{}},
+	"1508361685_add_phabricator_repos.down.sql":                               {_1508361685_add_phabricator_reposDownSql, map[string]*bintree{}},
+	"1508361685_add_phabricator_repos.up.sql":                                 {_1508361685_add_phabricator_reposUpSql, map[string]*bintree{}},
+	"1508795218_update_constraints.down.sql":                                  {_1508795218_update_constraintsDownSql, map[string]*bintree{}},
+	"1508795218_update_constraints.up.sql":                                    {_1508795218_update_constraintsUpSql, map[string]*bintree{}},
+	"1509599098_add-users-provider-column.down.sql":                           {_1509599098_addUsersProviderColumnDownSql, map[string]*bintree{}},
+	"1509599098_add-users-provider-column.up.sql":                             {_1509599098_addUsersProviderColumnUpSql, map[string]*bintree{}},
+	"1509645961_rename-users-auth0_id-to-uid.down.sql":                        {_1509645961_renameUsersAuth0_idToUidDownSql, map[string]*bintree{}},
+	"1509645961_rename-users-auth0_id-to-uid.up.sql":                          {_1509645961_renameUsersAuth0_idToUidUpSql, map[string]*bintree{}},
+	"1510709195_add_server_user_events_table.down.sql":                        {_1510709195_add_server_user_events_tableDownSql, map[string]*bintree{}},
+	"1510709195_add_server_user_events_table.up.sql":                          {_1510709195_add_server_user_events_tableUpSql, map[string]*bintree{}},
+	"1511004249_generalize_org_settings.down.sql":                             {_1511004249_generalize_org_settingsDownSql, map[string]*bintree{}},
+	"1511004249_generalize_org_settings.up.sql":                               {_1511004249_generalize_org_settingsUpSql, map[string]*bintree{}},
+	"1511011666_add_user_settings.down.sql":                                   {_1511011666_add_user_settingsDownSql, map[string]*bintree{}},
+	"1511011666_add_user_settings.up.sql":                                     {_1511011666_add_user_settingsUpSql, map[string]*bintree{}},
+	"1511365156_pkgs_and_global_dep_to_repo_foreign_key.down.sql":             {_1511365156_pkgs_and_global_dep_to_repo_foreign_keyDownSql, map[string]*bintree{}},
+	"1511365156_pkgs_and_global_dep_to_repo_foreign_key.up.sql":               {_1511365156_pkgs_and_global_dep_to_repo_foreign_keyUpSql, map[string]*bintree{}},
+	"1511852763_user_invite_quota.down.sql":                                   {_1511852763_user_invite_quotaDownSql, map[string]*bintree{}},
+	"1511852763_user_invite_quota.up.sql":                                     {_1511852763_user_invite_quotaUpSql, map[string]*bintree{}},
+	"1512437090_update_phabricator_repos.down.sql":                            {_1512437090_update_phabricator_reposDownSql, map[string]*bintree{}},
+	"1512437090_update_phabricator_repos.up.sql":                              {_1512437090_update_phabricator_reposUpSql, map[string]*bintree{}},
+	"1512998571_repo_nullable.down.sql":                                       {_1512998571_repo_nullableDownSql, map[string]*bintree{}},
+	"1512998571_repo_nullable.up.sql":                                         {_1512998571_repo_nullableUpSql, map[string]*bintree{}},
+	"1513000124_rm_repos_cols.down.sql":                                       {_1513000124_rm_repos_colsDownSql, map[string]*bintree{}},
+	"1513000124_rm_repos_cols.up.sql":                                         {_1513000124_rm_repos_colsUpSql, map[string]*bintree{}},
+	"1513188842_add_app_config_table.down.sql":                                {_1513188842_add_app_config_tableDownSql, map[string]*bintree{}},
+	"1513188842_add_app_config_table.up.sql":                                  {_1513188842_add_app_config_tableUpSql, map[string]*bintree{}},
+	"1513578663_user-passwords.down.sql":                                      {_1513578663_userPasswordsDownSql, map[string]*bintree{}},
+	"1513578663_user-passwords.up.sql":                                        {_1513578663_userPasswordsUpSql, map[string]*bintree{}},
+	"1513800341_update_username_orgname_regex.down.sql":                       {_1513800341_update_username_orgname_regexDownSql, map[string]*bintree{}},
+	"1513800341_update_username_orgname_regex.up.sql":                         {_1513800341_update_username_orgname_regexUpSql, map[string]*bintree{}},
+	"1514312401_add_site_admin_column_to_users.down.sql":                      {_1514312401_add_site_admin_column_to_usersDownSql, map[string]*bintree{}},
+	"1514312401_add_site_admin_column_to_users.up.sql":                        {_1514312401_add_site_admin_column_to_usersUpSql, map[string]*bintree{}},
+	"1514534085_add_orgs_deleted_at.down.sql":                                 {_1514534085_add_orgs_deleted_atDownSql, map[string]*bintree{}},
+	"1514534085_add_orgs_deleted_at.up.sql":                                   {_1514534085_add_orgs_deleted_atUpSql, map[string]*bintree{}},
+	"1514536731_add_org_members_user_fkey.down.sql":                           {_1514536731_add_org_members_user_fkeyDownSql, map[string]*bintree{}},
+	"1514536731_add_org_members_user_fkey.up.sql":                             {_1514536731_add_org_members_user_fkeyUpSql, map[string]*bintree{}},
+	"1514691735_rename_deployment_configuration.down.sql":                     {_1514691735_rename_deployment_configurationDownSql, map[string]*bintree{}},
+	"1514691735_rename_deployment_configuration.up.sql":                       {_1514691735_rename_deployment_configurationUpSql, map[string]*bintree{}},
+	"1514693059_user_emails_table.down.sql":                                   {_1514693059_user_emails_tableDownSql, map[string]*bintree{}},
+	"1514693059_user_emails_table.up.sql":                                     {_1514693059_user_emails_tableUpSql, map[string]*bintree{}},
+	"1514702776_add_settings_user_fkey.down.sql":                              {_1514702776_add_settings_user_fkeyDownSql, map[string]*bintree{}},
+	"1514702776_add_settings_user_fkey.up.sql":                                {_1514702776_add_settings_user_fkeyUpSql, map[string]*bintree{}},
+	"1514713044_rename_users_auth_id_to_external_id.down.sql":                 {_1514713044_rename_users_auth_id_to_external_idDownSql, map[string]*bintree{}},
+	"1514713044_rename_users_auth_id_to_external_id.up.sql":                   {_1514713044_rename_users_auth_id_to_external_idUpSql, map[string]*bintree{}},
+	"1514714572_external_provider.down.sql":                                   {_1514714572_external_providerDownSql, map[string]*bintree{}},
+	"1514714572_external_provider.up.sql":                                     {_1514714572_external_providerUpSql, map[string]*bintree{}},
+	"1514718560_external_provider_and_id.down.sql":                            {_1514718560_external_provider_and_idDownSql, map[string]*bintree{}},
+	"1514718560_external_provider_and_id.up.sql":                              {_1514718560_external_provider_and_idUpSql, map[string]*bintree{}},
+	"1514876826_site_id.down.sql":                                             {_1514876826_site_idDownSql, map[string]*bintree{}},
+	"1514876826_site_id.up.sql":                                               {_1514876826_site_idUpSql, map[string]*bintree{}},
+	"1514937919_remove_user_activity_table.down.sql":                          {_1514937919_remove_user_activity_tableDownSql, map[string]*bintree{}},
+	"1514937919_remove_user_activity_table.up.sql":                            {_1514937919_remove_user_activity_tableUpSql, map[string]*bintree{}},
+	"1515125883_repo_blocked_to_enabled.down.sql":                             {_1515125883_repo_blocked_to_enabledDownSql, map[string]*bintree{}},
+	"1515125883_repo_blocked_to_enabled.up.sql":                               {_1515125883_repo_blocked_to_enabledUpSql, map[string]*bintree{}},
+	"1515651962_drop_has_subject_constraint.down.sql":                         {_1515651962_drop_has_subject_constraintDownSql, map[string]*bintree{}},
+	"1515651962_drop_has_subject_constraint.up.sql":                           {_1515651962_drop_has_subject_constraintUpSql, map[string]*bintree{}},
+	"1516491388_remove_repo_private.down.sql":                                 {_1516491388_remove_repo_privateDownSql, map[string]*bintree{}},
+	"1516491388_remove_repo_private.up.sql":                                   {_1516491388_remove_repo_privateUpSql, map[string]*bintree{}},
+	"1516608575_repo_cleanup.down.sql":                                        {_1516608575_repo_cleanupDownSql, map[string]*bintree{}},
+	"1516608575_repo_cleanup.up.sql":                                          {_1516608575_repo_cleanupUpSql, map[string]*bintree{}},
+	"1516834731_add_saved_queries.down.sql":                                   {_1516834731_add_saved_queriesDownSql, map[string]*bintree{}},
+	"1516834731_add_saved_queries.up.sql":                                     {_1516834731_add_saved_queriesUpSql, map[string]*bintree{}},
+	"1517129075_repo_external.down.sql":                                       {_1517129075_repo_externalDownSql, map[string]*bintree{}},
+	"1517129075_repo_external.up.sql":                                         {_1517129075_repo_externalUpSql, map[string]*bintree{}},
+	"1518102181_cert_cache.down.sql":                                          {_1518102181_cert_cacheDownSql, map[string]*bintree{}},
+	"1518102181_cert_cache.up.sql":                                            {_1518102181_cert_cacheUpSql, map[string]*bintree{}},
+	"1518581786_remove_site_config_telemetry.down.sql":                        {_1518581786_remove_site_config_telemetryDownSql, map[string]*bintree{}},
+	"1518581786_remove_site_config_telemetry.up.sql":                          {_1518581786_remove_site_config_telemetryUpSql, map[string]*bintree{}},
+	"1518581860_add_site_config_initialized.down.sql":                         {_1518581860_add_site_config_initializedDownSql, map[string]*bintree{}},
+	"1518581860_add_site_config_initialized.up.sql":                           {_1518581860_add_site_config_initializedUpSql, map[string]*bintree{}},
+	"1519507899_drop_global_dep_private.down.sql":                             {_1519507899_drop_global_dep_privateDownSql, map[string]*bintree{}},
+	"1519507899_drop_global_dep_private.up.sql":                               {_1519507899_drop_global_dep_privateUpSql, map[string]*bintree{}},
+	"1520588597_user_emails_unique_verified_only.down.sql":                    {_1520588597_user_emails_unique_verified_onlyDownSql, map[string]*bintree{}},
+	"1520588597_user_emails_unique_verified_only.up.sql":                      {_1520588597_user_emails_unique_verified_onlyUpSql, map[string]*bintree{}},
+	"1520708880_users_display_name_nullable.down.sql":                         {_1520708880_users_display_name_nullableDownSql, map[string]*bintree{}},
+	"1520708880_users_display_name_nullable.up.sql":                           {_1520708880_users_display_name_nullableUpSql, map[string]*bintree{}},
+	"1522555179_create_access_tokens_table.down.sql":                          {_1522555179_create_access_tokens_tableDownSql, map[string]*bintree{}},
+	"1522555179_create_access_tokens_table.up.sql":                            {_1522555179_create_access_tokens_tableUpSql, map[string]*bintree{}},
+	"1522961518_create_survey_responses_table.down.sql":                       {_1522961518_create_survey_responses_tableDownSql, map[string]*bintree{}},
+	"1522961518_create_survey_responses_table.up.sql":                         {_1522961518_create_survey_responses_tableUpSql, map[string]*bintree{}},
+	"1524535307_remove_survey_responses_updated_at.down.sql":                  {_1524535307_remove_survey_responses_updated_atDownSql, map[string]*bintree{}},
+	"1524535307_remove_survey_responses_updated_at.up.sql":                    {_1524535307_remove_survey_responses_updated_atUpSql, map[string]*bintree{}},
+	"1524724144_add_access_tokens_fields.down.sql":                            {_1524724144_add_access_tokens_fieldsDownSql, map[string]*bintree{}},
+	"1524724144_add_access_tokens_fields.up.sql":                              {_1524724144_add_access_tokens_fieldsUpSql, map[string]*bintree{}},
+	"1524942857_trim_site_config.down.sql":                                    {_1524942857_trim_site_configDownSql, map[string]*bintree{}},
+	"1524942857_trim_site_config.up.sql":                                      {_1524942857_trim_site_configUpSql, map[string]*bintree{}},
+	"1524949295_simplify_initialization.down.sql":                             {_1524949295_simplify_initializationDownSql, map[string]*bintree{}},
+	"1524949295_simplify_initialization.up.sql":                               {_1524949295_simplify_initializationUpSql, map[string]*bintree{}},
+	"1525150355_add_access_tokens_scopes.down.sql":                            {_1525150355_add_access_tokens_scopesDownSql, map[string]*bintree{}},
+	"1525150355_add_access_tokens_scopes.up.sql":                              {_1525150355_add_access_tokens_scopesUpSql, map[string]*bintree{}},
+	"1525961108_user_unique_among_non-deleted.down.sql":                       {_1525961108_user_unique_among_nonDeletedDownSql, map[string]*bintree{}},
+	"1525961108_user_unique_among_non-deleted.up.sql":                         {_1525961108_user_unique_among_nonDeletedUpSql, map[string]*bintree{}},
+	"1526364839_user_multiple_external_accounts.down.sql":                     {_1526364839_user_multiple_external_accountsDownSql, map[string]*bintree{}},
+	"1526364839_user_multiple_external_accounts.up.sql":                       {_1526364839_user_multiple_external_accountsUpSql, map[string]*bintree{}},
+	"1526804768_add_external_account_client.down.sql":                         {_1526804768_add_external_account_clientDownSql, map[string]*bintree{}},
+	"1526804768_add_external_account_client.up.sql":                           {_1526804768_add_external_account_clientUpSql, map[string]*bintree{}},
+	"1527691234_reuse_org_name.down.sql":                                      {_1527691234_reuse_org_nameDownSql, map[string]*bintree{}},
+	"1527691234_reuse_org_name.up.sql":                                        {_1527691234_reuse_org_nameUpSql, map[string]*bintree{}},
+	"1528179233_drop_code_comments_tables.down.sql":                           {_1528179233_drop_code_comments_tablesDownSql, map[string]*bintree{}},
+	"1528179233_drop_code_comments_tables.up.sql":                             {_1528179233_drop_code_comments_tablesUpSql, map[string]*bintree{}},
+	"1528277031_create_org_invitations_table.down.sql":                        {_1528277031_create_org_invitations_tableDownSql, map[string]*bintree{}},
+	"1528277031_create_org_invitations_table.up.sql":                          {_1528277031_create_org_invitations_tableUpSql, map[string]*bintree{}},
+	"1528395534_.down.sql":                                                    {_1528395534_DownSql, map[string]*bintree{}},
+	"1528395534_.up.sql":                                                      {_1528395534_UpSql, map[string]*bintree{}},
+	"1528395535_.down.sql":                                                    {_1528395535_DownSql, map[string]*bintree{}},
+	"1528395535_.up.sql":                                                      {_1528395535_UpSql, map[string]*bintree{}},
+	"1528395536_.down.sql":                                                    {_1528395536_DownSql, map[string]*bintree{}},
+	"1528395536_.up.sql":                                                      {_1528395536_UpSql, map[string]*bintree{}},
+	"1528395537_.down.sql":                                                    {_1528395537_DownSql, map[string]*bintree{}},
+	"1528395537_.up.sql":                                                      {_1528395537_UpSql, map[string]*bintree{}},
+	"1528395538_.down.sql":                                                    {_1528395538_DownSql, map[string]*bintree{}},
+	"1528395538_.up.sql":                                                      {_1528395538_UpSql, map[string]*bintree{}},
+	"1528395539_.down.sql":                                                    {_1528395539_DownSql, map[string]*bintree{}},
+	"1528395539_.up.sql":                                                      {_1528395539_UpSql, map[string]*bintree{}},
+	"1528395540_.down.sql":                                                    {_1528395540_DownSql, map[string]*bintree{}},
+	"1528395540_.up.sql":                                                      {_1528395540_UpSql, map[string]*bintree{}},
+	"1528395541_.down.sql":                                                    {_1528395541_DownSql, map[string]*bintree{}},
+	"1528395541_.up.sql":                                                      {_1528395541_UpSql, map[string]*bintree{}},
+	"1528395542_.down.sql":                                                    {_1528395542_DownSql, map[string]*bintree{}},
+	"1528395542_.up.sql":                                                      {_1528395542_UpSql, map[string]*bintree{}},
+	"1528395544_.down.sql":                                                    {_1528395544_DownSql, map[string]*bintree{}},
+	"1528395544_.up.sql":                                                      {_1528395544_UpSql, map[string]*bintree{}},
+	"1528395545_.down.sql":                                                    {_1528395545_DownSql, map[string]*bintree{}},
+	"1528395545_.up.sql":                                                      {_1528395545_UpSql, map[string]*bintree{}},
+	"1528395546_.down.sql":                                                    {_1528395546_DownSql, map[string]*bintree{}},
+	"1528395546_.up.sql":                                                      {_1528395546_UpSql, map[string]*bintree{}},
+	"1528395547_.down.sql":                                                    {_1528395547_DownSql, map[string]*bintree{}},
+	"1528395547_.up.sql":                                                      {_1528395547_UpSql, map[string]*bintree{}},
+	"1528395548_.down.sql":                                                    {_1528395548_DownSql, map[string]*bintree{}},
+	"1528395548_.up.sql":                                                      {_1528395548_UpSql, map[string]*bintree{}},
+	"1528395549_.down.sql":                                                    {_1528395549_DownSql, map[string]*bintree{}},
+	"1528395549_.up.sql":                                                      {_1528395549_UpSql, map[string]*bintree{}},
+	"1528395550_.down.sql":                                                    {_1528395550_DownSql, map[string]*bintree{}},
+	"1528395550_.up.sql":                                                      {_1528395550_UpSql, map[string]*bintree{}},
+	"1528395551_.down.sql":                                                    {_1528395551_DownSql, map[string]*bintree{}},
+	"1528395551_.up.sql":                                                      {_1528395551_UpSql, map[string]*bintree{}},
+	"1528395552_.down.sql":                                                    {_1528395552_DownSql, map[string]*bintree{}},
+	"1528395552_.up.sql":                                                      {_1528395552_UpSql, map[string]*bintree{}},
+	"1528395553_.down.sql":                                                    {_1528395553_DownSql, map[string]*bintree{}},
+	"1528395553_.up.sql":                                                      {_1528395553_UpSql, map[string]*bintree{}},
+	"1528395554_oss_fake_migration.down.sql":                                  {_1528395554_oss_fake_migrationDownSql, map[string]*bintree{}},
+	"1528395554_oss_fake_migration.up.sql":                                    {_1528395554_oss_fake_migrationUpSql, map[string]*bintree{}},
+	"1528395555_.down.sql":                                                    {_1528395555_DownSql, map[string]*bintree{}},
+	"1528395555_.up.sql":                                                      {_1528395555_UpSql, map[string]*bintree{}},
+	"1528395556_.down.sql":                                                    {_1528395556_DownSql, map[string]*bintree{}},
+	"1528395556_.up.sql":                                                      {_1528395556_UpSql, map[string]*bintree{}},
+	"1528395557_.down.sql":                                                    {_1528395557_DownSql, map[string]*bintree{}},
+	"1528395557_.up.sql":                                                      {_1528395557_UpSql, map[string]*bintree{}},
+	"1528395558_.down.sql":                                                    {_1528395558_DownSql, map[string]*bintree{}},
+	"1528395558_.up.sql":                                                      {_1528395558_UpSql, map[string]*bintree{}},
+	"1528395559_.down.sql":                                                    {_1528395559_DownSql, map[string]*bintree{}},
+	"1528395559_.up.sql":                                                      {_1528395559_UpSql, map[string]*bintree{}},
+	"1528395560_.down.sql":                                                    {_1528395560_DownSql, map[string]*bintree{}},
+	"1528395560_.up.sql":                                                      {_1528395560_UpSql, map[string]*bintree{}},
+	"1528395561_.down.sql":                                                    {_1528395561_DownSql, map[string]*bintree{}},
+	"1528395561_.up.sql":                                                      {_1528395561_UpSql, map[string]*bintree{}},
+	"1528395562_.down.sql":                                                    {_1528395562_DownSql, map[string]*bintree{}},
+	"1528395562_.up.sql":                                                      {_1528395562_UpSql, map[string]*bintree{}},
+	"1528395563_.down.sql":                                                    {_1528395563_DownSql, map[string]*bintree{}},
+	"1528395563_.up.sql":                                                      {_1528395563_UpSql, map[string]*bintree{}},
+	"1528395564_.down.sql":                                                    {_1528395564_DownSql, map[string]*bintree{}},
+	"1528395564_.up.sql":                                                      {_1528395564_UpSql, map[string]*bintree{}},
+	"1528395565_.down.sql":                                                    {_1528395565_DownSql, map[string]*bintree{}},
+	"1528395565_.up.sql":                                                      {_1528395565_UpSql, map[string]*bintree{}},
+	"1528395566_.down.sql":                                                    {_1528395566_DownSql, map[string]*bintree{}},
+	"1528395566_.up.sql":                                                      {_1528395566_UpSql, map[string]*bintree{}},
+	"1528395567_.down.sql":                                                    {_1528395567_DownSql, map[string]*bintree{}},
+	"1528395567_.up.sql":                                                      {_1528395567_UpSql, map[string]*bintree{}},
+	"1528395568_.down.sql":                                                    {_1528395568_DownSql, map[string]*bintree{}},
+	"1528395568_.up.sql":                                                      {_1528395568_UpSql, map[string]*bintree{}},
+	"1528395569_.down.sql":                                                    {_1528395569_DownSql, map[string]*bintree{}},
+	"1528395569_.up.sql":                                                      {_1528395569_UpSql, map[string]*bintree{}},
+	"1528395570_.down.sql":                                                    {_1528395570_DownSql, map[string]*bintree{}},
+	"1528395570_.up.sql":                                                      {_1528395570_UpSql, map[string]*bintree{}},
+	"1528395571_.down.sql":                                                    {_1528395571_DownSql, map[string]*bintree{}},
+	"1528395571_.up.sql":                                                      {_1528395571_UpSql, map[string]*bintree{}},
+	"1528395572_.down.sql":                                                    {_1528395572_DownSql, map[string]*bintree{}},
+	"1528395572_.up.sql":                                                      {_1528395572_UpSql, map[string]*bintree{}},
+	"1528395573_recent_searches.down.sql":                                     {_1528395573_recent_searchesDownSql, map[string]*bintree{}},
+	"1528395573_recent_searches.up.sql":                                       {_1528395573_recent_searchesUpSql, map[string]*bintree{}},
+	"1528395574_.down.sql":                                                    {_1528395574_DownSql, map[string]*bintree{}},
+	"1528395574_.up.sql":                                                      {_1528395574_UpSql, map[string]*bintree{}},
+	"1528395575_.down.sql":                                                    {_1528395575_DownSql, map[string]*bintree{}},
+	"1528395575_.up.sql":                                                      {_1528395575_UpSql, map[string]*bintree{}},
+	"1528395576_.down.sql":                                                    {_1528395576_DownSql, map[string]*bintree{}},
+	"1528395576_.up.sql":                                                      {_1528395576_UpSql, map[string]*bintree{}},
+	"1528395577_.up.sql":                                                      {_1528395577_UpSql, map[string]*bintree{}},
+	"1528395578_.down.sql":                                                    {_1528395578_DownSql, map[string]*bintree{}},
+	"1528395578_.up.sql":                                                      {_1528395578_UpSql, map[string]*bintree{}},
+	"1528395579_.down.sql":                                                    {_1528395579_DownSql, map[string]*bintree{}},
+	"1528395579_.up.sql":                                                      {_1528395579_UpSql, map[string]*bintree{}},
+	"1528395580_create_user_permissions_table.down.sql":                       {_1528395580_create_user_permissions_tableDownSql, map[string]*bintree{}},
+	"1528395580_create_user_permissions_table.up.sql":                         {_1528395580_create_user_permissions_tableUpSql, map[string]*bintree{}},
+	"1528395581_allows_dots_in_usernames.down.sql":                            {_1528395581_allows_dots_in_usernamesDownSql, map[string]*bintree{}},
+	"1528395581_allows_dots_in_usernames.up.sql":                              {_1528395581_allows_dots_in_usernamesUpSql, map[string]*bintree{}},
+	"1528395582_repo_normalized_metadata.down.sql":                            {_1528395582_repo_normalized_metadataDownSql, map[string]*bintree{}},
+	"1528395582_repo_normalized_metadata.up.sql":                              {_1528395582_repo_normalized_metadataUpSql, map[string]*bintree{}},
+	"1528395583_repo_update_attempts.down.sql":                                {_1528395583_repo_update_attemptsDownSql, map[string]*bintree{}},
+	"1528395583_repo_update_attempts.up.sql":                                  {_1528395583_repo_update_attemptsUpSql, map[string]*bintree{}},
+	"1528395584_create_user_repo_permissions.down.sql":                        {_1528395584_create_user_repo_permissionsDownSql, map[string]*bintree{}},
+	"1528395584_create_user_repo_permissions.up.sql":                          {_1528395584_create_user_repo_permissionsUpSql, map[string]*bintree{}},
+	"1528395585_create_explicit_repo_permissions.down.sql":                    {_1528395585_create_explicit_repo_permissionsDownSql, map[string]*bintree{}},
+	"1528395585_create_explicit_repo_permissions.up.sql":                      {_1528395585_create_explicit_repo_permissionsUpSql, map[string]*bintree{}},
+	"1528395586_add_access_token_expiry.down.sql":                             {_1528395586_add_access_token_expiryDownSql, map[string]*bintree{}},
+	"1528395586_add_access_token_expiry.up.sql":                               {_1528395586_add_access_token_expiryUpSql, map[string]*bintree{}},
+	"1528395587_add_audit_log.down.sql":                                       {_1528395587_add_audit_logDownSql, map[string]*bintree{}},
+	"1528395587_add_audit_log.up.sql":                                         {_1528395587_add_audit_logUpSql, map[string]*bintree{}},
+	"1528395588_add_user_mfa.down.sql":                                        {_1528395588_add_user_mfaDownSql, map[string]*bintree{}},
+	"1528395588_add_user_mfa.up.sql":                                          {_1528395588_add_user_mfaUpSql, map[string]*bintree{}},
+	"1528395589_add_user_sessions.down.sql":                                   {_1528395589_add_user_sessionsDownSql, map[string]*bintree{}},
+	"1528395589_add_user_sessions.up.sql":                                     {_1528395589_add_user_sessionsUpSql, map[string]*bintree{}},
+	"1528395590_add_user_state.down.sql":                                      {_1528395590_add_user_stateDownSql, map[string]*bintree{}},
+	"1528395590_add_user_state.up.sql":                                        {_1528395590_add_user_stateUpSql, map[string]*bintree{}},
+	"1528395591_add_saved_search_result_fingerprints.down.sql":                {_1528395591_add_saved_search_result_fingerprintsDownSql, map[string]*bintree{}},
+	"1528395591_add_saved_search_result_fingerprints.up.sql":                  {_1528395591_add_saved_search_result_fingerprintsUpSql, map[string]*bintree{}},
+	"1528395592_add_saved_search_webhooks.down.sql":                           {_1528395592_add_saved_search_webhooksDownSql, map[string]*bintree{}},
+	"1528395592_add_saved_search_webhooks.up.sql":                             {_1528395592_add_saved_search_webhooksUpSql, map[string]*bintree{}},
+	"1528395593_add_saved_search_subscriptions_and_runs.down.sql":             {_1528395593_add_saved_search_subscriptions_and_runsDownSql, map[string]*bintree{}},
+	"1528395593_add_saved_search_subscriptions_and_runs.up.sql":               {_1528395593_add_saved_search_subscriptions_and_runsUpSql, map[string]*bintree{}},
+	"1528395594_add_saved_search_schedules.down.sql":                          {_1528395594_add_saved_search_schedulesDownSql, map[string]*bintree{}},
+	"1528395594_add_saved_search_schedules.up.sql":                            {_1528395594_add_saved_search_schedulesUpSql, map[string]*bintree{}},
+	"1528395595_add_discussion_threads_diff_targets.down.sql":                 {_1528395595_add_discussion_threads_diff_targetsDownSql, map[string]*bintree{}},
+	"1528395595_add_discussion_threads_diff_targets.up.sql":                   {_1528395595_add_discussion_threads_diff_targetsUpSql, map[string]*bintree{}},
+	"1528395596_add_notifications.down.sql":                                   {_1528395596_add_notificationsDownSql, map[string]*bintree{}},
+	"1528395596_add_notifications.up.sql":                                     {_1528395596_add_notificationsUpSql, map[string]*bintree{}},
+	"1528395597_add_discussions_full_text_indexes.down.sql":                   {_1528395597_add_discussions_full_text_indexesDownSql, map[string]*bintree{}},
+	"1528395597_add_discussions_full_text_indexes.up.sql":                     {_1528395597_add_discussions_full_text_indexesUpSql, map[string]*bintree{}},
+	"1528395598_add_discussion_thread_resolution_assignee_reactions.down.sql": {_1528395598_add_discussion_thread_resolution_assignee_reactionsDownSql, map[string]*bintree{}},
+	"1528395598_add_discussion_thread_resolution_assignee_reactions.up.sql":   {_1528395598_add_discussion_thread_resolution_assignee_reactionsUpSql, map[string]*bintree{}},
+	"1528395599_repo_demand.down.sql":                                         {_1528395599_repo_demandDownSql, map[string]*bintree{}},
+	"1528395599_repo_demand.up.sql":                                           {_1528395599_repo_demandUpSql, map[string]*bintree{}},
+	"1528395600_add_user_mfa_failed_attempts.down.sql":                        {_1528395600_add_user_mfa_failed_attemptsDownSql, map[string]*bintree{}},
+	"1528395600_add_user_mfa_failed_attempts.up.sql":                          {_1528395600_add_user_mfa_failed_attemptsUpSql, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory.
@@ -24,6 +24,17 @@ type Actor struct {
 	// to selectively display a logout link. (If the actor wasn't authenticated with a session
 	// cookie, logout would be ineffective.)
 	FromSessionCookie bool `json:"-"`
+
+	// MFASatisfied is whether the user satisfied multi-factor authentication when signing in to
+	// the session that was used to authenticate the actor. It is only meaningful if
+	// FromSessionCookie is true.
+	MFASatisfied bool `json:"-"`
+
+	// Scopes, if non-nil, are the scopes of the access token that was used to authenticate the
+	// actor. The actor may only perform the operations that these scopes permit. It is nil if the
+	// actor was not authenticated with an access token or if the token grants full control of the
+	// user account.
+	Scopes []string `json:"-"`
 }
 
 // FromUser returns an actor corresponding to a user
@@ -140,6 +140,20 @@ type ExternalService struct {
 	DeletedAt   *time.Time
 }
 
+// RepoUpdateAttempt describes a single attempt by gitserver to clone or fetch
+// a repository from its remote.
+type RepoUpdateAttempt struct {
+	Kind          string        // "clone" or "fetch"
+	Started       time.Time     // when the attempt started
+	Duration      time.Duration // how long the attempt took
+	Error         string        // the error that the attempt failed with, or empty if it succeeded
+	Output        string        // the (truncated) output of the git command, with credentials redacted
+	BytesReceived int64         // the number of bytes received from the remote, if reported by git
+}
+
+// Failed reports whether the attempt failed.
+func (a RepoUpdateAttempt) Failed() bool { return a.Error != "" }
+
 func cmp(a, b string) int {
 	switch {
 	case a < b:
@@ -40,6 +40,11 @@ type ReposUpdateMetadataRequest struct {
 	Archived    bool   `json:"Archived"`
 }
 
+type ReposRecordUpdateAttemptRequest struct {
+	Repo    RepoName          `json:"repo"`
+	Attempt RepoUpdateAttempt `json:"attempt"`
+}
+
 type PhabricatorRepoCreateRequest struct {
 	RepoName `json:"repo"`
 	Callsign string `json:"callsign"`
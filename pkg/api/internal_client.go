@@ -78,14 +78,18 @@ type SavedQueryIDSpec struct {
 // ConfigSavedQuery is the JSON shape of a saved query entry in the JSON configuration
 // (i.e., an entry in the {"search.savedQueries": [...]} array).
 type ConfigSavedQuery struct {
-	Key             string  `json:"key,omitempty"`
-	Description     string  `json:"description"`
-	Query           string  `json:"query"`
-	Notify          bool    `json:"notify,omitempty"`
-	NotifySlack     bool    `json:"notifySlack,omitempty"`
-	UserID          *int32  `json:"userID"`
-	OrgID           *int32  `json:"orgID"`
-	SlackWebhookURL *string `json:"slackWebhookURL"`
+	Key              string  `json:"key,omitempty"`
+	Description      string  `json:"description"`
+	Query            string  `json:"query"`
+	Notify           bool    `json:"notify,omitempty"`
+	NotifySlack      bool    `json:"notifySlack,omitempty"`
+	UserID           *int32  `json:"userID"`
+	OrgID            *int32  `json:"orgID"`
+	SlackWebhookURL  *string `json:"slackWebhookURL"`
+	HasWebhooks      bool    `json:"hasWebhooks,omitempty"`      // whether the saved search has webhooks (in the saved_search_webhooks table)
+	HasSubscriptions bool    `json:"hasSubscriptions,omitempty"` // whether users subscribed to the saved search (in the saved_search_subscriptions table)
+	Schedule         *string `json:"schedule,omitempty"`         // the schedule on which the saved search is run, or nil for the default schedule
+	TimeoutSeconds   *int32  `json:"timeoutSeconds,omitempty"`   // the maximum duration of each run, or nil for the default timeout
 }
 
 func (sq ConfigSavedQuery) Equals(other ConfigSavedQuery) bool {
@@ -129,9 +133,8 @@ type SavedQueryInfo struct {
 	// executed.
 	LastExecuted time.Time
 
-	// LatestResult is the timestamp of the latest-known result for the search
-	// query. Therefore, searching `after:<LatestResult>` will return the new
-	// search results not yet seen.
+	// LatestResult is the timestamp of the last time that new search results
+	// were found for the search query.
 	LatestResult time.Time
 
 	// ExecDuration is the amount of time it took for the query to execute.
@@ -158,6 +161,151 @@ func (c *internalClient) SavedQueriesDeleteInfo(ctx context.Context, query strin
 	return c.postInternal(ctx, "saved-queries/delete-info", query, nil)
 }
 
+// SavedQueryResultFingerprints holds the fingerprints of a saved search's search results.
+type SavedQueryResultFingerprints struct {
+	SavedSearchID int32
+	Fingerprints  []string
+}
+
+// SavedQueriesGetResultFingerprints gets the fingerprints of the search results that were recorded
+// for the saved search (by its DB ID). nil is returned if none have been recorded.
+func (c *internalClient) SavedQueriesGetResultFingerprints(ctx context.Context, savedSearchID int32) ([]string, error) {
+	var result []string
+	err := c.postInternal(ctx, "saved-queries/get-result-fingerprints", savedSearchID, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SavedQueriesSetResultFingerprints records the fingerprints of the saved search's search results,
+// replacing any previously recorded fingerprints.
+func (c *internalClient) SavedQueriesSetResultFingerprints(ctx context.Context, savedSearchID int32, fingerprints []string) error {
+	return c.postInternal(ctx, "saved-queries/set-result-fingerprints", &SavedQueryResultFingerprints{
+		SavedSearchID: savedSearchID,
+		Fingerprints:  fingerprints,
+	}, nil)
+}
+
+// SavedQueryWebhookDeliveriesCreateArgs are the arguments for creating a delivery of a notification
+// to each of a saved search's webhooks.
+type SavedQueryWebhookDeliveriesCreateArgs struct {
+	SavedSearchID int32
+	Event         string // the type of the notification (e.g., "saved_search.new_results")
+	Payload       string // the JSON request body
+}
+
+// SavedQueryWebhookDelivery is a pending delivery of a notification to a saved search webhook.
+type SavedQueryWebhookDelivery struct {
+	ID       int32
+	URL      string
+	Secret   string // the secret used to sign the request body (HMAC-SHA256)
+	Event    string
+	Payload  string
+	Attempts int32 // the number of previous delivery attempts
+}
+
+// SavedQueryWebhookDeliveryAttempt describes the outcome of an attempt to deliver a saved search
+// webhook notification.
+type SavedQueryWebhookDeliveryAttempt struct {
+	DeliveryID         int32
+	Success            bool
+	ResponseStatusCode *int32
+	Error              *string
+	NextAttemptAt      *time.Time // when to retry (nil if the delivery succeeded or should not be retried)
+}
+
+// SavedQueriesCreateWebhookDeliveries creates a pending delivery of the notification to each of the
+// saved search's webhooks and returns the number of deliveries created.
+func (c *internalClient) SavedQueriesCreateWebhookDeliveries(ctx context.Context, args SavedQueryWebhookDeliveriesCreateArgs) (int, error) {
+	var n int
+	err := c.postInternal(ctx, "saved-queries/webhook-deliveries/create", args, &n)
+	return n, err
+}
+
+// SavedQueriesListDueWebhookDeliveries lists the pending saved search webhook deliveries whose next
+// attempt is due.
+func (c *internalClient) SavedQueriesListDueWebhookDeliveries(ctx context.Context) ([]*SavedQueryWebhookDelivery, error) {
+	var deliveries []*SavedQueryWebhookDelivery
+	err := c.postInternal(ctx, "saved-queries/webhook-deliveries/list-due", nil, &deliveries)
+	return deliveries, err
+}
+
+// SavedQueriesRecordWebhookDeliveryAttempt records the outcome of an attempt to deliver a saved
+// search webhook notification.
+func (c *internalClient) SavedQueriesRecordWebhookDeliveryAttempt(ctx context.Context, attempt *SavedQueryWebhookDeliveryAttempt) error {
+	return c.postInternal(ctx, "saved-queries/webhook-deliveries/record-attempt", attempt, nil)
+}
+
+// SavedQuerySubscription is a user's subscription to notifications about a saved search.
+type SavedQuerySubscription struct {
+	UserID          int32
+	Channel         string  // "email" or "slack"
+	SlackWebhookURL *string // the Slack webhook URL (only for the "slack" channel)
+}
+
+var MockSavedQueriesListSubscriptions func(savedSearchID int32) ([]*SavedQuerySubscription, error)
+
+// SavedQueriesListSubscriptions lists the users' subscriptions to notifications about the saved
+// search (by its DB ID).
+func (c *internalClient) SavedQueriesListSubscriptions(ctx context.Context, savedSearchID int32) ([]*SavedQuerySubscription, error) {
+	if MockSavedQueriesListSubscriptions != nil {
+		return MockSavedQueriesListSubscriptions(savedSearchID)
+	}
+	var subscriptions []*SavedQuerySubscription
+	err := c.postInternal(ctx, "saved-queries/list-subscriptions", savedSearchID, &subscriptions)
+	return subscriptions, err
+}
+
+// SavedQueryRun describes an execution of a saved search by the query runner.
+type SavedQueryRun struct {
+	SavedSearchID  int32
+	StartedAt      time.Time
+	Duration       time.Duration
+	ResultCount    int     // the number of matches in the search results
+	NewResultCount int     // the number of matches that were not in the previous run's results
+	Error          *string // the error that occurred during the run, if any
+
+	// NextRunAt is when the saved search should run next (taking into account its schedule and
+	// back-off after errors).
+	NextRunAt time.Time
+}
+
+var MockSavedQueriesRecordRun func(run *SavedQueryRun) error
+
+// SavedQueriesRecordRun records a run of a saved search in its run history and updates the saved
+// search's run state.
+func (c *internalClient) SavedQueriesRecordRun(ctx context.Context, run *SavedQueryRun) error {
+	if MockSavedQueriesRecordRun != nil {
+		return MockSavedQueriesRecordRun(run)
+	}
+	return c.postInternal(ctx, "saved-queries/record-run", run, nil)
+}
+
+// SavedQueryRunState is the query runner's scheduling state of a saved search, which is persisted
+// so that it survives restarts of the query runner.
+type SavedQueryRunState struct {
+	SavedSearchID       int32
+	NextRunAt           *time.Time // when the saved search should run next, or nil if it should run now
+	LastRunAt           *time.Time // when the most recent run started, or nil if it has never run
+	LastRunDuration     time.Duration
+	LastRunError        *string // the error that occurred during the most recent run, if any
+	ConsecutiveFailures int     // the number of consecutive runs (including the most recent) that failed
+}
+
+// SavedQueriesListRunStates lists the run states of all saved searches, keyed by saved search ID.
+func (c *internalClient) SavedQueriesListRunStates(ctx context.Context) (map[int32]*SavedQueryRunState, error) {
+	var states []*SavedQueryRunState
+	if err := c.postInternal(ctx, "saved-queries/list-run-states", nil, &states); err != nil {
+		return nil, err
+	}
+	m := make(map[int32]*SavedQueryRunState, len(states))
+	for _, state := range states {
+		m[state.SavedSearchID] = state
+	}
+	return m, nil
+}
+
 func (c *internalClient) SettingsGetForSubject(ctx context.Context, subject SettingsSubject) (parsed *schema.Settings, settings *Settings, err error) {
 	err = c.postInternal(ctx, "settings/get-for-subject", subject, &settings)
 	if err == nil {
@@ -247,6 +395,25 @@ func (c *internalClient) ReposListEnabled(ctx context.Context) ([]RepoName, erro
 	return names, err
 }
 
+// ReposFilterReadableArgs are the arguments to ReposFilterReadable.
+type ReposFilterReadableArgs struct {
+	UserID int32
+	Repos  []RepoName
+}
+
+var MockReposFilterReadable func(userID int32, repos []RepoName) ([]RepoName, error)
+
+// ReposFilterReadable returns the repositories (among the given ones) that the user is allowed to
+// read.
+func (c *internalClient) ReposFilterReadable(ctx context.Context, userID int32, repos []RepoName) ([]RepoName, error) {
+	if MockReposFilterReadable != nil {
+		return MockReposFilterReadable(userID, repos)
+	}
+	var readable []RepoName
+	err := c.postInternal(ctx, "repos/filter-readable", ReposFilterReadableArgs{UserID: userID, Repos: repos}, &readable)
+	return readable, err
+}
+
 // MockInternalClientConfiguration mocks (*internalClient).Configuration.
 var MockInternalClientConfiguration func() (conftypes.RawUnified, error)
 
@@ -268,6 +435,20 @@ func (c *internalClient) ReposUpdateMetadata(ctx context.Context, repo RepoName,
 	}, nil)
 }
 
+// MockReposRecordUpdateAttempt mocks (*internalClient).ReposRecordUpdateAttempt for tests.
+var MockReposRecordUpdateAttempt func(repo RepoName, attempt RepoUpdateAttempt) error
+
+// ReposRecordUpdateAttempt adds a clone or fetch attempt to the update history of the repository.
+func (c *internalClient) ReposRecordUpdateAttempt(ctx context.Context, repo RepoName, attempt RepoUpdateAttempt) error {
+	if MockReposRecordUpdateAttempt != nil {
+		return MockReposRecordUpdateAttempt(repo, attempt)
+	}
+	return c.postInternal(ctx, "repos/record-update-attempt", ReposRecordUpdateAttemptRequest{
+		Repo:    repo,
+		Attempt: attempt,
+	}, nil)
+}
+
 func (c *internalClient) ReposGetByName(ctx context.Context, repoName RepoName) (*Repo, error) {
 	var repo Repo
 	err := c.postInternal(ctx, "repos/"+string(repoName), nil, &repo)
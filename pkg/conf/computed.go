@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"log"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/conf/confdefaults"
@@ -181,9 +183,22 @@ func CanSendEmail() bool {
 	return Get().EmailSmtp != nil
 }
 
-// CanReadEmail tells if an IMAP server is configured and reading email is possible.
+// CanReadEmail tells if an IMAP server or SMTP receiver is configured and reading email is
+// possible.
 func CanReadEmail() bool {
-	return Get().EmailImap != nil
+	c := Get()
+	return c.EmailImap != nil || c.EmailSmtpReceiver != nil
+}
+
+// EmailReplyAddress returns the address that email replies (such as code discussion reply emails)
+// are sent to: the SMTP receiver's address if one is configured, and otherwise the IMAP username.
+// It should only be called if CanReadEmail returns true.
+func EmailReplyAddress() string {
+	c := Get()
+	if c.EmailSmtpReceiver != nil {
+		return c.EmailSmtpReceiver.Address
+	}
+	return c.EmailImap.Username
 }
 
 // Deploy type constants. Any changes here should be reflected in the DeployType type declared in web/src/globals.d.ts:
@@ -256,6 +271,42 @@ func SearchIndexEnabled() bool {
 	return DeployType() != DeployDocker
 }
 
+// PermissionsBackgroundSyncEnabled reports whether repository permissions are
+// synced in the background and enforced with a database join.
+func PermissionsBackgroundSyncEnabled() bool {
+	if v := Get().PermissionsBackgroundSync; v != nil {
+		return v.Enabled
+	}
+	return false
+}
+
+// PermissionsUserSyncInterval returns the interval between syncs of each
+// user's repository permissions.
+func PermissionsUserSyncInterval() time.Duration {
+	if v := Get().PermissionsBackgroundSync; v != nil && v.UserInterval > 0 {
+		return time.Duration(v.UserInterval) * time.Minute
+	}
+	return time.Hour
+}
+
+// IsRepoReadOnly reports whether the repository is protected by the "repositories.readOnly" site
+// configuration property. Sourcegraph must not create commits or branches in a protected
+// repository.
+func IsRepoReadOnly(repo api.RepoName) bool {
+	for _, pattern := range Get().RepositoriesReadOnly {
+		match, err := regexp.MatchString(pattern, string(repo))
+		if err != nil {
+			// Fail closed. A user-visible validation error is shown for invalid patterns.
+			log.Printf("Site config: unable to compile repositories.readOnly regexp %q: %s", pattern, err)
+			return true
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
 func UsingExternalURL() bool {
 	url := Get().Critical.ExternalURL
 	return !(url == "" || strings.HasPrefix(url, "http://localhost") || strings.HasPrefix(url, "https://localhost") || strings.HasPrefix(url, "http://127.0.0.1") || strings.HasPrefix(url, "https://127.0.0.1")) // CI:LOCALHOST_OK
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/conf/confdefaults"
 	"github.com/sourcegraph/sourcegraph/pkg/conf/conftypes"
 
@@ -113,3 +114,28 @@ func setenv(t *testing.T, keyval string) func() {
 func boolPtr(b bool) *bool {
 	return &b
 }
+
+func TestIsRepoReadOnly(t *testing.T) {
+	Mock(&Unified{SiteConfiguration: schema.SiteConfiguration{
+		RepositoriesReadOnly: []string{`^github\.com/protected/`, `^gitlab\.com/infra/prod$`},
+	}})
+	defer Mock(nil)
+
+	tests := map[string]bool{
+		"github.com/protected/r":   true,
+		"github.com/unprotected/r": false,
+		"gitlab.com/infra/prod":    true,
+		"gitlab.com/infra/prod2":   false,
+	}
+	for repo, want := range tests {
+		if got := IsRepoReadOnly(api.RepoName(repo)); got != want {
+			t.Errorf("%s: got %v, want %v", repo, got, want)
+		}
+	}
+
+	// Invalid patterns fail closed.
+	Mock(&Unified{SiteConfiguration: schema.SiteConfiguration{RepositoriesReadOnly: []string{`(`}}})
+	if !IsRepoReadOnly("github.com/u/r") {
+		t.Error("invalid pattern: got false, want true")
+	}
+}
@@ -1,13 +1,101 @@
 package conf
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
+// FieldChange is a change of the value of a configuration field.
+type FieldChange struct {
+	Field  string      // the name of the field (such as "auth.providers" or "critical::externalURL")
+	Before interface{} // the JSON value before the change (nil if unset)
+	After  interface{} // the JSON value after the change (nil if unset)
+}
+
+// RedactedSecret replaces the values of secrets in the changes returned by Changes.
+const RedactedSecret = "REDACTED"
+
+// secretFieldName matches the names of configuration fields (at any depth) whose values are
+// secrets.
+var secretFieldName = regexp.MustCompile(`(?i)(password|secret|token$|privatekey|signingkey|licensekey|consumerkey|credentials|dsn$)`)
+
+// Changes returns the fields that have different values between the two configurations, sorted by
+// name, with their values before and after. Secrets (such as passwords, tokens and client
+// secrets), including secrets nested in the values of other fields (such as auth.providers), are
+// replaced with RedactedSecret, so the changes can be recorded and shown to site admins.
+func Changes(before, after *Unified) []FieldChange {
+	var fields []string
+	for field := range diff(before, after) {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	beforeFields, afterFields := unifiedFields(before), unifiedFields(after)
+	changes := make([]FieldChange, len(fields))
+	for i, field := range fields {
+		changes[i] = FieldChange{
+			Field:  field,
+			Before: redactSecrets(field, beforeFields[field]),
+			After:  redactSecrets(field, afterFields[field]),
+		}
+	}
+	return changes
+}
+
+func unifiedFields(c *Unified) map[string]interface{} {
+	fields := getJSONFields(c.SiteConfiguration, "")
+	for k, v := range getJSONFields(c.Critical, "critical::") {
+		fields[k] = v
+	}
+	for k, v := range getJSONFields(c.ServiceConnections, "serviceConnections::") {
+		fields[k] = v
+	}
+	return fields
+}
+
+// redactSecrets returns the JSON value of the named field's value with all secrets replaced by
+// RedactedSecret.
+func redactSecrets(field string, v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return RedactedSecret
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return RedactedSecret
+	}
+	if i := strings.LastIndex(field, "::"); i != -1 {
+		field = field[i+len("::"):]
+	}
+	return redactValue(field, value)
+}
+
+func redactValue(name string, v interface{}) interface{} {
+	if v == nil || v == "" {
+		return v // an unset secret reveals nothing
+	}
+	if secretFieldName.MatchString(name) {
+		return RedactedSecret
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = redactValue(k, e)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = redactValue("", e)
+		}
+	}
+	return v
+}
+
 // diff returns names of the Go fields that have different values between the
 // two configurations.
 func diff(before, after *Unified) (fields map[string]struct{}) {
@@ -89,6 +89,42 @@ func TestDiff(t *testing.T) {
 	}
 }
 
+func TestChanges(t *testing.T) {
+	before := &Unified{
+		SiteConfiguration: schema.SiteConfiguration{
+			MaxReposToSearch: 1,
+			CorsOrigin:       "a",
+			AuthProviders: []schema.AuthProviders{
+				{Openidconnect: &schema.OpenIDConnectAuthProvider{Type: "openidconnect", ClientID: "c", ClientSecret: "s1"}},
+			},
+		},
+		Critical: schema.CriticalConfiguration{ExternalURL: "a", LicenseKey: "k1"},
+	}
+	after := &Unified{
+		SiteConfiguration: schema.SiteConfiguration{
+			MaxReposToSearch: 2,
+			CorsOrigin:       "a",
+			AuthProviders: []schema.AuthProviders{
+				{Openidconnect: &schema.OpenIDConnectAuthProvider{Type: "openidconnect", ClientID: "c", ClientSecret: "s2"}},
+			},
+		},
+		Critical: schema.CriticalConfiguration{ExternalURL: "b", LicenseKey: "k2"},
+	}
+	want := []FieldChange{
+		{
+			Field:  "auth.providers",
+			Before: []interface{}{map[string]interface{}{"type": "openidconnect", "clientID": "c", "clientSecret": RedactedSecret, "issuer": ""}},
+			After:  []interface{}{map[string]interface{}{"type": "openidconnect", "clientID": "c", "clientSecret": RedactedSecret, "issuer": ""}},
+		},
+		{Field: "critical::externalURL", Before: "a", After: "b"},
+		{Field: "critical::licenseKey", Before: RedactedSecret, After: RedactedSecret},
+		{Field: "maxReposToSearch", Before: float64(1), After: float64(2)},
+	}
+	if got := Changes(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
+
 func toSlice(m map[string]struct{}) []string {
 	var s []string
 	for v := range m {
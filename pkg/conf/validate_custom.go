@@ -2,6 +2,8 @@ package conf
 
 import (
 	"encoding/json"
+	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/sourcegraph/sourcegraph/pkg/conf/conftypes"
@@ -51,6 +53,12 @@ func validateCustom(cfg Unified) (problems []string) {
 		}
 	}
 
+	for _, pattern := range cfg.RepositoriesReadOnly {
+		if _, err := regexp.Compile(pattern); err != nil {
+			invalid(fmt.Sprintf("repositories.readOnly: not a valid regexp: %s. See the valid syntax: https://golang.org/pkg/regexp/", pattern))
+		}
+	}
+
 	for _, f := range contributedValidators {
 		problems = append(problems, f(cfg)...)
 	}
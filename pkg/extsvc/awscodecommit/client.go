@@ -1,6 +1,7 @@
 package awscodecommit
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws/awserr"
 	"github.com/aws/aws-sdk-go-v2/service/codecommit"
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/ratelimit"
 	"github.com/sourcegraph/sourcegraph/pkg/rcache"
 )
 
@@ -42,6 +44,16 @@ func (c *Client) cacheKeyPrefix() (string, error) {
 	return hex.EncodeToString(key[:]), nil
 }
 
+// wait blocks until the request governor for the client's region and credentials allows another
+// request to be made.
+func (c *Client) wait(ctx context.Context) error {
+	key, err := c.cacheKeyPrefix()
+	if err != nil {
+		return err
+	}
+	return ratelimit.GetGovernor("https://codecommit."+c.aws.Region+".amazonaws.com", key).Wait(ctx)
+}
+
 // ErrNotFound is when the requested AWS CodeCommit repository is not found.
 var ErrNotFound = errors.New("AWS CodeCommit repository not found")
 
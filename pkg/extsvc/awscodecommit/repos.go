@@ -137,6 +137,9 @@ func (c *Client) getRepositoryFromAPI(ctx context.Context, arn string) (*Reposit
 	svc := codecommit.New(c.aws)
 	req := svc.GetRepositoryRequest(&codecommit.GetRepositoryInput{RepositoryName: &repoName})
 	req.SetContext(ctx)
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
 	result, err := req.Send()
 	if err != nil {
 		return nil, err
@@ -162,6 +165,9 @@ func (c *Client) ListRepositories(ctx context.Context, nextToken string) (repos
 	}
 	listReq := svc.ListRepositoriesRequest(&listInput)
 	listReq.SetContext(ctx)
+	if err := c.wait(ctx); err != nil {
+		return nil, "", err
+	}
 	listResult, err := listReq.Send()
 	if err != nil {
 		return nil, "", err
@@ -199,6 +205,9 @@ func (c *Client) getRepositories(ctx context.Context, svc *codecommit.CodeCommit
 	getInput := codecommit.BatchGetRepositoriesInput{RepositoryNames: repositoryNames}
 	getReq := svc.BatchGetRepositoriesRequest(&getInput)
 	getReq.SetContext(ctx)
+	if err := c.wait(ctx); err != nil {
+		return nil, err
+	}
 	getResult, err := getReq.Send()
 	if err != nil {
 		return nil, err
@@ -16,6 +16,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/pkg/httpcli"
 	"github.com/sourcegraph/sourcegraph/pkg/metrics"
+	"github.com/sourcegraph/sourcegraph/pkg/ratelimit"
 	"golang.org/x/time/rate"
 	"gopkg.in/inconshreveable/log15.v2"
 )
@@ -158,6 +159,10 @@ func (c *Client) do(ctx context.Context, req *http.Request, result interface{})
 		log15.Warn("Bitbucket Cloud self-enforced API rate limit: request delayed longer than expected due to rate limit", "delay", d)
 	}
 
+	if err := c.governor().Wait(ctx); err != nil {
+		return err
+	}
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
@@ -190,6 +195,11 @@ func (c *Client) authenticate(req *http.Request) error {
 	return nil
 }
 
+// governor returns the request governor for Bitbucket Cloud and the client's credentials.
+func (c *Client) governor() *ratelimit.Governor {
+	return ratelimit.GetGovernor(c.URL.String(), c.Username)
+}
+
 type PageToken struct {
 	Size    int    `json:"size"`
 	Page    int    `json:"page"`
@@ -216,15 +226,17 @@ func (t *PageToken) Values() url.Values {
 }
 
 type Repo struct {
-	Slug        string `json:"slug"`
-	Name        string `json:"name"`
-	FullName    string `json:"full_name"`
-	UUID        string `json:"uuid"`
-	SCM         string `json:"scm"`
-	Description string `json:"description"`
-	Parent      *Repo  `json:"parent"`
-	IsPrivate   bool   `json:"is_private"`
-	Links       Links  `json:"links"`
+	Slug        string     `json:"slug"`
+	Name        string     `json:"name"`
+	FullName    string     `json:"full_name"`
+	UUID        string     `json:"uuid"`
+	SCM         string     `json:"scm"`
+	Description string     `json:"description"`
+	Parent      *Repo      `json:"parent"`
+	IsPrivate   bool       `json:"is_private"`
+	Links       Links      `json:"links"`
+	Language    string     `json:"language"`
+	UpdatedOn   *time.Time `json:"updated_on,omitempty"`
 }
 
 type Links struct {
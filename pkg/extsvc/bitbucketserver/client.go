@@ -22,6 +22,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/pkg/httpcli"
 	"github.com/sourcegraph/sourcegraph/pkg/metrics"
+	"github.com/sourcegraph/sourcegraph/pkg/ratelimit"
 	"golang.org/x/time/rate"
 	log15 "gopkg.in/inconshreveable/log15.v2"
 )
@@ -470,6 +471,10 @@ func (c *Client) do(ctx context.Context, req *http.Request, result interface{})
 		log15.Warn("Bitbucket self-enforced API rate limit: request delayed longer than expected due to rate limit", "delay", d)
 	}
 
+	if err := c.governor().Wait(ctx); err != nil {
+		return err
+	}
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return err
@@ -524,6 +529,19 @@ func (c *Client) authenticate(req *http.Request) error {
 	return nil
 }
 
+// governor returns the request governor for the client's Bitbucket Server instance and
+// credentials. Requests authenticated with OAuth share the budget of the OAuth consumer, regardless
+// of the user they impersonate.
+func (c *Client) governor() *ratelimit.Governor {
+	credential := c.Token
+	if c.Oauth != nil {
+		credential = c.Oauth.Credentials.Token
+	} else if credential == "" {
+		credential = c.Username
+	}
+	return ratelimit.GetGovernor(c.URL.String(), credential)
+}
+
 func parseQueryStrings(qs ...string) (url.Values, error) {
 	vals := make(url.Values)
 	for _, q := range qs {
@@ -189,10 +189,9 @@ func (c *Client) do(ctx context.Context, token string, req *http.Request, result
 	req.URL.Path = path.Join(c.apiURL.Path, req.URL.Path)
 	req.URL = c.apiURL.ResolveReference(req.URL)
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	token = firstNonEmpty(token, c.defaultToken)
 	if token != "" {
 		req.Header.Set("Authorization", "bearer "+token)
-	} else if c.defaultToken != "" {
-		req.Header.Set("Authorization", "bearer "+c.defaultToken)
 	}
 
 	var resp *http.Response
@@ -209,6 +208,10 @@ func (c *Client) do(ctx context.Context, token string, req *http.Request, result
 		span.Finish()
 	}()
 
+	if err = ratelimit.GetGovernor(c.apiURL.String(), token).Wait(ctx); err != nil {
+		return err
+	}
+
 	resp, err = c.httpClient.Do(req.WithContext(ctx))
 	if err != nil {
 		return err
@@ -237,6 +240,9 @@ func (c *Client) requestGet(ctx context.Context, token, requestURI string, resul
 	// Include node_id (GraphQL ID) in response. See
 	// https://developer.github.com/changes/2017-12-19-graphql-node-id/.
 	req.Header.Add("Accept", "application/vnd.github.jean-grey-preview+json")
+	// Include topics in repository responses. See
+	// https://developer.github.com/v3/repos/#list-all-topics-for-a-repository.
+	req.Header.Add("Accept", "application/vnd.github.mercy-preview+json")
 
 	return c.do(ctx, token, req, result)
 }
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -35,6 +36,53 @@ type Repository struct {
 	IsFork           bool   // whether the repository is a fork of another repository
 	IsArchived       bool   // whether the repository is archived on the code host
 	ViewerPermission string // ADMIN, WRITE, READ, or empty if unknown. Only the graphql api populates this.
+
+	StargazerCount  int       // number of users who starred the repository
+	PrimaryLanguage string    // name of the repository's primary programming language, if any
+	Topics          []string  // topics the repository is tagged with
+	PushedAt        time.Time // when the repository was last pushed to
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts both the flat representation produced by
+// json.Marshal and the nested shape of the RepositoryFields GraphQL fragment.
+func (r *Repository) UnmarshalJSON(data []byte) error {
+	type repository Repository // prevents recursing into this method
+	var v struct {
+		*repository
+		PrimaryLanguage  json.RawMessage // "Go" or {"name": "Go"}
+		Stargazers       *struct{ TotalCount int }
+		RepositoryTopics *struct {
+			Nodes []struct{ Topic struct{ Name string } }
+		}
+	}
+	v.repository = (*repository)(r)
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	if len(v.PrimaryLanguage) > 0 && string(v.PrimaryLanguage) != "null" {
+		if v.PrimaryLanguage[0] == '"' {
+			if err := json.Unmarshal(v.PrimaryLanguage, &r.PrimaryLanguage); err != nil {
+				return err
+			}
+		} else {
+			var lang struct{ Name string }
+			if err := json.Unmarshal(v.PrimaryLanguage, &lang); err != nil {
+				return err
+			}
+			r.PrimaryLanguage = lang.Name
+		}
+	}
+	if v.Stargazers != nil {
+		r.StargazerCount = v.Stargazers.TotalCount
+	}
+	if v.RepositoryTopics != nil {
+		r.Topics = make([]string, 0, len(v.RepositoryTopics.Nodes))
+		for _, n := range v.RepositoryTopics.Nodes {
+			r.Topics = append(r.Topics, n.Topic.Name)
+		}
+	}
+	return nil
 }
 
 // repositoryFieldsGraphQLFragment returns a GraphQL fragment that contains the fields needed to populate the
@@ -51,6 +99,10 @@ fragment RepositoryFields on Repository {
 	isPrivate
 	isFork
 	isArchived
+	stargazers { totalCount }
+	primaryLanguage { name }
+	repositoryTopics(first: 100) { nodes { topic { name } } }
+	pushedAt
 	viewerPermission
 }
 	`
@@ -68,6 +120,10 @@ fragment RepositoryFields on Repository {
 	isPrivate
 	isFork
 	isArchived
+	stargazers { totalCount }
+	primaryLanguage { name }
+	repositoryTopics(first: 100) { nodes { topic { name } } }
+	pushedAt
 }
 	`
 }
@@ -245,6 +301,11 @@ type restRepository struct {
 	Private     bool
 	Fork        bool
 	Archived    bool
+
+	StargazersCount int       `json:"stargazers_count"`
+	Language        string    `json:"language"`
+	Topics          []string  `json:"topics"`
+	PushedAt        time.Time `json:"pushed_at"`
 }
 
 // getRepositoryFromAPI attempts to fetch a repository from the GitHub API without use of the redis cache.
@@ -275,6 +336,11 @@ func convertRestRepo(restRepo restRepository) *Repository {
 		IsPrivate:     restRepo.Private,
 		IsFork:        restRepo.Fork,
 		IsArchived:    restRepo.Archived,
+
+		StargazerCount:  restRepo.StargazersCount,
+		PrimaryLanguage: restRepo.Language,
+		Topics:          restRepo.Topics,
+		PushedAt:        restRepo.PushedAt,
 	}
 }
 
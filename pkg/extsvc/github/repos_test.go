@@ -3,6 +3,7 @@ package github
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/sergi/go-diff/diffmatchpatch"
@@ -82,6 +84,57 @@ func newTestClient(t *testing.T, cli httpcli.Doer) *Client {
 }
 
 // TestClient_GetRepository tests the behavior of GetRepository.
+func TestRepository_UnmarshalJSON(t *testing.T) {
+	pushedAt := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	want := Repository{
+		ID:              "i",
+		NameWithOwner:   "o/r",
+		StargazerCount:  42,
+		PrimaryLanguage: "Go",
+		Topics:          []string{"payments", "api"},
+		PushedAt:        pushedAt,
+	}
+
+	for name, data := range map[string]string{
+		"graphql": `{
+			"id": "i",
+			"nameWithOwner": "o/r",
+			"stargazers": {"totalCount": 42},
+			"primaryLanguage": {"name": "Go"},
+			"repositoryTopics": {"nodes": [{"topic": {"name": "payments"}}, {"topic": {"name": "api"}}]},
+			"pushedAt": "2019-06-01T12:00:00Z"
+		}`,
+		"marshaled": mustMarshal(t, want),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var have Repository
+			if err := json.Unmarshal([]byte(data), &have); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(have, want) {
+				t.Errorf("got repository %+v, want %+v", have, want)
+			}
+		})
+	}
+
+	var have Repository
+	if err := json.Unmarshal([]byte(`{"primaryLanguage": null}`), &have); err != nil {
+		t.Fatal(err)
+	}
+	if have.PrimaryLanguage != "" {
+		t.Errorf("got primary language %q, want none", have.PrimaryLanguage)
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
 func TestClient_GetRepository(t *testing.T) {
 	mock := mockHTTPResponseBody{
 		responseBody: `
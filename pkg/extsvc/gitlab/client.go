@@ -164,6 +164,11 @@ func (p *ClientProvider) newClient(baseURL *url.URL, op getClientOp, httpClient
 	}
 }
 
+// governor returns the request governor for the client's GitLab instance and credentials.
+func (c *Client) governor() *ratelimit.Governor {
+	return ratelimit.GetGovernor(c.baseURL.String(), c.PersonalAccessToken+c.OAuthToken)
+}
+
 func isGitLabDotComURL(baseURL *url.URL) bool {
 	hostname := strings.ToLower(baseURL.Hostname())
 	return hostname == "gitlab.com" || hostname == "www.gitlab.com"
@@ -196,6 +201,10 @@ func (c *Client) do(ctx context.Context, req *http.Request, result interface{})
 		span.Finish()
 	}()
 
+	if err = c.governor().Wait(ctx); err != nil {
+		return nil, err
+	}
+
 	resp, err = c.httpClient.Do(req.WithContext(ctx))
 	if err != nil {
 		return nil, err
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/peterhellberg/link"
 	"github.com/prometheus/client_golang/prometheus"
@@ -27,6 +28,54 @@ type Project struct {
 	Visibility        Visibility     `json:"visibility"`                    // "private", "internal", or "public"
 	ForkedFromProject *ProjectCommon `json:"forked_from_project,omitempty"` // If non-nil, the project from which this project was forked
 	Archived          bool           `json:"archived"`
+	TagList           []string       `json:"tag_list"`                   // topics the project is tagged with
+	StarCount         int            `json:"star_count"`                 // number of users who starred the project
+	LastActivityAt    *time.Time     `json:"last_activity_at,omitempty"` // when the project was last pushed to or otherwise active
+
+	// Permissions describes the requesting user's access to the project. It is only set when
+	// fetching a single project as an authenticated user. Because it is specific to the user (and
+	// the Sudo value, which is not part of the project cache key), callers that depend on it should
+	// bypass the cache (see CommonOp.NoCache).
+	Permissions *ProjectPermissions `json:"permissions,omitempty"`
+}
+
+// AccessLevel is a user's access level to a project or group
+// (https://docs.gitlab.com/ee/api/members.html).
+type AccessLevel int
+
+const (
+	GuestAccess      AccessLevel = 10
+	ReporterAccess   AccessLevel = 20
+	DeveloperAccess  AccessLevel = 30
+	MaintainerAccess AccessLevel = 40
+	OwnerAccess      AccessLevel = 50
+)
+
+// ProjectPermissions describes a user's access to a project, either through direct project membership
+// or through membership of the project's group.
+type ProjectPermissions struct {
+	ProjectAccess *MemberAccess `json:"project_access"`
+	GroupAccess   *MemberAccess `json:"group_access"`
+}
+
+// MemberAccess is the access a member has to a project or group.
+type MemberAccess struct {
+	AccessLevel AccessLevel `json:"access_level"`
+}
+
+// AccessLevel returns the highest access level granted by the project or group membership, or 0 if
+// the user is not a member of either.
+func (p *ProjectPermissions) AccessLevel() AccessLevel {
+	var level AccessLevel
+	if p == nil {
+		return level
+	}
+	for _, m := range []*MemberAccess{p.ProjectAccess, p.GroupAccess} {
+		if m != nil && m.AccessLevel > level {
+			level = m.AccessLevel
+		}
+	}
+	return level
 }
 
 type ProjectCommon struct {
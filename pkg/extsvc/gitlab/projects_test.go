@@ -139,3 +139,29 @@ func TestClient_GetProject_nonexistent(t *testing.T) {
 		t.Error("proj != nil")
 	}
 }
+
+func TestProjectPermissions_AccessLevel(t *testing.T) {
+	tests := map[string]struct {
+		perms *ProjectPermissions
+		want  AccessLevel
+	}{
+		"nil":           {perms: nil, want: 0},
+		"no membership": {perms: &ProjectPermissions{}, want: 0},
+		"project only":  {perms: &ProjectPermissions{ProjectAccess: &MemberAccess{AccessLevel: ReporterAccess}}, want: ReporterAccess},
+		"group only":    {perms: &ProjectPermissions{GroupAccess: &MemberAccess{AccessLevel: DeveloperAccess}}, want: DeveloperAccess},
+		"highest wins": {
+			perms: &ProjectPermissions{
+				ProjectAccess: &MemberAccess{AccessLevel: MaintainerAccess},
+				GroupAccess:   &MemberAccess{AccessLevel: GuestAccess},
+			},
+			want: MaintainerAccess,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := test.perms.AccessLevel(); got != test.want {
+				t.Errorf("got %d, want %d", got, test.want)
+			}
+		})
+	}
+}
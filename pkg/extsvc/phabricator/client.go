@@ -4,12 +4,14 @@ package phabricator
 import (
 	"context"
 	"encoding/json"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/pkg/httpcli"
+	"github.com/sourcegraph/sourcegraph/pkg/ratelimit"
 	"github.com/uber/gonduit"
 	"github.com/uber/gonduit/core"
 	"github.com/uber/gonduit/requests"
@@ -25,6 +27,11 @@ type Client struct {
 // This constructor needs a context because it calls the Conduit API to negotiate
 // capabilities as part of the dial process.
 func NewClient(ctx context.Context, url, token string, cli httpcli.Doer) (*Client, error) {
+	if cli == nil {
+		cli = http.DefaultClient
+	}
+	cli = ratelimit.GetGovernor(url, token).Doer(cli)
+
 	conn, err := gonduit.DialContext(ctx, url, &core.ClientOptions{
 		APIToken: token,
 		Client:   httpcli.HeadersMiddleware("User-Agent", "sourcegraph/phabricator-client")(cli),
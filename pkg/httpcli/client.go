@@ -3,7 +3,10 @@ package httpcli
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
+	"net"
 	"net/http"
+	"syscall"
 	"time"
 
 	"github.com/gregjones/httpcache"
@@ -204,6 +207,74 @@ func NewIdleConnTimeoutOpt(timeout time.Duration) Opt {
 	}
 }
 
+// PublicAddressesOpt configures an http.Client's transport to only connect to public IP
+// addresses (see IsPublicIP). The addresses are checked when dialing (after the host name is
+// resolved), so host names that resolve to internal addresses are also rejected. Proxies are not
+// used, because the proxy's address would be checked instead of the destination's.
+//
+// 🚨 SECURITY: Use this for requests to user-specified URLs, to prevent them from being used to
+// reach internal services.
+func PublicAddressesOpt(cli *http.Client) error {
+	tr, err := getTransportForMutation(cli)
+	if err != nil {
+		return errors.Wrap(err, "httpcli.PublicAddressesOpt")
+	}
+
+	tr.Proxy = nil
+	tr.DialContext = (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !IsPublicIP(ip) {
+				return fmt.Errorf("connecting to non-public address %s is not allowed", host)
+			}
+			return nil
+		},
+	}).DialContext
+
+	return nil
+}
+
+// nonPublicNetworks are the IP networks that are not publicly routable (other than the loopback,
+// link-local, and unspecified addresses, which IsPublicIP checks separately).
+var nonPublicNetworks = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",      // "this" network (RFC 1122)
+		"10.0.0.0/8",     // private (RFC 1918)
+		"100.64.0.0/10",  // carrier-grade NAT (RFC 6598)
+		"172.16.0.0/12",  // private (RFC 1918)
+		"192.168.0.0/16", // private (RFC 1918)
+		"fc00::/7",       // unique local (RFC 4193)
+	} {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+// IsPublicIP reports whether ip is a publicly routable unicast address, i.e., it is not a
+// loopback, link-local, private, multicast, or unspecified address.
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, n := range nonPublicNetworks {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
 // getTransport returns the http.Transport for cli. If Transport is nil, it is
 // set to a copy of the DefaultTransport. If it is the DefaultTransport, it is
 // updated to a copy of the DefaultTransport.
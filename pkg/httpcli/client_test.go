@@ -5,9 +5,11 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -239,6 +241,54 @@ func TestNewIdleConnTimeoutOpt(t *testing.T) {
 	}
 }
 
+func TestPublicAddressesOpt(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request to non-public address was sent")
+	}))
+	defer srv.Close()
+
+	var cli http.Client
+	if err := PublicAddressesOpt(&cli); err != nil {
+		t.Fatal(err)
+	}
+
+	// The test server listens on a loopback address.
+	_, err := cli.Get(srv.URL)
+	if err == nil || !strings.Contains(err.Error(), "non-public address") {
+		t.Fatalf("have error: %v\nwant error about a non-public address", err)
+	}
+
+	if err := PublicAddressesOpt(&http.Client{Transport: bogusTransport{}}); err == nil {
+		t.Fatal("have no error, want error for a transport that isn't an http.Transport")
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for ip, want := range map[string]bool{
+		"1.1.1.1":              true,
+		"2606:4700::1111":      true,
+		"127.0.0.1":            false,
+		"::1":                  false,
+		"::ffff:127.0.0.1":     false,
+		"0.0.0.0":              false,
+		"::":                   false,
+		"10.1.2.3":             false,
+		"172.16.0.1":           false,
+		"192.168.1.1":          false,
+		"100.64.0.1":           false,
+		"169.254.169.254":      false,
+		"fe80::1":              false,
+		"fd00::1":              false,
+		"224.0.0.1":            false,
+		"::ffff:192.168.1.1":   false,
+		"::ffff:93.184.216.34": true,
+	} {
+		if have := IsPublicIP(net.ParseIP(ip)); have != want {
+			t.Errorf("IsPublicIP(%s): have %v, want %v", ip, have, want)
+		}
+	}
+}
+
 func newFakeClient(code int, body []byte, err error) Doer {
 	return DoerFunc(func(r *http.Request) (*http.Response, error) {
 		rr := httptest.NewRecorder()
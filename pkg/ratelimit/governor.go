@@ -0,0 +1,340 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/httpcli"
+	"github.com/sourcegraph/sourcegraph/pkg/redispool"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// A Consumer is a class of callers that make requests to a code host. Each consumer gets its own
+// request budget, so that e.g. a long running repository sync cannot exhaust the rate limit that
+// user-facing requests rely on.
+type Consumer string
+
+const (
+	// ConsumerSync is for background repository syncing (repo-updater).
+	ConsumerSync Consumer = "sync"
+	// ConsumerPermissions is for fetching repository permissions (authz providers).
+	ConsumerPermissions Consumer = "permissions"
+	// ConsumerUserFacing is for requests made on behalf of a user (e.g. the frontend API). It is
+	// the default when no consumer is set in the context.
+	ConsumerUserFacing Consumer = "userFacing"
+)
+
+// Consumers is the list of all consumer classes.
+var Consumers = []Consumer{ConsumerSync, ConsumerPermissions, ConsumerUserFacing}
+
+type consumerKey struct{}
+
+// WithConsumer returns a copy of ctx whose code host requests are accounted against the budget of
+// the given consumer.
+func WithConsumer(ctx context.Context, c Consumer) context.Context {
+	return context.WithValue(ctx, consumerKey{}, c)
+}
+
+// ConsumerFromContext returns the consumer set in ctx by WithConsumer, or ConsumerUserFacing if
+// none is set.
+func ConsumerFromContext(ctx context.Context) Consumer {
+	if c, ok := ctx.Value(consumerKey{}).(Consumer); ok && c != "" {
+		return c
+	}
+	return ConsumerUserFacing
+}
+
+// budget returns the number of requests per hour the consumer may make to a single external
+// service, as configured in the "codeHostRequestBudgets" site configuration. A value <= 0 means
+// the consumer is not throttled.
+func budget(c Consumer) int {
+	b := conf.Get().CodeHostRequestBudgets
+	if b == nil {
+		return 0
+	}
+	switch c {
+	case ConsumerSync:
+		return b.Sync
+	case ConsumerPermissions:
+		return b.Permissions
+	case ConsumerUserFacing:
+		return b.UserFacing
+	}
+	return 0
+}
+
+// A Governor coordinates the requests that all Sourcegraph processes make to a single external
+// service (identified by its API URL and the credential used to talk to it). It implements a
+// Redis-backed token bucket per consumer, so that the budgets are shared across processes.
+type Governor struct {
+	// Name is a human readable name for the external service, typically its base URL.
+	Name string
+
+	key          string
+	registerOnce sync.Once
+	lastUsed     time.Time // protected by governorsMu
+}
+
+var (
+	governorsMu        sync.Mutex
+	governors          = map[string]*Governor{}
+	governorsLastEvict time.Time
+)
+
+// governorIdleTimeout is how long a Governor may go unused before GetGovernor removes it from
+// governors, so that the map does not grow without bound as credentials change (e.g. when users'
+// OAuth tokens are refreshed). An evicted Governor still works for callers that hold on to it,
+// because its token buckets are stored in Redis.
+const governorIdleTimeout = time.Hour
+
+// GetGovernor returns the Governor for the external service at the given base URL, accessed with
+// the given credential (e.g. an access token). The credential is never stored, only a checksum of
+// it is used to build the Redis key.
+func GetGovernor(baseURL, credential string) *Governor {
+	sum := sha256.Sum256([]byte(credential + ":" + baseURL))
+	key := base64.URLEncoding.EncodeToString(sum[:])
+
+	governorsMu.Lock()
+	defer governorsMu.Unlock()
+	t := now()
+	if t.Sub(governorsLastEvict) > governorIdleTimeout {
+		evictIdleGovernors(t)
+	}
+	g, ok := governors[key]
+	if !ok {
+		g = &Governor{Name: baseURL, key: key}
+		governors[key] = g
+	}
+	g.lastUsed = t
+	return g
+}
+
+// evictIdleGovernors removes the governors that have not been used within governorIdleTimeout
+// before t. The caller must hold governorsMu.
+func evictIdleGovernors(t time.Time) {
+	for key, g := range governors {
+		if t.Sub(g.lastUsed) > governorIdleTimeout {
+			delete(governors, key)
+		}
+	}
+	governorsLastEvict = t
+}
+
+// Wait blocks until the consumer set in ctx (see WithConsumer) has budget left to make a request
+// to the external service, and then accounts for the request. It returns an error only if ctx is
+// done before the request can be made. If Redis is unavailable, requests are not throttled.
+func (g *Governor) Wait(ctx context.Context) error {
+	if g == nil {
+		return nil
+	}
+
+	consumer := ConsumerFromContext(ctx)
+	perHour := budget(consumer)
+	if perHour <= 0 {
+		return nil
+	}
+
+	g.register()
+
+	governorsMu.Lock()
+	g.lastUsed = now()
+	governorsMu.Unlock()
+
+	startWait := time.Now()
+	for {
+		wait, _, err := g.take(consumer, perHour)
+		if err != nil {
+			log15.Warn("ratelimit: failed to consult request governor, not throttling", "service", g.Name, "error", err)
+			return nil
+		}
+		if wait <= 0 {
+			break
+		}
+
+		g.recordThrottled(consumer)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if d := time.Since(startWait); d > 200*time.Millisecond {
+		log15.Warn("ratelimit: request delayed by code host request budget", "service", g.Name, "consumer", consumer, "delay", d)
+	}
+	return nil
+}
+
+// Doer returns a Doer that calls Wait before each request it sends with cli. It is used by clients
+// that do not own their request loop (e.g. third-party API client libraries).
+func (g *Governor) Doer(cli httpcli.Doer) httpcli.Doer {
+	return httpcli.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		if err := g.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+		return cli.Do(req)
+	})
+}
+
+// bucketScript atomically refills and takes a token from the token bucket stored at KEYS[1]. It
+// returns the number of milliseconds to wait before a token is available (0 if one was taken) and
+// the number of whole tokens left in the bucket.
+//
+// ARGV: capacity, refill rate (tokens per second), current time (ms), cost.
+var bucketScript = redis.NewScript(1, `
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local cost = tonumber(ARGV[4])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+if now > ts then
+	tokens = math.min(capacity, tokens + (now - ts) / 1000 * rate)
+	ts = now
+end
+
+local wait = 0
+if tokens >= cost then
+	tokens = tokens - cost
+else
+	wait = math.ceil((cost - tokens) / rate * 1000)
+end
+
+redis.call("HMSET", KEYS[1], "tokens", tostring(tokens), "ts", tostring(ts), "capacity", tostring(capacity))
+redis.call("PEXPIRE", KEYS[1], math.ceil(capacity / rate * 1000))
+return {wait, math.floor(tokens)}
+`)
+
+func (g *Governor) take(c Consumer, perHour int) (wait time.Duration, remaining int, err error) {
+	conn := pool.Get()
+	defer conn.Close()
+
+	vals, err := redis.Ints(bucketScript.Do(conn,
+		g.bucketKey(c),
+		perHour,
+		float64(perHour)/time.Hour.Seconds(),
+		now().UnixNano()/int64(time.Millisecond),
+		1,
+	))
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(vals) != 2 {
+		return 0, 0, fmt.Errorf("unexpected reply from request governor script: %v", vals)
+	}
+	return time.Duration(vals[0]) * time.Millisecond, vals[1], nil
+}
+
+func (g *Governor) register() {
+	g.registerOnce.Do(func() {
+		conn := pool.Get()
+		defer conn.Close()
+		if _, err := conn.Do("HSET", registryKey(), g.key, g.Name); err != nil {
+			log15.Warn("ratelimit: failed to register request governor", "service", g.Name, "error", err)
+		}
+	})
+}
+
+func (g *Governor) recordThrottled(c Consumer) {
+	conn := pool.Get()
+	defer conn.Close()
+	if _, err := conn.Do("HSET", g.bucketKey(c), "throttled_at", strconv.FormatInt(now().Unix(), 10)); err != nil {
+		log15.Warn("ratelimit: failed to record throttled request", "service", g.Name, "error", err)
+	}
+}
+
+func (g *Governor) bucketKey(c Consumer) string {
+	return fmt.Sprintf("%s:%s:%s", keyPrefix, g.key, c)
+}
+
+func registryKey() string {
+	return keyPrefix + ":governors"
+}
+
+// BudgetStatus describes the state of a consumer's request budget for an external service.
+type BudgetStatus struct {
+	Service     string    // the Governor's Name
+	Consumer    Consumer  // the consumer class
+	Budget      int       // the configured requests per hour
+	Remaining   int       // the number of requests that can be made right now
+	ThrottledAt time.Time // the last time a request was delayed, if ever
+}
+
+// Throttled reports whether requests were delayed within the given window before now.
+func (s BudgetStatus) Throttled(window time.Duration) bool {
+	return !s.ThrottledAt.IsZero() && now().Sub(s.ThrottledAt) < window
+}
+
+// Statuses returns the current budget status of every consumer of every external service that has
+// made requests through a Governor (in any process) and has a configured budget.
+func Statuses() ([]BudgetStatus, error) {
+	conn := pool.Get()
+	defer conn.Close()
+
+	names, err := redis.StringMap(conn.Do("HGETALL", registryKey()))
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []BudgetStatus
+	for key, name := range names {
+		g := &Governor{Name: name, key: key}
+		for _, c := range Consumers {
+			perHour := budget(c)
+			if perHour <= 0 {
+				continue
+			}
+
+			vals, err := redis.Strings(conn.Do("HMGET", g.bucketKey(c), "tokens", "ts", "throttled_at"))
+			if err != nil {
+				return nil, err
+			}
+
+			s := BudgetStatus{Service: name, Consumer: c, Budget: perHour, Remaining: perHour}
+			if tokens, err := strconv.ParseFloat(vals[0], 64); err == nil {
+				// Account for the refill since the last request.
+				if ts, err := strconv.ParseInt(vals[1], 10, 64); err == nil {
+					elapsed := time.Duration(now().UnixNano()/int64(time.Millisecond)-ts) * time.Millisecond
+					tokens += elapsed.Hours() * float64(perHour)
+				}
+				if tokens < float64(perHour) {
+					s.Remaining = int(tokens)
+				}
+			}
+			if at, err := strconv.ParseInt(vals[2], 10, 64); err == nil {
+				s.ThrottledAt = time.Unix(at, 0)
+			}
+			statuses = append(statuses, s)
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Service != statuses[j].Service {
+			return statuses[i].Service < statuses[j].Service
+		}
+		return statuses[i].Consumer < statuses[j].Consumer
+	})
+	return statuses, nil
+}
+
+const keyPrefix = "ratelimit:v1"
+
+var (
+	pool = redispool.Cache
+	now  = time.Now
+)
@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestConsumerFromContext(t *testing.T) {
+	ctx := context.Background()
+	if got, want := ConsumerFromContext(ctx), ConsumerUserFacing; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := ConsumerFromContext(WithConsumer(ctx, ConsumerSync)), ConsumerSync; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestGetGovernor(t *testing.T) {
+	a := GetGovernor("https://github.example.com", "token-a")
+	if b := GetGovernor("https://github.example.com", "token-a"); a != b {
+		t.Error("expected the same governor for the same service and credential")
+	}
+	if b := GetGovernor("https://github.example.com", "token-b"); a == b {
+		t.Error("expected different governors for different credentials")
+	}
+}
+
+func TestGetGovernor_evictsIdle(t *testing.T) {
+	clock := time.Unix(1500000000, 0)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	idle := GetGovernor("https://github.example.com", "token-idle")
+	clock = clock.Add(governorIdleTimeout / 2)
+	used := GetGovernor("https://github.example.com", "token-used")
+
+	// After the idle timeout, the governor that was not used since is evicted.
+	clock = clock.Add(governorIdleTimeout/2 + time.Minute)
+	if g := GetGovernor("https://github.example.com", "token-used"); g != used {
+		t.Error("expected the recently used governor to be kept")
+	}
+	governorsMu.Lock()
+	_, ok := governors[idle.key]
+	governorsMu.Unlock()
+	if ok {
+		t.Error("expected the idle governor to be evicted")
+	}
+	if g := GetGovernor("https://github.example.com", "token-idle"); g == idle || g.key != idle.key {
+		t.Error("expected a new governor for the same service and credential after eviction")
+	}
+}
+
+func TestGovernor_Wait(t *testing.T) {
+	setupRedisForTest(t)
+
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		CodeHostRequestBudgets: &schema.CodeHostRequestBudgets{Sync: 2},
+	}})
+	defer conf.Mock(nil)
+
+	clock := time.Unix(1500000000, 0)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	g := GetGovernor("https://github.example.com", t.Name())
+	deleteKeys(t, g.bucketKey(ConsumerSync))
+
+	// The bucket starts full, so the first two requests are not delayed.
+	for i := 0; i < 2; i++ {
+		if wait, _, err := g.take(ConsumerSync, budget(ConsumerSync)); err != nil || wait != 0 {
+			t.Fatalf("request %d: got wait %s, err %v; want no wait", i, wait, err)
+		}
+	}
+
+	// The third request has to wait for the bucket to refill one token (30 minutes at 2/hour).
+	wait, remaining, err := g.take(ConsumerSync, budget(ConsumerSync))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 30 * time.Minute; wait < want || wait > want+time.Second {
+		t.Errorf("got wait %s, want %s", wait, want)
+	}
+	if remaining != 0 {
+		t.Errorf("got remaining %d, want 0", remaining)
+	}
+
+	// Waiting gives up when the context is done.
+	ctx, cancel := context.WithTimeout(WithConsumer(context.Background(), ConsumerSync), 10*time.Millisecond)
+	defer cancel()
+	if err := g.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("got err %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// Consumers without a budget are never delayed.
+	if err := g.Wait(context.Background()); err != nil {
+		t.Errorf("user-facing request: got err %v, want nil", err)
+	}
+
+	statuses, err := Statuses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 1 {
+		t.Fatalf("got %d statuses, want 1: %+v", len(statuses), statuses)
+	}
+	if s := statuses[0]; s.Consumer != ConsumerSync || s.Remaining != 0 || !s.Throttled(time.Minute) {
+		t.Errorf("unexpected status %+v", s)
+	}
+}
+
+func setupRedisForTest(t *testing.T) {
+	t.Helper()
+
+	pool = &redis.Pool{
+		MaxIdle:     3,
+		IdleTimeout: 240 * time.Second,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", "127.0.0.1:6379")
+		},
+	}
+
+	c := pool.Get()
+	defer c.Close()
+
+	// If we are not on CI, skip the test if our redis connection fails.
+	if _, err := c.Do("PING"); err != nil {
+		if os.Getenv("CI") == "" {
+			t.Skip("could not connect to redis", err)
+		}
+		t.Fatal(err)
+	}
+
+	deleteKeys(t, registryKey())
+}
+
+func deleteKeys(t *testing.T, keys ...interface{}) {
+	t.Helper()
+
+	c := pool.Get()
+	defer c.Close()
+
+	if _, err := c.Do("DEL", keys...); err != nil {
+		t.Fatal(err)
+	}
+}
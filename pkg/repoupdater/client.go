@@ -203,6 +203,38 @@ func (c *Client) SyncExternalService(ctx context.Context, svc api.ExternalServic
 	return &result, nil
 }
 
+// MockExternalServiceDryRun mocks (*Client).ExternalServiceDryRun for tests.
+var MockExternalServiceDryRun func(ctx context.Context, svc api.ExternalService) (*protocol.ExternalServiceDryRunResult, error)
+
+// ExternalServiceDryRun returns the changes that syncing the given external service would make to
+// the stored repositories, without making them.
+func (c *Client) ExternalServiceDryRun(ctx context.Context, svc api.ExternalService) (*protocol.ExternalServiceDryRunResult, error) {
+	if MockExternalServiceDryRun != nil {
+		return MockExternalServiceDryRun(ctx, svc)
+	}
+
+	req := &protocol.ExternalServiceDryRunRequest{ExternalService: svc}
+	resp, err := c.httpPost(ctx, "external-service-dry-run", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bs, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+
+	var res protocol.ExternalServiceDryRunResult
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return nil, errors.New(string(bs))
+	} else if err = json.Unmarshal(bs, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
 // RepoExternalServices requests the external services associated with a
 // repository with the given id.
 func (c *Client) RepoExternalServices(ctx context.Context, id uint32) ([]api.ExternalService, error) {
@@ -257,6 +289,36 @@ func (c *Client) ExcludeRepo(ctx context.Context, id uint32) (*protocol.ExcludeR
 	return &res, nil
 }
 
+// MockRecordRepoDemand mocks (*Client).RecordRepoDemand for tests.
+var MockRecordRepoDemand func(ctx context.Context, kind protocol.RepoDemandKind, ids ...uint32) error
+
+// RecordRepoDemand reports that users are interested in the repositories with
+// the given IDs, so that repo-updater keeps them more up to date.
+func (c *Client) RecordRepoDemand(ctx context.Context, kind protocol.RepoDemandKind, ids ...uint32) error {
+	if MockRecordRepoDemand != nil {
+		return MockRecordRepoDemand(ctx, kind, ids...)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	req := protocol.RepoDemandRequest{Kind: kind, IDs: ids}
+	resp, err := c.httpPost(ctx, "record-repo-demand", &req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		bs, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return errors.Wrap(err, "failed to read response body")
+		}
+		return errors.New(string(bs))
+	}
+	return nil
+}
+
 // MockStatusMessages mocks (*Client).StatusMessages for tests.
 var MockStatusMessages func(context.Context) (*protocol.StatusMessagesResponse, error)
 
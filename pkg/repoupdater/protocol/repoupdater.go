@@ -26,6 +26,32 @@ type RepoScheduleState struct {
 	Total           int
 	IntervalSeconds int
 	Due             time.Time
+
+	// Demand is how much users are interested in the repo ("hot", "warm" or
+	// "cold"), based on the demand signals recorded with RepoDemandRequest.
+	Demand string `json:",omitempty"`
+	// IntervalReason explains how IntervalSeconds was chosen.
+	IntervalReason string `json:",omitempty"`
+}
+
+// RepoDemandKind is a kind of signal that users are interested in a repo.
+type RepoDemandKind string
+
+const (
+	// RepoDemandView signals that a repo was viewed.
+	RepoDemandView RepoDemandKind = "view"
+	// RepoDemandSearch signals that a repo was searched by a search scoped to
+	// a small number of repos.
+	RepoDemandSearch RepoDemandKind = "search"
+	// RepoDemandSavedSearch signals that a repo was searched by a saved search.
+	RepoDemandSavedSearch RepoDemandKind = "saved-search"
+)
+
+// RepoDemandRequest is a request to record that users are interested in
+// repos, which makes the scheduler update them more often.
+type RepoDemandRequest struct {
+	Kind RepoDemandKind
+	IDs  []uint32
 }
 
 type RepoQueueState struct {
@@ -174,10 +200,31 @@ type ExternalServiceSyncResult struct {
 	Error           string
 }
 
+// ExternalServiceDryRunRequest is a request to preview the changes that syncing an external
+// service with the given configuration would make to the stored repositories. Nothing is written.
+//
+// If ExternalService.ID is zero, the external service is previewed as a new one.
+type ExternalServiceDryRunRequest struct {
+	ExternalService api.ExternalService
+}
+
+// ExternalServiceDryRunResult is the result of an ExternalServiceDryRunRequest.
+type ExternalServiceDryRunResult struct {
+	// Added are the names of the repositories that the external service would add.
+	Added []api.RepoName
+	// Removed are the names of the stored repositories that the external service would no longer yield.
+	Removed []api.RepoName
+	// Modified are the names of the stored repositories whose metadata would change.
+	Modified []api.RepoName
+	// Unmodified is the number of stored repositories that would be left unchanged.
+	Unmodified int
+}
+
 type StatusMessageType string
 
 const (
-	CloningStatusMessage StatusMessageType = "CLONING"
+	CloningStatusMessage   StatusMessageType = "CLONING"
+	RateLimitStatusMessage StatusMessageType = "RATE_LIMIT"
 )
 
 type StatusMessage struct {
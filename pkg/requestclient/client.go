@@ -0,0 +1,46 @@
+// Package requestclient records information about the client that made the current HTTP request.
+package requestclient
+
+import (
+	"context"
+	"net"
+	"net/http"
+)
+
+// Client describes the client that made an HTTP request.
+type Client struct {
+	// IP is the IP address of the direct peer of the server (which may be a proxy).
+	IP string
+
+	// ForwardedFor is the value of the X-Forwarded-For header, if any. It is supplied by the
+	// client (or proxies), so it must not be trusted for security decisions.
+	ForwardedFor string
+}
+
+type clientKey struct{}
+
+// WithClient returns a copy of the context with the given client.
+func WithClient(ctx context.Context, client *Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// FromContext returns the client of the HTTP request that the context is for, or nil if there is
+// none.
+func FromContext(ctx context.Context) *Client {
+	client, _ := ctx.Value(clientKey{}).(*Client)
+	return client
+}
+
+// HTTPMiddleware records the client of each HTTP request in the request's context.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+		next.ServeHTTP(w, r.WithContext(WithClient(r.Context(), &Client{
+			IP:           ip,
+			ForwardedFor: r.Header.Get("X-Forwarded-For"),
+		})))
+	})
+}
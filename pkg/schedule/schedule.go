@@ -0,0 +1,205 @@
+// Package schedule parses and evaluates schedules for recurring jobs, expressed
+// either as an interval (such as "@every 1h") or as a cron expression (such as
+// "0 9 * * 1-5").
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MinInterval is the shortest interval that a schedule may have.
+const MinInterval = time.Minute
+
+// Schedule describes when a recurring job runs.
+type Schedule interface {
+	// Next returns the first time after t that the job runs. The zero time is
+	// returned if the job never runs after t.
+	Next(t time.Time) time.Time
+}
+
+// Parse parses a schedule. The following formats are supported:
+//
+//	@every <duration>   run at a fixed interval (such as "@every 30m")
+//	@hourly             run at the start of every hour ("0 * * * *")
+//	@daily, @midnight   run at midnight every day ("0 0 * * *")
+//	@weekly             run at midnight every Sunday ("0 0 * * 0")
+//	@monthly            run at midnight on the first day of every month ("0 0 1 * *")
+//	<cron expression>   a standard 5-field cron expression (minute, hour,
+//	                    day of month, month, and day of week)
+//
+// Cron fields may be "*", a number, a range ("1-5"), a step ("*/15" or
+// "0-30/10"), or a comma-separated list of these. In the day-of-week field,
+// both 0 and 7 are Sunday. Cron expressions are evaluated in UTC.
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %s", spec, err)
+		}
+		if d < MinInterval {
+			return nil, fmt.Errorf("invalid schedule %q: interval must be at least %s", spec, MinInterval)
+		}
+		return Interval(d), nil
+	}
+	switch spec {
+	case "@hourly":
+		spec = "0 * * * *"
+	case "@daily", "@midnight":
+		spec = "0 0 * * *"
+	case "@weekly":
+		spec = "0 0 * * 0"
+	case "@monthly":
+		spec = "0 0 1 * *"
+	}
+	return parseCron(spec)
+}
+
+// Interval is a schedule that runs a job at a fixed interval.
+type Interval time.Duration
+
+// Next implements Schedule.
+func (i Interval) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(i))
+}
+
+// cron is a schedule described by a cron expression. Each field is a bitset of
+// the values that match.
+type cron struct {
+	minute, hour, dom, month, dow uint64
+
+	// domStar and dowStar record whether the day-of-month and day-of-week
+	// fields were "*". If neither is, a day matches if either field matches
+	// (as in standard cron).
+	domStar, dowStar bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+func parseCron(spec string) (*cron, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("invalid schedule %q: expected @every <duration> or a cron expression with %d fields", spec, len(cronFields))
+	}
+	var bits [5]uint64
+	for i, f := range fields {
+		b, err := parseCronField(f, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %s", spec, err)
+		}
+		bits[i] = b
+	}
+	c := &cron{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}
+	if c.dow&(1<<7) != 0 {
+		// 7 is Sunday, like 0.
+		c.dow |= 1
+	}
+	return c, nil
+}
+
+func parseCronField(s string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(s, ",") {
+		rangeStr, step := part, 1
+		if i := strings.Index(part, "/"); i != -1 {
+			var err error
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field %q", f.name, part)
+			}
+			rangeStr = part[:i]
+		}
+
+		var lo, hi int
+		switch {
+		case rangeStr == "*":
+			lo, hi = f.min, f.max
+		case strings.Contains(rangeStr, "-"):
+			i := strings.Index(rangeStr, "-")
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(rangeStr[:i])
+			hi, err2 = strconv.Atoi(rangeStr[i+1:])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("invalid range in %s field %q", f.name, part)
+			}
+		default:
+			n, err := strconv.Atoi(rangeStr)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value in %s field %q", f.name, part)
+			}
+			lo, hi = n, n
+			if step != 1 {
+				// "N/S" means from N to the maximum, every S.
+				hi = f.max
+			}
+		}
+		if lo < f.min || hi > f.max || lo > hi {
+			return 0, fmt.Errorf("%s field %q is out of range (%d-%d)", f.name, part, f.min, f.max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// maxCronSearchYears is how far in the future Next looks for a matching time
+// before concluding that the schedule never matches (e.g., "0 0 31 2 *").
+const maxCronSearchYears = 5
+
+// Next implements Schedule.
+func (c *cron) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + maxCronSearchYears
+	for t.Year() <= limit {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, time.UTC)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (c *cron) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,78 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse_invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"@every",
+		"@every 1x",
+		"@every 30s",
+		"@yearly",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("%q: got nil error, want error", spec)
+		}
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	mustParseTime := func(s string) time.Time {
+		t2, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return t2
+	}
+
+	// 2019-03-06 is a Wednesday.
+	tests := []struct {
+		spec string
+		from string
+		want string
+	}{
+		{"@every 90m", "2019-03-06T10:15:30Z", "2019-03-06T11:45:30Z"},
+		{"@hourly", "2019-03-06T10:15:30Z", "2019-03-06T11:00:00Z"},
+		{"@daily", "2019-03-06T10:15:30Z", "2019-03-07T00:00:00Z"},
+		{"@weekly", "2019-03-06T10:15:30Z", "2019-03-10T00:00:00Z"},
+		{"@monthly", "2019-03-06T10:15:30Z", "2019-04-01T00:00:00Z"},
+		{"* * * * *", "2019-03-06T10:15:30Z", "2019-03-06T10:16:00Z"},
+		{"* * * * *", "2019-03-06T10:15:00Z", "2019-03-06T10:16:00Z"},
+		{"*/15 * * * *", "2019-03-06T10:15:00Z", "2019-03-06T10:30:00Z"},
+		{"5/20 * * * *", "2019-03-06T10:46:00Z", "2019-03-06T11:05:00Z"},
+		{"0,30 9-17 * * *", "2019-03-06T17:30:00Z", "2019-03-07T09:00:00Z"},
+		{"0 9 * * 1-5", "2019-03-08T09:00:00Z", "2019-03-11T09:00:00Z"},
+		{"0 0 * * 7", "2019-03-06T10:15:30Z", "2019-03-10T00:00:00Z"},
+		{"0 0 31 * *", "2019-04-01T00:00:00Z", "2019-05-31T00:00:00Z"},
+		{"0 0 29 2 *", "2019-03-06T10:15:30Z", "2020-02-29T00:00:00Z"},
+		{"0 0 13 * 5", "2019-03-06T10:15:30Z", "2019-03-08T00:00:00Z"}, // day of month OR day of week
+		{"0 0 31 2 *", "2019-03-06T10:15:30Z", "0001-01-01T00:00:00Z"}, // never
+		{"30 23 31 12 *", "2019-12-31T23:30:00Z", "2020-12-31T23:30:00Z"},
+		{"0 12 * * *", "2019-03-06T10:15:30-05:00", "2019-03-07T12:00:00Z"}, // evaluated in UTC
+	}
+	for _, test := range tests {
+		t.Run(test.spec+" from "+test.from, func(t *testing.T) {
+			s, err := Parse(test.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := s.Next(mustParseTime(test.from))
+			if want := mustParseTime(test.want); !got.Equal(want) {
+				t.Errorf("got %s, want %s", got.Format(time.RFC3339), want.Format(time.RFC3339))
+			}
+		})
+	}
+}
@@ -99,7 +99,7 @@ const CriticalSchemaJSON = `{
         "properties": {
           "type": {
             "type": "string",
-            "enum": ["builtin", "saml", "openidconnect", "http-header", "github", "gitlab"]
+            "enum": ["builtin", "saml", "openidconnect", "http-header", "github", "gitlab", "ldap"]
           }
         },
         "oneOf": [
@@ -108,7 +108,8 @@ const CriticalSchemaJSON = `{
           { "$ref": "#/definitions/OpenIDConnectAuthProvider" },
           { "$ref": "#/definitions/HTTPHeaderAuthProvider" },
           { "$ref": "#/definitions/GitHubAuthProvider" },
-          { "$ref": "#/definitions/GitLabAuthProvider" }
+          { "$ref": "#/definitions/GitLabAuthProvider" },
+          { "$ref": "#/definitions/LDAPAuthProvider" }
         ],
         "!go": {
           "taggedUnionType": true
@@ -130,6 +131,12 @@ const CriticalSchemaJSON = `{
       "examples": ["168h"],
       "group": "Authentication"
     },
+    "auth.userApprovalRequired": {
+      "description": "Requires a site admin to approve user accounts that are automatically created on sign-in via an external authentication provider (such as SAML, OpenID Connect, GitHub, GitLab, or an HTTP authentication proxy). Until they are approved, these users can't sign in. Site admins can approve pending users on the site admin users page.",
+      "type": "boolean",
+      "default": false,
+      "group": "Authentication"
+    },
     "auth.disableUsernameChanges": {
       "description": "WARNING: This option has been removed in favor of ` + "`" + `auth.enableUsernameChanges` + "`" + `. As of 3.3, it has no effect, and as of 3.4, it will be removed entirely.",
       "type": "boolean",
@@ -164,6 +171,12 @@ const CriticalSchemaJSON = `{
           "description": "Allows new visitors to sign up for accounts. The sign-up page will be enabled and accessible to all visitors.\n\nSECURITY: If the site has no users (i.e., during initial setup), it will always allow the first user to sign up and become site admin **without any approval** (first user to sign up becomes the admin).",
           "type": "boolean",
           "default": false
+        },
+        "requireMFA": {
+          "description": "Which users who sign in with a username and password must use multi-factor authentication (a time-based one-time password from an authenticator app). Users who are required to use it but have not enrolled are asked to enroll when they sign in.\n\nUsers who are not required to use multi-factor authentication may still enroll voluntarily.",
+          "type": "string",
+          "enum": ["none", "site-admins", "all"],
+          "default": "none"
         }
       }
     },
@@ -359,6 +372,114 @@ const CriticalSchemaJSON = `{
         "displayName": { "$ref": "#/definitions/AuthProviderCommon/properties/displayName" }
       }
     },
+    "LDAPAuthProvider": {
+      "description": "Configures the LDAP authentication provider, which authenticates users with a username and password against an LDAP directory (such as Active Directory or OpenLDAP). When a user signs in, Sourcegraph searches for the user's directory entry (optionally binding as a service account first) and then verifies the password by binding as that entry.",
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["type", "url", "userSearchBase"],
+      "properties": {
+        "type": {
+          "type": "string",
+          "const": "ldap"
+        },
+        "displayName": { "$ref": "#/definitions/AuthProviderCommon/properties/displayName" },
+        "url": {
+          "description": "The URL of the LDAP server. Use the ldaps:// scheme to connect over TLS, or set ` + "`" + `startTLS` + "`" + ` to upgrade an ldap:// connection to TLS. The default ports are 389 (ldap) and 636 (ldaps).",
+          "type": "string",
+          "pattern": "^ldaps?://",
+          "examples": ["ldaps://ldap.example.com", "ldap://ad.example.com:389"]
+        },
+        "startTLS": {
+          "description": "Upgrade the connection to TLS with the StartTLS extended operation before sending any credentials. Has no effect for ldaps:// URLs.",
+          "type": "boolean",
+          "default": false
+        },
+        "tls": {
+          "description": "Configures how the TLS certificate of the LDAP server is verified (for ldaps:// URLs and StartTLS).",
+          "title": "LDAPTLSConfig",
+          "type": "object",
+          "additionalProperties": false,
+          "properties": {
+            "ca": {
+              "description": "The PEM-encoded certificate(s) of the certificate authorities that the LDAP server's certificate must be signed by. Only necessary if the certificate is self-signed or signed by an internal CA. If empty, the system's certificate authorities are used.",
+              "type": "string",
+              "pattern": "^-----BEGIN CERTIFICATE-----\n",
+              "examples": ["-----BEGIN CERTIFICATE-----\n..."]
+            },
+            "insecureSkipVerify": {
+              "description": "Don't verify the LDAP server's certificate. This allows an attacker on the network to intercept the credentials of users who sign in, so it should only be used for testing.",
+              "type": "boolean",
+              "default": false
+            }
+          }
+        },
+        "bindDN": {
+          "description": "The DN of the service account to bind as when searching for users and groups. If empty, searches are performed anonymously.",
+          "type": "string",
+          "examples": ["cn=sourcegraph,ou=services,dc=example,dc=com"]
+        },
+        "bindPassword": {
+          "description": "The password of the service account specified in ` + "`" + `bindDN` + "`" + `.",
+          "type": "string"
+        },
+        "userSearchBase": {
+          "description": "The DN of the subtree in which to search for users.",
+          "type": "string",
+          "examples": ["ou=people,dc=example,dc=com"]
+        },
+        "userSearchFilter": {
+          "description": "The LDAP filter that matches the directory entry of the user who is signing in. The string ` + "`" + `{username}` + "`" + ` is replaced with the (escaped) username entered on the sign-in form. It must match at most 1 entry.",
+          "type": "string",
+          "default": "(uid={username})",
+          "examples": ["(&(objectClass=user)(sAMAccountName={username}))"]
+        },
+        "usernameAttribute": {
+          "description": "The attribute of the user's directory entry that contains the Sourcegraph username. If the entry has no such attribute, the username entered on the sign-in form is used.",
+          "type": "string",
+          "default": "uid",
+          "examples": ["sAMAccountName"]
+        },
+        "emailAttribute": {
+          "description": "The attribute of the user's directory entry that contains the user's email address. Email addresses from the directory are assumed to be verified.",
+          "type": "string",
+          "default": "mail"
+        },
+        "displayNameAttribute": {
+          "description": "The attribute of the user's directory entry that contains the user's display name.",
+          "type": "string",
+          "default": "cn",
+          "examples": ["displayName"]
+        },
+        "groupSearchBase": {
+          "description": "The DN of the subtree in which to search for the groups that the user is a member of. If empty, group memberships are not looked up (and ` + "`" + `groupOrgMap` + "`" + ` has no effect).",
+          "type": "string",
+          "examples": ["ou=groups,dc=example,dc=com"]
+        },
+        "groupSearchFilter": {
+          "description": "The LDAP filter that matches the groups that the user is a member of. The string ` + "`" + `{dn}` + "`" + ` is replaced with the (escaped) DN of the user's directory entry, and ` + "`" + `{username}` + "`" + ` with the (escaped) username entered on the sign-in form.",
+          "type": "string",
+          "default": "(member={dn})",
+          "examples": ["(&(objectClass=posixGroup)(memberUid={username}))"]
+        },
+        "groupOrgMap": {
+          "description": "Ensures that members of the specified LDAP groups are members of the specified Sourcegraph organizations. Each key is the DN of an LDAP group, and each value is a list of organization names. When a user signs in, they are added to the organizations of the groups they are a member of and removed from the (mapped) organizations of the groups they are no longer a member of. Organizations must already exist.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "array",
+            "items": {
+              "type": "string"
+            }
+          },
+          "examples": [{ "cn=engineering,ou=groups,dc=example,dc=com": ["engineering"] }]
+        },
+        "allowSignup": {
+          "description": "Allows users who can authenticate against the LDAP directory to sign in even if they don't have a Sourcegraph account yet (an account is created for them). If false, users must have an existing Sourcegraph account with the same verified email address, which will be linked to their directory entry after sign-in.",
+          "type": "boolean",
+          "default": true,
+          "!go": { "pointer": true }
+        }
+      }
+    },
     "AuthProviderCommon": {
       "$comment": "This schema is not used directly. The *AuthProvider schemas refer to its properties directly.",
       "description": "Common properties for authentication providers.",
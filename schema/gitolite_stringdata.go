@@ -75,6 +75,13 @@ const GitoliteSchemaJSON = `{
           "type": "string"
         }
       }
+    },
+    "authorization": {
+      "title": "GitoliteAuthorization",
+      "description": "If non-null, restricts access to the repositories of this connection to the users that are explicitly granted access. Access is granted by site admins with the ` + "`" + `setRepositoryPermissionsForUsers` + "`" + ` and ` + "`" + `setRepositoryPatternPermissionsForUsers` + "`" + ` GraphQL mutations.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {}
     }
   }
 }
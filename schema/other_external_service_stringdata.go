@@ -33,6 +33,13 @@ const OtherExternalServiceSchemaJSON = `{
         "format": "uri-reference",
         "examples": ["path/to/my/repo", "path/to/my/repo.git/"]
       }
+    },
+    "authorization": {
+      "title": "OtherExternalServiceAuthorization",
+      "description": "If non-null, restricts access to the repositories of this connection to the users that are explicitly granted access. Access is granted by site admins with the ` + "`" + `setRepositoryPermissionsForUsers` + "`" + ` and ` + "`" + `setRepositoryPatternPermissionsForUsers` + "`" + ` GraphQL mutations.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {}
     }
   }
 }
@@ -28,6 +28,19 @@ type AWSCodeCommitGitCredentials struct {
 	Username string `json:"username"`
 }
 
+// AuditLog description: Settings for the audit log, which records security-relevant actions (such as site configuration changes, site admin promotions and access token creation). Entries are always stored in the database and can be viewed by site admins. They can also be written to a file or to syslog.
+type AuditLog struct {
+	File   string          `json:"file,omitempty"`
+	Syslog *AuditLogSyslog `json:"syslog,omitempty"`
+}
+
+// AuditLogSyslog description: Sends each audit log entry as JSON to a syslog server.
+type AuditLogSyslog struct {
+	Address string `json:"address"`
+	Network string `json:"network,omitempty"`
+	Tag     string `json:"tag,omitempty"`
+}
+
 // AuthAccessTokens description: Settings for access tokens, which enable external tools to access the Sourcegraph API with the privileges of the user.
 type AuthAccessTokens struct {
 	Allow string `json:"allow,omitempty"`
@@ -44,6 +57,7 @@ type AuthProviders struct {
 	HttpHeader    *HTTPHeaderAuthProvider
 	Github        *GitHubAuthProvider
 	Gitlab        *GitLabAuthProvider
+	Ldap          *LDAPAuthProvider
 }
 
 func (v AuthProviders) MarshalJSON() ([]byte, error) {
@@ -65,6 +79,9 @@ func (v AuthProviders) MarshalJSON() ([]byte, error) {
 	if v.Gitlab != nil {
 		return json.Marshal(v.Gitlab)
 	}
+	if v.Ldap != nil {
+		return json.Marshal(v.Ldap)
+	}
 	return nil, errors.New("tagged union type must have exactly 1 non-nil field value")
 }
 func (v *AuthProviders) UnmarshalJSON(data []byte) error {
@@ -83,12 +100,14 @@ func (v *AuthProviders) UnmarshalJSON(data []byte) error {
 		return json.Unmarshal(data, &v.Gitlab)
 	case "http-header":
 		return json.Unmarshal(data, &v.HttpHeader)
+	case "ldap":
+		return json.Unmarshal(data, &v.Ldap)
 	case "openidconnect":
 		return json.Unmarshal(data, &v.Openidconnect)
 	case "saml":
 		return json.Unmarshal(data, &v.Saml)
 	}
-	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"builtin", "saml", "openidconnect", "http-header", "github", "gitlab"})
+	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"builtin", "saml", "openidconnect", "http-header", "github", "gitlab", "ldap"})
 }
 
 // BitbucketCloudConnection description: Configuration for a connection to Bitbucket Cloud.
@@ -176,6 +195,7 @@ type Branding struct {
 // BuiltinAuthProvider description: Configures the builtin username-password authentication provider.
 type BuiltinAuthProvider struct {
 	AllowSignup bool   `json:"allowSignup,omitempty"`
+	RequireMFA  string `json:"requireMFA,omitempty"`
 	Type        string `json:"type"`
 }
 
@@ -185,6 +205,13 @@ type CloneURLToRepositoryName struct {
 	To   string `json:"to"`
 }
 
+// CodeHostRequestBudgets description: Budgets (in requests per hour) for the requests Sourcegraph makes to each code host connection, by class of consumer. Budgets are shared across all Sourcegraph processes and apply separately to each code host and credential. A consumer whose budget is unset or 0 is not throttled.
+type CodeHostRequestBudgets struct {
+	Permissions int `json:"permissions,omitempty"`
+	Sync        int `json:"sync,omitempty"`
+	UserFacing  int `json:"userFacing,omitempty"`
+}
+
 // CriticalConfiguration description: Critical configuration for a Sourcegraph site.
 type CriticalConfiguration struct {
 	AuthDisableUsernameChanges bool                `json:"auth.disableUsernameChanges,omitempty"`
@@ -192,6 +219,7 @@ type CriticalConfiguration struct {
 	AuthProviders              []AuthProviders     `json:"auth.providers,omitempty"`
 	AuthPublic                 bool                `json:"auth.public,omitempty"`
 	AuthSessionExpiry          string              `json:"auth.sessionExpiry,omitempty"`
+	AuthUserApprovalRequired   bool                `json:"auth.userApprovalRequired,omitempty"`
 	AuthUserOrgMap             map[string][]string `json:"auth.userOrgMap,omitempty"`
 	ExternalURL                string              `json:"externalURL,omitempty"`
 	HtmlBodyBottom             string              `json:"htmlBodyBottom,omitempty"`
@@ -315,8 +343,13 @@ type GitLabProject struct {
 	Name string `json:"name,omitempty"`
 }
 
+// GitoliteAuthorization description: If non-null, restricts access to the repositories of this connection to the users that are explicitly granted access. Access is granted by site admins with the `setRepositoryPermissionsForUsers` and `setRepositoryPatternPermissionsForUsers` GraphQL mutations.
+type GitoliteAuthorization struct {
+}
+
 // GitoliteConnection description: Configuration for a connection to Gitolite.
 type GitoliteConnection struct {
+	Authorization              *GitoliteAuthorization  `json:"authorization,omitempty"`
 	Blacklist                  string                  `json:"blacklist,omitempty"`
 	Exclude                    []*ExcludedGitoliteRepo `json:"exclude,omitempty"`
 	Host                       string                  `json:"host"`
@@ -377,6 +410,32 @@ func (v *IdentityProvider) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"oauth", "username", "external"})
 }
 
+// LDAPAuthProvider description: Configures the LDAP authentication provider, which authenticates users with a username and password against an LDAP directory (such as Active Directory or OpenLDAP). When a user signs in, Sourcegraph searches for the user's directory entry (optionally binding as a service account first) and then verifies the password by binding as that entry.
+type LDAPAuthProvider struct {
+	AllowSignup          *bool               `json:"allowSignup,omitempty"`
+	BindDN               string              `json:"bindDN,omitempty"`
+	BindPassword         string              `json:"bindPassword,omitempty"`
+	DisplayName          string              `json:"displayName,omitempty"`
+	DisplayNameAttribute string              `json:"displayNameAttribute,omitempty"`
+	EmailAttribute       string              `json:"emailAttribute,omitempty"`
+	GroupOrgMap          map[string][]string `json:"groupOrgMap,omitempty"`
+	GroupSearchBase      string              `json:"groupSearchBase,omitempty"`
+	GroupSearchFilter    string              `json:"groupSearchFilter,omitempty"`
+	StartTLS             bool                `json:"startTLS,omitempty"`
+	Tls                  *LDAPTLSConfig      `json:"tls,omitempty"`
+	Type                 string              `json:"type"`
+	Url                  string              `json:"url"`
+	UserSearchBase       string              `json:"userSearchBase"`
+	UserSearchFilter     string              `json:"userSearchFilter,omitempty"`
+	UsernameAttribute    string              `json:"usernameAttribute,omitempty"`
+}
+
+// LDAPTLSConfig description: Configures how the TLS certificate of the LDAP server is verified (for ldaps:// URLs and StartTLS).
+type LDAPTLSConfig struct {
+	Ca                 string `json:"ca,omitempty"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty"`
+}
+
 // Log description: Configuration for logging and alerting, including to external services.
 type Log struct {
 	Sentry *Sentry `json:"sentry,omitempty"`
@@ -401,10 +460,15 @@ type OpenIDConnectAuthProvider struct {
 	Type               string `json:"type"`
 }
 
+// OtherExternalServiceAuthorization description: If non-null, restricts access to the repositories of this connection to the users that are explicitly granted access. Access is granted by site admins with the `setRepositoryPermissionsForUsers` and `setRepositoryPatternPermissionsForUsers` GraphQL mutations.
+type OtherExternalServiceAuthorization struct {
+}
+
 // OtherExternalServiceConnection description: Configuration for a Connection to Git repositories for which an external service integration isn't yet available.
 type OtherExternalServiceConnection struct {
-	Repos []string `json:"repos"`
-	Url   string   `json:"url,omitempty"`
+	Authorization *OtherExternalServiceAuthorization `json:"authorization,omitempty"`
+	Repos         []string                           `json:"repos"`
+	Url           string                             `json:"url,omitempty"`
 }
 
 // ParentSourcegraph description: URL to fetch unreachable repository details from. Defaults to "https://sourcegraph.com"
@@ -412,6 +476,12 @@ type ParentSourcegraph struct {
 	Url string `json:"url,omitempty"`
 }
 
+// PermissionsBackgroundSync description: Syncs repository permissions from code hosts with authorization configured in the background, and enforces them with a database join instead of querying the code host when a user accesses repositories. A user's permissions are synced when they sign in and then periodically. Repositories that were added after a user's permissions were last synced are not visible to them until the next sync.
+type PermissionsBackgroundSync struct {
+	Enabled      bool `json:"enabled,omitempty"`
+	UserInterval int  `json:"userInterval,omitempty"`
+}
+
 // Phabricator description: Phabricator instance that integrates with this Gitolite instance
 type Phabricator struct {
 	CallsignCommand string `json:"callsignCommand"`
@@ -451,6 +521,13 @@ type SAMLAuthProvider struct {
 	Type                                     string `json:"type"`
 }
 
+// SMTPReceiverConfig description: Optional. Runs a built-in SMTP (or LMTP) server that receives emails (such as code discussion reply emails) forwarded to it by your mail server. Use this instead of `email.imap` if your mail setup can forward mail for the reply address to an SMTP endpoint but can't provide an IMAP mailbox.
+type SMTPReceiverConfig struct {
+	Address       string `json:"address"`
+	ListenAddress string `json:"listenAddress"`
+	Protocol      string `json:"protocol,omitempty"`
+}
+
 // SMTPServerConfig description: The SMTP server used to send transactional emails (such as email verifications, reset-password emails, and notifications).
 type SMTPServerConfig struct {
 	Authentication string `json:"authentication"`
@@ -497,8 +574,10 @@ type Settings struct {
 
 // SiteConfiguration description: Configuration for a Sourcegraph site.
 type SiteConfiguration struct {
+	AuditLog                          *AuditLog                   `json:"auditLog,omitempty"`
 	AuthAccessTokens                  *AuthAccessTokens           `json:"auth.accessTokens,omitempty"`
 	Branding                          *Branding                   `json:"branding,omitempty"`
+	CodeHostRequestBudgets            *CodeHostRequestBudgets     `json:"codeHostRequestBudgets,omitempty"`
 	CorsOrigin                        string                      `json:"corsOrigin,omitempty"`
 	DisableAutoGitUpdates             bool                        `json:"disableAutoGitUpdates,omitempty"`
 	DisableBuiltInSearches            bool                        `json:"disableBuiltInSearches,omitempty"`
@@ -508,6 +587,7 @@ type SiteConfiguration struct {
 	EmailAddress                      string                      `json:"email.address,omitempty"`
 	EmailImap                         *IMAPServerConfig           `json:"email.imap,omitempty"`
 	EmailSmtp                         *SMTPServerConfig           `json:"email.smtp,omitempty"`
+	EmailSmtpReceiver                 *SMTPReceiverConfig         `json:"email.smtpReceiver,omitempty"`
 	ExperimentalFeatures              *ExperimentalFeatures       `json:"experimentalFeatures,omitempty"`
 	Extensions                        *Extensions                 `json:"extensions,omitempty"`
 	GitCloneURLToRepositoryName       []*CloneURLToRepositoryName `json:"git.cloneURLToRepositoryName,omitempty"`
@@ -516,7 +596,9 @@ type SiteConfiguration struct {
 	GithubClientSecret                string                      `json:"githubClientSecret,omitempty"`
 	MaxReposToSearch                  int                         `json:"maxReposToSearch,omitempty"`
 	ParentSourcegraph                 *ParentSourcegraph          `json:"parentSourcegraph,omitempty"`
+	PermissionsBackgroundSync         *PermissionsBackgroundSync  `json:"permissions.backgroundSync,omitempty"`
 	RepoListUpdateInterval            int                         `json:"repoListUpdateInterval,omitempty"`
+	RepositoriesReadOnly              []string                    `json:"repositories.readOnly,omitempty"`
 	SearchIndexEnabled                *bool                       `json:"search.index.enabled,omitempty"`
 	SearchLargeFiles                  []string                    `json:"search.largeFiles,omitempty"`
 }
@@ -121,6 +121,60 @@ const SiteSchemaJSON = `{
       "default": 1,
       "group": "External services"
     },
+    "codeHostRequestBudgets": {
+      "description": "Budgets (in requests per hour) for the requests Sourcegraph makes to each code host connection, by class of consumer. Budgets are shared across all Sourcegraph processes and apply separately to each code host and credential. A consumer whose budget is unset or 0 is not throttled.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "sync": {
+          "description": "Budget for background repository syncing.",
+          "type": "integer",
+          "minimum": 0
+        },
+        "permissions": {
+          "description": "Budget for fetching repository permissions.",
+          "type": "integer",
+          "minimum": 0
+        },
+        "userFacing": {
+          "description": "Budget for requests made on behalf of users (e.g. repository lookups and API calls).",
+          "type": "integer",
+          "minimum": 0
+        }
+      },
+      "group": "External services",
+      "examples": [{ "sync": 3000, "permissions": 1000, "userFacing": 500 }]
+    },
+    "permissions.backgroundSync": {
+      "description": "Syncs repository permissions from code hosts with authorization configured in the background, and enforces them with a database join instead of querying the code host when a user accesses repositories. A user's permissions are synced when they sign in and then periodically. Repositories that were added after a user's permissions were last synced are not visible to them until the next sync.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "enabled": {
+          "description": "Whether repository permissions are synced in the background.",
+          "type": "boolean",
+          "default": false
+        },
+        "userInterval": {
+          "description": "Interval (in minutes) between syncs of each user's repository permissions.",
+          "type": "integer",
+          "minimum": 1,
+          "default": 60
+        }
+      },
+      "group": "Security",
+      "examples": [{ "enabled": true, "userInterval": 60 }]
+    },
+    "repositories.readOnly": {
+      "description": "A list of regular expressions matching the names of protected repositories. Sourcegraph never creates commits or branches in a protected repository, even for users (including site admins) who have write access to it on the code host.",
+      "type": "array",
+      "items": {
+        "type": "string",
+        "format": "regex"
+      },
+      "group": "Security",
+      "examples": [["^github\\.com/myorg/production$", "^gitlab\\.example\\.com/infra/"]]
+    },
     "maxReposToSearch": {
       "description": "The maximum number of repositories to search across. The user is prompted to narrow their query if exceeded. Any value less than or equal to zero means unlimited.",
       "type": "integer",
@@ -162,6 +216,45 @@ const SiteSchemaJSON = `{
       ],
       "group": "Security"
     },
+    "auditLog": {
+      "description": "Settings for the audit log, which records security-relevant actions (such as site configuration changes, site admin promotions and access token creation). Entries are always stored in the database and can be viewed by site admins. They can also be written to a file or to syslog.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "file": {
+          "description": "The path of a file to which each audit log entry is appended as a line of JSON.",
+          "type": "string"
+        },
+        "syslog": {
+          "description": "Sends each audit log entry as JSON to a syslog server.",
+          "type": "object",
+          "additionalProperties": false,
+          "required": ["address"],
+          "properties": {
+            "network": {
+              "description": "The network of the syslog server. If empty, the local syslog server is used and address is ignored.",
+              "type": "string",
+              "enum": ["", "udp", "tcp"],
+              "default": ""
+            },
+            "address": {
+              "description": "The address (host:port) of the syslog server.",
+              "type": "string"
+            },
+            "tag": {
+              "description": "The tag of the syslog messages.",
+              "type": "string",
+              "default": "sourcegraph-audit"
+            }
+          }
+        }
+      },
+      "examples": [
+        { "file": "/var/log/sourcegraph/audit.log" },
+        { "syslog": { "network": "udp", "address": "syslog.example.com:514" } }
+      ],
+      "group": "Security"
+    },
     "branding": {
       "description": "Customize Sourcegraph homepage logo and search icon.\n\nOnly available in Sourcegraph Enterprise.",
       "type": "object",
@@ -280,6 +373,39 @@ const SiteSchemaJSON = `{
       "group": "Email",
       "hide": true
     },
+    "email.smtpReceiver": {
+      "title": "SMTPReceiverConfig",
+      "description": "Optional. Runs a built-in SMTP (or LMTP) server that receives emails (such as code discussion reply emails) forwarded to it by your mail server. Use this instead of ` + "`" + `email.imap` + "`" + ` if your mail setup can forward mail for the reply address to an SMTP endpoint but can't provide an IMAP mailbox.",
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["listenAddress", "address"],
+      "properties": {
+        "listenAddress": {
+          "description": "The TCP address (host:port) to listen on for incoming mail.",
+          "type": "string"
+        },
+        "address": {
+          "description": "The address that email replies are sent to. A secret reply token is added to it as a sub-address (e.g., \"notifications+TOKEN@example.com\"), and only mail for addresses in its domain is accepted.",
+          "type": "string",
+          "format": "email"
+        },
+        "protocol": {
+          "description": "The protocol to speak: \"smtp\" (the default) or \"lmtp\" (for delivery from a local mail server such as Postfix).",
+          "type": "string",
+          "enum": ["smtp", "lmtp"],
+          "default": "smtp"
+        }
+      },
+      "default": null,
+      "examples": [
+        {
+          "listenAddress": ":2525",
+          "address": "notifications@sourcegraph.example.com"
+        }
+      ],
+      "group": "Email",
+      "hide": true
+    },
     "email.address": {
       "description": "The \"from\" address for emails sent by this server.",
       "type": "string",
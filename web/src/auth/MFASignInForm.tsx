@@ -0,0 +1,168 @@
+import { LoadingSpinner } from '@sourcegraph/react-loading-spinner'
+import * as H from 'history'
+import { upperFirst } from 'lodash'
+import * as React from 'react'
+import { Form } from '../components/Form'
+import { getReturnTo } from './SignInSignUpCommon'
+
+interface Props {
+    location: H.Location
+
+    /**
+     * Whether the user must supply a code ("verify") or enroll in multi-factor authentication ("enroll") to
+     * complete signing in.
+     */
+    mode: 'verify' | 'enroll'
+}
+
+interface State {
+    code: string
+    errorDescription: string
+    loading: boolean
+
+    /** The TOTP secret and otpauth:// URL to add to the authenticator app (only when enrolling). */
+    enrollment?: { secret: string; url: string }
+
+    /** The user's recovery codes (only after enrolling). */
+    recoveryCodes?: string[]
+}
+
+/**
+ * The form for the second step of signing in with a username and password, when multi-factor authentication
+ * is enabled for (or required of) the user.
+ */
+export class MFASignInForm extends React.Component<Props, State> {
+    public state: State = {
+        code: '',
+        errorDescription: '',
+        loading: false,
+    }
+
+    public componentDidMount(): void {
+        if (this.props.mode === 'enroll') {
+            this.setState({ loading: true })
+            this.post('/-/sign-in/mfa-enroll', '')
+                .then(resp => resp.json())
+                .then(enrollment => this.setState({ loading: false, enrollment }))
+                .catch(this.onError)
+        }
+    }
+
+    public render(): JSX.Element | null {
+        if (this.state.recoveryCodes) {
+            return (
+                <div className="signin-signup-form signin-form">
+                    <p>
+                        Save these recovery codes in a safe place. Each code may be used once to sign in if you lose
+                        access to your authenticator app.
+                    </p>
+                    <pre className="form-group">{this.state.recoveryCodes.join('\n')}</pre>
+                    <button className="btn btn-primary btn-block" type="button" onClick={this.onContinue}>
+                        Continue
+                    </button>
+                </div>
+            )
+        }
+
+        return (
+            <Form className="signin-signup-form signin-form" onSubmit={this.handleSubmit}>
+                {this.props.mode === 'enroll' ? (
+                    <p className="text-muted">
+                        Multi-factor authentication is required. Add this secret to your authenticator app, then
+                        enter the 6-digit code it shows.
+                    </p>
+                ) : (
+                    <p className="text-muted">Enter the 6-digit code from your authenticator app or a recovery code.</p>
+                )}
+                {this.state.errorDescription !== '' && (
+                    <div className="alert alert-danger my-2">Error: {upperFirst(this.state.errorDescription)}</div>
+                )}
+                {this.state.enrollment && (
+                    <div className="form-group">
+                        <code>{this.state.enrollment.secret}</code>
+                        <small className="form-text text-muted">
+                            On a mobile device, <a href={this.state.enrollment.url}>open this link</a> to add it to
+                            your authenticator app.
+                        </small>
+                    </div>
+                )}
+                <div className="form-group">
+                    <input
+                        className="form-control signin-signup-form__input"
+                        type="text"
+                        placeholder="Code"
+                        onChange={this.onCodeFieldChange}
+                        required={true}
+                        value={this.state.code}
+                        disabled={this.state.loading || (this.props.mode === 'enroll' && !this.state.enrollment)}
+                        autoCapitalize="off"
+                        autoComplete="one-time-code"
+                        autoFocus={true}
+                    />
+                </div>
+                <div className="form-group">
+                    <button className="btn btn-primary btn-block" type="submit" disabled={this.state.loading}>
+                        Verify
+                    </button>
+                </div>
+                {this.state.loading && (
+                    <div className="signin-signup-form__loader">
+                        <LoadingSpinner className="icon-inline" />
+                    </div>
+                )}
+            </Form>
+        )
+    }
+
+    private onCodeFieldChange = (e: React.ChangeEvent<HTMLInputElement>) => {
+        this.setState({ code: e.target.value })
+    }
+
+    private onContinue = () => {
+        window.location.replace(getReturnTo(this.props.location))
+    }
+
+    private onError = (err: any) => {
+        console.error('auth error: ', err)
+        this.setState({ loading: false, errorDescription: (err && err.message) || 'Unknown Error' })
+    }
+
+    private handleSubmit = (event: React.FormEvent<HTMLFormElement>) => {
+        event.preventDefault()
+        if (this.state.loading) {
+            return
+        }
+
+        this.setState({ loading: true })
+        if (this.props.mode === 'enroll') {
+            this.post('/-/sign-in/mfa-enroll', this.state.code)
+                .then(resp => resp.json())
+                .then(({ recoveryCodes }) => this.setState({ loading: false, recoveryCodes }))
+                .catch(this.onError)
+        } else {
+            this.post('/-/sign-in/mfa', this.state.code)
+                .then(this.onContinue)
+                .catch(this.onError)
+        }
+    }
+
+    private post(url: string, code: string): Promise<Response> {
+        return fetch(url, {
+            credentials: 'same-origin',
+            method: 'POST',
+            headers: {
+                ...window.context.xhrHeaders,
+                Accept: 'application/json',
+                'Content-Type': 'application/json',
+            },
+            body: JSON.stringify({ code }),
+        }).then(resp => {
+            if (resp.status === 200) {
+                return resp
+            }
+            return resp.text().then(text => {
+                throw new Error(resp.status === 401 || resp.status === 429 ? text.trim() : 'Unknown Error')
+            })
+        })
+    }
+}
@@ -8,6 +8,7 @@ import { asError } from '../../../shared/src/util/errors'
 import { Form } from '../components/Form'
 import { eventLogger } from '../tracking/eventLogger'
 import { enterpriseTrial, signupTerms } from '../util/features'
+import { MFASignInForm } from './MFASignInForm'
 import { EmailInput, PasswordInput, UsernameInput } from './SignInSignUpCommon'
 
 export interface SignUpArgs {
@@ -21,8 +22,11 @@ interface SignUpFormProps {
     location: H.Location
     history: H.History
 
-    /** Called to perform the signup on the server. */
-    doSignUp: (args: SignUpArgs) => Promise<void>
+    /**
+     * Called to perform the signup on the server. It resolves to the multi-factor authentication step that the
+     * new user must complete before being signed in, if any.
+     */
+    doSignUp: (args: SignUpArgs) => Promise<'verify' | 'enroll' | void>
 
     buttonLabel?: string
 }
@@ -34,6 +38,9 @@ interface SignUpFormState {
     error?: Error
     loading: boolean
     requestedTrial: boolean
+
+    /** Set if the new user must complete multi-factor authentication to be signed in. */
+    mfa?: 'verify' | 'enroll'
 }
 
 export class SignUpForm extends React.Component<SignUpFormProps, SignUpFormState> {
@@ -51,6 +58,9 @@ export class SignUpForm extends React.Component<SignUpFormProps, SignUpFormState
     }
 
     public render(): JSX.Element | null {
+        if (this.state.mfa) {
+            return <MFASignInForm location={this.props.location} mode={this.state.mfa} />
+        }
         return (
             <Form className="signin-signup-form signup-form" onSubmit={this.handleSubmit}>
                 {this.state.error && (
@@ -160,6 +170,11 @@ export class SignUpForm extends React.Component<SignUpFormProps, SignUpFormState
                         password: this.state.password,
                         requestedTrial: this.state.requestedTrial,
                     })
+                    .then(mfa => {
+                        if (mfa) {
+                            this.setState({ loading: false, mfa })
+                        }
+                    })
                     .catch(error => this.setState({ error: asError(error), loading: false }))
             ).subscribe()
         )
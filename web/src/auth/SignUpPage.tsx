@@ -51,7 +51,7 @@ export class SignUpPage extends React.Component<SignUpPageProps> {
         )
     }
 
-    private doSignUp = (args: SignUpArgs): Promise<void> =>
+    private doSignUp = (args: SignUpArgs): Promise<'verify' | 'enroll' | void> =>
         fetch('/-/sign-up', {
             credentials: 'same-origin',
             method: 'POST',
@@ -65,7 +65,13 @@ export class SignUpPage extends React.Component<SignUpPageProps> {
             if (resp.status !== 200) {
                 return resp.text().then(text => Promise.reject(new Error(text)))
             }
-            window.location.replace(getReturnTo(this.props.location))
-            return Promise.resolve()
+            return resp.text().then(text => {
+                // The response body is empty unless multi-factor authentication is needed.
+                const { mfa } = text ? JSON.parse(text) : { mfa: undefined }
+                if (!mfa) {
+                    window.location.replace(getReturnTo(this.props.location))
+                }
+                return mfa
+            })
         })
 }
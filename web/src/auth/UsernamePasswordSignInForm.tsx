@@ -5,6 +5,7 @@ import * as React from 'react'
 import { Link } from 'react-router-dom'
 import { Form } from '../components/Form'
 import { eventLogger } from '../tracking/eventLogger'
+import { MFASignInForm } from './MFASignInForm'
 import { getReturnTo, PasswordInput } from './SignInSignUpCommon'
 
 interface Props {
@@ -17,6 +18,9 @@ interface State {
     password: string
     errorDescription: string
     loading: boolean
+
+    /** Set if the user must complete multi-factor authentication after entering a correct password. */
+    mfa?: 'verify' | 'enroll'
 }
 
 /**
@@ -34,6 +38,9 @@ export class UsernamePasswordSignInForm extends React.Component<Props, State> {
     }
 
     public render(): JSX.Element | null {
+        if (this.state.mfa) {
+            return <MFASignInForm location={this.props.location} mode={this.state.mfa} />
+        }
         return (
             <Form className="signin-signup-form signin-form" onSubmit={this.handleSubmit}>
                 {window.context.allowSignup ? (
@@ -120,8 +127,16 @@ export class UsernamePasswordSignInForm extends React.Component<Props, State> {
         })
             .then(resp => {
                 if (resp.status === 200) {
-                    const returnTo = getReturnTo(this.props.location)
-                    window.location.replace(returnTo)
+                    return resp.text().then(text => {
+                        // The response body is empty unless multi-factor authentication is needed.
+                        const { mfa } = text ? JSON.parse(text) : { mfa: undefined }
+                        if (mfa) {
+                            this.setState({ loading: false, mfa })
+                            return
+                        }
+                        const returnTo = getReturnTo(this.props.location)
+                        window.location.replace(returnTo)
+                    })
                 } else if (resp.status === 401) {
                     throw new Error('User or password was incorrect')
                 } else {
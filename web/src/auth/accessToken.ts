@@ -4,4 +4,29 @@
 export enum AccessTokenScopes {
     UserAll = 'user:all',
     SiteAdminSudo = 'site-admin:sudo',
+    SiteAdminSCIM = 'site-admin:scim',
+    SearchRead = 'search:read',
+    RepoRead = 'repo:read',
+    RepoWrite = 'repo:write',
+    SettingsRead = 'settings:read',
+    SettingsWrite = 'settings:write',
+    UserRead = 'user:read',
+    UserWrite = 'user:write',
 }
+
+/**
+ * The access token scopes that any user may select, with their descriptions.
+ */
+export const USER_ACCESS_TOKEN_SCOPES: { scope: AccessTokenScopes; description: string }[] = [
+    { scope: AccessTokenScopes.UserAll, description: 'Full control of all resources accessible to the user account' },
+    { scope: AccessTokenScopes.SearchRead, description: 'Ability to search' },
+    { scope: AccessTokenScopes.RepoRead, description: 'Read access to repositories and their contents' },
+    { scope: AccessTokenScopes.RepoWrite, description: 'Ability to update repositories and discussions' },
+    { scope: AccessTokenScopes.SettingsRead, description: 'Read access to settings and saved searches' },
+    { scope: AccessTokenScopes.SettingsWrite, description: 'Ability to change settings and saved searches' },
+    { scope: AccessTokenScopes.UserRead, description: 'Read access to the user account, its emails and organizations' },
+    {
+        scope: AccessTokenScopes.UserWrite,
+        description: 'Ability to change the user account, its emails and organizations',
+    },
+]
@@ -108,6 +108,8 @@ interface SourcegraphContext {
     authProviders?: {
         displayName: string
         isBuiltin: boolean
+        /** The type of the auth provider (e.g., "builtin", "ldap", "saml"). */
+        serviceType: string
         authenticationURL?: string
     }[]
 
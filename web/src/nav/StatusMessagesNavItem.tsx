@@ -101,6 +101,17 @@ export class StatusMessagesNavItem extends React.PureComponent<Props, State> {
                         linkText="Configure external services"
                     />
                 )
+            case GQL.StatusMessageType.RATE_LIMIT:
+                return (
+                    <StatusMessagesNavItemEntry
+                        key={message.message}
+                        title="Code host requests throttled"
+                        text={message.message}
+                        showLink={this.props.isSiteAdmin}
+                        linkTo="/site-admin/configuration"
+                        linkText="Configure request budgets"
+                    />
+                )
         }
     }
 
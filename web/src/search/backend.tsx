@@ -107,6 +107,9 @@ export function search(
                                     ... on CommitSearchResult {
                                         ${genericSearchResultInterfaceFields}
                                     }
+                                    ... on DiscussionSearchResult {
+                                        ${genericSearchResultInterfaceFields}
+                                    }
                                     ${codemodActive}
                                 }
                                 alert {
@@ -242,6 +245,8 @@ const savedSearchFragment = gql`
         userID
         orgID
         slackWebhookURL
+        schedule
+        timeoutSeconds
     }
 `
 
@@ -294,7 +299,9 @@ export function createSavedSearch(
     notify: boolean,
     notifySlack: boolean,
     userId: GQL.ID | null,
-    orgId: GQL.ID | null
+    orgId: GQL.ID | null,
+    schedule: string | null,
+    timeoutSeconds: number | null
 ): Observable<void> {
     return mutateGraphQL(
         gql`
@@ -305,6 +312,8 @@ export function createSavedSearch(
                 $notifySlack: Boolean!
                 $userID: ID
                 $orgID: ID
+                $schedule: String
+                $timeoutSeconds: Int
             ) {
                 createSavedSearch(
                     description: $description
@@ -313,6 +322,8 @@ export function createSavedSearch(
                     notifySlack: $notifySlack
                     userID: $userID
                     orgID: $orgID
+                    schedule: $schedule
+                    timeoutSeconds: $timeoutSeconds
                 ) {
                     ...SavedSearchFields
                 }
@@ -326,6 +337,8 @@ export function createSavedSearch(
             notifySlack,
             userID: userId,
             orgID: orgId,
+            schedule,
+            timeoutSeconds,
         }
     ).pipe(
         map(dataOrThrowErrors),
@@ -340,7 +353,9 @@ export function updateSavedSearch(
     notify: boolean,
     notifySlack: boolean,
     userId: GQL.ID | null,
-    orgId: GQL.ID | null
+    orgId: GQL.ID | null,
+    schedule: string | null,
+    timeoutSeconds: number | null
 ): Observable<void> {
     return mutateGraphQL(
         gql`
@@ -352,6 +367,8 @@ export function updateSavedSearch(
                 $notifySlack: Boolean!
                 $userID: ID
                 $orgID: ID
+                $schedule: String
+                $timeoutSeconds: Int
             ) {
                 updateSavedSearch(
                     id: $id
@@ -361,6 +378,8 @@ export function updateSavedSearch(
                     notifySlack: $notifySlack
                     userID: $userID
                     orgID: $orgID
+                    schedule: $schedule
+                    timeoutSeconds: $timeoutSeconds
                 ) {
                     ...SavedSearchFields
                 }
@@ -375,6 +394,8 @@ export function updateSavedSearch(
             notifySlack,
             userID: userId,
             orgID: orgId,
+            schedule,
+            timeoutSeconds,
         }
     ).pipe(
         map(dataOrThrowErrors),
@@ -398,6 +419,196 @@ export function deleteSavedSearch(id: GQL.ID): Observable<void> {
     )
 }
 
+export function fetchSavedSearchWebhooks(savedSearch: GQL.ID): Observable<GQL.ISavedSearchWebhook[]> {
+    return queryGraphQL(
+        gql`
+            query SavedSearchWebhooks($savedSearch: ID!) {
+                node(id: $savedSearch) {
+                    ... on SavedSearch {
+                        webhooks {
+                            id
+                            url
+                            createdAt
+                            deliveries(first: 20) {
+                                nodes {
+                                    id
+                                    event
+                                    payload
+                                    state
+                                    attempts
+                                    responseStatusCode
+                                    error
+                                    createdAt
+                                    lastAttemptAt
+                                    nextAttemptAt
+                                }
+                                totalCount
+                            }
+                        }
+                    }
+                }
+            }
+        `,
+        { savedSearch }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(data => (data.node as GQL.ISavedSearch).webhooks)
+    )
+}
+
+export function addSavedSearchWebhook(savedSearch: GQL.ID, url: string, secret: string): Observable<void> {
+    return mutateGraphQL(
+        gql`
+            mutation AddSavedSearchWebhook($savedSearch: ID!, $url: String!, $secret: String!) {
+                addSavedSearchWebhook(savedSearch: $savedSearch, url: $url, secret: $secret) {
+                    id
+                }
+            }
+        `,
+        { savedSearch, url, secret }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(() => undefined)
+    )
+}
+
+export function deleteSavedSearchWebhook(id: GQL.ID): Observable<void> {
+    return mutateGraphQL(
+        gql`
+            mutation DeleteSavedSearchWebhook($id: ID!) {
+                deleteSavedSearchWebhook(id: $id) {
+                    alwaysNil
+                }
+            }
+        `,
+        { id }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(() => undefined)
+    )
+}
+
+export interface SavedSearchSubscriptions {
+    viewerSubscriptions: GQL.SavedSearchSubscriptionChannel[]
+    subscriptions: GQL.ISavedSearchSubscription[]
+}
+
+export function fetchSavedSearchSubscriptions(savedSearch: GQL.ID): Observable<SavedSearchSubscriptions> {
+    return queryGraphQL(
+        gql`
+            query SavedSearchSubscriptions($savedSearch: ID!) {
+                node(id: $savedSearch) {
+                    ... on SavedSearch {
+                        viewerSubscriptions
+                        subscriptions {
+                            id
+                            user {
+                                id
+                                username
+                                url
+                            }
+                            channel
+                            createdAt
+                        }
+                    }
+                }
+            }
+        `,
+        { savedSearch }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(data => data.node as GQL.ISavedSearch)
+    )
+}
+
+export function subscribeToSavedSearch(
+    savedSearch: GQL.ID,
+    channel: GQL.SavedSearchSubscriptionChannel,
+    slackWebhookURL?: string
+): Observable<void> {
+    return mutateGraphQL(
+        gql`
+            mutation SubscribeToSavedSearch(
+                $savedSearch: ID!
+                $channel: SavedSearchSubscriptionChannel!
+                $slackWebhookURL: String
+            ) {
+                subscribeToSavedSearch(
+                    savedSearch: $savedSearch
+                    channel: $channel
+                    slackWebhookURL: $slackWebhookURL
+                ) {
+                    id
+                }
+            }
+        `,
+        { savedSearch, channel, slackWebhookURL }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(() => undefined)
+    )
+}
+
+export function unsubscribeFromSavedSearch(
+    savedSearch: GQL.ID,
+    channel: GQL.SavedSearchSubscriptionChannel,
+    user?: GQL.ID
+): Observable<void> {
+    return mutateGraphQL(
+        gql`
+            mutation UnsubscribeFromSavedSearch(
+                $savedSearch: ID!
+                $channel: SavedSearchSubscriptionChannel!
+                $user: ID
+            ) {
+                unsubscribeFromSavedSearch(savedSearch: $savedSearch, channel: $channel, user: $user) {
+                    alwaysNil
+                }
+            }
+        `,
+        { savedSearch, channel, user }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(() => undefined)
+    )
+}
+
+export interface SavedSearchRunHistory {
+    nextRunAt: string | null
+    consecutiveFailures: number
+    runs: GQL.ISavedSearchRunConnection
+}
+
+export function fetchSavedSearchRuns(savedSearch: GQL.ID): Observable<SavedSearchRunHistory> {
+    return queryGraphQL(
+        gql`
+            query SavedSearchRuns($savedSearch: ID!) {
+                node(id: $savedSearch) {
+                    ... on SavedSearch {
+                        nextRunAt
+                        consecutiveFailures
+                        runs(first: 20) {
+                            nodes {
+                                id
+                                startedAt
+                                durationMilliseconds
+                                resultCount
+                                newResultCount
+                                error
+                            }
+                            totalCount
+                        }
+                    }
+                }
+            }
+        `,
+        { savedSearch }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(data => data.node as GQL.ISavedSearch)
+    )
+}
+
 export const highlightCode = memoizeObservable(
     (ctx: {
         code: string
@@ -46,7 +46,9 @@ export class SavedSearchCreateForm extends React.Component<Props, State> {
                                 fields.notify,
                                 fields.notifySlack,
                                 fields.userID,
-                                fields.orgID
+                                fields.orgID,
+                                fields.schedule,
+                                fields.timeoutSeconds
                             ).pipe(
                                 map(() => true),
                                 catchError(error => [error])
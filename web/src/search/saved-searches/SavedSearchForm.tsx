@@ -14,6 +14,8 @@ export interface SavedQueryFields {
     userID: GQL.ID | null
     orgID: GQL.ID | null
     slackWebhookURL: string | null
+    schedule: string | null
+    timeoutSeconds: number | null
 }
 
 interface Props extends RouteComponentProps<{}> {
@@ -45,6 +47,8 @@ export class SavedSearchForm extends React.Component<Props, State> {
             userID = null,
             orgID = null,
             slackWebhookURL = '',
+            schedule = null,
+            timeoutSeconds = null,
         } = props.defaultValues || {}
 
         this.state = {
@@ -56,6 +60,8 @@ export class SavedSearchForm extends React.Component<Props, State> {
                 userID,
                 orgID,
                 slackWebhookURL,
+                schedule,
+                timeoutSeconds,
             },
         }
     }
@@ -77,6 +83,16 @@ export class SavedSearchForm extends React.Component<Props, State> {
         }
     }
 
+    private handleScheduleChange = (event: React.ChangeEvent<HTMLInputElement>) => {
+        const schedule = event.currentTarget.value
+        this.setState(state => ({ values: { ...state.values, schedule: schedule || null } }))
+    }
+
+    private handleTimeoutSecondsChange = (event: React.ChangeEvent<HTMLInputElement>) => {
+        const value = event.currentTarget.value
+        this.setState(state => ({ values: { ...state.values, timeoutSeconds: value ? parseInt(value, 10) : null } }))
+    }
+
     private handleSubmit = (event: React.FormEvent<HTMLFormElement>) => {
         event.preventDefault()
         this.props.onSubmit(this.state.values)
@@ -84,7 +100,7 @@ export class SavedSearchForm extends React.Component<Props, State> {
 
     public render(): JSX.Element | null {
         const {
-            values: { query, description, notify, notifySlack, slackWebhookURL },
+            values: { query, description, notify, notifySlack, slackWebhookURL, schedule, timeoutSeconds },
         } = this.state
 
         return (
@@ -151,6 +167,35 @@ export class SavedSearchForm extends React.Component<Props, State> {
                             </label>
                         </div>
                     )}
+                    <div className="saved-search-form__input">
+                        <label className="saved-search-form__label">Schedule:</label>
+                        <input
+                            type="text"
+                            name="schedule"
+                            className="form-control"
+                            placeholder="Automatic"
+                            value={schedule || ''}
+                            onChange={this.handleScheduleChange}
+                        />
+                        <small className="form-text text-muted">
+                            How often to run this saved search to check for new results: an interval such as{' '}
+                            <code>@every 1h</code>, or a cron expression (in UTC) such as <code>0 9 * * 1-5</code>.
+                            If empty, the saved search runs more often the faster it is.
+                        </small>
+                    </div>
+                    <div className="saved-search-form__input">
+                        <label className="saved-search-form__label">Timeout (seconds):</label>
+                        <input
+                            type="number"
+                            name="timeoutSeconds"
+                            className="form-control"
+                            placeholder="Default"
+                            min={1}
+                            max={1800}
+                            value={timeoutSeconds === null ? '' : timeoutSeconds}
+                            onChange={this.handleTimeoutSecondsChange}
+                        />
+                    </div>
                     {this.isUnsupportedNotifyQuery(this.state.values) && (
                         <div className="alert alert-warning mb-3">
                             <strong>Warning:</strong> non-commit searches do not currently support notifications.
@@ -0,0 +1,107 @@
+import { LoadingSpinner } from '@sourcegraph/react-loading-spinner'
+import * as React from 'react'
+import { Subscription } from 'rxjs'
+import { catchError, map } from 'rxjs/operators'
+import * as GQL from '../../../../shared/src/graphql/schema'
+import { asError, ErrorLike, isErrorLike } from '../../../../shared/src/util/errors'
+import { pluralize } from '../../../../shared/src/util/strings'
+import { Timestamp } from '../../components/time/Timestamp'
+import { fetchSavedSearchRuns, SavedSearchRunHistory } from '../backend'
+
+const LOADING: 'loading' = 'loading'
+
+interface Props {
+    savedSearch: GQL.ID
+}
+
+interface State {
+    runsOrError: typeof LOADING | SavedSearchRunHistory | ErrorLike
+}
+
+/**
+ * Displays the most recent runs of a saved search by the query runner.
+ */
+export class SavedSearchRuns extends React.PureComponent<Props, State> {
+    public state: State = { runsOrError: LOADING }
+
+    private subscriptions = new Subscription()
+
+    public componentDidMount(): void {
+        this.subscriptions.add(
+            fetchSavedSearchRuns(this.props.savedSearch)
+                .pipe(
+                    catchError(err => [asError(err)]),
+                    map(result => ({ runsOrError: result }))
+                )
+                .subscribe(stateUpdate => this.setState(stateUpdate))
+        )
+    }
+
+    public componentWillUnmount(): void {
+        this.subscriptions.unsubscribe()
+    }
+
+    public render(): JSX.Element | null {
+        const { runsOrError } = this.state
+        return (
+            <div className="saved-search-runs mt-4">
+                <h3>Recent runs</h3>
+                <p>
+                    Saved searches that notify anyone of new results run periodically. Each run records the number of
+                    results, how many of them are new, and any error.
+                </p>
+                {runsOrError !== LOADING && !isErrorLike(runsOrError) && (
+                    <p>
+                        {runsOrError.nextRunAt ? (
+                            <>
+                                Next run <Timestamp date={runsOrError.nextRunAt} />.
+                            </>
+                        ) : (
+                            'This saved search is due to run.'
+                        )}
+                        {runsOrError.consecutiveFailures > 0 && (
+                            <span className="text-danger">
+                                {' '}
+                                The last {runsOrError.consecutiveFailures}{' '}
+                                {pluralize('run', runsOrError.consecutiveFailures)} failed, so this saved search runs
+                                less often until a run succeeds.
+                            </span>
+                        )}
+                    </p>
+                )}
+                {runsOrError === LOADING ? (
+                    <LoadingSpinner className="icon-inline" />
+                ) : isErrorLike(runsOrError) ? (
+                    <div className="alert alert-danger">{runsOrError.message}</div>
+                ) : runsOrError.runs.nodes.length > 0 ? (
+                    <table className="table table-sm">
+                        <thead>
+                            <tr>
+                                <th>Started</th>
+                                <th>Duration</th>
+                                <th>Results</th>
+                                <th>New results</th>
+                                <th>Error</th>
+                            </tr>
+                        </thead>
+                        <tbody>
+                            {runsOrError.runs.nodes.map(run => (
+                                <tr key={run.id}>
+                                    <td>
+                                        <Timestamp date={run.startedAt} />
+                                    </td>
+                                    <td>{(run.durationMilliseconds / 1000).toFixed(1)}s</td>
+                                    <td>{run.resultCount}</td>
+                                    <td>{run.newResultCount}</td>
+                                    <td className="text-danger">{run.error}</td>
+                                </tr>
+                            ))}
+                        </tbody>
+                    </table>
+                ) : (
+                    <p className="text-muted">This saved search has not run yet.</p>
+                )}
+            </div>
+        )
+    }
+}
@@ -0,0 +1,200 @@
+import { LoadingSpinner } from '@sourcegraph/react-loading-spinner'
+import * as React from 'react'
+import { Link } from 'react-router-dom'
+import { concat, Subject, Subscription } from 'rxjs'
+import { catchError, map, mapTo, startWith, switchMap, tap } from 'rxjs/operators'
+import * as GQL from '../../../../shared/src/graphql/schema'
+import { asError, ErrorLike, isErrorLike } from '../../../../shared/src/util/errors'
+import {
+    fetchSavedSearchSubscriptions,
+    SavedSearchSubscriptions as SavedSearchSubscriptionsData,
+    subscribeToSavedSearch,
+    unsubscribeFromSavedSearch,
+} from '../backend'
+
+const LOADING: 'loading' = 'loading'
+
+const channelLabels: { [C in GQL.SavedSearchSubscriptionChannel]: string } = {
+    EMAIL: 'email',
+    SLACK: 'Slack',
+}
+
+interface Props {
+    savedSearch: GQL.ID
+}
+
+interface State {
+    subscriptionsOrError: typeof LOADING | SavedSearchSubscriptionsData | ErrorLike
+    slackWebhookURL: string
+    updateOrError: null | typeof LOADING | ErrorLike
+}
+
+type Update =
+    | { type: 'subscribe'; channel: GQL.SavedSearchSubscriptionChannel; slackWebhookURL?: string }
+    | { type: 'unsubscribe'; channel: GQL.SavedSearchSubscriptionChannel }
+
+/**
+ * Lets the current user subscribe to notifications about a saved search's new results, and lists the users
+ * who are subscribed.
+ */
+export class SavedSearchSubscriptions extends React.PureComponent<Props, State> {
+    public state: State = { subscriptionsOrError: LOADING, slackWebhookURL: '', updateOrError: null }
+
+    private refreshes = new Subject<void>()
+    private updates = new Subject<Update>()
+    private subscriptions = new Subscription()
+
+    public componentDidMount(): void {
+        this.subscriptions.add(
+            this.refreshes
+                .pipe(
+                    startWith(void 0),
+                    switchMap(() =>
+                        fetchSavedSearchSubscriptions(this.props.savedSearch).pipe(catchError(err => [asError(err)]))
+                    ),
+                    map(result => ({ subscriptionsOrError: result }))
+                )
+                .subscribe(stateUpdate => this.setState(stateUpdate))
+        )
+
+        this.subscriptions.add(
+            this.updates
+                .pipe(
+                    switchMap(update =>
+                        concat(
+                            [{ updateOrError: LOADING }],
+                            (update.type === 'subscribe'
+                                ? subscribeToSavedSearch(this.props.savedSearch, update.channel, update.slackWebhookURL)
+                                : unsubscribeFromSavedSearch(this.props.savedSearch, update.channel)
+                            ).pipe(
+                                tap(() => this.refreshes.next()),
+                                mapTo({ updateOrError: null, slackWebhookURL: '' }),
+                                catchError(err => [{ updateOrError: asError(err) }])
+                            )
+                        )
+                    )
+                )
+                .subscribe(stateUpdate => this.setState(stateUpdate as State))
+        )
+    }
+
+    public componentWillUnmount(): void {
+        this.subscriptions.unsubscribe()
+    }
+
+    public render(): JSX.Element | null {
+        const { subscriptionsOrError } = this.state
+        return (
+            <div className="saved-search-subscriptions mt-4">
+                <h3>Subscriptions</h3>
+                <p>
+                    Anyone who can view this saved search can subscribe to be notified of its new results, in addition
+                    to the notifications that are sent to its owner.
+                </p>
+                {subscriptionsOrError === LOADING ? (
+                    <LoadingSpinner className="icon-inline" />
+                ) : isErrorLike(subscriptionsOrError) ? (
+                    <div className="alert alert-danger">{subscriptionsOrError.message}</div>
+                ) : (
+                    <>
+                        {this.renderViewerSubscriptions(subscriptionsOrError.viewerSubscriptions)}
+                        {isErrorLike(this.state.updateOrError) && (
+                            <div className="alert alert-danger">{this.state.updateOrError.message}</div>
+                        )}
+                        <h4>Subscribers</h4>
+                        {subscriptionsOrError.subscriptions.length > 0 ? (
+                            <ul className="list-group">
+                                {subscriptionsOrError.subscriptions.map(subscription => (
+                                    <li key={subscription.id} className="list-group-item">
+                                        <Link to={subscription.user.url}>{subscription.user.username}</Link>{' '}
+                                        <span className="badge badge-secondary">
+                                            {channelLabels[subscription.channel]}
+                                        </span>
+                                    </li>
+                                ))}
+                            </ul>
+                        ) : (
+                            <p className="text-muted">No users are subscribed.</p>
+                        )}
+                    </>
+                )}
+            </div>
+        )
+    }
+
+    private renderViewerSubscriptions(channels: GQL.SavedSearchSubscriptionChannel[]): JSX.Element {
+        const loading = this.state.updateOrError === LOADING
+        const email = channels.includes(GQL.SavedSearchSubscriptionChannel.EMAIL)
+        const slack = channels.includes(GQL.SavedSearchSubscriptionChannel.SLACK)
+        return (
+            <div className="mb-3">
+                <div className="mb-2">
+                    {email ? (
+                        <button
+                            type="button"
+                            className="btn btn-secondary"
+                            onClick={this.unsubscribeEmail}
+                            disabled={loading}
+                        >
+                            Unsubscribe from email notifications
+                        </button>
+                    ) : (
+                        <button
+                            type="button"
+                            className="btn btn-primary"
+                            onClick={this.subscribeEmail}
+                            disabled={loading}
+                        >
+                            Subscribe to email notifications
+                        </button>
+                    )}
+                </div>
+                {slack ? (
+                    <button
+                        type="button"
+                        className="btn btn-secondary"
+                        onClick={this.unsubscribeSlack}
+                        disabled={loading}
+                    >
+                        Unsubscribe from Slack notifications
+                    </button>
+                ) : (
+                    <form className="form-inline" onSubmit={this.subscribeSlack}>
+                        <input
+                            type="url"
+                            className="form-control mr-2"
+                            placeholder="Slack webhook URL"
+                            required={true}
+                            value={this.state.slackWebhookURL}
+                            onChange={this.onSlackWebhookURLChange}
+                        />
+                        <button type="submit" className="btn btn-primary" disabled={loading}>
+                            Subscribe to Slack notifications
+                        </button>
+                    </form>
+                )}
+            </div>
+        )
+    }
+
+    private onSlackWebhookURLChange = (e: React.ChangeEvent<HTMLInputElement>) =>
+        this.setState({ slackWebhookURL: e.currentTarget.value })
+
+    private subscribeEmail = () =>
+        this.updates.next({ type: 'subscribe', channel: GQL.SavedSearchSubscriptionChannel.EMAIL })
+
+    private unsubscribeEmail = () =>
+        this.updates.next({ type: 'unsubscribe', channel: GQL.SavedSearchSubscriptionChannel.EMAIL })
+
+    private subscribeSlack = (e: React.FormEvent<HTMLFormElement>) => {
+        e.preventDefault()
+        this.updates.next({
+            type: 'subscribe',
+            channel: GQL.SavedSearchSubscriptionChannel.SLACK,
+            slackWebhookURL: this.state.slackWebhookURL,
+        })
+    }
+
+    private unsubscribeSlack = () =>
+        this.updates.next({ type: 'unsubscribe', channel: GQL.SavedSearchSubscriptionChannel.SLACK })
+}
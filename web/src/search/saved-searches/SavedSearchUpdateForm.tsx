@@ -7,6 +7,9 @@ import * as GQL from '../../../../shared/src/graphql/schema'
 import { asError, ErrorLike, isErrorLike } from '../../../../shared/src/util/errors'
 import { fetchSavedSearch, updateSavedSearch } from '../../search/backend'
 import { SavedQueryFields, SavedSearchForm } from '../../search/saved-searches/SavedSearchForm'
+import { SavedSearchRuns } from './SavedSearchRuns'
+import { SavedSearchSubscriptions } from './SavedSearchSubscriptions'
+import { SavedSearchWebhooks } from './SavedSearchWebhooks'
 
 interface Props extends RouteComponentProps<{ id: GQL.ID }> {
     authenticatedUser: GQL.IUser | null
@@ -63,7 +66,9 @@ export class SavedSearchUpdateForm extends React.Component<Props, State> {
                                 input.notify,
                                 input.notifySlack,
                                 input.userID,
-                                input.orgID
+                                input.orgID,
+                                input.schedule,
+                                input.timeoutSeconds
                             ).pipe(
                                 mapTo(null),
                                 mergeMap(() =>
@@ -109,6 +114,8 @@ export class SavedSearchUpdateForm extends React.Component<Props, State> {
                             slackWebhookURL: savedSearch.slackWebhookURL,
                             userID: savedSearch.userID,
                             orgID: savedSearch.orgID,
+                            schedule: savedSearch.schedule,
+                            timeoutSeconds: savedSearch.timeoutSeconds,
                         }}
                         loading={this.state.updatedOrError === LOADING}
                         // tslint:disable-next-line:jsx-no-lambda
@@ -121,6 +128,13 @@ export class SavedSearchUpdateForm extends React.Component<Props, State> {
                 {this.state.updatedOrError === true && (
                     <p className="alert alert-success user-settings-profile-page__alert">Updated!</p>
                 )}
+                {this.props.authenticatedUser && savedSearch && (
+                    <>
+                        <SavedSearchSubscriptions savedSearch={savedSearch.id} />
+                        <SavedSearchWebhooks savedSearch={savedSearch.id} />
+                        <SavedSearchRuns savedSearch={savedSearch.id} />
+                    </>
+                )}
             </div>
         )
     }
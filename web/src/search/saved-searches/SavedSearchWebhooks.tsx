@@ -0,0 +1,252 @@
+import { LoadingSpinner } from '@sourcegraph/react-loading-spinner'
+import * as React from 'react'
+import { concat, Subject, Subscription } from 'rxjs'
+import { catchError, map, mapTo, startWith, switchMap, tap } from 'rxjs/operators'
+import * as GQL from '../../../../shared/src/graphql/schema'
+import { asError, ErrorLike, isErrorLike } from '../../../../shared/src/util/errors'
+import { Timestamp } from '../../components/time/Timestamp'
+import { addSavedSearchWebhook, deleteSavedSearchWebhook, fetchSavedSearchWebhooks } from '../backend'
+
+const LOADING: 'loading' = 'loading'
+
+const deliveryStateClassName: { [S in GQL.SavedSearchWebhookDeliveryState]: string } = {
+    PENDING: 'badge-secondary',
+    SUCCEEDED: 'badge-success',
+    FAILED: 'badge-danger',
+}
+
+const SavedSearchWebhookDeliveryNode: React.FunctionComponent<{ node: GQL.ISavedSearchWebhookDelivery }> = ({
+    node,
+}) => (
+    <li className="list-group-item">
+        <div className="d-flex align-items-center justify-content-between">
+            <div>
+                <span className={`badge ${deliveryStateClassName[node.state]} mr-2`}>{node.state.toLowerCase()}</span>
+                <code>{node.event}</code>
+                {node.responseStatusCode !== null && (
+                    <span className="text-muted ml-2">HTTP {node.responseStatusCode}</span>
+                )}
+            </div>
+            <small className="text-muted">
+                <Timestamp date={node.createdAt} />
+                {node.attempts > 1 && <>, {node.attempts} attempts</>}
+                {node.state === GQL.SavedSearchWebhookDeliveryState.PENDING && node.nextAttemptAt && (
+                    <>
+                        , next attempt <Timestamp date={node.nextAttemptAt} />
+                    </>
+                )}
+            </small>
+        </div>
+        {node.error && <div className="text-danger mt-1">{node.error}</div>}
+        <details className="mt-1">
+            <summary>Payload</summary>
+            <pre className="mb-0">
+                <code>{node.payload}</code>
+            </pre>
+        </details>
+    </li>
+)
+
+interface SavedSearchWebhookNodeProps {
+    node: GQL.ISavedSearchWebhook
+    onDelete: (webhook: GQL.ISavedSearchWebhook) => void
+}
+
+interface SavedSearchWebhookNodeState {
+    showDeliveries: boolean
+}
+
+class SavedSearchWebhookNode extends React.PureComponent<SavedSearchWebhookNodeProps, SavedSearchWebhookNodeState> {
+    public state: SavedSearchWebhookNodeState = { showDeliveries: false }
+
+    public render(): JSX.Element | null {
+        const { node } = this.props
+        return (
+            <li className="list-group-item">
+                <div className="d-flex align-items-center justify-content-between">
+                    <div>
+                        <code>{node.url}</code>{' '}
+                        <small className="text-muted">
+                            added <Timestamp date={node.createdAt} />
+                        </small>
+                    </div>
+                    <div className="text-nowrap">
+                        <button type="button" className="btn btn-sm btn-secondary" onClick={this.toggleDeliveries}>
+                            {this.state.showDeliveries ? 'Hide' : 'Show'} deliveries ({node.deliveries.totalCount})
+                        </button>{' '}
+                        <button type="button" className="btn btn-sm btn-danger" onClick={this.delete}>
+                            Delete
+                        </button>
+                    </div>
+                </div>
+                {this.state.showDeliveries &&
+                    (node.deliveries.nodes.length > 0 ? (
+                        <ul className="list-group mt-2">
+                            {node.deliveries.nodes.map(delivery => (
+                                <SavedSearchWebhookDeliveryNode key={delivery.id} node={delivery} />
+                            ))}
+                        </ul>
+                    ) : (
+                        <p className="text-muted mt-2 mb-0">No deliveries yet.</p>
+                    ))}
+                {this.state.showDeliveries && node.deliveries.pageInfo.hasNextPage && (
+                    <small className="text-muted">
+                        Showing the {node.deliveries.nodes.length} most recent of {node.deliveries.totalCount}{' '}
+                        deliveries.
+                    </small>
+                )}
+            </li>
+        )
+    }
+
+    private toggleDeliveries = () => this.setState(state => ({ showDeliveries: !state.showDeliveries }))
+
+    private delete = () => {
+        if (!window.confirm(`Delete the webhook ${this.props.node.url}?`)) {
+            return
+        }
+        this.props.onDelete(this.props.node)
+    }
+}
+
+interface Props {
+    savedSearch: GQL.ID
+}
+
+interface State {
+    webhooksOrError: typeof LOADING | GQL.ISavedSearchWebhook[] | ErrorLike
+    url: string
+    secret: string
+    updateOrError: null | typeof LOADING | ErrorLike
+}
+
+/**
+ * Lists and manages the webhooks that are notified of a saved search's new results.
+ */
+export class SavedSearchWebhooks extends React.PureComponent<Props, State> {
+    public state: State = { webhooksOrError: LOADING, url: '', secret: '', updateOrError: null }
+
+    private refreshes = new Subject<void>()
+    private adds = new Subject<{ url: string; secret: string }>()
+    private deletes = new Subject<GQL.ISavedSearchWebhook>()
+    private subscriptions = new Subscription()
+
+    public componentDidMount(): void {
+        this.subscriptions.add(
+            this.refreshes
+                .pipe(
+                    startWith(void 0),
+                    switchMap(() =>
+                        fetchSavedSearchWebhooks(this.props.savedSearch).pipe(catchError(err => [asError(err)]))
+                    ),
+                    map(result => ({ webhooksOrError: result }))
+                )
+                .subscribe(stateUpdate => this.setState(stateUpdate))
+        )
+
+        this.subscriptions.add(
+            this.adds
+                .pipe(
+                    switchMap(({ url, secret }) =>
+                        concat(
+                            [{ updateOrError: LOADING }],
+                            addSavedSearchWebhook(this.props.savedSearch, url, secret).pipe(
+                                tap(() => this.refreshes.next()),
+                                mapTo({ updateOrError: null, url: '', secret: '' }),
+                                catchError(err => [{ updateOrError: asError(err) }])
+                            )
+                        )
+                    )
+                )
+                .subscribe(stateUpdate => this.setState(stateUpdate as State))
+        )
+
+        this.subscriptions.add(
+            this.deletes
+                .pipe(
+                    switchMap(webhook =>
+                        concat(
+                            [{ updateOrError: LOADING }],
+                            deleteSavedSearchWebhook(webhook.id).pipe(
+                                tap(() => this.refreshes.next()),
+                                mapTo({ updateOrError: null }),
+                                catchError(err => [{ updateOrError: asError(err) }])
+                            )
+                        )
+                    )
+                )
+                .subscribe(stateUpdate => this.setState(stateUpdate as State))
+        )
+    }
+
+    public componentWillUnmount(): void {
+        this.subscriptions.unsubscribe()
+    }
+
+    public render(): JSX.Element | null {
+        return (
+            <div className="saved-search-webhooks mt-4">
+                <h3>Webhooks</h3>
+                <p>
+                    When this saved search has new results, a JSON payload describing them is sent in a{' '}
+                    <code>POST</code> request to each webhook URL. Requests are signed with the webhook's secret in
+                    the <code>X-Sourcegraph-Signature</code> header. Failed deliveries are retried with backoff.
+                </p>
+                {this.state.webhooksOrError === LOADING ? (
+                    <LoadingSpinner className="icon-inline" />
+                ) : isErrorLike(this.state.webhooksOrError) ? (
+                    <div className="alert alert-danger">{this.state.webhooksOrError.message}</div>
+                ) : this.state.webhooksOrError.length > 0 ? (
+                    <ul className="list-group mb-3">
+                        {this.state.webhooksOrError.map(webhook => (
+                            <SavedSearchWebhookNode key={webhook.id} node={webhook} onDelete={this.onDelete} />
+                        ))}
+                    </ul>
+                ) : (
+                    <p className="text-muted">No webhooks have been added.</p>
+                )}
+                <form className="form-inline" onSubmit={this.onSubmit}>
+                    <input
+                        type="url"
+                        className="form-control mr-2 mb-2"
+                        placeholder="https://example.com/webhook"
+                        required={true}
+                        value={this.state.url}
+                        onChange={this.onURLChange}
+                    />
+                    <input
+                        type="password"
+                        className="form-control mr-2 mb-2"
+                        placeholder="Secret"
+                        required={true}
+                        autoComplete="off"
+                        value={this.state.secret}
+                        onChange={this.onSecretChange}
+                    />
+                    <button
+                        type="submit"
+                        className="btn btn-primary mb-2"
+                        disabled={this.state.updateOrError === LOADING}
+                    >
+                        Add webhook
+                    </button>
+                </form>
+                {isErrorLike(this.state.updateOrError) && (
+                    <div className="alert alert-danger">{this.state.updateOrError.message}</div>
+                )}
+            </div>
+        )
+    }
+
+    private onURLChange = (e: React.ChangeEvent<HTMLInputElement>) => this.setState({ url: e.currentTarget.value })
+
+    private onSecretChange = (e: React.ChangeEvent<HTMLInputElement>) =>
+        this.setState({ secret: e.currentTarget.value })
+
+    private onSubmit = (e: React.FormEvent<HTMLFormElement>) => {
+        e.preventDefault()
+        this.adds.next({ url: this.state.url, secret: this.state.secret })
+    }
+
+    private onDelete = (webhook: GQL.ISavedSearchWebhook) => this.deletes.next(webhook)
+}
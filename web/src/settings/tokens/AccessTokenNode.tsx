@@ -19,6 +19,7 @@ export const accessTokenFragment = gql`
         note
         createdAt
         lastUsedAt
+        expiresAt
         subject {
             username
         }
@@ -144,6 +145,11 @@ export class AccessTokenNode extends React.PureComponent<AccessTokenNodeProps, A
                                     </Link>
                                 </>
                             )}
+                            {this.props.node.expiresAt && (
+                                <>
+                                    , expires <Timestamp date={this.props.node.expiresAt} />
+                                </>
+                            )}
                         </small>
                     </div>
                     <div>
@@ -6,17 +6,25 @@ import SettingsIcon from 'mdi-react/SettingsIcon'
 import * as React from 'react'
 import { RouteComponentProps } from 'react-router'
 import { Link } from 'react-router-dom'
-import { merge, of, Subject, Subscription } from 'rxjs'
+import { merge, Observable, of, Subject, Subscription } from 'rxjs'
 import { catchError, distinctUntilChanged, map, switchMap } from 'rxjs/operators'
 import * as GQL from '../../../shared/src/graphql/schema'
 import { asError } from '../../../shared/src/util/errors'
 import { CopyableText } from '../components/CopyableText'
-import { FilteredConnection } from '../components/FilteredConnection'
+import { FilteredConnection, FilteredConnectionFilter } from '../components/FilteredConnection'
 import { PageTitle } from '../components/PageTitle'
 import { eventLogger } from '../tracking/eventLogger'
 import { userURL } from '../user'
 import { setUserEmailVerified } from '../user/settings/backend'
-import { deleteUser, fetchAllUsers, randomizeUserPassword, setUserIsSiteAdmin } from './backend'
+import {
+    activateUser,
+    approveUser,
+    deleteUser,
+    fetchAllUsers,
+    randomizeUserPassword,
+    setUserIsSiteAdmin,
+    suspendUser,
+} from './backend'
 
 interface UserNodeProps {
     /**
@@ -102,6 +110,12 @@ class UserNode extends React.PureComponent<UserNodeProps, UserNodeState> {
                         <Link to={`/users/${this.props.node.username}`}>
                             <strong>{this.props.node.username}</strong>
                         </Link>
+                        {this.props.node.state === GQL.UserState.SUSPENDED && (
+                            <span className="badge badge-danger ml-2">Suspended</span>
+                        )}
+                        {this.props.node.state === GQL.UserState.PENDING_APPROVAL && (
+                            <span className="badge badge-warning ml-2">Pending approval</span>
+                        )}
                         <br />
                         <span className="text-muted">{this.props.node.displayName}</span>
                     </div>
@@ -137,6 +151,35 @@ class UserNode extends React.PureComponent<UserNodeProps, UserNodeState> {
                                     Promote to site admin
                                 </button>
                             ))}{' '}
+                        {this.props.node.state === GQL.UserState.PENDING_APPROVAL && (
+                            <button
+                                className="btn btn-sm btn-primary"
+                                onClick={this.approveUser}
+                                disabled={this.state.loading}
+                            >
+                                Approve
+                            </button>
+                        )}
+                        {this.props.node.state === GQL.UserState.SUSPENDED && (
+                            <button
+                                className="btn btn-sm btn-secondary"
+                                onClick={this.activateUser}
+                                disabled={this.state.loading}
+                            >
+                                Reactivate
+                            </button>
+                        )}
+                        {this.props.node.state === GQL.UserState.ACTIVE &&
+                            this.props.node.id !== this.props.authenticatedUser.id && (
+                                <button
+                                    className="btn btn-sm btn-secondary"
+                                    onClick={this.suspendUser}
+                                    disabled={this.state.loading}
+                                    data-tooltip="Revoke access but keep the user's data"
+                                >
+                                    Suspend
+                                </button>
+                            )}{' '}
                         {this.props.node.id !== this.props.authenticatedUser.id && (
                             <button
                                 className="btn btn-sm btn-danger"
@@ -207,6 +250,37 @@ class UserNode extends React.PureComponent<UserNodeProps, UserNodeState> {
             )
     }
 
+    private suspendUser = () =>
+        this.setUserState(
+            suspendUser,
+            `Suspend the user ${this.props.node.username}? The user will be signed out and can't sign in or use access tokens until reactivated. Their data is kept.`
+        )
+    private activateUser = () => this.setUserState(activateUser, `Reactivate the user ${this.props.node.username}?`)
+    private approveUser = () => this.setUserState(approveUser, `Approve the user ${this.props.node.username}?`)
+
+    private setUserState(mutate: (user: GQL.ID) => Observable<void>, message: string): void {
+        if (!window.confirm(message)) {
+            return
+        }
+
+        this.setState({
+            errorDescription: undefined,
+            loading: true,
+        })
+
+        mutate(this.props.node.id)
+            .toPromise()
+            .then(
+                () => {
+                    this.setState({ loading: false })
+                    if (this.props.onDidUpdate) {
+                        this.props.onDidUpdate()
+                    }
+                },
+                err => this.setState({ loading: false, errorDescription: err.message })
+            )
+    }
+
     private randomizePassword = () => {
         if (
             !window.confirm(
@@ -285,6 +359,33 @@ class FilteredUserConnection extends FilteredConnection<
  * A page displaying the users on this site.
  */
 export class SiteAdminAllUsersPage extends React.Component<Props, State> {
+    private static FILTERS: FilteredConnectionFilter[] = [
+        {
+            label: 'All',
+            id: 'all',
+            tooltip: 'Show all users',
+            args: {},
+        },
+        {
+            label: 'Active',
+            id: 'active',
+            tooltip: 'Show only active users',
+            args: { state: GQL.UserState.ACTIVE },
+        },
+        {
+            label: 'Suspended',
+            id: 'suspended',
+            tooltip: 'Show only suspended users',
+            args: { state: GQL.UserState.SUSPENDED },
+        },
+        {
+            label: 'Pending approval',
+            id: 'pending-approval',
+            tooltip: 'Show only users who are pending approval',
+            args: { state: GQL.UserState.PENDING_APPROVAL },
+        },
+    ]
+
     public state: State = {}
 
     private userUpdates = new Subject<void>()
@@ -320,6 +421,7 @@ export class SiteAdminAllUsersPage extends React.Component<Props, State> {
                     noun="user"
                     pluralNoun="users"
                     queryConnection={fetchAllUsers}
+                    filters={SiteAdminAllUsersPage.FILTERS}
                     nodeComponent={UserNode}
                     nodeComponentProps={nodeProps}
                     updates={this.userUpdates}
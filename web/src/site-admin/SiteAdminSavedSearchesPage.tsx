@@ -0,0 +1,112 @@
+import * as React from 'react'
+import { RouteComponentProps } from 'react-router'
+import { Link } from 'react-router-dom'
+import * as GQL from '../../../shared/src/graphql/schema'
+import { pluralize } from '../../../shared/src/util/strings'
+import { FilteredConnection } from '../components/FilteredConnection'
+import { PageTitle } from '../components/PageTitle'
+import { Timestamp } from '../components/time/Timestamp'
+import { eventLogger } from '../tracking/eventLogger'
+import { fetchAllSavedSearches } from './backend'
+
+const SavedSearchNode: React.FunctionComponent<{ node: GQL.ISavedSearch }> = ({ node }) => {
+    const owner = node.ownerUser
+        ? { name: node.ownerUser.username, url: node.ownerUser.url }
+        : node.ownerOrg
+            ? { name: node.ownerOrg.name, url: node.ownerOrg.url }
+            : null
+    const lastRun = node.runs.nodes.length > 0 ? node.runs.nodes[0] : null
+    return (
+        <li className="list-group-item py-2">
+            <div className="d-flex align-items-start justify-content-between">
+                <div>
+                    {owner ? (
+                        <Link to={`${owner.url}/searches/${node.id}`}>
+                            <strong>{node.description}</strong>
+                        </Link>
+                    ) : (
+                        <strong>{node.description}</strong>
+                    )}
+                    {owner && (
+                        <>
+                            {' '}
+                            <span className="text-muted">
+                                owned by <Link to={owner.url}>{owner.name}</Link>
+                            </span>
+                        </>
+                    )}
+                    <br />
+                    <code>{node.query}</code>
+                    <div>
+                        {node.notify && <span className="badge badge-secondary mr-1">email</span>}
+                        {node.notifySlack && <span className="badge badge-secondary mr-1">Slack</span>}
+                        {node.webhooks.length > 0 && (
+                            <span className="badge badge-secondary mr-1">
+                                {node.webhooks.length} {pluralize('webhook', node.webhooks.length)}
+                            </span>
+                        )}
+                        {node.subscriptions.length > 0 && (
+                            <span className="badge badge-secondary mr-1">
+                                {node.subscriptions.length} {pluralize('subscription', node.subscriptions.length)}
+                            </span>
+                        )}
+                    </div>
+                </div>
+                <small className="text-muted text-right">
+                    {lastRun ? (
+                        <>
+                            Last run <Timestamp date={lastRun.startedAt} />
+                            <br />
+                            {lastRun.error ? (
+                                <span className="text-danger">{lastRun.error}</span>
+                            ) : (
+                                <>
+                                    {lastRun.resultCount} {pluralize('result', lastRun.resultCount)} (
+                                    {lastRun.newResultCount} new)
+                                </>
+                            )}
+                        </>
+                    ) : (
+                        'Never run'
+                    )}
+                </small>
+            </div>
+        </li>
+    )
+}
+
+class FilteredSavedSearchConnection extends FilteredConnection<GQL.ISavedSearch> {}
+
+interface Props extends RouteComponentProps<any> {}
+
+/**
+ * A page displaying all saved searches on this site, with their notification settings and most recent run.
+ */
+export class SiteAdminSavedSearchesPage extends React.Component<Props> {
+    public componentDidMount(): void {
+        eventLogger.logViewEvent('SiteAdminSavedSearches')
+    }
+
+    public render(): JSX.Element | null {
+        return (
+            <div className="site-admin-saved-searches-page">
+                <PageTitle title="Saved searches - Admin" />
+                <h2>Saved searches</h2>
+                <p>
+                    All saved searches on this site. Saved searches that notify their owners, subscribers, or webhooks
+                    of new results are run periodically to monitor code. See{' '}
+                    <Link to="/help/user/search/saved_searches">Sourcegraph documentation</Link> for more information.
+                </p>
+                <FilteredSavedSearchConnection
+                    className="list-group list-group-flush mt-3"
+                    noun="saved search"
+                    pluralNoun="saved searches"
+                    queryConnection={fetchAllSavedSearches}
+                    nodeComponent={SavedSearchNode}
+                    history={this.props.history}
+                    location={this.props.location}
+                />
+            </div>
+        )
+    }
+}
@@ -50,7 +50,7 @@ export class SiteInitPage extends React.Component<Props> {
         )
     }
 
-    private doSiteInit = (args: SignUpArgs): Promise<void> =>
+    private doSiteInit = (args: SignUpArgs): Promise<'verify' | 'enroll' | void> =>
         fetch('/-/site-init', {
             credentials: 'same-origin',
             method: 'POST',
@@ -68,7 +68,13 @@ export class SiteInitPage extends React.Component<Props> {
                 submitTrialRequest(args.email)
             }
 
-            window.location.replace('/site-admin')
-            return Promise.resolve()
+            return resp.text().then(text => {
+                // The response body is empty unless multi-factor authentication is needed.
+                const { mfa } = text ? JSON.parse(text) : { mfa: undefined }
+                if (!mfa) {
+                    window.location.replace('/site-admin')
+                }
+                return mfa
+            })
         })
 }
@@ -8,15 +8,20 @@ import { mutateGraphQL, queryGraphQL } from '../backend/graphql'
 /**
  * Fetches all users.
  */
-export function fetchAllUsers(args: { first?: number; query?: string }): Observable<GQL.IUserConnection> {
+export function fetchAllUsers(args: {
+    first?: number
+    query?: string
+    state?: GQL.UserState
+}): Observable<GQL.IUserConnection> {
     return queryGraphQL(
         gql`
-            query Users($first: Int, $query: String) {
-                users(first: $first, query: $query) {
+            query Users($first: Int, $query: String, $state: UserState) {
+                users(first: $first, query: $query, state: $state) {
                     nodes {
                         id
                         username
                         displayName
+                        state
                         emails {
                             email
                             verified
@@ -78,6 +83,60 @@ export function fetchAllOrganizations(args: { first?: number; query?: string }):
     )
 }
 
+/**
+ * Fetches all saved searches (including their notification settings and most recent run).
+ */
+export function fetchAllSavedSearches(args: {
+    first?: number
+    query?: string
+}): Observable<GQL.ISavedSearchConnection> {
+    return queryGraphQL(
+        gql`
+            query AllSavedSearches($first: Int, $query: String) {
+                allSavedSearches(first: $first, query: $query) {
+                    nodes {
+                        id
+                        description
+                        query
+                        notify
+                        notifySlack
+                        ownerUser {
+                            username
+                            url
+                        }
+                        ownerOrg {
+                            name
+                            url
+                        }
+                        webhooks {
+                            id
+                        }
+                        subscriptions {
+                            id
+                        }
+                        runs(first: 1) {
+                            nodes {
+                                startedAt
+                                resultCount
+                                newResultCount
+                                error
+                            }
+                        }
+                    }
+                    totalCount
+                    pageInfo {
+                        hasNextPage
+                    }
+                }
+            }
+        `,
+        args
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(data => data.allSavedSearches)
+    )
+}
+
 interface RepositoryArgs {
     first?: number
     query?: string
@@ -362,6 +421,54 @@ export function setUserIsSiteAdmin(userID: GQL.ID, siteAdmin: boolean): Observab
     )
 }
 
+export function suspendUser(user: GQL.ID): Observable<void> {
+    return mutateGraphQL(
+        gql`
+            mutation SuspendUser($user: ID!) {
+                suspendUser(user: $user) {
+                    alwaysNil
+                }
+            }
+        `,
+        { user }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(() => undefined)
+    )
+}
+
+export function activateUser(user: GQL.ID): Observable<void> {
+    return mutateGraphQL(
+        gql`
+            mutation ActivateUser($user: ID!) {
+                activateUser(user: $user) {
+                    alwaysNil
+                }
+            }
+        `,
+        { user }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(() => undefined)
+    )
+}
+
+export function approveUser(user: GQL.ID): Observable<void> {
+    return mutateGraphQL(
+        gql`
+            mutation ApproveUser($user: ID!) {
+                approveUser(user: $user) {
+                    alwaysNil
+                }
+            }
+        `,
+        { user }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(() => undefined)
+    )
+}
+
 export function randomizeUserPassword(user: GQL.ID): Observable<GQL.IRandomizeUserPasswordResult> {
     return mutateGraphQL(
         gql`
@@ -66,6 +66,11 @@ export const siteAdminAreaRoutes: ReadonlyArray<SiteAdminAreaRoute> = [
         render: lazyComponent(() => import('./SiteAdminCreateUserPage'), 'SiteAdminCreateUserPage'),
         exact: true,
     },
+    {
+        path: '/saved-searches',
+        exact: true,
+        render: lazyComponent(() => import('./SiteAdminSavedSearchesPage'), 'SiteAdminSavedSearchesPage'),
+    },
     {
         path: '/tokens',
         exact: true,
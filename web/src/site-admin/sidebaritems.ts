@@ -38,6 +38,10 @@ export const secondaryGroup: SiteAdminSideBarGroup = {
             label: 'Organizations',
             to: '/site-admin/organizations',
         },
+        {
+            label: 'Saved searches',
+            to: '/site-admin/saved-searches',
+        },
         {
             label: 'Global settings',
             to: '/site-admin/global-settings',
@@ -9,7 +9,7 @@ import { catchError, concatMap, map, tap } from 'rxjs/operators'
 import { gql } from '../../../../../shared/src/graphql/graphql'
 import * as GQL from '../../../../../shared/src/graphql/schema'
 import { asError, createAggregateError, ErrorLike, isErrorLike } from '../../../../../shared/src/util/errors'
-import { AccessTokenScopes } from '../../../auth/accessToken'
+import { AccessTokenScopes, USER_ACCESS_TOKEN_SCOPES } from '../../../auth/accessToken'
 import { mutateGraphQL } from '../../../backend/graphql'
 import { Form } from '../../../components/Form'
 import { PageTitle } from '../../../components/PageTitle'
@@ -17,17 +17,22 @@ import { SiteAdminAlert } from '../../../site-admin/SiteAdminAlert'
 import { eventLogger } from '../../../tracking/eventLogger'
 import { UserAreaRouteContext } from '../../area/UserArea'
 
-function createAccessToken(user: GQL.ID, scopes: string[], note: string): Observable<GQL.ICreateAccessTokenResult> {
+function createAccessToken(
+    user: GQL.ID,
+    scopes: string[],
+    note: string,
+    expiresAt: string | null
+): Observable<GQL.ICreateAccessTokenResult> {
     return mutateGraphQL(
         gql`
-            mutation CreateAccessToken($user: ID!, $scopes: [String!]!, $note: String!) {
-                createAccessToken(user: $user, scopes: $scopes, note: $note) {
+            mutation CreateAccessToken($user: ID!, $scopes: [String!]!, $note: String!, $expiresAt: String) {
+                createAccessToken(user: $user, scopes: $scopes, note: $note, expiresAt: $expiresAt) {
                     id
                     token
                 }
             }
         `,
-        { user, scopes, note }
+        { user, scopes, note, expiresAt }
     ).pipe(
         map(({ data, errors }) => {
             if (!data || !data.createAccessToken || (errors && errors.length > 0)) {
@@ -52,6 +57,9 @@ interface State {
     /** The selected scopes checkboxes. */
     scopes: string[]
 
+    /** The contents of the expiration date input field (YYYY-MM-DD), or empty if the token does not expire. */
+    expiresAt: string
+
     creationOrError?: 'loading' | GQL.ICreateAccessTokenResult | ErrorLike
 }
 
@@ -62,6 +70,7 @@ export class UserSettingsCreateAccessTokenPage extends React.PureComponent<Props
     public state: State = {
         note: '',
         scopes: [AccessTokenScopes.UserAll],
+        expiresAt: '',
     }
 
     private submits = new Subject<React.FormEvent<HTMLFormElement>>()
@@ -77,7 +86,12 @@ export class UserSettingsCreateAccessTokenPage extends React.PureComponent<Props
                     concatMap(() =>
                         concat(
                             [{ creationOrError: 'loading' }],
-                            createAccessToken(this.props.user.id, this.state.scopes, this.state.note).pipe(
+                            createAccessToken(
+                                this.props.user.id,
+                                this.state.scopes,
+                                this.state.note,
+                                this.state.expiresAt ? new Date(this.state.expiresAt).toISOString() : null
+                            ).pipe(
                                 tap(result => {
                                     // Go back to access tokens list page and display the token secret value.
                                     this.props.history.push(`${this.props.match.url.replace(/\/new$/, '')}`)
@@ -135,27 +149,28 @@ export class UserSettingsCreateAccessTokenPage extends React.PureComponent<Props
                         </label>
                         <div>
                             <small className="form-help text-muted">
-                                Tokens with limited user scopes are not yet supported.
+                                A token without the {AccessTokenScopes.UserAll} scope may only perform the API
+                                operations that its scopes permit.
                             </small>
                         </div>
-                        <div className="form-check">
-                            <input
-                                className="form-check-input"
-                                type="checkbox"
-                                id="user-settings-create-access-token-page__scope-user:all"
-                                checked={true}
-                                value={AccessTokenScopes.UserAll}
-                                onChange={this.onScopesChange}
-                                disabled={true}
-                            />
-                            <label
-                                className="form-check-label"
-                                htmlFor="user-settings-create-access-token-page__scope-user:all"
-                            >
-                                <strong>{AccessTokenScopes.UserAll}</strong> — Full control of all resources accessible
-                                to the user account
-                            </label>
-                        </div>
+                        {USER_ACCESS_TOKEN_SCOPES.map(({ scope, description }) => (
+                            <div className="form-check" key={scope}>
+                                <input
+                                    className="form-check-input"
+                                    type="checkbox"
+                                    id={`user-settings-create-access-token-page__scope-${scope}`}
+                                    checked={this.state.scopes.includes(scope)}
+                                    value={scope}
+                                    onChange={this.onScopesChange}
+                                />
+                                <label
+                                    className="form-check-label"
+                                    htmlFor={`user-settings-create-access-token-page__scope-${scope}`}
+                                >
+                                    <strong>{scope}</strong> — {description}
+                                </label>
+                            </div>
+                        ))}
                         {this.props.user.siteAdmin && (
                             <div className="form-check">
                                 <input
@@ -175,6 +190,36 @@ export class UserSettingsCreateAccessTokenPage extends React.PureComponent<Props
                                 </label>
                             </div>
                         )}
+                        {this.props.user.siteAdmin && (
+                            <div className="form-check">
+                                <input
+                                    className="form-check-input"
+                                    type="checkbox"
+                                    id="user-settings-create-access-token-page__scope-site-admin:scim"
+                                    checked={this.state.scopes.includes(AccessTokenScopes.SiteAdminSCIM)}
+                                    value={AccessTokenScopes.SiteAdminSCIM}
+                                    onChange={this.onScopesChange}
+                                />
+                                <label
+                                    className="form-check-label"
+                                    htmlFor="user-settings-create-access-token-page__scope-site-admin:scim"
+                                >
+                                    <strong>{AccessTokenScopes.SiteAdminSCIM}</strong> — Ability to provision users and
+                                    organizations with the SCIM API
+                                </label>
+                            </div>
+                        )}
+                    </div>
+                    <div className="form-group">
+                        <label htmlFor="user-settings-create-access-token-page__expires-at">Expiration date</label>
+                        <input
+                            type="date"
+                            className="form-control"
+                            id="user-settings-create-access-token-page__expires-at"
+                            onChange={this.onExpiresAtChange}
+                            value={this.state.expiresAt}
+                        />
+                        <small className="form-help text-muted">Leave empty for a token that does not expire.</small>
                     </div>
                     <button
                         type="submit"
@@ -204,6 +249,9 @@ export class UserSettingsCreateAccessTokenPage extends React.PureComponent<Props
     private onNoteChange: React.ChangeEventHandler<HTMLInputElement> = e =>
         this.setState({ note: e.currentTarget.value })
 
+    private onExpiresAtChange: React.ChangeEventHandler<HTMLInputElement> = e =>
+        this.setState({ expiresAt: e.currentTarget.value })
+
     private onScopesChange: React.ChangeEventHandler<HTMLInputElement> = e => {
         const checked = e.currentTarget.checked
         const value = e.currentTarget.value
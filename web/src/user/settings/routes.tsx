@@ -43,6 +43,11 @@ export const userSettingsAreaRoutes: ReadonlyArray<UserSettingsAreaRoute> = [
         exact: true,
         render: lazyComponent(() => import('./emails/UserSettingsEmailsPage'), 'UserSettingsEmailsPage'),
     },
+    {
+        path: '/sessions',
+        exact: true,
+        render: lazyComponent(() => import('./sessions/UserSettingsSessionsPage'), 'UserSettingsSessionsPage'),
+    },
     {
         path: '/tokens',
         exact: true,
@@ -0,0 +1,246 @@
+import * as React from 'react'
+import { RouteComponentProps } from 'react-router'
+import { Observable, Subject } from 'rxjs'
+import { map } from 'rxjs/operators'
+import { gql } from '../../../../../shared/src/graphql/graphql'
+import * as GQL from '../../../../../shared/src/graphql/schema'
+import { createAggregateError } from '../../../../../shared/src/util/errors'
+import { mutateGraphQL, queryGraphQL } from '../../../backend/graphql'
+import { FilteredConnection } from '../../../components/FilteredConnection'
+import { PageTitle } from '../../../components/PageTitle'
+import { Timestamp } from '../../../components/time/Timestamp'
+import { eventLogger } from '../../../tracking/eventLogger'
+
+function revokeUserSession(session: GQL.ID): Observable<void> {
+    return mutateGraphQL(
+        gql`
+            mutation RevokeUserSession($session: ID!) {
+                revokeUserSession(session: $session) {
+                    alwaysNil
+                }
+            }
+        `,
+        { session }
+    ).pipe(
+        map(({ data, errors }) => {
+            if (!data || !data.revokeUserSession || (errors && errors.length > 0)) {
+                throw createAggregateError(errors)
+            }
+        })
+    )
+}
+
+function revokeAllUserSessions(user: GQL.ID): Observable<void> {
+    return mutateGraphQL(
+        gql`
+            mutation RevokeAllUserSessions($user: ID!) {
+                revokeAllUserSessions(user: $user) {
+                    alwaysNil
+                }
+            }
+        `,
+        { user }
+    ).pipe(
+        map(({ data, errors }) => {
+            if (!data || !data.revokeAllUserSessions || (errors && errors.length > 0)) {
+                throw createAggregateError(errors)
+            }
+        })
+    )
+}
+
+interface UserSessionNodeProps {
+    node: GQL.IUserSession
+    onDidUpdate: () => void
+}
+
+interface UserSessionNodeState {
+    loading: boolean
+    errorDescription?: string
+}
+
+class UserSessionNode extends React.PureComponent<UserSessionNodeProps, UserSessionNodeState> {
+    public state: UserSessionNodeState = {
+        loading: false,
+    }
+
+    public render(): JSX.Element | null {
+        return (
+            <li className="list-group-item py-2">
+                <div className="d-flex align-items-center justify-content-between">
+                    <div>
+                        <strong>{this.props.node.remoteAddr || 'Unknown IP address'}</strong>
+                        {this.props.node.isCurrent && <span className="badge badge-primary ml-1">Current session</span>}
+                        {this.props.node.authProvider && (
+                            <span className="badge badge-secondary ml-1">{this.props.node.authProvider}</span>
+                        )}
+                        <br />
+                        <small className="text-muted">
+                            {this.props.node.userAgent && (
+                                <>
+                                    {this.props.node.userAgent}
+                                    <br />
+                                </>
+                            )}
+                            Signed in <Timestamp date={this.props.node.createdAt} />, last active{' '}
+                            <Timestamp date={this.props.node.lastActiveAt} />
+                        </small>
+                    </div>
+                    <button className="btn btn-sm btn-danger" onClick={this.revoke} disabled={this.state.loading}>
+                        Revoke
+                    </button>
+                </div>
+                {this.state.errorDescription && (
+                    <div className="alert alert-danger mt-2">{this.state.errorDescription}</div>
+                )}
+            </li>
+        )
+    }
+
+    private revoke = () => {
+        if (
+            !window.confirm(
+                this.props.node.isCurrent
+                    ? 'Revoke the current session? You will be signed out.'
+                    : 'Revoke this session? The browser or device will be signed out.'
+            )
+        ) {
+            return
+        }
+
+        this.setState({ errorDescription: undefined, loading: true })
+        revokeUserSession(this.props.node.id).subscribe(
+            () => {
+                eventLogger.log('UserSessionRevoked')
+                if (this.props.node.isCurrent) {
+                    window.location.reload()
+                    return
+                }
+                this.setState({ loading: false })
+                this.props.onDidUpdate()
+            },
+            error => this.setState({ loading: false, errorDescription: error.message })
+        )
+    }
+}
+
+interface Props extends RouteComponentProps<{}> {
+    user: GQL.IUser
+}
+
+interface State {
+    loading: boolean
+    errorDescription?: string
+}
+
+/** We fake a XyzConnection type because our GraphQL API doesn't have one (or need one) for user sessions. */
+interface UserSessionConnection {
+    nodes: GQL.IUserSession[]
+    totalCount: number
+}
+
+/**
+ * Displays the sessions (browsers and devices where the user is signed in) of a user, and allows revoking them.
+ */
+export class UserSettingsSessionsPage extends React.Component<Props, State> {
+    public state: State = { loading: false }
+
+    private userSessionUpdates = new Subject<void>()
+
+    public componentDidMount(): void {
+        eventLogger.logViewEvent('UserSettingsSessions')
+    }
+
+    public render(): JSX.Element | null {
+        const nodeProps: Pick<UserSessionNodeProps, 'onDidUpdate'> = {
+            onDidUpdate: this.onDidUpdateUserSession,
+        }
+
+        return (
+            <div className="user-settings-sessions-page">
+                <PageTitle title="Sessions" />
+                <div className="d-flex justify-content-between align-items-center">
+                    <h2>Sessions</h2>
+                    <button
+                        className="btn btn-danger"
+                        onClick={this.revokeAll}
+                        disabled={this.state.loading}
+                        data-tooltip="Sign out everywhere"
+                    >
+                        Revoke all sessions
+                    </button>
+                </div>
+                <p>These are the browsers and devices where you are signed in.</p>
+                {this.state.errorDescription && (
+                    <div className="alert alert-danger mt-2">{this.state.errorDescription}</div>
+                )}
+                <FilteredConnection<GQL.IUserSession, Pick<UserSessionNodeProps, 'onDidUpdate'>>
+                    className="list-group list-group-flush mt-3"
+                    noun="session"
+                    pluralNoun="sessions"
+                    queryConnection={this.queryUserSessions}
+                    nodeComponent={UserSessionNode}
+                    nodeComponentProps={nodeProps}
+                    updates={this.userSessionUpdates}
+                    hideSearch={true}
+                    noSummaryIfAllNodesVisible={true}
+                    history={this.props.history}
+                    location={this.props.location}
+                />
+            </div>
+        )
+    }
+
+    private queryUserSessions = (args: {}): Observable<UserSessionConnection> =>
+        queryGraphQL(
+            gql`
+                query UserSessions($user: ID!) {
+                    node(id: $user) {
+                        ... on User {
+                            sessions {
+                                id
+                                createdAt
+                                lastActiveAt
+                                remoteAddr
+                                userAgent
+                                authProvider
+                                isCurrent
+                            }
+                        }
+                    }
+                }
+            `,
+            { user: this.props.user.id }
+        ).pipe(
+            map(({ data, errors }) => {
+                if (!data || !data.node) {
+                    throw createAggregateError(errors)
+                }
+                const user = data.node as GQL.IUser
+                if (!user.sessions) {
+                    throw createAggregateError(errors)
+                }
+                return {
+                    nodes: user.sessions,
+                    totalCount: user.sessions.length,
+                }
+            })
+        )
+
+    private revokeAll = () => {
+        if (!window.confirm('Revoke all sessions? This signs out every browser and device, including this one.')) {
+            return
+        }
+
+        this.setState({ errorDescription: undefined, loading: true })
+        revokeAllUserSessions(this.props.user.id).subscribe(
+            () => {
+                eventLogger.log('UserSessionsRevokedAll')
+                window.location.reload()
+            },
+            error => this.setState({ loading: false, errorDescription: error.message })
+        )
+    }
+
+    private onDidUpdateUserSession = () => this.userSessionUpdates.next()
+}
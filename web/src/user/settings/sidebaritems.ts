@@ -24,6 +24,11 @@ export const userSettingsSideBarItems: UserSettingsSidebarItems = {
             to: `/emails`,
             exact: true,
         },
+        {
+            label: 'Sessions',
+            to: `/sessions`,
+            exact: true,
+        },
         {
             label: 'Access tokens',
             to: `/tokens`,